@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"sync"
 
+	"github.com/sirrobot01/decypharr/internal/buffer"
 	"github.com/sirrobot01/decypharr/internal/config"
 	"github.com/sirrobot01/decypharr/internal/logger"
 	"github.com/sirrobot01/decypharr/internal/utils"
@@ -17,6 +18,7 @@ import (
 	"github.com/sirrobot01/decypharr/pkg/mount/external"
 	"github.com/sirrobot01/decypharr/pkg/mount/rclone"
 	"github.com/sirrobot01/decypharr/pkg/server"
+	"github.com/sirrobot01/decypharr/pkg/server/sftp"
 	"github.com/sirrobot01/decypharr/pkg/version"
 )
 
@@ -65,6 +67,7 @@ func Start(ctx context.Context) error {
 `, version.GetInfo(), cfg.LogLevel)
 
 		// Initialize services
+		configureBufferGovernor(cfg)
 		mountMgr := createMountManager(mgr, cfg)
 		mgr.SetMountManager(mountMgr)
 		srv := server.New(mgr)
@@ -122,6 +125,20 @@ func Start(ctx context.Context) error {
 	}
 }
 
+// configureBufferGovernor applies the shared streaming-buffer budget. Pools
+// re-read it on the next rebalance, including the usenet pool that outlives
+// restarts.
+func configureBufferGovernor(cfg *config.Config) {
+	buffer.DefaultGovernor().Configure(buffer.GovernorConfig{
+		MemoryBudget: cfg.Buffers.MemoryBytes(),
+		DiskBudget:   cfg.Buffers.DiskBytes(),
+		Priorities: map[string]int{
+			"dfs":    cfg.Buffers.DFSPriority,
+			"usenet": cfg.Buffers.UsenetPriority,
+		},
+	})
+}
+
 func createMountManager(mgr *manager.Manager, cfg *config.Config) manager.MountManager {
 	switch cfg.Mount.Type {
 	case config.MountTypeRclone:
@@ -171,6 +188,12 @@ func startServices(ctx context.Context, manager *manager.Manager, cancelSvc cont
 		return manager.Start(ctx)
 	})
 
+	if config.Get().SFTP.Enabled {
+		safeGo(func() error {
+			return sftp.New(manager).Start(ctx)
+		})
+	}
+
 	go func() {
 		wg.Wait()
 		close(errChan)
@@ -8,7 +8,6 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"slices"
 	"strings"
 	"time"
 
@@ -17,116 +16,96 @@ import (
 	"github.com/sirrobot01/decypharr/internal/config"
 )
 
-// HealthStatus represents the status of various components
-type HealthStatus struct {
-	QbitAPI       bool `json:"qbit_api"`
-	WebUI         bool `json:"web_ui"`
-	WebDAVService bool `json:"webdav_service"`
-	OverallStatus bool `json:"overall_status"`
+// ComponentHealth mirrors a component of the server's /readyz report. The
+// reason is only filled in by the authenticated /debug/health.
+type ComponentHealth struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// HealthReport mirrors the server's /readyz report
+type HealthReport struct {
+	Ready      bool              `json:"ready"`
+	Components []ComponentHealth `json:"components"`
 }
 
 func main() {
 	var (
 		configPath string
 		debug      bool
+		ready      bool
 	)
 	flag.StringVar(&configPath, "config", "/data", "path to the data folder")
 	flag.BoolVar(&debug, "debug", false, "enable debug mode for detailed output")
+	flag.BoolVar(&ready, "ready", false, "check full readiness (/readyz), which fails while every debrid or the NNTP pool is unreachable, instead of only that the server answers (/healthz)")
 	flag.Parse()
 	config.SetConfigPath(configPath)
 	cfg := config.Get()
 	// GetReader port from environment variable or use default
 	port := cmp.Or(os.Getenv("QBIT_PORT"), cfg.Port)
 
-	// Initialize status
-	status := HealthStatus{
-		QbitAPI:       false,
-		WebUI:         false,
-		WebDAVService: false,
-		OverallStatus: false,
-	}
-
 	// Create a context with timeout for all HTTP requests
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
-	client := &http.Client{
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
-	}
 
 	baseUrl := cmp.Or(cfg.URLBase, "/")
-	auth := cfg.GetAuth()
-
-	status.QbitAPI = checkQbitAPI(ctx, client, baseUrl, port, auth, cfg.UseAuth)
-	status.WebUI = checkWebUI(ctx, client, baseUrl, port, auth, cfg.UseAuth)
-	status.WebDAVService = checkBaseWebdav(ctx, client, baseUrl, port, cfg)
-	// Determine overall status
-	// Consider the application healthy if core services are running
-	status.OverallStatus = status.QbitAPI && status.WebUI && status.WebDAVService
+	endpoint := "healthz"
+	if ready {
+		endpoint = "readyz"
+	}
 
-	// Optional: output health status as JSON for logging
-	if debug {
-		statusJSON, _ := json.MarshalIndent(status, "", "  ")
-		fmt.Println(string(statusJSON))
+	healthy, err := probe(ctx, localURL(port, baseUrl, endpoint), debug)
+	if err != nil {
+		if debug {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
 	}
 
 	// Exit with appropriate code
-	if status.OverallStatus {
+	if healthy {
 		os.Exit(0)
 	}
 
 	os.Exit(1)
 }
 
-func checkQbitAPI(ctx context.Context, client *http.Client, baseUrl, port string, auth *config.Auth, authMayBeRequired bool) bool {
-	url := localURL(port, baseUrl, "api/v2/app/version")
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// probe calls a health endpoint. The server answers 200 when healthy and 503
+// with the failing components otherwise.
+func probe(ctx context.Context, url string, debug bool) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return false
+		return false, err
 	}
-	addBearerAuth(req, auth)
-
-	resp, err := client.Do(req)
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return false
+		return false, err
 	}
 	defer drainAndClose(resp)
 
-	return isHealthyStatus(resp.StatusCode, authMayBeRequired, http.StatusOK)
-}
-
-func checkWebUI(ctx context.Context, client *http.Client, baseUrl, port string, auth *config.Auth, authMayBeRequired bool) bool {
-	req, err := http.NewRequestWithContext(ctx, "GET", localURL(port, baseUrl, "version"), nil)
-	if err != nil {
-		return false
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return false, fmt.Errorf("unexpected status from %s: %d", url, resp.StatusCode)
 	}
-	addBearerAuth(req, auth)
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return false
+	if debug {
+		var report HealthReport
+		if err := json.ConfigDefault.NewDecoder(resp.Body).Decode(&report); err == nil && report.Components != nil {
+			printReport(report)
+		}
 	}
-	defer drainAndClose(resp)
-
-	return isHealthyStatus(resp.StatusCode, authMayBeRequired, http.StatusOK) || isRedirect(resp.StatusCode)
+	return resp.StatusCode == http.StatusOK, nil
 }
 
-func checkBaseWebdav(ctx context.Context, client *http.Client, baseUrl, port string, cfg *config.Config) bool {
-	url := localURL(port, baseUrl, "webdav/")
-	req, err := http.NewRequestWithContext(ctx, "PROPFIND", url, nil)
-	if err != nil {
-		return false
+func printReport(report HealthReport) {
+	for _, c := range report.Components {
+		line := fmt.Sprintf("%-10s %s", c.Name, c.Status)
+		if c.Reason != "" {
+			line += ": " + c.Reason
+		}
+		fmt.Println(line)
 	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return false
-	}
-	defer drainAndClose(resp)
-
-	authMayBeRequired := cfg.UseAuth && cfg.EnableWebdavAuth
-	return isHealthyStatus(resp.StatusCode, authMayBeRequired, http.StatusOK, http.StatusCreated, http.StatusMultiStatus)
+	fmt.Printf("ready: %v\n", report.Ready)
 }
 
 func localURL(port, baseUrl, endpoint string) string {
@@ -145,30 +124,7 @@ func localURL(port, baseUrl, endpoint string) string {
 	}
 }
 
-func addBearerAuth(req *http.Request, auth *config.Auth) {
-	if auth == nil || auth.APIToken == "" {
-		return
-	}
-	req.Header.Set("Authorization", "Bearer "+auth.APIToken)
-}
-
 func drainAndClose(resp *http.Response) {
 	_, _ = io.Copy(io.Discard, resp.Body)
 	_ = resp.Body.Close()
 }
-
-func isHealthyStatus(statusCode int, authMayBeRequired bool, expectedStatusCodes ...int) bool {
-	if slices.Contains(expectedStatusCodes, statusCode) {
-		return true
-	}
-
-	if !authMayBeRequired {
-		return false
-	}
-
-	return statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden
-}
-
-func isRedirect(statusCode int) bool {
-	return statusCode >= http.StatusMultipleChoices && statusCode < http.StatusBadRequest
-}
@@ -49,6 +49,7 @@ export default defineConfig({
                     items: [
                         {label: 'Overview', link: '/guides/usenet/overview'},
                         {label: 'Sabnzbd API', link: '/guides/usenet/sabnzbd'},
+                        {label: 'NZBGet API', link: '/guides/usenet/nzbget'},
                     ],
                 },
                 {
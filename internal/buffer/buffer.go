@@ -130,6 +130,9 @@ type Config struct {
 	// read/write path with no buffer lock held; must not call back into the
 	// buffer in a way that blocks.
 	OnEvict func(off, length int64)
+
+	// Pinned starts the buffer pinned (see SetPinned).
+	Pinned bool
 }
 
 // Buffer is the public type. Methods are safe for concurrent use.
@@ -185,6 +188,11 @@ type Buffer struct {
 	// (the RAM cache is a small layer on top); used to enforce DiskLimit.
 	diskBytes atomic.Int64
 
+	// pinned buffers count their disk bytes toward the pool's pinned total
+	// instead of diskInUse, and the disk backstop never punches them. Guarded
+	// by mu.
+	pinned bool
+
 	// alloc owns block memory for this Buffer. It is per-Buffer rather than
 	// package-global: each Buffer is one file (minutes-to-hours lifetime)
 	// with its own working set, and a shared pool would either hold blocks
@@ -268,6 +276,7 @@ func newBuffer(p *Pool, cfg Config) (*Buffer, error) {
 		blocks:   make(map[int64]*block),
 		maxBytes: cfg.MemorySize,
 		ranges:   newRangeSet(),
+		pinned:   cfg.Pinned,
 	}
 	if cfg.TotalSize > 0 {
 		n := int((cfg.TotalSize + blockSize - 1) / blockSize)
@@ -657,6 +666,10 @@ func (b *Buffer) punchBehindWindow(backWindow int64) int64 {
 		return 0
 	}
 	b.mu.RLock()
+	if b.pinned {
+		b.mu.RUnlock()
+		return 0
+	}
 	present := b.ranges.presentRanges(0, ceiling)
 	b.mu.RUnlock()
 	if len(present) == 0 {
@@ -686,7 +699,11 @@ func (b *Buffer) rangesInsert(off, length int64) {
 	added := b.ranges.insert(off, length)
 	if added > 0 {
 		b.diskBytes.Add(added)
-		b.pool.addDisk(added)
+		if b.pinned {
+			b.pool.pinnedInUse.Add(added)
+		} else {
+			b.pool.addDisk(added)
+		}
 	}
 }
 
@@ -697,7 +714,11 @@ func (b *Buffer) rangesRemove(off, length int64) int64 {
 	removed := b.ranges.remove(off, length)
 	if removed > 0 {
 		b.diskBytes.Add(-removed)
-		b.pool.subDisk(removed)
+		if b.pinned {
+			b.pool.pinnedInUse.Add(-removed)
+		} else {
+			b.pool.subDisk(removed)
+		}
 	}
 	return removed
 }
@@ -825,7 +846,11 @@ func (b *Buffer) Close() error {
 	// Release this Buffer's share of the pool disk footprint and unregister it
 	// so the disk backstop stops considering it.
 	if db := b.diskBytes.Swap(0); db > 0 {
-		b.pool.subDisk(db)
+		if b.pinned {
+			b.pool.pinnedInUse.Add(-db)
+		} else {
+			b.pool.subDisk(db)
+		}
 	}
 	// Empty the range tracker so a racing discard/publish that somehow
 	// reaches it (belt and suspenders on top of the closed re-checks) finds
@@ -882,6 +907,30 @@ func (b *Buffer) SetReadHead(off int64) {
 	b.readHead.Store(off)
 }
 
+// SetPinned marks the buffer as pinned: its disk bytes move from the pool's
+// DiskLimit accounting to a separate pinned total, and the disk backstop no
+// longer punches behind its read head. Unpinning moves them back, which may
+// push the pool over its limit and wake the backstop.
+func (b *Buffer) SetPinned(pinned bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed.Load() || b.pinned == pinned {
+		return
+	}
+	b.pinned = pinned
+	db := b.diskBytes.Load()
+	if db <= 0 {
+		return
+	}
+	if pinned {
+		b.pool.subDisk(db)
+		b.pool.pinnedInUse.Add(db)
+	} else {
+		b.pool.pinnedInUse.Add(-db)
+		b.pool.addDisk(db)
+	}
+}
+
 // Stats returns the current observability counters.
 func (b *Buffer) Stats() Stats {
 	b.mu.RLock()
@@ -0,0 +1,238 @@
+package buffer
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// governorTick is how often the governor rebalances even when no stream
+// opened or closed, so budgets follow priority and config changes.
+const governorTick = 5 * time.Second
+
+// Governor shares one RAM budget and one disk budget between every Pool in
+// the process. Each registered pool is guaranteed a floor of a quarter of an
+// even split; the rest is handed out by weight, where a pool's weight is its
+// priority times its active Buffers. Idle pools shrink to the floor and the
+// busy ones get the headroom.
+//
+// A zero budget leaves that resource ungoverned: pools keep the
+// MemoryBudget/DiskLimit they were created with. This keeps separate
+// per-workload caps as the default.
+type Governor struct {
+	mu         sync.Mutex
+	memBudget  int64
+	diskBudget int64
+	priorities map[string]int
+	pools      map[*Pool]struct{}
+
+	pokeCh    chan struct{}
+	startOnce sync.Once
+}
+
+// GovernorConfig is the process-wide budget handed to Governor.Configure.
+type GovernorConfig struct {
+	// MemoryBudget caps the sum of resident block RAM across all pools.
+	// 0 = each pool keeps its own MemoryBudget.
+	MemoryBudget int64
+
+	// DiskBudget caps the sum of on-disk present bytes across all pools.
+	// Pinned bytes are not counted. 0 = each pool keeps its own DiskLimit.
+	DiskBudget int64
+
+	// Priorities weighs pools by name ("dfs", "usenet"). Missing or <= 0
+	// means 1.
+	Priorities map[string]int
+}
+
+// ConsumerStats is one pool's share of the governed budgets.
+type ConsumerStats struct {
+	Name         string `json:"name"`
+	Priority     int    `json:"priority"`
+	Streams      int    `json:"streams"`
+	MemoryInUse  int64  `json:"memory_in_use"`
+	MemoryBudget int64  `json:"memory_budget"`
+	DiskInUse    int64  `json:"disk_in_use"`
+	DiskLimit    int64  `json:"disk_limit"`
+	PinnedInUse  int64  `json:"pinned_in_use"`
+}
+
+// GovernorStats reports the governed budgets and per-consumer usage.
+type GovernorStats struct {
+	MemoryBudget int64           `json:"memory_budget"`
+	DiskBudget   int64           `json:"disk_budget"`
+	MemoryInUse  int64           `json:"memory_in_use"`
+	DiskInUse    int64           `json:"disk_in_use"`
+	Consumers    []ConsumerStats `json:"consumers"`
+}
+
+var (
+	defaultGovernorOnce sync.Once
+	defaultGovernor     *Governor
+)
+
+// DefaultGovernor returns the process-wide governor. Workloads pass it as
+// PoolConfig.Governor so their pools share its budgets.
+func DefaultGovernor() *Governor {
+	defaultGovernorOnce.Do(func() {
+		defaultGovernor = NewGovernor()
+	})
+	return defaultGovernor
+}
+
+// NewGovernor creates a governor with no budgets.
+func NewGovernor() *Governor {
+	return &Governor{
+		priorities: make(map[string]int),
+		pools:      make(map[*Pool]struct{}),
+		pokeCh:     make(chan struct{}, 1),
+	}
+}
+
+// Configure replaces the budgets and priorities and rebalances straight
+// away. Clearing a budget gives every pool its own cap back.
+func (g *Governor) Configure(cfg GovernorConfig) {
+	g.mu.Lock()
+	g.memBudget = max(cfg.MemoryBudget, 0)
+	g.diskBudget = max(cfg.DiskBudget, 0)
+	g.priorities = make(map[string]int, len(cfg.Priorities))
+	for name, p := range cfg.Priorities {
+		g.priorities[name] = p
+	}
+	g.mu.Unlock()
+	g.rebalance()
+}
+
+func (g *Governor) register(p *Pool) {
+	g.mu.Lock()
+	g.pools[p] = struct{}{}
+	g.mu.Unlock()
+	g.startOnce.Do(func() { go g.loop() })
+	g.rebalance()
+}
+
+func (g *Governor) unregister(p *Pool) {
+	g.mu.Lock()
+	delete(g.pools, p)
+	g.mu.Unlock()
+	g.poke()
+}
+
+// poke asks the loop for a rebalance. Called when a stream opens or closes.
+func (g *Governor) poke() {
+	select {
+	case g.pokeCh <- struct{}{}:
+	default:
+	}
+}
+
+func (g *Governor) loop() {
+	ticker := time.NewTicker(governorTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-g.pokeCh:
+		}
+		g.rebalance()
+	}
+}
+
+func (g *Governor) priority(name string) int {
+	if p := g.priorities[name]; p > 0 {
+		return p
+	}
+	return 1
+}
+
+// sortedPools returns the registered pools by name so shares and stats come
+// out in a stable order. Caller holds g.mu.
+func (g *Governor) sortedPools() []*Pool {
+	pools := make([]*Pool, 0, len(g.pools))
+	for p := range g.pools {
+		pools = append(pools, p)
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].name < pools[j].name })
+	return pools
+}
+
+// rebalance recomputes every pool's share and applies it
+func (g *Governor) rebalance() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	pools := g.sortedPools()
+	if len(pools) == 0 {
+		return
+	}
+	weights := make([]int64, len(pools))
+	for i, p := range pools {
+		weights[i] = int64(g.priority(p.name)) * int64(p.streams())
+	}
+	memShares := splitBudget(g.memBudget, weights)
+	diskShares := splitBudget(g.diskBudget, weights)
+	for i, p := range pools {
+		mem, disk := p.baseMemBudget, p.baseDiskLimit
+		if g.memBudget > 0 {
+			mem = memShares[i]
+		}
+		if g.diskBudget > 0 {
+			disk = diskShares[i]
+		}
+		p.memBudget.Store(mem)
+		if old := p.diskLimit.Swap(disk); disk > 0 && (old == 0 || disk < old) && p.diskInUse.Load() > disk {
+			p.signalDiskEvict()
+		}
+	}
+}
+
+// splitBudget gives each weight a floor of budget/(4n) and splits the rest
+// in proportion to the weights, or evenly when they are all zero.
+func splitBudget(budget int64, weights []int64) []int64 {
+	shares := make([]int64, len(weights))
+	if budget <= 0 || len(weights) == 0 {
+		return shares
+	}
+	n := int64(len(weights))
+	floor := budget / (4 * n)
+	rest := budget - floor*n
+	var total int64
+	for _, w := range weights {
+		total += w
+	}
+	for i, w := range weights {
+		shares[i] = floor
+		if total > 0 {
+			shares[i] += int64(float64(rest) * float64(w) / float64(total))
+		} else {
+			shares[i] += rest / n
+		}
+	}
+	return shares
+}
+
+// Stats returns the governed budgets and each registered pool's usage.
+func (g *Governor) Stats() GovernorStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	stats := GovernorStats{
+		MemoryBudget: g.memBudget,
+		DiskBudget:   g.diskBudget,
+		Consumers:    make([]ConsumerStats, 0, len(g.pools)),
+	}
+	for _, p := range g.sortedPools() {
+		ps := p.Stats()
+		stats.MemoryInUse += ps.MemoryInUse
+		stats.DiskInUse += ps.DiskInUse
+		stats.Consumers = append(stats.Consumers, ConsumerStats{
+			Name:         p.name,
+			Priority:     g.priority(p.name),
+			Streams:      ps.Buffers,
+			MemoryInUse:  ps.MemoryInUse,
+			MemoryBudget: ps.MemoryBudget,
+			DiskInUse:    ps.DiskInUse,
+			DiskLimit:    ps.DiskLimit,
+			PinnedInUse:  ps.PinnedInUse,
+		})
+	}
+	return stats
+}
@@ -0,0 +1,102 @@
+package buffer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitBudget(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  int64
+		weights []int64
+		want    []int64
+	}{
+		{name: "no budget", budget: 0, weights: []int64{1, 2}, want: []int64{0, 0}},
+		{name: "no pools", budget: 800, weights: nil, want: []int64{}},
+		{name: "single pool gets everything", budget: 800, weights: []int64{3}, want: []int64{800}},
+		{name: "all weights zero split evenly", budget: 800, weights: []int64{0, 0}, want: []int64{400, 400}},
+		{name: "idle pool keeps the floor", budget: 800, weights: []int64{1, 0}, want: []int64{700, 100}},
+		{name: "shares follow weight", budget: 800, weights: []int64{3, 1}, want: []int64{550, 250}},
+		{name: "three pools", budget: 1200, weights: []int64{2, 1, 1}, want: []int64{550, 325, 325}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitBudget(tt.budget, tt.weights); !slices.Equal(got, tt.want) {
+				t.Errorf("splitBudget(%d, %v) = %v, want %v", tt.budget, tt.weights, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGovernorRebalance(t *testing.T) {
+	g := NewGovernor()
+	dfs := NewPool(PoolConfig{Name: "dfs", MemoryBudget: 100, DiskLimit: 1000, Governor: g})
+	defer dfs.Close()
+	usenet := NewPool(PoolConfig{Name: "usenet", MemoryBudget: 200, Governor: g})
+	defer usenet.Close()
+
+	// openStream stands in for NewBuffer: the governor only counts entries.
+	// The stand-ins are dropped again before the pools close them.
+	defer func() {
+		for _, p := range []*Pool{dfs, usenet} {
+			p.mu.Lock()
+			clear(p.buffers)
+			p.mu.Unlock()
+		}
+	}()
+	openStream := func(p *Pool) {
+		p.mu.Lock()
+		p.buffers[&Buffer{}] = struct{}{}
+		p.mu.Unlock()
+	}
+	limits := func() []int64 {
+		return []int64{dfs.memBudget.Load(), dfs.DiskLimit(), usenet.memBudget.Load(), usenet.DiskLimit()}
+	}
+
+	steps := []struct {
+		name  string
+		apply func()
+		want  []int64
+	}{
+		{
+			name:  "ungoverned pools keep their own caps",
+			apply: func() {},
+			want:  []int64{100, 1000, 200, 0},
+		},
+		{
+			name:  "idle pools split the budget evenly",
+			apply: func() { g.Configure(GovernorConfig{MemoryBudget: 800, DiskBudget: 4000}) },
+			want:  []int64{400, 2000, 400, 2000},
+		},
+		{
+			name:  "a busy pool takes the headroom",
+			apply: func() { openStream(dfs); g.rebalance() },
+			want:  []int64{700, 3500, 100, 500},
+		},
+		{
+			name: "priority weighs streams",
+			apply: func() {
+				openStream(usenet)
+				g.Configure(GovernorConfig{MemoryBudget: 800, DiskBudget: 4000, Priorities: map[string]int{"dfs": 3}})
+			},
+			want: []int64{550, 2750, 250, 1250},
+		},
+		{
+			name:  "clearing one budget restores that base limit",
+			apply: func() { g.Configure(GovernorConfig{MemoryBudget: 800, Priorities: map[string]int{"dfs": 3}}) },
+			want:  []int64{550, 1000, 250, 0},
+		},
+		{
+			name:  "clearing both budgets restores every base limit",
+			apply: func() { g.Configure(GovernorConfig{}) },
+			want:  []int64{100, 1000, 200, 0},
+		},
+	}
+	for _, step := range steps {
+		step.apply()
+		if got := limits(); !slices.Equal(got, step.want) {
+			t.Fatalf("%s: limits = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
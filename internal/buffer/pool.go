@@ -8,7 +8,8 @@ import (
 // Pool is the buffer service: it owns a RAM budget and a disk limit shared
 // across every Buffer it hands out, plus the eviction policy that enforces
 // them. Instantiate one per workload (e.g. one for DFS, one for usenet) so the
-// two have independent budgets and can't starve each other.
+// two have independent budgets and can't starve each other, or register them
+// with a Governor to have them share one budget by demand.
 //
 // Memory: per-Buffer Config.MemorySize is a ceiling on one stream's hot
 // working set; the Pool's MemoryBudget caps the *sum* of resident block RAM
@@ -23,15 +24,29 @@ import (
 // single huge file is being streamed and never closed — the case whole-file
 // eviction can't handle. DiskLimit == 0 disables the disk backstop entirely
 // (usenet relies on its own playback-aware sliding window instead).
+//
+// A Pool registered with a Governor has its budget and limit replaced by its
+// share of the governor's, whenever the governor has one configured.
 type Pool struct {
 	name       string
 	memBudget  atomic.Int64 // RAM ceiling across Buffers; 0 = unlimited
 	diskLimit  atomic.Int64 // on-disk present bytes ceiling; 0 = unlimited
 	backWindow int64        // bytes retained behind a read head before punching
 
+	// baseMemBudget and baseDiskLimit are the configured caps, restored when
+	// the governor has no budget for that resource
+	baseMemBudget int64
+	baseDiskLimit int64
+	governor      *Governor
+	diskLoop      bool
+
 	memInUse  atomic.Int64 // sum of resident block RAM across Buffers
 	diskInUse atomic.Int64 // sum of on-disk present bytes across Buffers
 
+	// pinnedInUse is the on-disk present bytes of pinned Buffers, which are
+	// kept out of diskInUse and never reclaimed by the backstop
+	pinnedInUse atomic.Int64
+
 	mu      sync.RWMutex
 	buffers map[*Buffer]struct{}
 
@@ -62,6 +77,11 @@ type PoolConfig struct {
 	// backstop preserves before punching (short seek-backs stay local). Only
 	// meaningful when DiskLimit > 0.
 	BackWindow int64
+
+	// Governor, when set, shares its process-wide budgets between this pool
+	// and the others registered with it. nil = the pool only answers to
+	// MemoryBudget and DiskLimit.
+	Governor *Governor
 }
 
 // PoolStats reports pool-wide counters.
@@ -70,13 +90,15 @@ type PoolStats struct {
 	MemoryBudget   int64
 	DiskInUse      int64
 	DiskLimit      int64
+	PinnedInUse    int64
 	Buffers        int
 	DiskPunches    int64
 	BytesReclaimed int64
 }
 
-// NewPool creates a Pool. If DiskLimit > 0 it starts a background worker that
-// reclaims disk by punching behind read heads when the limit is breached.
+// NewPool creates a Pool. If DiskLimit > 0 or the pool is governed it starts a
+// background worker that reclaims disk by punching behind read heads when the
+// limit is breached.
 func NewPool(cfg PoolConfig) *Pool {
 	if cfg.MemoryBudget < 0 {
 		cfg.MemoryBudget = 0
@@ -88,18 +110,25 @@ func NewPool(cfg PoolConfig) *Pool {
 		cfg.BackWindow = 0
 	}
 	p := &Pool{
-		name:       cfg.Name,
-		backWindow: cfg.BackWindow,
-		buffers:    make(map[*Buffer]struct{}),
-		evictSig:   make(chan struct{}, 1),
-		stopCh:     make(chan struct{}),
+		name:          cfg.Name,
+		backWindow:    cfg.BackWindow,
+		baseMemBudget: cfg.MemoryBudget,
+		baseDiskLimit: cfg.DiskLimit,
+		governor:      cfg.Governor,
+		diskLoop:      cfg.DiskLimit > 0 || cfg.Governor != nil,
+		buffers:       make(map[*Buffer]struct{}),
+		evictSig:      make(chan struct{}, 1),
+		stopCh:        make(chan struct{}),
 	}
 	p.memBudget.Store(cfg.MemoryBudget)
 	p.diskLimit.Store(cfg.DiskLimit)
-	if cfg.DiskLimit > 0 {
+	if p.diskLoop {
 		p.wg.Add(1)
 		go p.diskEvictLoop()
 	}
+	if p.governor != nil {
+		p.governor.register(p)
+	}
 	return p
 }
 
@@ -113,6 +142,9 @@ func (p *Pool) NewBuffer(cfg Config) (*Buffer, error) {
 	p.mu.Lock()
 	p.buffers[b] = struct{}{}
 	p.mu.Unlock()
+	if p.governor != nil {
+		p.governor.poke()
+	}
 	return b, nil
 }
 
@@ -121,6 +153,23 @@ func (p *Pool) remove(b *Buffer) {
 	p.mu.Lock()
 	delete(p.buffers, b)
 	p.mu.Unlock()
+	if p.governor != nil {
+		p.governor.poke()
+	}
+}
+
+// streams returns how many Buffers are open, the pool's share of the
+// governor's weight.
+func (p *Pool) streams() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.buffers)
+}
+
+// DiskLimit returns the pool's current disk ceiling, which the governor may
+// have moved off the configured DiskLimit. 0 = unlimited.
+func (p *Pool) DiskLimit() int64 {
+	return p.diskLimit.Load()
 }
 
 // Stats returns a snapshot of the pool's counters.
@@ -133,6 +182,7 @@ func (p *Pool) Stats() PoolStats {
 		MemoryBudget:   p.memBudget.Load(),
 		DiskInUse:      p.diskInUse.Load(),
 		DiskLimit:      p.diskLimit.Load(),
+		PinnedInUse:    p.pinnedInUse.Load(),
 		Buffers:        n,
 		DiskPunches:    p.statsPunches.Load(),
 		BytesReclaimed: p.statsReclaimed.Load(),
@@ -146,7 +196,10 @@ func (p *Pool) Close() error {
 	if !p.closed.CompareAndSwap(false, true) {
 		return nil
 	}
-	if p.diskLimit.Load() > 0 {
+	if p.governor != nil {
+		p.governor.unregister(p)
+	}
+	if p.diskLoop {
 		close(p.stopCh)
 		p.wg.Wait()
 	}
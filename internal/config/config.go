@@ -43,6 +43,15 @@ const (
 	DownloadActionNone     DownloadAction = "none"
 )
 
+// Valid reports whether a is one of the download actions
+func (a DownloadAction) Valid() bool {
+	switch a {
+	case DownloadActionSymlink, DownloadActionDownload, DownloadActionStrm, DownloadActionNone:
+		return true
+	}
+	return false
+}
+
 const (
 	WebDavUseFileName          WebDavFolderNaming = "filename"
 	WebDavUseOriginalName      WebDavFolderNaming = "original"
@@ -171,6 +180,10 @@ func mergeQueueCleanupRules(rules []QueueCleanupRule) []QueueCleanupRule {
 
 type CustomFolders struct {
 	Filters map[string]string `json:"filters,omitempty"`
+
+	// Expr is a boolean expression over the entry (see FolderExpr). It
+	// replaces Filters, which only ANDs one filter of each type.
+	Expr string `json:"expr,omitempty"`
 }
 
 type Auth struct {
@@ -197,6 +210,8 @@ type RepairConfig struct {
 	Workers               int          `json:"workers,omitempty"`
 	NNTPConnectionPercent int          `json:"nntp_connection_percent,omitempty"`
 	Strategy              string       `json:"strategy,omitempty"`
+	PieceVerify           bool         `json:"piece_verify,omitempty"` // Hash-check sampled torrent pieces instead of only link liveness
+	PieceVerifySamples    int          `json:"piece_verify_samples,omitempty"`
 	RecheckInterval       string       `json:"recheck_interval,omitempty"`
 	Arrs                  []string     `json:"arrs,omitempty"`
 	AutoRepair            bool         `json:"auto_repair,omitempty"`
@@ -205,10 +220,90 @@ type RepairConfig struct {
 
 func (r RepairConfig) IsZero() bool {
 	return !r.Enabled && r.Source == "" && r.Schedule == "" && r.Workers == 0 &&
-		r.NNTPConnectionPercent == 0 && r.Strategy == "" && r.RecheckInterval == "" && len(r.Arrs) == 0 &&
+		r.NNTPConnectionPercent == 0 && r.Strategy == "" && !r.PieceVerify && r.PieceVerifySamples == 0 && r.RecheckInterval == "" && len(r.Arrs) == 0 &&
 		!r.AutoRepair && !r.SkipNZBRepair
 }
 
+// ReconcileConfig schedules the debrid library reconciliation. Actions are
+// "" (report only) or one of: orphans adopt|delete, duplicates delete,
+// missing readd|remove.
+type ReconcileConfig struct {
+	Schedule   string `json:"schedule,omitempty"` // Empty disables the scheduled run
+	DryRun     bool   `json:"dry_run,omitempty"`
+	Orphans    string `json:"orphans,omitempty"`
+	Duplicates string `json:"duplicates,omitempty"`
+	Missing    string `json:"missing,omitempty"`
+}
+
+func (r ReconcileConfig) IsZero() bool {
+	return r.Schedule == "" && !r.DryRun && r.Orphans == "" && r.Duplicates == "" && r.Missing == ""
+}
+
+// RecycleBinConfig keeps deleted entries restorable for a while. Their
+// placements are removed at once; the entry, magnet, NZB and metainfo are
+// kept until the entry is restored or purged.
+type RecycleBinConfig struct {
+	Enabled   bool   `json:"enabled,omitempty"`
+	Retention string `json:"retention,omitempty"` // How long deleted entries are kept. Default 7d
+	Schedule  string `json:"schedule,omitempty"`  // How often expired entries are purged. Default 1h
+}
+
+func (r RecycleBinConfig) IsZero() bool {
+	return !r.Enabled && r.Retention == "" && r.Schedule == ""
+}
+
+// EventsConfig controls the per-entry event timeline
+type EventsConfig struct {
+	Disabled    bool   `json:"disabled,omitempty"`
+	Retention   string `json:"retention,omitempty"`     // Events older than this are pruned, e.g. "30d"
+	MaxPerEntry int    `json:"max_per_entry,omitempty"` // Oldest events are dropped past this count
+}
+
+func (e EventsConfig) IsZero() bool {
+	return !e.Disabled && e.Retention == "" && e.MaxPerEntry == 0
+}
+
+// SFTPConfig serves the virtual library read-only over SFTP. Logins use the
+// app credentials, the API token as a password, or a key listed in
+// AuthorizedKeys.
+type SFTPConfig struct {
+	Enabled        bool   `json:"enabled,omitempty"`
+	Port           string `json:"port,omitempty"`            // Default 2222, on bind_address
+	HostKey        string `json:"host_key,omitempty"`        // Private key file; generated when missing. Default main_path/sftp_host_key
+	AuthorizedKeys string `json:"authorized_keys,omitempty"` // OpenSSH authorized_keys file. Default main_path/sftp_authorized_keys
+}
+
+func (s SFTPConfig) IsZero() bool {
+	return !s.Enabled && s.Port == "" && s.HostKey == "" && s.AuthorizedKeys == ""
+}
+
+// BuffersConfig is one RAM and disk budget shared by the DFS and usenet
+// streaming buffers. Headroom goes to whichever side has more active streams,
+// weighted by priority. An empty size keeps the per-side caps
+// (mount.dfs.buffer_memory, usenet.buffer_memory, mount.dfs.cache_max_size).
+type BuffersConfig struct {
+	Memory         string `json:"memory,omitempty"`          // e.g. "1GB"
+	Disk           string `json:"disk,omitempty"`            // e.g. "100GB"
+	DFSPriority    int    `json:"dfs_priority,omitempty"`    // Default 1
+	UsenetPriority int    `json:"usenet_priority,omitempty"` // Default 1
+}
+
+func (b BuffersConfig) IsZero() bool {
+	return b.Memory == "" && b.Disk == "" && b.DFSPriority == 0 && b.UsenetPriority == 0
+}
+
+// MemoryBytes resolves the shared RAM budget; 0 when unset or invalid.
+func (b BuffersConfig) MemoryBytes() int64 {
+	n, _ := ParseSize(b.Memory)
+	return n
+}
+
+// DiskBytes resolves the shared disk budget; 0 when unset or invalid.
+func (b BuffersConfig) DiskBytes() int64 {
+	n, _ := ParseSize(b.Disk)
+	return n
+}
+
 type Config struct {
 	// server
 	BindAddress string `json:"bind_address,omitempty"`
@@ -227,6 +322,7 @@ type Config struct {
 
 	AllowedExt         []string `json:"allowed_file_types,omitempty"`
 	AllowSamples       bool     `json:"allow_samples,omitempty"`
+	ProbeMedia         bool     `json:"probe_media,omitempty"` // Read MKV/MP4 headers on import and during repair
 	MinFileSize        string   `json:"min_file_size,omitempty"`
 	MaxFileSize        string   `json:"max_file_size,omitempty"`
 	RemoveStalledAfter string   `json:"remove_stalled_after,omitzero"`
@@ -255,13 +351,23 @@ type Config struct {
 	Categories            []string                 `json:"categories,omitempty"`
 	FolderNaming          WebDavFolderNaming       `json:"folder_naming,omitempty"`
 	CustomFolders         map[string]CustomFolders `json:"custom_folders,omitempty"`
+	FileSelection         map[string]FileSelection `json:"file_selection,omitempty"` // category -> file-selection rules
+	CategoryProfiles      []CategoryProfile        `json:"category_profiles,omitempty"`
+	TagRules              []TagRule                `json:"tag_rules,omitempty"`
 	DefaultDownloadAction DownloadAction           `json:"default_download_action,omitempty"`
 
 	RefreshDirs  string `json:"refresh_dirs,omitempty"`
 	Retries      int    `json:"retries,omitempty"`
 	SkipAutoMove bool   `json:"skip_auto_move,omitempty"`
 
-	Repair RepairConfig `json:"repair,omitzero"`
+	Repair     RepairConfig     `json:"repair,omitzero"`
+	Reconcile  ReconcileConfig  `json:"reconcile,omitzero"`
+	Retention  RetentionConfig  `json:"retention,omitzero"`
+	RecycleBin RecycleBinConfig `json:"recycle_bin,omitzero"`
+	Guardian   GuardianConfig   `json:"guardian,omitzero"`
+	Events     EventsConfig     `json:"events,omitzero"`
+	Buffers    BuffersConfig    `json:"buffers,omitzero"`
+	SFTP       SFTPConfig       `json:"sftp,omitzero"`
 
 	// QueueCleanup is the global arr queue-cleanup policy (see CleanupQueue).
 	QueueCleanup QueueCleanup `json:"queue_cleanup"`
@@ -319,6 +425,51 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := validateMountViews(c.Mount); err != nil {
+		return err
+	}
+
+	for name, folder := range c.CustomFolders {
+		if folder.Expr == "" {
+			continue
+		}
+		if len(folder.Filters) > 0 {
+			return fmt.Errorf("custom folder %s: use either expr or filters, not both", name)
+		}
+		if _, err := ParseFolderExpr(folder.Expr); err != nil {
+			return fmt.Errorf("custom folder %s: %w", name, err)
+		}
+	}
+
+	if err := validateFileSelection(c.FileSelection); err != nil {
+		return err
+	}
+
+	if err := validateCategoryProfiles(c.CategoryProfiles, c.Debrids, c.Arrs); err != nil {
+		return err
+	}
+
+	if err := ValidateTagRules(c.TagRules, c.CategoryProfiles, c.CustomFolders); err != nil {
+		return err
+	}
+
+	if err := validateRetention(c.Retention); err != nil {
+		return err
+	}
+
+	if err := validateGuardian(c.Guardian); err != nil {
+		return err
+	}
+
+	for _, size := range []string{c.Buffers.Memory, c.Buffers.Disk} {
+		if size == "" {
+			continue
+		}
+		if _, err := ParseSize(size); err != nil {
+			return fmt.Errorf("buffers: invalid size %q", size)
+		}
+	}
+
 	if c.DownloadFolder == "" {
 		return errors.New("download folder is required")
 	}
@@ -643,6 +794,13 @@ func (c *Config) setDefaults() {
 	}
 
 	c.applyRepairDefaults()
+
+	if c.Events.Retention == "" {
+		c.Events.Retention = "30d"
+	}
+	if c.Events.MaxPerEntry <= 0 {
+		c.Events.MaxPerEntry = 200
+	}
 }
 
 func (c *Config) applyRepairDefaults() {
@@ -707,6 +865,7 @@ func clearHotFields(c *Config) {
 	c.Arrs = nil
 	c.AllowedExt = nil
 	c.AllowSamples = false
+	c.ProbeMedia = false
 	c.MinFileSize = ""
 	c.MaxFileSize = ""
 	c.RemoveStalledAfter = ""
@@ -723,12 +882,31 @@ func clearHotFields(c *Config) {
 	c.Categories = nil
 	c.FolderNaming = ""
 	c.CustomFolders = nil
+	c.FileSelection = nil
+	c.CategoryProfiles = nil
+	c.TagRules = nil
 	c.DefaultDownloadAction = ""
 	c.RefreshDirs = ""
 	c.Retries = 0
 	c.SkipAutoMove = false
 	c.Repair = RepairConfig{}
 
+	// Retention policies are read live on each run; only the schedule is
+	// registered at startup.
+	c.Retention.DryRun = false
+	c.Retention.Policies = nil
+
+	// The recycle bin is checked on each delete and purge; only the purge
+	// schedule is registered at startup.
+	c.RecycleBin.Enabled = false
+	c.RecycleBin.Retention = ""
+
+	// The guardian reads its margin, dry_run and eviction switch on each
+	// run; only the schedule is registered at startup.
+	c.Guardian.Margin = ""
+	c.Guardian.DryRun = false
+	c.Guardian.Evict = false
+
 	// Queue cleanup rules are read live via config.Get() inside CleanupQueue,
 	// so changes apply on the next cleanup cycle without a restart.
 	c.QueueCleanup = QueueCleanup{}
@@ -740,9 +918,11 @@ func clearHotFields(c *Config) {
 	// and the streaming buffer pool are all established at startup). But the
 	// availability sampling percentages are read live on each repair/import
 	// check (see Usenet.CheckFile / checkNZBAvailability), so they apply without
-	// a restart. Everything else in Usenet stays restart-required.
+	// a restart. PreferDebrid is read live on each NZB import. Everything else
+	// in Usenet stays restart-required.
 	c.Usenet.AvailabilitySamplePercent = 0
 	c.Usenet.ImportAvailabilitySamplePercent = 0
+	c.Usenet.PreferDebrid = false
 }
 
 // RequiresRestart reports whether applying n on top of c needs a full service
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"runtime"
+	"time"
 )
 
 type Debrid struct {
@@ -24,6 +26,17 @@ type Debrid struct {
 	Workers                      int      `json:"workers,omitempty"`
 	AutoExpireLinksAfter         string   `json:"auto_expire_links_after,omitempty"`
 	UserAgent                    string   `json:"user_agent,omitempty"`
+	Usenet                       bool     `json:"usenet,omitempty"` // Also send NZBs to this debrid's usenet service (torbox, premiumize)
+
+	// Account lifecycle. Traffic limits apply to each download account.
+	DailyTrafficLimit   string `json:"daily_traffic_limit,omitempty"`   // e.g. 500GB
+	MonthlyTrafficLimit string `json:"monthly_traffic_limit,omitempty"` // e.g. 5TB
+	ExpiryWarningDays   int    `json:"expiry_warning_days,omitempty"`   // Notify this many days before premium expires
+
+	// Torrent expiry, as the debrid applies it. The guardian refreshes a
+	// placement before either rule drops it. Durations take days and weeks.
+	TorrentMaxAge     string `json:"torrent_max_age,omitempty"`     // Torrents are dropped this long after being added, e.g. 30d
+	TorrentIdleExpiry string `json:"torrent_idle_expiry,omitempty"` // Torrents not streamed for this long are dropped
 
 	// Folder
 	Folder        string `json:"folder,omitempty"`          // Deprecated. Use Mount MountPath instead.
@@ -81,11 +94,45 @@ func validateDebrids(debrids []Debrid) error {
 		if debrid.APIKey == "" {
 			return errors.New("debrid api key is required")
 		}
+		if debrid.Usenet && !SupportsUsenet(cmp.Or(debrid.Provider, debrid.Name)) {
+			return fmt.Errorf("debrid %s: usenet is only supported on torbox and premiumize", debrid.Name)
+		}
+		for _, limit := range []string{debrid.DailyTrafficLimit, debrid.MonthlyTrafficLimit} {
+			if limit == "" {
+				continue
+			}
+			if _, err := ParseSize(limit); err != nil {
+				return fmt.Errorf("debrid %s: invalid traffic limit %q", debrid.Name, limit)
+			}
+		}
+		if _, _, err := debrid.TorrentExpiry(); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// TorrentExpiry parses TorrentMaxAge and TorrentIdleExpiry; an unset one is 0
+func (d *Debrid) TorrentExpiry() (maxAge, idle time.Duration, err error) {
+	if d.TorrentMaxAge != "" {
+		if maxAge, err = ParseDuration(d.TorrentMaxAge); err != nil || maxAge <= 0 {
+			return 0, 0, fmt.Errorf("debrid %s: invalid torrent_max_age %q", d.Name, d.TorrentMaxAge)
+		}
+	}
+	if d.TorrentIdleExpiry != "" {
+		if idle, err = ParseDuration(d.TorrentIdleExpiry); err != nil || idle <= 0 {
+			return 0, 0, fmt.Errorf("debrid %s: invalid torrent_idle_expiry %q", d.Name, d.TorrentIdleExpiry)
+		}
+	}
+	return maxAge, idle, nil
+}
+
+// SupportsUsenet reports whether a debrid provider accepts NZBs.
+func SupportsUsenet(provider string) bool {
+	return provider == "torbox" || provider == "premiumize"
+}
+
 func (c *Config) applyDebridEnvVars() {
 	// Debrid providers array
 	for i := range 10 { // Support up to 10 debrid providers
@@ -110,6 +157,9 @@ func (c *Config) applyDebridEnvVars() {
 			if proxy := getEnv(prefix + "PROXY"); proxy != "" {
 				c.Debrids[i].Proxy = proxy
 			}
+			if usenet := getEnv(prefix + "USENET"); usenet != "" {
+				c.Debrids[i].Usenet = parseBool(usenet)
+			}
 		}
 	}
 }
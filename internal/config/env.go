@@ -55,6 +55,9 @@ func (c *Config) applyEnvOverrides() {
 	if val := getEnv("SKIP_PRE_CACHE"); val != "" {
 		c.SkipPreCache = parseBool(val)
 	}
+	if val := getEnv("PROBE_MEDIA"); val != "" {
+		c.ProbeMedia = parseBool(val)
+	}
 	if val := getEnv("ALWAYS_RM_TRACKER_URLS"); val != "" {
 		c.AlwaysRmTrackerUrls = parseBool(val)
 	}
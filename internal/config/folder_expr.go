@@ -0,0 +1,423 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// FolderExpr is a compiled custom folder expression, e.g.
+//
+//	(name ~ "(?i)remux" or file = "*.iso") and size > 2GB and not tag = kids
+//
+// Conditions are `field op value` or the bare word `bad`, combined with
+// and, or, not and parentheses. String fields compare with = and != as
+// case-insensitive globs, and with ~ and !~ as regular expressions; tag and
+// file match when any of the entry's tags or files do, and their negated
+// forms when none do. size, files (the file count) and age take =, !=, <,
+// <=, > and >=.
+type FolderExpr struct {
+	src  string
+	root exprNode
+}
+
+// FolderSubject is what a folder expression is matched against
+type FolderSubject struct {
+	Name     string
+	Category string
+	Provider string
+	Protocol string
+	Health   string
+	Tags     []string
+	Files    []string
+	Size     int64
+	AddedOn  time.Time
+	Bad      bool
+	Folders  []string // Custom folders tag rules routed the entry to
+}
+
+// Fields a folder expression can test
+const (
+	FolderFieldName     = "name"
+	FolderFieldFile     = "file"
+	FolderFieldCategory = "category"
+	FolderFieldTag      = "tag"
+	FolderFieldProvider = "provider"
+	FolderFieldProtocol = "protocol"
+	FolderFieldHealth   = "health"
+	FolderFieldSize     = "size"
+	FolderFieldFiles    = "files"
+	FolderFieldAge      = "age"
+	FolderFieldBad      = "bad"
+)
+
+// ParseFolderExpr compiles a custom folder expression
+func ParseFolderExpr(src string) (*FolderExpr, error) {
+	tokens, err := lexFolderExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at %d", tok.text, tok.pos)
+	}
+	return &FolderExpr{src: src, root: root}, nil
+}
+
+func (e *FolderExpr) String() string { return e.src }
+
+// Match reports whether the subject belongs in the folder. now is the time
+// age conditions are measured against.
+func (e *FolderExpr) Match(s *FolderSubject, now time.Time) bool {
+	return e.root.eval(s, now)
+}
+
+// Uses reports whether the expression has a condition on field
+func (e *FolderExpr) Uses(field string) bool {
+	return e.root.uses(field)
+}
+
+type exprNode interface {
+	eval(s *FolderSubject, now time.Time) bool
+	uses(field string) bool
+}
+
+type andNode struct{ left, right exprNode }
+type orNode struct{ left, right exprNode }
+type notNode struct{ inner exprNode }
+
+func (n andNode) eval(s *FolderSubject, now time.Time) bool {
+	return n.left.eval(s, now) && n.right.eval(s, now)
+}
+func (n andNode) uses(field string) bool { return n.left.uses(field) || n.right.uses(field) }
+
+func (n orNode) eval(s *FolderSubject, now time.Time) bool {
+	return n.left.eval(s, now) || n.right.eval(s, now)
+}
+func (n orNode) uses(field string) bool { return n.left.uses(field) || n.right.uses(field) }
+
+func (n notNode) eval(s *FolderSubject, now time.Time) bool { return !n.inner.eval(s, now) }
+func (n notNode) uses(field string) bool                    { return n.inner.uses(field) }
+
+type badNode struct{}
+
+func (badNode) eval(s *FolderSubject, _ time.Time) bool { return s.Bad }
+func (badNode) uses(field string) bool                  { return field == FolderFieldBad }
+
+// stringNode matches a text field. Negated operators hold when no value
+// matches, so `tag != kids` means the entry has no kids tag.
+type stringNode struct {
+	field  string
+	negate bool
+	glob   string         // for = and !=, lower-cased
+	regex  *regexp.Regexp // for ~ and !~
+}
+
+func (n stringNode) eval(s *FolderSubject, _ time.Time) bool {
+	var values []string
+	switch n.field {
+	case FolderFieldName:
+		values = []string{s.Name}
+	case FolderFieldFile:
+		values = s.Files
+	case FolderFieldCategory:
+		values = []string{s.Category}
+	case FolderFieldTag:
+		values = s.Tags
+	case FolderFieldProvider:
+		values = []string{s.Provider}
+	case FolderFieldProtocol:
+		values = []string{s.Protocol}
+	case FolderFieldHealth:
+		values = []string{s.Health}
+	}
+	matched := false
+	for _, v := range values {
+		if n.regex != nil {
+			matched = n.regex.MatchString(v)
+		} else {
+			matched, _ = path.Match(n.glob, strings.ToLower(v))
+		}
+		if matched {
+			break
+		}
+	}
+	return matched != n.negate
+}
+
+func (n stringNode) uses(field string) bool { return n.field == field }
+
+type numberNode struct {
+	field string
+	op    string
+	value int64 // bytes, count or nanoseconds
+}
+
+func (n numberNode) eval(s *FolderSubject, now time.Time) bool {
+	var v int64
+	switch n.field {
+	case FolderFieldSize:
+		v = s.Size
+	case FolderFieldFiles:
+		v = int64(len(s.Files))
+	case FolderFieldAge:
+		v = int64(now.Sub(s.AddedOn))
+	}
+	switch n.op {
+	case "=":
+		return v == n.value
+	case "!=":
+		return v != n.value
+	case "<":
+		return v < n.value
+	case "<=":
+		return v <= n.value
+	case ">":
+		return v > n.value
+	case ">=":
+		return v >= n.value
+	}
+	return false
+}
+
+func (n numberNode) uses(field string) bool { return n.field == field }
+
+// Parser
+
+type exprTokenKind int
+
+const (
+	tokEOF exprTokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	text string
+	pos  int
+}
+
+func lexFolderExpr(src string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, exprToken{tokLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, exprToken{tokRParen, ")", i})
+			i++
+		case r == '"':
+			start := i
+			var sb strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at %d", start)
+			}
+			i++
+			tokens = append(tokens, exprToken{tokString, sb.String(), start})
+		case strings.ContainsRune("=!<>~", r):
+			start := i
+			op := string(r)
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '!' && runes[i+1] == '~')) {
+				op += string(runes[i+1])
+			}
+			i += len([]rune(op))
+			switch op {
+			case "=", "!=", "~", "!~", "<", "<=", ">", ">=":
+			default:
+				return nil, fmt.Errorf("unknown operator %q at %d", op, start)
+			}
+			tokens = append(tokens, exprToken{tokOp, op, start})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("()\"=!<>~", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, exprToken{tokWord, string(runes[start:i]), start})
+		}
+	}
+	return append(tokens, exprToken{tokEOF, "end of expression", len(runes)}), nil
+}
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+func (p *exprParser) peek() exprToken { return p.tokens[p.pos] }
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *exprParser) keyword(word string) bool {
+	tok := p.peek()
+	if tok.kind == tokWord && strings.EqualFold(tok.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseNot() (exprNode, error) {
+	if p.keyword("not") {
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	}
+	if p.peek().kind == tokLParen {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokRParen {
+			return nil, fmt.Errorf("expected ) at %d, got %q", tok.pos, tok.text)
+		}
+		return inner, nil
+	}
+	return p.parseCondition()
+}
+
+func (p *exprParser) parseCondition() (exprNode, error) {
+	tok := p.next()
+	if tok.kind != tokWord {
+		return nil, fmt.Errorf("expected a condition at %d, got %q", tok.pos, tok.text)
+	}
+	field := strings.ToLower(tok.text)
+	if field == FolderFieldBad {
+		return badNode{}, nil
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokOp {
+		return nil, fmt.Errorf("expected an operator after %s at %d, got %q", field, opTok.pos, opTok.text)
+	}
+	op := opTok.text
+	valTok := p.next()
+	if valTok.kind != tokWord && valTok.kind != tokString {
+		return nil, fmt.Errorf("expected a value after %s %s at %d, got %q", field, op, valTok.pos, valTok.text)
+	}
+	value := valTok.text
+
+	switch field {
+	case FolderFieldName, FolderFieldFile, FolderFieldCategory, FolderFieldTag, FolderFieldProvider, FolderFieldProtocol, FolderFieldHealth:
+		n := stringNode{field: field, negate: op == "!=" || op == "!~"}
+		switch op {
+		case "=", "!=":
+			n.glob = strings.ToLower(value)
+			if _, err := path.Match(n.glob, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q for %s: %w", value, field, err)
+			}
+		case "~", "!~":
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex %q for %s: %w", value, field, err)
+			}
+			n.regex = re
+		default:
+			return nil, fmt.Errorf("%s does not support %s", field, op)
+		}
+		return n, nil
+	case FolderFieldSize, FolderFieldFiles, FolderFieldAge:
+		if op == "~" || op == "!~" {
+			return nil, fmt.Errorf("%s does not support %s", field, op)
+		}
+		n := numberNode{field: field, op: op}
+		var err error
+		switch field {
+		case FolderFieldSize:
+			n.value, err = ParseSize(value)
+		case FolderFieldFiles:
+			n.value, err = strconv.ParseInt(value, 10, 64)
+		case FolderFieldAge:
+			var d time.Duration
+			d, err = parseExprAge(value)
+			n.value = int64(d)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q", field, value)
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("unknown field %q at %d", tok.text, tok.pos)
+	}
+}
+
+// parseExprAge parses Go durations plus whole days and weeks, e.g. 7d or 2w
+func parseExprAge(s string) (time.Duration, error) {
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit == 0 {
+		return time.ParseDuration(s)
+	}
+	n, err := strconv.ParseFloat(s[:len(s)-1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(n * float64(unit)), nil
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFolderExprMatch(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	subject := &FolderSubject{
+		Name:     `Movie "Cut" 2019 REMUX`,
+		Category: "movies",
+		Provider: "realdebrid",
+		Protocol: "torrent",
+		Health:   "healthy",
+		Tags:     []string{"4k", "family"},
+		Files:    []string{"Movie.mkv", "Movie.srt"},
+		Size:     3 << 30,
+		AddedOn:  now.Add(-10 * 24 * time.Hour),
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		// and binds tighter than or, not tighter than and
+		{expr: `bad or category = movies and not tag = 4k`, want: false},
+		{expr: `category = movies or bad and not tag = 4k`, want: true},
+		{expr: `(category = movies or bad) and not tag = 4k`, want: false},
+		{expr: `not bad and not not tag = 4k`, want: true},
+		{expr: `BAD OR Category = MOVIES`, want: true},
+
+		// negated forms hold when no value matches
+		{expr: `tag != kids`, want: true},
+		{expr: `tag != family`, want: false},
+		{expr: `tag = family`, want: true},
+		{expr: `file != "*.iso"`, want: true},
+		{expr: `file = "*.SRT"`, want: true},
+		{expr: `file !~ "\.mkv$"`, want: false},
+
+		// strings, globs and regexes
+		{expr: `name = "movie \"cut\" *"`, want: true},
+		{expr: `name = "*\\*"`, want: false},
+		{expr: `name ~ "(?i)remux"`, want: true},
+		{expr: `name ~ "remux"`, want: false},
+		{expr: `provider = real*`, want: true},
+
+		// ages
+		{expr: `age > 7d`, want: true},
+		{expr: `age > 2w`, want: false},
+		{expr: `age >= 1.5w`, want: false},
+		{expr: `age < 240h`, want: false},
+		{expr: `age <= 240h`, want: true},
+
+		// sizes and counts
+		{expr: `size > 2GB`, want: true},
+		{expr: `size > 3GB`, want: false},
+		{expr: `size >= 3GB`, want: true},
+		{expr: `size < 2.5gb`, want: false},
+		{expr: `files = 2`, want: true},
+		{expr: `files != 2`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := ParseFolderExpr(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseFolderExpr(%q) error = %v", tt.expr, err)
+			}
+			if got := e.Match(subject, now); got != tt.want {
+				t.Errorf("%q matched = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFolderExprErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{expr: `size => 2GB`, wantErr: `expected a value after size = at 6, got ">"`},
+		{expr: `tag ! kids`, wantErr: `unknown operator "!" at 4`},
+		{expr: `tag = kids and name = "remux`, wantErr: "unterminated string at 22"},
+		{expr: `name = "a\"`, wantErr: "unterminated string at 7"},
+		{expr: `colour = red`, wantErr: `unknown field "colour" at 0`},
+		{expr: `bad and quality = 4k`, wantErr: `unknown field "quality" at 8`},
+		{expr: `tag kids`, wantErr: `expected an operator after tag at 4, got "kids"`},
+		{expr: `(bad or tag = kids`, wantErr: `expected ) at 18, got "end of expression"`},
+		{expr: `bad tag = kids`, wantErr: `unexpected "tag" at 4`},
+		{expr: `size ~ 2GB`, wantErr: "size does not support ~"},
+		{expr: `tag > kids`, wantErr: "tag does not support >"},
+		{expr: `age > soon`, wantErr: `invalid age value "soon"`},
+		{expr: `name ~ "("`, wantErr: `invalid regex "("`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseFolderExpr(tt.expr)
+			if err == nil {
+				t.Fatalf("ParseFolderExpr(%q) succeeded, want error %q", tt.expr, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseFolderExpr(%q) error = %q, want %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
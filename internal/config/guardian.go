@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultGuardianMargin is how long before expiry the guardian refreshes a
+// placement when no margin is set.
+const DefaultGuardianMargin = 2 * 24 * time.Hour
+
+// GuardianConfig schedules the debrid expiry guardian. It keeps entries'
+// placements in sync with their debrids, re-adds placements that are gone or
+// about to expire under the debrid's torrent_max_age and torrent_idle_expiry,
+// and with Evict frees room on a debrid at its limit.
+type GuardianConfig struct {
+	Schedule string `json:"schedule,omitempty"` // Empty disables the scheduled run
+	Margin   string `json:"margin,omitempty"`   // Refresh placements this long before they expire. Default 2d
+	DryRun   bool   `json:"dry_run,omitempty"`  // Scheduled runs only report
+	Evict    bool   `json:"evict,omitempty"`    // Evict the least recently streamed entries from a debrid at its limit
+}
+
+func (g GuardianConfig) IsZero() bool {
+	return g.Schedule == "" && g.Margin == "" && !g.DryRun && !g.Evict
+}
+
+// MarginDuration parses Margin, falling back to DefaultGuardianMargin
+func (g GuardianConfig) MarginDuration() (time.Duration, error) {
+	if g.Margin == "" {
+		return DefaultGuardianMargin, nil
+	}
+	margin, err := ParseDuration(g.Margin)
+	if err != nil || margin < 0 {
+		return 0, fmt.Errorf("guardian: invalid margin %q", g.Margin)
+	}
+	return margin, nil
+}
+
+func validateGuardian(g GuardianConfig) error {
+	_, err := g.MarginDuration()
+	return err
+}
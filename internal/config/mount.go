@@ -1,6 +1,12 @@
 package config
 
-import "strconv"
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
 
 type Rclone struct {
 	// Global mount folder where all providers will be mounted as subfolders
@@ -55,6 +61,10 @@ type DFS struct {
 	DiskCacheSize        string `json:"disk_cache_size,omitempty"`        // 10GB, 50GB etc
 	CacheCleanupInterval string `json:"cache_cleanup_interval,omitempty"` // 10m, 1h etc
 
+	// PinnedCacheSize caps the disk used by pinned entries, e.g. "200GB". It
+	// is accounted separately from DiskCacheSize. Empty = unlimited.
+	PinnedCacheSize string `json:"pinned_cache_size,omitempty"`
+
 	// BufferMemory caps the total RAM the DFS streaming buffers hold across all
 	// open files, e.g. "512MB". Per-file buffers stay generous for smooth
 	// playback; this bounds the aggregate so many concurrent streams can't OOM.
@@ -119,8 +129,79 @@ type Mount struct {
 	Rclone         Rclone         `json:"rclone"`
 	DFS            DFS            `json:"dfs"`
 	ExternalRclone ExternalRclone `json:"external_rclone"`
+
+	// Views are extra mounts that each show a filtered part of the library,
+	// next to the full tree at MountPath. DFS and the managed rclone mount only.
+	Views []MountView `json:"views,omitempty"`
+}
+
+// Mount view health filters
+const (
+	ViewHealthAny     = ""
+	ViewHealthHealthy = "healthy"
+	ViewHealthBad     = "bad"
+)
+
+// MountView is a named mount of part of the library. Every filter that is set
+// must match; an empty filter matches everything.
+type MountView struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mount_path"`
+
+	Folders    []string `json:"folders,omitempty"`    // Root folders shown: __all__, torrents, nzbs, providers, custom folders
+	Categories []string `json:"categories,omitempty"` // Only entries in these categories
+	Protocols  []string `json:"protocols,omitempty"`  // Only torrent or nzb entries
+	Health     string   `json:"health,omitempty"`     // "healthy" hides bad entries, "bad" shows only them
+
+	// Rename shows root folders under another name, e.g. {"__all__": "Movies"}
+	Rename map[string]string `json:"rename,omitempty"`
 }
 
+func validateMountViews(mount Mount) error {
+	names := make(map[string]bool, len(mount.Views))
+	paths := map[string]bool{filepath.Clean(mount.MountPath): true}
+	for _, view := range mount.Views {
+		if !viewNameRe.MatchString(view.Name) {
+			return fmt.Errorf("mount view %q: name may only contain letters, digits, '-' and '_'", view.Name)
+		}
+		if names[view.Name] {
+			return fmt.Errorf("mount view %s is defined more than once", view.Name)
+		}
+		names[view.Name] = true
+		if view.MountPath == "" {
+			return fmt.Errorf("mount view %s: mount_path is required", view.Name)
+		}
+		if path := filepath.Clean(view.MountPath); paths[path] {
+			return fmt.Errorf("mount view %s: mount_path %s is already used", view.Name, view.MountPath)
+		} else {
+			paths[path] = true
+		}
+		for _, p := range view.Protocols {
+			if Protocol(p) != ProtocolTorrent && Protocol(p) != ProtocolNZB {
+				return fmt.Errorf("mount view %s: unknown protocol %q", view.Name, p)
+			}
+		}
+		switch view.Health {
+		case ViewHealthAny, ViewHealthHealthy, ViewHealthBad:
+		default:
+			return fmt.Errorf("mount view %s: health must be healthy or bad", view.Name)
+		}
+		shown := make(map[string]bool, len(view.Rename))
+		for from, to := range view.Rename {
+			if to == "" || strings.ContainsAny(to, `/\`) {
+				return fmt.Errorf("mount view %s: invalid name %q for %s", view.Name, to, from)
+			}
+			if shown[to] {
+				return fmt.Errorf("mount view %s: %q is used for more than one folder", view.Name, to)
+			}
+			shown[to] = true
+		}
+	}
+	return nil
+}
+
+var viewNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
 func (c *Config) applyMountEnvVars() {
 	// DFS settings
 	if val := getEnv("MOUNT__DFS__CACHE_DIR"); val != "" {
@@ -141,6 +222,9 @@ func (c *Config) applyMountEnvVars() {
 	if val := getEnv("MOUNT__DFS__CACHE_CLEANUP_INTERVAL"); val != "" {
 		c.Mount.DFS.CacheCleanupInterval = val
 	}
+	if val := getEnv("MOUNT__DFS__PINNED_CACHE_SIZE"); val != "" {
+		c.Mount.DFS.PinnedCacheSize = val
+	}
 	if val := getEnv("MOUNT__DFS__DAEMON_TIMEOUT"); val != "" {
 		c.Mount.DFS.DaemonTimeout = val
 	}
@@ -12,6 +12,10 @@ const (
 	EventRepairComplete   NotificationEvent = "repair_complete"
 	EventRepairFailed     NotificationEvent = "repair_failed"
 	EventRepairCancelled  NotificationEvent = "repair_cancelled"
+	EventAccountExpiring  NotificationEvent = "account_expiring"
+	EventAccountQuota     NotificationEvent = "account_quota"
+	EventLibraryPruned    NotificationEvent = "library_pruned"
+	EventDebridExpiry     NotificationEvent = "debrid_expiry"
 )
 
 // Notifications holds all notification configuration
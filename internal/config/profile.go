@@ -0,0 +1,164 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// CategoryProfile is a named download policy shared by one or more
+// categories. Unset fields fall back to the category's Arr and then to the
+// global settings.
+type CategoryProfile struct {
+	Name             string         `json:"name"`
+	Categories       []string       `json:"categories,omitempty"`        // Categories using this profile; a profile also applies to the category of its own name
+	DownloadAction   DownloadAction `json:"download_action,omitempty"`   // Overrides default_download_action
+	PreferredDebrid  string         `json:"preferred_debrid,omitempty"`  // Tried first
+	AllowedDebrids   []string       `json:"allowed_debrids,omitempty"`   // When set, only these are used
+	DownloadUncached *bool          `json:"download_uncached,omitempty"` // Overrides the Arr and debrid setting
+	DownloadFolder   string         `json:"download_folder,omitempty"`   // Overrides download_folder
+	FileSelection    *FileSelection `json:"file_selection,omitempty"`    // Overrides the category's file_selection rules
+	SkipMultiSeason  *bool          `json:"skip_multi_season,omitempty"` // Overrides skip_multi_season
+	SkipRepair       bool           `json:"skip_repair,omitempty"`       // Leave the category out of repairs
+}
+
+// Policy is the download policy resolved for a category
+type Policy struct {
+	Profile          string // Name of the matching profile, empty if none
+	DownloadAction   DownloadAction
+	PreferredDebrid  string
+	AllowedDebrids   []string
+	DownloadUncached *bool
+	DownloadFolder   string // Empty unless the profile sets one; callers keep their own folder then
+	FileSelection    *FileSelection
+	SkipMultiSeason  bool
+	SkipRepair       bool
+}
+
+// AllowsDebrid reports whether the policy lets a download go to a debrid
+func (p Policy) AllowsDebrid(name string) bool {
+	return len(p.AllowedDebrids) == 0 || slices.ContainsFunc(p.AllowedDebrids, func(allowed string) bool {
+		return strings.EqualFold(allowed, name)
+	})
+}
+
+// ProfileFor returns the profile a category uses, or nil. A profile listing
+// the category wins over one named after it.
+func (c *Config) ProfileFor(category string) *CategoryProfile {
+	if category == "" {
+		return nil
+	}
+	for i := range c.CategoryProfiles {
+		p := &c.CategoryProfiles[i]
+		if slices.ContainsFunc(p.Categories, func(cat string) bool { return strings.EqualFold(cat, category) }) {
+			return p
+		}
+	}
+	return c.ProfileNamed(category)
+}
+
+// ProfileNamed returns the category profile with the given name, or nil
+func (c *Config) ProfileNamed(name string) *CategoryProfile {
+	if name == "" {
+		return nil
+	}
+	for i := range c.CategoryProfiles {
+		if strings.EqualFold(c.CategoryProfiles[i].Name, name) {
+			return &c.CategoryProfiles[i]
+		}
+	}
+	return nil
+}
+
+// PolicyFor resolves the download policy of a category: its profile first,
+// then the Arr of the same name, then the global settings.
+func (c *Config) PolicyFor(category string) Policy {
+	policy := Policy{
+		DownloadAction:  c.DefaultDownloadAction,
+		FileSelection:   c.FileSelectionFor(category),
+		SkipMultiSeason: c.SkipMultiSeason,
+	}
+	for _, a := range c.Arrs {
+		if a.Name == category && category != "" {
+			policy.PreferredDebrid = a.SelectedDebrid
+			policy.DownloadUncached = a.DownloadUncached
+			policy.SkipRepair = a.SkipRepair
+			break
+		}
+	}
+
+	p := c.ProfileFor(category)
+	if p == nil {
+		return policy
+	}
+	policy.Profile = p.Name
+	policy.DownloadAction = cmp.Or(p.DownloadAction, policy.DownloadAction)
+	policy.PreferredDebrid = cmp.Or(p.PreferredDebrid, policy.PreferredDebrid)
+	policy.AllowedDebrids = slices.Clone(p.AllowedDebrids)
+	policy.DownloadFolder = p.DownloadFolder
+	policy.SkipRepair = policy.SkipRepair || p.SkipRepair
+	if p.DownloadUncached != nil {
+		policy.DownloadUncached = p.DownloadUncached
+	}
+	if !p.FileSelection.IsZero() {
+		policy.FileSelection = p.FileSelection.Clone()
+	}
+	if p.SkipMultiSeason != nil {
+		policy.SkipMultiSeason = *p.SkipMultiSeason
+	}
+	return policy
+}
+
+func validateCategoryProfiles(profiles []CategoryProfile, debrids []Debrid, arrs []Arr) error {
+	names := make(map[string]bool, len(profiles))
+	claimed := make(map[string]string)
+	for _, p := range profiles {
+		name := strings.ToLower(strings.TrimSpace(p.Name))
+		if name == "" {
+			return fmt.Errorf("category profile name is required")
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate category profile %q", p.Name)
+		}
+		names[name] = true
+
+		for _, category := range p.Categories {
+			key := strings.ToLower(category)
+			if other, ok := claimed[key]; ok {
+				return fmt.Errorf("category %q is in profiles %q and %q", category, other, p.Name)
+			}
+			claimed[key] = p.Name
+		}
+		if p.DownloadAction != "" && !p.DownloadAction.Valid() {
+			return fmt.Errorf("category profile %s: invalid download action %q", p.Name, p.DownloadAction)
+		}
+		if p.DownloadFolder != "" && !filepath.IsAbs(p.DownloadFolder) {
+			return fmt.Errorf("category profile %s: download folder must be an absolute path", p.Name)
+		}
+		for _, debrid := range append([]string{p.PreferredDebrid}, p.AllowedDebrids...) {
+			if debrid != "" && debrid != "usenet" && !slices.ContainsFunc(debrids, func(d Debrid) bool { return strings.EqualFold(d.Name, debrid) }) {
+				return fmt.Errorf("category profile %s: unknown debrid %q", p.Name, debrid)
+			}
+		}
+		if p.PreferredDebrid != "" && len(p.AllowedDebrids) > 0 && !(Policy{AllowedDebrids: p.AllowedDebrids}).AllowsDebrid(p.PreferredDebrid) {
+			return fmt.Errorf("category profile %s: preferred debrid %q is not allowed", p.Name, p.PreferredDebrid)
+		}
+		if err := p.FileSelection.Validate(); err != nil {
+			return fmt.Errorf("category profile %s: file selection: %w", p.Name, err)
+		}
+	}
+	// An Arr's selected debrid pins its downloads, so its profile must allow it
+	c := &Config{CategoryProfiles: profiles}
+	for _, a := range arrs {
+		p := c.ProfileFor(a.Name)
+		if p == nil || a.SelectedDebrid == "" {
+			continue
+		}
+		if !(Policy{AllowedDebrids: p.AllowedDebrids}).AllowsDebrid(a.SelectedDebrid) {
+			return fmt.Errorf("arr %s: selected debrid %q is not allowed by category profile %s", a.Name, a.SelectedDebrid, p.Name)
+		}
+	}
+	return nil
+}
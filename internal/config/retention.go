@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RetentionConfig prunes the library on a schedule. Each policy selects
+// entries by category or tag; a selected entry is deleted, placements and
+// all, as soon as any of the policy's limits applies to it.
+type RetentionConfig struct {
+	Schedule string            `json:"schedule,omitempty"` // Empty disables the scheduled run
+	DryRun   bool              `json:"dry_run,omitempty"`  // Scheduled runs only report
+	Policies []RetentionPolicy `json:"policies,omitempty"`
+}
+
+func (r RetentionConfig) IsZero() bool {
+	return r.Schedule == "" && !r.DryRun && len(r.Policies) == 0
+}
+
+// RetentionPolicy is one set of retention limits. An entry is selected when
+// it is in one of Categories or has one of Tags; a policy must set at least
+// one of the two. Durations take days and weeks, e.g. "30d" or "2w".
+type RetentionPolicy struct {
+	Name       string   `json:"name"`
+	Categories []string `json:"categories,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+
+	// Limits
+	MaxAge       string `json:"max_age,omitempty"`      // Added longer ago than this
+	Unreferenced bool   `json:"unreferenced,omitempty"` // No longer referenced by the category's Arr
+	UnplayedFor  string `json:"unplayed_for,omitempty"` // Not streamed for this long; never-streamed entries count from when they were added
+	KeepLatest   int    `json:"keep_latest,omitempty"`  // All but the N most recently added
+}
+
+// Selects reports whether the policy applies to an entry in category with tags
+func (p *RetentionPolicy) Selects(category string, tags []string) bool {
+	for _, c := range p.Categories {
+		if strings.EqualFold(c, category) {
+			return true
+		}
+	}
+	for _, want := range p.Tags {
+		for _, tag := range tags {
+			if strings.EqualFold(want, tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Durations parses MaxAge and UnplayedFor; an unset one is 0
+func (p *RetentionPolicy) Durations() (maxAge, unplayedFor time.Duration, err error) {
+	if p.MaxAge != "" {
+		if maxAge, err = ParseDuration(p.MaxAge); err != nil || maxAge <= 0 {
+			return 0, 0, fmt.Errorf("retention policy %s: invalid max_age %q", p.Name, p.MaxAge)
+		}
+	}
+	if p.UnplayedFor != "" {
+		if unplayedFor, err = ParseDuration(p.UnplayedFor); err != nil || unplayedFor <= 0 {
+			return 0, 0, fmt.Errorf("retention policy %s: invalid unplayed_for %q", p.Name, p.UnplayedFor)
+		}
+	}
+	return maxAge, unplayedFor, nil
+}
+
+func validateRetention(r RetentionConfig) error {
+	names := make(map[string]bool, len(r.Policies))
+	for _, p := range r.Policies {
+		name := strings.ToLower(strings.TrimSpace(p.Name))
+		if name == "" {
+			return fmt.Errorf("retention policy name is required")
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate retention policy %q", p.Name)
+		}
+		names[name] = true
+
+		if len(p.Categories) == 0 && len(p.Tags) == 0 {
+			return fmt.Errorf("retention policy %s: set categories or tags", p.Name)
+		}
+		if p.KeepLatest < 0 {
+			return fmt.Errorf("retention policy %s: keep_latest can't be negative", p.Name)
+		}
+		if p.MaxAge == "" && !p.Unreferenced && p.UnplayedFor == "" && p.KeepLatest == 0 {
+			return fmt.Errorf("retention policy %s: no limit", p.Name)
+		}
+		if _, _, err := p.Durations(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
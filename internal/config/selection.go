@@ -0,0 +1,239 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FileSelection decides which files of a download are fetched and shown in
+// the mount. Rules apply in order: include, exclude, extras, season/episode
+// ranges, then largest-only. Keep and Skip hold per-file choices made through
+// the qBit filePrio endpoint and override the rules; once a file is kept
+// explicitly, largest-only adds no other file. Only Real-Debrid selects files
+// on its side; other debrids fetch everything and the selection only hides
+// files in the mount.
+type FileSelection struct {
+	Include     []string `json:"include,omitempty"`      // Glob patterns; when set, a file must match one
+	Exclude     []string `json:"exclude,omitempty"`      // Glob patterns of files to drop
+	SkipExtras  bool     `json:"skip_extras,omitempty"`  // Drop samples, trailers, featurettes and other extras
+	Seasons     string   `json:"seasons,omitempty"`      // Season ranges, e.g. "1-3,5"
+	Episodes    string   `json:"episodes,omitempty"`     // Episode ranges, e.g. "1-10,12"
+	LargestOnly bool     `json:"largest_only,omitempty"` // Keep only the largest remaining file
+
+	Keep []string `json:"keep,omitempty"`
+	Skip []string `json:"skip,omitempty"`
+}
+
+// SelectionFile is a file offered to a FileSelection. Path is relative to the
+// download root; globs are matched against it and against its base name.
+// Name is the key the entry's files are stored under, which Keep and Skip
+// hold; it defaults to the base name of Path.
+type SelectionFile struct {
+	Name string
+	Path string
+	Size int64
+}
+
+var (
+	extrasRegex  = regexp.MustCompile(`(?i)(^|[\s._\-/\\(\[])(featurettes?|behind[\s._-]the[\s._-]scenes|deleted[\s._-]scenes|interviews?|bloopers|bonus|extras?|samples?|trailers?)([\s._\-/\\)\]]|$)`)
+	episodeRegex = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])s(\d{1,2})[\s._-]?e(\d{1,3})|(?:^|[^a-z0-9])(\d{1,2})x(\d{2,3})(?:[^0-9]|$)`)
+	seasonRegex  = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(?:s|season[\s._-]?)(\d{1,2})(?:[^0-9]|$)`)
+)
+
+func (s *FileSelection) IsZero() bool {
+	return s == nil || (len(s.Include) == 0 && len(s.Exclude) == 0 && !s.SkipExtras && s.Seasons == "" &&
+		s.Episodes == "" && !s.LargestOnly && len(s.Keep) == 0 && len(s.Skip) == 0)
+}
+
+// Validate checks the glob patterns and range expressions.
+func (s *FileSelection) Validate() error {
+	if s == nil {
+		return nil
+	}
+	for _, pattern := range slices.Concat(s.Include, s.Exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+	}
+	if _, err := parseRanges(s.Seasons); err != nil {
+		return fmt.Errorf("invalid seasons: %w", err)
+	}
+	if _, err := parseRanges(s.Episodes); err != nil {
+		return fmt.Errorf("invalid episodes: %w", err)
+	}
+	return nil
+}
+
+// Select returns which of files the selection keeps, by index. A nil or empty
+// selection keeps everything.
+func (s *FileSelection) Select(files []SelectionFile) []bool {
+	keep := make([]bool, len(files))
+	if s.IsZero() {
+		for i := range keep {
+			keep[i] = true
+		}
+		return keep
+	}
+	seasons, _ := parseRanges(s.Seasons)
+	episodes, _ := parseRanges(s.Episodes)
+
+	largest := -1
+	kept := false
+	for i, f := range files {
+		name := cmp.Or(f.Name, path.Base(f.Path))
+		switch {
+		case slices.Contains(s.Keep, name):
+			keep[i] = true
+			kept = true
+			continue
+		case slices.Contains(s.Skip, name):
+			continue
+		case len(s.Include) > 0 && !matchesAny(s.Include, f.Path):
+			continue
+		case matchesAny(s.Exclude, f.Path):
+			continue
+		case s.SkipExtras && (isSample(f.Path) || extrasRegex.MatchString(f.Path)):
+			continue
+		case !inEpisodeRanges(f.Path, seasons, episodes):
+			continue
+		}
+		if s.LargestOnly {
+			if largest < 0 || f.Size > files[largest].Size {
+				largest = i
+			}
+			continue
+		}
+		keep[i] = true
+	}
+	// An explicit keep stands in for the largest-only pick
+	if largest >= 0 && !kept {
+		keep[largest] = true
+	}
+	return keep
+}
+
+// Toggle records an explicit per-file choice, as made through qBit filePrio.
+func (s *FileSelection) Toggle(name string, selected bool) {
+	s.Keep = slices.DeleteFunc(s.Keep, func(n string) bool { return n == name })
+	s.Skip = slices.DeleteFunc(s.Skip, func(n string) bool { return n == name })
+	if selected {
+		s.Keep = append(s.Keep, name)
+	} else {
+		s.Skip = append(s.Skip, name)
+	}
+}
+
+// Clone returns a deep copy, so an entry's selection can diverge from the
+// category rules it started from.
+func (s *FileSelection) Clone() *FileSelection {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.Include = slices.Clone(s.Include)
+	c.Exclude = slices.Clone(s.Exclude)
+	c.Keep = slices.Clone(s.Keep)
+	c.Skip = slices.Clone(s.Skip)
+	return &c
+}
+
+// FileSelectionFor returns the file-selection rules of a category, or nil.
+func (c *Config) FileSelectionFor(category string) *FileSelection {
+	sel, ok := c.FileSelection[category]
+	if !ok || sel.IsZero() {
+		return nil
+	}
+	return sel.Clone()
+}
+
+func validateFileSelection(rules map[string]FileSelection) error {
+	for category, sel := range rules {
+		if err := sel.Validate(); err != nil {
+			return fmt.Errorf("file selection for %s: %w", category, err)
+		}
+	}
+	return nil
+}
+
+func matchesAny(patterns []string, filePath string) bool {
+	lowerPath := strings.ToLower(filePath)
+	lowerName := path.Base(lowerPath)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if ok, _ := path.Match(pattern, lowerName); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, lowerPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// inEpisodeRanges reports whether a file falls inside the season and episode
+// ranges. Files that carry no season or episode number are dropped once a
+// range is set; in a season pack those are extras.
+func inEpisodeRanges(filePath string, seasons, episodes [][2]int) bool {
+	if len(seasons) == 0 && len(episodes) == 0 {
+		return true
+	}
+	season, episode := -1, -1
+	if m := episodeRegex.FindStringSubmatch(path.Base(filePath)); m != nil {
+		season, _ = strconv.Atoi(m[1] + m[3])
+		episode, _ = strconv.Atoi(m[2] + m[4])
+	} else if m := seasonRegex.FindStringSubmatch(filePath); m != nil {
+		season, _ = strconv.Atoi(m[1])
+	}
+	if len(seasons) > 0 && !inRanges(season, seasons) {
+		return false
+	}
+	if len(episodes) > 0 && !inRanges(episode, episodes) {
+		return false
+	}
+	return true
+}
+
+func inRanges(n int, ranges [][2]int) bool {
+	if n < 0 {
+		return false
+	}
+	for _, r := range ranges {
+		if n >= r[0] && n <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRanges parses "1-3,5,8-" into inclusive ranges. An open end means no
+// upper bound.
+func parseRanges(expr string) ([][2]int, error) {
+	var ranges [][2]int
+	for part := range strings.SplitSeq(expr, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil {
+			return nil, fmt.Errorf("bad range %q", part)
+		}
+		end := start
+		if isRange {
+			hi = strings.TrimSpace(hi)
+			if hi == "" {
+				end = int(^uint(0) >> 1)
+			} else if end, err = strconv.Atoi(hi); err != nil || end < start {
+				return nil, fmt.Errorf("bad range %q", part)
+			}
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges, nil
+}
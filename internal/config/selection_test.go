@@ -0,0 +1,143 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseRanges(t *testing.T) {
+	maxInt := int(^uint(0) >> 1)
+	tests := []struct {
+		expr    string
+		want    [][2]int
+		wantErr bool
+	}{
+		{expr: "", want: nil},
+		{expr: "5", want: [][2]int{{5, 5}}},
+		{expr: "1-3,5", want: [][2]int{{1, 3}, {5, 5}}},
+		{expr: " 2 - 4 , 7 ", want: [][2]int{{2, 4}, {7, 7}}},
+		{expr: "8-", want: [][2]int{{8, maxInt}}},
+		{expr: "1,,2", want: [][2]int{{1, 1}, {2, 2}}},
+		{expr: "3-1", wantErr: true},
+		{expr: "a", wantErr: true},
+		{expr: "-4", wantErr: true},
+		{expr: "1-b", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := parseRanges(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRanges(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+			if !tt.wantErr && !slices.Equal(got, tt.want) {
+				t.Errorf("parseRanges(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInEpisodeRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		seasons  string
+		episodes string
+		want     bool
+	}{
+		{name: "no ranges", path: "Movie.2019.mkv", want: true},
+		{name: "SxxEyy in season", path: "Show.S02E05.1080p.mkv", seasons: "1-2", want: true},
+		{name: "SxxEyy outside season", path: "Show.S03E05.1080p.mkv", seasons: "1-2", want: false},
+		{name: "SxxEyy episode", path: "Show.S01E12.mkv", episodes: "1-10,12", want: true},
+		{name: "SxxEyy episode outside", path: "Show.S01E11.mkv", episodes: "1-10,12", want: false},
+		{name: "1x02 naming", path: "Show.1x02.Title.mkv", seasons: "1", episodes: "2", want: true},
+		{name: "1x02 naming outside", path: "Show.1x03.Title.mkv", episodes: "2", want: false},
+		{name: "open range", path: "Show.S01E40.mkv", episodes: "8-", want: true},
+		{name: "open range below", path: "Show.S01E07.mkv", episodes: "8-", want: false},
+		{name: "season folder", path: "Season 2/Show.Title.mkv", seasons: "2", want: true},
+		{name: "season folder other season", path: "Season 3/Show.Title.mkv", seasons: "2", want: false},
+		{name: "season folder without episode", path: "Season 2/Show.Title.mkv", seasons: "2", episodes: "1", want: false},
+		{name: "no number with a range set", path: "Extras/Interview.mkv", seasons: "1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seasons, err := parseRanges(tt.seasons)
+			if err != nil {
+				t.Fatal(err)
+			}
+			episodes, err := parseRanges(tt.episodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := inEpisodeRanges(tt.path, seasons, episodes); got != tt.want {
+				t.Errorf("inEpisodeRanges(%q, %q, %q) = %v, want %v", tt.path, tt.seasons, tt.episodes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileSelectionSelect(t *testing.T) {
+	pack := []SelectionFile{
+		{Path: "Show.S01E01.mkv", Size: 100},
+		{Path: "Show.S01E02.mkv", Size: 300},
+		{Path: "Sample/Show.S01E01.sample.mkv", Size: 10},
+		{Path: "Show.S02E01.mkv", Size: 200},
+	}
+	tests := []struct {
+		name  string
+		sel   *FileSelection
+		files []SelectionFile
+		want  []bool
+	}{
+		{
+			name:  "nil keeps everything",
+			files: pack,
+			want:  []bool{true, true, true, true},
+		},
+		{
+			name:  "include and exclude",
+			sel:   &FileSelection{Include: []string{"*.mkv"}, Exclude: []string{"*S02*"}},
+			files: pack,
+			want:  []bool{true, true, true, false},
+		},
+		{
+			name:  "skip extras and season",
+			sel:   &FileSelection{SkipExtras: true, Seasons: "1"},
+			files: pack,
+			want:  []bool{true, true, false, false},
+		},
+		{
+			name:  "largest only",
+			sel:   &FileSelection{LargestOnly: true},
+			files: pack,
+			want:  []bool{false, true, false, false},
+		},
+		{
+			name:  "largest only with an explicit keep",
+			sel:   &FileSelection{LargestOnly: true, Keep: []string{"Show.S01E01.mkv"}},
+			files: pack,
+			want:  []bool{true, false, false, false},
+		},
+		{
+			name:  "explicit skip beats the rules",
+			sel:   &FileSelection{Include: []string{"*.mkv"}, Skip: []string{"Show.S01E02.mkv"}},
+			files: pack,
+			want:  []bool{true, false, true, true},
+		},
+		{
+			name: "keep and skip match the stored name, not the base name",
+			sel:  &FileSelection{Exclude: []string{"*"}, Keep: []string{"CD2/movie.avi"}},
+			files: []SelectionFile{
+				{Name: "CD1/movie.avi", Path: "CD1/movie.avi", Size: 1},
+				{Name: "CD2/movie.avi", Path: "CD2/movie.avi", Size: 1},
+			},
+			want: []bool{false, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sel.Select(tt.files); !slices.Equal(got, tt.want) {
+				t.Errorf("Select() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// TagRuleEvent is a point in an entry's life where tag rules run
+type TagRuleEvent string
+
+const (
+	TagRuleOnAdd      TagRuleEvent = "add"      // The entry enters the download queue
+	TagRuleOnComplete TagRuleEvent = "complete" // The download finished, before the post-download action
+)
+
+// TagRule tags and routes entries that match its conditions. Every condition
+// that is set must match; list conditions match when any value does, without
+// regard to case. Rules run in order, so a later rule sees the tags an
+// earlier one added.
+type TagRule struct {
+	Name string         `json:"name"`
+	On   []TagRuleEvent `json:"on,omitempty"` // Events the rule runs on; empty runs on both
+
+	// Conditions
+	NameRegex   string   `json:"name_regex,omitempty"`  // Matched against the entry name
+	Resolutions []string `json:"resolutions,omitempty"` // Parsed from the name, e.g. 2160p, 1080p
+	Codecs      []string `json:"codecs,omitempty"`      // Parsed from the name: h264, h265, av1, vp9, xvid, mpeg2, vc1
+	MinSize     string   `json:"min_size,omitempty"`
+	MaxSize     string   `json:"max_size,omitempty"`
+	Providers   []string `json:"providers,omitempty"`  // Active debrid, or "usenet"
+	Categories  []string `json:"categories,omitempty"` // Entry categories
+	Arrs        []string `json:"arrs,omitempty"`       // Name or type (sonarr, radarr...) of the category's Arr
+	Tags        []string `json:"tags,omitempty"`       // Tags the entry already has
+	MinFiles    int      `json:"min_files,omitempty"`
+	MaxFiles    int      `json:"max_files,omitempty"`
+
+	// Actions
+	AddTags    []string `json:"add_tags,omitempty"`
+	RemoveTags []string `json:"remove_tags,omitempty"`
+	Profile    string   `json:"profile,omitempty"` // Category profile whose policy the entry uses
+	Folder     string   `json:"folder,omitempty"`  // Custom folder the entry is shown in
+}
+
+// RunsOn reports whether the rule runs on event
+func (r *TagRule) RunsOn(event TagRuleEvent) bool {
+	return len(r.On) == 0 || slices.Contains(r.On, event)
+}
+
+// ValidateTagRules checks tag rules against the category profiles and custom
+// folders they may refer to
+func ValidateTagRules(rules []TagRule, profiles []CategoryProfile, folders map[string]CustomFolders) error {
+	names := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		name := strings.ToLower(strings.TrimSpace(r.Name))
+		if name == "" {
+			return fmt.Errorf("tag rule name is required")
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate tag rule %q", r.Name)
+		}
+		names[name] = true
+
+		for _, event := range r.On {
+			if event != TagRuleOnAdd && event != TagRuleOnComplete {
+				return fmt.Errorf("tag rule %s: unknown event %q", r.Name, event)
+			}
+		}
+		if r.NameRegex != "" {
+			if _, err := regexp.Compile(r.NameRegex); err != nil {
+				return fmt.Errorf("tag rule %s: invalid name_regex: %w", r.Name, err)
+			}
+		}
+		for _, size := range []string{r.MinSize, r.MaxSize} {
+			if size == "" {
+				continue
+			}
+			if _, err := ParseSize(size); err != nil {
+				return fmt.Errorf("tag rule %s: invalid size %q", r.Name, size)
+			}
+		}
+		if r.MinFiles < 0 || r.MaxFiles < 0 {
+			return fmt.Errorf("tag rule %s: file counts can't be negative", r.Name)
+		}
+		if len(r.AddTags) == 0 && len(r.RemoveTags) == 0 && r.Profile == "" && r.Folder == "" {
+			return fmt.Errorf("tag rule %s: no action", r.Name)
+		}
+		if r.Profile != "" && !slices.ContainsFunc(profiles, func(p CategoryProfile) bool { return strings.EqualFold(p.Name, r.Profile) }) {
+			return fmt.Errorf("tag rule %s: unknown category profile %q", r.Name, r.Profile)
+		}
+		if r.Folder != "" {
+			if _, ok := folders[r.Folder]; !ok {
+				return fmt.Errorf("tag rule %s: unknown custom folder %q", r.Name, r.Folder)
+			}
+		}
+	}
+	return nil
+}
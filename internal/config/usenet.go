@@ -56,6 +56,12 @@ type Usenet struct {
 	// smooth playback; this bounds the aggregate so many concurrent streams
 	// can't OOM. Empty = default (512MB); "0" disables the cap.
 	BufferMemory string `json:"buffer_memory,omitempty"`
+
+	// PreferDebrid sends NZBs to the debrid usenet services (debrids with
+	// usenet enabled) before the NNTP providers. By default the debrids are
+	// only a fallback for NZBs the NNTP providers cannot import, or the only
+	// path when no NNTP provider is configured.
+	PreferDebrid bool `json:"prefer_debrid,omitempty"`
 }
 
 // BufferMemoryBytes resolves the usenet streaming-buffer RAM cap. Empty ->
@@ -72,7 +78,7 @@ func (u Usenet) BufferMemoryBytes() int64 {
 }
 
 func (u Usenet) IsZero() bool {
-	return len(u.Providers) == 0 && u.MaxConnections == 0 && u.ProcessingMaxConnections == 0 && u.ReadAhead == "" && u.ProcessingTimeout == ""
+	return len(u.Providers) == 0 && u.MaxConnections == 0 && u.ProcessingMaxConnections == 0 && u.ReadAhead == "" && u.ProcessingTimeout == "" && !u.PreferDebrid
 }
 
 func (c *Config) updateUsenetConfig() {
@@ -192,6 +198,10 @@ func (c *Config) applyUsenetEnvVars() {
 		c.Usenet.ProcessingTimeout = processingTimeout
 	}
 
+	if preferDebrid := getEnv("USENET__PREFER_DEBRID"); preferDebrid != "" {
+		c.Usenet.PreferDebrid = parseBool(preferDebrid)
+	}
+
 	if availabilitySample := getEnv("USENET__AVAILABILITY_SAMPLE_PERCENT"); availabilitySample != "" {
 		if v, err := strconv.Atoi(availabilitySample); err == nil {
 			c.Usenet.AvailabilitySamplePercent = v
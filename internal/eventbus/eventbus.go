@@ -0,0 +1,200 @@
+// Package eventbus is an in-process publish/subscribe bus for live updates.
+// Every event gets an increasing ID and the latest events of each topic are
+// kept, so a subscriber that reconnects can resume after the last ID it saw.
+package eventbus
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Topics published by the manager
+const (
+	TopicQueue   = "queue"
+	TopicStreams = "streams"
+	TopicRepair  = "repair"
+	TopicLogs    = "logs"
+)
+
+const (
+	defaultHistorySize = 500
+	subscriberBuffer   = 256
+)
+
+type Event struct {
+	ID    uint64    `json:"id"`
+	Topic string    `json:"topic"`
+	Type  string    `json:"type"`
+	Time  time.Time `json:"time"`
+	Data  any       `json:"data,omitempty"`
+}
+
+// Subscription receives the events of its topics. Its channel is closed when
+// it is unsubscribed or falls too far behind; the subscriber then resumes with
+// a new subscription from the last ID it received.
+type Subscription struct {
+	topics map[string]struct{} // nil for all topics
+	ch     chan Event
+}
+
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+func (s *Subscription) wants(topic string) bool {
+	if s.topics == nil {
+		return true
+	}
+	_, ok := s.topics[topic]
+	return ok
+}
+
+// ring keeps the latest events of a topic
+type ring struct {
+	buf     []Event
+	start   int
+	n       int
+	evicted uint64 // ID of the last event pushed out
+}
+
+func (r *ring) push(ev Event) {
+	if r.n < len(r.buf) {
+		r.buf[(r.start+r.n)%len(r.buf)] = ev
+		r.n++
+		return
+	}
+	r.evicted = r.buf[r.start].ID
+	r.buf[r.start] = ev
+	r.start = (r.start + 1) % len(r.buf)
+}
+
+func (r *ring) after(id uint64, out []Event) []Event {
+	for i := range r.n {
+		if ev := r.buf[(r.start+i)%len(r.buf)]; ev.ID > id {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+type Bus struct {
+	mu          sync.Mutex
+	lastID      uint64
+	historySize int
+	history     map[string]*ring
+	subs        map[*Subscription]struct{}
+}
+
+// New creates a bus keeping the latest historySize events of each topic
+func New(historySize int) *Bus {
+	if historySize <= 0 {
+		historySize = defaultHistorySize
+	}
+	return &Bus{
+		historySize: historySize,
+		history:     make(map[string]*ring),
+		subs:        make(map[*Subscription]struct{}),
+	}
+}
+
+var (
+	defaultOnce sync.Once
+	defaultBus  *Bus
+)
+
+// Default returns the process-wide bus
+func Default() *Bus {
+	defaultOnce.Do(func() {
+		defaultBus = New(defaultHistorySize)
+	})
+	return defaultBus
+}
+
+// Publish sends an event to the subscribers of topic. Subscribers whose
+// buffer is full are dropped rather than slowing the publisher down.
+func (b *Bus) Publish(topic, typ string, data any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	ev := Event{ID: b.lastID, Topic: topic, Type: typ, Time: time.Now(), Data: data}
+
+	r, ok := b.history[topic]
+	if !ok {
+		r = &ring{buf: make([]Event, b.historySize)}
+		b.history[topic] = r
+	}
+	r.push(ev)
+
+	for sub := range b.subs {
+		if !sub.wants(topic) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			b.removeLocked(sub)
+		}
+	}
+}
+
+// HasSubscribers reports whether anyone listens to topic, so expensive
+// payloads can be skipped when nobody does
+func (b *Bus) HasSubscribers(topic string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		if sub.wants(topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribe listens to topics, or to every topic when none are given. With a
+// lastID above zero the retained events after it are returned for replay,
+// oldest first; they are not sent on the channel. complete is false when
+// events after lastID are no longer retained, or lastID was handed out before
+// a restart, so the subscriber should reload its state instead.
+func (b *Bus) Subscribe(topics []string, lastID uint64) (sub *Subscription, replay []Event, complete bool) {
+	sub = &Subscription{ch: make(chan Event, subscriberBuffer)}
+	if len(topics) > 0 {
+		sub.topics = make(map[string]struct{}, len(topics))
+		for _, t := range topics {
+			sub.topics[t] = struct{}{}
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	complete = true
+	if lastID > 0 {
+		complete = lastID <= b.lastID
+		for topic, r := range b.history {
+			if sub.wants(topic) {
+				replay = r.after(lastID, replay)
+				complete = complete && r.evicted <= lastID
+			}
+		}
+		slices.SortFunc(replay, func(a, b Event) int { return cmp.Compare(a.ID, b.ID) })
+	}
+	b.subs[sub] = struct{}{}
+	return sub, replay, complete
+}
+
+func (b *Bus) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removeLocked(sub)
+}
+
+func (b *Bus) removeLocked(sub *Subscription) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	close(sub.ch)
+}
@@ -0,0 +1,89 @@
+package eventbus
+
+import "testing"
+
+func TestSubscribeFiltersTopics(t *testing.T) {
+	b := New(10)
+	sub, _, _ := b.Subscribe([]string{TopicQueue}, 0)
+	defer b.Unsubscribe(sub)
+
+	b.Publish(TopicRepair, "run", nil)
+	b.Publish(TopicQueue, "updated", "a")
+
+	select {
+	case ev := <-sub.Events():
+		if ev.Topic != TopicQueue || ev.Data != "a" {
+			t.Fatalf("got %+v, want the queue event", ev)
+		}
+	default:
+		t.Fatal("expected a queue event")
+	}
+	select {
+	case ev := <-sub.Events():
+		t.Fatalf("unexpected event %+v", ev)
+	default:
+	}
+}
+
+func TestReplayAfterLastID(t *testing.T) {
+	b := New(3)
+	for range 5 {
+		b.Publish(TopicQueue, "updated", nil)
+	}
+	b.Publish(TopicLogs, "line", nil)
+
+	// IDs 3-5 are retained for the queue topic; the log line is filtered out
+	sub, replay, complete := b.Subscribe([]string{TopicQueue}, 3)
+	defer b.Unsubscribe(sub)
+	if len(replay) != 2 || replay[0].ID != 4 || replay[1].ID != 5 || !complete {
+		t.Fatalf("replay = %+v (complete %v), want IDs 4 and 5", replay, complete)
+	}
+
+	// IDs 1 and 2 were pushed out, so resuming after 1 misses ID 2
+	all, replay, complete := b.Subscribe(nil, 1)
+	defer b.Unsubscribe(all)
+	if complete {
+		t.Fatal("replay after an evicted event should be incomplete")
+	}
+	want := []uint64{3, 4, 5, 6}
+	if len(replay) != len(want) {
+		t.Fatalf("replay has %d events, want %d", len(replay), len(want))
+	}
+	for i, ev := range replay {
+		if ev.ID != want[i] {
+			t.Fatalf("replay[%d].ID = %d, want %d", i, ev.ID, want[i])
+		}
+	}
+}
+
+func TestReplayAfterRestart(t *testing.T) {
+	b := New(3)
+	b.Publish(TopicQueue, "updated", nil)
+
+	// An ID from before a restart is ahead of the bus
+	sub, replay, complete := b.Subscribe(nil, 42)
+	defer b.Unsubscribe(sub)
+	if len(replay) != 0 || complete {
+		t.Fatalf("replay = %+v (complete %v), want an incomplete empty replay", replay, complete)
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	b := New(10)
+	sub, _, _ := b.Subscribe(nil, 0)
+	for range subscriberBuffer + 1 {
+		b.Publish(TopicQueue, "updated", nil)
+	}
+	if b.HasSubscribers(TopicQueue) {
+		t.Fatal("slow subscriber should have been dropped")
+	}
+	n := 0
+	for range sub.Events() {
+		n++
+	}
+	if n != subscriberBuffer {
+		t.Fatalf("drained %d events, want %d", n, subscriberBuffer)
+	}
+	// Unsubscribing a dropped subscription is a no-op
+	b.Unsubscribe(sub)
+}
@@ -0,0 +1,28 @@
+package logger
+
+import (
+	json "github.com/bytedance/sonic"
+
+	"github.com/sirrobot01/decypharr/internal/eventbus"
+)
+
+// busWriter publishes log lines to the logs topic of the event bus. Lines are
+// only decoded while someone is subscribed.
+type busWriter struct {
+	component string
+}
+
+func (w busWriter) Write(p []byte) (int, error) {
+	bus := eventbus.Default()
+	if !bus.HasSubscribers(eventbus.TopicLogs) {
+		return len(p), nil
+	}
+	var line map[string]any
+	if err := json.Unmarshal(p, &line); err != nil {
+		return len(p), nil
+	}
+	line["component"] = w.component
+	level, _ := line["level"].(string)
+	bus.Publish(eventbus.TopicLogs, level, line)
+	return len(p), nil
+}
@@ -94,7 +94,7 @@ func New(prefix string) zerolog.Logger {
 		},
 	}
 
-	multi := zerolog.MultiLevelWriter(consoleWriter, fileWriter)
+	multi := zerolog.MultiLevelWriter(consoleWriter, fileWriter, busWriter{component: prefix})
 
 	logger := zerolog.New(multi).
 		With().
@@ -0,0 +1,466 @@
+package mediaprobe
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// EBML element IDs used by the probe, with their marker bits kept.
+const (
+	mkvEBML           = 0x1A45DFA3
+	mkvDocType        = 0x4282
+	mkvSegment        = 0x18538067
+	mkvSeekHead       = 0x114D9B74
+	mkvSeek           = 0x4DBB
+	mkvSeekID         = 0x53AB
+	mkvSeekPosition   = 0x53AC
+	mkvInfo           = 0x1549A966
+	mkvTimecodeScale  = 0x2AD7B1
+	mkvDuration       = 0x4489
+	mkvTracks         = 0x1654AE6B
+	mkvTrackEntry     = 0xAE
+	mkvTrackType      = 0x83
+	mkvCodecID        = 0x86
+	mkvLanguage       = 0x22B59C
+	mkvLanguageIETF   = 0x22B59D
+	mkvVideo          = 0xE0
+	mkvPixelWidth     = 0xB0
+	mkvPixelHeight    = 0xBA
+	mkvCluster        = 0x1F43B675
+	mkvCues           = 0x1C53BB6B
+	mkvUnknownSize    = -1
+	mkvMaxTopLevel    = 256
+	mkvTrackVideo     = 1
+	mkvTrackAudio     = 2
+	mkvTrackSubtitle  = 17
+	mkvDefaultTCScale = 1_000_000
+)
+
+type ebmlHeader struct {
+	id       uint32
+	size     int64 // mkvUnknownSize when the element is open-ended
+	headerSz int64
+}
+
+func probeMatroska(r io.ReaderAt, size int64) (*Info, error) {
+	info := &Info{Container: "matroska"}
+
+	hdr, err := readEBMLHeader(r, 0, size)
+	if err != nil {
+		return info, err
+	}
+	if hdr.id != mkvEBML || hdr.size == mkvUnknownSize || hdr.size > 4096 {
+		return info, unplayable("missing EBML header")
+	}
+	head := make([]byte, hdr.size)
+	if err := readFull(r, head, hdr.headerSz, size); err != nil {
+		return info, err
+	}
+	_ = walkEBML(head, func(id uint32, payload []byte) error {
+		if id == mkvDocType && strings.TrimRight(string(payload), "\x00") == "webm" {
+			info.Container = "webm"
+		}
+		return nil
+	})
+
+	segOff := hdr.headerSz + hdr.size
+	seg, err := readEBMLHeader(r, segOff, size)
+	if err != nil {
+		return info, err
+	}
+	if seg.id != mkvSegment {
+		return info, unplayable("missing Segment element")
+	}
+	segStart := segOff + seg.headerSz
+	segEnd := size
+	var truncErr error
+	if seg.size != mkvUnknownSize {
+		segEnd = segStart + seg.size
+		if segEnd > size {
+			truncErr = truncated("segment declares %d bytes, file ends %d bytes early", seg.size, segEnd-size)
+			segEnd = size
+		}
+	}
+
+	var (
+		seeks       = make(map[uint32]int64)
+		haveInfo    bool
+		haveTracks  bool
+		sawCluster  bool
+		reachedEOF  bool
+		timecodeScl = uint64(mkvDefaultTCScale)
+		rawDuration float64
+	)
+	parseInfo := func(data []byte) {
+		haveInfo = true
+		_ = walkEBML(data, func(id uint32, payload []byte) error {
+			switch id {
+			case mkvTimecodeScale:
+				if v := ebmlUint(payload); v > 0 {
+					timecodeScl = v
+				}
+			case mkvDuration:
+				rawDuration = ebmlFloat(payload)
+			}
+			return nil
+		})
+	}
+
+	pos := segStart
+	for i := 0; i < mkvMaxTopLevel; i++ {
+		if pos >= segEnd {
+			reachedEOF = pos >= size
+			break
+		}
+		el, err := readEBMLHeader(r, pos, size)
+		if err != nil {
+			if _, ok := err.(*structureError); ok {
+				reachedEOF = true
+				if truncErr == nil {
+					truncErr = err
+				}
+				break
+			}
+			return info, err
+		}
+		if el.id == mkvCluster {
+			sawCluster = true
+			break
+		}
+		if el.size == mkvUnknownSize {
+			break
+		}
+		dataStart := pos + el.headerSz
+		switch el.id {
+		case mkvSeekHead, mkvInfo, mkvTracks:
+			data, err := readElementData(r, dataStart, el.size, size)
+			if err != nil {
+				return info, err
+			}
+			switch el.id {
+			case mkvSeekHead:
+				parseSeekHead(data, segStart, seeks)
+			case mkvInfo:
+				parseInfo(data)
+			case mkvTracks:
+				haveTracks = true
+				parseTracks(data, info)
+			}
+		}
+		pos = dataStart + el.size
+	}
+
+	// Files muxed with the index at the end only reach Info/Tracks via SeekHead.
+	if !haveInfo {
+		if off, ok := seeks[mkvInfo]; ok {
+			if data, err := readSeekTarget(r, off, mkvInfo, size); err == nil {
+				parseInfo(data)
+			} else if _, ok := err.(*structureError); !ok {
+				return info, err
+			}
+		}
+	}
+	if !haveTracks {
+		if off, ok := seeks[mkvTracks]; ok {
+			data, err := readSeekTarget(r, off, mkvTracks, size)
+			if err != nil {
+				return info, err
+			}
+			haveTracks = true
+			parseTracks(data, info)
+		}
+	}
+	if rawDuration > 0 {
+		info.Duration = time.Duration(rawDuration * float64(timecodeScl))
+	}
+
+	if truncErr != nil {
+		return info, truncErr
+	}
+	if !haveTracks {
+		return info, unplayable("no Tracks element")
+	}
+	// Cues are written last; a seek entry pointing past the end is the
+	// clearest sign of a cut-off download.
+	if off, ok := seeks[mkvCues]; ok {
+		if off >= size {
+			return info, truncated("cues at %d beyond end of file", off)
+		}
+		cues, err := readEBMLHeader(r, off, size)
+		if err != nil {
+			return info, err
+		}
+		if cues.id != mkvCues {
+			return info, truncated("cues missing at indexed position %d", off)
+		}
+		if cues.size != mkvUnknownSize && off+cues.headerSz+cues.size > size {
+			return info, truncated("cues cut short at end of file")
+		}
+	}
+	if !sawCluster && reachedEOF {
+		return info, truncated("no media clusters")
+	}
+	return info, nil
+}
+
+func parseSeekHead(data []byte, segStart int64, seeks map[uint32]int64) {
+	_ = walkEBML(data, func(id uint32, payload []byte) error {
+		if id != mkvSeek {
+			return nil
+		}
+		var (
+			target  uint32
+			pos     uint64
+			havePos bool
+		)
+		_ = walkEBML(payload, func(id uint32, v []byte) error {
+			switch id {
+			case mkvSeekID:
+				target = uint32(ebmlUint(v))
+			case mkvSeekPosition:
+				pos = ebmlUint(v)
+				havePos = true
+			}
+			return nil
+		})
+		if target != 0 && havePos {
+			if _, exists := seeks[target]; !exists {
+				seeks[target] = segStart + int64(pos)
+			}
+		}
+		return nil
+	})
+}
+
+func parseTracks(data []byte, info *Info) {
+	_ = walkEBML(data, func(id uint32, entry []byte) error {
+		if id != mkvTrackEntry {
+			return nil
+		}
+		var (
+			trackType     uint64
+			codec         string
+			lang          = "eng" // Matroska default when Language is absent
+			langIETF      string
+			width, height int
+		)
+		_ = walkEBML(entry, func(id uint32, v []byte) error {
+			switch id {
+			case mkvTrackType:
+				trackType = ebmlUint(v)
+			case mkvCodecID:
+				codec = ebmlString(v)
+			case mkvLanguage:
+				lang = ebmlString(v)
+			case mkvLanguageIETF:
+				langIETF = ebmlString(v)
+			case mkvVideo:
+				_ = walkEBML(v, func(id uint32, v []byte) error {
+					switch id {
+					case mkvPixelWidth:
+						width = int(ebmlUint(v))
+					case mkvPixelHeight:
+						height = int(ebmlUint(v))
+					}
+					return nil
+				})
+			}
+			return nil
+		})
+		if langIETF != "" {
+			lang = langIETF
+		}
+		if lang == "und" {
+			lang = ""
+		}
+		switch trackType {
+		case mkvTrackVideo:
+			if info.VideoCodec == "" {
+				info.VideoCodec = matroskaCodecName(codec)
+				info.Width, info.Height = width, height
+			}
+		case mkvTrackAudio:
+			info.AudioCodecs = appendUnique(info.AudioCodecs, matroskaCodecName(codec))
+			info.AudioLanguages = appendUnique(info.AudioLanguages, lang)
+		case mkvTrackSubtitle:
+			info.SubtitleLanguages = appendUnique(info.SubtitleLanguages, lang)
+		}
+		return nil
+	})
+}
+
+func readSeekTarget(r io.ReaderAt, off int64, want uint32, size int64) ([]byte, error) {
+	el, err := readEBMLHeader(r, off, size)
+	if err != nil {
+		return nil, err
+	}
+	if el.id != want || el.size == mkvUnknownSize {
+		return nil, unplayable("seek entry does not point at element %x", want)
+	}
+	return readElementData(r, off+el.headerSz, el.size, size)
+}
+
+func readElementData(r io.ReaderAt, off, length, size int64) ([]byte, error) {
+	if length > maxIndexSize {
+		return nil, unplayable("element of %d bytes at %d is implausibly large", length, off)
+	}
+	data := make([]byte, length)
+	if err := readFull(r, data, off, size); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readEBMLHeader decodes the ID and size vints of the element at off.
+func readEBMLHeader(r io.ReaderAt, off, size int64) (ebmlHeader, error) {
+	buf := make([]byte, min(12, size-off))
+	if len(buf) < 2 {
+		return ebmlHeader{}, truncated("element header at %d past end of file", off)
+	}
+	if err := readFull(r, buf, off, size); err != nil {
+		return ebmlHeader{}, err
+	}
+	id, idLen, ok := ebmlID(buf)
+	if !ok {
+		return ebmlHeader{}, unplayable("invalid element ID at %d", off)
+	}
+	sz, szLen, ok := ebmlSize(buf[idLen:])
+	if !ok {
+		return ebmlHeader{}, unplayable("invalid element size at %d", off)
+	}
+	return ebmlHeader{id: id, size: sz, headerSz: int64(idLen + szLen)}, nil
+}
+
+// walkEBML calls fn for each element directly inside data.
+func walkEBML(data []byte, fn func(id uint32, payload []byte) error) error {
+	for len(data) > 0 {
+		id, idLen, ok := ebmlID(data)
+		if !ok {
+			return unplayable("invalid element ID")
+		}
+		sz, szLen, ok := ebmlSize(data[idLen:])
+		if !ok || sz == mkvUnknownSize {
+			return unplayable("invalid element size")
+		}
+		start := idLen + szLen
+		if int64(len(data)-start) < sz {
+			return truncated("element overruns its parent")
+		}
+		if err := fn(id, data[start:start+int(sz)]); err != nil {
+			return err
+		}
+		data = data[start+int(sz):]
+	}
+	return nil
+}
+
+func ebmlID(b []byte) (uint32, int, bool) {
+	if len(b) == 0 || b[0] == 0 {
+		return 0, 0, false
+	}
+	n := vintLength(b[0])
+	if n > 4 || n > len(b) {
+		return 0, 0, false
+	}
+	var id uint32
+	for i := range n {
+		id = id<<8 | uint32(b[i])
+	}
+	return id, n, true
+}
+
+func ebmlSize(b []byte) (int64, int, bool) {
+	if len(b) == 0 || b[0] == 0 {
+		return 0, 0, false
+	}
+	n := vintLength(b[0])
+	if n > len(b) {
+		return 0, 0, false
+	}
+	v := uint64(b[0]) & (0xFF >> n)
+	allOnes := v == uint64(0xFF>>n)
+	for i := 1; i < n; i++ {
+		v = v<<8 | uint64(b[i])
+		allOnes = allOnes && b[i] == 0xFF
+	}
+	if allOnes {
+		return mkvUnknownSize, n, true
+	}
+	if v > math.MaxInt64 {
+		return 0, 0, false
+	}
+	return int64(v), n, true
+}
+
+func vintLength(first byte) int {
+	n := 1
+	for mask := byte(0x80); mask != 0 && first&mask == 0; mask >>= 1 {
+		n++
+	}
+	return n
+}
+
+func ebmlUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+func ebmlFloat(b []byte) float64 {
+	switch len(b) {
+	case 4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b)))
+	case 8:
+		return math.Float64frombits(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+func ebmlString(b []byte) string {
+	return strings.TrimRight(string(b), "\x00")
+}
+
+func matroskaCodecName(id string) string {
+	switch {
+	case strings.HasPrefix(id, "V_MPEG4/ISO/AVC"):
+		return "h264"
+	case strings.HasPrefix(id, "V_MPEGH/ISO/HEVC"):
+		return "hevc"
+	case id == "V_AV1":
+		return "av1"
+	case id == "V_VP9":
+		return "vp9"
+	case id == "V_VP8":
+		return "vp8"
+	case strings.HasPrefix(id, "V_MPEG4/ISO/"):
+		return "mpeg4"
+	case id == "V_MPEG2":
+		return "mpeg2"
+	case strings.HasPrefix(id, "A_AAC"):
+		return "aac"
+	case id == "A_AC3":
+		return "ac3"
+	case id == "A_EAC3":
+		return "eac3"
+	case strings.HasPrefix(id, "A_DTS"):
+		return "dts"
+	case id == "A_TRUEHD":
+		return "truehd"
+	case id == "A_OPUS":
+		return "opus"
+	case id == "A_FLAC":
+		return "flac"
+	case id == "A_VORBIS":
+		return "vorbis"
+	case strings.HasPrefix(id, "A_MPEG/L3"):
+		return "mp3"
+	case strings.HasPrefix(id, "A_PCM"):
+		return "pcm"
+	}
+	return strings.ToLower(id)
+}
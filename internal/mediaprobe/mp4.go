@@ -0,0 +1,331 @@
+package mediaprobe
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+	"time"
+)
+
+const mp4MaxTopLevel = 1024
+
+type mp4Box struct {
+	typ       string
+	size      int64 // including header
+	headerLen int64
+}
+
+func probeMP4(r io.ReaderAt, size int64) (*Info, error) {
+	info := &Info{Container: "mp4"}
+
+	var (
+		moovOff, moovSize int64 = -1, 0
+		sawMdat           bool
+		fragmented        bool
+	)
+	pos := int64(0)
+	for i := 0; i < mp4MaxTopLevel && pos < size; i++ {
+		box, err := readMP4BoxHeader(r, pos, size)
+		if err != nil {
+			return info, err
+		}
+		if i == 0 && box.typ != "ftyp" && box.typ != "moov" && box.typ != "free" && box.typ != "wide" && box.typ != "mdat" && box.typ != "skip" {
+			return info, unplayable("not an ISO media file")
+		}
+		if pos+box.size > size {
+			if box.typ == "mdat" {
+				return info, truncated("mdat declares %d bytes, file ends %d bytes early", box.size, pos+box.size-size)
+			}
+			if box.typ == "moov" {
+				return info, truncated("moov cut short at end of file")
+			}
+			return info, truncated("%s box at %d runs past end of file", box.typ, pos)
+		}
+		switch box.typ {
+		case "ftyp":
+			brand := make([]byte, 4)
+			if err := readFull(r, brand, pos+box.headerLen, size); err != nil {
+				return info, err
+			}
+			if string(brand) == "qt  " {
+				info.Container = "mov"
+			}
+		case "moov":
+			if moovOff < 0 {
+				moovOff, moovSize = pos+box.headerLen, box.size-box.headerLen
+			}
+		case "mdat":
+			sawMdat = true
+		case "moof":
+			fragmented = true
+		}
+		pos += box.size
+	}
+
+	if moovOff < 0 {
+		return info, unplayable("moov atom missing")
+	}
+	if moovSize > maxIndexSize {
+		return info, unplayable("moov atom of %d bytes is implausibly large", moovSize)
+	}
+	moov := make([]byte, moovSize)
+	if err := readFull(r, moov, moovOff, size); err != nil {
+		return info, err
+	}
+	maxChunk := parseMoov(moov, info)
+	if !sawMdat {
+		return info, truncated("mdat atom missing")
+	}
+	if !fragmented && maxChunk >= size {
+		return info, truncated("sample data indexed at %d beyond end of file", maxChunk)
+	}
+	return info, nil
+}
+
+func readMP4BoxHeader(r io.ReaderAt, off, size int64) (mp4Box, error) {
+	if size-off < 8 {
+		return mp4Box{}, truncated("box header at %d past end of file", off)
+	}
+	buf := make([]byte, 16)
+	hdr := buf[:min(16, size-off)]
+	if err := readFull(r, hdr, off, size); err != nil {
+		return mp4Box{}, err
+	}
+	box := mp4Box{typ: string(hdr[4:8]), size: int64(binary.BigEndian.Uint32(hdr[:4])), headerLen: 8}
+	switch box.size {
+	case 0:
+		box.size = size - off
+	case 1:
+		if len(hdr) < 16 {
+			return mp4Box{}, truncated("large box header at %d past end of file", off)
+		}
+		box.size = int64(binary.BigEndian.Uint64(hdr[8:16]))
+		box.headerLen = 16
+	}
+	if box.size < box.headerLen {
+		return mp4Box{}, unplayable("invalid %q box size at %d", box.typ, off)
+	}
+	return box, nil
+}
+
+// walkBoxes calls fn for each box directly inside data.
+func walkBoxes(data []byte, fn func(typ string, payload []byte)) {
+	for len(data) >= 8 {
+		size := uint64(binary.BigEndian.Uint32(data[:4]))
+		typ := string(data[4:8])
+		hdr := uint64(8)
+		switch size {
+		case 0:
+			size = uint64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return
+			}
+			size = binary.BigEndian.Uint64(data[8:16])
+			hdr = 16
+		}
+		if size < hdr || size > uint64(len(data)) {
+			return
+		}
+		fn(typ, data[hdr:size])
+		data = data[size:]
+	}
+}
+
+// parseMoov fills info from the movie header and track boxes and returns the
+// highest chunk offset referenced by any sample table.
+func parseMoov(moov []byte, info *Info) int64 {
+	var maxChunk int64
+	walkBoxes(moov, func(typ string, payload []byte) {
+		switch typ {
+		case "mvhd":
+			if d := mvhdDuration(payload); d > 0 {
+				info.Duration = d
+			}
+		case "trak":
+			if c := parseTrak(payload, info); c > maxChunk {
+				maxChunk = c
+			}
+		}
+	})
+	return maxChunk
+}
+
+func mvhdDuration(b []byte) time.Duration {
+	if len(b) < 1 {
+		return 0
+	}
+	var timescale, duration uint64
+	if b[0] == 1 {
+		if len(b) < 32 {
+			return 0
+		}
+		timescale = uint64(binary.BigEndian.Uint32(b[20:24]))
+		duration = binary.BigEndian.Uint64(b[24:32])
+	} else {
+		if len(b) < 20 {
+			return 0
+		}
+		timescale = uint64(binary.BigEndian.Uint32(b[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(b[16:20]))
+	}
+	if timescale == 0 || duration == 0 || duration == 0xFFFFFFFF {
+		return 0
+	}
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second))
+}
+
+func parseTrak(trak []byte, info *Info) int64 {
+	var (
+		handler       string
+		codec         string
+		lang          string
+		width, height int
+		maxChunk      int64
+	)
+	walkBoxes(trak, func(typ string, payload []byte) {
+		switch typ {
+		case "tkhd":
+			width, height = tkhdDimensions(payload)
+		case "mdia":
+			walkBoxes(payload, func(typ string, payload []byte) {
+				switch typ {
+				case "mdhd":
+					lang = mdhdLanguage(payload)
+				case "hdlr":
+					if len(payload) >= 12 {
+						handler = string(payload[8:12])
+					}
+				case "minf":
+					walkBoxes(payload, func(typ string, payload []byte) {
+						if typ != "stbl" {
+							return
+						}
+						walkBoxes(payload, func(typ string, payload []byte) {
+							switch typ {
+							case "stsd":
+								if len(payload) >= 16 {
+									codec = string(payload[12:16])
+								}
+							case "stco", "co64":
+								if c := maxChunkOffset(typ, payload); c > maxChunk {
+									maxChunk = c
+								}
+							}
+						})
+					})
+				}
+			})
+		}
+	})
+
+	switch handler {
+	case "vide":
+		if info.VideoCodec == "" {
+			info.VideoCodec = mp4CodecName(codec)
+			info.Width, info.Height = width, height
+		}
+	case "soun":
+		info.AudioCodecs = appendUnique(info.AudioCodecs, mp4CodecName(codec))
+		info.AudioLanguages = appendUnique(info.AudioLanguages, lang)
+	case "subt", "text", "sbtl":
+		info.SubtitleLanguages = appendUnique(info.SubtitleLanguages, lang)
+	}
+	return maxChunk
+}
+
+func tkhdDimensions(b []byte) (int, int) {
+	if len(b) < 1 {
+		return 0, 0
+	}
+	off := 76
+	if b[0] == 1 {
+		off = 88
+	}
+	if len(b) < off+8 {
+		return 0, 0
+	}
+	// 16.16 fixed point
+	return int(binary.BigEndian.Uint32(b[off:off+4]) >> 16), int(binary.BigEndian.Uint32(b[off+4:off+8]) >> 16)
+}
+
+// mdhdLanguage decodes the packed ISO-639-2/T code in a media header.
+func mdhdLanguage(b []byte) string {
+	if len(b) < 1 {
+		return ""
+	}
+	off := 20
+	if b[0] == 1 {
+		off = 32
+	}
+	if len(b) < off+2 {
+		return ""
+	}
+	packed := binary.BigEndian.Uint16(b[off : off+2])
+	if packed == 0 || packed == 0x7FFF {
+		return ""
+	}
+	code := []byte{
+		byte(packed>>10&0x1F) + 0x60,
+		byte(packed>>5&0x1F) + 0x60,
+		byte(packed&0x1F) + 0x60,
+	}
+	lang := string(code)
+	if lang == "und" {
+		return ""
+	}
+	return lang
+}
+
+func maxChunkOffset(typ string, b []byte) int64 {
+	if len(b) < 8 {
+		return 0
+	}
+	count := int(binary.BigEndian.Uint32(b[4:8]))
+	entries := b[8:]
+	width := 4
+	if typ == "co64" {
+		width = 8
+	}
+	var highest int64
+	for i := 0; i < count && (i+1)*width <= len(entries); i++ {
+		var v int64
+		if width == 8 {
+			v = int64(binary.BigEndian.Uint64(entries[i*8:]))
+		} else {
+			v = int64(binary.BigEndian.Uint32(entries[i*4:]))
+		}
+		if v > highest {
+			highest = v
+		}
+	}
+	return highest
+}
+
+func mp4CodecName(fourcc string) string {
+	switch fourcc {
+	case "avc1", "avc3":
+		return "h264"
+	case "hvc1", "hev1":
+		return "hevc"
+	case "av01":
+		return "av1"
+	case "vp09":
+		return "vp9"
+	case "mp4v":
+		return "mpeg4"
+	case "mp4a":
+		return "aac"
+	case "ac-3":
+		return "ac3"
+	case "ec-3":
+		return "eac3"
+	case "Opus":
+		return "opus"
+	case "fLaC":
+		return "flac"
+	case ".mp3":
+		return "mp3"
+	}
+	return strings.ToLower(strings.TrimSpace(fourcc))
+}
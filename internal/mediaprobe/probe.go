@@ -0,0 +1,231 @@
+// Package mediaprobe reads just enough of a Matroska/WebM or MP4/MOV file to
+// describe its streams and to tell whether the container is intact. It never
+// decodes media; all reads go through an io.ReaderAt so callers can back it
+// with ranged HTTP or usenet reads.
+package mediaprobe
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Status summarises whether a probed container looks playable.
+type Status string
+
+const (
+	StatusOK         Status = "ok"
+	StatusTruncated  Status = "truncated"
+	StatusUnplayable Status = "unplayable"
+)
+
+// Info is the stream metadata extracted from a container header and index.
+type Info struct {
+	Container         string
+	Duration          time.Duration
+	VideoCodec        string
+	Width             int
+	Height            int
+	AudioCodecs       []string
+	AudioLanguages    []string
+	SubtitleLanguages []string
+	Status            Status
+	Reason            string
+}
+
+// ErrUnsupported is returned for files whose extension is not a container
+// this package understands.
+var ErrUnsupported = errors.New("unsupported container")
+
+const (
+	readBlockSize = 64 << 10
+	maxCachedRead = 32
+	// maxIndexSize bounds how much of an index element (MP4 moov, MKV
+	// Tracks) is pulled into memory.
+	maxIndexSize = 64 << 20
+)
+
+// Supported reports whether Probe can handle a file with this name.
+func Supported(name string) bool {
+	_, ok := containerForExt(name)
+	return ok
+}
+
+func containerForExt(name string) (string, bool) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".mkv", ".mk3d", ".mka":
+		return "matroska", true
+	case ".webm":
+		return "webm", true
+	case ".mp4", ".m4v":
+		return "mp4", true
+	case ".mov":
+		return "mov", true
+	}
+	return "", false
+}
+
+// Probe inspects the container of the named file. Structural problems are
+// reported through Info.Status; the returned error is reserved for read
+// failures and unsupported files, where nothing can be said about the media.
+func Probe(r io.ReaderAt, size int64, name string) (*Info, error) {
+	container, ok := containerForExt(name)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	if size <= 0 {
+		return &Info{Container: container, Status: StatusTruncated, Reason: "empty file"}, nil
+	}
+	br := newBlockReader(r, size)
+
+	var (
+		info *Info
+		err  error
+	)
+	switch container {
+	case "matroska", "webm":
+		info, err = probeMatroska(br, size)
+	default:
+		info, err = probeMP4(br, size)
+	}
+	if err != nil {
+		var se *structureError
+		if !errors.As(err, &se) {
+			return nil, err
+		}
+		if info == nil {
+			info = &Info{}
+		}
+		info.Status = se.status
+		info.Reason = se.reason
+	}
+	if info.Container == "" {
+		info.Container = container
+	}
+	if info.Status == "" {
+		info.Status = StatusOK
+		if info.VideoCodec == "" && len(info.AudioCodecs) == 0 {
+			info.Status = StatusUnplayable
+			info.Reason = "no audio or video tracks"
+		}
+	}
+	return info, nil
+}
+
+// structureError marks a malformed container, as opposed to an I/O failure.
+type structureError struct {
+	status Status
+	reason string
+}
+
+func (e *structureError) Error() string { return fmt.Sprintf("%s: %s", e.status, e.reason) }
+
+func truncated(format string, args ...any) error {
+	return &structureError{status: StatusTruncated, reason: fmt.Sprintf(format, args...)}
+}
+
+func unplayable(format string, args ...any) error {
+	return &structureError{status: StatusUnplayable, reason: fmt.Sprintf(format, args...)}
+}
+
+// blockReader serves small header reads from aligned, cached blocks so that
+// walking a container does not turn every 8-byte read into a remote request.
+type blockReader struct {
+	r      io.ReaderAt
+	size   int64
+	blocks map[int64][]byte
+	order  []int64
+}
+
+func newBlockReader(r io.ReaderAt, size int64) *blockReader {
+	return &blockReader{r: r, size: size, blocks: make(map[int64][]byte)}
+}
+
+func (b *blockReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset")
+	}
+	if off >= b.size {
+		return 0, io.EOF
+	}
+	if len(p) > readBlockSize {
+		// Large reads (moov, Tracks) bypass the cache.
+		want := p
+		if rem := b.size - off; int64(len(want)) > rem {
+			want = want[:rem]
+		}
+		n, err := b.r.ReadAt(want, off)
+		if err == nil && n < len(p) {
+			err = io.EOF
+		}
+		return n, err
+	}
+	n := 0
+	for n < len(p) && off+int64(n) < b.size {
+		pos := off + int64(n)
+		start := pos - pos%readBlockSize
+		block, err := b.block(start)
+		if err != nil {
+			return n, err
+		}
+		c := copy(p[n:], block[pos-start:])
+		if c == 0 {
+			break
+		}
+		n += c
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (b *blockReader) block(start int64) ([]byte, error) {
+	if data, ok := b.blocks[start]; ok {
+		return data, nil
+	}
+	length := min(int64(readBlockSize), b.size-start)
+	data := make([]byte, length)
+	n, err := b.r.ReadAt(data, start)
+	if err != nil && !(errors.Is(err, io.EOF) && int64(n) == length) {
+		return nil, err
+	}
+	if len(b.order) >= maxCachedRead {
+		delete(b.blocks, b.order[0])
+		b.order = b.order[1:]
+	}
+	b.blocks[start] = data
+	b.order = append(b.order, start)
+	return data, nil
+}
+
+// readFull reads exactly len(p) bytes at off. A short read past the end of
+// the file is a truncation, anything else is an I/O error.
+func readFull(r io.ReaderAt, p []byte, off, size int64) error {
+	if off+int64(len(p)) > size {
+		return truncated("read of %d bytes at %d past end of file", len(p), off)
+	}
+	n, err := r.ReadAt(p, off)
+	if n == len(p) {
+		return nil
+	}
+	if err == nil || errors.Is(err, io.EOF) {
+		return truncated("short read at %d", off)
+	}
+	return err
+}
+
+func appendUnique(list []string, v string) []string {
+	if v == "" {
+		return list
+	}
+	for _, existing := range list {
+		if existing == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
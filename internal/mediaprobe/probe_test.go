@@ -0,0 +1,208 @@
+package mediaprobe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+func ebmlElement(id uint32, payload []byte) []byte {
+	var out []byte
+	switch {
+	case id > 0xFFFFFF:
+		out = append(out, byte(id>>24), byte(id>>16), byte(id>>8), byte(id))
+	case id > 0xFFFF:
+		out = append(out, byte(id>>16), byte(id>>8), byte(id))
+	case id > 0xFF:
+		out = append(out, byte(id>>8), byte(id))
+	default:
+		out = append(out, byte(id))
+	}
+	// 8-byte size vint keeps the builder simple.
+	size := make([]byte, 8)
+	binary.BigEndian.PutUint64(size, uint64(len(payload)))
+	size[0] = 0x01
+	out = append(out, size...)
+	return append(out, payload...)
+}
+
+func ebmlUintBytes(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
+func buildMKV(withCluster bool) []byte {
+	head := ebmlElement(mkvEBML, ebmlElement(mkvDocType, []byte("matroska")))
+	dur := make([]byte, 8)
+	binary.BigEndian.PutUint64(dur, math.Float64bits(90_000)) // 90s at 1ms scale
+	info := ebmlElement(mkvInfo, append(
+		ebmlElement(mkvTimecodeScale, ebmlUintBytes(1_000_000)),
+		ebmlElement(mkvDuration, dur)...,
+	))
+	video := ebmlElement(mkvTrackEntry, bytes.Join([][]byte{
+		ebmlElement(mkvTrackType, []byte{mkvTrackVideo}),
+		ebmlElement(mkvCodecID, []byte("V_MPEGH/ISO/HEVC")),
+		ebmlElement(mkvVideo, append(
+			ebmlElement(mkvPixelWidth, ebmlUintBytes(1920)),
+			ebmlElement(mkvPixelHeight, ebmlUintBytes(1080))...,
+		)),
+	}, nil))
+	audio := ebmlElement(mkvTrackEntry, bytes.Join([][]byte{
+		ebmlElement(mkvTrackType, []byte{mkvTrackAudio}),
+		ebmlElement(mkvCodecID, []byte("A_EAC3")),
+		ebmlElement(mkvLanguage, []byte("ger")),
+	}, nil))
+	sub := ebmlElement(mkvTrackEntry, bytes.Join([][]byte{
+		ebmlElement(mkvTrackType, []byte{mkvTrackSubtitle}),
+		ebmlElement(mkvCodecID, []byte("S_TEXT/UTF8")),
+	}, nil))
+	tracks := ebmlElement(mkvTracks, bytes.Join([][]byte{video, audio, sub}, nil))
+	body := append(info, tracks...)
+	if withCluster {
+		body = append(body, ebmlElement(mkvCluster, make([]byte, 4096))...)
+	}
+	return append(head, ebmlElement(mkvSegment, body)...)
+}
+
+func TestProbeMatroska(t *testing.T) {
+	data := buildMKV(true)
+	info, err := Probe(bytes.NewReader(data), int64(len(data)), "movie.mkv")
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	if info.Status != StatusOK {
+		t.Fatalf("status = %s (%s), want ok", info.Status, info.Reason)
+	}
+	if info.VideoCodec != "hevc" || info.Width != 1920 || info.Height != 1080 {
+		t.Errorf("video = %s %dx%d", info.VideoCodec, info.Width, info.Height)
+	}
+	if len(info.AudioCodecs) != 1 || info.AudioCodecs[0] != "eac3" {
+		t.Errorf("audio codecs = %v", info.AudioCodecs)
+	}
+	if len(info.AudioLanguages) != 1 || info.AudioLanguages[0] != "ger" {
+		t.Errorf("audio languages = %v", info.AudioLanguages)
+	}
+	if len(info.SubtitleLanguages) != 1 || info.SubtitleLanguages[0] != "eng" {
+		t.Errorf("subtitle languages = %v", info.SubtitleLanguages)
+	}
+	if info.Duration != 90*time.Second {
+		t.Errorf("duration = %s", info.Duration)
+	}
+}
+
+func TestProbeMatroskaTruncated(t *testing.T) {
+	data := buildMKV(true)
+	cut := data[:len(data)-1024]
+	info, err := Probe(bytes.NewReader(cut), int64(len(cut)), "movie.mkv")
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	if info.Status != StatusTruncated {
+		t.Fatalf("status = %s, want truncated", info.Status)
+	}
+	if info.VideoCodec != "hevc" {
+		t.Errorf("tracks should still be read from a truncated file, got %q", info.VideoCodec)
+	}
+}
+
+func testMP4Box(typ string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	out := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(out, uint32(8+len(body)))
+	copy(out[4:], typ)
+	return append(out, body...)
+}
+
+func buildMP4(mdatLen int, chunkOffset uint32) []byte {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:], 42_000)
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:], 1280<<16)
+	binary.BigEndian.PutUint32(tkhd[80:], 720<<16)
+	mdhd := make([]byte, 24)
+	binary.BigEndian.PutUint16(mdhd[20:], uint16('f'-0x60)<<10|uint16('r'-0x60)<<5|uint16('e'-0x60))
+	hdlr := func(h string) []byte {
+		b := make([]byte, 24)
+		copy(b[8:], h)
+		return b
+	}
+	stsd := func(fourcc string) []byte {
+		b := make([]byte, 16)
+		binary.BigEndian.PutUint32(b[4:], 1)
+		copy(b[12:], fourcc)
+		return b
+	}
+	stco := make([]byte, 12)
+	binary.BigEndian.PutUint32(stco[4:], 1)
+	binary.BigEndian.PutUint32(stco[8:], chunkOffset)
+
+	videoTrak := testMP4Box("trak",
+		testMP4Box("tkhd", tkhd),
+		testMP4Box("mdia", testMP4Box("mdhd", mdhd), testMP4Box("hdlr", hdlr("vide")),
+			testMP4Box("minf", testMP4Box("stbl", testMP4Box("stsd", stsd("avc1")), testMP4Box("stco", stco)))),
+	)
+	audioTrak := testMP4Box("trak",
+		testMP4Box("mdia", testMP4Box("mdhd", mdhd), testMP4Box("hdlr", hdlr("soun")),
+			testMP4Box("minf", testMP4Box("stbl", testMP4Box("stsd", stsd("mp4a"))))),
+	)
+	ftyp := testMP4Box("ftyp", []byte("isom"), make([]byte, 4))
+	moov := testMP4Box("moov", testMP4Box("mvhd", mvhd), videoTrak, audioTrak)
+	mdat := testMP4Box("mdat", make([]byte, mdatLen))
+	return bytes.Join([][]byte{ftyp, moov, mdat}, nil)
+}
+
+func TestProbeMP4(t *testing.T) {
+	data := buildMP4(8192, 600)
+	info, err := Probe(bytes.NewReader(data), int64(len(data)), "clip.mp4")
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	if info.Status != StatusOK {
+		t.Fatalf("status = %s (%s), want ok", info.Status, info.Reason)
+	}
+	if info.VideoCodec != "h264" || info.Width != 1280 || info.Height != 720 {
+		t.Errorf("video = %s %dx%d", info.VideoCodec, info.Width, info.Height)
+	}
+	if len(info.AudioCodecs) != 1 || info.AudioCodecs[0] != "aac" {
+		t.Errorf("audio codecs = %v", info.AudioCodecs)
+	}
+	if len(info.AudioLanguages) != 1 || info.AudioLanguages[0] != "fre" {
+		t.Errorf("audio languages = %v", info.AudioLanguages)
+	}
+	if info.Duration != 42*time.Second {
+		t.Errorf("duration = %s", info.Duration)
+	}
+}
+
+func TestProbeMP4Truncated(t *testing.T) {
+	data := buildMP4(8192, 600)
+	cut := data[:len(data)-100]
+	info, err := Probe(bytes.NewReader(cut), int64(len(cut)), "clip.mp4")
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	if info.Status != StatusTruncated {
+		t.Fatalf("status = %s, want truncated", info.Status)
+	}
+}
+
+func TestProbeMP4MissingMoov(t *testing.T) {
+	data := append(testMP4Box("ftyp", []byte("isom"), make([]byte, 4)), testMP4Box("mdat", make([]byte, 512))...)
+	info, err := Probe(bytes.NewReader(data), int64(len(data)), "clip.mp4")
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	if info.Status != StatusUnplayable {
+		t.Fatalf("status = %s, want unplayable", info.Status)
+	}
+}
+
+func TestProbeUnsupported(t *testing.T) {
+	if _, err := Probe(bytes.NewReader(nil), 10, "movie.avi"); err != ErrUnsupported {
+		t.Fatalf("err = %v, want ErrUnsupported", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/textproto"
 	"sort"
@@ -32,6 +33,7 @@ type ProviderPool struct {
 	max         int
 	config      config.UsenetProvider
 	activeConns sync.Map // *Connection → struct{}; tracks checked-out connections for force-close on shutdown
+	traffic     *providerTraffic
 }
 
 // Client manages a pool of NNTP connections.
@@ -46,6 +48,9 @@ type Client struct {
 	// Speed test results storage
 	speedTestResults *xsync.Map[string, SpeedTestResult]
 
+	// trafficStore persists the per-provider traffic counters
+	trafficStore atomic.Pointer[TrafficStore]
+
 	// repairPool is the shared worker pool that processes BatchStat
 	// chunks. Sized at construction from cfg.Repair.NNTPConnectionPercent.
 	// Replaces the previous RepairBank counting semaphore + per-call
@@ -201,10 +206,11 @@ func NewClient(cfg *config.Config) (*Client, error) {
 	pools := make(map[string]*ProviderPool)
 	for _, p := range providers {
 		pp := &ProviderPool{
-			conns:  make([]*connectionEntry, 0, p.MaxConnections),
-			slots:  make(chan struct{}, p.MaxConnections),
-			max:    p.MaxConnections,
-			config: p,
+			conns:   make([]*connectionEntry, 0, p.MaxConnections),
+			slots:   make(chan struct{}, p.MaxConnections),
+			max:     p.MaxConnections,
+			config:  p,
+			traffic: &providerTraffic{},
 		}
 		pools[p.Host] = pp
 	}
@@ -476,8 +482,10 @@ func (c *Client) getConnectionFromProvider(ctx context.Context, provider config.
 	}
 }
 
-// safeExecute wraps fn execution with panic recovery
+// safeExecute wraps fn execution with panic recovery. An execution that
+// fetched an article body is recorded in the provider's article stats.
 func (c *Client) safeExecute(conn *Connection, fn func(conn *Connection) error) (err error) {
+	conn.fetchedArticle = false
 	defer func() {
 		if r := recover(); r != nil {
 			c.logger.Error().
@@ -486,6 +494,9 @@ func (c *Client) safeExecute(conn *Connection, fn func(conn *Connection) error)
 				Msg("Recovered from panic in NNTP operation")
 			err = customerror.NewPanicError(r)
 		}
+		if conn.fetchedArticle {
+			c.recordArticle(conn.address, err == nil)
+		}
 	}()
 	return fn(conn)
 }
@@ -821,7 +832,11 @@ func (c *Client) createConnection(ctx context.Context, provider config.UsenetPro
 		}
 	}
 
-	reader := bufio.NewReaderSize(netConn, 512*1024)
+	var source io.Reader = netConn
+	if pp, ok := c.pools[provider.Host]; ok {
+		source = &countingReader{r: netConn, traffic: pp.traffic}
+	}
+	reader := bufio.NewReaderSize(source, 512*1024)
 	writer := bufio.NewWriterSize(netConn, 64*1024)
 
 	conn := &Connection{
@@ -955,6 +970,14 @@ func (c *Client) Stats() map[string]any {
 			"ssl":             p.SSL,
 		}
 
+		traffic := pp.traffic.snapshot(p.Host)
+		providerInfo["traffic"] = map[string]any{
+			"total": traffic.Total,
+			"month": traffic.Month,
+			"week":  traffic.Week,
+			"day":   traffic.Day,
+		}
+
 		// Add speed test result if available
 		if result, ok := c.speedTestResults.Load(p.Host); ok {
 			providerInfo["speed_test"] = map[string]any{
@@ -982,6 +1005,39 @@ func (c *Client) Stats() map[string]any {
 	return stats
 }
 
+// Ping checks that at least one provider accepts and authenticates a
+// connection. A provider whose pool is fully checked out is serving traffic,
+// so it counts as reachable without waiting for a slot.
+func (c *Client) Ping(ctx context.Context) error {
+	if c.closed.Load() {
+		return errors.New("nntp client is closed")
+	}
+
+	var errs []error
+	for _, provider := range c.providers {
+		pp := c.pools[provider.Host]
+		select {
+		case pp.slots <- struct{}{}:
+		default:
+			return nil
+		}
+		conn, err := c.getOrCreateFromPool(ctx, pp, provider)
+		if err != nil {
+			<-pp.slots
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Host, err))
+			continue
+		}
+		if err := conn.ping(); err != nil {
+			c.release(conn)
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Host, err))
+			continue
+		}
+		c.put(conn, provider)
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
 func (c *Client) Stat(ctx context.Context, messageID string) (int, string, error) {
 	if c.closed.Load() {
 		return 0, "", errors.New("nntp client is closed")
@@ -219,6 +219,10 @@ type Connection struct {
 	logger                      zerolog.Logger
 	closed                      atomic.Bool
 
+	// fetchedArticle is set once BODY or ARTICLE is sent, so only article
+	// fetches count towards the provider's article stats, not STAT or HEAD.
+	fetchedArticle bool
+
 	// Body-copy idle tracking. Written by copyBodyWithIdleDeadline on
 	// copyBodyWithIdleDeadline periodically while reads make progress;
 	// read by the shared janitor goroutine when sweeping for stalls.
@@ -329,6 +333,9 @@ func (c *Connection) sendCommand(command string) error {
 }
 
 func (c *Connection) sendCommandArg(command, arg string) error {
+	if command == "BODY" || command == "ARTICLE" {
+		c.fetchedArticle = true
+	}
 	_ = c.conn.SetWriteDeadline(utils.Now().Add(timeouts.HandshakeTimeout))
 	defer func() { _ = c.conn.SetWriteDeadline(time.Time{}) }()
 
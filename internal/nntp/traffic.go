@@ -0,0 +1,259 @@
+package nntp
+
+import (
+	"cmp"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/utils"
+)
+
+// trafficHistoryDays is how many finished days of traffic a provider keeps,
+// enough to cover the current calendar month.
+const trafficHistoryDays = 31
+
+// ProviderTraffic is what one provider served, counters persisted across
+// restarts included, in the shape of SABnzbd's server_stats. Daily keys are local dates and cover
+// at most the last trafficHistoryDays days.
+type ProviderTraffic struct {
+	Host            string
+	Total           int64
+	Month           int64
+	Week            int64
+	Day             int64
+	Daily           map[string]int64
+	ArticlesTried   map[string]int64
+	ArticlesSuccess map[string]int64
+}
+
+// TrafficDay is one day of a provider's traffic as persisted.
+type TrafficDay struct {
+	Day             int64 `json:"day"` // Days since the epoch in local time
+	Bytes           int64 `json:"bytes"`
+	ArticlesTried   int64 `json:"articles_tried"`
+	ArticlesSuccess int64 `json:"articles_success"`
+}
+
+// TrafficState is a provider's traffic counters as persisted across restarts.
+type TrafficState struct {
+	Total int64        `json:"total"`
+	Days  []TrafficDay `json:"days"`
+}
+
+// TrafficStore persists provider traffic counters across restarts.
+type TrafficStore interface {
+	GetProviderTraffic(host string) (*TrafficState, error)
+	SaveProviderTraffic(host string, traffic *TrafficState) error
+}
+
+type dayTraffic struct {
+	day             int64 // Days since the epoch in local time
+	bytes           atomic.Int64
+	articlesTried   atomic.Int64
+	articlesSuccess atomic.Int64
+}
+
+// providerTraffic counts bytes read and articles fetched from one provider,
+// bucketed by day. The current day is updated lock-free; the mutex only
+// guards rolling over to a new day.
+type providerTraffic struct {
+	total   atomic.Int64
+	current atomic.Pointer[dayTraffic]
+	mu      sync.Mutex
+	history []*dayTraffic // Finished days, oldest first
+}
+
+func localDay(t time.Time) int64 {
+	_, offset := t.Zone()
+	return (t.Unix() + int64(offset)) / 86400
+}
+
+func (t *providerTraffic) today() *dayTraffic {
+	day := localDay(utils.Now())
+	if d := t.current.Load(); d != nil && d.day == day {
+		return d
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	d := t.current.Load()
+	if d != nil && d.day == day {
+		return d
+	}
+	if d != nil {
+		t.history = append(t.history, d)
+		if len(t.history) > trafficHistoryDays {
+			t.history = t.history[len(t.history)-trafficHistoryDays:]
+		}
+	}
+	next := &dayTraffic{day: day}
+	t.current.Store(next)
+	return next
+}
+
+func (t *providerTraffic) addBytes(n int) {
+	if n > 0 {
+		t.total.Add(int64(n))
+		t.today().bytes.Add(int64(n))
+	}
+}
+
+func (t *providerTraffic) addArticle(success bool) {
+	d := t.today()
+	d.articlesTried.Add(1)
+	if success {
+		d.articlesSuccess.Add(1)
+	}
+}
+
+// snapshot sums the recorded days into totals for today, this week (from
+// Monday) and this calendar month.
+func (t *providerTraffic) snapshot(host string) ProviderTraffic {
+	current := t.today()
+	t.mu.Lock()
+	days := append(append([]*dayTraffic{}, t.history...), current)
+	t.mu.Unlock()
+
+	now := utils.Now()
+	weekStart := current.day - int64((now.Weekday()+6)%7)
+	monthStart := current.day - int64(now.Day()-1)
+
+	out := ProviderTraffic{
+		Host:            host,
+		Total:           t.total.Load(),
+		Daily:           make(map[string]int64, len(days)),
+		ArticlesTried:   make(map[string]int64, len(days)),
+		ArticlesSuccess: make(map[string]int64, len(days)),
+	}
+	for _, d := range days {
+		n := d.bytes.Load()
+		if d.day == current.day {
+			out.Day += n
+		}
+		if d.day >= weekStart {
+			out.Week += n
+		}
+		if d.day >= monthStart {
+			out.Month += n
+		}
+		date := time.Unix(d.day*86400, 0).UTC().Format(time.DateOnly)
+		out.Daily[date] = n
+		out.ArticlesTried[date] = d.articlesTried.Load()
+		out.ArticlesSuccess[date] = d.articlesSuccess.Load()
+	}
+	return out
+}
+
+// state returns the counters in their persisted form.
+func (t *providerTraffic) state() *TrafficState {
+	current := t.today()
+	t.mu.Lock()
+	days := append(append([]*dayTraffic{}, t.history...), current)
+	t.mu.Unlock()
+
+	out := &TrafficState{Total: t.total.Load(), Days: make([]TrafficDay, 0, len(days))}
+	for _, d := range days {
+		out.Days = append(out.Days, TrafficDay{
+			Day:             d.day,
+			Bytes:           d.bytes.Load(),
+			ArticlesTried:   d.articlesTried.Load(),
+			ArticlesSuccess: d.articlesSuccess.Load(),
+		})
+	}
+	return out
+}
+
+// restore adds persisted counters to the ones recorded since start. Days
+// older than the kept history are dropped.
+func (t *providerTraffic) restore(s *TrafficState) {
+	current := t.today()
+	t.total.Add(s.Total)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	byDay := make(map[int64]*dayTraffic, len(t.history)+len(s.Days))
+	for _, d := range t.history {
+		byDay[d.day] = d
+	}
+	for _, sd := range s.Days {
+		if sd.Day > current.day || sd.Day <= current.day-trafficHistoryDays {
+			continue
+		}
+		d := current
+		if sd.Day != current.day {
+			if d = byDay[sd.Day]; d == nil {
+				d = &dayTraffic{day: sd.Day}
+				byDay[sd.Day] = d
+			}
+		}
+		d.bytes.Add(sd.Bytes)
+		d.articlesTried.Add(sd.ArticlesTried)
+		d.articlesSuccess.Add(sd.ArticlesSuccess)
+	}
+	t.history = t.history[:0]
+	for _, d := range byDay {
+		t.history = append(t.history, d)
+	}
+	slices.SortFunc(t.history, func(a, b *dayTraffic) int {
+		return cmp.Compare(a.day, b.day)
+	})
+}
+
+// countingReader feeds the bytes read from a provider connection into its
+// traffic counters.
+type countingReader struct {
+	r       io.Reader
+	traffic *providerTraffic
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.traffic.addBytes(n)
+	return n, err
+}
+
+// Traffic returns the per-provider traffic counters, in provider order.
+func (c *Client) Traffic() []ProviderTraffic {
+	out := make([]ProviderTraffic, 0, len(c.providers))
+	for _, p := range c.providers {
+		if pp, ok := c.pools[p.Host]; ok {
+			out = append(out, pp.traffic.snapshot(p.Host))
+		}
+	}
+	return out
+}
+
+// SetTrafficStore registers where traffic counters are persisted and restores
+// the stored counters of every provider.
+func (c *Client) SetTrafficStore(store TrafficStore) {
+	c.trafficStore.Store(&store)
+	for host, pp := range c.pools {
+		s, err := store.GetProviderTraffic(host)
+		if err != nil || s == nil {
+			continue
+		}
+		pp.traffic.restore(s)
+	}
+}
+
+// SaveTraffic persists the traffic counters of every provider.
+func (c *Client) SaveTraffic() {
+	store := c.trafficStore.Load()
+	if store == nil || *store == nil {
+		return
+	}
+	for host, pp := range c.pools {
+		if err := (*store).SaveProviderTraffic(host, pp.traffic.state()); err != nil {
+			c.logger.Debug().Err(err).Str("host", host).Msg("Failed to save provider traffic")
+		}
+	}
+}
+
+func (c *Client) recordArticle(host string, success bool) {
+	if pp, ok := c.pools[host]; ok {
+		pp.traffic.addArticle(success)
+	}
+}
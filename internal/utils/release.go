@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	resolutionRegex = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(4320p|2160p|1440p|1080[pi]|720p|576[pi]|480[pi]|360p|8k|4k|uhd)(?:[^a-z0-9]|$)`)
+	codecRegex      = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(av1|vp9|[xh][\s.]?26[45]|hevc|avc|xvid|divx|mpeg-?2|vc-?1)(?:[^a-z0-9]|$)`)
+)
+
+// ReleaseResolution returns the resolution a release name advertises, e.g.
+// "2160p" for "Movie.2021.UHD.BluRay.2160p.x265", or "" when it has none.
+// 4K and UHD read as 2160p and 8K as 4320p.
+func ReleaseResolution(name string) string {
+	m := resolutionRegex.FindStringSubmatch(name)
+	if m == nil {
+		return ""
+	}
+	switch res := strings.ToLower(m[1]); res {
+	case "4k", "uhd":
+		return "2160p"
+	case "8k":
+		return "4320p"
+	default:
+		return res
+	}
+}
+
+// ReleaseCodec returns the video codec a release name advertises, normalised
+// to h264, h265, av1, vp9, xvid, mpeg2 or vc1, or "" when it has none
+func ReleaseCodec(name string) string {
+	m := codecRegex.FindStringSubmatch(name)
+	if m == nil {
+		return ""
+	}
+	codec := strings.NewReplacer(" ", "", ".", "", "-", "").Replace(strings.ToLower(m[1]))
+	switch codec {
+	case "x264", "h264", "avc":
+		return "h264"
+	case "x265", "h265", "hevc":
+		return "h265"
+	case "divx":
+		return "xvid"
+	default:
+		return codec
+	}
+}
@@ -0,0 +1,31 @@
+package utils
+
+import "testing"
+
+func TestReleaseResolutionAndCodec(t *testing.T) {
+	tests := []struct {
+		name       string
+		resolution string
+		codec      string
+	}{
+		{"Movie.2021.2160p.UHD.BluRay.x265-GROUP", "2160p", "h265"},
+		{"Movie 2021 1080p WEB-DL H.264", "1080p", "h264"},
+		{"Show.S01E02.720p.HDTV.h264", "720p", "h264"},
+		{"Movie.2019.4K.HDR.HEVC", "2160p", "h265"},
+		{"Movie.2020.UHD.AV1", "2160p", "av1"},
+		{"Old.Movie.1999.DVDRip.XviD", "", "xvid"},
+		{"Show.S02.1080i.AVC.REMUX", "1080i", "h264"},
+		{"Documentary.2018.WEB", "", ""},
+		{"Album.x2650.Remastered", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReleaseResolution(tt.name); got != tt.resolution {
+				t.Errorf("ReleaseResolution(%q) = %q, want %q", tt.name, got, tt.resolution)
+			}
+			if got := ReleaseCodec(tt.name); got != tt.codec {
+				t.Errorf("ReleaseCodec(%q) = %q, want %q", tt.name, got, tt.codec)
+			}
+		})
+	}
+}
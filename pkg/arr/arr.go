@@ -198,6 +198,45 @@ func (s *Storage) AddOrUpdate(arr *Arr) {
 	s.arrs.Store(arr.Name, arr)
 }
 
+// Authenticate resolves the Arr behind a download-client request. Clients
+// pass the Arr's host and token as their credentials; when auth is enabled
+// and those don't validate, they must be the user's own credentials. A
+// valid host and token registers the Arr under category.
+func (s *Storage) Authenticate(category, username, password string) (*Arr, error) {
+	cfg := config.Get()
+	a := s.Get(category)
+	if a == nil {
+		// Arr is not configured yet — inherit download_uncached from a
+		// matching config entry. If no config match, leave nil so
+		// SendToDebrid falls back to the debrid provider's setting.
+		var downloadUncached *bool
+		for _, cfgArr := range cfg.Arrs {
+			if cfgArr.Name == category {
+				downloadUncached = cfgArr.DownloadUncached
+				break
+			}
+		}
+		a = New(category, username, password, false, downloadUncached, "", "auto")
+	}
+	if (username == "" || password == "") && cfg.UseAuth {
+		return nil, fmt.Errorf("unauthorized: Host and token are required for authentication(you've enabled authentication)")
+	}
+	if a.Source == "auto" {
+		a.Host = username
+		a.Token = password
+	}
+	if a.Validate() != nil && cfg.UseAuth {
+		// If arr validation failed, try to use user auth validation
+		if !config.VerifyAuth(username, password) {
+			return nil, fmt.Errorf("unauthorized: invalid credentials")
+		}
+	}
+	if username != "" && password != "" {
+		s.AddOrUpdate(a)
+	}
+	return a, nil
+}
+
 func (s *Storage) GetOrCreate(name string) *Arr {
 	if name == "" {
 		name = "uncategorized"
@@ -6,6 +6,7 @@ import (
 	gourl "net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sirrobot01/decypharr/internal/config"
 	"github.com/sirrobot01/decypharr/internal/logger"
@@ -78,12 +79,13 @@ type HistorySchema struct {
 }
 
 type HistoryRecord struct {
-	ID         int    `json:"id"`
-	DownloadID string `json:"downloadId"`
-	EventType  string `json:"eventType"`
-	EpisodeID  int    `json:"episodeId,omitempty"`
-	SeriesID   int    `json:"seriesId,omitempty"`
-	MovieID    int    `json:"movieId,omitempty"`
+	ID         int       `json:"id"`
+	DownloadID string    `json:"downloadId"`
+	EventType  string    `json:"eventType"`
+	EpisodeID  int       `json:"episodeId,omitempty"`
+	SeriesID   int       `json:"seriesId,omitempty"`
+	MovieID    int       `json:"movieId,omitempty"`
+	Date       time.Time `json:"date"`
 }
 
 type QueueResponseScheme struct {
@@ -136,6 +138,31 @@ func (a *Arr) GetHistory(downloadId, eventType string) *HistorySchema {
 	return data
 }
 
+// historyEventImported is the history event type of a completed download
+// being imported, shared by Sonarr and Radarr
+const historyEventImported = "3"
+
+// RecentImports returns when each of the latest imports happened, keyed by
+// lowercased download ID. A download imported more than once keeps its
+// latest import.
+func (a *Arr) RecentImports() map[string]time.Time {
+	history := a.GetHistory("", historyEventImported)
+	if history == nil {
+		return nil
+	}
+	imports := make(map[string]time.Time, len(history.Records))
+	for _, record := range history.Records {
+		if record.DownloadID == "" {
+			continue
+		}
+		id := strings.ToLower(record.DownloadID)
+		if record.Date.After(imports[id]) {
+			imports[id] = record.Date
+		}
+	}
+	return imports
+}
+
 func (a *Arr) GetQueue() []QueueSchema {
 	query := gourl.Values{}
 	query.Add("page", "1")
@@ -1,6 +1,7 @@
 package account
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -22,6 +23,16 @@ type Account struct {
 
 	// Account reactivation tracking
 	DisableCount atomic.Int32 `json:"disable_count"`
+
+	// Traffic budget tracking. OverQuota is kept apart from Disabled so the
+	// account comes back on its own once the quota window resets.
+	OverQuota    atomic.Bool `json:"over_quota"`
+	trafficMu    sync.Mutex
+	trafficDay   string
+	trafficMonth string
+	dailyBytes   int64
+	monthlyBytes int64
+	expiryWarned time.Time // Expiration the last warning was sent for
 }
 
 func (a *Account) Equals(other *Account) bool {
@@ -0,0 +1,307 @@
+package account
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/debrid/types"
+)
+
+type NoticeKind string
+
+const (
+	NoticeQuotaExceeded NoticeKind = "quota_exceeded"
+	NoticeQuotaRestored NoticeKind = "quota_restored"
+	NoticeExpiring      NoticeKind = "expiring"
+)
+
+// Notice reports an account lifecycle change worth telling the user about.
+type Notice struct {
+	Kind    NoticeKind
+	Debrid  string
+	Account *Account
+	Message string
+}
+
+type NoticeFunc func(Notice)
+
+// TrafficStore persists account traffic counters across restarts.
+type TrafficStore interface {
+	GetAccountTraffic(key string) (*types.AccountTraffic, error)
+	SaveAccountTraffic(key string, traffic *types.AccountTraffic) error
+}
+
+// Budget holds the per-account traffic limits of a debrid. Zero disables a
+// limit.
+type Budget struct {
+	Daily         int64
+	Monthly       int64
+	ExpiryWarning time.Duration
+}
+
+func budgetFromConfig(dc config.Debrid) Budget {
+	var b Budget
+	if dc.DailyTrafficLimit != "" {
+		b.Daily, _ = config.ParseSize(dc.DailyTrafficLimit)
+	}
+	if dc.MonthlyTrafficLimit != "" {
+		b.Monthly, _ = config.ParseSize(dc.MonthlyTrafficLimit)
+	}
+	if dc.ExpiryWarningDays > 0 {
+		b.ExpiryWarning = time.Duration(dc.ExpiryWarningDays) * 24 * time.Hour
+	}
+	return b
+}
+
+// rollTraffic resets the counters whose window has passed.
+// Callers must hold trafficMu.
+func (a *Account) rollTraffic(now time.Time) {
+	day := now.Format(time.DateOnly)
+	month := now.Format("2006-01")
+	if a.trafficDay != day {
+		a.trafficDay = day
+		a.dailyBytes = 0
+	}
+	if a.trafficMonth != month {
+		a.trafficMonth = month
+		a.monthlyBytes = 0
+	}
+}
+
+func (a *Account) addTraffic(n int64, now time.Time) {
+	a.trafficMu.Lock()
+	defer a.trafficMu.Unlock()
+	a.rollTraffic(now)
+	a.dailyBytes += n
+	a.monthlyBytes += n
+}
+
+// ObserveTraffic records the bytes the provider reports as used today. What
+// it reports beyond this instance's own count, e.g. downloads made elsewhere,
+// is folded into the day and month counters, so the month keeps it after the
+// provider's daily figure resets.
+func (a *Account) ObserveTraffic(used int64, now time.Time) {
+	a.TrafficUsed.Store(used)
+	a.trafficMu.Lock()
+	defer a.trafficMu.Unlock()
+	a.rollTraffic(now)
+	if external := used - a.dailyBytes; external > 0 {
+		a.dailyBytes += external
+		a.monthlyBytes += external
+	}
+}
+
+// TrafficUsage returns the bytes used in the current day and month.
+func (a *Account) TrafficUsage(now time.Time) (daily, monthly int64) {
+	a.trafficMu.Lock()
+	defer a.trafficMu.Unlock()
+	a.rollTraffic(now)
+	return a.dailyBytes, a.monthlyBytes
+}
+
+func (a *Account) trafficState() *types.AccountTraffic {
+	a.trafficMu.Lock()
+	defer a.trafficMu.Unlock()
+	return &types.AccountTraffic{
+		Day:          a.trafficDay,
+		Month:        a.trafficMonth,
+		Daily:        a.dailyBytes,
+		Monthly:      a.monthlyBytes,
+		ExpiryWarned: a.expiryWarned,
+	}
+}
+
+func (a *Account) restoreTraffic(t *types.AccountTraffic) {
+	a.trafficMu.Lock()
+	defer a.trafficMu.Unlock()
+	a.trafficDay, a.dailyBytes = t.Day, t.Daily
+	a.trafficMonth, a.monthlyBytes = t.Month, t.Monthly
+	a.expiryWarned = t.ExpiryWarned
+}
+
+// Usable reports whether the account may be handed out for new downloads.
+func (a *Account) Usable() bool {
+	return !a.Disabled.Load() && !a.OverQuota.Load()
+}
+
+// quotaExceeded returns which budget, if any, the account has used up.
+func (b Budget) quotaExceeded(a *Account, now time.Time) string {
+	daily, monthly := a.TrafficUsage(now)
+	switch {
+	case b.Daily > 0 && daily >= b.Daily:
+		return "daily"
+	case b.Monthly > 0 && monthly >= b.Monthly:
+		return "monthly"
+	}
+	return ""
+}
+
+// SetNotifier registers the callback receiving account lifecycle notices.
+func (m *Manager) SetNotifier(fn NoticeFunc) {
+	m.notify.Store(&fn)
+}
+
+func (m *Manager) emit(n Notice) {
+	fn := m.notify.Load()
+	if fn == nil || *fn == nil {
+		return
+	}
+	n.Debrid = m.debrid
+	(*fn)(n)
+}
+
+// RecordTraffic adds bytes served through the account owning token and takes
+// it out of rotation once a budget is used up.
+func (m *Manager) RecordTraffic(token string, n int64) {
+	if n <= 0 || token == "" {
+		return
+	}
+	acc, ok := m.accounts.Load(token)
+	if !ok {
+		return
+	}
+	now := utils.Now()
+	acc.addTraffic(n, now)
+	m.checkBudget(acc, now)
+}
+
+// CheckBudgets re-evaluates every account: accounts whose quota window reset
+// are put back into rotation and expiry warnings are sent.
+func (m *Manager) CheckBudgets() {
+	now := utils.Now()
+	for _, acc := range m.All() {
+		m.checkBudget(acc, now)
+		m.checkExpiry(acc, now)
+	}
+	m.SaveTraffic()
+}
+
+func (m *Manager) checkBudget(acc *Account, now time.Time) {
+	exceeded := m.budget.quotaExceeded(acc, now)
+	switch {
+	case exceeded != "" && !acc.OverQuota.Load():
+		acc.OverQuota.Store(true)
+		m.logger.Warn().
+			Str("debrid", m.debrid).
+			Str("account_token", utils.Mask(acc.Token)).
+			Str("budget", exceeded).
+			Msg("Account traffic budget exceeded, removing from rotation")
+		m.rotate()
+		m.emit(Notice{
+			Kind:    NoticeQuotaExceeded,
+			Account: acc,
+			Message: fmt.Sprintf("Account %s used up its %s traffic budget", accountLabel(acc), exceeded),
+		})
+	case exceeded == "" && acc.OverQuota.Load():
+		acc.OverQuota.Store(false)
+		m.logger.Info().
+			Str("debrid", m.debrid).
+			Str("account_token", utils.Mask(acc.Token)).
+			Msg("Account traffic budget reset, back in rotation")
+		m.rotate()
+		m.emit(Notice{
+			Kind:    NoticeQuotaRestored,
+			Account: acc,
+			Message: fmt.Sprintf("Account %s traffic budget reset", accountLabel(acc)),
+		})
+	}
+}
+
+// checkExpiry warns once per expiration date when premium is about to run out.
+func (m *Manager) checkExpiry(acc *Account, now time.Time) {
+	if m.budget.ExpiryWarning <= 0 || acc.Expiration.IsZero() || now.After(acc.Expiration) {
+		return
+	}
+	left := acc.Expiration.Sub(now)
+	if left > m.budget.ExpiryWarning {
+		return
+	}
+	acc.trafficMu.Lock()
+	warned := acc.expiryWarned.Equal(acc.Expiration)
+	acc.expiryWarned = acc.Expiration
+	acc.trafficMu.Unlock()
+	if warned {
+		return
+	}
+	days := int(left.Hours() / 24)
+	m.logger.Warn().
+		Str("debrid", m.debrid).
+		Str("account_token", utils.Mask(acc.Token)).
+		Time("expiration", acc.Expiration).
+		Msg("Account premium expires soon")
+	m.emit(Notice{
+		Kind:    NoticeExpiring,
+		Account: acc,
+		Message: fmt.Sprintf("Account %s premium expires in %d day(s), on %s", accountLabel(acc), days, acc.Expiration.Format(time.DateOnly)),
+	})
+}
+
+// rotate moves the current pointer to the next usable account, in config
+// order, when the current one left rotation.
+func (m *Manager) rotate() {
+	current := m.current.Load()
+	if current != nil && current.Usable() {
+		return
+	}
+	active := m.Active()
+	if len(active) == 0 {
+		m.current.Store(nil)
+		m.Current()
+		return
+	}
+	next := active[0]
+	if current != nil {
+		for _, acc := range active {
+			if acc.Index > current.Index {
+				next = acc
+				break
+			}
+		}
+	}
+	m.current.Store(next)
+}
+
+// SetTrafficStore registers where traffic counters are persisted and restores
+// the stored counters of every account.
+func (m *Manager) SetTrafficStore(store TrafficStore) {
+	m.store.Store(&store)
+	now := utils.Now()
+	for _, acc := range m.All() {
+		t, err := store.GetAccountTraffic(m.trafficKey(acc))
+		if err != nil || t == nil {
+			continue
+		}
+		acc.restoreTraffic(t)
+		m.checkBudget(acc, now)
+	}
+}
+
+// SaveTraffic persists the traffic counters of every account.
+func (m *Manager) SaveTraffic() {
+	store := m.store.Load()
+	if store == nil || *store == nil {
+		return
+	}
+	for _, acc := range m.All() {
+		if err := (*store).SaveAccountTraffic(m.trafficKey(acc), acc.trafficState()); err != nil {
+			m.logger.Debug().Err(err).Str("debrid", m.debrid).Str("account_token", utils.Mask(acc.Token)).Msg("Failed to save account traffic")
+		}
+	}
+}
+
+// trafficKey identifies an account in the store without keeping its token.
+func (m *Manager) trafficKey(acc *Account) string {
+	sum := sha256.Sum256([]byte(acc.Token))
+	return m.debrid + ":" + hex.EncodeToString(sum[:8])
+}
+
+func accountLabel(acc *Account) string {
+	if acc.Username != "" {
+		return acc.Username
+	}
+	return utils.Mask(acc.Token)
+}
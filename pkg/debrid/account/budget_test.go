@@ -0,0 +1,92 @@
+package account
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRollTraffic(t *testing.T) {
+	at := func(s string) time.Time {
+		tm, err := time.ParseInLocation(time.DateTime, s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+	tests := []struct {
+		name        string
+		now         string
+		wantDaily   int64
+		wantMonthly int64
+	}{
+		{name: "same day", now: "2026-01-30 23:59:59", wantDaily: 10, wantMonthly: 100},
+		{name: "next day", now: "2026-01-31 00:00:00", wantDaily: 0, wantMonthly: 100},
+		{name: "next month", now: "2026-02-01 00:00:00", wantDaily: 0, wantMonthly: 0},
+		{name: "same day a year later", now: "2027-01-30 12:00:00", wantDaily: 0, wantMonthly: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Account{trafficDay: "2026-01-30", trafficMonth: "2026-01", dailyBytes: 10, monthlyBytes: 100}
+			now := at(tt.now)
+			a.rollTraffic(now)
+			if a.dailyBytes != tt.wantDaily || a.monthlyBytes != tt.wantMonthly {
+				t.Errorf("after roll = %d/%d, want %d/%d", a.dailyBytes, a.monthlyBytes, tt.wantDaily, tt.wantMonthly)
+			}
+			if a.trafficDay != now.Format(time.DateOnly) || a.trafficMonth != now.Format("2006-01") {
+				t.Errorf("window = %s/%s, want the one of %s", a.trafficDay, a.trafficMonth, tt.now)
+			}
+		})
+	}
+}
+
+func TestTrafficUsage(t *testing.T) {
+	// Each step at its time either counts bytes served, takes the provider's
+	// daily figure (observe >= 0), or just reads the usage.
+	steps := []struct {
+		at          string
+		add         int64
+		observe     int64
+		wantDaily   int64
+		wantMonthly int64
+	}{
+		{at: "2026-01-31 10:00:00", add: 100, observe: -1, wantDaily: 100, wantMonthly: 100},
+		// 50 bytes downloaded elsewhere
+		{at: "2026-01-31 12:00:00", observe: 150, wantDaily: 150, wantMonthly: 150},
+		// Seen again, not counted twice
+		{at: "2026-01-31 13:00:00", observe: 150, wantDaily: 150, wantMonthly: 150},
+		// Our own traffic the provider hasn't reported yet
+		{at: "2026-01-31 14:00:00", add: 10, observe: -1, wantDaily: 160, wantMonthly: 160},
+		{at: "2026-01-31 15:00:00", observe: 160, wantDaily: 160, wantMonthly: 160},
+		{at: "2026-02-01 01:00:00", observe: -1, wantDaily: 0, wantMonthly: 0},
+		{at: "2026-02-01 02:00:00", add: 20, observe: -1, wantDaily: 20, wantMonthly: 20},
+		{at: "2026-02-02 00:30:00", observe: -1, wantDaily: 0, wantMonthly: 20},
+		// The provider's daily figure reset
+		{at: "2026-02-02 01:00:00", observe: 0, wantDaily: 0, wantMonthly: 20},
+		{at: "2026-02-02 05:00:00", observe: 30, wantDaily: 30, wantMonthly: 50},
+		// The month keeps the external traffic of past days
+		{at: "2026-02-03 00:00:00", observe: -1, wantDaily: 0, wantMonthly: 50},
+	}
+	a := &Account{}
+	for _, s := range steps {
+		now, err := time.ParseInLocation(time.DateTime, s.at, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.add > 0 {
+			a.addTraffic(s.add, now)
+		}
+		if s.observe >= 0 {
+			a.ObserveTraffic(s.observe, now)
+		}
+		daily, monthly := a.TrafficUsage(now)
+		if daily != s.wantDaily || monthly != s.wantMonthly {
+			t.Errorf("%s: usage = %d/%d, want %d/%d", s.at, daily, monthly, s.wantDaily, s.wantMonthly)
+		}
+	}
+
+	// The folded traffic is what gets persisted
+	state := a.trafficState()
+	if state.Daily != 0 || state.Monthly != 50 {
+		t.Errorf("persisted = %d/%d, want 0/50", state.Daily, state.Monthly)
+	}
+}
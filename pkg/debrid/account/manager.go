@@ -25,6 +25,9 @@ type Manager struct {
 	debrid   string
 	current  atomic.Pointer[Account]
 	accounts *xsync.Map[string, *Account]
+	budget   Budget
+	notify   atomic.Pointer[NoticeFunc]
+	store    atomic.Pointer[TrafficStore]
 	logger   zerolog.Logger
 }
 
@@ -32,6 +35,7 @@ func NewManager(debridConf config.Debrid, downloadRL ratelimit.Limiter, logger z
 	m := &Manager{
 		debrid:   debridConf.Name,
 		accounts: xsync.NewMap[string, *Account](),
+		budget:   budgetFromConfig(debridConf),
 		logger:   logger,
 	}
 	cfg := config.Get()
@@ -72,10 +76,12 @@ func NewManager(debridConf config.Debrid, downloadRL ratelimit.Limiter, logger z
 	return m
 }
 
+// Active returns the accounts in rotation: not disabled and within their
+// traffic budget.
 func (m *Manager) Active() []*Account {
 	activeAccounts := make([]*Account, 0)
 	m.accounts.Range(func(key string, acc *Account) bool {
-		if !acc.Disabled.Load() {
+		if acc.Usable() {
 			activeAccounts = append(activeAccounts, acc)
 		}
 		return true
@@ -103,7 +109,7 @@ func (m *Manager) All() []*Account {
 func (m *Manager) Current() *Account {
 	// Fast path - most common case
 	current := m.current.Load()
-	if current != nil && !current.Disabled.Load() {
+	if current != nil && current.Usable() {
 		return current
 	}
 
@@ -118,8 +124,16 @@ func (m *Manager) Current() *Account {
 			m.current.Store(nil)
 			return nil
 		}
-		m.current.Store(allAccounts[0])
-		return allAccounts[0]
+		fallback := allAccounts[0]
+		// An account over its budget still works, prefer it to a broken one
+		for _, acc := range allAccounts {
+			if !acc.Disabled.Load() {
+				fallback = acc
+				break
+			}
+		}
+		m.current.Store(fallback)
+		return fallback
 	}
 
 	newCurrent := activeAccounts[0]
@@ -228,16 +242,22 @@ func (m *Manager) Stats() []map[string]any {
 
 	for _, acc := range m.All() {
 		maskedToken := utils.Mask(acc.Token)
+		daily, monthly := acc.TrafficUsage(utils.Now())
 		accountDetail := map[string]any{
-			"in_use":       acc.Equals(m.Current()),
-			"order":        acc.Index,
-			"disabled":     acc.Disabled.Load(),
-			"token_masked": maskedToken,
-			"username":     acc.Username,
-			"traffic_used": acc.TrafficUsed.Load(),
-			"expiration":   acc.Expiration,
-			"links_count":  acc.DownloadLinksCount(),
-			"debrid":       acc.Debrid,
+			"in_use":                acc.Equals(m.Current()),
+			"order":                 acc.Index,
+			"disabled":              acc.Disabled.Load(),
+			"over_quota":            acc.OverQuota.Load(),
+			"token_masked":          maskedToken,
+			"username":              acc.Username,
+			"traffic_used":          acc.TrafficUsed.Load(),
+			"daily_traffic":         daily,
+			"daily_traffic_limit":   m.budget.Daily,
+			"monthly_traffic":       monthly,
+			"monthly_traffic_limit": m.budget.Monthly,
+			"expiration":            acc.Expiration,
+			"links_count":           acc.DownloadLinksCount(),
+			"debrid":                acc.Debrid,
 		}
 		stats = append(stats, accountDetail)
 	}
@@ -285,6 +305,7 @@ func (m *Manager) Sync(syncer SyncFunc) {
 		return true
 	})
 	wgPool.Wait()
+	m.CheckBudgets()
 }
 
 func (m *Manager) UpdateAccount(updatedAccount *Account) {
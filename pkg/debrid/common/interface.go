@@ -27,6 +27,19 @@ type Client interface {
 	GetAvailableSlots() (int, error)
 	SyncAccounts() // Updates each accounts details(like traffic, username, etc.)
 	DeleteLink(dl types.DownloadLink) error
+	Ping(ctx context.Context) error // Checks that the API answers for the current account
 	SpeedTest(ctx context.Context) types.SpeedTestResult
 	SupportsCheck() bool
+	SupportsFileSelection() bool // Whether only the files a FileSelection keeps are fetched
+}
+
+// NZBClient is implemented by debrids that also run a usenet service. An NZB
+// submitted this way is tracked and streamed like a torrent on the same debrid.
+type NZBClient interface {
+	// SubmitNZB uploads the NZB named by tr.Name and returns tr with the
+	// debrid's download ID set.
+	SubmitNZB(tr *types.Torrent, content []byte) (*types.Torrent, error)
+	// GetIngests lists torrents and usenet downloads together. Usenet
+	// downloads carry the same IDs SubmitNZB returns.
+	GetIngests() ([]*types.Torrent, error)
 }
@@ -454,7 +454,7 @@ func (ad *AllDebrid) fetchDownloadLink(account *account.Account, id string, file
 	now := time.Now()
 	dl := types.DownloadLink{
 		Debrid:       ad.config.Name,
-		Token:        ad.APIKey,
+		Token:        account.Token,
 		Link:         file.Link,
 		DownloadLink: link,
 		Id:           data.Data.Id,
@@ -643,6 +643,18 @@ func (ad *AllDebrid) DeleteLink(downloadLink types.DownloadLink) error {
 	return ad.accountsManager.DeleteDownloadLink(downloadLink, ad.deleteLink)
 }
 
+// Ping checks that the API answers for the current account
+func (ad *AllDebrid) Ping(_ context.Context) error {
+	resp, err := ad.doRequest("/user", nil, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // SpeedTest measures API latency and download speed using cached links
 func (ad *AllDebrid) SpeedTest(ctx context.Context) types.SpeedTestResult {
 	result := types.SpeedTestResult{
@@ -651,18 +663,13 @@ func (ad *AllDebrid) SpeedTest(ctx context.Context) types.SpeedTestResult {
 	}
 
 	start := time.Now()
-	resp, err := ad.doRequest("/user", nil, nil)
+	err := ad.Ping(ctx)
 	latency := time.Since(start)
 
 	if err != nil {
 		result.Error = fmt.Sprintf("latency test failed: %v", err)
 		return result
 	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		result.Error = fmt.Sprintf("latency test unexpected status: %d", resp.StatusCode)
-		return result
-	}
 	result.LatencyMs = latency.Milliseconds()
 
 	// Try to measure download speed using a cached link
@@ -709,3 +716,7 @@ func (ad *AllDebrid) SpeedTest(ctx context.Context) types.SpeedTestResult {
 func (ad *AllDebrid) SupportsCheck() bool {
 	return true
 }
+
+func (ad *AllDebrid) SupportsFileSelection() bool {
+	return false
+}
@@ -414,7 +414,7 @@ func (dl *DebridLink) fetchDownloadLink(account *account.Account, id string, fil
 	now := time.Now()
 	link := types.DownloadLink{
 		Debrid:       dl.config.Name,
-		Token:        dl.APIKey,
+		Token:        account.Token,
 		Filename:     file.Name,
 		Link:         file.Link,
 		DownloadLink: file.Link,
@@ -709,6 +709,18 @@ func (dl *DebridLink) DeleteLink(downloadLink types.DownloadLink) error {
 	return dl.accountsManager.DeleteDownloadLink(downloadLink, dl.deleteDownloadLink)
 }
 
+// Ping checks that the API answers for the current account
+func (dl *DebridLink) Ping(_ context.Context) error {
+	resp, err := dl.doGet("/account/infos", nil, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // SpeedTest measures API latency and download speed using cached links
 func (dl *DebridLink) SpeedTest(ctx context.Context) types.SpeedTestResult {
 	result := types.SpeedTestResult{
@@ -717,18 +729,13 @@ func (dl *DebridLink) SpeedTest(ctx context.Context) types.SpeedTestResult {
 	}
 
 	start := time.Now()
-	resp, err := dl.doGet("/account/infos", nil, nil)
+	err := dl.Ping(ctx)
 	latency := time.Since(start)
 
 	if err != nil {
 		result.Error = fmt.Sprintf("latency test failed: %v", err)
 		return result
 	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		result.Error = fmt.Sprintf("latency test unexpected status: %d", resp.StatusCode)
-		return result
-	}
 	result.LatencyMs = latency.Milliseconds()
 
 	// Try to measure download speed using a cached link
@@ -775,3 +782,7 @@ func (dl *DebridLink) SpeedTest(ctx context.Context) types.SpeedTestResult {
 func (dl *DebridLink) SupportsCheck() bool {
 	return true
 }
+
+func (dl *DebridLink) SupportsFileSelection() bool {
+	return false
+}
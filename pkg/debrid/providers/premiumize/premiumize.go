@@ -35,7 +35,10 @@ const (
 	profileCacheDuration = time.Hour
 )
 
-var _ common.Client = (*Premiumize)(nil)
+var (
+	_ common.Client    = (*Premiumize)(nil)
+	_ common.NZBClient = (*Premiumize)(nil)
+)
 
 type Premiumize struct {
 	Host                  string `json:"host"`
@@ -171,13 +174,27 @@ func (pm *Premiumize) addMagnet(t *types.Torrent) (*types.Torrent, error) {
 }
 
 func (pm *Premiumize) addTorrent(t *types.Torrent) (*types.Torrent, error) {
+	return pm.uploadTransfer(t, cmp.Or(t.Filename, t.Magnet.Name, "upload.torrent"), t.Magnet.File)
+}
+
+// SubmitNZB creates a usenet transfer. Premiumize tracks it like any other
+// transfer, so status, files and links go through the transfer endpoints.
+func (pm *Premiumize) SubmitNZB(t *types.Torrent, content []byte) (*types.Torrent, error) {
+	filename := cmp.Or(t.Name, "upload")
+	if !strings.HasSuffix(strings.ToLower(filename), ".nzb") {
+		filename += ".nzb"
+	}
+	return pm.uploadTransfer(t, filename, content)
+}
+
+func (pm *Premiumize) uploadTransfer(t *types.Torrent, filename string, content []byte) (*types.Torrent, error) {
 	var body bytes.Buffer
 	writer := multipart.NewWriter(&body)
-	part, err := writer.CreateFormFile("src", cmp.Or(t.Filename, t.Magnet.Name, "upload.torrent"))
+	part, err := writer.CreateFormFile("src", filename)
 	if err != nil {
 		return nil, err
 	}
-	if _, err := part.Write(t.Magnet.File); err != nil {
+	if _, err := part.Write(content); err != nil {
 		return nil, err
 	}
 	if err := writer.Close(); err != nil {
@@ -207,7 +224,11 @@ func (pm *Premiumize) applySubmittedTorrent(t *types.Torrent, data transferCreat
 		t.Filename = data.Name
 	}
 	if t.OriginalFilename == "" {
-		t.OriginalFilename = cmp.Or(t.Magnet.Name, t.Name)
+		var magnetName string
+		if t.Magnet != nil {
+			magnetName = t.Magnet.Name
+		}
+		t.OriginalFilename = cmp.Or(magnetName, t.Name)
 	}
 }
 
@@ -329,6 +350,12 @@ func (pm *Premiumize) GetTorrents() ([]*types.Torrent, error) {
 	return torrents, nil
 }
 
+// GetIngests lists every transfer. NZB transfers share the torrent transfer
+// list on Premiumize, so this is GetTorrents.
+func (pm *Premiumize) GetIngests() ([]*types.Torrent, error) {
+	return pm.GetTorrents()
+}
+
 func (pm *Premiumize) listTransfers() ([]premiumizeTransfer, error) {
 	var data transferListResponse
 	req, err := http.NewRequest(http.MethodGet, pm.endpoint("/api/transfer/list"), nil)
@@ -634,28 +661,35 @@ func (pm *Premiumize) DeleteLink(downloadLink types.DownloadLink) error {
 	})
 }
 
+// Ping checks that the API answers for the current account
+func (pm *Premiumize) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pm.endpoint("/api/account/info"), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := pm.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
 func (pm *Premiumize) SpeedTest(ctx context.Context) types.SpeedTestResult {
 	result := types.SpeedTestResult{
 		Provider: pm.config.Name,
 		TestedAt: time.Now(),
 	}
 	start := time.Now()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pm.endpoint("/api/account/info"), nil)
-	if err != nil {
-		result.Error = err.Error()
-		return result
-	}
-	resp, err := pm.client.Do(req)
+	err := pm.Ping(ctx)
 	latency := time.Since(start)
 	if err != nil {
 		result.Error = fmt.Sprintf("latency test failed: %v", err)
 		return result
 	}
-	resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		result.Error = fmt.Sprintf("latency test unexpected status: %d", resp.StatusCode)
-		return result
-	}
 	result.LatencyMs = latency.Milliseconds()
 
 	current := pm.accountsManager.Current()
@@ -666,7 +700,7 @@ func (pm *Premiumize) SpeedTest(ctx context.Context) types.SpeedTestResult {
 	if !found || link.DownloadLink == "" {
 		return result
 	}
-	req, err = http.NewRequestWithContext(ctx, http.MethodGet, link.DownloadLink, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.DownloadLink, nil)
 	if err != nil {
 		return result
 	}
@@ -693,6 +727,10 @@ func (pm *Premiumize) SupportsCheck() bool {
 	return true
 }
 
+func (pm *Premiumize) SupportsFileSelection() bool {
+	return false
+}
+
 func mapStatus(status string) types.TorrentStatus {
 	switch strings.ToLower(status) {
 	case "finished", "seeding":
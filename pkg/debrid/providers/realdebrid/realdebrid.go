@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -392,7 +393,8 @@ func (r *RealDebrid) handleRarArchive(t *types.Torrent, data torrentInfo, select
 func (r *RealDebrid) getTorrentFiles(t *types.Torrent, data torrentInfo) map[string]types.File {
 	files := make(map[string]types.File)
 	cfg := config.Get()
-	idx := 0
+	candidates := make([]types.File, 0, len(data.Files))
+	selection := make([]config.SelectionFile, 0, len(data.Files))
 
 	for _, f := range data.Files {
 		name := filepath.Base(f.Path)
@@ -400,15 +402,27 @@ func (r *RealDebrid) getTorrentFiles(t *types.Torrent, data torrentInfo) map[str
 			continue
 		}
 
-		file := types.File{
+		candidates = append(candidates, types.File{
 			TorrentId: t.Id,
 			Name:      name,
 			Path:      name,
 			Size:      f.Bytes,
 			Id:        strconv.Itoa(f.ID),
+		})
+		selection = append(selection, config.SelectionFile{Name: name, Path: strings.TrimPrefix(f.Path, "/"), Size: f.Bytes})
+	}
+
+	// Only the files the selection keeps are requested from RD; if it keeps
+	// none, fall back to every allowed file as the other providers do.
+	keep := t.FileSelection.Select(selection)
+	if !slices.Contains(keep, true) {
+		keep = nil
+	}
+	for i, file := range candidates {
+		if keep != nil && !keep[i] {
+			continue
 		}
-		files[name] = file
-		idx++
+		files[file.Name] = file
 	}
 	return files
 }
@@ -1060,12 +1074,13 @@ func (r *RealDebrid) syncAccount(acc *account.Account) error {
 		return nil
 	}
 
+	now := time.Now()
 	if len(trafficData) == 0 {
-		acc.TrafficUsed.Store(0)
+		acc.ObserveTraffic(0, now)
 	} else {
-		today := time.Now().Format(time.DateOnly)
+		today := now.Format(time.DateOnly)
 		if todayData, exists := trafficData[today]; exists {
-			acc.TrafficUsed.Store(todayData.Bytes)
+			acc.ObserveTraffic(todayData.Bytes, now)
 		}
 	}
 	return nil
@@ -1087,6 +1102,18 @@ func (r *RealDebrid) DeleteLink(downloadLink types.DownloadLink) error {
 	return r.accountsManager.DeleteDownloadLink(downloadLink, r.deleteDownloadLink)
 }
 
+// Ping checks that the API answers for the current account
+func (r *RealDebrid) Ping(_ context.Context) error {
+	resp, err := r.doGet("/user", nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // SpeedTest measures API latency and download speed using cached links
 func (r *RealDebrid) SpeedTest(ctx context.Context) types.SpeedTestResult {
 	result := types.SpeedTestResult{
@@ -1094,20 +1121,14 @@ func (r *RealDebrid) SpeedTest(ctx context.Context) types.SpeedTestResult {
 		TestedAt: time.Now(),
 	}
 
-	// Measure latency by hitting the user endpoint
 	start := time.Now()
-	resp, err := r.doGet("/user", nil)
+	err := r.Ping(ctx)
 	latency := time.Since(start)
 
 	if err != nil {
 		result.Error = fmt.Sprintf("latency test failed: %v", err)
 		return result
 	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		result.Error = fmt.Sprintf("latency test unexpected status: %d", resp.StatusCode)
-		return result
-	}
 	result.LatencyMs = latency.Milliseconds()
 
 	// Try to measure download speed using a cached link
@@ -1155,3 +1176,7 @@ func (r *RealDebrid) SpeedTest(ctx context.Context) types.SpeedTestResult {
 func (r *RealDebrid) SupportsCheck() bool {
 	return true
 }
+
+func (r *RealDebrid) SupportsFileSelection() bool {
+	return true
+}
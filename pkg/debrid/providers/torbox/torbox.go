@@ -25,11 +25,17 @@ import (
 	"github.com/sirrobot01/decypharr/internal/request"
 	"github.com/sirrobot01/decypharr/internal/utils"
 	"github.com/sirrobot01/decypharr/pkg/debrid/account"
+	"github.com/sirrobot01/decypharr/pkg/debrid/common"
 	"github.com/sirrobot01/decypharr/pkg/debrid/types"
 	"github.com/sirrobot01/decypharr/pkg/version"
 	"go.uber.org/ratelimit"
 )
 
+var (
+	_ common.Client    = (*Torbox)(nil)
+	_ common.NZBClient = (*Torbox)(nil)
+)
+
 var planSlots = map[string]int{
 	"essential": 3,
 	"standard":  5,
@@ -283,7 +289,8 @@ func (tb *Torbox) getTorboxStatus(status string, finished bool) types.TorrentSta
 func (tb *Torbox) GetTorrent(torrentId string) (*types.Torrent, error) {
 	var res InfoResponse
 
-	resp, err := tb.doGet("/api/torrents/mylist", map[string]string{"id": torrentId}, &res)
+	endpoint, id := listEndpoint(torrentId)
+	resp, err := tb.doGet(endpoint, map[string]string{"id": id}, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -296,7 +303,7 @@ func (tb *Torbox) GetTorrent(torrentId string) (*types.Torrent, error) {
 		return nil, fmt.Errorf("error getting torrent")
 	}
 	t := &types.Torrent{
-		Id:               strconv.Itoa(data.Id),
+		Id:               torrentId,
 		Name:             data.Name,
 		Bytes:            data.Size,
 		Progress:         data.Progress * 100,
@@ -372,7 +379,8 @@ func (tb *Torbox) loadDownloadPresent() error {
 func (tb *Torbox) UpdateTorrent(t *types.Torrent) error {
 	var res InfoResponse
 
-	resp, err := tb.doGet("/api/torrents/mylist", map[string]string{"id": t.Id}, &res)
+	endpoint, id := listEndpoint(t.Id)
+	resp, err := tb.doGet(endpoint, map[string]string{"id": id}, &res)
 	if err != nil {
 		return err
 	}
@@ -381,6 +389,9 @@ func (tb *Torbox) UpdateTorrent(t *types.Torrent) error {
 		return fmt.Errorf("torbox API error: Status: %d", resp.StatusCode)
 	}
 	data := res.Data
+	if data == nil {
+		return fmt.Errorf("error getting torrent")
+	}
 	name := data.Name
 
 	t.Name = name
@@ -391,7 +402,7 @@ func (tb *Torbox) UpdateTorrent(t *types.Torrent) error {
 	t.Seeders = data.Seeds
 	t.Filename = name
 	t.OriginalFilename = name
-	if data.Hash != "" {
+	if data.Hash != "" && !isUsenetID(t.Id) {
 		t.InfoHash = data.Hash
 	}
 	t.Debrid = tb.config.Name
@@ -458,6 +469,9 @@ func (tb *Torbox) CheckStatus(torrent *types.Torrent) (*types.Torrent, error) {
 }
 
 func (tb *Torbox) DeleteTorrent(torrentId string) error {
+	if isUsenetID(torrentId) {
+		return tb.deleteUsenetDownload(torrentId)
+	}
 	payload := map[string]string{"torrent_id": torrentId, "action": "Delete"}
 
 	resp, err := tb.doDelete(fmt.Sprintf("/api/torrents/controltorrent/%s", torrentId), payload)
@@ -480,11 +494,18 @@ func (tb *Torbox) GetDownloadLink(id string, file *types.File) (types.DownloadLi
 func (tb *Torbox) fetchDownloadLink(account *account.Account, id string, file *types.File) (types.DownloadLink, error) {
 	query := url.Values{}
 	query.Set("token", account.Token)
-	query.Set("torrent_id", id)
 	query.Set("file_id", file.Id)
 	query.Set("redirect", "true")
 
-	downloadURL := fmt.Sprintf("%s/api/torrents/requestdl?%s", tb.Host, query.Encode())
+	endpoint := "/api/torrents/requestdl"
+	if usenetID, ok := strings.CutPrefix(id, usenetIDPrefix); ok {
+		endpoint = "/api/usenet/requestdl"
+		query.Set("usenet_id", usenetID)
+	} else {
+		query.Set("torrent_id", id)
+	}
+
+	downloadURL := fmt.Sprintf("%s%s?%s", tb.Host, endpoint, query.Encode())
 
 	now := time.Now()
 
@@ -492,7 +513,7 @@ func (tb *Torbox) fetchDownloadLink(account *account.Account, id string, file *t
 	dl := types.DownloadLink{
 		Filename:     file.Name,
 		Size:         file.Size,
-		Token:        tb.APIKey,
+		Token:        account.Token,
 		Link:         file.Link,
 		DownloadLink: downloadURL,
 		Debrid:       tb.config.Name,
@@ -504,11 +525,28 @@ func (tb *Torbox) fetchDownloadLink(account *account.Account, id string, file *t
 }
 
 func (tb *Torbox) GetTorrents() ([]*types.Torrent, error) {
+	return tb.listDownloads("/api/torrents/mylist")
+}
+
+// GetIngests lists torrents and usenet downloads together.
+func (tb *Torbox) GetIngests() ([]*types.Torrent, error) {
+	torrents, err := tb.GetTorrents()
+	if err != nil {
+		return nil, err
+	}
+	usenet, err := tb.listDownloads("/api/usenet/mylist")
+	if err != nil {
+		return nil, err
+	}
+	return append(torrents, usenet...), nil
+}
+
+func (tb *Torbox) listDownloads(endpoint string) ([]*types.Torrent, error) {
 	offset := 0
 	allTorrents := make([]*types.Torrent, 0)
 
 	for {
-		torrents, err := tb.getTorrents(offset)
+		torrents, err := tb.getTorrents(endpoint, offset)
 		if err != nil {
 			break
 		}
@@ -521,10 +559,10 @@ func (tb *Torbox) GetTorrents() ([]*types.Torrent, error) {
 	return allTorrents, nil
 }
 
-func (tb *Torbox) getTorrents(offset int) ([]*types.Torrent, error) {
+func (tb *Torbox) getTorrents(endpoint string, offset int) ([]*types.Torrent, error) {
 	var res TorrentsListResponse
 
-	resp, err := tb.doGet("/api/torrents/mylist", map[string]string{"offset": fmt.Sprintf("%d", offset)}, &res)
+	resp, err := tb.doGet(endpoint, map[string]string{"offset": fmt.Sprintf("%d", offset)}, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -539,10 +577,14 @@ func (tb *Torbox) getTorrents(offset int) ([]*types.Torrent, error) {
 
 	torrents := make([]*types.Torrent, 0, len(*res.Data))
 	cfg := config.Get()
+	var idPrefix string
+	if strings.HasPrefix(endpoint, "/api/usenet/") {
+		idPrefix = usenetIDPrefix
+	}
 
 	for _, data := range *res.Data {
 		t := &types.Torrent{
-			Id:               strconv.Itoa(data.Id),
+			Id:               idPrefix + strconv.Itoa(data.Id),
 			Name:             data.Name,
 			Bytes:            data.Size,
 			Progress:         data.Progress * 100,
@@ -556,6 +598,10 @@ func (tb *Torbox) getTorrents(offset int) ([]*types.Torrent, error) {
 			Added:            data.CreatedAt,
 			InfoHash:         data.Hash,
 		}
+		if idPrefix != "" {
+			// A usenet download's hash is not an infohash
+			t.InfoHash = ""
+		}
 
 		for _, f := range data.Files {
 			fileName := filepath.Base(f.Name)
@@ -600,6 +646,9 @@ func (tb *Torbox) RefreshDownloadLinks() error {
 }
 
 func (tb *Torbox) CheckFile(ctx context.Context, infohash, link string) error {
+	if id, ok := strings.CutPrefix(link, "torbox://"); ok && isUsenetID(id) {
+		return tb.checkUsenetDownload(strings.SplitN(id, "/", 2)[0])
+	}
 	tb.downloadPresentMu.Lock()
 	if !tb.downloadPresentLoaded {
 		if err := tb.loadDownloadPresent(); err != nil {
@@ -709,6 +758,18 @@ func (tb *Torbox) DeleteLink(downloadLink types.DownloadLink) error {
 	return tb.accountsManager.DeleteDownloadLink(downloadLink, tb.deleteDownloadLink)
 }
 
+// Ping checks that the API answers for the current account
+func (tb *Torbox) Ping(_ context.Context) error {
+	resp, err := tb.doGet("/api/user/me", nil, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // SpeedTest measures API latency and download speed using cached links
 func (tb *Torbox) SpeedTest(ctx context.Context) types.SpeedTestResult {
 	result := types.SpeedTestResult{
@@ -717,18 +778,13 @@ func (tb *Torbox) SpeedTest(ctx context.Context) types.SpeedTestResult {
 	}
 
 	start := time.Now()
-	resp, err := tb.doGet("/api/user/me", nil, nil)
+	err := tb.Ping(ctx)
 	latency := time.Since(start)
 
 	if err != nil {
 		result.Error = fmt.Sprintf("latency test failed: %v", err)
 		return result
 	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		result.Error = fmt.Sprintf("latency test unexpected status: %d", resp.StatusCode)
-		return result
-	}
 	result.LatencyMs = latency.Milliseconds()
 
 	// Try to measure download speed using a cached link
@@ -775,3 +831,7 @@ func (tb *Torbox) SpeedTest(ctx context.Context) types.SpeedTestResult {
 func (tb *Torbox) SupportsCheck() bool {
 	return true
 }
+
+func (tb *Torbox) SupportsFileSelection() bool {
+	return false
+}
@@ -20,6 +20,11 @@ type AddMagnetResponse APIResponse[struct {
 	Hash string `json:"hash"`
 }]
 
+type AddUsenetResponse APIResponse[struct {
+	Id   int    `json:"usenetdownload_id"`
+	Hash string `json:"hash"`
+}]
+
 type torboxInfo struct {
 	Id              int       `json:"id"`
 	AuthId          string    `json:"auth_id"`
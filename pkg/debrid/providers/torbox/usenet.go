@@ -0,0 +1,129 @@
+package torbox
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	json "github.com/bytedance/sonic"
+
+	"github.com/sirrobot01/decypharr/internal/customerror"
+	"github.com/sirrobot01/decypharr/pkg/debrid/types"
+)
+
+// Usenet downloads live in their own ID space on TorBox. They are tracked with
+// this prefix so the torrent endpoints are never called with a usenet ID.
+const usenetIDPrefix = "usenet-"
+
+func isUsenetID(id string) bool {
+	return strings.HasPrefix(id, usenetIDPrefix)
+}
+
+// listEndpoint returns the mylist endpoint and the raw TorBox ID for a
+// torrent or usenet download ID.
+func listEndpoint(id string) (string, string) {
+	if usenetID, ok := strings.CutPrefix(id, usenetIDPrefix); ok {
+		return "/api/usenet/mylist", usenetID
+	}
+	return "/api/torrents/mylist", id
+}
+
+func (tb *Torbox) SubmitNZB(torrent *types.Torrent, content []byte) (*types.Torrent, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	filename := torrent.Name
+	if !strings.HasSuffix(strings.ToLower(filename), ".nzb") {
+		filename += ".nzb"
+	}
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(content); err != nil {
+		return nil, err
+	}
+	if err := writer.WriteField("name", torrent.Name); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, tb.Host+"/api/usenet/createusenetdownload", &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := tb.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("torbox API error: Status: %d", resp.StatusCode)
+	}
+	var data AddUsenetResponse
+	if err := json.ConfigDefault.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	if data.Data == nil {
+		return nil, fmt.Errorf("error adding nzb: %v", data.Error)
+	}
+	torrent.Id = usenetIDPrefix + strconv.Itoa(data.Data.Id)
+	torrent.Debrid = tb.config.Name
+	torrent.Added = time.Now()
+	return torrent, nil
+}
+
+func (tb *Torbox) deleteUsenetDownload(id string) error {
+	usenetID, _ := strings.CutPrefix(id, usenetIDPrefix)
+	data, err := json.Marshal(map[string]any{"usenet_id": usenetID, "operation": "delete"})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, tb.Host+"/api/usenet/controlusenetdownload", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := tb.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("torbox API error: Status: %d", resp.StatusCode)
+	}
+	tb.logger.Info().Msgf("Usenet download %s deleted from Torbox", id)
+	return nil
+}
+
+// checkUsenetDownload reports whether TorBox still holds the files of a usenet
+// download. The torrent check relies on a bulk cache of the torrent list,
+// which does not cover usenet downloads.
+func (tb *Torbox) checkUsenetDownload(id string) error {
+	var res InfoResponse
+	endpoint, usenetID := listEndpoint(id)
+	resp, err := tb.doGet(endpoint, map[string]string{"id": usenetID}, &res)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return customerror.HosterUnavailableError
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("torbox API error: Status: %d", resp.StatusCode)
+	}
+	if res.Data == nil || !res.Data.DownloadPresent {
+		return customerror.HosterUnavailableError
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package types
+
+import "time"
+
+// AccountTraffic is the persisted traffic budget state of a debrid account,
+// so counters survive restarts.
+type AccountTraffic struct {
+	Day          string    `json:"day"`   // YYYY-MM-DD of Daily
+	Month        string    `json:"month"` // YYYY-MM of Monthly
+	Daily        int64     `json:"daily"`
+	Monthly      int64     `json:"monthly"`
+	ExpiryWarned time.Time `json:"expiry_warned,omitzero"` // Expiration the last warning was sent for
+}
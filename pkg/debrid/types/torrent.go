@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/sirrobot01/decypharr/internal/config"
 	"github.com/sirrobot01/decypharr/internal/utils"
 	"github.com/sirrobot01/decypharr/pkg/arr"
 )
@@ -32,8 +33,9 @@ type Torrent struct {
 
 	Arr *arr.Arr `json:"arr"`
 
-	SizeDownloaded   int64 `json:"-"` // This is used for local download
-	DownloadUncached bool  `json:"-"`
+	SizeDownloaded   int64                 `json:"-"` // This is used for local download
+	DownloadUncached bool                  `json:"-"`
+	FileSelection    *config.FileSelection `json:"-"` // Files to select, on providers that support it
 
 	sync.Mutex
 }
@@ -70,6 +72,7 @@ func (t *Torrent) Copy() *Torrent {
 		Links:            append([]string{}, t.Links...),
 		Debrid:           t.Debrid,
 		Arr:              t.Arr,
+		FileSelection:    t.FileSelection.Clone(),
 	}
 }
 
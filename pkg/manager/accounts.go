@@ -0,0 +1,52 @@
+package manager
+
+import (
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/pkg/debrid/account"
+	"github.com/sirrobot01/decypharr/pkg/debrid/types"
+	"github.com/sirrobot01/decypharr/pkg/notifications"
+)
+
+// AccountStats returns each debrid's download accounts with their traffic
+// budget usage, keyed by debrid name.
+func (m *Manager) AccountStats() map[string][]map[string]any {
+	out := make(map[string][]map[string]any)
+	for _, dc := range config.Get().Debrids {
+		client := m.ProviderClient(dc.Name)
+		if client == nil {
+			continue
+		}
+		out[dc.Name] = client.AccountManager().Stats()
+	}
+	return out
+}
+
+// recordLinkTraffic charges bytes streamed through a download link to the
+// account that generated it.
+func (m *Manager) recordLinkTraffic(link types.DownloadLink, n int64) {
+	client := m.ProviderClient(link.Debrid)
+	if client == nil {
+		return
+	}
+	client.AccountManager().RecordTraffic(link.Token, n)
+}
+
+// notifyAccount forwards account lifecycle notices to the notification
+// service.
+func (m *Manager) notifyAccount(n account.Notice) {
+	if m.Notifications == nil {
+		return
+	}
+	event := notifications.Event{
+		Type:    config.EventAccountQuota,
+		Status:  "warning",
+		Message: "[" + n.Debrid + "] " + n.Message,
+	}
+	switch n.Kind {
+	case account.NoticeExpiring:
+		event.Type = config.EventAccountExpiring
+	case account.NoticeQuotaRestored:
+		event.Status = "success"
+	}
+	m.Notifications.Notify(event)
+}
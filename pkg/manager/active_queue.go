@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
@@ -26,9 +27,10 @@ func (m *Manager) restoreActiveDownloadJobs() {
 			continue
 		}
 		_ = m.SubmitJob(&Job{
-			ID:    entry.InfoHash,
-			Type:  jobTypeForEntry(entry),
-			Entry: entry,
+			ID:       entry.InfoHash,
+			Type:     jobTypeForEntry(entry),
+			Entry:    entry,
+			Priority: entry.Priority,
 		})
 	}
 
@@ -54,14 +56,14 @@ func (m *Manager) restoreActiveDownloadJobs() {
 }
 
 func jobTypeForEntry(entry *storage.Entry) JobType {
-	if entry != nil && entry.IsNZB() {
+	if entry != nil && entry.OnUsenet() {
 		return JobTypeNZB
 	}
 	return JobTypeTorrent
 }
 
 func (m *Manager) nzbNeedsReprocessing(entry *storage.Entry) bool {
-	if entry == nil || !entry.IsNZB() || m.usenet == nil {
+	if entry == nil || !entry.OnUsenet() || m.usenet == nil {
 		return false
 	}
 	meta, err := m.usenet.GetNZBHeader(entry.InfoHash)
@@ -82,6 +84,7 @@ func (m *Manager) rebuildQueuedTorrentJob(entry *storage.Entry) (*Job, error) {
 			Type:           JobTypeTorrent,
 			Entry:          entry,
 			ResumeExisting: true,
+			Priority:       entry.Priority,
 		}, nil
 	}
 
@@ -96,13 +99,14 @@ func (m *Manager) rebuildQueuedTorrentJob(entry *storage.Entry) (*Job, error) {
 		downloadFolderForEntry(m.config.DownloadFolder, entry),
 		magnet,
 		m.arr.GetOrCreate(entry.Category),
-		entry.Action,
+		entryPolicy(entry),
 		&downloadUncached,
 		entry.CallbackURL,
 		ImportTypeAPI,
-		entry.SkipMultiSeason,
 	)
 	req.Id = entry.InfoHash
+	req.FileSelection = entry.FileSelection.Clone()
+	req.Priority = entry.Priority
 	job := NewJob(JobTypeTorrent, req)
 	job.ID = entry.InfoHash
 	job.Entry = entry
@@ -135,7 +139,7 @@ func (m *Manager) rebuildQueuedNZBJob(entry *storage.Entry) (*Job, error) {
 	}
 
 	entry.Magnet = ""
-	entry.Name = meta.Name
+	entry.Name = cmp.Or(entry.NameOverride, meta.Name)
 	entry.OriginalFilename = meta.Name
 	entry.Size = meta.TotalSize
 	entry.Bytes = meta.TotalSize
@@ -148,12 +152,13 @@ func (m *Manager) rebuildQueuedNZBJob(entry *storage.Entry) (*Job, error) {
 		downloadFolderForEntry(m.config.DownloadFolder, entry),
 		content,
 		m.arr.GetOrCreate(entry.Category),
-		entry.Action,
+		entryPolicy(entry),
 		entry.CallbackURL,
 		ImportTypeSABnzbd,
-		entry.SkipMultiSeason,
 	)
 	req.Id = entry.InfoHash
+	req.FileSelection = entry.FileSelection.Clone()
+	req.Priority = entry.Priority
 	job := NewJob(JobTypeNZB, req)
 	job.ID = entry.InfoHash
 	job.Entry = entry
@@ -168,3 +173,12 @@ func downloadFolderForEntry(fallback string, entry *storage.Entry) string {
 	}
 	return fallback
 }
+
+// entryPolicy is the category profile for a queued entry, keeping the action
+// and season handling it was queued with
+func entryPolicy(entry *storage.Entry) config.Policy {
+	policy := config.Get().PolicyFor(entry.Category)
+	policy.DownloadAction = entry.Action
+	policy.SkipMultiSeason = entry.SkipMultiSeason
+	return policy
+}
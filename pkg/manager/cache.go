@@ -71,6 +71,11 @@ func (e *EntryCache) _refreshEntry(name string) EntryCacheItem {
 	return item
 }
 
+// Invalidate drops the cached file listing of a single entry folder.
+func (e *EntryCache) Invalidate(folder string) {
+	e.entries.Delete(torrentEntryCachePrefix + folder)
+}
+
 // Refresh triggers a cache refresh with debouncing.
 // If called multiple times rapidly, only one refresh will occur.
 func (e *EntryCache) Refresh() {
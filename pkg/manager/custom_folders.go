@@ -40,7 +40,13 @@ const (
 
 type CustomFolders struct {
 	filters map[string][]directoryFilter
+	exprs   map[string]*config.FolderExpr
 	folders []string
+
+	usesAge    bool // Membership changes as time passes
+	usesHealth bool // Membership depends on the health checker's status
+
+	index *folderIndex
 }
 
 type directoryFilter struct {
@@ -55,7 +61,21 @@ type directoryFilter struct {
 func (m *Manager) initCustomFolders() {
 	var customFolders []string
 	dirFilters := map[string][]directoryFilter{}
+	exprs := map[string]*config.FolderExpr{}
+	usesAge, usesHealth := false, false
 	for name, value := range m.config.CustomFolders {
+		customFolders = append(customFolders, name)
+		if value.Expr != "" {
+			expr, err := config.ParseFolderExpr(value.Expr)
+			if err != nil {
+				m.logger.Error().Err(err).Str("folder", name).Msg("Invalid custom folder expression, folder stays empty")
+				continue
+			}
+			exprs[name] = expr
+			usesAge = usesAge || expr.Uses(config.FolderFieldAge)
+			usesHealth = usesHealth || expr.Uses(config.FolderFieldHealth)
+			continue
+		}
 		for filterType, v := range value.Filters {
 			df := directoryFilter{filterType: filterType, value: v}
 			switch filterType {
@@ -65,23 +85,54 @@ func (m *Manager) initCustomFolders() {
 				df.sizeThreshold, _ = config.ParseSize(v)
 			case filterBLastAdded:
 				df.ageThreshold, _ = utils.ParseDuration(v)
+				usesAge = true
 			case filterByFileCountGT, filterByFileCountLT:
 				_, _ = fmt.Sscanf(v, "%d", &df.countThreshold)
 			}
 			dirFilters[name] = append(dirFilters[name], df)
 		}
-		customFolders = append(customFolders, name)
 	}
 	m.customFolders = &CustomFolders{
-		filters: dirFilters,
-		folders: customFolders,
+		filters:    dirFilters,
+		exprs:      exprs,
+		folders:    customFolders,
+		usesAge:    usesAge,
+		usesHealth: usesHealth,
 	}
+	m.customFolders.index = newFolderIndex(m, m.customFolders)
+	m.storage.SetEntryObserver(m.customFolders.index)
 }
 
 func (m *Manager) GetCustomFolders() []string {
 	return m.customFolders.folders
 }
 
+// matches reports whether an entry belongs in a folder: routed there by a tag
+// rule, or else by the folder's expression or legacy filters. Legacy filters
+// never take bad entries.
+func (cf *CustomFolders) matches(folderName string, subject *config.FolderSubject, now time.Time) bool {
+	if slices.Contains(subject.Folders, folderName) {
+		return true
+	}
+	if expr, ok := cf.exprs[folderName]; ok {
+		return expr.Match(subject, now)
+	}
+	if len(cf.filters[folderName]) == 0 || subject.Bad {
+		return false
+	}
+	getFileNames := func() []string {
+		names := make([]string, 0, len(subject.Files))
+		for _, fn := range subject.Files {
+			names = append(names, strings.ToLower(fn))
+		}
+		return names
+	}
+	return cf.matchesFilter(folderName, &FileInfo{
+		name: subject.Name,
+		size: subject.Size,
+	}, subject.AddedOn, getFileNames)
+}
+
 // matchesFilter checks if a torrent matches all filters for a folder.
 // getFileNames is a lazy loader called only when files_regex/not_files_regex/file_count filters are needed.
 func (cf *CustomFolders) matchesFilter(folderName string, fileInfo os.FileInfo, addedTime time.Time, getFileNames func() []string) bool {
@@ -37,6 +37,8 @@ func (m *Manager) initDebridClients() {
 			m.logger.Error().Err(err).Str("debrid", dc.Name).Msg("Failed to create debrid client")
 			continue
 		}
+		client.AccountManager().SetNotifier(m.notifyAccount)
+		client.AccountManager().SetTrafficStore(m.storage)
 		m.clients.Store(dc.Name, client)
 	}
 }
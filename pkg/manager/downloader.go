@@ -136,6 +136,7 @@ func (d *Downloader) markAsCompleted(entry *storage.Entry) {
 	// Mark as completed
 	entry.MarkAsCompleted(entry.DownloadPath())
 	_ = d.manager.queue.Update(entry)
+	publishQueue(QueueEventCompleted, entry)
 }
 
 func (d *Downloader) notifyCompleted(entry *storage.Entry) {
@@ -169,6 +170,7 @@ func (d *Downloader) markAsError(entry *storage.Entry, err error) {
 	d.logger.Error().Err(err).Str("name", entry.Name).Msg("Failed to process action")
 	entry.MarkAsError(err)
 	_ = d.manager.queue.Update(entry)
+	publishQueue(QueueEventFailed, entry)
 
 	// Send error notification
 	msg := fmt.Sprintf("Download failed: %s [%s] - %s", entry.Name, entry.Category, err.Error())
@@ -197,6 +199,7 @@ func (d *Downloader) processSymlink(entry *storage.Entry, mountPath string) erro
 	if err != nil {
 		return err
 	}
+	d.manager.RecordEvent(entry.InfoHash, storage.EntryEventSymlinks, entry.ActiveProvider, fmt.Sprintf("%d symlinks created in %s", len(filePaths), torrentSymlinkPath))
 
 	entry.IsDownloading = true
 	_ = d.manager.queue.Update(entry)
@@ -209,7 +212,7 @@ func (d *Downloader) processSymlink(entry *storage.Entry, mountPath string) erro
 	// Usenet parsing/probing deliberately avoids the streaming read-ahead
 	// setting. A large playback window can turn a small import probe into a
 	// substantial background download and hold an active slot unnecessarily.
-	if !entry.IsNZB() && !d.manager.config.SkipPreCache && len(filePaths) > 0 {
+	if !entry.OnUsenet() && !d.manager.config.SkipPreCache && len(filePaths) > 0 {
 		probeFiles := filePaths
 		if len(probeFiles) > MaxNZBPreCacheFiles {
 			probeFiles = probeFiles[:MaxNZBPreCacheFiles]
@@ -465,7 +468,7 @@ func limitedStringSample(values []string, limit int) []string {
 // For NZBs: uses parallel NNTP segment download
 func (d *Downloader) processDownload(entry *storage.Entry) error {
 	// Check if this is a usenet entry
-	if entry.IsNZB() {
+	if entry.OnUsenet() {
 		return d.processUsenetDownload(entry)
 	}
 	return d.processTorrentDownload(entry)
@@ -600,7 +603,8 @@ func (d *Downloader) processUsenetDownload(entry *storage.Entry) error {
 				_ = d.manager.queue.Update(entry)
 			}
 
-			if err := d.manager.usenet.Download(d.manager.ctx, entry.InfoHash, file.Name, destFile, progressCallback); err != nil {
+			writer := d.manager.speedLimit.writer(d.manager.ctx, destFile)
+			if err := d.manager.usenet.Download(d.manager.ctx, entry.InfoHash, file.Name, writer, progressCallback); err != nil {
 				_ = os.Remove(destPath)
 				return fmt.Errorf("failed to download %s: %w", file.Name, err)
 			}
@@ -636,8 +640,17 @@ func (d *Downloader) processStrm(torrent *storage.Entry) error {
 		return fmt.Errorf("failed to create directory: %s: %v", torrentSymlinkPath, err)
 	}
 
+	if err := d.writeStrmFiles(torrent, files, torrentSymlinkPath); err != nil {
+		return err
+	}
+	d.completeEntry(torrent)
+	d.logger.Info().Str("destination", torrentSymlinkPath).Msgf("Created .strm files for %s", torrent.Name)
+	return nil
+}
+
+func (d *Downloader) writeStrmFiles(torrent *storage.Entry, files []*storage.File, dir string) error {
 	for _, file := range files {
-		strmFilePath := filepath.Join(torrentSymlinkPath, file.Name+".strm")
+		strmFilePath := filepath.Join(dir, file.Name+".strm")
 		streamURL, err := url.JoinPath(
 			d.strmURL,
 			"webdav",
@@ -653,8 +666,56 @@ func (d *Downloader) processStrm(torrent *storage.Entry) error {
 			return fmt.Errorf("failed to create .strm file: %s: %v", strmFilePath, err)
 		}
 	}
-	d.completeEntry(torrent)
-	d.logger.Info().Str("destination", torrentSymlinkPath).Msgf("Created .strm files for %s", torrent.Name)
+	return nil
+}
+
+// relink rebuilds the local folder of a completed entry after a rename or a
+// move. Symlinks and .strm files point into the mount by folder name, so they
+// are written afresh; downloaded files are moved. renamed maps old to new file
+// names. An empty oldDir means there is nothing on disk to clear.
+func (d *Downloader) relink(entry *storage.Entry, oldDir string, renamed map[string]string) error {
+	newDir := entry.DownloadPath()
+	switch entry.Action {
+	case config.DownloadActionNone:
+		return nil
+	case config.DownloadActionDownload:
+		if oldDir != "" && oldDir != newDir {
+			if err := os.MkdirAll(filepath.Dir(newDir), os.ModePerm); err != nil {
+				return fmt.Errorf("failed to create directory: %s: %v", filepath.Dir(newDir), err)
+			}
+			if err := os.Rename(oldDir, newDir); err != nil {
+				return fmt.Errorf("failed to move %s to %s: %w", oldDir, newDir, err)
+			}
+		}
+		for oldName, newName := range renamed {
+			err := os.Rename(filepath.Join(newDir, oldName), filepath.Join(newDir, newName))
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to rename %s: %w", oldName, err)
+			}
+		}
+		return nil
+	}
+
+	if oldDir != "" {
+		if err := os.RemoveAll(oldDir); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", oldDir, err)
+		}
+	}
+	if err := os.MkdirAll(newDir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create directory: %s: %v", newDir, err)
+	}
+	files := entry.GetActiveFiles()
+	if entry.Action == config.DownloadActionStrm {
+		return d.writeStrmFiles(entry, files, newDir)
+	}
+	mountPath := d.manager.GetTorrentMountPath(entry)
+	for _, file := range files {
+		link := filepath.Join(newDir, file.Name)
+		if err := os.Symlink(filepath.Join(mountPath, file.Name), link); err != nil && !os.IsExist(err) {
+			return fmt.Errorf("failed to create symlink %s: %w", link, err)
+		}
+	}
+	d.logger.Info().Str("destination", newDir).Msgf("Relinked %d files for %s", len(files), entry.Name)
 	return nil
 }
 
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/sirrobot01/decypharr/internal/utils"
@@ -33,6 +32,7 @@ type FileInfo struct {
 	canDelete    bool
 	byteRange    *[2]int64
 	infohash     string
+	media        *storage.MediaInfo
 	sys          any // For caching fuse nodes
 }
 
@@ -51,6 +51,10 @@ func (f *FileInfo) IsRemote() bool       { return len(f.content) == 0 }
 func (f *FileInfo) ByteRange() *[2]int64 { return f.byteRange }
 func (f *FileInfo) InfoHash() string     { return f.infohash }
 
+// Media returns the container probe result for a file, or nil if the file
+// has not been probed.
+func (f *FileInfo) Media() *storage.MediaInfo { return f.media }
+
 // GetTorrentMountPath returns the full mount path for a torrent
 // Returns the path based on the new unified mount structure
 func (m *Manager) GetTorrentMountPath(torrent *storage.Entry) string {
@@ -189,6 +193,7 @@ func (m *Manager) GetTorrentFile(torrentName, fileName string) (*FileInfo, error
 		parent:    entry.Name,
 		canDelete: true,
 		byteRange: file.ByteRange,
+		media:     file.Media,
 	}, nil
 }
 
@@ -362,6 +367,7 @@ func (m *Manager) getTorrentChildren(name string) (*FileInfo, []FileInfo) {
 			parent:    entry.Name,
 			canDelete: true,
 			byteRange: file.ByteRange,
+			media:     file.Media,
 		})
 		size += file.Size
 	}
@@ -405,7 +411,7 @@ func (m *Manager) RemoveEntry(entry *FileInfo) error {
 			}
 			infohash = firstFile.InfoHash
 		}
-		return m.DeleteEntry(infohash, true)
+		return m.DeleteEntry(infohash, true, true)
 	}
 	// This is a file within a torrent
 	return m.RemoveTorrentFile(entry.Parent(), entry.Name())
@@ -485,64 +491,18 @@ func (m *Manager) RemoveTorrentFile(torrentName, filename string) error {
 	// If the torrent has no more files, delete the entire entry
 	hasFiles := false
 	for _, f := range item.Files {
-		if !f.Deleted {
+		if f.Active() {
 			hasFiles = true
 			break
 		}
 	}
 	if !hasFiles {
 		m.logger.Debug().Str("entry", torrentName).Msg("Removing entry folder as it has no more files")
-		return m.DeleteEntry(file.InfoHash, true)
+		return m.DeleteEntry(file.InfoHash, true, true)
 	}
 	return nil
 }
 
 func (m *Manager) getCustomFolderChildren(folder string) []FileInfo {
-	filters := m.customFolders.filters[folder]
-	if len(filters) == 0 {
-		return nil
-	}
-
-	// Use metadata-only iteration (no disk reads)
-	var infos []FileInfo
-	seen := make(map[string]struct{})
-	err := m.storage.ForEachMeta(func(meta *storage.EntryMetaInfo) error {
-		if meta.Bad {
-			return nil
-		}
-		getFileNames := func() []string {
-			item, err := m.storage.GetEntryItem(meta.Name)
-			if err != nil || item == nil {
-				return nil
-			}
-			names := make([]string, 0, len(item.Files))
-			for fn := range item.Files {
-				names = append(names, strings.ToLower(fn))
-			}
-			return names
-		}
-		if m.customFolders.matchesFilter(folder, &FileInfo{
-			name: meta.Name,
-			size: meta.Size,
-		}, meta.AddedOn, getFileNames) {
-			if _, ok := seen[meta.Name]; ok {
-				return nil
-			}
-			seen[meta.Name] = struct{}{}
-			infos = append(infos, FileInfo{
-				infohash:     meta.InfoHash,
-				name:         meta.Name,
-				size:         meta.Size,
-				modTime:      meta.AddedOn,
-				isDir:        true,
-				activeDebrid: meta.Provider,
-				canDelete:    true,
-			})
-		}
-		return nil
-	})
-	if err != nil {
-		return nil
-	}
-	return infos
+	return m.customFolders.index.children(folder)
 }
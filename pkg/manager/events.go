@@ -0,0 +1,187 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/puzpuzpuz/xsync/v4"
+	"github.com/rs/zerolog"
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/logger"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+const (
+	defaultEventRetention = 30 * 24 * time.Hour
+	eventPruneInterval    = "6h"
+	arrImportScanInterval = "5m"
+)
+
+// eventRecorder appends to the per-entry timelines. Recording is best effort:
+// failures are logged and never fail the operation being recorded.
+type eventRecorder struct {
+	storage     *storage.Storage
+	logger      zerolog.Logger
+	disabled    bool
+	maxPerEntry int
+	retention   time.Duration
+
+	// Last recorded status per infohash, so queue updates that don't change
+	// anything aren't recorded
+	lastStatus *xsync.Map[string, string]
+}
+
+func newEventRecorder(strg *storage.Storage, cfg config.EventsConfig) *eventRecorder {
+	r := &eventRecorder{
+		storage:     strg,
+		logger:      logger.New("events"),
+		disabled:    cfg.Disabled,
+		maxPerEntry: cfg.MaxPerEntry,
+		retention:   defaultEventRetention,
+		lastStatus:  xsync.NewMap[string, string](),
+	}
+	if cfg.Retention != "" {
+		if retention, err := utils.ParseDuration(cfg.Retention); err != nil {
+			r.logger.Warn().Err(err).Str("retention", cfg.Retention).Msg("Invalid events retention, using default")
+		} else {
+			r.retention = retention
+		}
+	}
+	return r
+}
+
+func (r *eventRecorder) record(infohash string, typ storage.EntryEventType, provider, message string) {
+	if r == nil || r.disabled || infohash == "" {
+		return
+	}
+	ev := storage.EntryEvent{Time: time.Now(), Type: typ, Provider: provider, Message: message}
+	if err := r.storage.AppendEntryEvent(infohash, ev, r.maxPerEntry); err != nil {
+		r.logger.Debug().Err(err).Str("infohash", infohash).Str("type", string(typ)).Msg("Failed to record entry event")
+	}
+}
+
+// recordStatus records the entry's state when it differs from the last one
+// recorded. After a restart the last status is read back from the timeline.
+func (r *eventRecorder) recordStatus(entry *storage.Entry) {
+	if r == nil || r.disabled || entry == nil || entry.InfoHash == "" {
+		return
+	}
+	key := strings.ToLower(entry.InfoHash)
+	message := fmt.Sprintf("%s (%s)", entry.State, entry.Status)
+	if entry.State == storage.EntryStateError && entry.LastError != "" {
+		message += ": " + entry.LastError
+	}
+	previous, ok := r.lastStatus.Load(key)
+	if !ok {
+		previous = r.lastRecordedStatus(key)
+	}
+	r.lastStatus.Store(key, message)
+	if previous == message {
+		return
+	}
+	r.record(key, storage.EntryEventStatus, entry.ActiveProvider, message)
+}
+
+func (r *eventRecorder) lastRecordedStatus(infohash string) string {
+	events, err := r.storage.GetEntryEvents(infohash)
+	if err != nil {
+		return ""
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Type == storage.EntryEventStatus {
+			return events[i].Message
+		}
+	}
+	return ""
+}
+
+func (r *eventRecorder) forget(infohash string) {
+	if r == nil {
+		return
+	}
+	r.lastStatus.Delete(strings.ToLower(infohash))
+}
+
+// RecordEvent appends an event to an entry's timeline
+func (m *Manager) RecordEvent(infohash string, typ storage.EntryEventType, provider, message string) {
+	m.events.record(infohash, typ, provider, message)
+}
+
+// EntryEvents returns an entry's timeline, oldest first
+func (m *Manager) EntryEvents(infohash string) ([]storage.EntryEvent, error) {
+	return m.storage.GetEntryEvents(infohash)
+}
+
+// recordLinkFailure is the link service's failure hook
+func (m *Manager) recordLinkFailure(entry *storage.Entry, filename, provider string, err error) {
+	m.RecordEvent(entry.InfoHash, storage.EntryEventLinkFailed, provider, fmt.Sprintf("%s: %v", filename, err))
+}
+
+func (m *Manager) pruneEntryEvents() {
+	removed, err := m.storage.PruneEntryEvents(time.Now().Add(-m.events.retention))
+	if err != nil {
+		m.logger.Error().Err(err).Msg("Failed to prune entry events")
+		return
+	}
+	if removed > 0 {
+		m.logger.Debug().Int("removed", removed).Msg("Pruned old entry events")
+	}
+}
+
+// trackArrImports records an imported event for entries the arrs report as
+// imported. Only entries with a timeline are considered, and each download is
+// recorded once: an entry added again is tracked afresh, from imports after
+// it was added.
+func (m *Manager) trackArrImports() {
+	for _, a := range m.arr.GetAll() {
+		for id, importedAt := range a.RecentImports() {
+			if !m.storage.HasEntryEvents(id) {
+				continue
+			}
+			events, err := m.storage.GetEntryEvents(id)
+			if err != nil {
+				continue
+			}
+			if importPending(events, importedAt) {
+				m.RecordEvent(id, storage.EntryEventImported, "", fmt.Sprintf("imported by %s", a.Name))
+			}
+		}
+	}
+}
+
+// importPending reports whether an import at importedAt is new to a
+// timeline: it happened after the entry was last added, and no import has
+// been recorded since.
+func importPending(events []storage.EntryEvent, importedAt time.Time) bool {
+	for i := len(events) - 1; i >= 0; i-- {
+		switch events[i].Type {
+		case storage.EntryEventImported:
+			return false
+		case storage.EntryEventAdded:
+			return !importedAt.Before(events[i].Time)
+		}
+	}
+	return true
+}
+
+// scheduleEventJobs registers the timeline pruning and arr import tracking
+func (m *Manager) scheduleEventJobs(ctx context.Context) {
+	if m.events.disabled {
+		return
+	}
+	if jd, err := utils.ConvertToJobDef(eventPruneInterval); err != nil {
+		m.logger.Error().Err(err).Msg("Failed to convert event prune interval to job definition")
+	} else if _, err := m.scheduler.NewJob(jd, gocron.NewTask(m.pruneEntryEvents), gocron.WithContext(ctx), gocron.WithName("event-prune")); err != nil {
+		m.logger.Error().Err(err).Msg("Failed to create event prune job")
+	}
+
+	if jd, err := utils.ConvertToJobDef(arrImportScanInterval); err != nil {
+		m.logger.Error().Err(err).Msg("Failed to convert arr import scan interval to job definition")
+	} else if _, err := m.scheduler.NewJob(jd, gocron.NewTask(m.trackArrImports), gocron.WithContext(ctx), gocron.WithName("arr-import-events")); err != nil {
+		m.logger.Error().Err(err).Msg("Failed to create arr import tracking job")
+	}
+}
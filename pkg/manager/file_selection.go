@@ -0,0 +1,48 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// SetFilesSelected records a per-file choice, as made through the qBit filePrio
+// endpoint, and shows or hides the files in the mount. The choice sticks across
+// repairs and provider moves. Files a provider never fetched (deselected on
+// Real-Debrid at add time) and files removed by repair cannot be brought back
+// this way.
+func (m *Manager) SetFilesSelected(infohash string, names []string, selected bool) error {
+	update := func(entry *storage.Entry) {
+		if entry.FileSelection == nil {
+			entry.FileSelection = &config.FileSelection{}
+		}
+		for _, name := range names {
+			entry.FileSelection.Toggle(name, selected)
+			if file, ok := entry.Files[name]; ok {
+				file.Unselected = !selected
+			}
+		}
+	}
+
+	found := false
+	if entry, err := m.queue.GetTorrent(infohash); err == nil {
+		found = true
+		update(entry)
+		if err := m.queue.Update(entry); err != nil {
+			return fmt.Errorf("failed to update queued entry: %w", err)
+		}
+	}
+	if entry, err := m.storage.Get(infohash); err == nil {
+		found = true
+		update(entry)
+		if err := m.storage.AddOrUpdate(entry); err != nil {
+			return fmt.Errorf("failed to update entry: %w", err)
+		}
+		m.RefreshEntries(true)
+	}
+	if !found {
+		return fmt.Errorf("entry %s not found", infohash)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -103,6 +104,15 @@ func (f *Fixer) FixTorrent(ctx context.Context, entry *storage.Entry, skipCurren
 
 	// Build debrid attempt order: current debrid first, then others in config order
 	attemptOrder := f.buildAttemptOrder(entry, skipCurrent)
+	if len(attemptOrder) == 0 && entry.IsNZB() {
+		// Nowhere else to send the NZB; leave it to the Arr repair.
+		result := &FixResult{
+			Success: false,
+			Error:   fmt.Errorf("no other usenet provider for %s", entry.Name),
+		}
+		req.result <- result
+		return result, nil
+	}
 
 	var lastErr error
 	totalAttempts := 0
@@ -137,7 +147,8 @@ func (f *Fixer) FixTorrent(ctx context.Context, entry *storage.Entry, skipCurren
 		success, err := f.MoveTorrent(entry, debridName, reinsert)
 		totalAttempts++
 
-		if success {
+		// A pending NZB placement takes over in the background once ready
+		if success || errors.Is(err, ErrPlacementPending) {
 			f.manager.logger.Info().
 				Str("debrid", debridName).
 				Str("name", entry.Name).
@@ -188,7 +199,7 @@ func (f *Fixer) FixTorrent(ctx context.Context, entry *storage.Entry, skipCurren
 }
 
 // MoveTorrent attempts to re-insert a torrent on a specific debrid
-func (f *Fixer) MoveTorrent(entry *storage.Entry, debridName string, reinsert bool) (bool, error) {
+func (f *Fixer) MoveTorrent(entry *storage.Entry, debridName string, reinsert bool) (moved bool, err error) {
 	// Check if entry can be moved
 	if entry == nil {
 		return false, fmt.Errorf("entry is nil")
@@ -197,11 +208,24 @@ func (f *Fixer) MoveTorrent(entry *storage.Entry, debridName string, reinsert bo
 		return false, fmt.Errorf("entry %s cannot be moved", entry.Name)
 	}
 
+	from := entry.ActiveProvider
 	defer func() {
 		// Save to storage
 		_ = f.manager.AddOrUpdate(entry, nil) // No need to refresh mounts
+		switch {
+		case errors.Is(err, ErrPlacementPending):
+			f.manager.RecordEvent(entry.InfoHash, storage.EntryEventSubmitted, debridName, "placement pending, switching once ready")
+		case err != nil:
+			f.manager.RecordEvent(entry.InfoHash, storage.EntryEventSwitchFailed, debridName, fmt.Sprintf("switch from %s failed: %v", from, err))
+		case moved:
+			f.manager.RecordEvent(entry.InfoHash, storage.EntryEventSwitched, debridName, fmt.Sprintf("switched from %s", from))
+		}
 	}()
 
+	if entry.IsNZB() {
+		return f.moveNZB(entry, debridName, reinsert)
+	}
+
 	client := f.manager.ProviderClient(debridName)
 	if client == nil {
 		return false, fmt.Errorf("debrid client %s not found", debridName)
@@ -248,6 +272,7 @@ func (f *Fixer) MoveTorrent(entry *storage.Entry, debridName string, reinsert bo
 		Size:             entry.Size,
 		Files:            make(map[string]types.File),
 		DownloadUncached: false,
+		FileSelection:    entry.FileSelection,
 	}
 
 	newDebridTorrent, err = client.SubmitMagnet(newDebridTorrent)
@@ -290,28 +315,7 @@ func (f *Fixer) MoveTorrent(entry *storage.Entry, debridName string, reinsert bo
 
 	// Update entry with new placement
 	_ = entry.AddTorrentProvider(newDebridTorrent)
-	// Update global file metadata (revives files that previously existed)
-	if entry.Files == nil {
-		entry.Files = make(map[string]*storage.File)
-	}
-	for _, f := range newDebridTorrent.GetFiles() {
-		if existing, exists := entry.Files[f.Name]; exists {
-			existing.Size = f.Size
-			existing.ByteRange = f.ByteRange
-			existing.Deleted = false
-			existing.InfoHash = entry.InfoHash
-			existing.AddedOn = addedOn
-		} else {
-			entry.Files[f.Name] = &storage.File{
-				Name:      f.Name,
-				Size:      f.Size,
-				ByteRange: f.ByteRange,
-				Deleted:   false,
-				InfoHash:  entry.InfoHash,
-				AddedOn:   addedOn,
-			}
-		}
-	}
+	mergeEntryFiles(entry, newDebridTorrent.GetFiles(), addedOn)
 
 	// Activate this debrid
 	if err := entry.ActivatePlacement(debridName); err != nil {
@@ -331,10 +335,84 @@ func (f *Fixer) MoveTorrent(entry *storage.Entry, debridName string, reinsert bo
 	return true, nil
 }
 
+// moveNZB places an NZB entry on target, reusing a completed placement unless
+// reinsert is set. Unlike torrents, an NZB can move between the NNTP providers
+// ("usenet") and the debrid usenet services.
+func (f *Fixer) moveNZB(entry *storage.Entry, target string, reinsert bool) (bool, error) {
+	if !f.manager.acceptsNZB(target) {
+		return false, fmt.Errorf("%s does not accept NZBs", target)
+	}
+	if !reinsert {
+		if placement, ok := entry.Providers[target]; ok && placement != nil && placement.ID != "" && placement.Status == types.TorrentStatusDownloaded {
+			if err := entry.ActivatePlacement(target); err == nil {
+				entry.Bad = false
+				entry.UpdatedAt = time.Now()
+				return true, nil
+			}
+		}
+	}
+
+	old := entry.Providers[target]
+	placement, err := f.manager.placeNZB(entry, target)
+	if err != nil {
+		return false, err
+	}
+	f.activateNZBPlacement(entry, target, placement, old)
+	return true, nil
+}
+
+// activateNZBPlacement switches an NZB entry to its new placement on target.
+// old is the placement target had before.
+func (f *Fixer) activateNZBPlacement(entry *storage.Entry, target string, placement, old *storage.ProviderEntry) {
+	if err := entry.ActivatePlacement(target); err != nil {
+		f.manager.logger.Warn().Err(err).Msg("failed to activate placement")
+	}
+	entry.Bad = false
+	entry.UpdatedAt = time.Now()
+
+	// A re-insert on the same debrid leaves the broken download behind
+	if old != nil && target != "usenet" && old.ID != placement.ID {
+		go func() {
+			_ = f.manager.RemoveFromProvider(old)
+		}()
+	}
+}
+
+// mergeEntryFiles updates global file metadata from a new placement, reviving
+// files that previously existed.
+func mergeEntryFiles(entry *storage.Entry, files []types.File, addedOn time.Time) {
+	if entry.Files == nil {
+		entry.Files = make(map[string]*storage.File)
+	}
+	for _, f := range files {
+		name := entry.FileName(f.Name)
+		if existing, exists := entry.Files[name]; exists {
+			existing.Size = f.Size
+			existing.ByteRange = f.ByteRange
+			existing.Deleted = false
+			existing.InfoHash = entry.InfoHash
+			existing.AddedOn = addedOn
+		} else {
+			entry.Files[name] = &storage.File{
+				Name:      name,
+				Size:      f.Size,
+				ByteRange: f.ByteRange,
+				Deleted:   false,
+				InfoHash:  entry.InfoHash,
+				AddedOn:   addedOn,
+			}
+		}
+	}
+	entry.ApplyFileSelection()
+}
+
 // buildAttemptOrder creates the order of debrids to attempt re-insertion
 // Priority: current active debrid first, then others in config order
 // If skipCurrent is true, current active debrid is skipped
 func (f *Fixer) buildAttemptOrder(torrent *storage.Entry, skipCurrent bool) []string {
+	if torrent.IsNZB() {
+		return f.buildNZBAttemptOrder(torrent, skipCurrent)
+	}
 	order := make([]string, 0, len(f.providerOrder))
 
 	// AddOrUpdate other debrids in config order
@@ -348,6 +426,21 @@ func (f *Fixer) buildAttemptOrder(torrent *storage.Entry, skipCurrent bool) []st
 	return order
 }
 
+// buildNZBAttemptOrder orders the usenet providers an NZB can be re-inserted
+// on. Re-parsing over NNTP cannot bring back missing articles, so NNTP is only
+// a target when the NZB is currently served by a debrid.
+func (f *Fixer) buildNZBAttemptOrder(entry *storage.Entry, skipCurrent bool) []string {
+	providers := f.manager.nzbProviderOrder()
+	order := make([]string, 0, len(providers))
+	for _, name := range providers {
+		if (skipCurrent && name == entry.ActiveProvider) || (name == "usenet" && entry.OnUsenet()) {
+			continue
+		}
+		order = append(order, name)
+	}
+	return order
+}
+
 // IsFailedToReinsert checks if a torrent has been marked as failed to re-insert
 func (f *Fixer) IsFailedToReinsert(infohash, debrid string) bool {
 	_, failed := f.failedToReinsert.Load(fmt.Sprintf("%s:%s", infohash, debrid))
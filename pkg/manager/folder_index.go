@@ -0,0 +1,240 @@
+package manager
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// folderAgeRecheck is how often an index with age conditions is rebuilt, as
+// entries move in and out of those folders with time alone
+const folderAgeRecheck = 5 * time.Minute
+
+// folderIndex holds the members of every custom folder. It is built with one
+// pass over the entries on first use and then follows the entry and health
+// writes storage reports, so listing a folder never scans the store.
+type folderIndex struct {
+	manager *Manager
+	folders *CustomFolders
+
+	mu      sync.Mutex
+	built   atomic.Bool
+	builtAt time.Time
+	entries map[string]*folderIndexEntry   // Infohash -> entry in at least one folder
+	names   map[string]string              // Infohash -> entry folder name
+	byName  map[string]map[string]struct{} // Entry folder name -> infohashes
+	members map[string]map[string]struct{} // Custom folder -> infohashes
+
+	dirtyMu     sync.Mutex
+	dirty       map[string]struct{} // Infohashes written since the last update
+	dirtyHealth map[string]struct{} // Entry folder names whose health changed
+}
+
+type folderIndexEntry struct {
+	name    string
+	info    FileInfo
+	folders []string
+}
+
+func newFolderIndex(m *Manager, folders *CustomFolders) *folderIndex {
+	return &folderIndex{
+		manager:     m,
+		folders:     folders,
+		dirty:       make(map[string]struct{}),
+		dirtyHealth: make(map[string]struct{}),
+	}
+}
+
+// EntryChanged implements storage.EntryObserver. Writes before the first
+// build are covered by the build itself.
+func (idx *folderIndex) EntryChanged(infohash string) {
+	if !idx.built.Load() {
+		return
+	}
+	idx.dirtyMu.Lock()
+	idx.dirty[infohash] = struct{}{}
+	idx.dirtyMu.Unlock()
+}
+
+// EntryHealthChanged implements storage.EntryObserver. Only folders that
+// test health care; the entries with that folder name are re-evaluated.
+func (idx *folderIndex) EntryHealthChanged(entryName string) {
+	if !idx.folders.usesHealth || !idx.built.Load() {
+		return
+	}
+	idx.dirtyMu.Lock()
+	idx.dirtyHealth[entryName] = struct{}{}
+	idx.dirtyMu.Unlock()
+}
+
+// children returns the folder's members as directory entries, one per entry
+// folder name
+func (idx *folderIndex) children(folder string) []FileInfo {
+	if len(idx.folders.folders) == 0 {
+		return nil
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if !idx.built.Load() || (idx.folders.usesAge && time.Since(idx.builtAt) > folderAgeRecheck) {
+		idx.rebuild()
+	} else {
+		idx.update()
+	}
+
+	members := idx.members[folder]
+	infos := make([]FileInfo, 0, len(members))
+	seen := make(map[string]struct{}, len(members))
+	for infohash := range members {
+		entry := idx.entries[infohash]
+		if _, ok := seen[entry.name]; ok {
+			continue
+		}
+		seen[entry.name] = struct{}{}
+		infos = append(infos, entry.info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].name < infos[j].name })
+	return infos
+}
+
+// rebuild evaluates every entry. Caller holds idx.mu.
+func (idx *folderIndex) rebuild() {
+	idx.built.Store(true) // Start collecting writes the pass may miss
+	idx.takeDirty()       // Those before are covered by the pass
+	idx.entries = make(map[string]*folderIndexEntry)
+	idx.names = make(map[string]string)
+	idx.byName = make(map[string]map[string]struct{})
+	idx.members = make(map[string]map[string]struct{}, len(idx.folders.folders))
+
+	now := time.Now()
+	err := idx.manager.storage.ForEach(func(entry *storage.Entry) error {
+		idx.add(entry, now)
+		return nil
+	})
+	if err != nil {
+		idx.manager.logger.Warn().Err(err).Msg("Failed to build custom folder index")
+	}
+	idx.builtAt = now
+}
+
+// update re-evaluates the entries written since the last call. Caller holds
+// idx.mu.
+func (idx *folderIndex) update() {
+	dirty, dirtyHealth := idx.takeDirty()
+	for name := range dirtyHealth {
+		for infohash := range idx.byName[name] {
+			dirty[infohash] = struct{}{}
+		}
+	}
+	if len(dirty) == 0 {
+		return
+	}
+	now := time.Now()
+	for infohash := range dirty {
+		idx.remove(infohash)
+		entry, err := idx.manager.storage.Get(infohash)
+		if err != nil || entry == nil {
+			continue // Deleted
+		}
+		idx.add(entry, now)
+	}
+}
+
+func (idx *folderIndex) takeDirty() (map[string]struct{}, map[string]struct{}) {
+	idx.dirtyMu.Lock()
+	defer idx.dirtyMu.Unlock()
+	dirty, dirtyHealth := idx.dirty, idx.dirtyHealth
+	idx.dirty = make(map[string]struct{})
+	idx.dirtyHealth = make(map[string]struct{})
+	return dirty, dirtyHealth
+}
+
+func (idx *folderIndex) add(entry *storage.Entry, now time.Time) {
+	name := entry.GetFolder()
+	if name == "" || entry.InfoHash == "" {
+		return
+	}
+	idx.names[entry.InfoHash] = name
+	if idx.byName[name] == nil {
+		idx.byName[name] = make(map[string]struct{})
+	}
+	idx.byName[name][entry.InfoHash] = struct{}{}
+
+	subject := idx.subject(entry, name)
+	var folders []string
+	for _, folder := range idx.folders.folders {
+		if idx.folders.matches(folder, subject, now) {
+			folders = append(folders, folder)
+		}
+	}
+	if len(folders) == 0 {
+		return
+	}
+	idx.entries[entry.InfoHash] = &folderIndexEntry{
+		name: name,
+		info: FileInfo{
+			infohash:     entry.InfoHash,
+			name:         name,
+			size:         entry.Size,
+			modTime:      entry.AddedOn,
+			isDir:        true,
+			activeDebrid: entry.ActiveProvider,
+			canDelete:    true,
+		},
+		folders: folders,
+	}
+	for _, folder := range folders {
+		if idx.members[folder] == nil {
+			idx.members[folder] = make(map[string]struct{})
+		}
+		idx.members[folder][entry.InfoHash] = struct{}{}
+	}
+}
+
+func (idx *folderIndex) remove(infohash string) {
+	if name, ok := idx.names[infohash]; ok {
+		delete(idx.byName[name], infohash)
+		if len(idx.byName[name]) == 0 {
+			delete(idx.byName, name)
+		}
+		delete(idx.names, infohash)
+	}
+	entry, ok := idx.entries[infohash]
+	if !ok {
+		return
+	}
+	for _, folder := range entry.folders {
+		delete(idx.members[folder], infohash)
+	}
+	delete(idx.entries, infohash)
+}
+
+func (idx *folderIndex) subject(entry *storage.Entry, name string) *config.FolderSubject {
+	subject := &config.FolderSubject{
+		Name:     name,
+		Category: entry.Category,
+		Provider: entry.ActiveProvider,
+		Protocol: string(entry.Protocol),
+		Health:   string(storage.HealthUnknown),
+		Tags:     entry.Tags,
+		Files:    make([]string, 0, len(entry.Files)),
+		Size:     entry.Size,
+		AddedOn:  entry.AddedOn,
+		Bad:      entry.Bad,
+		Folders:  entry.Folders,
+	}
+	for fileName, f := range entry.Files {
+		if f.Active() {
+			subject.Files = append(subject.Files, fileName)
+		}
+	}
+	if idx.folders.usesHealth {
+		if h, err := idx.manager.storage.GetEntryHealth(name); err == nil && h != nil {
+			subject.Health = string(h.Status)
+		}
+	}
+	return subject
+}
@@ -0,0 +1,420 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/notifications"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+const guardianHistoryRetained = 50
+
+var ErrGuardianRunning = errors.New("expiry guardian already running")
+
+// GuardianOptions controls an expiry guardian pass.
+type GuardianOptions struct {
+	Providers []string // Empty means every configured debrid
+	DryRun    bool
+}
+
+// guardedPlacement is the slice of an entry's placement the guardian needs;
+// keeping only this avoids holding every entry in memory during the pass.
+type guardedPlacement struct {
+	infohash string
+	name     string
+	id       string
+	size     int64
+	addedAt  time.Time
+	active   bool
+}
+
+// StartGuardian runs the expiry guardian in the background and returns the
+// run record, which is updated in storage as the pass progresses.
+func (m *Manager) StartGuardian(trigger storage.RepairRunTrigger, opts GuardianOptions) (*storage.GuardianRun, error) {
+	for _, name := range opts.Providers {
+		if m.ProviderClient(name) == nil {
+			return nil, fmt.Errorf("debrid %s not found", name)
+		}
+	}
+	if !m.guardianRunning.CompareAndSwap(false, true) {
+		return nil, ErrGuardianRunning
+	}
+	run := &storage.GuardianRun{
+		ID:        uuid.New().String(),
+		Trigger:   trigger,
+		Status:    storage.RepairRunRunning,
+		DryRun:    opts.DryRun,
+		Providers: opts.Providers,
+		StartedAt: time.Now(),
+		Items:     make([]*storage.GuardianItem, 0),
+	}
+	if err := m.storage.SaveGuardianRun(run); err != nil {
+		m.guardianRunning.Store(false)
+		return nil, err
+	}
+	go func() {
+		defer m.guardianRunning.Store(false)
+		m.guardian(m.ctx, run, opts)
+	}()
+	return run, nil
+}
+
+func (m *Manager) guardian(ctx context.Context, run *storage.GuardianRun, opts GuardianOptions) {
+	m.logger.Info().Str("run_id", run.ID).Bool("dry_run", opts.DryRun).Msg("Starting expiry guardian")
+
+	cfg := config.Get()
+	margin, err := cfg.Guardian.MarginDuration()
+	if err != nil {
+		m.logger.Warn().Err(err).Msg("Invalid guardian margin, using default")
+		margin = config.DefaultGuardianMargin
+	}
+
+	// Only torrents: a debrid's torrent list doesn't show its usenet
+	// downloads, so NZB placements would all look gone.
+	placements := make(map[string][]*guardedPlacement) // Provider -> placements
+	err = m.storage.ForEach(func(e *storage.Entry) error {
+		if !e.IsTorrent() {
+			return nil
+		}
+		for name, p := range e.Providers {
+			if p == nil || p.ID == "" {
+				continue
+			}
+			placements[name] = append(placements[name], &guardedPlacement{
+				infohash: e.InfoHash,
+				name:     e.Name,
+				id:       p.ID,
+				size:     e.Size,
+				addedAt:  p.AddedAt,
+				active:   name == e.ActiveProvider,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		m.finishGuardian(run, storage.RepairRunFailed, fmt.Sprintf("failed to read entries: %v", err))
+		return
+	}
+	plays, err := m.storage.GetPlayRecords()
+	if err != nil {
+		m.finishGuardian(run, storage.RepairRunFailed, fmt.Sprintf("failed to read stream history: %v", err))
+		return
+	}
+	streaming := make(map[string]bool)
+	m.activeStreams.Range(func(_ string, s *ActiveStream) bool {
+		streaming[s.EntryName] = true
+		return true
+	})
+
+	for _, dc := range cfg.Debrids {
+		if len(opts.Providers) > 0 && !containsFold(opts.Providers, dc.Name) {
+			continue
+		}
+		client := m.ProviderClient(dc.Name)
+		if client == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			m.finishGuardian(run, storage.RepairRunCancelled, "")
+			return
+		}
+		remote, err := client.GetTorrents()
+		if err != nil {
+			if run.Errors == nil {
+				run.Errors = make(map[string]string)
+			}
+			run.Errors[dc.Name] = err.Error()
+			m.logger.Warn().Err(err).Str("debrid", dc.Name).Msg("Expiry guardian could not list torrents")
+			continue
+		}
+		seen := make(map[string]bool, len(remote))
+		for _, t := range remote {
+			seen[t.Id] = true
+		}
+		m.guardProvider(run, dc, len(remote), placements[dc.Name], seen, plays, streaming, margin, cfg.Guardian.Evict)
+	}
+
+	if !opts.DryRun {
+		// Evictions first, so re-added torrents have room
+		slices.SortStableFunc(run.Items, func(a, b *storage.GuardianItem) int {
+			return guardianActionOrder(a.Action) - guardianActionOrder(b.Action)
+		})
+		cancelled := false
+		for _, item := range run.Items {
+			if ctx.Err() != nil {
+				cancelled = true
+				break
+			}
+			if err := m.applyGuardianItem(item); err != nil {
+				item.Error = err.Error()
+				run.Stats.Failed++
+				continue
+			}
+			item.Applied = true
+			run.Stats.Applied++
+		}
+		if run.Stats.Applied > 0 {
+			m.RefreshEntries(true)
+		}
+		if cancelled {
+			m.finishGuardian(run, storage.RepairRunCancelled, "")
+			return
+		}
+	}
+	m.finishGuardian(run, storage.RepairRunCompleted, "")
+}
+
+// guardProvider classifies the placements on one debrid: gone from it,
+// about to expire under its rules, or, with evict set and the debrid at its
+// limit, the least recently streamed.
+func (m *Manager) guardProvider(run *storage.GuardianRun, dc config.Debrid, remoteCount int, placements []*guardedPlacement, seen map[string]bool, plays map[string]storage.PlayRecord, streaming map[string]bool, margin time.Duration, evict bool) {
+	maxAge, idle, err := dc.TorrentExpiry()
+	if err != nil {
+		m.logger.Warn().Err(err).Msg("Invalid torrent expiry, only syncing placements")
+	}
+	// A debrid with a limit may list no more than that many torrents, so the
+	// placements past the listing can't be told apart from gone ones
+	truncated := dc.Limit > 0 && remoteCount >= dc.Limit
+
+	now := time.Now()
+	added := 0
+	candidates := make([]*storage.GuardianItem, 0)
+	for _, p := range placements {
+		run.Stats.Placements++
+		item := &storage.GuardianItem{
+			Provider:  dc.Name,
+			TorrentID: p.id,
+			InfoHash:  p.infohash,
+			Name:      p.name,
+			Size:      p.size,
+			AddedAt:   p.addedAt,
+		}
+		if play, ok := plays[strings.ToLower(p.infohash)]; ok {
+			item.LastPlayed = &play.LastPlayed
+		}
+
+		if !seen[p.id] {
+			if truncated {
+				continue
+			}
+			run.Stats.Missing++
+			item.Action = storage.GuardianDrop
+			// Only the active placement is worth re-adding; a secondary one
+			// is dropped since the entry is served from elsewhere.
+			if p.active {
+				item.Action = storage.GuardianReAdd
+				added++
+			}
+			run.Items = append(run.Items, item)
+			continue
+		}
+
+		item.ExpiresAt = placementExpiry(p.addedAt, item.LastPlayed, maxAge, idle)
+		// Re-adding a torrent being streamed would cut the stream off
+		if streaming[p.name] {
+			continue
+		}
+		// Secondary placements aren't refreshed, since re-adding one would
+		// make it active; they are dropped once the debrid removes them.
+		if p.active && item.ExpiresAt != nil && now.Add(margin).After(*item.ExpiresAt) {
+			run.Stats.Expiring++
+			item.Action = storage.GuardianRefresh
+			run.Items = append(run.Items, item)
+			continue
+		}
+		candidates = append(candidates, item)
+	}
+
+	if !evict || dc.Limit <= 0 {
+		return
+	}
+	// Room for the re-added torrents and one more
+	over := remoteCount + added - dc.Limit + 1
+	if over <= 0 {
+		return
+	}
+	slices.SortStableFunc(candidates, func(a, b *storage.GuardianItem) int {
+		return lastActive(a).Compare(lastActive(b))
+	})
+	for _, item := range candidates[:min(over, len(candidates))] {
+		item.Action = storage.GuardianEvict
+		run.Stats.Evicted++
+		run.Items = append(run.Items, item)
+	}
+}
+
+// placementExpiry is when a debrid's expiry rules drop a placement, or nil
+// when it has none. Streaming counts as activity for the idle rule.
+func placementExpiry(addedAt time.Time, lastPlayed *time.Time, maxAge, idle time.Duration) *time.Time {
+	if addedAt.IsZero() {
+		return nil
+	}
+	var expiry time.Time
+	if maxAge > 0 {
+		expiry = addedAt.Add(maxAge)
+	}
+	if idle > 0 {
+		since := addedAt
+		if lastPlayed != nil && lastPlayed.After(since) {
+			since = *lastPlayed
+		}
+		if until := since.Add(idle); expiry.IsZero() || until.Before(expiry) {
+			expiry = until
+		}
+	}
+	if expiry.IsZero() {
+		return nil
+	}
+	return &expiry
+}
+
+// lastActive is when a placement was last streamed, or added if never
+func lastActive(item *storage.GuardianItem) time.Time {
+	if item.LastPlayed != nil && item.LastPlayed.After(item.AddedAt) {
+		return *item.LastPlayed
+	}
+	return item.AddedAt
+}
+
+func guardianActionOrder(action storage.GuardianAction) int {
+	switch action {
+	case storage.GuardianEvict:
+		return 0
+	case storage.GuardianDrop:
+		return 1
+	case storage.GuardianReAdd:
+		return 2
+	}
+	return 3
+}
+
+func (m *Manager) applyGuardianItem(item *storage.GuardianItem) error {
+	entry, err := m.storage.Get(item.InfoHash)
+	if err != nil {
+		return err
+	}
+	placement, ok := entry.Providers[item.Provider]
+	if !ok || placement == nil || placement.ID != item.TorrentID {
+		return fmt.Errorf("placement changed since the scan")
+	}
+
+	switch item.Action {
+	case storage.GuardianReAdd, storage.GuardianRefresh:
+		if entry.ActiveProvider != item.Provider {
+			return fmt.Errorf("%s is no longer the active placement", item.Provider)
+		}
+		// Re-submits the stored magnet and deletes the old torrent
+		if _, err := m.fixer.MoveTorrent(entry, item.Provider, true); err != nil {
+			return err
+		}
+		reason := "placement was gone from the debrid"
+		if item.Action == storage.GuardianRefresh {
+			reason = "placement was about to expire"
+		}
+		m.RecordEvent(entry.InfoHash, storage.EntryEventRefreshed, item.Provider, "re-added, "+reason)
+		return nil
+
+	case storage.GuardianDrop:
+		entry.RemoveProvider(item.Provider, nil)
+		return m.AddOrUpdate(entry, nil)
+
+	case storage.GuardianEvict:
+		m.RecordEvent(entry.InfoHash, storage.EntryEventEvicted, item.Provider, "evicted, debrid at its torrent limit")
+		if len(entry.Providers) == 1 {
+			// Its only placement: the entry leaves the library
+			return m.DeleteEntry(entry.InfoHash, true, false)
+		}
+		entry.RemoveProvider(item.Provider, m.RemoveFromProvider)
+		return m.AddOrUpdate(entry, nil)
+	}
+	return fmt.Errorf("unknown action %q", item.Action)
+}
+
+func (m *Manager) finishGuardian(run *storage.GuardianRun, status storage.RepairRunStatus, errStr string) {
+	run.Status = status
+	run.Error = errStr
+	run.CompletedAt = time.Now()
+	if err := m.storage.SaveGuardianRun(run); err != nil {
+		m.logger.Warn().Err(err).Str("run_id", run.ID).Msg("Failed to save guardian run")
+	}
+	_ = m.storage.PruneGuardianRuns(guardianHistoryRetained)
+	m.logger.Info().
+		Str("run_id", run.ID).
+		Str("status", string(status)).
+		Int("placements", run.Stats.Placements).
+		Int("missing", run.Stats.Missing).
+		Int("expiring", run.Stats.Expiring).
+		Int("evicted", run.Stats.Evicted).
+		Int("applied", run.Stats.Applied).
+		Int("failed", run.Stats.Failed).
+		Msg("Expiry guardian finished")
+
+	acted := run.Stats.Missing + run.Stats.Expiring + run.Stats.Evicted
+	if m.Notifications == nil || (status == storage.RepairRunCompleted && acted == 0) {
+		return
+	}
+	event := notifications.Event{Type: config.EventDebridExpiry, Status: "success"}
+	switch {
+	case status == storage.RepairRunFailed:
+		event.Status = "error"
+		event.Message = "Expiry guardian failed: " + errStr
+	case status == storage.RepairRunCancelled:
+		event.Status = "warning"
+		event.Message = fmt.Sprintf("Expiry guardian cancelled after %d changes", run.Stats.Applied)
+	case run.DryRun:
+		event.Status = "pending"
+		event.Message = fmt.Sprintf("Dry run: %d missing, %d expiring and %d to evict", run.Stats.Missing, run.Stats.Expiring, run.Stats.Evicted)
+	default:
+		var readded, evicted int
+		var freed int64
+		for _, item := range run.Items {
+			switch {
+			case !item.Applied:
+			case item.Action == storage.GuardianEvict:
+				evicted++
+				freed += item.Size
+			case item.Action != storage.GuardianDrop:
+				readded++
+			}
+		}
+		event.Message = fmt.Sprintf("Re-added %d torrents, evicted %d (%s)", readded, evicted, utils.FormatSize(freed))
+		if run.Stats.Failed > 0 {
+			event.Status = "warning"
+			event.Message += fmt.Sprintf(", %d failed", run.Stats.Failed)
+		}
+	}
+	m.Notifications.Notify(event)
+}
+
+// scheduleGuardian registers the periodic expiry guardian from config. The
+// margin, dry_run and evict are read when the run starts.
+func (m *Manager) scheduleGuardian(ctx context.Context) {
+	schedule := m.config.Guardian.Schedule
+	if strings.TrimSpace(schedule) == "" {
+		return
+	}
+	jd, err := utils.ConvertToJobDef(schedule)
+	if err != nil {
+		m.logger.Error().Err(err).Str("schedule", schedule).Msg("Failed to convert guardian schedule to job definition")
+		return
+	}
+	if _, err := m.scheduler.NewJob(jd, gocron.NewTask(func() {
+		opts := GuardianOptions{DryRun: config.Get().Guardian.DryRun}
+		if _, err := m.StartGuardian(storage.RepairTriggerScheduled, opts); err != nil {
+			m.logger.Warn().Err(err).Msg("Scheduled expiry guardian skipped")
+		}
+	}), gocron.WithContext(ctx), gocron.WithName("guardian")); err != nil {
+		m.logger.Error().Err(err).Msg("Failed to create guardian job")
+		return
+	}
+	m.logger.Debug().Msgf("Expiry guardian scheduled for %s", schedule)
+}
@@ -0,0 +1,168 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+func TestGuardProvider(t *testing.T) {
+	const day = 24 * time.Hour
+	now := time.Now()
+	placement := func(id string, age time.Duration, active bool) *guardedPlacement {
+		return &guardedPlacement{infohash: "HASH-" + id, name: "Name-" + id, id: id, addedAt: now.Add(-age), active: active}
+	}
+	tests := []struct {
+		name        string
+		debrid      config.Debrid
+		remoteCount int
+		placements  []*guardedPlacement
+		seen        []string
+		played      map[string]time.Duration // Placement id -> last streamed this long ago
+		streaming   []string                 // Placement ids being streamed
+		evict       bool
+		want        map[string]storage.GuardianAction
+	}{
+		{
+			name:        "gone placements are re-added when active and dropped otherwise",
+			debrid:      config.Debrid{Name: "rd"},
+			remoteCount: 1,
+			placements:  []*guardedPlacement{placement("a", day, true), placement("b", day, false), placement("c", day, true)},
+			seen:        []string{"c"},
+			want:        map[string]storage.GuardianAction{"a": storage.GuardianReAdd, "b": storage.GuardianDrop},
+		},
+		{
+			name:        "active placements inside the margin are refreshed",
+			debrid:      config.Debrid{Name: "rd", TorrentMaxAge: "30d"},
+			remoteCount: 4,
+			placements: []*guardedPlacement{
+				placement("expiring", 29*day, true),
+				placement("fresh", 10*day, true),
+				placement("secondary", 29*day, false),
+				placement("streaming", 29*day, true),
+			},
+			seen:      []string{"expiring", "fresh", "secondary", "streaming"},
+			streaming: []string{"streaming"},
+			want:      map[string]storage.GuardianAction{"expiring": storage.GuardianRefresh},
+		},
+		{
+			name:        "streaming restarts the idle expiry",
+			debrid:      config.Debrid{Name: "rd", TorrentIdleExpiry: "1w"},
+			remoteCount: 2,
+			placements:  []*guardedPlacement{placement("idle", 6*day, true), placement("played", 6*day, true)},
+			seen:        []string{"idle", "played"},
+			played:      map[string]time.Duration{"played": day},
+			want:        map[string]storage.GuardianAction{"idle": storage.GuardianRefresh},
+		},
+		{
+			name:        "at the limit the least recently active placement is evicted, not the unlisted dropped",
+			debrid:      config.Debrid{Name: "rd", Limit: 3},
+			remoteCount: 3,
+			placements: []*guardedPlacement{
+				placement("oldest", 9*day, true),
+				placement("old-but-played", 8*day, true),
+				placement("newest", day, true),
+				placement("unlisted", 9*day, false),
+			},
+			seen:   []string{"oldest", "old-but-played", "newest"},
+			played: map[string]time.Duration{"old-but-played": time.Hour},
+			evict:  true,
+			want:   map[string]storage.GuardianAction{"oldest": storage.GuardianEvict},
+		},
+		{
+			name:        "re-added placements make room first",
+			debrid:      config.Debrid{Name: "rd", Limit: 3},
+			remoteCount: 2,
+			placements: []*guardedPlacement{
+				placement("gone", 9*day, true),
+				placement("oldest", 8*day, true),
+				placement("newest", day, true),
+			},
+			seen:  []string{"oldest", "newest"},
+			evict: true,
+			want:  map[string]storage.GuardianAction{"gone": storage.GuardianReAdd, "oldest": storage.GuardianEvict},
+		},
+		{
+			name:        "without evict a full debrid is left alone",
+			debrid:      config.Debrid{Name: "rd", Limit: 2},
+			remoteCount: 2,
+			placements:  []*guardedPlacement{placement("a", 9*day, true), placement("b", day, true)},
+			seen:        []string{"a", "b"},
+			want:        map[string]storage.GuardianAction{},
+		},
+		{
+			name:        "below the limit nothing is evicted",
+			debrid:      config.Debrid{Name: "rd", Limit: 5},
+			remoteCount: 2,
+			placements:  []*guardedPlacement{placement("a", 9*day, true), placement("b", day, true)},
+			seen:        []string{"a", "b"},
+			evict:       true,
+			want:        map[string]storage.GuardianAction{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+			seen := make(map[string]bool)
+			for _, id := range tt.seen {
+				seen[id] = true
+			}
+			plays := make(map[string]storage.PlayRecord)
+			for id, ago := range tt.played {
+				plays["hash-"+id] = storage.PlayRecord{LastPlayed: now.Add(-ago)}
+			}
+			streaming := make(map[string]bool)
+			for _, id := range tt.streaming {
+				streaming["Name-"+id] = true
+			}
+			run := &storage.GuardianRun{}
+			m.guardProvider(run, tt.debrid, tt.remoteCount, tt.placements, seen, plays, streaming, 2*day, tt.evict)
+
+			got := make(map[string]storage.GuardianAction)
+			for _, item := range run.Items {
+				got[item.TorrentID] = item.Action
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("actions = %v, want %v", got, tt.want)
+			}
+			for id, action := range tt.want {
+				if got[id] != action {
+					t.Errorf("%s action = %q, want %q", id, got[id], action)
+				}
+			}
+		})
+	}
+}
+
+func TestPlacementExpiry(t *testing.T) {
+	added := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	played := added.Add(5 * 24 * time.Hour)
+	tests := []struct {
+		name       string
+		addedAt    time.Time
+		lastPlayed *time.Time
+		maxAge     time.Duration
+		idle       time.Duration
+		want       time.Time // Zero for no expiry
+	}{
+		{name: "no rules", addedAt: added},
+		{name: "unknown add time", maxAge: time.Hour},
+		{name: "max age", addedAt: added, maxAge: 30 * 24 * time.Hour, want: added.Add(30 * 24 * time.Hour)},
+		{name: "idle from add", addedAt: added, idle: 7 * 24 * time.Hour, want: added.Add(7 * 24 * time.Hour)},
+		{name: "idle from last play", addedAt: added, lastPlayed: &played, idle: 7 * 24 * time.Hour, want: played.Add(7 * 24 * time.Hour)},
+		{name: "earlier rule wins", addedAt: added, lastPlayed: &played, maxAge: 10 * 24 * time.Hour, idle: 7 * 24 * time.Hour, want: added.Add(10 * 24 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := placementExpiry(tt.addedAt, tt.lastPlayed, tt.maxAge, tt.idle)
+			switch {
+			case tt.want.IsZero() && got != nil:
+				t.Errorf("expiry = %v, want none", *got)
+			case !tt.want.IsZero() && (got == nil || !got.Equal(tt.want)):
+				t.Errorf("expiry = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	debrid "github.com/sirrobot01/decypharr/pkg/debrid/common"
+)
+
+const (
+	// healthCheckTimeout bounds each component check
+	healthCheckTimeout = 10 * time.Second
+	// healthCacheTTL is how long a debrid or NNTP result is reused, so frequent
+	// probes don't hit the providers on every request
+	healthCacheTTL = 30 * time.Second
+)
+
+// Component statuses reported by Health
+const (
+	ComponentOK       = "ok"
+	ComponentFailing  = "failing"
+	ComponentDisabled = "disabled"
+)
+
+// MountHealthChecker is implemented by mount managers that can probe the mount
+// itself rather than only report whether it was started
+type MountHealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// ComponentHealth is the state of one component
+type ComponentHealth struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// HealthReport is the readiness of the manager and its components. It is
+// ready when no component is failing.
+type HealthReport struct {
+	Ready      bool              `json:"ready"`
+	Components []ComponentHealth `json:"components"`
+}
+
+type healthCache struct {
+	mu      sync.Mutex
+	entries map[string]*healthEntry
+}
+
+type healthEntry struct {
+	mu     sync.Mutex
+	result ComponentHealth
+}
+
+// get returns a cached result, or runs check and caches it. The component's
+// lock is held during the check so concurrent probes share a single remote
+// call; other components are not held up.
+func (c *healthCache) get(name string, check func() ComponentHealth) ComponentHealth {
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]*healthEntry)
+	}
+	entry, ok := c.entries[name]
+	if !ok {
+		entry = &healthEntry{}
+		c.entries[name] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if !entry.result.CheckedAt.IsZero() && time.Since(entry.result.CheckedAt) < healthCacheTTL {
+		return entry.result
+	}
+	entry.result = check()
+	return entry.result
+}
+
+// Health checks storage, the mount, the debrids, the NNTP pool and the
+// scheduler. Components that aren't configured are reported as disabled.
+// The checks run concurrently, so a probe takes as long as the slowest one.
+func (m *Manager) Health(ctx context.Context) HealthReport {
+	// Cached results outlive the request, so a probe that hangs up early
+	// mustn't leave a failure behind
+	detached := context.WithoutCancel(ctx)
+	checks := []func() ComponentHealth{
+		m.storageHealth,
+		func() ComponentHealth { return m.mountHealth(ctx) },
+		func() ComponentHealth {
+			return m.health.get("debrid", func() ComponentHealth { return m.debridHealth(detached) })
+		},
+		func() ComponentHealth {
+			return m.health.get("usenet", func() ComponentHealth { return m.usenetHealth(detached) })
+		},
+		m.schedulerHealth,
+	}
+	components := make([]ComponentHealth, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Go(func() {
+			components[i] = check()
+		})
+	}
+	wg.Wait()
+
+	report := HealthReport{Ready: true, Components: components}
+	for _, c := range components {
+		if c.Status == ComponentFailing {
+			report.Ready = false
+		}
+	}
+	return report
+}
+
+func componentHealth(name string, err error) ComponentHealth {
+	h := ComponentHealth{Name: name, Status: ComponentOK, CheckedAt: time.Now()}
+	if err != nil {
+		h.Status = ComponentFailing
+		h.Reason = err.Error()
+	}
+	return h
+}
+
+func disabledComponent(name, reason string) ComponentHealth {
+	return ComponentHealth{Name: name, Status: ComponentDisabled, Reason: reason, CheckedAt: time.Now()}
+}
+
+func (m *Manager) storageHealth() ComponentHealth {
+	if m.storage == nil {
+		return componentHealth("storage", errors.New("storage is not open"))
+	}
+	return componentHealth("storage", m.storage.Ping())
+}
+
+func (m *Manager) mountHealth(ctx context.Context) ComponentHealth {
+	mountMgr := m.mountManager
+	if mountMgr == nil || mountMgr.Type() == "none" {
+		return disabledComponent("mount", "no mount configured")
+	}
+	if checker, ok := mountMgr.(MountHealthChecker); ok {
+		ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		defer cancel()
+		return componentHealth("mount", checker.CheckHealth(ctx))
+	}
+	if !mountMgr.IsReady() {
+		return componentHealth("mount", fmt.Errorf("%s mount is not ready", mountMgr.Type()))
+	}
+	return componentHealth("mount", nil)
+}
+
+// debridHealth is ok when at least one debrid answers. The reason lists the
+// debrids that didn't.
+func (m *Manager) debridHealth(ctx context.Context) ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	type result struct {
+		name string
+		err  error
+	}
+	results := make(chan result)
+	count := 0
+	m.clients.Range(func(name string, client debrid.Client) bool {
+		if client == nil {
+			return true
+		}
+		count++
+		go func() {
+			// Not every provider honours ctx, so the wait below is bounded separately
+			err := pingDebrid(ctx, client)
+			select {
+			case results <- result{name: name, err: err}:
+			case <-ctx.Done():
+			}
+		}()
+		return true
+	})
+	if count == 0 {
+		return disabledComponent("debrid", "no debrid configured")
+	}
+
+	var failures []string
+	for range count {
+		select {
+		case r := <-results:
+			if r.err == nil {
+				return componentHealth("debrid", nil)
+			}
+			failures = append(failures, fmt.Sprintf("%s: %v", r.name, r.err))
+		case <-ctx.Done():
+			failures = append(failures, "timed out waiting for a debrid to answer")
+			return componentHealth("debrid", errors.New(strings.Join(failures, "; ")))
+		}
+	}
+	return componentHealth("debrid", errors.New(strings.Join(failures, "; ")))
+}
+
+func pingDebrid(ctx context.Context, client debrid.Client) error {
+	if client.AccountManager().Current() == nil {
+		return errors.New("no usable account")
+	}
+	return client.Ping(ctx)
+}
+
+func (m *Manager) usenetHealth(ctx context.Context) ComponentHealth {
+	if m.usenet == nil {
+		return disabledComponent("usenet", "no NNTP provider configured")
+	}
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	return componentHealth("usenet", m.usenet.Ping(ctx))
+}
+
+func (m *Manager) schedulerHealth() ComponentHealth {
+	if !m.schedulerRunning.Load() {
+		return componentHealth("scheduler", errors.New("scheduler is not running"))
+	}
+	return componentHealth("scheduler", nil)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"runtime/debug"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -33,23 +34,36 @@ type Job struct {
 	NZBGroups      map[string]*parser.FileGroup // NZB file groups parsed before the active-download gate
 	Entry          *storage.Entry               // Entry created during processing
 	ResumeExisting bool                         // Continue an already persisted provider placement
+	Priority       int                          // Higher priorities are picked up first
 	CreatedAt      time.Time
 }
 
 // NewJob creates a new job
 func NewJob(jobType JobType, req *ImportRequest) *Job {
 	id := ""
+	priority := 0
 	if req != nil {
 		id = req.Id
+		priority = req.Priority
 	}
 	return &Job{
 		ID:        id,
 		Type:      jobType,
 		Request:   req,
+		Priority:  priority,
 		CreatedAt: time.Now(),
 	}
 }
 
+// setPriority keeps the job's entry in step, so the priority survives the
+// entry being written back to the queue.
+func (j *Job) setPriority(priority int) {
+	j.Priority = priority
+	if j.Entry != nil {
+		j.Entry.Priority = priority
+	}
+}
+
 // JobQueue is a unified, unbounded, thread-safe job queue with a fixed worker pool.
 // It replaces the separate ImportRequest queue, nzbJobQueue, and unbounded goroutine
 // fan-out with a single queue that processes both torrent and NZB jobs.
@@ -97,7 +111,8 @@ func NewJobQueue(ctx context.Context, maxWorkers int, processFunc func(ctx conte
 	return q
 }
 
-// Submit adds a job to the queue (never blocks)
+// Submit adds a job to the queue (never blocks). It lands behind every
+// pending job of the same or a higher priority.
 func (q *JobQueue) Submit(job *Job) error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -106,7 +121,7 @@ func (q *JobQueue) Submit(job *Job) error {
 		return fmt.Errorf("job queue is closed")
 	}
 
-	q.jobs = append(q.jobs, job)
+	q.insert(job)
 	q.cond.Signal() // Wake one waiting worker
 	q.logger.Debug().
 		Str("id", job.ID).
@@ -116,6 +131,16 @@ func (q *JobQueue) Submit(job *Job) error {
 	return nil
 }
 
+// insert places job after the last pending job whose priority is at least
+// its own. Callers must hold q.mu.
+func (q *JobQueue) insert(job *Job) {
+	i := len(q.jobs)
+	for i > 0 && q.jobs[i-1].Priority < job.Priority {
+		i--
+	}
+	q.jobs = slices.Insert(q.jobs, i, job)
+}
+
 // Len returns the current number of pending jobs
 func (q *JobQueue) Len() int {
 	q.mu.Lock()
@@ -197,13 +222,14 @@ func (q *JobQueue) runJob(job *Job) {
 	q.processFunc(q.ctx, job)
 }
 
-// pop removes and returns the next job, blocking if queue is empty.
-// Returns nil if the queue is closed and empty.
+// pop removes and returns the next job, blocking while no job is runnable.
+// Paused jobs sort last, so the head is runnable unless every job is paused.
+// Returns nil if the queue is closed.
 func (q *JobQueue) pop() *Job {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	for len(q.jobs) == 0 && !q.closed {
+	for (len(q.jobs) == 0 || q.jobs[0].Priority <= PriorityPaused) && !q.closed {
 		q.cond.Wait()
 	}
 
@@ -261,3 +287,81 @@ func (q *JobQueue) PendingCount(jobType JobType) int {
 	}
 	return count
 }
+
+// UpdatePending runs fn on a pending job under the queue lock, so no worker
+// picks the job up halfway through. Returns false if no pending job has the ID.
+func (q *JobQueue) UpdatePending(jobID string, fn func(job *Job)) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := q.indexOf(jobID)
+	if i < 0 {
+		return false
+	}
+	fn(q.jobs[i])
+	return true
+}
+
+// Positions returns the index of every pending job by ID, in the order the
+// workers will pick them up.
+func (q *JobQueue) Positions() map[string]int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	positions := make(map[string]int, len(q.jobs))
+	for i, job := range q.jobs {
+		positions[job.ID] = i
+	}
+	return positions
+}
+
+// SetPriority changes a pending job's priority and moves it to where a job of
+// that priority would have been submitted. Returns the job's new position, or
+// -1 if no pending job has the ID.
+func (q *JobQueue) SetPriority(jobID string, priority int) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := q.indexOf(jobID)
+	if i < 0 {
+		return -1
+	}
+	job := q.jobs[i]
+	q.jobs = slices.Delete(q.jobs, i, i+1)
+	job.setPriority(priority)
+	q.insert(job)
+	q.cond.Broadcast() // A resumed job may now be runnable
+	return q.indexOf(jobID)
+}
+
+// Move puts a pending job at the given position. The job takes the priority
+// of the job it displaced, so later submissions keep the order. Returns the
+// job's position and priority, or -1 if no pending job has the ID.
+func (q *JobQueue) Move(jobID string, position int) (int, int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := q.indexOf(jobID)
+	if i < 0 {
+		return -1, 0
+	}
+	job := q.jobs[i]
+	q.jobs = slices.Delete(q.jobs, i, i+1)
+	position = max(0, min(position, len(q.jobs)))
+	switch {
+	case position < len(q.jobs):
+		job.setPriority(q.jobs[position].Priority)
+	case position > 0:
+		job.setPriority(q.jobs[position-1].Priority)
+	}
+	q.jobs = slices.Insert(q.jobs, position, job)
+	q.cond.Broadcast()
+	return position, job.Priority
+}
+
+// indexOf returns the index of a pending job or -1. Callers must hold q.mu.
+func (q *JobQueue) indexOf(jobID string) int {
+	return slices.IndexFunc(q.jobs, func(job *Job) bool {
+		return job.ID == jobID
+	})
+}
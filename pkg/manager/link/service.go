@@ -30,6 +30,10 @@ type EntryRefresher func(infohash string) (*storage.Entry, error)
 type EntryRepairer func(ctx context.Context, entry *storage.Entry) error
 type EntrySaver func(entry *storage.Entry) error
 
+// FailureRecorder is told about links that failed validation and entries given
+// up on, so they show up in the entry's timeline
+type FailureRecorder func(entry *storage.Entry, filename, provider string, err error)
+
 // Service handles download link fetching and validation.
 // It uses the account-level cache for storing links and only tracks validation state.
 type Service struct {
@@ -39,6 +43,7 @@ type Service struct {
 	entryRefresher EntryRefresher
 	repairer       EntryRepairer
 	entrySaver     EntrySaver
+	onFailure      FailureRecorder
 	httpClient     *http.Client
 	retries        int
 	logger         zerolog.Logger
@@ -50,6 +55,7 @@ func New(
 	entryRefresher EntryRefresher,
 	entryReinsert EntryRepairer,
 	entrySaver EntrySaver,
+	onFailure FailureRecorder,
 	httpClient *http.Client,
 	retries int,
 	logger zerolog.Logger,
@@ -60,6 +66,7 @@ func New(
 		entryRefresher: entryRefresher,
 		repairer:       entryReinsert,
 		entrySaver:     entrySaver,
+		onFailure:      onFailure,
 		httpClient:     httpClient,
 		retries:        retries,
 		logger:         logger,
@@ -151,9 +158,17 @@ func (s *Service) fetchAndValidate(ctx context.Context, entry *storage.Entry, fi
 	if validationErr == nil {
 		return link, nil
 	}
+	s.recordFailure(entry, filename, link.Debrid, validationErr)
 	return emptyDownloadLink, validationErr
 }
 
+func (s *Service) recordFailure(entry *storage.Entry, filename, provider string, err error) {
+	if s.onFailure == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	s.onFailure(entry, filename, provider, err)
+}
+
 func (s *Service) handleBadLink(ctx context.Context, err error, entry *storage.Entry, dl types.DownloadLink, attempt int) (types.DownloadLink, error) {
 	if errors.Is(err, customerror.HosterUnavailableError) {
 		if entry.Bad {
@@ -198,6 +213,7 @@ func (s *Service) markEntryBad(entry *storage.Entry, filename string, attempt in
 		Int("attempts", attempt).
 		Str("reason", reason).
 		Msg("Giving up on entry after repeated failed re-insertions")
+	s.recordFailure(entry, filename, entry.ActiveProvider, fmt.Errorf("marked as bad after %d re-insertion attempts: %s", attempt, reason))
 }
 
 // fetchLink fetches a download link from the debrid provider (via account cache)
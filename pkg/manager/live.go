@@ -0,0 +1,81 @@
+package manager
+
+import (
+	"github.com/sirrobot01/decypharr/internal/eventbus"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// Event types published on the live update topics
+const (
+	QueueEventAdded     = "added"
+	QueueEventUpdated   = "updated"
+	QueueEventDeleted   = "deleted"
+	QueueEventCompleted = "completed"
+	QueueEventFailed    = "failed"
+
+	StreamEventStarted = "started"
+	StreamEventStopped = "stopped"
+
+	RepairEventRun    = "run"
+	RepairEventHealth = "health"
+)
+
+// QueueItem is the payload of queue topic events
+type QueueItem struct {
+	InfoHash string  `json:"info_hash"`
+	Name     string  `json:"name,omitempty"`
+	Category string  `json:"category,omitempty"`
+	Protocol string  `json:"protocol,omitempty"`
+	Provider string  `json:"provider,omitempty"`
+	State    string  `json:"state,omitempty"`
+	Status   string  `json:"status,omitempty"`
+	Progress float64 `json:"progress"`
+	Speed    int64   `json:"speed"`
+	Size     int64   `json:"size,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+func newQueueItem(entry *storage.Entry) QueueItem {
+	return QueueItem{
+		InfoHash: entry.InfoHash,
+		Name:     entry.Name,
+		Category: entry.Category,
+		Protocol: string(entry.Protocol),
+		Provider: entry.ActiveProvider,
+		State:    string(entry.State),
+		Status:   string(entry.Status),
+		Progress: entry.Progress,
+		Speed:    entry.Speed,
+		Size:     entry.Size,
+		Error:    entry.LastError,
+	}
+}
+
+// Bus returns the event bus live updates are published on
+func (m *Manager) Bus() *eventbus.Bus {
+	return eventbus.Default()
+}
+
+func publishQueue(typ string, entry *storage.Entry) {
+	if entry == nil {
+		return
+	}
+	eventbus.Default().Publish(eventbus.TopicQueue, typ, newQueueItem(entry))
+}
+
+func publishQueueDeleted(infohash string) {
+	eventbus.Default().Publish(eventbus.TopicQueue, QueueEventDeleted, QueueItem{InfoHash: infohash})
+}
+
+func publishStream(typ string, stream ActiveStream) {
+	eventbus.Default().Publish(eventbus.TopicStreams, typ, stream)
+}
+
+// publishRepairRun sends a copy, as the run keeps changing while it is encoded
+func publishRepairRun(run *storage.RepairRun) {
+	eventbus.Default().Publish(eventbus.TopicRepair, RepairEventRun, *run)
+}
+
+func publishEntryHealth(state *storage.EntryHealth) {
+	eventbus.Default().Publish(eventbus.TopicRepair, RepairEventHealth, *state)
+}
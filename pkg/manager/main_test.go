@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/puzpuzpuz/xsync/v4"
+	"github.com/rs/zerolog"
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/pkg/arr"
+	debrid "github.com/sirrobot01/decypharr/pkg/debrid/common"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+func TestMain(m *testing.M) {
+	configDir, err := os.MkdirTemp("", "decypharr-manager-test-")
+	if err != nil {
+		panic(err)
+	}
+
+	config.SetConfigPath(configDir)
+	code := m.Run()
+	_ = os.RemoveAll(configDir)
+	os.Exit(code)
+}
+
+// newTestManager returns a manager over a fresh storage with no debrids,
+// Arrs, usenet, mount or schedulers
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	strg, err := storage.NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = strg.Close() })
+	m := &Manager{
+		storage:       strg,
+		logger:        zerolog.Nop(),
+		config:        config.Get(),
+		ctx:           context.Background(),
+		clients:       xsync.NewMap[string, debrid.Client](),
+		migrationJobs: xsync.NewMap[string, *storage.SwitcherJob](),
+		switchBatches: xsync.NewMap[string, *switchBatchRun](),
+		lastPlays:     xsync.NewMap[string, time.Time](),
+		activeStreams: xsync.NewMap[string, *ActiveStream](),
+		arr:           arr.NewStorage(),
+		usenetTimeout: time.Minute,
+		events:        newEventRecorder(strg, config.EventsConfig{}),
+	}
+	m.entry = NewEntryCache(m)
+	m.fixer = NewFixer(m)
+	return m
+}
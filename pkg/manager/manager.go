@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
@@ -41,15 +43,21 @@ type Manager struct {
 	streamClient *http.Client
 
 	// Migration jobs tracking
-	migrationJobs   *xsync.Map[string, *storage.SwitcherJob]
-	refreshInterval time.Duration
+	migrationJobs *xsync.Map[string, *storage.SwitcherJob]
+	switchBatches *xsync.Map[string, *switchBatchRun]
+
+	reconcileRunning atomic.Bool
+	retentionRunning atomic.Bool
+	guardianRunning  atomic.Bool
+	refreshInterval  time.Duration
 
 	config *config.Config
 
 	// Processing workers
-	scheduler    gocron.Scheduler
-	cetScheduler gocron.Scheduler
-	queue        *Queue
+	scheduler        gocron.Scheduler
+	cetScheduler     gocron.Scheduler
+	schedulerRunning atomic.Bool
+	queue            *Queue
 
 	// downloading
 	refreshSG   singleflight.Group
@@ -60,6 +68,7 @@ type Manager struct {
 	ctx   context.Context
 
 	customFolders *CustomFolders
+	views         map[string]*View
 	mountManager  MountManager
 
 	startTime     time.Time
@@ -76,6 +85,9 @@ type Manager struct {
 	// Active streams tracking
 	activeStreams *xsync.Map[string, *ActiveStream]
 
+	// When each entry's play was last persisted, keyed by infohash
+	lastPlays *xsync.Map[string, time.Time]
+
 	// In-flight queue-processor dispatches, keyed by InfoHash, to prevent
 	// duplicate goroutines from processing the same entry when the scheduler
 	// re-fires before the previous pass has updated the queue row.
@@ -85,6 +97,28 @@ type Manager struct {
 	jobQueue  *JobQueue
 	nzbSyncMu sync.Mutex
 
+	// Callers waiting on background NZB placements, keyed by infohash and target
+	placementWaitersMu sync.Mutex
+	placementWaiters   map[string][]chan error
+
+	// Runtime cap on local usenet download speed, set through SABnzbd speedlimit
+	speedLimit speedLimiter
+
+	// Cached results of the remote readiness checks
+	health *healthCache
+
+	// Per-entry event timelines
+	events *eventRecorder
+
+	// The configured tag rules, compiled by LoadTagRules
+	tagRules atomic.Pointer[[]*tagRule]
+
+	// Signalled when DFS cache pins change
+	pinsChanged chan struct{}
+
+	// Imported entries waiting for a media probe
+	mediaProbes chan *storage.Entry
+
 	// Notifications service
 	Notifications *notifications.Service
 }
@@ -143,16 +177,19 @@ func New() *Manager {
 		clients:                xsync.NewMap[string, debrid.Client](),
 		logger:                 _logger,
 		migrationJobs:          xsync.NewMap[string, *storage.SwitcherJob](),
+		switchBatches:          xsync.NewMap[string, *switchBatchRun](),
 		config:                 cfg,
 		arr:                    arr.NewStorage(),
-		queue:                  newQueue(strg, cfg.RemoveStalledAfter),
 		ctx:                    ctx,
 		ready:                  make(chan struct{}),
 		streamClient:           streamClient,
 		usenetTimeout:          usenetTimeout,
 		debridSpeedTestResults: xsync.NewMap[string, debridTypes.SpeedTestResult](),
 		activeStreams:          xsync.NewMap[string, *ActiveStream](),
+		lastPlays:              xsync.NewMap[string, time.Time](),
 		processingEntries:      xsync.NewMap[string, struct{}](),
+		pinsChanged:            make(chan struct{}, 1),
+		mediaProbes:            make(chan *storage.Entry, mediaProbeQueueSize),
 	}
 
 	instance.init()
@@ -181,7 +218,8 @@ func (m *Manager) init() {
 	m.config = cfg
 
 	// Recreate queue with new config
-	m.queue = newQueue(m.storage, cfg.RemoveStalledAfter)
+	m.events = newEventRecorder(m.storage, cfg.Events)
+	m.queue = newQueue(m.storage, cfg.RemoveStalledAfter, m.events)
 
 	// Clear debrid clients so they get recreated with new config
 	m.clients = xsync.NewMap[string, debrid.Client]()
@@ -192,6 +230,7 @@ func (m *Manager) init() {
 
 	m.scheduler = scheduler
 	m.cetScheduler = cetScheduler
+	m.health = &healthCache{}
 	m.migrator = NewMigrator(m.storage)
 	m.downloader = NewDownloadManager(m)
 
@@ -219,6 +258,12 @@ func (m *Manager) init() {
 	// Init custom folders
 	m.initCustomFolders()
 
+	// Compile tag rules
+	m.LoadTagRules()
+
+	// Init mount views
+	m.initViews()
+
 	// Initialize fixer
 	m.fixer = NewFixer(m)
 
@@ -244,6 +289,9 @@ func (m *Manager) initUsenet() {
 		m.usenet = nil
 		return
 	}
+	if m.storage != nil {
+		usenetClient.SetTrafficStore(m.storage)
+	}
 	m.usenet = usenetClient
 }
 
@@ -254,6 +302,7 @@ func (m *Manager) initLinkService() {
 		m.refreshTorrent,
 		m.ReinsertEntry,
 		func(entry *storage.Entry) error { return m.AddOrUpdate(entry, nil) },
+		m.recordLinkFailure,
 		m.streamClient,
 		m.config.Retries,
 		logger.New("link"),
@@ -404,6 +453,8 @@ func (m *Manager) Start(ctx context.Context) error {
 			m.logger.Info().Msg("Starting NZB file size correction as requested by environment variable")
 			m.fixNZBFileSizes(ctx)
 		}
+		// Bulk migrations interrupted by the last shutdown pick up where they stopped
+		m.resumeSwitchBatches()
 	}()
 
 	// Start workers
@@ -442,6 +493,7 @@ func (m *Manager) Stop() error {
 	}
 
 	// Stop schedulers
+	m.schedulerRunning.Store(false)
 	if m.scheduler != nil {
 		if err := m.scheduler.Shutdown(); err != nil {
 			m.logger.Warn().Err(err).Msg("Failed to shutdown scheduler")
@@ -460,6 +512,7 @@ func (m *Manager) Stop() error {
 
 	// Close usenet connection manager if active
 	if m.usenet != nil {
+		m.usenet.SaveTraffic()
 		m.logger.Info().Msg("Closing usenet connections")
 		if err := m.usenet.Close(); err != nil {
 			m.logger.Warn().Err(err).Msg("Failed to close usenet")
@@ -470,6 +523,14 @@ func (m *Manager) Stop() error {
 		m.repair.Stop()
 	}
 
+	m.stopSwitchBatches()
+
+	// Keep account traffic budgets across restarts
+	m.clients.Range(func(_ string, client debrid.Client) bool {
+		client.AccountManager().SaveTraffic()
+		return true
+	})
+
 	// Close storage
 	if m.storage != nil {
 		m.logger.Info().Msg("Closing storage database")
@@ -605,12 +666,18 @@ func (m *Manager) GetTorrentsCount() (int, error) {
 	return m.storage.Count()
 }
 
-// DeleteEntry deletes a torrent by infohash
-func (m *Manager) DeleteEntry(infohash string, removePlacements bool) error {
+// DeleteEntry deletes a torrent by infohash. recycle is set for deletes a
+// user starts: with the recycle bin enabled, an entry whose placements are
+// removed then goes to the bin instead. Internal deletes of failed or broken
+// entries never do.
+func (m *Manager) DeleteEntry(infohash string, removePlacements, recycle bool) error {
 	torr, err := m.GetEntry(infohash)
 	if err != nil {
 		return err
 	}
+	if removePlacements && recycle && config.Get().RecycleBin.Enabled {
+		return m.recycleEntry(torr)
+	}
 	// Delete active placements from debrid clients
 	if removePlacements {
 		go m.RemoveTorrentPlacements(torr)
@@ -619,14 +686,19 @@ func (m *Manager) DeleteEntry(infohash string, removePlacements bool) error {
 	if err := m.storage.Delete(infohash); err != nil {
 		return err
 	}
+	_ = m.storage.DeleteTorrentMetainfo(infohash)
+	_ = m.storage.DeleteNZBSource(infohash)
+	_ = m.storage.DeletePlayRecord(infohash)
+	m.lastPlays.Delete(strings.ToLower(infohash))
 	// Refresh entry cache
 	m.RefreshEntries(true)
 	return nil
 }
 
+// DeleteTorrents deletes entries a user picked
 func (m *Manager) DeleteTorrents(infohashes []string, removeFromDebrid bool) error {
 	for _, infohash := range infohashes {
-		if err := m.DeleteEntry(infohash, removeFromDebrid); err != nil {
+		if err := m.DeleteEntry(infohash, removeFromDebrid, true); err != nil {
 			return err
 		}
 	}
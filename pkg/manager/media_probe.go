@@ -0,0 +1,187 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/mediaprobe"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+const (
+	mediaProbeClient  = "media_probe"
+	mediaProbeTimeout = 2 * time.Minute
+
+	// Imports are probed by a few workers fed from a bounded queue; entries
+	// that don't fit are left to the repair sweep
+	mediaProbeWorkers   = 2
+	mediaProbeQueueSize = 256
+)
+
+// streamReaderAt exposes one entry file as an io.ReaderAt backed by ranged
+// Manager.Stream reads, so the container probe never downloads the payload.
+type streamReaderAt struct {
+	ctx      context.Context
+	manager  *Manager
+	entry    *storage.Entry
+	filename string
+	client   string
+}
+
+func (s *streamReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	w := &fixedBufferWriter{buf: p}
+	if err := s.manager.Stream(s.ctx, s.entry, s.filename, off, off+int64(len(p))-1, w, nil, s.client); err != nil {
+		return w.n, err
+	}
+	if w.n < len(p) {
+		return w.n, io.EOF
+	}
+	return w.n, nil
+}
+
+// fixedBufferWriter fills a caller-provided slice and drops anything past it.
+type fixedBufferWriter struct {
+	buf []byte
+	n   int
+}
+
+func (w *fixedBufferWriter) Write(p []byte) (int, error) {
+	w.n += copy(w.buf[w.n:], p)
+	return len(p), nil
+}
+
+// ProbeFileMedia reads the container headers of one file in an entry. It
+// returns mediaprobe.ErrUnsupported for files that are not MKV/WebM/MP4/MOV
+// or that live inside an archive.
+func (m *Manager) ProbeFileMedia(ctx context.Context, entry *storage.Entry, filename string) (*storage.MediaInfo, error) {
+	file, ok := entry.Files[filename]
+	if !ok || !file.Active() {
+		return nil, fmt.Errorf("file %s not found", filename)
+	}
+	if !mediaprobe.Supported(filename) || file.ByteRange != nil {
+		// Files extracted from archives are not addressable through Stream
+		// offsets, so only plain files are probed.
+		return nil, mediaprobe.ErrUnsupported
+	}
+	ctx, cancel := context.WithTimeout(ctx, mediaProbeTimeout)
+	defer cancel()
+
+	r := &streamReaderAt{ctx: ctx, manager: m, entry: entry, filename: filename, client: mediaProbeClient}
+	info, err := mediaprobe.Probe(r, file.Size, filename)
+	if err != nil {
+		return nil, err
+	}
+	return &storage.MediaInfo{
+		Container:         info.Container,
+		DurationSeconds:   info.Duration.Seconds(),
+		VideoCodec:        info.VideoCodec,
+		Width:             info.Width,
+		Height:            info.Height,
+		AudioCodecs:       info.AudioCodecs,
+		AudioLanguages:    info.AudioLanguages,
+		SubtitleLanguages: info.SubtitleLanguages,
+		Status:            string(info.Status),
+		Reason:            info.Reason,
+		ProbedAt:          time.Now(),
+	}, nil
+}
+
+// queueMediaProbe hands a freshly imported entry to the probe workers
+func (m *Manager) queueMediaProbe(entry *storage.Entry) {
+	if !config.Get().ProbeMedia {
+		return
+	}
+	select {
+	case m.mediaProbes <- entry:
+	default:
+		m.logger.Debug().Str("entry", entry.Name).Msg("Media probe queue full, leaving the entry to the repair sweep")
+	}
+}
+
+// startMediaProbers runs the import probe workers until ctx is done
+func (m *Manager) startMediaProbers(ctx context.Context) {
+	for range mediaProbeWorkers {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case entry := <-m.mediaProbes:
+					m.probeEntryMedia(ctx, entry)
+				}
+			}
+		}()
+	}
+}
+
+// probeEntryMedia probes every supported file of a freshly imported entry
+// that has not been probed yet and stores the results. Truncated or
+// unplayable files are logged and marked for the health checker.
+func (m *Manager) probeEntryMedia(ctx context.Context, entry *storage.Entry) {
+	if !config.Get().ProbeMedia {
+		return
+	}
+	results := make(map[string]*storage.MediaInfo)
+	for name, file := range entry.Files {
+		if !file.Active() || file.Media != nil || file.ByteRange != nil || !mediaprobe.Supported(name) {
+			continue
+		}
+		media, err := m.ProbeFileMedia(ctx, entry, name)
+		if err != nil {
+			m.logger.Debug().Err(err).Str("entry", entry.Name).Str("file", name).Msg("Media probe failed")
+			continue
+		}
+		if !media.Playable() {
+			m.logger.Warn().
+				Str("entry", entry.Name).
+				Str("file", name).
+				Str("status", media.Status).
+				Str("reason", media.Reason).
+				Msg("Imported file failed media probe")
+		}
+		results[name] = media
+	}
+	if len(results) == 0 {
+		return
+	}
+	if err := m.saveFileMedia(entry.InfoHash, results); err != nil {
+		m.logger.Warn().Err(err).Str("entry", entry.Name).Msg("Failed to save media probe results")
+		return
+	}
+	for _, media := range results {
+		if !media.Playable() {
+			m.storage.MarkEntryDirty(entry.GetFolder(), entry.Protocol, "media_"+media.Status)
+			break
+		}
+	}
+}
+
+// saveFileMedia attaches probe results to the stored entry. The entry is
+// re-read so concurrent updates made while probing are not lost.
+func (m *Manager) saveFileMedia(infohash string, results map[string]*storage.MediaInfo) error {
+	entry, err := m.storage.Get(infohash)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for name, media := range results {
+		if file, ok := entry.Files[name]; ok {
+			file.Media = media
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := m.storage.AddOrUpdate(entry); err != nil {
+		return err
+	}
+	m.entry.Invalidate(entry.GetFolder())
+	return nil
+}
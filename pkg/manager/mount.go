@@ -35,6 +35,7 @@ type MountManager interface {
 func (m *Manager) RefreshEntries(refreshMount bool) {
 	// Refresh entries
 	m.entry.Refresh()
+	m.invalidateViews()
 
 	// Refresh mount if needed
 	if refreshMount {
@@ -72,12 +72,13 @@ func convertToMultiSeason(torrent *storage.Entry, seasons []SeasonInfo) []*stora
 		size := int64(0)
 		for _, file := range seasonInfo.Files {
 			seasonFiles[file.Name] = &storage.File{
-				Name:      file.Name,
-				Size:      file.Size,
-				ByteRange: file.ByteRange,
-				Deleted:   file.Deleted,
-				InfoHash:  file.InfoHash,
-				AddedOn:   torrent.AddedOn,
+				Name:       file.Name,
+				Size:       file.Size,
+				ByteRange:  file.ByteRange,
+				Deleted:    file.Deleted,
+				InfoHash:   file.InfoHash,
+				AddedOn:    torrent.AddedOn,
+				Unselected: file.Unselected,
 			}
 			size += file.Size
 		}
@@ -0,0 +1,415 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/pkg/debrid/common"
+	debridTypes "github.com/sirrobot01/decypharr/pkg/debrid/types"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// nzbPollInterval paces status checks while a debrid usenet service fetches
+// an NZB that is moved or re-inserted outside the download queue.
+const nzbPollInterval = 5 * time.Second
+
+var ErrNoDebridUsenet = errors.New("no debrid usenet service configured")
+
+// ErrPlacementPending is returned when an NZB placement is still being
+// fetched. The entry is switched to it in the background once it is ready.
+var ErrPlacementPending = errors.New("nzb is still being fetched, the entry switches once it is ready")
+
+// nzbDebrids returns the debrids that take NZBs, in config order.
+func (m *Manager) nzbDebrids() []string {
+	names := make([]string, 0)
+	for _, dc := range config.Get().Debrids {
+		if !dc.Usenet {
+			continue
+		}
+		if _, ok := m.ProviderClient(dc.Name).(common.NZBClient); ok {
+			names = append(names, dc.Name)
+		}
+	}
+	return names
+}
+
+// HasDebridUsenet returns true if at least one debrid accepts NZBs.
+func (m *Manager) HasDebridUsenet() bool {
+	return len(m.nzbDebrids()) > 0
+}
+
+// acceptsNZB reports whether an NZB can be placed on provider, either the NNTP
+// providers ("usenet") or a debrid with usenet enabled.
+func (m *Manager) acceptsNZB(provider string) bool {
+	if provider == "usenet" {
+		return m.usenet != nil
+	}
+	for _, name := range m.nzbDebrids() {
+		if name == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// listRemote lists a debrid's torrents, and its usenet downloads when it runs
+// a usenet service.
+func listRemote(client common.Client) ([]*debridTypes.Torrent, error) {
+	if nzbClient, ok := client.(common.NZBClient); ok {
+		return nzbClient.GetIngests()
+	}
+	return client.GetTorrents()
+}
+
+// canHost reports whether target can take a placement of the entry.
+func (m *Manager) canHost(entry *storage.Entry, target string) bool {
+	if entry.IsNZB() {
+		return m.acceptsNZB(target)
+	}
+	return m.ProviderClient(target) != nil
+}
+
+// nzbProviderOrder returns where an NZB is tried, in order: NNTP first unless
+// debrids are preferred, then the debrid usenet services in config order.
+func (m *Manager) nzbProviderOrder() []string {
+	debrids := m.nzbDebrids()
+	if m.usenet == nil {
+		return debrids
+	}
+	if config.Get().Usenet.PreferDebrid {
+		return append(debrids, "usenet")
+	}
+	return append([]string{"usenet"}, debrids...)
+}
+
+// addDebridNZB submits an NZB to the debrid usenet services and queues it like
+// a torrent on whichever debrid accepts it.
+func (m *Manager) addDebridNZB(ctx context.Context, req *ImportRequest) (string, error) {
+	name := nzbName(req.Name)
+	debridTorrent, err := m.sendNZBToDebrid(ctx, req, req.Id, name, true)
+	if err != nil {
+		return "", err
+	}
+	if err := m.storage.SaveNZBSource(req.Id, req.NZBContent); err != nil {
+		m.logger.Warn().Err(err).Str("id", req.Id).Msg("Failed to persist NZB source")
+	}
+
+	entry := newNZBQueueEntry(req, req.Id, name, 0)
+	entry.DownloadUncached = true
+	applyDebridTorrentToEntry(entry, debridTorrent)
+	m.applyTagRules(entry, config.TagRuleOnAdd)
+	if err := m.queue.Add(entry); err != nil {
+		return "", fmt.Errorf("failed to add nzb to queue: %w", err)
+	}
+
+	req.Status = "started"
+	job := NewJob(JobTypeTorrent, req)
+	job.ID = entry.InfoHash
+	job.Entry = entry
+	job.DebridTorrent = debridTorrent
+	if err := m.SubmitJob(job); err != nil {
+		entry.MarkAsError(err)
+		_ = m.queue.Update(entry)
+		return "", fmt.Errorf("failed to queue NZB: %w", err)
+	}
+	return entry.InfoHash, nil
+}
+
+// sendNZBToDebrid submits an NZB to each debrid usenet service in turn and
+// returns the first download accepted. With wait unset the download must be
+// complete right away, as for torrent re-insertion.
+func (m *Manager) sendNZBToDebrid(ctx context.Context, req *ImportRequest, id, name string, wait bool) (*debridTypes.Torrent, error) {
+	debrids := req.orderProviders(m.nzbDebrids())
+	content := req.NZBContent
+	if len(debrids) == 0 {
+		return nil, ErrNoDebridUsenet
+	}
+	errs := make([]error, 0, len(debrids))
+	for _, debridName := range debrids {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		dbt, err := m.submitNZB(debridName, id, name, content, wait)
+		if err != nil {
+			m.RecordEvent(id, storage.EntryEventSubmitFailed, debridName, err.Error())
+			errs = append(errs, fmt.Errorf("%s: %w", debridName, err))
+			continue
+		}
+		m.RecordEvent(id, storage.EntryEventSubmitted, debridName, fmt.Sprintf("submitted as %s", dbt.Id))
+		return dbt, nil
+	}
+	return nil, fmt.Errorf("failed to submit nzb: %w", errors.Join(errs...))
+}
+
+func (m *Manager) submitNZB(debridName, id, name string, content []byte, wait bool) (*debridTypes.Torrent, error) {
+	client := m.ProviderClient(debridName)
+	nzbClient, ok := client.(common.NZBClient)
+	if !ok {
+		return nil, fmt.Errorf("debrid %s does not accept NZBs", debridName)
+	}
+	_logger := client.Logger()
+	_logger.Info().Str("id", id).Str("name", name).Msg("Submitting NZB to debrid usenet")
+
+	dbt, err := nzbClient.SubmitNZB(&debridTypes.Torrent{
+		InfoHash:         id,
+		Name:             name,
+		Files:            make(map[string]debridTypes.File),
+		DownloadUncached: wait,
+	}, content)
+	if err != nil {
+		return nil, err
+	}
+	if dbt == nil || dbt.Id == "" {
+		return nil, fmt.Errorf("nzb %s returned no download id", name)
+	}
+	dbt.InfoHash = id
+	dbt.DownloadUncached = wait
+
+	checked, err := client.CheckStatus(dbt)
+	if err != nil {
+		_ = client.DeleteTorrent(dbt.Id)
+		return nil, err
+	}
+	if checked == nil {
+		_ = client.DeleteTorrent(dbt.Id)
+		return nil, fmt.Errorf("nzb %s returned nil after checking status", name)
+	}
+	return checked, nil
+}
+
+// handNZBToDebrid moves a queued NZB whose NNTP import failed to a debrid
+// usenet service. The entry keeps its ID and continues through the queue as a
+// debrid download.
+func (m *Manager) handNZBToDebrid(ctx context.Context, job *Job, cause error) error {
+	if job.Request == nil || len(job.Request.NZBContent) == 0 {
+		return cause
+	}
+	entry := job.Entry
+	m.logger.Warn().Err(cause).Str("name", entry.Name).Msg("NNTP import failed, sending NZB to debrid usenet")
+
+	debridTorrent, err := m.sendNZBToDebrid(ctx, job.Request, entry.InfoHash, entry.Name, true)
+	if err != nil {
+		return errors.Join(cause, err)
+	}
+	if err := m.storage.SaveNZBSource(entry.InfoHash, job.Request.NZBContent); err != nil {
+		m.logger.Warn().Err(err).Str("id", entry.InfoHash).Msg("Failed to persist NZB source")
+	}
+	entry.RemoveProvider("usenet", m.RemoveFromProvider)
+	entry.Files = make(map[string]*storage.File)
+	entry.Status = debridTypes.TorrentStatusDownloading
+	entry.DownloadUncached = true
+	m.processNewTorrent(entry, debridTorrent)
+	return nil
+}
+
+// nzbSource returns the NZB an entry was imported from: the copy kept for
+// debrid imports, or the file of the NNTP import.
+func (m *Manager) nzbSource(entry *storage.Entry) ([]byte, error) {
+	if content, err := m.storage.GetNZBSource(entry.InfoHash); err == nil && len(content) > 0 {
+		return content, nil
+	}
+	if m.usenet != nil {
+		if meta, err := m.usenet.GetNZBHeader(entry.InfoHash); err == nil && meta != nil && meta.Path != "" {
+			return os.ReadFile(meta.Path)
+		}
+	}
+	return nil, fmt.Errorf("nzb source for %s not found", entry.Name)
+}
+
+// placeNZB adds a fresh placement of an NZB entry on target, a debrid usenet
+// service or the NNTP providers. A placement that is not ready right away is
+// finished in the background and ErrPlacementPending is returned.
+func (m *Manager) placeNZB(entry *storage.Entry, target string) (*storage.ProviderEntry, error) {
+	content, err := m.nzbSource(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if target == "usenet" {
+		if m.usenet == nil {
+			return nil, fmt.Errorf("usenet not configured")
+		}
+		go m.placeUsenetNZB(entry.InfoHash, entry.Name, entry.Category, content)
+		return nil, ErrPlacementPending
+	}
+
+	if !m.acceptsNZB(target) {
+		return nil, fmt.Errorf("debrid %s does not accept NZBs", target)
+	}
+	// Keep a copy so the entry can still move once the NNTP import is gone.
+	_ = m.storage.SaveNZBSource(entry.InfoHash, content)
+	dbt, err := m.submitNZB(target, entry.InfoHash, entry.Name, content, true)
+	if err != nil {
+		return nil, err
+	}
+	if dbt.Status != debridTypes.TorrentStatusDownloaded {
+		go m.awaitDebridNZB(entry.InfoHash, target, dbt)
+		return nil, ErrPlacementPending
+	}
+	return m.addDebridNZBPlacement(entry, target, dbt)
+}
+
+// placeUsenetNZB imports an NZB over the NNTP providers and switches the entry
+// to it.
+func (m *Manager) placeUsenetNZB(infohash, name, category string, content []byte) {
+	ctx, cancel := context.WithTimeout(m.ctx, m.usenetTimeout)
+	defer cancel()
+	meta, groups, err := m.usenet.ParseWithID(ctx, infohash, name, content, category)
+	if err != nil {
+		m.failNZBPlacement(infohash, "usenet", fmt.Errorf("usenet parse failed: %w", err))
+		return
+	}
+	meta, err = m.usenet.Process(ctx, meta, groups)
+	if err != nil {
+		m.failNZBPlacement(infohash, "usenet", fmt.Errorf("failed to process nzb: %w", err))
+		return
+	}
+	_ = m.completeNZBPlacement(infohash, "usenet", func(entry *storage.Entry) (*storage.ProviderEntry, error) {
+		placement := entry.AddUsenetProvider(meta)
+		now := time.Now()
+		placement.DownloadedAt = &now
+		placement.Progress = 1.0
+		files := make([]debridTypes.File, 0, len(meta.Files))
+		for _, f := range meta.Files {
+			files = append(files, debridTypes.File{Name: f.Name, Size: f.Size})
+		}
+		mergeEntryFiles(entry, files, now)
+		return placement, nil
+	})
+}
+
+// awaitDebridNZB polls a debrid usenet download until it is ready, then
+// switches the entry to it.
+func (m *Manager) awaitDebridNZB(infohash, target string, dbt *debridTypes.Torrent) {
+	ctx, cancel := context.WithTimeout(m.ctx, m.usenetTimeout)
+	defer cancel()
+	client := m.ProviderClient(target)
+	for dbt.Status != debridTypes.TorrentStatusDownloaded {
+		select {
+		case <-ctx.Done():
+			_ = client.DeleteTorrent(dbt.Id)
+			m.failNZBPlacement(infohash, target, fmt.Errorf("nzb not ready on %s: %w", target, ctx.Err()))
+			return
+		case <-time.After(nzbPollInterval):
+		}
+		checked, err := client.CheckStatus(dbt)
+		if err != nil {
+			_ = client.DeleteTorrent(dbt.Id)
+			m.failNZBPlacement(infohash, target, err)
+			return
+		}
+		dbt = checked
+	}
+	err := m.completeNZBPlacement(infohash, target, func(entry *storage.Entry) (*storage.ProviderEntry, error) {
+		return m.addDebridNZBPlacement(entry, target, dbt)
+	})
+	if err != nil {
+		_ = client.DeleteTorrent(dbt.Id)
+	}
+}
+
+// addDebridNZBPlacement records a finished debrid usenet download as a
+// placement of entry.
+func (m *Manager) addDebridNZBPlacement(entry *storage.Entry, target string, dbt *debridTypes.Torrent) (*storage.ProviderEntry, error) {
+	if len(dbt.Files) == 0 {
+		_ = m.ProviderClient(target).DeleteTorrent(dbt.Id)
+		return nil, fmt.Errorf("no files in nzb after submission")
+	}
+	placement := entry.AddTorrentProvider(dbt)
+	now := time.Now()
+	placement.DownloadedAt = &now
+	placement.Progress = 1.0
+	mergeEntryFiles(entry, dbt.GetFiles(), now)
+	return placement, nil
+}
+
+// completeNZBPlacement reloads the entry, adds the placement built by place
+// and switches the entry to it.
+func (m *Manager) completeNZBPlacement(infohash, target string, place func(*storage.Entry) (*storage.ProviderEntry, error)) error {
+	entry, err := m.storage.Get(infohash)
+	if err != nil {
+		m.logger.Warn().Err(err).Str("infohash", infohash).Msg("Entry gone before its NZB placement was ready")
+		m.settleNZBPlacement(infohash, target, err)
+		return err
+	}
+	from := entry.ActiveProvider
+	old := entry.Providers[target]
+	placement, err := place(entry)
+	if err != nil {
+		m.failNZBPlacement(infohash, target, err)
+		return err
+	}
+	m.fixer.activateNZBPlacement(entry, target, placement, old)
+	if err := m.AddOrUpdate(entry, func(*storage.Entry) {
+		m.RefreshEntries(false)
+	}); err != nil {
+		m.settleNZBPlacement(infohash, target, err)
+		return err
+	}
+	m.RecordEvent(infohash, storage.EntryEventSwitched, target, fmt.Sprintf("switched from %s", from))
+	m.settleNZBPlacement(infohash, target, nil)
+	return nil
+}
+
+func (m *Manager) failNZBPlacement(infohash, target string, err error) {
+	m.logger.Warn().Err(err).Str("infohash", infohash).Str("target", target).Msg("NZB placement failed")
+	m.RecordEvent(infohash, storage.EntryEventSwitchFailed, target, err.Error())
+	m.settleNZBPlacement(infohash, target, err)
+}
+
+func placementWaiterKey(infohash, target string) string {
+	return strings.ToLower(infohash) + "|" + target
+}
+
+// watchNZBPlacement returns a channel that receives the outcome of the next
+// background NZB placement of infohash on target: nil once the entry has
+// switched to it, or the error it failed with. Call it before the placement
+// is started so a fast one isn't missed, and call stop when done waiting.
+func (m *Manager) watchNZBPlacement(infohash, target string) (done <-chan error, stop func()) {
+	ch := make(chan error, 1)
+	key := placementWaiterKey(infohash, target)
+	m.placementWaitersMu.Lock()
+	if m.placementWaiters == nil {
+		m.placementWaiters = make(map[string][]chan error)
+	}
+	m.placementWaiters[key] = append(m.placementWaiters[key], ch)
+	m.placementWaitersMu.Unlock()
+	return ch, func() {
+		m.placementWaitersMu.Lock()
+		defer m.placementWaitersMu.Unlock()
+		waiters := slices.DeleteFunc(m.placementWaiters[key], func(c chan error) bool { return c == ch })
+		if len(waiters) == 0 {
+			delete(m.placementWaiters, key)
+		} else {
+			m.placementWaiters[key] = waiters
+		}
+	}
+}
+
+// settleNZBPlacement hands the outcome of a background NZB placement to
+// everyone watching it
+func (m *Manager) settleNZBPlacement(infohash, target string, err error) {
+	key := placementWaiterKey(infohash, target)
+	m.placementWaitersMu.Lock()
+	waiters := m.placementWaiters[key]
+	delete(m.placementWaiters, key)
+	m.placementWaitersMu.Unlock()
+	for _, ch := range waiters {
+		ch <- err
+	}
+}
+
+// nzbName strips the .nzb extension from an uploaded file name.
+func nzbName(name string) string {
+	if strings.EqualFold(filepath.Ext(name), ".nzb") {
+		return strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
@@ -0,0 +1,193 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/anacrolix/torrent/metainfo"
+
+	"github.com/sirrobot01/decypharr/internal/customerror"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+const (
+	repairDefaultPieceSamples = 4
+	repairPieceVerifyClient   = "repair:piece_verify"
+)
+
+// pieceSpan is a torrent piece whose bytes lie wholly inside one file.
+// fileOffset is relative to the start of that file, so the span can be
+// range-read straight off the file's debrid link.
+type pieceSpan struct {
+	index      int
+	fileOffset int64
+	length     int64
+	hash       metainfo.Hash
+}
+
+func (r *Repair) pieceVerifySamples() int {
+	if n := r.cfg().PieceVerifySamples; n > 0 {
+		return n
+	}
+	return repairDefaultPieceSamples
+}
+
+// usePieceVerify reports whether torrent probes in this run should check
+// piece hashes, as asked by the run or by repair.piece_verify.
+func (r *Repair) usePieceVerify(opts RepairRunOptions) bool {
+	return opts.PieceVerify || r.cfg().PieceVerify
+}
+
+// probeTorrentFileByPieces range-reads a sample of pieces through the debrid
+// link and compares their SHA-1 against the stored metainfo. The boolean is
+// false when piece verification cannot apply to this file (no metainfo,
+// archive-backed file, file not in the torrent) and the caller should fall
+// back to the regular probe.
+func (r *Repair) probeTorrentFileByPieces(ctx context.Context, entry *storage.Entry, file *storage.File, name string, res fileResult) (fileResult, bool) {
+	if file.ByteRange != nil {
+		// Files extracted from a RAR are not laid out like the torrent payload.
+		return res, false
+	}
+	data, err := r.manager.storage.GetTorrentMetainfo(entry.InfoHash)
+	if err != nil || len(data) == 0 {
+		return res, false
+	}
+	info, err := loadMetainfoInfo(data)
+	if err != nil {
+		r.logger.Debug().Err(err).Str("infohash", entry.InfoHash).Msg("Piece verify: invalid stored metainfo")
+		return res, false
+	}
+	length, spans, err := filePieceSpans(info, name, file.Path)
+	if err != nil {
+		r.logger.Debug().Err(err).Str("infohash", entry.InfoHash).Str("file", name).Msg("Piece verify: file not in metainfo")
+		return res, false
+	}
+	if length != file.Size {
+		res.broken = true
+		res.reason = "piece_size_mismatch"
+		return res, true
+	}
+	if len(spans) == 0 {
+		// File is smaller than a piece and shares every piece with its
+		// neighbours; nothing can be verified in isolation.
+		return res, false
+	}
+
+	for _, span := range samplePieceSpans(spans, r.pieceVerifySamples()) {
+		if ctx.Err() != nil {
+			res.reason = "context_cancelled"
+			return res, true
+		}
+		h := sha1.New()
+		err := r.manager.Stream(ctx, entry, name, span.fileOffset, span.fileOffset+span.length-1, h, nil, repairPieceVerifyClient)
+		if err != nil {
+			if errors.Is(err, customerror.HosterUnavailableError) {
+				res.broken = true
+				res.reason = "hoster_unavailable"
+			} else {
+				res.reason = "piece_read_error"
+			}
+			return res, true
+		}
+		if !bytes.Equal(h.Sum(nil), span.hash[:]) {
+			r.logger.Debug().
+				Str("infohash", entry.InfoHash).
+				Str("file", name).
+				Int("piece", span.index).
+				Msg("Piece verify: hash mismatch")
+			res.broken = true
+			res.reason = "piece_hash_mismatch"
+			return res, true
+		}
+	}
+	res.healthy = true
+	return res, true
+}
+
+func loadMetainfoInfo(data []byte) (*metainfo.Info, error) {
+	mi, err := metainfo.Load(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	info, err := mi.UnmarshalInfo()
+	if err != nil {
+		return nil, err
+	}
+	if info.PieceLength <= 0 || info.NumPieces() == 0 {
+		return nil, fmt.Errorf("metainfo has no pieces")
+	}
+	return &info, nil
+}
+
+// filePieceSpans locates a file inside the torrent payload and returns its
+// length and every piece that starts and ends within it. Files are matched by
+// full path first, then by base name when that is unambiguous.
+func filePieceSpans(info *metainfo.Info, name, filePath string) (int64, []pieceSpan, error) {
+	wantPath := strings.Trim(strings.ReplaceAll(filePath, "\\", "/"), "/")
+	var (
+		offset      int64
+		matchOffset int64 = -1
+		matchLength int64
+		baseMatches int
+		baseOffset  int64
+		baseLength  int64
+	)
+	for _, fi := range info.UpvertedFiles() {
+		display := fi.DisplayPath(info)
+		if wantPath != "" && (display == wantPath || strings.HasSuffix(wantPath, "/"+display) || strings.HasSuffix(display, "/"+wantPath)) {
+			matchOffset, matchLength = offset, fi.Length
+			break
+		}
+		if path.Base(display) == name {
+			baseMatches++
+			baseOffset, baseLength = offset, fi.Length
+		}
+		offset += fi.Length
+	}
+	if matchOffset < 0 {
+		if baseMatches != 1 {
+			return 0, nil, fmt.Errorf("file %s matched %d metainfo files", name, baseMatches)
+		}
+		matchOffset, matchLength = baseOffset, baseLength
+	}
+
+	pieceLength := info.PieceLength
+	end := matchOffset + matchLength
+	first := int((matchOffset + pieceLength - 1) / pieceLength)
+	spans := make([]pieceSpan, 0)
+	for i := first; i < info.NumPieces(); i++ {
+		piece := info.Piece(i)
+		if piece.Offset()+piece.Length() > end {
+			break
+		}
+		spans = append(spans, pieceSpan{
+			index:      i,
+			fileOffset: piece.Offset() - matchOffset,
+			length:     piece.Length(),
+			hash:       piece.Hash(),
+		})
+	}
+	return matchLength, spans, nil
+}
+
+// samplePieceSpans picks up to n spans spread evenly across the file, always
+// including the first and the last so truncated tails are caught.
+func samplePieceSpans(spans []pieceSpan, n int) []pieceSpan {
+	if n <= 0 || len(spans) <= n {
+		return spans
+	}
+	if n == 1 {
+		return []pieceSpan{spans[len(spans)-1]}
+	}
+	out := make([]pieceSpan, 0, n)
+	step := float64(len(spans)-1) / float64(n-1)
+	for i := range n {
+		out = append(out, spans[int(float64(i)*step+0.5)])
+	}
+	return out
+}
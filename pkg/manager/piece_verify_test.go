@@ -0,0 +1,109 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+// testInfo lays files out in pieces of pieceLength, each piece hash filled
+// with its index. Without files it is a single-file torrent of length bytes.
+func testInfo(name string, pieceLength, length int64, files ...metainfo.FileInfo) *metainfo.Info {
+	info := &metainfo.Info{Name: name, PieceLength: pieceLength, Files: files}
+	if len(files) == 0 {
+		info.Length = length
+	}
+	total := info.TotalLength()
+	for i := range int((total + pieceLength - 1) / pieceLength) {
+		for range 20 {
+			info.Pieces = append(info.Pieces, byte(i))
+		}
+	}
+	return info
+}
+
+func TestFilePieceSpans(t *testing.T) {
+	// Pieces of 10 bytes over a 25, 3 and 32 byte file: a.mkv ends mid-piece,
+	// b.nfo sits inside one piece and c.mkv starts mid-piece.
+	multi := testInfo("Show", 10, 0,
+		metainfo.FileInfo{Length: 25, Path: []string{"a.mkv"}},
+		metainfo.FileInfo{Length: 3, Path: []string{"b.nfo"}},
+		metainfo.FileInfo{Length: 32, Path: []string{"c.mkv"}},
+	)
+	single := testInfo("movie.mkv", 10, 25)
+	seasons := testInfo("Show", 10, 0,
+		metainfo.FileInfo{Length: 20, Path: []string{"S01", "e1.mkv"}},
+		metainfo.FileInfo{Length: 20, Path: []string{"S02", "e1.mkv"}},
+	)
+
+	// A span as index, file offset and length
+	tests := []struct {
+		name       string
+		info       *metainfo.Info
+		file       string
+		path       string
+		wantLength int64
+		want       [][3]int64
+		wantErr    bool
+	}{
+		{name: "file ending mid-piece", info: multi, file: "a.mkv", wantLength: 25, want: [][3]int64{{0, 0, 10}, {1, 10, 10}}},
+		{name: "file smaller than a piece", info: multi, file: "b.nfo", wantLength: 3},
+		{name: "file starting mid-piece", info: multi, file: "c.mkv", wantLength: 32, want: [][3]int64{{3, 2, 10}, {4, 12, 10}, {5, 22, 10}}},
+		{name: "single-file torrent with a short last piece", info: single, file: "movie.mkv", wantLength: 25, want: [][3]int64{{0, 0, 10}, {1, 10, 10}, {2, 20, 5}}},
+		{name: "matched by path", info: seasons, file: "e1.mkv", path: "Show/S02/e1.mkv", wantLength: 20, want: [][3]int64{{2, 0, 10}, {3, 10, 10}}},
+		{name: "ambiguous base name", info: seasons, file: "e1.mkv", wantErr: true},
+		{name: "not in the torrent", info: multi, file: "d.mkv", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			length, spans, err := filePieceSpans(tt.info, tt.file, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("filePieceSpans error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if length != tt.wantLength {
+				t.Errorf("length = %d, want %d", length, tt.wantLength)
+			}
+			var got [][3]int64
+			for _, s := range spans {
+				got = append(got, [3]int64{int64(s.index), s.fileOffset, s.length})
+				if s.hash != tt.info.Piece(s.index).Hash() {
+					t.Errorf("piece %d has the hash of another piece", s.index)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("spans = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSamplePieceSpans(t *testing.T) {
+	spans := make([]pieceSpan, 10)
+	for i := range spans {
+		spans[i].index = i
+	}
+	tests := []struct {
+		n    int
+		want []int
+	}{
+		{n: 0, want: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{n: 10, want: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{n: 1, want: []int{9}},
+		{n: 2, want: []int{0, 9}},
+		{n: 3, want: []int{0, 5, 9}},
+		{n: 4, want: []int{0, 3, 6, 9}},
+	}
+	for _, tt := range tests {
+		var got []int
+		for _, s := range samplePieceSpans(spans, tt.n) {
+			got = append(got, s.index)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("samplePieceSpans(n=%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// CachePins returns the DFS cache pins, oldest first
+func (m *Manager) CachePins() ([]storage.CachePin, error) {
+	return m.storage.GetCachePins()
+}
+
+// PinCache pins an entry (by infohash) or a category so DFS keeps its files
+// fully on the cache disk
+func (m *Manager) PinCache(typ storage.CachePinType, value string) (storage.CachePin, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return storage.CachePin{}, fmt.Errorf("pin value is required")
+	}
+	switch typ {
+	case storage.CachePinEntry:
+		entry, err := m.storage.Get(value)
+		if err != nil || entry == nil {
+			return storage.CachePin{}, fmt.Errorf("entry %s not found", value)
+		}
+		value = entry.InfoHash
+	case storage.CachePinCategory:
+	default:
+		return storage.CachePin{}, fmt.Errorf("unknown pin type %q", typ)
+	}
+
+	pin := storage.CachePin{Type: typ, Value: value, CreatedAt: time.Now()}
+	if err := m.storage.PutCachePin(pin); err != nil {
+		return storage.CachePin{}, err
+	}
+	m.notifyPinsChanged()
+	return pin, nil
+}
+
+// UnpinCache removes a pin. The files stay cached and become ordinary cache
+// items, evicted by age and size again.
+func (m *Manager) UnpinCache(typ storage.CachePinType, value string) error {
+	if err := m.storage.DeleteCachePin(typ, strings.TrimSpace(value)); err != nil {
+		return err
+	}
+	m.notifyPinsChanged()
+	return nil
+}
+
+// CachePinsChanged is signalled whenever a pin is added or removed
+func (m *Manager) CachePinsChanged() <-chan struct{} {
+	return m.pinsChanged
+}
+
+func (m *Manager) notifyPinsChanged() {
+	select {
+	case m.pinsChanged <- struct{}{}:
+	default:
+	}
+}
+
+// CachePinSet resolves pins to entry folders. It caches what it looked up, so
+// take a fresh one per pass.
+type CachePinSet struct {
+	storage    *storage.Storage
+	entries    map[string]string // lowercased infohash -> infohash
+	categories map[string]struct{}
+	folders    map[string]bool
+}
+
+// CachePinSet loads the current pins
+func (m *Manager) CachePinSet() (*CachePinSet, error) {
+	pins, err := m.storage.GetCachePins()
+	if err != nil {
+		return nil, err
+	}
+	set := &CachePinSet{
+		storage:    m.storage,
+		entries:    make(map[string]string),
+		categories: make(map[string]struct{}),
+		folders:    make(map[string]bool),
+	}
+	for _, pin := range pins {
+		switch pin.Type {
+		case storage.CachePinEntry:
+			set.entries[strings.ToLower(pin.Value)] = pin.Value
+		case storage.CachePinCategory:
+			set.categories[pin.Value] = struct{}{}
+		}
+	}
+	return set, nil
+}
+
+// Empty reports whether nothing is pinned
+func (p *CachePinSet) Empty() bool {
+	return p == nil || (len(p.entries) == 0 && len(p.categories) == 0)
+}
+
+// Folder reports whether an entry folder is pinned, directly or through its
+// category
+func (p *CachePinSet) Folder(name string) bool {
+	if p.Empty() {
+		return false
+	}
+	if pinned, ok := p.folders[name]; ok {
+		return pinned
+	}
+	pinned := false
+	if item, err := p.storage.GetEntryItem(name); err == nil && item != nil {
+		seen := make(map[string]struct{})
+		for _, f := range item.Files {
+			if !f.Active() || f.InfoHash == "" {
+				continue
+			}
+			if _, ok := seen[f.InfoHash]; ok {
+				continue
+			}
+			seen[f.InfoHash] = struct{}{}
+			if p.entryPinned(f.InfoHash) {
+				pinned = true
+				break
+			}
+		}
+	}
+	p.folders[name] = pinned
+	return pinned
+}
+
+func (p *CachePinSet) entryPinned(infohash string) bool {
+	if _, ok := p.entries[strings.ToLower(infohash)]; ok {
+		return true
+	}
+	if len(p.categories) == 0 {
+		return false
+	}
+	entry, err := p.storage.Get(infohash)
+	if err != nil || entry == nil {
+		return false
+	}
+	_, ok := p.categories[entry.Category]
+	return ok
+}
+
+// Folders returns every pinned entry folder, sorted. Category pins need a
+// pass over all entries.
+func (p *CachePinSet) Folders() []string {
+	if p.Empty() {
+		return nil
+	}
+	found := make(map[string]struct{})
+	for _, hash := range p.entries {
+		if entry, err := p.storage.Get(hash); err == nil && entry != nil {
+			if folder := entry.GetFolder(); folder != "" {
+				found[folder] = struct{}{}
+			}
+		}
+	}
+	if len(p.categories) > 0 {
+		_ = p.storage.ForEach(func(entry *storage.Entry) error {
+			if _, ok := p.categories[entry.Category]; ok {
+				if folder := entry.GetFolder(); folder != "" {
+					found[folder] = struct{}{}
+				}
+			}
+			return nil
+		})
+	}
+
+	folders := make([]string, 0, len(found))
+	for folder := range found {
+		p.folders[folder] = true
+		folders = append(folders, folder)
+	}
+	sort.Strings(folders)
+	return folders
+}
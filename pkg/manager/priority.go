@@ -0,0 +1,101 @@
+package manager
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// PriorityPaused holds a job back: it keeps its place among the pending jobs
+// but no worker picks it up until its priority is raised.
+const PriorityPaused = -2
+
+// QueuePositions returns where each entry waiting for a slot sits among the
+// pending jobs, keyed by infohash. Running entries are absent.
+func (m *Manager) QueuePositions() map[string]int {
+	if m.jobQueue == nil {
+		return nil
+	}
+	return m.jobQueue.Positions()
+}
+
+// SortByQueuePosition orders queued entries the way they are processed:
+// entries holding an active-download slot first, then those waiting for one
+// in the order they will be picked up.
+func (m *Manager) SortByQueuePosition(entries []*storage.Entry) {
+	positions := m.QueuePositions()
+	rank := func(e *storage.Entry) int {
+		if p, ok := positions[e.InfoHash]; ok {
+			return p
+		}
+		return -1
+	}
+	slices.SortStableFunc(entries, func(a, b *storage.Entry) int {
+		return cmp.Compare(rank(a), rank(b))
+	})
+}
+
+// SetEntryPriority changes the priority of a queued entry. A job still
+// waiting for a slot moves ahead of lower priorities. Returns the job's new
+// position, or -1 when the entry is no longer waiting.
+func (m *Manager) SetEntryPriority(infohash string, priority int) (int, error) {
+	entry, err := m.queue.GetTorrent(infohash)
+	if err != nil {
+		return -1, fmt.Errorf("entry %s not found", infohash)
+	}
+	position := -1
+	if m.jobQueue != nil {
+		position = m.jobQueue.SetPriority(infohash, priority)
+	}
+	entry.Priority = priority
+	if err := m.queue.Update(entry); err != nil {
+		return -1, fmt.Errorf("failed to update entry: %w", err)
+	}
+	return position, nil
+}
+
+// MoveEntry puts a waiting entry's job at position among the pending jobs,
+// as SABnzbd's switch does. The entry takes the priority of the job it
+// displaced. Returns the new position and priority.
+func (m *Manager) MoveEntry(infohash string, position int) (int, int, error) {
+	entry, err := m.queue.GetTorrent(infohash)
+	if err != nil {
+		return -1, 0, fmt.Errorf("entry %s not found", infohash)
+	}
+	if m.jobQueue == nil {
+		return -1, 0, fmt.Errorf("active download queue not initialized")
+	}
+	position, priority := m.jobQueue.Move(infohash, position)
+	if position < 0 {
+		return -1, entry.Priority, fmt.Errorf("entry %s is not waiting for a slot", entry.Name)
+	}
+	entry.Priority = priority
+	if err := m.queue.Update(entry); err != nil {
+		return -1, 0, fmt.Errorf("failed to update entry: %w", err)
+	}
+	return position, priority, nil
+}
+
+// SetEntryCategory moves an entry to another category. Its save path follows
+// the category folder, relinking a completed download as setLocation does.
+func (m *Manager) SetEntryCategory(infohash, category string) error {
+	category = strings.TrimSpace(category)
+	if !validEntryName(category) {
+		return fmt.Errorf("invalid category %q", category)
+	}
+	return m.relocateEntry(infohash, nil, func(entry *storage.Entry) error {
+		if entry.SavePath != "" && filepath.Base(entry.SavePath) == entry.Category {
+			entry.SavePath = filepath.Join(filepath.Dir(entry.SavePath), category)
+			if err := os.MkdirAll(entry.SavePath, os.ModePerm); err != nil {
+				return fmt.Errorf("failed to create %s: %w", entry.SavePath, err)
+			}
+		}
+		entry.Category = category
+		return nil
+	})
+}
@@ -1,10 +1,12 @@
 package manager
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/sirrobot01/decypharr/internal/config"
@@ -24,7 +26,6 @@ func (m *Manager) AddNewTorrent(ctx context.Context, importReq *ImportRequest) e
 	if importReq.Arr == nil {
 		return fmt.Errorf("arr is required")
 	}
-
 	debridTorrent, err := m.SendToDebrid(ctx, importReq)
 	if err != nil {
 		if isTooManyActiveDownloads(err) {
@@ -37,10 +38,12 @@ func (m *Manager) AddNewTorrent(ctx context.Context, importReq *ImportRequest) e
 	torrent := newTorrentQueueEntry(importReq, debridTypes.TorrentStatusQueued)
 	torrent.DownloadUncached = debridTorrent.DownloadUncached
 	applyDebridTorrentToEntry(torrent, debridTorrent)
+	m.applyTagRules(torrent, config.TagRuleOnAdd)
 
 	if err := m.queue.Add(torrent); err != nil {
 		return fmt.Errorf("failed to add torrent to queue: %w", err)
 	}
+	m.saveTorrentMetainfo(importReq.Magnet)
 
 	job := NewJob(JobTypeTorrent, importReq)
 	job.ID = torrent.InfoHash
@@ -92,9 +95,11 @@ func (m *Manager) processTorrentJob(ctx context.Context, job *Job) error {
 
 func (m *Manager) queueTorrentRetry(importReq *ImportRequest) error {
 	torrent := newTorrentQueueEntry(importReq, debridTypes.TorrentStatusQueued)
+	m.applyTagRules(torrent, config.TagRuleOnAdd)
 	if err := m.queue.Add(torrent); err != nil {
 		return fmt.Errorf("failed to add torrent to queue: %w", err)
 	}
+	m.saveTorrentMetainfo(importReq.Magnet)
 
 	importReq.Status = "queued"
 	importReq.CompletedAt = time.Time{}
@@ -128,6 +133,8 @@ func newTorrentQueueEntry(importReq *ImportRequest, status debridTypes.TorrentSt
 		Action:           importReq.Action,
 		CallbackURL:      importReq.CallBackUrl,
 		SkipMultiSeason:  importReq.SkipMultiSeason,
+		FileSelection:    importReq.FileSelection.Clone(),
+		Priority:         importReq.Priority,
 		CreatedAt:        now,
 		UpdatedAt:        now,
 		AddedOn:          now,
@@ -139,6 +146,18 @@ func newTorrentQueueEntry(importReq *ImportRequest, status debridTypes.TorrentSt
 	return torrent
 }
 
+// saveTorrentMetainfo keeps the .torrent bytes of a file-based import so the
+// piece_verify repair probe can check content against its piece hashes later.
+// It's called once the entry is queued, so a rejected submit leaves nothing.
+func (m *Manager) saveTorrentMetainfo(magnet *utils.Magnet) {
+	if magnet == nil || !magnet.IsTorrent() {
+		return
+	}
+	if err := m.storage.SaveTorrentMetainfo(magnet.InfoHash, magnet.File); err != nil {
+		m.logger.Warn().Err(err).Str("infohash", magnet.InfoHash).Msg("Failed to persist torrent metainfo")
+	}
+}
+
 func isTooManyActiveDownloads(err error) bool {
 	customErr, ok := errors.AsType[*customerror.Error](err)
 	return ok && customErr.Code == "too_many_active_downloads"
@@ -165,14 +184,15 @@ func (m *Manager) processQueuedEntries() {
 		if _, loaded := m.processingEntries.LoadOrStore(entry.InfoHash, struct{}{}); loaded {
 			continue
 		}
-		if entry.IsTorrent() {
+		if entry.OnUsenet() {
+			go m.processQueuedNZB(entry)
+		} else if entry.IsTorrent() || entry.IsNZB() {
+			// NZBs on a debrid usenet service are polled like torrents
 			if entry.ActiveProvider != "" {
 				go m.processQueuedTorrent(entry)
 			} else {
 				m.processingEntries.Delete(entry.InfoHash)
 			}
-		} else if entry.IsNZB() {
-			go m.processQueuedNZB(entry)
 		} else {
 			m.processingEntries.Delete(entry.InfoHash)
 		}
@@ -254,6 +274,7 @@ func (m *Manager) processQueuedTorrent(entry *storage.Entry) {
 		Size:             entry.Size,
 		Files:            make(map[string]debridTypes.File),
 		DownloadUncached: entry.DownloadUncached,
+		FileSelection:    entry.FileSelection,
 	}
 
 	dbT, err := client.CheckStatus(debridTorrent)
@@ -325,6 +346,7 @@ func (m *Manager) processAction(entry *storage.Entry) {
 	if existing, err := m.storage.Get(entry.InfoHash); err == nil && existing != nil {
 		entry = storage.HandleExistingEntryMerge(existing, entry)
 	}
+	m.applyTagRules(entry, config.TagRuleOnComplete)
 
 	// Now add entry to the main storage
 	if err := m.AddOrUpdate(entry, func(t *storage.Entry) {
@@ -335,6 +357,7 @@ func (m *Manager) processAction(entry *storage.Entry) {
 		_ = m.queue.Update(entry)
 		return
 	}
+	m.queueMediaProbe(entry)
 	err := m.downloader.download(entry)
 	if err != nil {
 		m.logger.Error().
@@ -371,21 +394,23 @@ func applyDebridTorrentToEntry(torrent *storage.Entry, debridTorrent *debridType
 	torrent.ActiveProvider = debridTorrent.Debrid
 	torrent.Bytes = debridTorrent.GetSize()
 	torrent.Size = debridTorrent.GetSize()
-	torrent.Name = debridTorrent.Name
+	torrent.Name = cmp.Or(torrent.NameOverride, debridTorrent.Name)
 	torrent.OriginalFilename = debridTorrent.OriginalFilename
 	torrent.UpdatedAt = time.Now()
 
 	for _, file := range debridTorrent.Files {
+		name := torrent.FileName(file.Name)
 		tFile := &storage.File{
-			Name:      file.Name,
+			Name:      name,
 			Size:      file.Size,
 			ByteRange: file.ByteRange,
 			Deleted:   file.Deleted,
 			InfoHash:  torrent.InfoHash,
 			AddedOn:   torrent.AddedOn,
 		}
-		torrent.Files[file.Name] = tFile
+		torrent.Files[name] = tFile
 	}
+	torrent.ApplyFileSelection()
 
 	if debridTorrent.Status != debridTypes.TorrentStatusDownloaded {
 		return
@@ -400,24 +425,33 @@ func applyDebridTorrentToEntry(torrent *storage.Entry, debridTorrent *debridType
 // SendToDebrid submits a magnet to debrid service(s) - replaces debrid.Parse
 func (m *Manager) SendToDebrid(ctx context.Context, importRequest *ImportRequest) (*debridTypes.Torrent, error) {
 	debridTorrent := &debridTypes.Torrent{
-		InfoHash: importRequest.Magnet.InfoHash,
-		Magnet:   importRequest.Magnet,
-		Name:     importRequest.Magnet.Name,
-		Arr:      importRequest.Arr,
-		Size:     importRequest.Magnet.Size,
-		Files:    make(map[string]debridTypes.File),
+		InfoHash:      importRequest.Magnet.InfoHash,
+		Magnet:        importRequest.Magnet,
+		Name:          importRequest.Magnet.Name,
+		Arr:           importRequest.Arr,
+		Size:          importRequest.Magnet.Size,
+		Files:         make(map[string]debridTypes.File),
+		FileSelection: importRequest.FileSelection,
 	}
 
+	if importRequest.SelectedDebrid != "" && !importRequest.allowsProvider(importRequest.SelectedDebrid) {
+		return nil, fmt.Errorf("debrid %s is not allowed by the category profile", importRequest.SelectedDebrid)
+	}
 	clients := m.FilterDebrid(func(c common.Client) bool {
 		if importRequest.SelectedDebrid != "" && c.Config().Name != importRequest.SelectedDebrid {
 			return false
 		}
-		return true
+		return importRequest.allowsProvider(c.Config().Name)
 	})
 
 	if len(clients) == 0 {
 		return nil, fmt.Errorf("no debrid clients available")
 	}
+	// The category profile's preferred debrid is tried first
+	order := importRequest.orderProviders(debridNames(clients))
+	slices.SortStableFunc(clients, func(a, b common.Client) int {
+		return cmp.Compare(slices.Index(order, a.Config().Name), slices.Index(order, b.Config().Name))
+	})
 
 	errs := make([]error, 0, len(clients))
 
@@ -438,14 +472,21 @@ func (m *Manager) SendToDebrid(ctx context.Context, importRequest *ImportRequest
 			Str("Name", debridTorrent.Name).
 			Str("Action", string(importRequest.Action)).
 			Msg("Processing torrent")
+		if !debridTorrent.FileSelection.IsZero() && !db.SupportsFileSelection() {
+			_logger.Debug().Str("Provider", db.Config().Name).Msg("Debrid fetches every file, the file selection only hides files in the mount")
+		}
 
 		dbt, err := db.SubmitMagnet(debridTorrent)
 		if err != nil || dbt == nil || dbt.Id == "" {
+			if err != nil {
+				m.RecordEvent(debridTorrent.InfoHash, storage.EntryEventSubmitFailed, db.Config().Name, err.Error())
+			}
 			errs = append(errs, err)
 			continue
 		}
 		dbt.Arr = importRequest.Arr
 		_logger.Info().Str("id", dbt.Id).Msgf("Entry: %s submitted to %s", dbt.Name, db.Config().Name)
+		m.RecordEvent(debridTorrent.InfoHash, storage.EntryEventSubmitted, db.Config().Name, fmt.Sprintf("submitted as %s", dbt.Id))
 
 		torrent, err := db.CheckStatus(dbt)
 		if err != nil && torrent != nil && torrent.Id != "" {
@@ -455,6 +496,7 @@ func (m *Manager) SendToDebrid(ctx context.Context, importRequest *ImportRequest
 			}(torrent.Id)
 		}
 		if err != nil {
+			m.RecordEvent(debridTorrent.InfoHash, storage.EntryEventSubmitFailed, db.Config().Name, err.Error())
 			errs = append(errs, err)
 			continue
 		}
@@ -470,3 +512,11 @@ func (m *Manager) SendToDebrid(ctx context.Context, importRequest *ImportRequest
 	joinedErrors := errors.Join(errs...)
 	return nil, fmt.Errorf("failed to process torrent: %w", joinedErrors)
 }
+
+func debridNames(clients []common.Client) []string {
+	names := make([]string, 0, len(clients))
+	for _, c := range clients {
+		names = append(names, c.Config().Name)
+	}
+	return names
+}
@@ -2,7 +2,9 @@ package manager
 
 import (
 	"cmp"
+	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -20,11 +22,13 @@ import (
 type ImportType string
 
 const (
-	ImportTypeQBit    ImportType = "qbit"
-	ImportTypeAPI     ImportType = "api"
-	ImportTypeSABnzbd ImportType = "sabnzbd"
-	ImportTypeWatch   ImportType = "watch"
-	ImportSwitcher    ImportType = "switcher"
+	ImportTypeQBit         ImportType = "qbit"
+	ImportTypeAPI          ImportType = "api"
+	ImportTypeSABnzbd      ImportType = "sabnzbd"
+	ImportTypeNZBGet       ImportType = "nzbget"
+	ImportTypeTransmission ImportType = "transmission"
+	ImportTypeWatch        ImportType = "watch"
+	ImportSwitcher         ImportType = "switcher"
 )
 
 type ImportRequest struct {
@@ -39,6 +43,11 @@ type ImportRequest struct {
 	DownloadUncached *bool                 `json:"downloadUncached"`
 	CallBackUrl      string                `json:"callBackUrl"`
 	SkipMultiSeason  bool                  `json:"skip_multi_season"`
+	FileSelection    *config.FileSelection `json:"file_selection,omitempty"`
+	PreferredDebrid  string                `json:"preferred_debrid,omitempty"` // From the category profile; tried first
+	AllowedDebrids   []string              `json:"allowed_debrids,omitempty"`  // From the category profile; empty allows all
+	Priority         int                   `json:"priority,omitempty"`
+	PPParameters     map[string]string     `json:"pp_parameters,omitempty"`
 
 	Status      string    `json:"status"`
 	CompletedAt time.Time `json:"completedAt"`
@@ -48,8 +57,9 @@ type ImportRequest struct {
 	Async bool       `json:"async"`
 }
 
-func NewTorrentRequest(debrid string, downloadFolder string, magnet *utils.Magnet, arr *arr.Arr, action config.DownloadAction, downloadUncached *bool, callBackUrl string, importType ImportType, skipMultiSeason bool) *ImportRequest {
-
+// NewTorrentRequest builds a torrent import under policy, the category
+// profile the caller resolved with any per-request overrides applied.
+func NewTorrentRequest(debrid string, downloadFolder string, magnet *utils.Magnet, arr *arr.Arr, policy config.Policy, downloadUncached *bool, callBackUrl string, importType ImportType) *ImportRequest {
 	return &ImportRequest{
 		Id:               uuid.New().String(),
 		Status:           "started",
@@ -57,15 +67,19 @@ func NewTorrentRequest(debrid string, downloadFolder string, magnet *utils.Magne
 		SelectedDebrid:   cmp.Or(arr.SelectedDebrid, debrid), // Use debrid from arr if available
 		Magnet:           magnet,
 		Arr:              arr,
-		Action:           action,
+		Action:           policy.DownloadAction,
 		DownloadUncached: downloadUncached,
 		CallBackUrl:      callBackUrl,
 		Type:             importType,
-		SkipMultiSeason:  skipMultiSeason,
+		SkipMultiSeason:  policy.SkipMultiSeason,
+		FileSelection:    policy.FileSelection,
+		PreferredDebrid:  policy.PreferredDebrid,
+		AllowedDebrids:   policy.AllowedDebrids,
 	}
 }
 
-func NewNZBRequest(name, downloadFolder string, nzbContent []byte, arr *arr.Arr, action config.DownloadAction, callBackUrl string, importType ImportType, skipMultiSeason bool) *ImportRequest {
+// NewNZBRequest builds an NZB import under policy, like NewTorrentRequest.
+func NewNZBRequest(name, downloadFolder string, nzbContent []byte, arr *arr.Arr, policy config.Policy, callBackUrl string, importType ImportType) *ImportRequest {
 	return &ImportRequest{
 		Name:            name,
 		Id:              uuid.New().String(),
@@ -74,23 +88,51 @@ func NewNZBRequest(name, downloadFolder string, nzbContent []byte, arr *arr.Arr,
 		SelectedDebrid:  "usenet", // NZB imports always use usenet
 		NZBContent:      nzbContent,
 		Arr:             arr,
-		Action:          action,
+		Action:          policy.DownloadAction,
 		CallBackUrl:     callBackUrl,
 		Type:            importType,
-		SkipMultiSeason: skipMultiSeason,
+		SkipMultiSeason: policy.SkipMultiSeason,
+		FileSelection:   policy.FileSelection,
+		PreferredDebrid: policy.PreferredDebrid,
+		AllowedDebrids:  policy.AllowedDebrids,
+	}
+}
+
+// allowsProvider reports whether the request's category profile lets it go
+// to a debrid, or to "usenet" for NNTP.
+func (r *ImportRequest) allowsProvider(name string) bool {
+	return config.Policy{AllowedDebrids: r.AllowedDebrids}.AllowsDebrid(name)
+}
+
+// orderProviders drops the providers the request may not use and moves its
+// preferred one to the front, keeping the rest in order.
+func (r *ImportRequest) orderProviders(names []string) []string {
+	ordered := make([]string, 0, len(names))
+	for _, name := range names {
+		if !r.allowsProvider(name) {
+			continue
+		}
+		if r.PreferredDebrid != "" && strings.EqualFold(name, r.PreferredDebrid) {
+			ordered = slices.Insert(ordered, 0, name)
+			continue
+		}
+		ordered = append(ordered, name)
 	}
+	return ordered
 }
 
 type Queue struct {
 	storage            *storage.Storage
 	logger             zerolog.Logger
 	removeStalledAfter time.Duration
+	events             *eventRecorder
 }
 
-func newQueue(storage *storage.Storage, removeStalledAfterStr string) *Queue {
+func newQueue(storage *storage.Storage, removeStalledAfterStr string, events *eventRecorder) *Queue {
 	q := &Queue{
 		storage: storage,
 		logger:  logger.New("queue"),
+		events:  events,
 	}
 
 	if removeStalledAfterStr != "" {
@@ -104,7 +146,13 @@ func newQueue(storage *storage.Storage, removeStalledAfterStr string) *Queue {
 }
 
 func (q *Queue) Add(torrent *storage.Entry) error {
-	return q.storage.AddQueue(torrent)
+	if err := q.storage.AddQueue(torrent); err != nil {
+		return err
+	}
+	q.events.record(torrent.InfoHash, storage.EntryEventAdded, "", fmt.Sprintf("%s added to %s", torrent.Name, torrent.Category))
+	q.events.recordStatus(torrent)
+	publishQueue(QueueEventAdded, torrent)
+	return nil
 }
 
 func (q *Queue) GetTorrent(infohash string) (*storage.Entry, error) {
@@ -124,8 +172,16 @@ func (q *Queue) deleteEntryFiles(entry *storage.Entry) {
 	}
 }
 
+// deleted is told about every entry leaving the queue
+func (q *Queue) deleted(entry *storage.Entry) error {
+	q.events.forget(entry.InfoHash)
+	publishQueueDeleted(entry.InfoHash)
+	return nil
+}
+
 func (q *Queue) wrapCleanupWithFileDelete(cleanup func(t *storage.Entry) error) func(*storage.Entry) error {
 	return func(entry *storage.Entry) error {
+		_ = q.deleted(entry)
 		q.deleteEntryFiles(entry)
 		if cleanup != nil {
 			return cleanup(entry)
@@ -160,12 +216,17 @@ func (q *Queue) DeleteStalled() error {
 			return true
 		}
 		return false
-	}, nil)
+	}, q.deleted)
 }
 
 func (q *Queue) Update(torrent *storage.Entry) error {
 	// Update the state here
-	return q.storage.UpdateQueue(torrent)
+	if err := q.storage.UpdateQueue(torrent); err != nil {
+		return err
+	}
+	q.events.recordStatus(torrent)
+	publishQueue(QueueEventUpdated, torrent)
+	return nil
 }
 
 func (q *Queue) ListFilterFunc(category string, protocol config.Protocol, state storage.TorrentState, hashes []string) func(*storage.Entry) bool {
@@ -239,5 +300,11 @@ func (q *Queue) ListFilter(category string, protocol config.Protocol, state stor
 }
 
 func (q *Queue) UpdateWhere(predicate func(*storage.Entry) bool, updateFunc func(*storage.Entry) bool) error {
-	return q.storage.UpdateWhereQueued(predicate, updateFunc)
+	return q.storage.UpdateWhereQueued(predicate, func(entry *storage.Entry) bool {
+		if !updateFunc(entry) {
+			return false
+		}
+		publishQueue(QueueEventUpdated, entry)
+		return true
+	})
 }
@@ -0,0 +1,427 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	debrid "github.com/sirrobot01/decypharr/pkg/debrid/common"
+	"github.com/sirrobot01/decypharr/pkg/debrid/types"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+const reconcileHistoryRetained = 50
+
+var ErrReconcileRunning = errors.New("reconciliation already running")
+
+// ReconcileOptions controls a reconciliation pass. An empty action only
+// reports the finding.
+type ReconcileOptions struct {
+	Providers  []string // Empty means every configured debrid
+	DryRun     bool
+	Orphans    storage.ReconcileAction // adopt | delete
+	Duplicates storage.ReconcileAction // delete
+	Missing    storage.ReconcileAction // readd | remove
+}
+
+// ReconcileOptionsFromConfig builds the options of a scheduled run.
+func ReconcileOptionsFromConfig(cfg config.ReconcileConfig) ReconcileOptions {
+	return ReconcileOptions{
+		DryRun:     cfg.DryRun,
+		Orphans:    storage.ReconcileAction(strings.ToLower(strings.TrimSpace(cfg.Orphans))),
+		Duplicates: storage.ReconcileAction(strings.ToLower(strings.TrimSpace(cfg.Duplicates))),
+		Missing:    storage.ReconcileAction(strings.ToLower(strings.TrimSpace(cfg.Missing))),
+	}
+}
+
+// Validate rejects actions that do not apply to their class.
+func (o ReconcileOptions) Validate() error {
+	switch o.Orphans {
+	case storage.ReconcileActionNone, storage.ReconcileActionAdopt, storage.ReconcileActionDelete:
+	default:
+		return fmt.Errorf("invalid orphans action %q", o.Orphans)
+	}
+	switch o.Duplicates {
+	case storage.ReconcileActionNone, storage.ReconcileActionDelete:
+	default:
+		return fmt.Errorf("invalid duplicates action %q", o.Duplicates)
+	}
+	switch o.Missing {
+	case storage.ReconcileActionNone, storage.ReconcileActionReAdd, storage.ReconcileActionRemove:
+	default:
+		return fmt.Errorf("invalid missing action %q", o.Missing)
+	}
+	return nil
+}
+
+func (o ReconcileOptions) actionFor(class storage.ReconcileClass) storage.ReconcileAction {
+	switch class {
+	case storage.ReconcileOrphaned:
+		return o.Orphans
+	case storage.ReconcileDuplicate:
+		return o.Duplicates
+	case storage.ReconcileMissing:
+		return o.Missing
+	}
+	return storage.ReconcileActionNone
+}
+
+// localTorrent is the slice of an entry reconciliation needs; keeping only
+// this avoids holding every entry in memory during the pass.
+type localTorrent struct {
+	name       string
+	placements map[string]string // provider -> torrent ID
+}
+
+// StartReconcile runs a reconciliation pass in the background and returns the
+// run record. The stored record is updated after each debrid is checked and
+// when the pass finishes.
+func (m *Manager) StartReconcile(trigger storage.RepairRunTrigger, opts ReconcileOptions) (*storage.ReconcileRun, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	for _, name := range opts.Providers {
+		if m.ProviderClient(name) == nil {
+			return nil, fmt.Errorf("debrid %s not found", name)
+		}
+	}
+	if !m.reconcileRunning.CompareAndSwap(false, true) {
+		return nil, ErrReconcileRunning
+	}
+	run := &storage.ReconcileRun{
+		ID:        uuid.New().String(),
+		Trigger:   trigger,
+		Status:    storage.RepairRunRunning,
+		DryRun:    opts.DryRun,
+		Providers: opts.Providers,
+		StartedAt: time.Now(),
+		Items:     make([]*storage.ReconcileItem, 0),
+	}
+	if err := m.storage.SaveReconcileRun(run); err != nil {
+		m.reconcileRunning.Store(false)
+		return nil, err
+	}
+	go func() {
+		defer m.reconcileRunning.Store(false)
+		m.reconcile(m.ctx, run, opts)
+	}()
+	return run, nil
+}
+
+func (m *Manager) reconcile(ctx context.Context, run *storage.ReconcileRun, opts ReconcileOptions) {
+	m.logger.Info().Str("run_id", run.ID).Bool("dry_run", opts.DryRun).Msg("Starting library reconciliation")
+
+	local := make(map[string]*localTorrent)
+	// NZB downloads on a debrid usenet service have no infohash on the debrid
+	// side, so every placement is also indexed by provider and ID.
+	placed := make(map[string]bool)
+	err := m.storage.ForEach(func(e *storage.Entry) error {
+		for name, p := range e.Providers {
+			if p != nil {
+				placed[name+"/"+p.ID] = true
+			}
+		}
+		lt := &localTorrent{name: e.Name, placements: make(map[string]string, len(e.Providers))}
+		for name, p := range e.Providers {
+			if p != nil {
+				lt.placements[name] = p.ID
+			}
+		}
+		local[e.InfoHash] = lt
+		return nil
+	})
+	if err != nil {
+		m.finishReconcile(run, storage.RepairRunFailed, fmt.Sprintf("failed to read entries: %v", err))
+		return
+	}
+	// Torrents still importing are not in the entry store yet; count them as
+	// tracked so they are never taken for orphans.
+	queued := make(map[string]bool)
+	_, _ = m.storage.FilterQueued(func(e *storage.Entry) bool {
+		queued[e.InfoHash] = true
+		for name, p := range e.Providers {
+			if p != nil {
+				placed[name+"/"+p.ID] = true
+			}
+		}
+		return false
+	})
+
+	providers := opts.Providers
+	if len(providers) == 0 {
+		for _, dc := range config.Get().Debrids {
+			if m.ProviderClient(dc.Name) != nil {
+				providers = append(providers, dc.Name)
+			}
+		}
+	}
+
+	for _, provider := range providers {
+		if ctx.Err() != nil {
+			m.finishReconcile(run, storage.RepairRunCancelled, "")
+			return
+		}
+		remote, err := listRemote(m.ProviderClient(provider))
+		if err != nil {
+			if run.Errors == nil {
+				run.Errors = make(map[string]string)
+			}
+			run.Errors[provider] = err.Error()
+			m.logger.Warn().Err(err).Str("debrid", provider).Msg("Reconciliation could not list torrents")
+			continue
+		}
+		// A debrid with a limit may list no more than that many torrents, so
+		// the placements past the listing can't be told apart from gone ones
+		truncated := false
+		for _, dc := range config.Get().Debrids {
+			if dc.Name == provider && dc.Limit > 0 && len(remote) >= dc.Limit {
+				truncated = true
+				m.logger.Warn().Str("debrid", provider).Int("limit", dc.Limit).Msg("Torrent list may be cut off at the debrid limit; not checking for missing placements")
+			}
+		}
+		m.reconcileProvider(run, provider, remote, local, queued, placed, truncated)
+		if err := m.storage.SaveReconcileRun(run); err != nil {
+			m.logger.Warn().Err(err).Str("run_id", run.ID).Msg("Failed to save reconciliation progress")
+		}
+	}
+
+	if !opts.DryRun {
+		cancelled := false
+		for _, item := range run.Items {
+			if ctx.Err() != nil {
+				cancelled = true
+				break
+			}
+			action := opts.actionFor(item.Class)
+			if action == storage.ReconcileActionNone {
+				continue
+			}
+			// A missing placement with another copy on the debrid is pointed at
+			// that copy rather than re-added or dropped
+			if item.StandInID != "" {
+				action = storage.ReconcileActionAdopt
+			}
+			item.Action = action
+			if err := m.applyReconcileItem(item, action); err != nil {
+				item.Error = err.Error()
+				run.Stats.Failed++
+				continue
+			}
+			item.Applied = true
+			run.Stats.Applied++
+		}
+		if run.Stats.Applied > 0 {
+			m.RefreshEntries(true)
+		}
+		if cancelled {
+			m.finishReconcile(run, storage.RepairRunCancelled, "")
+			return
+		}
+	}
+	m.finishReconcile(run, storage.RepairRunCompleted, "")
+}
+
+// reconcileProvider classifies the remote torrents of one debrid and the local
+// placements it no longer has.
+// With truncated set the listing may be incomplete, so no placement is
+// reported missing.
+func (m *Manager) reconcileProvider(run *storage.ReconcileRun, provider string, remote []*types.Torrent, local map[string]*localTorrent, queued, placed map[string]bool, truncated bool) {
+	// Oldest first, so the original upload of an untracked torrent is the
+	// orphan and later copies are duplicates.
+	slices.SortStableFunc(remote, func(a, b *types.Torrent) int {
+		return a.Added.Compare(b.Added)
+	})
+	seenIDs := make(map[string]bool, len(remote))
+	for _, t := range remote {
+		seenIDs[t.Id] = true
+	}
+	// Entries whose placement ID on this debrid is gone. A remote copy of the
+	// same infohash under another ID is then the entry's only real copy, so
+	// it must not also be offered for deletion as a duplicate.
+	missing := make(map[string]bool)
+	for infohash, lt := range local {
+		if id, ok := lt.placements[provider]; ok && !seenIDs[id] && !truncated {
+			missing[infohash] = true
+		}
+	}
+
+	firstSeen := make(map[string]bool)
+	standIns := make(map[string]string)
+	for _, t := range remote {
+		run.Stats.Remote++
+		var class storage.ReconcileClass
+		_, known := local[t.InfoHash]
+		switch {
+		case placed[provider+"/"+t.Id], !known && queued[t.InfoHash]:
+			run.Stats.Tracked++
+			continue
+		case known && missing[t.InfoHash] && !firstSeen[t.InfoHash]:
+			// Stands in for the stale placement, which is reported as missing.
+			firstSeen[t.InfoHash] = true
+			standIns[t.InfoHash] = t.Id
+			run.Stats.Tracked++
+			continue
+		case known:
+			class = storage.ReconcileDuplicate
+		case t.InfoHash != "" && firstSeen[t.InfoHash]:
+			class = storage.ReconcileDuplicate
+		default:
+			class = storage.ReconcileOrphaned
+		}
+		firstSeen[t.InfoHash] = true
+		size := t.Size
+		if size == 0 {
+			size = t.Bytes
+		}
+		run.Items = append(run.Items, &storage.ReconcileItem{
+			Class:     class,
+			Provider:  provider,
+			TorrentID: t.Id,
+			InfoHash:  t.InfoHash,
+			Name:      t.Name,
+			Size:      size,
+			Status:    string(t.Status),
+		})
+		if class == storage.ReconcileOrphaned {
+			run.Stats.Orphaned++
+		} else {
+			run.Stats.Duplicate++
+		}
+	}
+
+	for infohash := range missing {
+		lt := local[infohash]
+		run.Stats.Missing++
+		run.Items = append(run.Items, &storage.ReconcileItem{
+			Class:     storage.ReconcileMissing,
+			Provider:  provider,
+			TorrentID: lt.placements[provider],
+			InfoHash:  infohash,
+			Name:      lt.name,
+			StandInID: standIns[infohash],
+		})
+	}
+}
+
+func (m *Manager) applyReconcileItem(item *storage.ReconcileItem, action storage.ReconcileAction) error {
+	client := m.ProviderClient(item.Provider)
+	if client == nil {
+		return fmt.Errorf("debrid %s not found", item.Provider)
+	}
+	switch action {
+	case storage.ReconcileActionDelete:
+		return client.DeleteTorrent(item.TorrentID)
+
+	case storage.ReconcileActionAdopt:
+		if item.InfoHash == "" {
+			return fmt.Errorf("transfer has no infohash to adopt")
+		}
+		if item.StandInID != "" {
+			return m.adoptStandIn(client, item)
+		}
+		t, err := client.GetTorrent(item.TorrentID)
+		if err != nil {
+			return err
+		}
+		entry, err := m.processSyncTorrent(t)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			return fmt.Errorf("torrent is not downloaded yet")
+		}
+		return m.AddOrUpdate(entry, nil)
+
+	case storage.ReconcileActionReAdd, storage.ReconcileActionRemove:
+		entry, err := m.storage.Get(item.InfoHash)
+		if err != nil {
+			return err
+		}
+		// Only the active placement is worth re-adding; a stale secondary
+		// placement is dropped since the entry is served from elsewhere.
+		if action == storage.ReconcileActionReAdd && entry.ActiveProvider == item.Provider {
+			if _, err := m.fixer.MoveTorrent(entry, item.Provider, true); err != nil {
+				return err
+			}
+			return nil
+		}
+		entry.RemoveProvider(item.Provider, nil)
+		if len(entry.Providers) == 0 {
+			return m.DeleteEntry(entry.InfoHash, false, false)
+		}
+		return m.AddOrUpdate(entry, nil)
+	}
+	return fmt.Errorf("unknown action %q", action)
+}
+
+// adoptStandIn re-points a missing placement at the copy of the same torrent
+// the debrid still lists under another ID.
+func (m *Manager) adoptStandIn(client debrid.Client, item *storage.ReconcileItem) error {
+	entry, err := m.storage.Get(item.InfoHash)
+	if err != nil {
+		return err
+	}
+	t, err := client.GetTorrent(item.StandInID)
+	if err != nil {
+		return err
+	}
+	if t.Debrid == "" {
+		t.Debrid = item.Provider
+	}
+	entry.AddTorrentProvider(t)
+	mergeEntryFiles(entry, t.GetFiles(), time.Now())
+	return m.AddOrUpdate(entry, nil)
+}
+
+func (m *Manager) finishReconcile(run *storage.ReconcileRun, status storage.RepairRunStatus, errStr string) {
+	run.Status = status
+	run.Error = errStr
+	run.CompletedAt = time.Now()
+	if err := m.storage.SaveReconcileRun(run); err != nil {
+		m.logger.Warn().Err(err).Str("run_id", run.ID).Msg("Failed to save reconciliation run")
+	}
+	_ = m.storage.PruneReconcileRuns(reconcileHistoryRetained)
+	m.logger.Info().
+		Str("run_id", run.ID).
+		Str("status", string(status)).
+		Int("remote", run.Stats.Remote).
+		Int("orphaned", run.Stats.Orphaned).
+		Int("duplicate", run.Stats.Duplicate).
+		Int("missing", run.Stats.Missing).
+		Int("applied", run.Stats.Applied).
+		Msg("Library reconciliation finished")
+}
+
+// scheduleReconcile registers the periodic reconciliation from config.
+func (m *Manager) scheduleReconcile(ctx context.Context) {
+	cfg := m.config.Reconcile
+	if strings.TrimSpace(cfg.Schedule) == "" {
+		return
+	}
+	opts := ReconcileOptionsFromConfig(cfg)
+	if err := opts.Validate(); err != nil {
+		m.logger.Error().Err(err).Msg("Invalid reconcile config, scheduled reconciliation disabled")
+		return
+	}
+	jd, err := utils.ConvertToJobDef(cfg.Schedule)
+	if err != nil {
+		m.logger.Error().Err(err).Str("schedule", cfg.Schedule).Msg("Failed to convert reconcile schedule to job definition")
+		return
+	}
+	if _, err := m.scheduler.NewJob(jd, gocron.NewTask(func() {
+		if _, err := m.StartReconcile(storage.RepairTriggerScheduled, opts); err != nil {
+			m.logger.Warn().Err(err).Msg("Scheduled reconciliation skipped")
+		}
+	}), gocron.WithContext(ctx), gocron.WithName("reconcile")); err != nil {
+		m.logger.Error().Err(err).Msg("Failed to create reconcile job")
+		return
+	}
+	m.logger.Debug().Msgf("Library reconciliation scheduled for %s", cfg.Schedule)
+}
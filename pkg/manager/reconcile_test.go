@@ -0,0 +1,198 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/pkg/debrid/types"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+func TestReconcileProvider(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	remote := func(id, hash string, age int) *types.Torrent {
+		return &types.Torrent{Id: id, InfoHash: hash, Name: hash, Added: base.Add(time.Duration(age) * time.Hour)}
+	}
+	local := func(hash string, placements map[string]string) map[string]*localTorrent {
+		return map[string]*localTorrent{hash: {name: hash, placements: placements}}
+	}
+
+	// An item as class/torrent ID/stand-in ID
+	type want struct {
+		class   storage.ReconcileClass
+		id      string
+		standIn string
+	}
+	tests := []struct {
+		name      string
+		remote    []*types.Torrent
+		local     map[string]*localTorrent
+		queued    map[string]bool
+		placed    map[string]bool
+		truncated bool
+		want      []want
+		tracked   int
+	}{
+		{
+			name:    "placed torrent is tracked",
+			remote:  []*types.Torrent{remote("a1", "aaa", 0)},
+			local:   local("aaa", map[string]string{"rd": "a1"}),
+			placed:  map[string]bool{"rd/a1": true},
+			tracked: 1,
+		},
+		{
+			name:    "queued torrent is tracked",
+			remote:  []*types.Torrent{remote("a1", "aaa", 0)},
+			queued:  map[string]bool{"aaa": true},
+			tracked: 1,
+		},
+		{
+			name:   "unknown torrent is an orphan",
+			remote: []*types.Torrent{remote("a1", "aaa", 0)},
+			want:   []want{{storage.ReconcileOrphaned, "a1", ""}},
+		},
+		{
+			name:   "later copies of an orphan are duplicates",
+			remote: []*types.Torrent{remote("a2", "aaa", 2), remote("a1", "aaa", 0)},
+			want: []want{
+				{storage.ReconcileOrphaned, "a1", ""},
+				{storage.ReconcileDuplicate, "a2", ""},
+			},
+		},
+		{
+			name:    "second copy of a placed torrent is a duplicate",
+			remote:  []*types.Torrent{remote("a1", "aaa", 0), remote("a2", "aaa", 1)},
+			local:   local("aaa", map[string]string{"rd": "a1"}),
+			placed:  map[string]bool{"rd/a1": true},
+			want:    []want{{storage.ReconcileDuplicate, "a2", ""}},
+			tracked: 1,
+		},
+		{
+			name:  "gone placement is missing",
+			local: local("aaa", map[string]string{"rd": "a1"}),
+			want:  []want{{storage.ReconcileMissing, "a1", ""}},
+		},
+		{
+			name:   "placement on another debrid is not missing here",
+			local:  local("aaa", map[string]string{"tb": "t1"}),
+			remote: []*types.Torrent{},
+		},
+		{
+			name:   "copy under a new ID stands in for a gone placement",
+			remote: []*types.Torrent{remote("a2", "aaa", 0), remote("a3", "aaa", 1)},
+			local:  local("aaa", map[string]string{"rd": "a1"}),
+			want: []want{
+				{storage.ReconcileDuplicate, "a3", ""},
+				{storage.ReconcileMissing, "a1", "a2"},
+			},
+			tracked: 1,
+		},
+		{
+			name:      "truncated listing reports nothing missing",
+			remote:    []*types.Torrent{remote("b1", "bbb", 0)},
+			local:     local("aaa", map[string]string{"rd": "a1"}),
+			truncated: true,
+			want:      []want{{storage.ReconcileOrphaned, "b1", ""}},
+		},
+		{
+			name:      "truncated listing has no stand-ins",
+			remote:    []*types.Torrent{remote("a2", "aaa", 0)},
+			local:     local("aaa", map[string]string{"rd": "a1"}),
+			truncated: true,
+			want:      []want{{storage.ReconcileDuplicate, "a2", ""}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manager{}
+			run := &storage.ReconcileRun{}
+			m.reconcileProvider(run, "rd", tt.remote, tt.local, tt.queued, tt.placed, tt.truncated)
+
+			var got []want
+			for _, item := range run.Items {
+				if item.Provider != "rd" {
+					t.Errorf("item %s provider = %q, want rd", item.TorrentID, item.Provider)
+				}
+				got = append(got, want{item.Class, item.TorrentID, item.StandInID})
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("items = %v, want %v", got, tt.want)
+			}
+			if run.Stats.Tracked != tt.tracked {
+				t.Errorf("tracked = %d, want %d", run.Stats.Tracked, tt.tracked)
+			}
+			if run.Stats.Remote != len(tt.remote) {
+				t.Errorf("remote = %d, want %d", run.Stats.Remote, len(tt.remote))
+			}
+		})
+	}
+}
+
+func TestReconcileAdoptsStandIn(t *testing.T) {
+	m := newTestManager(t)
+	standIn := &types.Torrent{
+		Id:       "new",
+		InfoHash: "aaa",
+		Name:     "Movie",
+		Debrid:   "rd",
+		Status:   types.TorrentStatusDownloaded,
+		Files: map[string]types.File{
+			"movie.mkv": {Id: "1", Name: "movie.mkv", Size: 1 << 20, Link: "https://rd/new/1"},
+		},
+	}
+	rd := &fakeDebrid{name: "rd", listed: []*types.Torrent{standIn}}
+	m.clients.Store("rd", rd)
+	entry := &storage.Entry{
+		InfoHash:       "aaa",
+		Name:           "Movie",
+		Protocol:       config.ProtocolTorrent,
+		ActiveProvider: "rd",
+		Providers: map[string]*storage.ProviderEntry{
+			"rd": {Provider: "rd", ID: "old"},
+		},
+	}
+	if err := m.storage.AddOrUpdate(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	run, err := m.StartReconcile(storage.RepairTriggerManual, ReconcileOptions{
+		Providers: []string{"rd"},
+		Missing:   storage.ReconcileActionReAdd,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for m.reconcileRunning.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("reconciliation still running")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if run, err = m.storage.GetReconcileRun(run.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(run.Items) != 1 {
+		t.Fatalf("items = %d, want 1", len(run.Items))
+	}
+	item := run.Items[0]
+	if item.Action != storage.ReconcileActionAdopt || !item.Applied {
+		t.Errorf("item action = %s applied = %v (%s), want applied adopt", item.Action, item.Applied, item.Error)
+	}
+	if got := rd.submitted.Load(); got != 0 {
+		t.Errorf("submitted %d magnets, want none", got)
+	}
+	got, err := m.storage.Get("aaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := got.Providers["rd"].ID; id != "new" {
+		t.Errorf("placement ID = %q, want new", id)
+	}
+	if got.ActiveProvider != "rd" {
+		t.Errorf("active provider = %q, want rd", got.ActiveProvider)
+	}
+}
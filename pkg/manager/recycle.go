@@ -0,0 +1,217 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+const (
+	defaultRecycleRetention     = 7 * 24 * time.Hour
+	defaultRecyclePurgeSchedule = "1h"
+)
+
+// recycleRetention is how long the recycle bin keeps a deleted entry
+func (m *Manager) recycleRetention() time.Duration {
+	retention := config.Get().RecycleBin.Retention
+	if retention == "" {
+		return defaultRecycleRetention
+	}
+	d, err := utils.ParseDuration(retention)
+	if err != nil || d <= 0 {
+		m.logger.Warn().Err(err).Str("retention", retention).Msg("Invalid recycle bin retention, using default")
+		return defaultRecycleRetention
+	}
+	return d
+}
+
+// recycleEntry soft-deletes an entry: it leaves the library and its
+// placements are removed, but the entry, magnet, NZB and metainfo stay in the
+// recycle bin so it can be restored.
+func (m *Manager) recycleEntry(entry *storage.Entry) error {
+	if entry.IsNZB() {
+		// An NNTP import's own copy of the NZB goes with its placement
+		if content, err := m.nzbSource(entry); err == nil {
+			_ = m.storage.SaveNZBSource(entry.InfoHash, content)
+		}
+	}
+	if err := m.storage.RecycleEntry(entry, time.Now()); err != nil {
+		return err
+	}
+	go m.RemoveTorrentPlacements(entry)
+	m.RecordEvent(entry.InfoHash, storage.EntryEventRecycled, entry.ActiveProvider, "moved to the recycle bin")
+	m.logger.Info().Str("name", entry.Name).Msg("Moved entry to the recycle bin")
+	m.RefreshEntries(true)
+	return nil
+}
+
+// RecycledEntries lists the recycle bin, most recently deleted first
+func (m *Manager) RecycledEntries() ([]*storage.RecycledEntry, error) {
+	return m.storage.ListRecycledEntries()
+}
+
+// RecycleExpiry is when a recycled entry will be purged
+func (m *Manager) RecycleExpiry(rec *storage.RecycledEntry) time.Time {
+	return rec.DeletedAt.Add(m.recycleRetention())
+}
+
+// restoreTarget picks where a restored entry is placed: provider when given,
+// else where it was served from, else the first provider that can take it.
+func (m *Manager) restoreTarget(entry *storage.Entry, provider string) (string, error) {
+	if provider != "" {
+		if !m.canHost(entry, provider) {
+			return "", fmt.Errorf("%s can't take %s", provider, entry.Name)
+		}
+		return provider, nil
+	}
+	if entry.ActiveProvider != "" && m.canHost(entry, entry.ActiveProvider) {
+		return entry.ActiveProvider, nil
+	}
+	candidates := m.nzbProviderOrder()
+	if !entry.IsNZB() {
+		candidates = candidates[:0]
+		for _, dc := range config.Get().Debrids {
+			candidates = append(candidates, dc.Name)
+		}
+	}
+	for _, name := range candidates {
+		if m.canHost(entry, name) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("no provider can take %s", entry.Name)
+}
+
+// RestoreEntry brings an entry back from the recycle bin. The magnet is
+// re-added to a debrid, or the stored NZB re-linked, and the entry's
+// symlinks are rebuilt once it is placed. provider overrides where it is
+// placed.
+func (m *Manager) RestoreEntry(infohash, provider string) (*storage.Entry, error) {
+	rec, err := m.storage.GetRecycledEntry(infohash)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in the recycle bin", infohash)
+	}
+	if exists, _ := m.storage.Exists(rec.InfoHash); exists {
+		return nil, fmt.Errorf("%s is already in the library", rec.Name)
+	}
+	entry, err := rec.Entry()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recycled entry: %w", err)
+	}
+	target, err := m.restoreTarget(entry, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every placement was removed on delete
+	clear(entry.Providers)
+	placed, stopWatching := m.watchNZBPlacement(entry.InfoHash, target)
+	_, err = m.fixer.MoveTorrent(entry, target, true)
+	// An NZB restored over NNTP, or not yet ready on a debrid, is placed in
+	// the background; the entry is kept and switches over once it's ready.
+	pending := errors.Is(err, ErrPlacementPending)
+	if err != nil && !pending {
+		stopWatching()
+		// MoveTorrent saves the entry whatever the outcome; a failed
+		// restore stays in the bin only.
+		_ = m.storage.Delete(entry.InfoHash)
+		return nil, fmt.Errorf("failed to restore %s: %w", entry.Name, err)
+	}
+	_ = m.storage.DeleteRecycledEntry(entry.InfoHash)
+	m.RecordEvent(entry.InfoHash, storage.EntryEventRestored, target, "restored from the recycle bin")
+	m.logger.Info().Str("name", entry.Name).Str("provider", target).Bool("pending", pending).Msg("Restored entry from the recycle bin")
+	m.RefreshEntries(true)
+
+	rebuild := entry.Action == config.DownloadActionSymlink || entry.Action == ""
+	go func() {
+		defer stopWatching()
+		restored := entry
+		if pending {
+			if err := <-placed; err != nil {
+				m.logger.Warn().Err(err).Str("name", entry.Name).Str("provider", target).Msg("Placement of restored entry failed, symlinks not rebuilt")
+				return
+			}
+			placedEntry, err := m.GetEntry(entry.InfoHash)
+			if err != nil {
+				return
+			}
+			restored = placedEntry
+		}
+		if !rebuild {
+			return
+		}
+		if err := m.downloader.download(restored); err != nil {
+			m.logger.Error().Err(err).Str("name", restored.Name).Msg("Failed to rebuild symlinks of restored entry")
+			restored.MarkAsError(err)
+			_ = m.queue.Update(restored)
+		}
+	}()
+	return entry, nil
+}
+
+// PurgeRecycledEntry deletes an entry from the recycle bin for good, with the
+// magnet, NZB and metainfo kept for it.
+func (m *Manager) PurgeRecycledEntry(infohash string) error {
+	rec, err := m.storage.GetRecycledEntry(infohash)
+	if err != nil {
+		return fmt.Errorf("%s is not in the recycle bin", infohash)
+	}
+	// The hash may be back in the library through a fresh add
+	if exists, _ := m.storage.Exists(rec.InfoHash); !exists {
+		_ = m.storage.DeleteTorrentMetainfo(rec.InfoHash)
+		_ = m.storage.DeleteNZBSource(rec.InfoHash)
+		_ = m.storage.DeletePlayRecord(rec.InfoHash)
+		m.lastPlays.Delete(strings.ToLower(rec.InfoHash))
+	}
+	return m.storage.DeleteRecycledEntry(rec.InfoHash)
+}
+
+// purgeRecycleBin deletes the recycled entries past their retention
+func (m *Manager) purgeRecycleBin() {
+	recs, err := m.storage.ListRecycledEntries()
+	if err != nil {
+		m.logger.Warn().Err(err).Msg("Failed to list the recycle bin")
+		return
+	}
+	cutoff := time.Now().Add(-m.recycleRetention())
+	purged := 0
+	for _, rec := range recs {
+		if rec.DeletedAt.After(cutoff) {
+			continue
+		}
+		if err := m.PurgeRecycledEntry(rec.InfoHash); err != nil {
+			m.logger.Warn().Err(err).Str("name", rec.Name).Msg("Failed to purge recycled entry")
+			continue
+		}
+		purged++
+	}
+	if purged > 0 {
+		m.logger.Info().Int("count", purged).Msg("Purged expired entries from the recycle bin")
+	}
+}
+
+// scheduleRecycleBin registers the recycle bin purge. It runs even with the
+// bin disabled, so entries recycled before are still purged.
+func (m *Manager) scheduleRecycleBin(ctx context.Context) {
+	schedule := m.config.RecycleBin.Schedule
+	if strings.TrimSpace(schedule) == "" {
+		schedule = defaultRecyclePurgeSchedule
+	}
+	jd, err := utils.ConvertToJobDef(schedule)
+	if err != nil {
+		m.logger.Error().Err(err).Str("schedule", schedule).Msg("Failed to convert recycle bin schedule to job definition")
+		return
+	}
+	if _, err := m.scheduler.NewJob(jd, gocron.NewTask(m.purgeRecycleBin), gocron.WithContext(ctx), gocron.WithName("recycle-bin-purge")); err != nil {
+		m.logger.Error().Err(err).Msg("Failed to create recycle bin purge job")
+		return
+	}
+	m.logger.Debug().Msgf("Recycle bin purge scheduled for %s", schedule)
+}
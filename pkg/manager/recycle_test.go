@@ -0,0 +1,76 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+	"github.com/sirrobot01/decypharr/pkg/usenet"
+)
+
+func TestRestoreEntryToUsenet(t *testing.T) {
+	m := newTestManager(t)
+	m.usenet = &usenet.Usenet{}
+	const infohash = "0123456789abcdef0123456789abcdef01234567"
+	entry := &storage.Entry{
+		InfoHash:       infohash,
+		Name:           "Show.S01E01",
+		Protocol:       config.ProtocolNZB,
+		Action:         config.DownloadActionSymlink,
+		ActiveProvider: "usenet",
+	}
+	if err := m.storage.AddOrUpdate(entry); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.storage.RecycleEntry(entry, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	// The NNTP import fails on it, after RestoreEntry has returned
+	if err := m.storage.SaveNZBSource(infohash, []byte("not an nzb")); err != nil {
+		t.Fatal(err)
+	}
+
+	placed, stop := m.watchNZBPlacement(infohash, "usenet")
+	defer stop()
+	restored, err := m.RestoreEntry(infohash, "usenet")
+	if err != nil {
+		t.Fatalf("RestoreEntry() error = %v, want the pending placement to count as restored", err)
+	}
+	if restored.InfoHash != infohash {
+		t.Errorf("RestoreEntry() = %s, want %s", restored.InfoHash, infohash)
+	}
+	if exists, _ := m.storage.Exists(infohash); !exists {
+		t.Error("restored entry is not in the library")
+	}
+	if _, err := m.storage.GetRecycledEntry(infohash); err == nil {
+		t.Error("restored entry is still in the recycle bin")
+	}
+
+	select {
+	case err := <-placed:
+		if err == nil {
+			t.Fatal("placement of an invalid NZB succeeded")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("placement never settled")
+	}
+	// A failed placement keeps the entry; symlinks are only rebuilt once
+	// one succeeds, which would need a downloader this manager doesn't have
+	if exists, _ := m.storage.Exists(infohash); !exists {
+		t.Error("entry was dropped after its placement failed")
+	}
+	events, err := m.storage.GetEntryEvents(infohash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := make([]storage.EntryEventType, 0, len(events))
+	for _, ev := range events {
+		types = append(types, ev.Type)
+	}
+	want := []storage.EntryEventType{storage.EntryEventSubmitted, storage.EntryEventRestored, storage.EntryEventSwitchFailed}
+	if !slices.Equal(types, want) {
+		t.Errorf("events = %v, want %v", types, want)
+	}
+}
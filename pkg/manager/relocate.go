@@ -0,0 +1,124 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// RenameEntry renames an entry as qBit torrents/rename does. The virtual
+// folder follows the new name, and the local symlinks or .strm files of a
+// completed download are rebuilt to point at it.
+func (m *Manager) RenameEntry(infohash, name string) error {
+	name = strings.TrimSpace(name)
+	if !validEntryName(name) {
+		return fmt.Errorf("invalid name %q", name)
+	}
+	return m.relocateEntry(infohash, nil, func(entry *storage.Entry) error {
+		entry.Rename(name)
+		return nil
+	})
+}
+
+// RenameEntryFile renames one file of an entry as qBit torrents/renameFile
+// does. Usenet entries are not supported; their files are addressed by name
+// in the NZB.
+func (m *Manager) RenameEntryFile(infohash, oldName, newName string) error {
+	if !validEntryName(newName) {
+		return fmt.Errorf("invalid file name %q", newName)
+	}
+	return m.relocateEntry(infohash, map[string]string{oldName: newName}, func(entry *storage.Entry) error {
+		if entry.OnUsenet() {
+			return fmt.Errorf("files of usenet entries cannot be renamed")
+		}
+		return entry.RenameFile(oldName, newName)
+	})
+}
+
+// SetEntryLocation changes an entry's save path as qBit torrents/setLocation
+// does, moving or relinking a completed download into it.
+func (m *Manager) SetEntryLocation(infohash, location string) error {
+	location = filepath.Clean(location)
+	if !filepath.IsAbs(location) {
+		return fmt.Errorf("location %q is not an absolute path", location)
+	}
+	if err := os.MkdirAll(location, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create %s: %w", location, err)
+	}
+	return m.relocateEntry(infohash, nil, func(entry *storage.Entry) error {
+		entry.SavePath = location
+		return nil
+	})
+}
+
+// relocateEntry applies update to the stored and the queued copy of an entry.
+// The stored copy is reindexed so the mount shows the new names; the queued
+// copy's local folder is rebuilt when its download already completed. Both
+// copies are updated and relinked before either record is written, and the
+// reindex is undone when the queued copy can't be saved.
+func (m *Manager) relocateEntry(infohash string, renamed map[string]string, update func(*storage.Entry) error) error {
+	queued, queueErr := m.queue.GetTorrent(infohash)
+	if queueErr == nil && queued.IsDownloading && !queued.IsComplete {
+		return fmt.Errorf("entry %s is still being processed", queued.Name)
+	}
+
+	var stored, old *storage.Entry
+	if entry, err := m.storage.Get(infohash); err == nil {
+		old, _ = m.storage.Get(infohash)
+		if err := update(entry); err != nil {
+			return err
+		}
+		entry.ContentPath = entry.DownloadPath()
+		stored = entry
+	}
+
+	if queueErr == nil {
+		oldDir := queued.DownloadPath()
+		if err := update(queued); err != nil {
+			return err
+		}
+		if queued.IsComplete {
+			if err := m.downloader.relink(queued, oldDir, renamed); err != nil {
+				return fmt.Errorf("failed to relink %s: %w", queued.Name, err)
+			}
+		}
+		queued.ContentPath = queued.DownloadPath()
+	} else if stored == nil {
+		return fmt.Errorf("entry %s not found", infohash)
+	}
+
+	if stored != nil {
+		if err := m.storage.Reindex(old, stored); err != nil {
+			return fmt.Errorf("failed to update entry: %w", err)
+		}
+	}
+	if queueErr == nil {
+		if err := m.queue.Update(queued); err != nil {
+			if stored != nil {
+				_ = m.storage.Reindex(stored, old)
+			}
+			return fmt.Errorf("failed to update queued entry: %w", err)
+		}
+
+		// A job still waiting for a slot writes its own copy back once it
+		// runs; keep that copy in step too.
+		if m.jobQueue != nil {
+			m.jobQueue.UpdatePending(infohash, func(job *Job) {
+				if job.Entry != nil && update(job.Entry) == nil {
+					job.Entry.ContentPath = job.Entry.DownloadPath()
+				}
+			})
+		}
+	}
+	if stored != nil {
+		m.RefreshEntries(true)
+	}
+	return nil
+}
+
+func validEntryName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
@@ -40,6 +40,7 @@ type RepairRunOptions struct {
 	IgnoreLastChecked bool
 	AutoRepair        *bool
 	UnrestrictLink    bool
+	PieceVerify       bool
 	ProtocolScope     string
 }
 
@@ -409,6 +410,9 @@ func (r *Repair) runSweep(trigger storage.RepairRunTrigger, opts RepairRunOption
 	if opts.UnrestrictLink {
 		sourceParts = append(sourceParts, "unrestrict-link")
 	}
+	if opts.PieceVerify {
+		sourceParts = append(sourceParts, "piece-verify")
+	}
 	if scope := normalizeRepairProtocolScope(opts.ProtocolScope); scope != "" {
 		sourceParts = append(sourceParts, "protocol-"+scope)
 	}
@@ -472,6 +476,7 @@ func (r *Repair) finalizeRun(run *storage.RepairRun, status storage.RepairRunSta
 	if err := r.manager.storage.SaveRepairRun(run); err != nil {
 		r.logger.Warn().Err(err).Str("run_id", run.ID).Msg("Failed to persist final run state")
 	}
+	publishRepairRun(run)
 	_ = r.manager.storage.PruneRepairRuns(repairHistoryRetained)
 
 	if r.manager.Notifications != nil {
@@ -516,12 +521,14 @@ func (r *Repair) saveRun(run *storage.RepairRun) {
 	if err := r.manager.storage.SaveRepairRun(run); err != nil {
 		r.logger.Trace().Err(err).Str("run_id", run.ID).Msg("Failed to persist run progress")
 	}
+	publishRepairRun(run)
 }
 
 func (r *Repair) saveHealth(state *storage.EntryHealth) {
 	if err := r.manager.storage.SaveEntryHealth(state); err != nil {
 		r.logger.Trace().Err(err).Str("entry", state.EntryName).Msg("Failed to persist entry health")
 	}
+	publishEntryHealth(state)
 }
 
 // ReinsertEntry attempts to fix a torrent by re-inserting it across debrids.
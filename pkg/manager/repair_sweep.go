@@ -7,6 +7,7 @@ import (
 	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/sirrobot01/decypharr/internal/config"
 	"github.com/sirrobot01/decypharr/internal/customerror"
+	"github.com/sirrobot01/decypharr/internal/mediaprobe"
 	"github.com/sirrobot01/decypharr/pkg/arr"
 	debrid "github.com/sirrobot01/decypharr/pkg/debrid/common"
 	debridTypes "github.com/sirrobot01/decypharr/pkg/debrid/types"
@@ -273,9 +275,50 @@ func (r *Repair) probeEntry(ctx context.Context, runID string, c *candidate, hea
 	}
 
 	r.saveHealth(h)
+	if final != previous || final == storage.HealthBroken {
+		r.recordProbeEvents(results)
+	}
 	return h
 }
 
+// recordProbeEvents adds the probe outcome to the timeline of each entry the
+// probed files belong to
+func (r *Repair) recordProbeEvents(results []fileResult) {
+	type outcome struct {
+		probed  int
+		broken  int
+		reasons []string
+	}
+	byHash := make(map[string]*outcome)
+	var order []string
+	for _, res := range results {
+		if res.infoHash == "" {
+			continue
+		}
+		o, ok := byHash[res.infoHash]
+		if !ok {
+			o = &outcome{}
+			byHash[res.infoHash] = o
+			order = append(order, res.infoHash)
+		}
+		o.probed++
+		if res.broken {
+			o.broken++
+			if res.reason != "" && !slices.Contains(o.reasons, res.reason) {
+				o.reasons = append(o.reasons, res.reason)
+			}
+		}
+	}
+	for _, hash := range order {
+		o := byHash[hash]
+		message := fmt.Sprintf("%d files probed, all healthy", o.probed)
+		if o.broken > 0 {
+			message = fmt.Sprintf("%d of %d files broken: %s", o.broken, o.probed, strings.Join(o.reasons, ", "))
+		}
+		r.manager.RecordEvent(hash, storage.EntryEventRepair, "", message)
+	}
+}
+
 // probeFiles fans per-file probes inside a single entry, capped at
 // repairFilesPerEntry concurrent workers.
 func (r *Repair) probeFiles(ctx context.Context, item *storage.EntryItem, names []string, opts RepairRunOptions) []fileResult {
@@ -297,8 +340,9 @@ func (r *Repair) probeFiles(ctx context.Context, item *storage.EntryItem, names
 }
 
 // probeFile checks one file. NZB probes use usenet.CheckFile. Torrent probes
-// use the provider CheckFile endpoint unless this run requests unrestrict-link
-// probing.
+// verify sampled piece hashes when piece verification is on and
+// metainfo is stored, and otherwise use the provider CheckFile endpoint unless
+// this run requests unrestrict-link probing.
 func (r *Repair) probeFile(ctx context.Context, item *storage.EntryItem, name string, opts RepairRunOptions) fileResult {
 	file := item.Files[name]
 	res := fileResult{name: name}
@@ -320,10 +364,46 @@ func (r *Repair) probeFile(ctx context.Context, item *storage.EntryItem, name st
 		return res
 	}
 
-	if entry.IsNZB() {
-		return r.probeNZBFile(ctx, entry, name, res)
+	if entry.OnUsenet() {
+		res = r.probeNZBFile(ctx, entry, name, res)
+	} else {
+		res = r.probeTorrentFile(ctx, entry, file, name, res, opts)
+	}
+	if res.healthy {
+		res = r.probeFileMedia(ctx, entry, name, res)
+	}
+	return res
+}
+
+// probeFileMedia runs the container probe on a file that passed the link or
+// segment check. Truncated and unplayable containers are reported broken;
+// probe read errors leave the earlier verdict untouched.
+func (r *Repair) probeFileMedia(ctx context.Context, entry *storage.Entry, name string, res fileResult) fileResult {
+	if !config.Get().ProbeMedia || !mediaprobe.Supported(name) {
+		return res
 	}
-	return r.probeTorrentFile(ctx, entry, file, name, res, opts)
+	// A file keeps its probe result until its content changes, so only
+	// files without one are read again
+	var media *storage.MediaInfo
+	if file, ok := entry.Files[name]; ok && file.Media != nil {
+		media = file.Media
+	} else {
+		var err error
+		media, err = r.manager.ProbeFileMedia(ctx, entry, name)
+		if err != nil {
+			r.logger.Debug().Err(err).Str("infohash", entry.InfoHash).Str("file", name).Msg("Media probe failed")
+			return res
+		}
+		if err := r.manager.saveFileMedia(entry.InfoHash, map[string]*storage.MediaInfo{name: media}); err != nil {
+			r.logger.Debug().Err(err).Str("infohash", entry.InfoHash).Msg("Failed to save media probe result")
+		}
+	}
+	if !media.Playable() {
+		res.healthy = false
+		res.broken = true
+		res.reason = "media_" + media.Status
+	}
+	return res
 }
 
 func (r *Repair) probeNZBFile(ctx context.Context, entry *storage.Entry, name string, res fileResult) fileResult {
@@ -351,6 +431,11 @@ func (r *Repair) probeTorrentFile(ctx context.Context, entry *storage.Entry, fil
 		res.reason = "provider_client_not_found"
 		return res
 	}
+	if r.usePieceVerify(opts) {
+		if verified, ok := r.probeTorrentFileByPieces(ctx, entry, file, name, res); ok {
+			return verified
+		}
+	}
 	if opts.UnrestrictLink {
 		return r.probeTorrentFileByUnrestrict(entry, file, name, res, client)
 	}
@@ -428,7 +513,7 @@ func (r *Repair) probeTorrentFileByUnrestrict(entry *storage.Entry, file *storag
 func (r *Repair) autoHealResults(ctx context.Context, results []fileResult, heal *healCache) {
 	byHash := make(map[string][]int)
 	for i, res := range results {
-		if !res.broken || res.protocol != config.ProtocolTorrent || res.infoHash == "" {
+		if !res.broken || (res.protocol != config.ProtocolTorrent && res.protocol != config.ProtocolNZB) || res.infoHash == "" {
 			continue
 		}
 		byHash[res.infoHash] = append(byHash[res.infoHash], i)
@@ -704,7 +789,7 @@ func (r *Repair) finalizeEntryRepair(name string, h *storage.EntryHealth, succee
 	}
 
 	for hash := range hashes {
-		if err := r.manager.DeleteEntry(hash, true); err != nil {
+		if err := r.manager.DeleteEntry(hash, true, false); err != nil {
 			r.logger.Warn().Err(err).Str("entry", name).Str("infohash", hash).Msg("Repair: failed to delete fully-broken entry after re-search")
 			continue
 		}
@@ -751,7 +836,7 @@ func (r *Repair) filterCandidateByProtocol(c *candidate, scope string) *candidat
 	}
 	files := make(map[string]*storage.File, len(c.item.Files))
 	for name, file := range c.item.Files {
-		if file == nil || file.Deleted || file.InfoHash == "" {
+		if file == nil || !file.Active() || file.InfoHash == "" {
 			continue
 		}
 		entry, err := r.manager.GetEntry(file.InfoHash)
@@ -904,7 +989,7 @@ func (r *Repair) eligibleArrs(filter []string) []*arr.Arr {
 	}
 	out := make([]*arr.Arr, 0, len(all))
 	for _, a := range all {
-		if a == nil || a.Host == "" || a.Token == "" || a.SkipRepair {
+		if a == nil || a.Host == "" || a.Token == "" || skipsRepair(a) {
 			continue
 		}
 		if len(wanted) > 0 {
@@ -1379,6 +1464,12 @@ func (r *Repair) executeRecheckMedia(ctx context.Context, run *storage.RepairRun
 		Msg("RecheckMedia: completed")
 }
 
+// skipsRepair reports whether an Arr opted out of repairs, itself or through
+// its category profile.
+func skipsRepair(a *arr.Arr) bool {
+	return a.SkipRepair || config.Get().PolicyFor(a.Name).SkipRepair
+}
+
 func (r *Repair) resolveArrsForMedia(arrName string) ([]*arr.Arr, error) {
 	if arrName != "" {
 		a := r.manager.arr.Get(arrName)
@@ -1388,7 +1479,7 @@ func (r *Repair) resolveArrsForMedia(arrName string) ([]*arr.Arr, error) {
 		if a.Host == "" || a.Token == "" {
 			return nil, fmt.Errorf("arr %q is not configured", arrName)
 		}
-		if a.SkipRepair {
+		if skipsRepair(a) {
 			return nil, fmt.Errorf("arr %q has skip_repair set", arrName)
 		}
 		return []*arr.Arr{a}, nil
@@ -1440,7 +1531,7 @@ func orderedFilenames(item *storage.EntryItem) []string {
 	}
 	out := make([]string, 0, len(item.Files))
 	for name, f := range item.Files {
-		if f == nil || f.Deleted {
+		if f == nil || !f.Active() {
 			continue
 		}
 		out = append(out, name)
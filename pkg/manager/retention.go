@@ -0,0 +1,339 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/arr"
+	"github.com/sirrobot01/decypharr/pkg/notifications"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+const (
+	retentionHistoryRetained = 50
+
+	// retentionImportGrace keeps new entries from counting as unreferenced
+	// before their Arr has had a chance to import them
+	retentionImportGrace = 24 * time.Hour
+)
+
+var ErrRetentionRunning = errors.New("retention run already running")
+
+// RetentionOptions controls a retention pass.
+type RetentionOptions struct {
+	Policies []string // Names of the policies to run; empty runs them all
+	DryRun   bool
+}
+
+// retentionPolicy is a config.RetentionPolicy with its durations parsed
+type retentionPolicy struct {
+	config.RetentionPolicy
+	maxAge      time.Duration
+	unplayedFor time.Duration
+}
+
+// retentionEntry is the slice of an entry a retention pass needs; keeping
+// only this avoids holding every entry in memory during the pass.
+type retentionEntry struct {
+	infohash string
+	name     string
+	folder   string
+	category string
+	size     int64
+	addedAt  time.Time
+	symlink  bool
+}
+
+func (m *Manager) retentionPolicies(names []string) ([]*retentionPolicy, error) {
+	configured := config.Get().Retention.Policies
+	if len(configured) == 0 {
+		return nil, fmt.Errorf("no retention policies configured")
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(configured, func(p config.RetentionPolicy) bool { return strings.EqualFold(p.Name, name) }) {
+			return nil, fmt.Errorf("retention policy %s not found", name)
+		}
+	}
+	policies := make([]*retentionPolicy, 0, len(configured))
+	for _, p := range configured {
+		if len(names) > 0 && !containsFold(names, p.Name) {
+			continue
+		}
+		maxAge, unplayedFor, err := p.Durations()
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, &retentionPolicy{RetentionPolicy: p, maxAge: maxAge, unplayedFor: unplayedFor})
+	}
+	return policies, nil
+}
+
+// StartRetention runs the retention policies in the background and returns
+// the run record, which is updated in storage as the pass progresses.
+func (m *Manager) StartRetention(trigger storage.RepairRunTrigger, opts RetentionOptions) (*storage.RetentionRun, error) {
+	policies, err := m.retentionPolicies(opts.Policies)
+	if err != nil {
+		return nil, err
+	}
+	if !m.retentionRunning.CompareAndSwap(false, true) {
+		return nil, ErrRetentionRunning
+	}
+	run := &storage.RetentionRun{
+		ID:        uuid.New().String(),
+		Trigger:   trigger,
+		Status:    storage.RepairRunRunning,
+		DryRun:    opts.DryRun,
+		StartedAt: time.Now(),
+		Items:     make([]*storage.RetentionItem, 0),
+	}
+	for _, p := range policies {
+		run.Policies = append(run.Policies, p.Name)
+	}
+	if err := m.storage.SaveRetentionRun(run); err != nil {
+		m.retentionRunning.Store(false)
+		return nil, err
+	}
+	go func() {
+		defer m.retentionRunning.Store(false)
+		m.retention(m.ctx, run, policies, opts.DryRun)
+	}()
+	return run, nil
+}
+
+func (m *Manager) retention(ctx context.Context, run *storage.RetentionRun, policies []*retentionPolicy, dryRun bool) {
+	m.logger.Info().Str("run_id", run.ID).Bool("dry_run", dryRun).Strs("policies", run.Policies).Msg("Starting retention run")
+
+	selected := make([][]*retentionEntry, len(policies))
+	scanned := 0
+	err := m.storage.ForEach(func(e *storage.Entry) error {
+		if e.State != storage.EntryStatePausedUP {
+			return nil
+		}
+		var re *retentionEntry
+		for i, p := range policies {
+			if !p.Selects(e.Category, e.Tags) {
+				continue
+			}
+			if re == nil {
+				re = &retentionEntry{
+					infohash: e.InfoHash,
+					name:     e.Name,
+					folder:   e.GetFolder(),
+					category: e.Category,
+					size:     e.Size,
+					addedAt:  e.CreatedAt,
+					symlink:  e.Action == config.DownloadActionSymlink,
+				}
+				if re.addedAt.IsZero() {
+					re.addedAt = e.AddedOn
+				}
+				scanned++
+			}
+			selected[i] = append(selected[i], re)
+		}
+		return nil
+	})
+	if err != nil {
+		m.finishRetention(run, storage.RepairRunFailed, fmt.Sprintf("failed to read entries: %v", err))
+		return
+	}
+	run.Stats.Scanned = scanned
+
+	plays, err := m.storage.GetPlayRecords()
+	if err != nil {
+		m.finishRetention(run, storage.RepairRunFailed, fmt.Sprintf("failed to read stream history: %v", err))
+		return
+	}
+	streaming := make(map[string]bool)
+	m.activeStreams.Range(func(_ string, s *ActiveStream) bool {
+		streaming[s.EntryName] = true
+		return true
+	})
+	references := make(map[string]map[string]bool) // Arr -> entry folders its media links to
+
+	now := time.Now()
+	matched := make(map[string]bool)
+	for i, p := range policies {
+		entries := selected[i]
+		// Newest first, for keep_latest
+		slices.SortStableFunc(entries, func(a, b *retentionEntry) int { return b.addedAt.Compare(a.addedAt) })
+		for rank, re := range entries {
+			if ctx.Err() != nil {
+				m.finishRetention(run, storage.RepairRunCancelled, "")
+				return
+			}
+			if matched[re.infohash] || streaming[re.name] {
+				continue
+			}
+			var reasons []storage.RetentionReason
+			if p.maxAge > 0 && now.Sub(re.addedAt) > p.maxAge {
+				reasons = append(reasons, storage.RetentionMaxAge)
+			}
+			var lastPlayed *time.Time
+			if play, ok := plays[strings.ToLower(re.infohash)]; ok {
+				lastPlayed = &play.LastPlayed
+			}
+			if p.unplayedFor > 0 {
+				since := re.addedAt
+				if lastPlayed != nil && lastPlayed.After(since) {
+					since = *lastPlayed
+				}
+				if now.Sub(since) > p.unplayedFor {
+					reasons = append(reasons, storage.RetentionUnplayed)
+				}
+			}
+			if p.KeepLatest > 0 && rank >= p.KeepLatest {
+				reasons = append(reasons, storage.RetentionKeepLatest)
+			}
+			if p.Unreferenced && re.symlink && now.Sub(re.addedAt) > retentionImportGrace {
+				if m.unreferenced(ctx, run, references, re) {
+					reasons = append(reasons, storage.RetentionUnreferenced)
+				}
+			}
+			if len(reasons) == 0 {
+				continue
+			}
+			matched[re.infohash] = true
+			run.Stats.Matched++
+			run.Stats.Freed += re.size
+			run.Items = append(run.Items, &storage.RetentionItem{
+				Policy:     p.Name,
+				InfoHash:   re.infohash,
+				Name:       re.name,
+				Category:   re.category,
+				Size:       re.size,
+				AddedOn:    re.addedAt,
+				LastPlayed: lastPlayed,
+				Reasons:    reasons,
+			})
+		}
+	}
+
+	if !dryRun {
+		for _, item := range run.Items {
+			if ctx.Err() != nil {
+				m.finishRetention(run, storage.RepairRunCancelled, "")
+				return
+			}
+			err := m.DeleteEntry(item.InfoHash, true, false)
+			if err != nil {
+				item.Error = err.Error()
+				run.Stats.Failed++
+				continue
+			}
+			item.Deleted = true
+			run.Stats.Deleted++
+		}
+	}
+	m.finishRetention(run, storage.RepairRunCompleted, "")
+}
+
+// unreferenced reports whether the Arr of the entry's category no longer
+// links to any of its files. An Arr whose media can't be listed, or that
+// links to nothing at all, never counts an entry as unreferenced.
+func (m *Manager) unreferenced(ctx context.Context, run *storage.RetentionRun, references map[string]map[string]bool, re *retentionEntry) bool {
+	a := m.arr.Get(re.category)
+	if a == nil || a.Host == "" || a.Token == "" {
+		return false
+	}
+	folders, ok := references[a.Name]
+	if !ok {
+		folders = m.arrReferences(ctx, run, a)
+		references[a.Name] = folders
+	}
+	return len(folders) > 0 && !folders[re.folder]
+}
+
+// arrReferences lists the entry folders an Arr's media symlinks point into
+func (m *Manager) arrReferences(ctx context.Context, run *storage.RetentionRun, a *arr.Arr) map[string]bool {
+	media, err := a.GetMedia(ctx, "")
+	if err != nil {
+		if run.Errors == nil {
+			run.Errors = make(map[string]string)
+		}
+		run.Errors[a.Name] = err.Error()
+		m.logger.Warn().Err(err).Str("arr", a.Name).Msg("Retention could not list Arr media; skipping unreferenced check")
+		return nil
+	}
+	folders := make(map[string]bool)
+	for _, content := range media {
+		for entryPath := range collectArrFiles(content) {
+			folders[filepath.Clean(filepath.Base(entryPath))] = true
+		}
+	}
+	return folders
+}
+
+func (m *Manager) finishRetention(run *storage.RetentionRun, status storage.RepairRunStatus, errStr string) {
+	run.Status = status
+	run.Error = errStr
+	run.CompletedAt = time.Now()
+	if err := m.storage.SaveRetentionRun(run); err != nil {
+		m.logger.Warn().Err(err).Str("run_id", run.ID).Msg("Failed to save retention run")
+	}
+	_ = m.storage.PruneRetentionRuns(retentionHistoryRetained)
+	m.logger.Info().
+		Str("run_id", run.ID).
+		Str("status", string(status)).
+		Int("scanned", run.Stats.Scanned).
+		Int("matched", run.Stats.Matched).
+		Int("deleted", run.Stats.Deleted).
+		Int("failed", run.Stats.Failed).
+		Msg("Retention run finished")
+
+	if m.Notifications == nil || (status == storage.RepairRunCompleted && run.Stats.Matched == 0) {
+		return
+	}
+	event := notifications.Event{Type: config.EventLibraryPruned, Status: "success"}
+	switch {
+	case status == storage.RepairRunFailed:
+		event.Status = "error"
+		event.Message = "Retention run failed: " + errStr
+	case status == storage.RepairRunCancelled:
+		event.Status = "warning"
+		event.Message = fmt.Sprintf("Retention run cancelled after deleting %d entries", run.Stats.Deleted)
+	case run.DryRun:
+		event.Status = "pending"
+		event.Message = fmt.Sprintf("Dry run: %d entries (%s) would be deleted", run.Stats.Matched, utils.FormatSize(run.Stats.Freed))
+	default:
+		event.Message = fmt.Sprintf("Deleted %d entries (%s)", run.Stats.Deleted, utils.FormatSize(run.Stats.Freed))
+		if run.Stats.Failed > 0 {
+			event.Status = "warning"
+			event.Message += fmt.Sprintf(", %d failed", run.Stats.Failed)
+		}
+	}
+	m.Notifications.Notify(event)
+}
+
+// scheduleRetention registers the periodic retention run from config. The
+// policies and dry_run are read when the run starts.
+func (m *Manager) scheduleRetention(ctx context.Context) {
+	schedule := m.config.Retention.Schedule
+	if strings.TrimSpace(schedule) == "" {
+		return
+	}
+	jd, err := utils.ConvertToJobDef(schedule)
+	if err != nil {
+		m.logger.Error().Err(err).Str("schedule", schedule).Msg("Failed to convert retention schedule to job definition")
+		return
+	}
+	if _, err := m.scheduler.NewJob(jd, gocron.NewTask(func() {
+		opts := RetentionOptions{DryRun: config.Get().Retention.DryRun}
+		if _, err := m.StartRetention(storage.RepairTriggerScheduled, opts); err != nil {
+			m.logger.Warn().Err(err).Msg("Scheduled retention run skipped")
+		}
+	}), gocron.WithContext(ctx), gocron.WithName("retention")); err != nil {
+		m.logger.Error().Err(err).Msg("Failed to create retention job")
+		return
+	}
+	m.logger.Debug().Msgf("Retention run scheduled for %s", schedule)
+}
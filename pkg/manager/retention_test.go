@@ -0,0 +1,203 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/pkg/arr"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// retentionTestEntry is a completed entry added age ago
+type retentionTestEntry struct {
+	name     string
+	category string
+	tags     []string
+	age      time.Duration
+	played   time.Duration // Last streamed this long ago; 0 never
+	action   config.DownloadAction
+}
+
+func TestRetention(t *testing.T) {
+	const day = 24 * time.Hour
+	tests := []struct {
+		name       string
+		policies   []config.RetentionPolicy
+		entries    []retentionTestEntry
+		referenced []string                             // Entries the radarr Arr links to
+		want       map[string][]storage.RetentionReason // Matched entries by name
+		wantPolicy map[string]string                    // Policy an entry was matched by, when it matters
+	}{
+		{
+			name:     "keep_latest keeps the most recently added",
+			policies: []config.RetentionPolicy{{Name: "latest", Categories: []string{"movies"}, KeepLatest: 2}},
+			entries: []retentionTestEntry{
+				{name: "Third", category: "movies", age: 3 * day},
+				{name: "First", category: "movies", age: 1 * day},
+				{name: "Fourth", category: "movies", age: 4 * day},
+				{name: "Second", category: "movies", age: 2 * day},
+				{name: "Other", category: "tv", age: 9 * day},
+			},
+			want: map[string][]storage.RetentionReason{
+				"Third":  {storage.RetentionKeepLatest},
+				"Fourth": {storage.RetentionKeepLatest},
+			},
+		},
+		{
+			name:     "unplayed counts from the last play, or from when added",
+			policies: []config.RetentionPolicy{{Name: "unplayed", Categories: []string{"movies"}, UnplayedFor: "1w"}},
+			entries: []retentionTestEntry{
+				{name: "NeverPlayed", category: "movies", age: 10 * day},
+				{name: "PlayedRecently", category: "movies", age: 10 * day, played: day},
+				{name: "PlayedLongAgo", category: "movies", age: 10 * day, played: 8 * day},
+				{name: "New", category: "movies", age: 3 * day},
+			},
+			want: map[string][]storage.RetentionReason{
+				"NeverPlayed":   {storage.RetentionUnplayed},
+				"PlayedLongAgo": {storage.RetentionUnplayed},
+			},
+		},
+		{
+			name:     "unreferenced waits out the import grace",
+			policies: []config.RetentionPolicy{{Name: "gone", Categories: []string{"radarr"}, Unreferenced: true}},
+			entries: []retentionTestEntry{
+				{name: "Dropped", category: "radarr", age: 2 * day, action: config.DownloadActionSymlink},
+				{name: "Importing", category: "radarr", age: time.Hour, action: config.DownloadActionSymlink},
+				{name: "Kept", category: "radarr", age: 2 * day, action: config.DownloadActionSymlink},
+				{name: "Downloaded", category: "radarr", age: 2 * day, action: config.DownloadActionDownload},
+			},
+			referenced: []string{"Kept"},
+			want: map[string][]storage.RetentionReason{
+				"Dropped": {storage.RetentionUnreferenced},
+			},
+		},
+		{
+			name: "an entry is matched by the first policy only",
+			policies: []config.RetentionPolicy{
+				{Name: "old", Categories: []string{"movies"}, MaxAge: "5d"},
+				{Name: "tagged", Tags: []string{"temp"}, MaxAge: "1d"},
+			},
+			entries: []retentionTestEntry{
+				{name: "Both", category: "movies", tags: []string{"temp"}, age: 10 * day},
+				{name: "TaggedOnly", category: "movies", tags: []string{"temp"}, age: 2 * day},
+				{name: "Fresh", category: "movies", tags: []string{"temp"}, age: 12 * time.Hour},
+			},
+			want: map[string][]storage.RetentionReason{
+				"Both":       {storage.RetentionMaxAge},
+				"TaggedOnly": {storage.RetentionMaxAge},
+			},
+			wantPolicy: map[string]string{"Both": "old", "TaggedOnly": "tagged"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+			now := time.Now()
+			folders := make(map[string]string)
+			for i, e := range tt.entries {
+				entry := &storage.Entry{
+					InfoHash:  fmt.Sprintf("%040x", i+1),
+					Name:      e.name,
+					Category:  e.category,
+					Tags:      e.tags,
+					Protocol:  config.ProtocolTorrent,
+					State:     storage.EntryStatePausedUP,
+					Action:    e.action,
+					CreatedAt: now.Add(-e.age),
+					Size:      1 << 20,
+				}
+				if err := m.storage.AddOrUpdate(entry); err != nil {
+					t.Fatal(err)
+				}
+				folders[e.name] = entry.GetFolder()
+				if e.played > 0 {
+					if err := m.storage.RecordPlay(entry.InfoHash, "movie.mkv", now.Add(-e.played)); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if tt.referenced != nil {
+				m.arr.AddOrUpdate(arr.New("radarr", fakeRadarr(t, tt.referenced, folders), "token", false, nil, "", ""))
+			}
+
+			var policies []*retentionPolicy
+			for _, p := range tt.policies {
+				maxAge, unplayedFor, err := p.Durations()
+				if err != nil {
+					t.Fatal(err)
+				}
+				policies = append(policies, &retentionPolicy{RetentionPolicy: p, maxAge: maxAge, unplayedFor: unplayedFor})
+			}
+			run := &storage.RetentionRun{ID: "run"}
+			m.retention(context.Background(), run, policies, true)
+
+			if run.Status != storage.RepairRunCompleted {
+				t.Fatalf("status = %s (%s), want completed", run.Status, run.Error)
+			}
+			got := make(map[string][]storage.RetentionReason)
+			for _, item := range run.Items {
+				if _, dup := got[item.Name]; dup {
+					t.Errorf("%s listed twice", item.Name)
+				}
+				got[item.Name] = item.Reasons
+				if want, ok := tt.wantPolicy[item.Name]; ok && item.Policy != want {
+					t.Errorf("%s matched by %s, want %s", item.Name, item.Policy, want)
+				}
+				if item.Deleted {
+					t.Errorf("%s deleted in a dry run", item.Name)
+				}
+			}
+			for name, reasons := range tt.want {
+				if !slices.Equal(got[name], reasons) {
+					t.Errorf("%s reasons = %v, want %v", name, got[name], reasons)
+				}
+			}
+			for name := range got {
+				if _, ok := tt.want[name]; !ok {
+					t.Errorf("%s matched with %v, want kept", name, got[name])
+				}
+			}
+			if run.Stats.Matched != len(tt.want) {
+				t.Errorf("matched = %d, want %d", run.Stats.Matched, len(tt.want))
+			}
+		})
+	}
+}
+
+// fakeRadarr serves one movie per referenced entry, whose file is a symlink
+// into that entry's folder
+func fakeRadarr(t *testing.T, referenced []string, folders map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	var movies []arr.Movie
+	for i, name := range referenced {
+		link := filepath.Join(dir, name+".mkv")
+		if err := os.Symlink(filepath.Join("/mnt/remote/__all__", folders[name], "movie.mkv"), link); err != nil {
+			t.Fatal(err)
+		}
+		var movie arr.Movie
+		movie.Id = i + 1
+		movie.Title = name
+		movie.MovieFile.Id = i + 1
+		movie.MovieFile.Path = link
+		movies = append(movies, movie)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/movie" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(movies)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
@@ -0,0 +1,77 @@
+package manager
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// speedLimiter paces writes so that every writer sharing it stays under one
+// combined rate. A zero limit lets writes through untouched.
+type speedLimiter struct {
+	limit atomic.Int64 // Bytes per second
+	mu    sync.Mutex
+	next  time.Time // When the bytes reserved so far are paid off
+}
+
+// wait blocks until n more bytes fit under the limit.
+func (l *speedLimiter) wait(ctx context.Context, n int) error {
+	limit := l.limit.Load()
+	if limit <= 0 || n <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(time.Duration(float64(n) / float64(limit) * float64(time.Second)))
+	l.mu.Unlock()
+
+	delay := start.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// writer wraps w so writes to it are paced by the limiter.
+func (l *speedLimiter) writer(ctx context.Context, w io.Writer) io.Writer {
+	return &limitedWriter{ctx: ctx, w: w, limiter: l}
+}
+
+type limitedWriter struct {
+	ctx     context.Context
+	w       io.Writer
+	limiter *speedLimiter
+}
+
+func (lw *limitedWriter) Write(p []byte) (int, error) {
+	if err := lw.limiter.wait(lw.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return lw.w.Write(p)
+}
+
+// SetDownloadSpeedLimit caps the combined speed of local usenet downloads in
+// bytes per second. Zero removes the cap. The limit is not persisted.
+func (m *Manager) SetDownloadSpeedLimit(bytesPerSecond int64) {
+	m.speedLimit.limit.Store(max(bytesPerSecond, 0))
+}
+
+// DownloadSpeedLimit returns the current download cap in bytes per second,
+// or zero when downloads are not limited.
+func (m *Manager) DownloadSpeedLimit() int64 {
+	return m.speedLimit.limit.Load()
+}
@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/sirrobot01/decypharr/internal/config"
 	"github.com/sirrobot01/decypharr/internal/retry"
@@ -19,6 +20,10 @@ import (
 
 const (
 	streamBufferSize = 256 * 1024
+
+	// playRecordInterval is how often an entry being streamed has its play
+	// persisted
+	playRecordInterval = time.Hour
 )
 
 // streamBufPool provides reusable buffers for streaming to reduce GC pressure.
@@ -65,6 +70,7 @@ func (m *Manager) registerStream(entryName, fileName string, fileSize int64, sou
 	}
 
 	m.activeStreams.Store(streamID, stream)
+	publishStream(StreamEventStarted, *stream)
 	return streamID
 }
 
@@ -73,7 +79,9 @@ func (m *Manager) unregisterStream(streamID string) {
 	if streamID == "" {
 		return
 	}
-	m.activeStreams.Delete(streamID)
+	if stream, ok := m.activeStreams.LoadAndDelete(streamID); ok {
+		publishStream(StreamEventStopped, *stream)
+	}
 }
 
 // GetActiveStreams returns all currently active streams.
@@ -150,11 +158,11 @@ func (m *Manager) Stream(ctx context.Context, entry *storage.Entry, filename str
 	}
 
 	// Route based on protocol
-	if entry.Protocol == config.ProtocolNZB {
+	if entry.OnUsenet() {
 		return m.streamUsenet(ctx, entry, filename, start, end, writer, onReady)
 	}
 
-	// Default to HTTP streaming for torrents
+	// Default to HTTP streaming for torrents and NZBs on a debrid
 	return m.streamHTTP(ctx, entry, filename, start, end, writer, onReady)
 }
 
@@ -170,16 +178,34 @@ func (m *Manager) TrackStream(entry *storage.Entry, filename, client string) str
 	}
 
 	var source, debrid string
-	if entry.Protocol == config.ProtocolNZB {
+	if entry.IsNZB() {
 		source = "nzb"
 	} else {
 		source = "torrent"
+	}
+	if !entry.OnUsenet() {
 		debrid = entry.ActiveProvider
 	}
 
+	m.recordPlay(entry.InfoHash, filename)
 	return m.registerStream(entry.Name, filename, file.Size, source, debrid, client)
 }
 
+// recordPlay persists the entry's stream history for retention policies. A
+// player opens the same file many times while it plays, so it is written at
+// most once per playRecordInterval.
+func (m *Manager) recordPlay(infohash, filename string) {
+	now := time.Now()
+	key := strings.ToLower(infohash)
+	if last, ok := m.lastPlays.Load(key); ok && now.Sub(last) < playRecordInterval {
+		return
+	}
+	m.lastPlays.Store(key, now)
+	if err := m.storage.RecordPlay(infohash, filename, now); err != nil {
+		m.logger.Debug().Err(err).Str("infohash", infohash).Msg("Failed to record play")
+	}
+}
+
 // UntrackStream removes a previously-registered active stream if the ID is non-empty.
 func (m *Manager) UntrackStream(streamID string) {
 	m.unregisterStream(streamID)
@@ -251,6 +277,7 @@ func (m *Manager) streamHTTP(ctx context.Context, torrent *storage.Entry, filena
 		}
 		n, copyErr := io.CopyBuffer(writer, reader, buf)
 		resp.Body.Close()
+		m.recordLinkTraffic(downloadLink, n)
 
 		if expectedLen > 0 && n < expectedLen && copyErr == nil {
 			copyErr = io.ErrUnexpectedEOF
@@ -0,0 +1,468 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+const (
+	switchBatchDefaultConcurrency = 2
+	switchBatchMaxConcurrency     = 20
+	// How long to wait before asking the target debrid for free slots again
+	// when it reports none.
+	switchBatchSlotWait = 30 * time.Second
+	// Progress is stored after this many finished items, or on the interval,
+	// whichever comes first.
+	switchBatchSaveEvery    = 25
+	switchBatchSaveInterval = 5 * time.Second
+)
+
+var (
+	ErrSwitchBatchNotFound   = errors.New("switch batch not found")
+	ErrSwitchBatchRunning    = errors.New("switch batch is already running")
+	ErrSwitchBatchNotRunning = errors.New("switch batch is not running")
+	ErrSwitchBatchFinished   = errors.New("switch batch has already finished")
+)
+
+// SwitchBatchRequest describes a bulk migration of every entry matching
+// Filter to Target.
+type SwitchBatchRequest struct {
+	Filter      storage.SwitchBatchFilter
+	Target      string
+	KeepOld     bool
+	DryRun      bool
+	Concurrency int
+}
+
+type switchBatchStop int
+
+const (
+	switchBatchStopNone switchBatchStop = iota
+	switchBatchStopPause
+	switchBatchStopCancel
+	switchBatchStopShutdown
+)
+
+// switchBatchRun is the in-memory handle of a batch that is currently
+// executing. mu guards the batch record, which workers update concurrently.
+// unsaved counts items finished since the batch was last stored.
+type switchBatchRun struct {
+	mu      sync.Mutex
+	batch   *storage.SwitchBatch
+	cancel  context.CancelFunc
+	stop    switchBatchStop
+	unsaved int
+
+	// reserved counts target slots held by items still migrating, so the
+	// workers don't all take the same free slot
+	reserved int
+}
+
+// StartSwitchBatch snapshots the entries matching the request into a new
+// batch and starts migrating them. A dry run only records which entries
+// would move and which would be skipped.
+func (m *Manager) StartSwitchBatch(req SwitchBatchRequest) (*storage.SwitchBatch, error) {
+	if req.Target == "" {
+		return nil, fmt.Errorf("target debrid is required")
+	}
+	if m.ProviderClient(req.Target) == nil && !m.acceptsNZB(req.Target) {
+		return nil, fmt.Errorf("target debrid %s not found", req.Target)
+	}
+	if req.Filter.Provider == req.Target {
+		return nil, fmt.Errorf("source and target debrid are the same")
+	}
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = switchBatchDefaultConcurrency
+	}
+	concurrency = min(concurrency, switchBatchMaxConcurrency)
+
+	entries, err := m.storage.List(func(e *storage.Entry) bool {
+		return req.Filter.Matches(e)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entries: %w", err)
+	}
+
+	now := time.Now()
+	batch := &storage.SwitchBatch{
+		ID:             uuid.New().String(),
+		Filter:         req.Filter,
+		TargetProvider: req.Target,
+		KeepOld:        req.KeepOld,
+		DryRun:         req.DryRun,
+		Concurrency:    concurrency,
+		Status:         storage.SwitcherStatusPending,
+		Items:          make([]*storage.SwitcherJob, 0, len(entries)),
+		CreatedAt:      now,
+	}
+	for _, entry := range entries {
+		item := &storage.SwitcherJob{
+			ID:             uuid.New().String(),
+			InfoHash:       entry.InfoHash,
+			SourceProvider: entry.ActiveProvider,
+			TargetProvider: req.Target,
+			Status:         storage.SwitcherStatusPending,
+			CreatedAt:      now,
+			KeepOld:        req.KeepOld,
+		}
+		switch {
+		case entry.ActiveProvider == req.Target:
+			item.Status = storage.SwitcherStatusSkipped
+			item.Error = storage.ErrAlreadyOnDebrid.Error()
+		case !entry.CanBeMoved() || !m.canHost(entry, req.Target):
+			item.Status = storage.SwitcherStatusSkipped
+			item.Error = "entry cannot be moved"
+		}
+		batch.Items = append(batch.Items, item)
+	}
+	batch.Recount()
+
+	if req.DryRun || batch.Stats.Pending == 0 {
+		batch.Status = storage.SwitcherStatusCompleted
+		batch.CompletedAt = &now
+		if err := m.storage.SaveSwitchBatch(batch); err != nil {
+			return nil, err
+		}
+		return batch, nil
+	}
+
+	batch.Status = storage.SwitcherStatusInProgress
+	if err := m.storage.SaveSwitchBatch(batch); err != nil {
+		return nil, err
+	}
+	m.logger.Info().
+		Str("batch_id", batch.ID).
+		Str("target", batch.TargetProvider).
+		Int("entries", batch.Stats.Pending).
+		Msg("Starting bulk migration")
+	m.runSwitchBatch(batch)
+	return batch, nil
+}
+
+// GetSwitchBatch returns the live copy of a running batch, or the stored one.
+func (m *Manager) GetSwitchBatch(id string) (*storage.SwitchBatch, error) {
+	if run, ok := m.switchBatches.Load(id); ok {
+		run.mu.Lock()
+		defer run.mu.Unlock()
+		return copySwitchBatch(run.batch), nil
+	}
+	batch, err := m.storage.GetSwitchBatch(id)
+	if err != nil {
+		return nil, ErrSwitchBatchNotFound
+	}
+	return batch, nil
+}
+
+func (m *Manager) ListSwitchBatches() ([]*storage.SwitchBatch, error) {
+	batches, err := m.storage.ListSwitchBatches()
+	if err != nil {
+		return nil, err
+	}
+	for i, batch := range batches {
+		if run, ok := m.switchBatches.Load(batch.ID); ok {
+			run.mu.Lock()
+			batches[i] = copySwitchBatch(run.batch)
+			run.mu.Unlock()
+		}
+	}
+	return batches, nil
+}
+
+// PauseSwitchBatch stops dispatching new items. Migrations already in flight
+// finish first; the batch can be resumed later.
+func (m *Manager) PauseSwitchBatch(id string) error {
+	return m.stopSwitchBatch(id, switchBatchStopPause)
+}
+
+// CancelSwitchBatch stops the batch for good. Pending items are marked
+// cancelled.
+func (m *Manager) CancelSwitchBatch(id string) error {
+	if err := m.stopSwitchBatch(id, switchBatchStopCancel); !errors.Is(err, ErrSwitchBatchNotRunning) {
+		return err
+	}
+	// Not running: a paused batch can still be cancelled.
+	batch, err := m.storage.GetSwitchBatch(id)
+	if err != nil {
+		return ErrSwitchBatchNotFound
+	}
+	if batch.Finished() {
+		return ErrSwitchBatchFinished
+	}
+	finishSwitchBatch(batch, storage.SwitcherStatusCancelled)
+	return m.storage.SaveSwitchBatch(batch)
+}
+
+func (m *Manager) ResumeSwitchBatch(id string) error {
+	if _, ok := m.switchBatches.Load(id); ok {
+		return ErrSwitchBatchRunning
+	}
+	batch, err := m.storage.GetSwitchBatch(id)
+	if err != nil {
+		return ErrSwitchBatchNotFound
+	}
+	if batch.Finished() {
+		return ErrSwitchBatchFinished
+	}
+	batch.Status = storage.SwitcherStatusInProgress
+	if err := m.storage.SaveSwitchBatch(batch); err != nil {
+		return err
+	}
+	m.runSwitchBatch(batch)
+	return nil
+}
+
+func (m *Manager) DeleteSwitchBatch(id string) error {
+	if _, ok := m.switchBatches.Load(id); ok {
+		return ErrSwitchBatchRunning
+	}
+	return m.storage.DeleteSwitchBatch(id)
+}
+
+func (m *Manager) stopSwitchBatch(id string, reason switchBatchStop) error {
+	run, ok := m.switchBatches.Load(id)
+	if !ok {
+		return ErrSwitchBatchNotRunning
+	}
+	run.mu.Lock()
+	run.stop = reason
+	run.mu.Unlock()
+	run.cancel()
+	return nil
+}
+
+// resumeSwitchBatches restarts batches that were in progress when the process
+// last stopped.
+func (m *Manager) resumeSwitchBatches() {
+	batches, err := m.storage.ListSwitchBatches()
+	if err != nil {
+		m.logger.Warn().Err(err).Msg("Failed to load bulk migrations")
+		return
+	}
+	for _, batch := range batches {
+		if batch.Status != storage.SwitcherStatusInProgress {
+			continue
+		}
+		m.logger.Info().Str("batch_id", batch.ID).Int("pending", batch.Stats.Pending).Msg("Resuming bulk migration")
+		m.runSwitchBatch(batch)
+	}
+}
+
+// stopSwitchBatches interrupts running batches on shutdown without changing
+// their stored status, so they resume on the next start.
+func (m *Manager) stopSwitchBatches() {
+	m.switchBatches.Range(func(id string, _ *switchBatchRun) bool {
+		_ = m.stopSwitchBatch(id, switchBatchStopShutdown)
+		return true
+	})
+}
+
+func (m *Manager) runSwitchBatch(batch *storage.SwitchBatch) {
+	// Items interrupted mid-migration are retried.
+	for _, item := range batch.Items {
+		if item.Status == storage.SwitcherStatusInProgress {
+			item.Status = storage.SwitcherStatusPending
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &switchBatchRun{batch: batch, cancel: cancel}
+	if _, loaded := m.switchBatches.LoadOrStore(batch.ID, run); loaded {
+		cancel()
+		return
+	}
+	go func() {
+		defer cancel()
+		defer m.switchBatches.Delete(batch.ID)
+		m.executeSwitchBatch(ctx, run)
+	}()
+}
+
+func (m *Manager) executeSwitchBatch(ctx context.Context, run *switchBatchRun) {
+	batch := run.batch
+	items := make(chan *storage.SwitcherJob)
+	go func() {
+		defer close(items)
+		for _, item := range pendingSwitchItems(run) {
+			select {
+			case items <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range max(batch.Concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range items {
+				if !m.reserveSwitchBatchSlot(ctx, run) {
+					return
+				}
+				run.mu.Lock()
+				item.Status = storage.SwitcherStatusInProgress
+				snapshot := *item
+				run.mu.Unlock()
+
+				result := m.migrateSwitchItem(ctx, snapshot)
+				run.mu.Lock()
+				run.reserved--
+				*item = result
+				run.unsaved++
+				if run.unsaved >= switchBatchSaveEvery {
+					m.saveSwitchBatchProgress(run)
+				}
+				run.mu.Unlock()
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(switchBatchSaveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				run.mu.Lock()
+				if run.unsaved > 0 {
+					m.saveSwitchBatchProgress(run)
+				}
+				run.mu.Unlock()
+			}
+		}
+	}()
+	wg.Wait()
+	close(done)
+
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	switch run.stop {
+	case switchBatchStopShutdown:
+		// Leave the batch in progress so it resumes on the next start.
+	case switchBatchStopPause:
+		batch.Status = storage.SwitcherStatusPaused
+	case switchBatchStopCancel:
+		finishSwitchBatch(batch, storage.SwitcherStatusCancelled)
+	default:
+		finishSwitchBatch(batch, storage.SwitcherStatusCompleted)
+	}
+	m.saveSwitchBatchProgress(run)
+	m.logger.Info().
+		Str("batch_id", batch.ID).
+		Str("status", string(batch.Status)).
+		Int("completed", batch.Stats.Completed).
+		Int("failed", batch.Stats.Failed).
+		Int("skipped", batch.Stats.Skipped).
+		Msg("Bulk migration stopped")
+}
+
+// saveSwitchBatchProgress recounts and stores the batch. Callers hold run.mu.
+func (m *Manager) saveSwitchBatchProgress(run *switchBatchRun) {
+	run.batch.Recount()
+	if err := m.storage.SaveSwitchBatch(run.batch); err != nil {
+		m.logger.Error().Err(err).Str("batch_id", run.batch.ID).Msg("Failed to save bulk migration")
+		return
+	}
+	run.unsaved = 0
+}
+
+// reserveSwitchBatchSlot blocks until the target debrid has a slot free that
+// no other worker of the batch holds, and takes it. The caller gives it back
+// by decrementing run.reserved. It returns false if ctx is cancelled
+// meanwhile.
+func (m *Manager) reserveSwitchBatchSlot(ctx context.Context, run *switchBatchRun) bool {
+	target := run.batch.TargetProvider
+	client := m.ProviderClient(target)
+	for ctx.Err() == nil {
+		slots := -1 // Unknown: don't hold the batch back
+		if client != nil {
+			var err error
+			if slots, err = client.GetAvailableSlots(); err != nil {
+				m.logger.Debug().Err(err).Str("debrid", target).Msg("Failed to get available slots, continuing")
+				slots = -1
+			}
+		}
+		run.mu.Lock()
+		free := slots < 0 || slots > run.reserved
+		if free {
+			run.reserved++
+		}
+		run.mu.Unlock()
+		if free {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(switchBatchSlotWait):
+		}
+	}
+	return false
+}
+
+// migrateSwitchItem moves one entry. It works on a copy of the item so the
+// batch record is only touched under the run lock.
+func (m *Manager) migrateSwitchItem(ctx context.Context, item storage.SwitcherJob) storage.SwitcherJob {
+	entry, err := m.GetEntry(item.InfoHash)
+	if err != nil {
+		item.Status = storage.SwitcherStatusFailed
+		item.Error = fmt.Sprintf("failed to get entry: %v", err)
+		item.CompletedAt = new(time.Now())
+		return item
+	}
+	if entry.ActiveProvider == item.TargetProvider {
+		item.Status = storage.SwitcherStatusSkipped
+		item.Error = storage.ErrAlreadyOnDebrid.Error()
+		item.CompletedAt = new(time.Now())
+		return item
+	}
+	item.SourceProvider = entry.ActiveProvider
+	m.executeMigration(ctx, &item, entry)
+	return item
+}
+
+func pendingSwitchItems(run *switchBatchRun) []*storage.SwitcherJob {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	pending := make([]*storage.SwitcherJob, 0)
+	for _, item := range run.batch.Items {
+		if item.Status == storage.SwitcherStatusPending {
+			pending = append(pending, item)
+		}
+	}
+	return pending
+}
+
+func finishSwitchBatch(batch *storage.SwitchBatch, status storage.SwitcherStatus) {
+	now := time.Now()
+	if status == storage.SwitcherStatusCancelled {
+		for _, item := range batch.Items {
+			if item.Status == storage.SwitcherStatusPending || item.Status == storage.SwitcherStatusInProgress {
+				item.Status = storage.SwitcherStatusCancelled
+				item.CompletedAt = &now
+			}
+		}
+	}
+	batch.Status = status
+	batch.CompletedAt = &now
+	batch.Recount()
+}
+
+func copySwitchBatch(batch *storage.SwitchBatch) *storage.SwitchBatch {
+	out := *batch
+	out.Items = make([]*storage.SwitcherJob, len(batch.Items))
+	for i, item := range batch.Items {
+		cp := *item
+		out.Items[i] = &cp
+	}
+	return &out
+}
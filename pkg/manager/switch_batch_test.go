@@ -0,0 +1,208 @@
+package manager
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	debrid "github.com/sirrobot01/decypharr/pkg/debrid/common"
+	"github.com/sirrobot01/decypharr/pkg/debrid/types"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// fakeDebrid accepts every magnet as a cached, downloaded torrent. Methods
+// the tests don't reach are left to the nil embedded Client.
+type fakeDebrid struct {
+	debrid.Client
+	name      string
+	submitted atomic.Int32
+
+	// When set, each submit announces itself on started and waits on release
+	started chan string
+	release chan struct{}
+
+	// Torrents the debrid lists
+	listed []*types.Torrent
+}
+
+func (f *fakeDebrid) SubmitMagnet(tr *types.Torrent) (*types.Torrent, error) {
+	if f.started != nil {
+		f.started <- tr.InfoHash
+		<-f.release
+	}
+	f.submitted.Add(1)
+	tr.Id = "id-" + tr.InfoHash
+	tr.Debrid = f.name
+	tr.Status = types.TorrentStatusDownloaded
+	tr.Files = map[string]types.File{
+		"movie.mkv": {Id: "1", Name: "movie.mkv", Size: 1 << 20},
+	}
+	return tr, nil
+}
+
+func (f *fakeDebrid) GetTorrents() ([]*types.Torrent, error) { return f.listed, nil }
+
+func (f *fakeDebrid) GetTorrent(id string) (*types.Torrent, error) {
+	for _, t := range f.listed {
+		if t.Id == id {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("torrent %s not found", id)
+}
+
+func (f *fakeDebrid) CheckStatus(tr *types.Torrent) (*types.Torrent, error) { return tr, nil }
+func (f *fakeDebrid) GetAvailableSlots() (int, error)                       { return 10, nil }
+func (f *fakeDebrid) DeleteTorrent(string) error                            { return nil }
+
+func addSwitchTestEntries(t *testing.T, m *Manager, n int) []string {
+	t.Helper()
+	hashes := make([]string, 0, n)
+	for i := range n {
+		hash := fmt.Sprintf("%040x", i+1)
+		entry := &storage.Entry{
+			InfoHash:       hash,
+			Name:           fmt.Sprintf("Movie.%d", i+1),
+			Protocol:       config.ProtocolTorrent,
+			ActiveProvider: "source",
+			Size:           1 << 20,
+		}
+		if err := m.storage.AddOrUpdate(entry); err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+// waitSwitchBatch waits for a batch to stop running and returns its stored copy
+func waitSwitchBatch(t *testing.T, m *Manager, id string) *storage.SwitchBatch {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, running := m.switchBatches.Load(id); !running {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("batch still running")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	batch, err := m.storage.GetSwitchBatch(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return batch
+}
+
+func TestSwitchBatchPauseAndResume(t *testing.T) {
+	m := newTestManager(t)
+	target := &fakeDebrid{name: "target", started: make(chan string), release: make(chan struct{})}
+	m.clients.Store("target", target)
+	addSwitchTestEntries(t, m, 3)
+
+	batch, err := m.StartSwitchBatch(SwitchBatchRequest{
+		Filter:      storage.SwitchBatchFilter{Provider: "source"},
+		Target:      "target",
+		KeepOld:     true,
+		Concurrency: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pause while the first item is being submitted; it still finishes
+	<-target.started
+	if err := m.PauseSwitchBatch(batch.ID); err != nil {
+		t.Fatal(err)
+	}
+	close(target.release)
+	target.started = nil
+
+	paused := waitSwitchBatch(t, m, batch.ID)
+	if paused.Status != storage.SwitcherStatusPaused {
+		t.Fatalf("status after pause = %s, want %s", paused.Status, storage.SwitcherStatusPaused)
+	}
+	if paused.Stats.Completed != 1 || paused.Stats.Pending != 2 {
+		t.Fatalf("stats after pause = %+v, want 1 completed and 2 pending", paused.Stats)
+	}
+
+	if err := m.ResumeSwitchBatch(batch.ID); err != nil {
+		t.Fatal(err)
+	}
+	done := waitSwitchBatch(t, m, batch.ID)
+	if done.Status != storage.SwitcherStatusCompleted {
+		t.Fatalf("status after resume = %s, want %s", done.Status, storage.SwitcherStatusCompleted)
+	}
+	if done.Stats.Completed != 3 {
+		t.Errorf("completed after resume = %d, want 3", done.Stats.Completed)
+	}
+	// The item finished before the pause isn't migrated again
+	if got := target.submitted.Load(); got != 3 {
+		t.Errorf("submitted %d magnets, want 3", got)
+	}
+	for _, item := range done.Items {
+		entry, err := m.GetEntry(item.InfoHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry.ActiveProvider != "target" {
+			t.Errorf("%s is served from %q, want target", entry.Name, entry.ActiveProvider)
+		}
+	}
+}
+
+func TestResumeSwitchBatchesAfterRestart(t *testing.T) {
+	m := newTestManager(t)
+	target := &fakeDebrid{name: "target"}
+	m.clients.Store("target", target)
+	hashes := addSwitchTestEntries(t, m, 3)
+
+	// As stored by a process stopped mid-run: one item done, one in flight
+	// and one not started
+	statuses := []storage.SwitcherStatus{storage.SwitcherStatusCompleted, storage.SwitcherStatusInProgress, storage.SwitcherStatusPending}
+	batch := &storage.SwitchBatch{
+		ID:             "interrupted",
+		Filter:         storage.SwitchBatchFilter{Provider: "source"},
+		TargetProvider: "target",
+		KeepOld:        true,
+		Concurrency:    2,
+		Status:         storage.SwitcherStatusInProgress,
+		CreatedAt:      time.Now(),
+	}
+	for i, hash := range hashes {
+		batch.Items = append(batch.Items, &storage.SwitcherJob{
+			ID:             fmt.Sprintf("item-%d", i),
+			InfoHash:       hash,
+			SourceProvider: "source",
+			TargetProvider: "target",
+			Status:         statuses[i],
+			KeepOld:        true,
+		})
+	}
+	batch.Recount()
+	if err := m.storage.SaveSwitchBatch(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	m.resumeSwitchBatches()
+	done := waitSwitchBatch(t, m, batch.ID)
+	if done.Status != storage.SwitcherStatusCompleted {
+		t.Fatalf("status = %s, want %s", done.Status, storage.SwitcherStatusCompleted)
+	}
+	if done.Stats.Completed != 3 {
+		t.Errorf("completed = %d, want 3", done.Stats.Completed)
+	}
+	if got := target.submitted.Load(); got != 2 {
+		t.Errorf("submitted %d magnets, want 2: the interrupted and the pending item", got)
+	}
+	first, err := m.GetEntry(hashes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ActiveProvider != "source" {
+		t.Errorf("item completed before the restart was migrated again")
+	}
+}
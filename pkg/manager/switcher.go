@@ -2,6 +2,7 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -46,13 +47,15 @@ func (m *Manager) SwitchTorrent(ctx context.Context, infohash, target string, ke
 	m.migrationJobs.Store(job.ID, job)
 
 	// Start migration in background
-	go m.executeMigration(job, entry)
+	go m.executeMigration(m.ctx, job, entry)
 
 	return job, nil
 }
 
-// executeMigration performs the actual torrent migration - COMPLETE IMPLEMENTATION
-func (m *Manager) executeMigration(job *storage.SwitcherJob, torrent *storage.Entry) {
+// executeMigration performs the actual torrent migration - COMPLETE IMPLEMENTATION.
+// Cancelling ctx stops it waiting on a pending placement and leaves the job
+// in progress.
+func (m *Manager) executeMigration(ctx context.Context, job *storage.SwitcherJob, torrent *storage.Entry) {
 	m.logger.Info().
 		Str("job_id", job.ID).
 		Str("torrent", torrent.Name).
@@ -61,11 +64,9 @@ func (m *Manager) executeMigration(job *storage.SwitcherJob, torrent *storage.En
 		Msg("Starting torrent migration")
 	job.Status = storage.SwitcherStatusInProgress
 
-	// GetReader target debrid client
-	targetClient := m.ProviderClient(job.TargetProvider)
-	if targetClient == nil {
+	if !m.canHost(torrent, job.TargetProvider) {
 		job.Status = storage.SwitcherStatusFailed
-		job.Error = fmt.Sprintf("target debrid %s not found", job.TargetProvider)
+		job.Error = fmt.Sprintf("target %s cannot host %s", job.TargetProvider, torrent.Name)
 		job.CompletedAt = new(time.Now())
 		return
 	}
@@ -73,8 +74,30 @@ func (m *Manager) executeMigration(job *storage.SwitcherJob, torrent *storage.En
 
 	job.Progress = 10
 
+	placed, stopWatching := m.watchNZBPlacement(torrent.InfoHash, job.TargetProvider)
 	success, err := m.fixer.MoveTorrent(torrent, job.TargetProvider, false) // false = don't force re-download
-
+	if errors.Is(err, ErrPlacementPending) {
+		// The entry switches in the background; the job runs until it has,
+		// and the source placement stays until then.
+		job.Progress = 50
+		select {
+		case err = <-placed:
+			if err == nil {
+				success = true
+				torrent, err = m.GetEntry(torrent.InfoHash)
+			}
+		case <-ctx.Done():
+			// The placement carries on and the entry still switches once it
+			// is ready; only this job stops waiting for it.
+			stopWatching()
+			m.logger.Info().
+				Str("job_id", job.ID).
+				Str("torrent", torrent.Name).
+				Msg("Stopped waiting for pending placement")
+			return
+		}
+	}
+	stopWatching()
 	if err != nil || !success {
 		job.Status = storage.SwitcherStatusFailed
 		job.Error = fmt.Sprintf("failed to move torrent to target debrid: %v", err)
@@ -0,0 +1,307 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// errPreviewLimit stops a preview's pass over the entries once it has enough
+var errPreviewLimit = errors.New("preview limit reached")
+
+// tagRule is a config.TagRule with its patterns parsed
+type tagRule struct {
+	config.TagRule
+	nameRegex *regexp.Regexp
+	minSize   int64
+	maxSize   int64
+}
+
+func compileTagRules(rules []config.TagRule) ([]*tagRule, error) {
+	compiled := make([]*tagRule, 0, len(rules))
+	for _, r := range rules {
+		tr := &tagRule{TagRule: r}
+		var err error
+		if r.NameRegex != "" {
+			if tr.nameRegex, err = regexp.Compile(r.NameRegex); err != nil {
+				return nil, fmt.Errorf("tag rule %s: invalid name_regex: %w", r.Name, err)
+			}
+		}
+		if r.MinSize != "" {
+			if tr.minSize, err = config.ParseSize(r.MinSize); err != nil {
+				return nil, fmt.Errorf("tag rule %s: invalid min_size %q", r.Name, r.MinSize)
+			}
+		}
+		if r.MaxSize != "" {
+			if tr.maxSize, err = config.ParseSize(r.MaxSize); err != nil {
+				return nil, fmt.Errorf("tag rule %s: invalid max_size %q", r.Name, r.MaxSize)
+			}
+		}
+		compiled = append(compiled, tr)
+	}
+	return compiled, nil
+}
+
+// tagSubject is what tag rule conditions look at, worked out once per entry
+type tagSubject struct {
+	entry      *storage.Entry
+	resolution string
+	codec      string
+	arrs       []string // Name and type of the category's Arr
+	files      int
+}
+
+func (m *Manager) newTagSubject(entry *storage.Entry) *tagSubject {
+	s := &tagSubject{
+		entry:      entry,
+		resolution: utils.ReleaseResolution(entry.Name),
+		codec:      utils.ReleaseCodec(entry.Name),
+	}
+	if a := m.arr.Get(entry.Category); a != nil {
+		s.arrs = []string{a.Name, string(a.Type)}
+	}
+	for _, f := range entry.Files {
+		if f.Active() {
+			s.files++
+		}
+	}
+	return s
+}
+
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, value) })
+}
+
+func anyFold(values, wanted []string) bool {
+	return slices.ContainsFunc(values, func(v string) bool { return containsFold(wanted, v) })
+}
+
+// matches reports whether the entry meets every condition the rule sets.
+// tags are the entry's tags as the rules before this one left them.
+func (r *tagRule) matches(s *tagSubject, tags []string) bool {
+	e := s.entry
+	switch {
+	case r.nameRegex != nil && !r.nameRegex.MatchString(e.Name):
+		return false
+	case len(r.Resolutions) > 0 && !containsFold(r.Resolutions, s.resolution):
+		return false
+	case len(r.Codecs) > 0 && !containsFold(r.Codecs, s.codec):
+		return false
+	case r.minSize > 0 && e.Size < r.minSize:
+		return false
+	case r.maxSize > 0 && e.Size > r.maxSize:
+		return false
+	case len(r.Providers) > 0 && !containsFold(r.Providers, e.ActiveProvider):
+		return false
+	case len(r.Categories) > 0 && !containsFold(r.Categories, e.Category):
+		return false
+	case len(r.Arrs) > 0 && !anyFold(s.arrs, r.Arrs):
+		return false
+	case len(r.Tags) > 0 && !anyFold(tags, r.Tags):
+		return false
+	case r.MinFiles > 0 && s.files < r.MinFiles:
+		return false
+	case r.MaxFiles > 0 && s.files > r.MaxFiles:
+		return false
+	}
+	return true
+}
+
+// TagRuleOutcome is what the tag rules did, or would do, to an entry
+type TagRuleOutcome struct {
+	InfoHash string   `json:"infohash"`
+	Name     string   `json:"name"`
+	Rules    []string `json:"rules"`             // Rules that matched, in order
+	Tags     []string `json:"tags"`              // Tags once the rules ran
+	Added    []string `json:"added,omitempty"`   // Tags the rules added
+	Removed  []string `json:"removed,omitempty"` // Tags the rules removed
+	Profile  string   `json:"profile,omitempty"` // Category profile set by the last rule that sets one
+	Folders  []string `json:"folders,omitempty"` // Custom folders the rules routed the entry to
+}
+
+// evaluateTagRules runs the rules for event against an entry without
+// changing it. It returns nil when no rule matched.
+func (m *Manager) evaluateTagRules(rules []*tagRule, entry *storage.Entry, event config.TagRuleEvent) *TagRuleOutcome {
+	subject := m.newTagSubject(entry)
+	tags := slices.Clone(entry.Tags)
+	var outcome *TagRuleOutcome
+	for _, r := range rules {
+		if !r.RunsOn(event) || !r.matches(subject, tags) {
+			continue
+		}
+		if outcome == nil {
+			outcome = &TagRuleOutcome{InfoHash: entry.InfoHash, Name: entry.Name}
+		}
+		outcome.Rules = append(outcome.Rules, r.Name)
+		for _, tag := range r.AddTags {
+			if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		tags = utils.RemoveItem(tags, r.RemoveTags...)
+		if r.Profile != "" {
+			outcome.Profile = r.Profile
+		}
+		if r.Folder != "" && !slices.Contains(outcome.Folders, r.Folder) {
+			outcome.Folders = append(outcome.Folders, r.Folder)
+		}
+	}
+	if outcome == nil {
+		return nil
+	}
+	outcome.Tags = tags
+	for _, tag := range tags {
+		if !slices.Contains(entry.Tags, tag) {
+			outcome.Added = append(outcome.Added, tag)
+		}
+	}
+	for _, tag := range entry.Tags {
+		if !slices.Contains(tags, tag) {
+			outcome.Removed = append(outcome.Removed, tag)
+		}
+	}
+	return outcome
+}
+
+// LoadTagRules compiles the configured tag rules. It runs at startup and
+// again whenever the config is applied without a restart.
+func (m *Manager) LoadTagRules() {
+	rules, err := compileTagRules(config.Get().TagRules)
+	if err != nil {
+		m.logger.Error().Err(err).Msg("Invalid tag rules, skipping")
+		rules = nil
+	}
+	m.tagRules.Store(&rules)
+}
+
+// loadedTagRules returns the rules compiled by the last LoadTagRules
+func (m *Manager) loadedTagRules() []*tagRule {
+	if rules := m.tagRules.Load(); rules != nil {
+		return *rules
+	}
+	return nil
+}
+
+// applyTagRules runs the configured tag rules for event on an entry about to
+// be saved, and records what they changed on its timeline
+func (m *Manager) applyTagRules(entry *storage.Entry, event config.TagRuleEvent) {
+	rules := m.loadedTagRules()
+	if len(rules) == 0 {
+		return
+	}
+	outcome := m.evaluateTagRules(rules, entry, event)
+	if outcome == nil {
+		return
+	}
+	entry.Tags = outcome.Tags
+	for _, folder := range outcome.Folders {
+		if !slices.Contains(entry.Folders, folder) {
+			entry.Folders = append(entry.Folders, folder)
+		}
+	}
+	if outcome.Profile != "" && !strings.EqualFold(outcome.Profile, entry.Profile) {
+		entry.Profile = outcome.Profile
+		applyEntryProfile(entry)
+	}
+
+	m.logger.Debug().
+		Str("name", entry.Name).
+		Str("event", string(event)).
+		Strs("rules", outcome.Rules).
+		Strs("tags", entry.Tags).
+		Msg("Applied tag rules")
+	var changes []string
+	if len(outcome.Added) > 0 {
+		changes = append(changes, "added "+strings.Join(outcome.Added, ", "))
+	}
+	if len(outcome.Removed) > 0 {
+		changes = append(changes, "removed "+strings.Join(outcome.Removed, ", "))
+	}
+	if outcome.Profile != "" {
+		changes = append(changes, "profile "+outcome.Profile)
+	}
+	if len(outcome.Folders) > 0 {
+		changes = append(changes, "folders "+strings.Join(outcome.Folders, ", "))
+	}
+	if len(changes) == 0 {
+		return
+	}
+	m.RecordEvent(entry.InfoHash, storage.EntryEventTagged, "", fmt.Sprintf("Rules %s on %s: %s", strings.Join(outcome.Rules, ", "), event, strings.Join(changes, "; ")))
+}
+
+// applyEntryProfile switches an entry to the policy of the profile a tag rule
+// gave it. Only these four fields are taken from it: the post-download action,
+// the save folder, file selection and multi-season handling. The debrid
+// fields were used when the entry was submitted, and skip_repair is resolved
+// from the Arr's category by repair, so the rule's profile doesn't change
+// them.
+func applyEntryProfile(entry *storage.Entry) {
+	p := config.Get().ProfileNamed(entry.Profile)
+	if p == nil {
+		return
+	}
+	if p.DownloadAction != "" {
+		entry.Action = p.DownloadAction
+	}
+	if p.DownloadFolder != "" {
+		entry.SavePath = filepath.Join(p.DownloadFolder, entry.Category)
+		entry.ContentPath = entry.DownloadPath()
+	}
+	if !p.FileSelection.IsZero() {
+		entry.FileSelection = p.FileSelection.Clone()
+		entry.ApplyFileSelection()
+	}
+	if p.SkipMultiSeason != nil {
+		entry.SkipMultiSeason = *p.SkipMultiSeason
+	}
+}
+
+// TagRulePreviewOptions selects the rules and entries a preview runs on
+type TagRulePreviewOptions struct {
+	Rules []config.TagRule    // Rules to try; nil uses the configured ones
+	Event config.TagRuleEvent // Rules that don't run on it are left out
+	Limit int                 // Most outcomes to return; 0 returns all
+}
+
+// PreviewTagRules runs tag rules against the stored entries without changing
+// any, and returns what they would do to the entries they match
+func (m *Manager) PreviewTagRules(opts TagRulePreviewOptions) ([]*TagRuleOutcome, error) {
+	rules := m.loadedTagRules()
+	if opts.Rules != nil {
+		cfg := config.Get()
+		if err := config.ValidateTagRules(opts.Rules, cfg.CategoryProfiles, cfg.CustomFolders); err != nil {
+			return nil, err
+		}
+		var err error
+		if rules, err = compileTagRules(opts.Rules); err != nil {
+			return nil, err
+		}
+	}
+	event := opts.Event
+	if event == "" {
+		event = config.TagRuleOnComplete
+	}
+
+	outcomes := make([]*TagRuleOutcome, 0)
+	err := m.storage.ForEach(func(entry *storage.Entry) error {
+		if outcome := m.evaluateTagRules(rules, entry, event); outcome != nil {
+			outcomes = append(outcomes, outcome)
+			if opts.Limit > 0 && len(outcomes) >= opts.Limit {
+				return errPreviewLimit
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errPreviewLimit) {
+		return nil, err
+	}
+	slices.SortFunc(outcomes, func(a, b *TagRuleOutcome) int { return strings.Compare(a.Name, b.Name) })
+	return outcomes, nil
+}
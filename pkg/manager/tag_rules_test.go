@@ -0,0 +1,132 @@
+package manager
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/pkg/arr"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+func TestEvaluateTagRules(t *testing.T) {
+	arrs := arr.NewStorage()
+	arrs.AddOrUpdate(arr.New("tv", "http://sonarr:8989", "token", false, nil, "", ""))
+	m := &Manager{arr: arrs}
+
+	entry := func(tags ...string) *storage.Entry {
+		return &storage.Entry{
+			InfoHash:       "aaa",
+			Name:           "Show.S01.2160p.WEB.x265",
+			Category:       "tv",
+			ActiveProvider: "realdebrid",
+			Size:           2 << 30,
+			Tags:           tags,
+		}
+	}
+	tests := []struct {
+		name  string
+		rules []config.TagRule
+		entry *storage.Entry
+		event config.TagRuleEvent
+		want  *TagRuleOutcome // nil when no rule matches
+	}{
+		{
+			name:  "no rule matches",
+			rules: []config.TagRule{{Name: "movies", Categories: []string{"movies"}, AddTags: []string{"film"}}},
+			entry: entry(),
+		},
+		{
+			name: "tag condition sees tags added by earlier rules",
+			rules: []config.TagRule{
+				{Name: "uhd", Resolutions: []string{"2160p"}, AddTags: []string{"4k"}},
+				{Name: "keep", Tags: []string{"4k"}, AddTags: []string{"keep"}},
+			},
+			entry: entry(),
+			want:  &TagRuleOutcome{Rules: []string{"uhd", "keep"}, Tags: []string{"4k", "keep"}, Added: []string{"4k", "keep"}},
+		},
+		{
+			name: "tag condition doesn't see tags added by later rules",
+			rules: []config.TagRule{
+				{Name: "keep", Tags: []string{"4k"}, AddTags: []string{"keep"}},
+				{Name: "uhd", Resolutions: []string{"2160p"}, AddTags: []string{"4k"}},
+			},
+			entry: entry(),
+			want:  &TagRuleOutcome{Rules: []string{"uhd"}, Tags: []string{"4k"}, Added: []string{"4k"}},
+		},
+		{
+			name:  "add and remove",
+			rules: []config.TagRule{{Name: "swap", AddTags: []string{"new", " "}, RemoveTags: []string{"old"}}},
+			entry: entry("old", "other"),
+			want:  &TagRuleOutcome{Rules: []string{"swap"}, Tags: []string{"other", "new"}, Added: []string{"new"}, Removed: []string{"old"}},
+		},
+		{
+			name: "later rule removes what an earlier one added",
+			rules: []config.TagRule{
+				{Name: "add", AddTags: []string{"temp"}},
+				{Name: "drop", Tags: []string{"temp"}, RemoveTags: []string{"temp"}},
+			},
+			entry: entry("kept"),
+			want:  &TagRuleOutcome{Rules: []string{"add", "drop"}, Tags: []string{"kept"}},
+		},
+		{
+			name: "last profile wins and folders are listed once",
+			rules: []config.TagRule{
+				{Name: "first", Profile: "standard", Folder: "TV"},
+				{Name: "second", Arrs: []string{"sonarr"}, Profile: "uhd", Folder: "TV"},
+				{Name: "third", Codecs: []string{"h265"}, MinSize: "1GB", Folder: "HEVC"},
+			},
+			entry: entry(),
+			want:  &TagRuleOutcome{Rules: []string{"first", "second", "third"}, Profile: "uhd", Folders: []string{"TV", "HEVC"}},
+		},
+		{
+			name:  "rule for another event",
+			rules: []config.TagRule{{Name: "on-add", On: []config.TagRuleEvent{config.TagRuleOnAdd}, AddTags: []string{"new"}}},
+			entry: entry(),
+		},
+		{
+			name: "conditions that don't hold",
+			rules: []config.TagRule{
+				{Name: "small", MaxSize: "1GB", AddTags: []string{"small"}},
+				{Name: "radarr", Arrs: []string{"radarr"}, AddTags: []string{"film"}},
+				{Name: "torbox", Providers: []string{"torbox"}, AddTags: []string{"tb"}},
+				{Name: "episodes", MinFiles: 2, AddTags: []string{"pack"}},
+			},
+			entry: entry(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := compileTagRules(tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			event := tt.event
+			if event == "" {
+				event = config.TagRuleOnComplete
+			}
+			before := append([]string(nil), tt.entry.Tags...)
+			got := m.evaluateTagRules(rules, tt.entry, event)
+			if !reflect.DeepEqual(tt.entry.Tags, before) {
+				t.Errorf("entry tags changed to %v", tt.entry.Tags)
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("outcome = %+v, want none", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("no outcome, want one")
+			}
+			tt.want.InfoHash, tt.want.Name = tt.entry.InfoHash, tt.entry.Name
+			// No tags may come back as nil or empty
+			if len(got.Tags) == 0 && len(tt.want.Tags) == 0 {
+				got.Tags = tt.want.Tags
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("outcome = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
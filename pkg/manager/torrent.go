@@ -64,7 +64,7 @@ func (m *Manager) refreshTorrents(ctx context.Context, provider string, debridCl
 
 // doRefreshTorrents performs the actual refresh logic
 func (m *Manager) doRefreshTorrents(_ context.Context, provider string, debridClient debrid.Client) error {
-	remote, err := debridClient.GetTorrents()
+	remote, err := listRemote(debridClient)
 	if err != nil {
 		m.logger.Error().Err(err).Str("debrid", provider).Msg("Failed to get remote")
 		return err
@@ -77,7 +77,13 @@ func (m *Manager) doRefreshTorrents(_ context.Context, provider string, debridCl
 
 	// Build map of current remote by infohash
 	remoteTorrentsByHash := make(map[string]*types.Torrent, len(remote))
+	remoteByID := make(map[string]*types.Torrent, len(remote))
 	for _, t := range remote {
+		remoteByID[t.Id] = t
+		// NZB transfers have no infohash; their entries track them by ID
+		if t.InfoHash == "" {
+			continue
+		}
 		old, exists := remoteTorrentsByHash[t.InfoHash]
 		if !exists {
 			remoteTorrentsByHash[t.InfoHash] = t
@@ -88,7 +94,7 @@ func (m *Manager) doRefreshTorrents(_ context.Context, provider string, debridCl
 	}
 
 	// Detect changes by streaming through cached entries
-	newTorrents, torrentsToUpdate, torrentsToDelete, err := m.detectTorrentChanges(provider, remoteTorrentsByHash)
+	newTorrents, torrentsToUpdate, torrentsToDelete, err := m.detectTorrentChanges(provider, remoteTorrentsByHash, remoteByID)
 	if err != nil {
 		return err
 	}
@@ -121,8 +127,9 @@ func (m *Manager) doRefreshTorrents(_ context.Context, provider string, debridCl
 	return nil
 }
 
-// detectTorrentChanges streams through cached entries and detects what changed
-func (m *Manager) detectTorrentChanges(provider string, remoteTorrentsByHash map[string]*types.Torrent) (
+// detectTorrentChanges streams through cached entries and detects what changed.
+// NZB entries on a debrid usenet service are matched by placement ID.
+func (m *Manager) detectTorrentChanges(provider string, remoteTorrentsByHash, remoteByID map[string]*types.Torrent) (
 	newTorrents []*types.Torrent,
 	torrentsToUpdate []*storage.Entry,
 	torrentsToDelete []string,
@@ -135,10 +142,21 @@ func (m *Manager) detectTorrentChanges(provider string, remoteTorrentsByHash map
 
 	err = m.storage.ForEachBatch(refreshBatchSize, func(batch []*storage.Entry) error {
 		for _, entry := range batch {
-			cachedInfoHashes[entry.InfoHash] = true
-
-			currentTorrent, onRemote := remoteTorrentsByHash[entry.InfoHash]
 			oldPlacement, placementOnDebrid := entry.Providers[provider]
+			var currentTorrent *types.Torrent
+			var onRemote bool
+			if entry.IsNZB() {
+				if !placementOnDebrid || oldPlacement == nil {
+					continue
+				}
+				if currentTorrent, onRemote = remoteByID[oldPlacement.ID]; onRemote {
+					// The debrid knows nothing of the entry's ID
+					currentTorrent.InfoHash = entry.InfoHash
+				}
+			} else {
+				cachedInfoHashes[entry.InfoHash] = true
+				currentTorrent, onRemote = remoteTorrentsByHash[entry.InfoHash]
+			}
 
 			if placementOnDebrid {
 				if !onRemote {
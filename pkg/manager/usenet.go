@@ -5,25 +5,46 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/nntp"
 	debridTypes "github.com/sirrobot01/decypharr/pkg/debrid/types"
 	"github.com/sirrobot01/decypharr/pkg/storage"
 	"github.com/sirrobot01/decypharr/pkg/usenet/parser"
 )
 
-// AddNewNZB parses an NZB before entering the active-download queue.
+// AddNewNZB parses an NZB before entering the active-download queue. NZBs go
+// to the NNTP providers first; debrids with usenet enabled take the ones the
+// NNTP providers reject, or come first when usenet.prefer_debrid is set.
 func (m *Manager) AddNewNZB(ctx context.Context, req *ImportRequest) (string, error) {
-	if m.usenet == nil {
-		return "", fmt.Errorf("usenet not configured")
-	}
 	if req == nil || len(req.NZBContent) == 0 {
 		return "", fmt.Errorf("NZB content is empty")
 	}
 	if req.Arr == nil {
 		return "", fmt.Errorf("arr is required")
 	}
+	// The category profile may rule out NNTP ("usenet") or some debrids
+	nntp := m.usenet != nil && req.allowsProvider("usenet")
+	hasDebrids := len(req.orderProviders(m.nzbDebrids())) > 0
+	if !nntp && !hasDebrids {
+		return "", fmt.Errorf("usenet not configured")
+	}
+
+	preferDebrid := hasDebrids && (!nntp || m.prefersDebridUsenet(req))
+	if preferDebrid {
+		m.logger.Info().
+			Str("name", req.Name).
+			Str("category", req.Arr.Name).
+			Msg("Adding new NZB to debrid usenet")
+		id, err := m.addDebridNZB(ctx, req)
+		if err == nil || !nntp {
+			return id, err
+		}
+		m.logger.Warn().Err(err).Str("name", req.Name).Msg("Debrid usenet rejected NZB, falling back to NNTP")
+	}
 
 	m.logger.Info().
 		Str("name", req.Name).
@@ -32,38 +53,21 @@ func (m *Manager) AddNewNZB(ctx context.Context, req *ImportRequest) (string, er
 
 	meta, groups, err := m.usenet.ParseWithID(ctx, req.Id, req.Name, req.NZBContent, req.Arr.Name)
 	if err != nil {
+		if hasDebrids && !preferDebrid {
+			m.logger.Warn().Err(err).Str("name", req.Name).Msg("NNTP parse failed, sending NZB to debrid usenet")
+			return m.addDebridNZB(ctx, req)
+		}
 		return "", fmt.Errorf("usenet parse failed: %w", err)
 	}
 
-	entry := &storage.Entry{
-		InfoHash:         meta.ID,
-		Name:             meta.Name,
-		OriginalFilename: meta.Name,
-		Size:             meta.TotalSize,
-		Protocol:         config.ProtocolNZB,
-		Bytes:            meta.TotalSize,
-		Category:         req.Arr.Name,
-		SavePath:         filepath.Join(req.DownloadFolder, req.Arr.Name),
-		Status:           debridTypes.TorrentStatusDownloading,
-		State:            storage.EntryStateDownloading,
-		Progress:         0,
-		Action:           req.Action,
-		CallbackURL:      req.CallBackUrl,
-		SkipMultiSeason:  req.SkipMultiSeason,
-		CreatedAt:        time.Now(),
-		UpdatedAt:        time.Now(),
-		AddedOn:          time.Now(),
-		Providers:        make(map[string]*storage.ProviderEntry),
-		Files:            make(map[string]*storage.File),
-		Tags:             []string{},
-	}
-
-	entry.ContentPath = entry.DownloadPath()
+	entry := newNZBQueueEntry(req, meta.ID, meta.Name, meta.TotalSize)
 	entry.ActiveProvider = "usenet"
 	_ = entry.AddUsenetProvider(meta)
+	m.applyTagRules(entry, config.TagRuleOnAdd)
 	if err := m.queue.Add(entry); err != nil {
 		return "", fmt.Errorf("failed to add nzb to queue: %w", err)
 	}
+	m.RecordEvent(entry.InfoHash, storage.EntryEventSubmitted, "usenet", fmt.Sprintf("%d files queued for NNTP download", len(meta.Files)))
 
 	req.Status = "started"
 	job := NewJob(JobTypeNZB, req)
@@ -79,6 +83,20 @@ func (m *Manager) AddNewNZB(ctx context.Context, req *ImportRequest) (string, er
 	return meta.ID, nil
 }
 
+// prefersDebridUsenet reports whether an NZB goes to debrid usenet before
+// NNTP: the category profile decides when it prefers "usenet" or an NZB
+// debrid, the usenet prefer_debrid setting otherwise.
+func (m *Manager) prefersDebridUsenet(req *ImportRequest) bool {
+	preferred := req.PreferredDebrid
+	if strings.EqualFold(preferred, "usenet") {
+		return false
+	}
+	if preferred != "" && slices.ContainsFunc(m.nzbDebrids(), func(name string) bool { return strings.EqualFold(name, preferred) }) {
+		return true
+	}
+	return config.Get().Usenet.PreferDebrid
+}
+
 func (m *Manager) processNZBJob(ctx context.Context, job *Job) error {
 	if job == nil || job.Entry == nil {
 		return fmt.Errorf("invalid NZB job")
@@ -96,7 +114,42 @@ func (m *Manager) processNZBJob(ctx context.Context, job *Job) error {
 	if job.Request != nil {
 		job.Request.Status = "started"
 	}
-	return m.processNewNzb(ctx, job.Entry, job.NZBMeta, job.NZBGroups)
+	err := m.processNewNzb(ctx, job.Entry, job.NZBMeta, job.NZBGroups)
+	if err != nil && ctx.Err() == nil && m.HasDebridUsenet() {
+		return m.handNZBToDebrid(ctx, job, err)
+	}
+	return err
+}
+
+func newNZBQueueEntry(req *ImportRequest, id, name string, size int64) *storage.Entry {
+	now := time.Now()
+	entry := &storage.Entry{
+		InfoHash:         id,
+		Name:             name,
+		OriginalFilename: name,
+		Size:             size,
+		Protocol:         config.ProtocolNZB,
+		Bytes:            size,
+		Category:         req.Arr.Name,
+		SavePath:         filepath.Join(req.DownloadFolder, req.Arr.Name),
+		Status:           debridTypes.TorrentStatusDownloading,
+		State:            storage.EntryStateDownloading,
+		Progress:         0,
+		Action:           req.Action,
+		CallbackURL:      req.CallBackUrl,
+		SkipMultiSeason:  req.SkipMultiSeason,
+		FileSelection:    req.FileSelection.Clone(),
+		Priority:         req.Priority,
+		PPParameters:     req.PPParameters,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		AddedOn:          now,
+		Providers:        make(map[string]*storage.ProviderEntry),
+		Files:            make(map[string]*storage.File),
+		Tags:             []string{},
+	}
+	entry.ContentPath = entry.DownloadPath()
+	return entry
 }
 
 func (m *Manager) processNZB(ctx context.Context, entry *storage.Entry, metadata *storage.NZB) error {
@@ -110,6 +163,7 @@ func (m *Manager) processNZB(ctx context.Context, entry *storage.Entry, metadata
 		}
 		entry.Files[file.Name] = tFile
 	}
+	entry.ApplyFileSelection()
 	// Mark as complete
 	if placement := entry.GetActiveProvider(); placement != nil {
 		now := time.Now()
@@ -160,6 +214,34 @@ func (m *Manager) UsenetStats() map[string]any {
 	return m.usenet.Stats()
 }
 
+// PurgeFailedNZB drops what a failed NZB left behind, as SABnzbd does when a
+// failed job is deleted with its files: the stored entry and its placements,
+// or else the parsed NZB. A completed entry is refused, since the library
+// may still link into it.
+func (m *Manager) PurgeFailedNZB(nzoID string) error {
+	if entry, err := m.storage.Get(nzoID); err == nil {
+		if entry.IsComplete && !entry.Bad {
+			return fmt.Errorf("%s completed, its files are kept", entry.Name)
+		}
+		return m.DeleteEntry(nzoID, true, false)
+	}
+	if m.usenet == nil {
+		return nil
+	}
+	if _, err := m.usenet.GetNZBHeader(nzoID); err != nil {
+		return nil
+	}
+	return m.usenet.Delete(nzoID)
+}
+
+// UsenetTraffic returns the per-provider NNTP traffic counters.
+func (m *Manager) UsenetTraffic() []nntp.ProviderTraffic {
+	if m.usenet == nil {
+		return nil
+	}
+	return m.usenet.Traffic()
+}
+
 // SpeedTestRequest represents a speed test request payload
 type SpeedTestRequest struct {
 	Protocol string `json:"protocol"` // "nntp" or "debrid"
@@ -250,15 +332,18 @@ func (m *Manager) syncNZBs(ctx context.Context) error {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
+		// Watched NZBs are only added, whatever the default profile says
+		policy := config.Get().PolicyFor("")
+		policy.DownloadAction = config.DownloadActionNone
+		policy.SkipMultiSeason = false
 		req := NewNZBRequest(
 			pending.Name,
 			m.config.DownloadFolder,
 			pending.Content,
 			m.arr.GetOrCreate(""),
-			config.DownloadActionNone,
+			policy,
 			"",
 			ImportTypeWatch,
-			false,
 		)
 		if _, err := m.AddNewNZB(ctx, req); err != nil {
 			m.logger.Error().Err(err).Str("name", pending.Name).Msg("Failed to queue watched NZB")
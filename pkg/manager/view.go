@@ -0,0 +1,326 @@
+package manager
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// viewFilterTTL is how long a view's set of matching entries is reused before
+// it is rebuilt. Refreshing the entries rebuilds it straight away.
+const viewFilterTTL = time.Minute
+
+// Tree is the read side of the virtual library that mounts and WebDAV serve:
+// the groups at the root, the entries in a group and the files in an entry.
+// *Manager serves the full tree; a View serves a filtered one.
+type Tree interface {
+	RootInfo() *FileInfo
+	GetEntries() []FileInfo
+	GetEntryChildren(group string) (*FileInfo, []FileInfo)
+	GetEntryInfo(name string) (*FileInfo, error)
+	GetTorrentChildren(name string) (*FileInfo, []FileInfo)
+	GetTorrentEntry(name string) (*FileInfo, error)
+	GetTorrentFile(torrentName, fileName string) (*FileInfo, error)
+}
+
+var _ Tree = (*Manager)(nil)
+var _ Tree = (*View)(nil)
+
+// View is the library as one of the configured mount views sees it. Root
+// folders outside its folder list are hidden and the rest may be renamed;
+// entries that don't match its category, protocol and health filters are
+// hidden everywhere in the view. Entry and file names are never changed, so
+// everything read through a view streams exactly like the full tree.
+//
+// FileInfos returned by a view are copies, so a mount's fuse nodes cached on
+// them never leak into another mount.
+type View struct {
+	manager *Manager
+	config  config.MountView
+	root    *FileInfo
+
+	folders map[string]struct{} // Source root folders shown, nil = all
+	shown   map[string]string   // Source folder -> shown name
+	source  map[string]string   // Shown name -> source folder
+
+	categories map[string]struct{}
+	protocols  map[string]struct{}
+
+	mu        sync.Mutex
+	allowed   map[string]struct{} // Entry folders that pass the filters
+	allowedAt time.Time
+}
+
+func newView(m *Manager, cfg config.MountView) *View {
+	v := &View{
+		manager: m,
+		config:  cfg,
+		root: &FileInfo{
+			name:    "",
+			modTime: utils.Now(),
+			isDir:   true,
+		},
+		shown:  make(map[string]string, len(cfg.Rename)),
+		source: make(map[string]string, len(cfg.Rename)),
+	}
+	if len(cfg.Folders) > 0 {
+		v.folders = make(map[string]struct{}, len(cfg.Folders))
+		for _, folder := range cfg.Folders {
+			v.folders[folder] = struct{}{}
+		}
+	}
+	for from, to := range cfg.Rename {
+		v.shown[from] = to
+		v.source[to] = from
+	}
+	if len(cfg.Categories) > 0 {
+		v.categories = make(map[string]struct{}, len(cfg.Categories))
+		for _, category := range cfg.Categories {
+			v.categories[category] = struct{}{}
+		}
+	}
+	if len(cfg.Protocols) > 0 {
+		v.protocols = make(map[string]struct{}, len(cfg.Protocols))
+		for _, protocol := range cfg.Protocols {
+			v.protocols[protocol] = struct{}{}
+		}
+	}
+	return v
+}
+
+func (m *Manager) initViews() {
+	m.views = make(map[string]*View, len(m.config.Mount.Views))
+	for _, cfg := range m.config.Mount.Views {
+		m.views[cfg.Name] = newView(m, cfg)
+	}
+}
+
+// View returns the configured mount view with the given name, or nil
+func (m *Manager) View(name string) *View {
+	return m.views[name]
+}
+
+// Views returns the configured mount views in config order
+func (m *Manager) Views() []*View {
+	views := make([]*View, 0, len(m.config.Mount.Views))
+	for _, cfg := range m.config.Mount.Views {
+		if v := m.views[cfg.Name]; v != nil {
+			views = append(views, v)
+		}
+	}
+	return views
+}
+
+// invalidateViews makes every view rebuild its matching entries on next use
+func (m *Manager) invalidateViews() {
+	for _, v := range m.views {
+		v.mu.Lock()
+		v.allowed = nil
+		v.mu.Unlock()
+	}
+}
+
+func (v *View) Name() string      { return v.config.Name }
+func (v *View) MountPath() string { return v.config.MountPath }
+
+// ShownName returns the name a source root folder has in the view, and false
+// when the view hides it
+func (v *View) ShownName(folder string) (string, bool) {
+	if v.folders != nil {
+		if _, ok := v.folders[folder]; !ok {
+			return "", false
+		}
+	}
+	if name, ok := v.shown[folder]; ok {
+		return name, true
+	}
+	if _, renamed := v.source[folder]; renamed {
+		return "", false // Another folder is shown under this name
+	}
+	return folder, true
+}
+
+// sourceName maps a root name in the view back to the library folder
+func (v *View) sourceName(name string) (string, bool) {
+	folder := name
+	if from, ok := v.source[name]; ok {
+		folder = from
+	}
+	if shown, ok := v.ShownName(folder); !ok || shown != name {
+		return "", false
+	}
+	return folder, true
+}
+
+// filtered reports whether the view hides any entries
+func (v *View) filtered() bool {
+	return v.categories != nil || v.protocols != nil || v.config.Health != config.ViewHealthAny
+}
+
+func (v *View) matches(category, protocol string, bad bool) bool {
+	if v.categories != nil {
+		if _, ok := v.categories[category]; !ok {
+			return false
+		}
+	}
+	if v.protocols != nil {
+		if _, ok := v.protocols[protocol]; !ok {
+			return false
+		}
+	}
+	switch v.config.Health {
+	case config.ViewHealthHealthy:
+		return !bad
+	case config.ViewHealthBad:
+		return bad
+	}
+	return true
+}
+
+// allows reports whether the entry folder passes the view's filters
+func (v *View) allows(name string) bool {
+	if !v.filtered() {
+		return true
+	}
+	_, ok := v.allowedEntries()[name]
+	return ok
+}
+
+// allowedEntries returns the entry folders that pass the filters. The index
+// alone covers protocol and health; categories need the full entries.
+func (v *View) allowedEntries() map[string]struct{} {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.allowed != nil && time.Since(v.allowedAt) < viewFilterTTL {
+		return v.allowed
+	}
+
+	allowed := make(map[string]struct{})
+	var err error
+	if v.categories != nil {
+		err = v.manager.storage.ForEach(func(entry *storage.Entry) error {
+			if v.matches(entry.Category, string(entry.Protocol), entry.Bad) {
+				allowed[entry.GetFolder()] = struct{}{}
+			}
+			return nil
+		})
+	} else {
+		err = v.manager.storage.ForEachMeta(func(meta *storage.EntryMetaInfo) error {
+			if v.matches("", meta.Protocol, meta.Bad) {
+				allowed[meta.Name] = struct{}{}
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		v.manager.logger.Warn().Err(err).Str("view", v.config.Name).Msg("Failed to filter entries for mount view")
+		if v.allowed != nil {
+			return v.allowed
+		}
+	}
+	v.allowed = allowed
+	v.allowedAt = time.Now()
+	return allowed
+}
+
+// copyInfo returns a copy of info that is safe to hand to another mount
+func copyInfo(info *FileInfo) *FileInfo {
+	if info == nil {
+		return nil
+	}
+	c := *info
+	c.sys = nil
+	return &c
+}
+
+func (v *View) RootInfo() *FileInfo {
+	return v.root
+}
+
+// GetEntries returns the view's root folders. Root files such as version.txt
+// only show when the view doesn't pick its folders.
+func (v *View) GetEntries() []FileInfo {
+	entries := v.manager.GetEntries()
+	out := make([]FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			if v.folders == nil {
+				out = append(out, entry)
+			}
+			continue
+		}
+		name, ok := v.ShownName(entry.Name())
+		if !ok {
+			continue
+		}
+		entry.name = name
+		entry.sys = nil
+		out = append(out, entry)
+	}
+	return out
+}
+
+func (v *View) GetEntryChildren(group string) (*FileInfo, []FileInfo) {
+	folder, ok := v.sourceName(group)
+	if !ok {
+		return nil, nil
+	}
+	current, children := v.manager.GetEntryChildren(folder)
+	if current == nil {
+		return nil, nil
+	}
+	current = copyInfo(current)
+	current.name = group
+
+	out := make([]FileInfo, 0, len(children))
+	for _, child := range children {
+		if !v.allows(child.Name()) {
+			continue
+		}
+		child.sys = nil
+		out = append(out, child)
+	}
+	return current, out
+}
+
+func (v *View) GetEntryInfo(name string) (*FileInfo, error) {
+	if !v.allows(name) {
+		return nil, fmt.Errorf("entry %s not found", name)
+	}
+	return v.manager.GetEntryInfo(name)
+}
+
+func (v *View) GetTorrentChildren(name string) (*FileInfo, []FileInfo) {
+	if !v.allows(name) {
+		return nil, nil
+	}
+	current, children := v.manager.GetTorrentChildren(name)
+	if current == nil {
+		return nil, nil
+	}
+	out := slices.Clone(children)
+	for i := range out {
+		out[i].sys = nil
+	}
+	return copyInfo(current), out
+}
+
+func (v *View) GetTorrentEntry(name string) (*FileInfo, error) {
+	current, _ := v.GetTorrentChildren(name)
+	if current == nil {
+		return nil, fmt.Errorf("torrent %s not found", name)
+	}
+	return current, nil
+}
+
+func (v *View) GetTorrentFile(torrentName, fileName string) (*FileInfo, error) {
+	if !v.allows(torrentName) {
+		return nil, fmt.Errorf("torrent %s not found", torrentName)
+	}
+	return v.manager.GetTorrentFile(torrentName, fileName)
+}
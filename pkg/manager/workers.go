@@ -89,6 +89,13 @@ func (m *Manager) addQueueProcessorJob(ctx context.Context) error {
 				m.logger.Debug().Msg("NZB refresh job scheduled for every 5m")
 			}
 		}
+
+		// Keep the server stats counters across restarts
+		if jd, err := utils.ConvertToJobDef("5m"); err != nil {
+			m.logger.Error().Err(err).Msg("Failed to convert usenet traffic save interval to job definition")
+		} else if _, err := m.scheduler.NewJob(jd, gocron.NewTask(m.usenet.SaveTraffic), gocron.WithContext(ctx), gocron.WithName("usenet-traffic-save")); err != nil {
+			m.logger.Error().Err(err).Msg("Failed to create usenet traffic save job")
+		}
 	}
 	return nil
 }
@@ -99,6 +106,7 @@ func (m *Manager) StartWorker(ctx context.Context) error {
 
 	// Call the initial calls
 	m.runInitialCalls(ctx)
+	m.startMediaProbers(ctx)
 
 	if err := m.addQueueProcessorJob(ctx); err != nil {
 		return err
@@ -192,6 +200,21 @@ func (m *Manager) StartWorker(ctx context.Context) error {
 		}
 	}
 
+	// Library reconciliation, if scheduled
+	m.scheduleReconcile(ctx)
+
+	// Retention policies, if scheduled
+	m.scheduleRetention(ctx)
+
+	// Recycle bin purge
+	m.scheduleRecycleBin(ctx)
+
+	// Debrid expiry guardian, if scheduled
+	m.scheduleGuardian(ctx)
+
+	// Entry timeline pruning and arr import tracking
+	m.scheduleEventJobs(ctx)
+
 	// Register the health checker sweep with the scheduler if enabled.
 	if m.repair != nil {
 		if err := m.repair.Start(ctx); err != nil {
@@ -202,5 +225,6 @@ func (m *Manager) StartWorker(ctx context.Context) error {
 	// Start the scheduler
 	m.scheduler.Start()
 	m.cetScheduler.Start()
+	m.schedulerRunning.Store(true)
 	return nil
 }
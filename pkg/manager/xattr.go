@@ -0,0 +1,87 @@
+package manager
+
+import (
+	"strings"
+	"time"
+)
+
+// XattrPrefix namespaces the extended attributes DFS exposes on entries and
+// their files
+const XattrPrefix = "user.decypharr."
+
+// Xattr is one extended attribute
+type Xattr struct {
+	Name  string
+	Value string
+}
+
+// Xattrs returns the extended attributes of an entry directory or one of its
+// files. Groups and other synthetic nodes have none.
+func (m *Manager) Xattrs(info *FileInfo) []Xattr {
+	if info == nil {
+		return nil
+	}
+	folder := info.name
+	if !info.isDir {
+		folder = info.parent
+	}
+	infohash := info.infohash
+	if infohash == "" && folder != "" {
+		infohash = m.entryItemInfoHash(folder)
+	}
+	if infohash == "" {
+		return nil
+	}
+
+	attrs := []Xattr{{Name: XattrPrefix + "infohash", Value: strings.ToLower(infohash)}}
+	if entry, err := m.storage.Get(infohash); err == nil && entry != nil {
+		name := entry.OriginalFilename
+		if name == "" {
+			name = entry.Name
+		}
+		attrs = append(attrs, Xattr{Name: XattrPrefix + "provider", Value: entry.ActiveProvider})
+		if h, err := m.storage.GetEntryHealth(folder); err == nil && h != nil && h.Status != "" {
+			attrs = append(attrs, Xattr{Name: XattrPrefix + "health", Value: string(h.Status)})
+		}
+		attrs = append(attrs, Xattr{Name: XattrPrefix + "category", Value: entry.Category})
+		if !entry.AddedOn.IsZero() {
+			attrs = append(attrs, Xattr{Name: XattrPrefix + "added_on", Value: entry.AddedOn.UTC().Format(time.RFC3339)})
+		}
+		attrs = append(attrs, Xattr{Name: XattrPrefix + "name", Value: name})
+	}
+
+	// Empty values are left out rather than listed
+	out := attrs[:0]
+	for _, a := range attrs {
+		if a.Value != "" {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// Xattr returns one extended attribute, and false when the node doesn't have it
+func (m *Manager) Xattr(info *FileInfo, name string) (string, bool) {
+	if !strings.HasPrefix(name, XattrPrefix) {
+		return "", false
+	}
+	for _, a := range m.Xattrs(info) {
+		if a.Name == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+func (m *Manager) entryItemInfoHash(folder string) string {
+	item, err := m.storage.GetEntryItem(folder)
+	if err != nil || item == nil {
+		return ""
+	}
+	for _, f := range item.Files {
+		if f.InfoHash != "" {
+			return f.InfoHash
+		}
+	}
+	return ""
+}
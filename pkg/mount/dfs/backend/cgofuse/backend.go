@@ -9,6 +9,7 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/sirrobot01/decypharr/internal/logger"
+	"github.com/sirrobot01/decypharr/pkg/manager"
 	"github.com/sirrobot01/decypharr/pkg/mount/dfs/backend"
 	"github.com/sirrobot01/decypharr/pkg/mount/dfs/config"
 	"github.com/sirrobot01/decypharr/pkg/mount/dfs/vfs"
@@ -16,8 +17,8 @@ import (
 )
 
 func init() {
-	backend.Register(backend.Cgo, func(vfs *vfs.Manager, config *config.FuseConfig) (backend.Backend, error) {
-		return NewBackend(vfs, config)
+	backend.Register(backend.Cgo, func(vfs *vfs.Manager, tree manager.Tree, config *config.FuseConfig) (backend.Backend, error) {
+		return NewBackend(vfs, tree, config)
 	})
 }
 
@@ -32,12 +33,12 @@ type Backend struct {
 }
 
 // NewBackend creates a new cgofuse backend
-func NewBackend(vfs *vfs.Manager, config *config.FuseConfig) (backend.Backend, error) {
+func NewBackend(vfs *vfs.Manager, tree manager.Tree, config *config.FuseConfig) (backend.Backend, error) {
 	log := logger.New("cgofuse")
 	return &Backend{
 		config: config,
 		logger: log,
-		root:   NewFS(vfs, config, log),
+		root:   NewFS(vfs, tree, config, log),
 		vfs:    vfs,
 	}, nil
 }
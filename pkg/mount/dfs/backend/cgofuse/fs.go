@@ -24,6 +24,7 @@ type FS struct {
 	fuse.FileSystemBase // Embed base for default implementations
 
 	vfs    *vfs.Manager
+	tree   manager.Tree
 	config *config.FuseConfig
 	logger zerolog.Logger
 
@@ -32,9 +33,10 @@ type FS struct {
 }
 
 // NewFS creates a new cgofuse filesystem
-func NewFS(vfsManager *vfs.Manager, config *config.FuseConfig, logger zerolog.Logger) *FS {
+func NewFS(vfsManager *vfs.Manager, tree manager.Tree, config *config.FuseConfig, logger zerolog.Logger) *FS {
 	return &FS{
 		vfs:     vfsManager,
+		tree:    tree,
 		config:  config,
 		logger:  logger,
 		handles: NewHandleManager(),
@@ -119,7 +121,7 @@ func (f *FS) Readdir(path string, fill func(name string, stat *fuse.Stat_t, ofst
 
 	if path == "/" {
 		// Root directory - list all torrents/entries
-		entries := f.vfs.GetManager().GetEntries()
+		entries := f.tree.GetEntries()
 		for _, entry := range entries {
 			fill(entry.Name(), f.entryStat(&entry), 0)
 		}
@@ -136,10 +138,10 @@ func (f *FS) Readdir(path string, fill func(name string, stat *fuse.Stat_t, ofst
 
 	if len(parts) == 1 {
 		// First level directory (e.g., /__all__, /__bad__, /torrents, /nzbs, or a direct torrent)
-		_, children := f.vfs.GetManager().GetEntryChildren(groupOrTorrent)
+		_, children := f.tree.GetEntryChildren(groupOrTorrent)
 		if children == nil {
 			// Try as a direct torrent directory
-			_, children = f.vfs.GetManager().GetTorrentChildren(groupOrTorrent)
+			_, children = f.tree.GetTorrentChildren(groupOrTorrent)
 		}
 		for _, child := range children {
 			fill(child.Name(), f.entryStat(&child), 0)
@@ -152,7 +154,7 @@ func (f *FS) Readdir(path string, fill func(name string, stat *fuse.Stat_t, ofst
 	torrentName := parts[1]
 
 	// get the torrent's children (files)
-	_, children := f.vfs.GetManager().GetTorrentChildren(torrentName)
+	_, children := f.tree.GetTorrentChildren(torrentName)
 	for _, child := range children {
 		fill(child.Name(), f.entryStat(&child), 0)
 	}
@@ -398,6 +400,33 @@ func (f *FS) Access(path string, mask uint32) int {
 	return 0
 }
 
+// Getxattr returns one of the entry attributes of a torrent directory or file
+func (f *FS) Getxattr(path string, name string) (int, []byte) {
+	info, err := f.getFileInfo(path)
+	if err != nil {
+		return -fuse.ENOENT, nil
+	}
+	value, ok := f.vfs.GetManager().Xattr(info, name)
+	if !ok {
+		return -fuse.ENOATTR, nil
+	}
+	return 0, []byte(value)
+}
+
+// Listxattr lists the entry attributes of a torrent directory or file
+func (f *FS) Listxattr(path string, fill func(name string) bool) int {
+	info, err := f.getFileInfo(path)
+	if err != nil {
+		return -fuse.ENOENT
+	}
+	for _, a := range f.vfs.GetManager().Xattrs(info) {
+		if !fill(a.Name) {
+			return -fuse.ERANGE
+		}
+	}
+	return 0
+}
+
 // getFileInfo resolves a path to FileInfo
 func (f *FS) getFileInfo(path string) (*manager.FileInfo, error) {
 	parts := splitPath(path)
@@ -410,7 +439,7 @@ func (f *FS) getFileInfo(path string) (*manager.FileInfo, error) {
 	// Check if it's a top-level entry (from GetEntries)
 	if len(parts) == 1 {
 		// First check if it's one of the special directories or version.txt
-		entries := f.vfs.GetManager().GetEntries()
+		entries := f.tree.GetEntries()
 		for _, entry := range entries {
 			if entry.Name() == name {
 				// Return a copy to avoid modifying the original
@@ -419,7 +448,7 @@ func (f *FS) getFileInfo(path string) (*manager.FileInfo, error) {
 			}
 		}
 		// Not found in root entries, try as a torrent in one of the groups
-		return f.vfs.GetManager().GetTorrentEntry(name)
+		return f.tree.GetTorrentEntry(name)
 	}
 
 	// Depth 2: could be a torrent inside __all__, or a file inside a torrent
@@ -428,7 +457,7 @@ func (f *FS) getFileInfo(path string) (*manager.FileInfo, error) {
 		entryName := parts[1]
 
 		// Check if first part is a special group (__all__, __bad__, torrents, nzbs)
-		_, children := f.vfs.GetManager().GetEntryChildren(groupOrTorrent)
+		_, children := f.tree.GetEntryChildren(groupOrTorrent)
 		for _, child := range children {
 			if child.Name() == entryName {
 				info := child
@@ -437,14 +466,14 @@ func (f *FS) getFileInfo(path string) (*manager.FileInfo, error) {
 		}
 
 		// Otherwise treat first part as torrent name and second as filename
-		return f.vfs.GetManager().GetTorrentFile(groupOrTorrent, entryName)
+		return f.tree.GetTorrentFile(groupOrTorrent, entryName)
 	}
 
 	// Depth 3+: file within a torrent inside a group
 	// Path: /group/torrent/file
 	torrentName := parts[1]
 	filename := strings.Join(parts[2:], "/")
-	return f.vfs.GetManager().GetTorrentFile(torrentName, filename)
+	return f.tree.GetTorrentFile(torrentName, filename)
 }
 
 // splitPath splits a path into components
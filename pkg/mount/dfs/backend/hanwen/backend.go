@@ -16,6 +16,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/sirrobot01/decypharr/internal/logger"
 	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/manager"
 	"github.com/sirrobot01/decypharr/pkg/mount/dfs/backend"
 	"github.com/sirrobot01/decypharr/pkg/mount/dfs/config"
 	"github.com/sirrobot01/decypharr/pkg/mount/dfs/vfs"
@@ -45,14 +46,14 @@ type Backend struct {
 }
 
 // NewBackend creates a new hanwen backend
-func NewBackend(vfs *vfs.Manager, config *config.FuseConfig) (backend.Backend, error) {
+func NewBackend(vfs *vfs.Manager, tree manager.Tree, config *config.FuseConfig) (backend.Backend, error) {
 	now := utils.Now()
 	log := logger.New("hanwen-backend")
 	// One shared rate-limited logger for the whole mount. Files/Dirs reference
 	// it instead of allocating their own xsync map per inode — dedup keys are
 	// already unique per inode so a shared map gives identical behaviour.
 	rl := logger.NewRateLimitedLogger(logger.WithLogger(log))
-	root := NewDir(vfs, "", LevelRoot, uint64(now.Unix()), config, log, rl)
+	root := NewDir(vfs, tree, "", LevelRoot, uint64(now.Unix()), config, log, rl)
 	return &Backend{
 		config: config,
 		logger: log,
@@ -80,7 +81,7 @@ func (b *Backend) Mount(ctx context.Context) error {
 		FsName:               "decypharr",
 		Debug:                false,
 		Name:                 "decypharr",
-		DisableXAttrs:        true,
+		DisableXAttrs:        false, // Entry metadata is exposed as user.decypharr.* attributes
 		IgnoreSecurityLabels: true,
 		MaxWrite:             1024 * 1024,
 		AllowOther: true,
@@ -27,6 +27,7 @@ const (
 type Dir struct {
 	fs.Inode
 	vfs      *vfs.Manager
+	tree     manager.Tree
 	level    DirLevel
 	name     string
 	config   *config.FuseConfig
@@ -42,9 +43,10 @@ var _ = (fs.NodeUnlinker)((*Dir)(nil))
 var _ = (fs.NodeRmdirer)((*Dir)(nil))
 
 // NewDir creates a new directory
-func NewDir(vfsManager *vfs.Manager, name string, level DirLevel, modTime uint64, config *config.FuseConfig, log zerolog.Logger, rl *logger.RateLimitedLogger) *Dir {
+func NewDir(vfsManager *vfs.Manager, tree manager.Tree, name string, level DirLevel, modTime uint64, config *config.FuseConfig, log zerolog.Logger, rl *logger.RateLimitedLogger) *Dir {
 	return &Dir{
 		vfs:      vfsManager,
+		tree:     tree,
 		name:     name,
 		level:    level,
 		config:   config,
@@ -63,7 +65,7 @@ func (d *Dir) newNode(info *manager.FileInfo) fs.InodeEmbedder {
 
 	var node fs.InodeEmbedder
 	if info.IsDir() {
-		node = NewDir(d.vfs, info.Name(), d.level+1, uint64(info.ModTime().Unix()), d.config, d.logger, d.rlLogger)
+		node = NewDir(d.vfs, d.tree, info.Name(), d.level+1, uint64(info.ModTime().Unix()), d.config, d.logger, d.rlLogger)
 	} else {
 		node = NewFile(d.vfs, d.config, info, d.rlLogger)
 	}
@@ -110,7 +112,7 @@ func (d *Dir) lookupChild(name string) (*manager.FileInfo, syscall.Errno) {
 	case LevelRoot:
 		// Root level: small static list (~6 entries), O(n) is acceptable
 		// These are __all__, __bad__, torrents, nzbs, custom folders, version.txt
-		entries := d.vfs.GetManager().GetEntries()
+		entries := d.tree.GetEntries()
 		for i := range entries {
 			if entries[i].Name() == name {
 				return &entries[i], 0
@@ -120,7 +122,7 @@ func (d *Dir) lookupChild(name string) (*manager.FileInfo, syscall.Errno) {
 
 	case LevelTorrent:
 		// Torrent level: O(1) lookup by name
-		info, err := d.vfs.GetManager().GetEntryInfo(name)
+		info, err := d.tree.GetEntryInfo(name)
 		if err != nil {
 			return nil, syscall.ENOENT
 		}
@@ -128,7 +130,7 @@ func (d *Dir) lookupChild(name string) (*manager.FileInfo, syscall.Errno) {
 
 	case LevelFile:
 		// File level: O(1) lookup specific file in torrent
-		info, err := d.vfs.GetManager().GetTorrentFile(d.name, name)
+		info, err := d.tree.GetTorrentFile(d.name, name)
 		if err != nil {
 			return nil, syscall.ENOENT
 		}
@@ -188,17 +190,17 @@ func (d *Dir) Readdir(ctx context.Context) (fs.DirStream, syscall.Errno) {
 func (d *Dir) listChildren() ([]manager.FileInfo, syscall.Errno) {
 	switch d.level {
 	case LevelRoot:
-		return d.vfs.GetManager().GetEntries(), 0
+		return d.tree.GetEntries(), 0
 
 	case LevelTorrent:
-		_, children := d.vfs.GetManager().GetEntryChildren(d.name)
+		_, children := d.tree.GetEntryChildren(d.name)
 		if children == nil {
 			return nil, 0
 		}
 		return children, 0
 
 	case LevelFile:
-		_, children := d.vfs.GetManager().GetTorrentChildren(d.name)
+		_, children := d.tree.GetTorrentChildren(d.name)
 		if children == nil {
 			return nil, 0
 		}
@@ -244,7 +246,7 @@ func (d *Dir) Unlink(ctx context.Context, name string) syscall.Errno {
 		return syscall.EPERM
 	}
 
-	info, err := d.vfs.GetManager().GetTorrentFile(d.name, name)
+	info, err := d.tree.GetTorrentFile(d.name, name)
 	if err != nil {
 		return syscall.ENOENT
 	}
@@ -263,7 +265,7 @@ func (d *Dir) Rmdir(ctx context.Context, name string) syscall.Errno {
 		return syscall.EPERM
 	}
 
-	info, err := d.vfs.GetManager().GetTorrentEntry(name)
+	info, err := d.tree.GetTorrentEntry(name)
 	if err != nil {
 		return syscall.ENOENT
 	}
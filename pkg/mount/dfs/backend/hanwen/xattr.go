@@ -0,0 +1,81 @@
+//go:build linux || (darwin && amd64)
+
+package hanwen
+
+import (
+	"context"
+	"syscall"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+)
+
+var (
+	_ = (fs.NodeGetxattrer)((*File)(nil))
+	_ = (fs.NodeListxattrer)((*File)(nil))
+	_ = (fs.NodeGetxattrer)((*Dir)(nil))
+	_ = (fs.NodeListxattrer)((*Dir)(nil))
+)
+
+// Getxattr returns one of the entry attributes of the file
+func (f *File) Getxattr(ctx context.Context, attr string, dest []byte) (uint32, syscall.Errno) {
+	return getxattr(f.vfs.GetManager(), f.info, attr, dest)
+}
+
+// Listxattr lists the entry attributes of the file
+func (f *File) Listxattr(ctx context.Context, dest []byte) (uint32, syscall.Errno) {
+	return listxattr(f.vfs.GetManager(), f.info, dest)
+}
+
+// Getxattr returns one of the entry attributes of a torrent directory
+func (d *Dir) Getxattr(ctx context.Context, attr string, dest []byte) (uint32, syscall.Errno) {
+	return getxattr(d.vfs.GetManager(), d.entryInfo(), attr, dest)
+}
+
+// Listxattr lists the entry attributes of a torrent directory
+func (d *Dir) Listxattr(ctx context.Context, dest []byte) (uint32, syscall.Errno) {
+	return listxattr(d.vfs.GetManager(), d.entryInfo(), dest)
+}
+
+// entryInfo returns the entry of a torrent directory. Root and group
+// directories have none.
+func (d *Dir) entryInfo() *manager.FileInfo {
+	if d.level != LevelFile {
+		return nil
+	}
+	info, err := d.vfs.GetManager().GetEntryInfo(d.name)
+	if err != nil {
+		return nil
+	}
+	return info
+}
+
+func getxattr(mgr *manager.Manager, info *manager.FileInfo, attr string, dest []byte) (uint32, syscall.Errno) {
+	if info == nil {
+		return 0, syscall.Errno(fuse.ENOATTR)
+	}
+	value, ok := mgr.Xattr(info, attr)
+	if !ok {
+		return 0, syscall.Errno(fuse.ENOATTR)
+	}
+	if len(dest) < len(value) {
+		return uint32(len(value)), syscall.ERANGE
+	}
+	return uint32(copy(dest, value)), 0
+}
+
+func listxattr(mgr *manager.Manager, info *manager.FileInfo, dest []byte) (uint32, syscall.Errno) {
+	if info == nil {
+		return 0, 0
+	}
+	var names []byte
+	for _, a := range mgr.Xattrs(info) {
+		names = append(names, a.Name...)
+		names = append(names, 0)
+	}
+	if len(dest) < len(names) {
+		return uint32(len(names)), syscall.ERANGE
+	}
+	return uint32(copy(dest, names)), 0
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"runtime"
 
+	"github.com/sirrobot01/decypharr/pkg/manager"
 	"github.com/sirrobot01/decypharr/pkg/mount/dfs/config"
 	"github.com/sirrobot01/decypharr/pkg/mount/dfs/vfs"
 )
@@ -39,7 +40,9 @@ type Backend interface {
 	Type() Type
 }
 
-type Func func(vfs *vfs.Manager, config *config.FuseConfig) (Backend, error)
+// Func creates a backend that mounts tree at config.MountPath, reading file
+// data through vfs. Several backends may share one vfs, one per mount view.
+type Func func(vfs *vfs.Manager, tree manager.Tree, config *config.FuseConfig) (Backend, error)
 
 var registry = make(map[Type]Func)
 
@@ -71,7 +74,7 @@ func GetDefaultBackendType() Type {
 	return Cgo
 }
 
-func New(backendType Type, vfs *vfs.Manager, config *config.FuseConfig) (Backend, error) {
+func New(backendType Type, vfs *vfs.Manager, tree manager.Tree, config *config.FuseConfig) (Backend, error) {
 	constructor, ok := registry[backendType]
 	if !ok {
 		if len(registry) == 0 {
@@ -79,8 +82,8 @@ func New(backendType Type, vfs *vfs.Manager, config *config.FuseConfig) (Backend
 		}
 		// Fallback to any available backend
 		for _, c := range registry {
-			return c(vfs, config)
+			return c(vfs, tree, config)
 		}
 	}
-	return constructor(vfs, config)
+	return constructor(vfs, tree, config)
 }
@@ -17,6 +17,10 @@ type FuseConfig struct {
 	CacheDiskSize        int64 // in bytes
 	CacheCleanupInterval time.Duration
 
+	// CachePinnedSize caps the bytes pinned entries may keep on disk, on top
+	// of CacheDiskSize. 0 = unlimited.
+	CachePinnedSize int64
+
 	// BufferMemory is the RAM budget (bytes) for the DFS streaming-buffer pool,
 	// shared across all open files. 0 disables the cap.
 	BufferMemory int64
@@ -91,6 +95,13 @@ func ParseFuseConfig() *FuseConfig {
 		}
 	}
 
+	if cfg.PinnedCacheSize != "" {
+		size, err := config.ParseSize(cfg.PinnedCacheSize)
+		if err == nil {
+			fuseConfig.CachePinnedSize = size
+		}
+	}
+
 	if cfg.CacheCleanupInterval != "" {
 		interval, err := utils.ParseDuration(cfg.CacheCleanupInterval)
 		if err == nil {
@@ -25,6 +25,14 @@ type Manager struct {
 	defaultBackendType backend.Type
 	config             *fuseconfig.FuseConfig
 	vfs                *vfs.Manager
+
+	// One extra backend per configured mount view, sharing vfs
+	views []*viewMount
+}
+
+type viewMount struct {
+	view    *manager.View
+	backend backend.Backend
 }
 
 // NewManager creates a new  FUSE filesystem manager
@@ -56,7 +64,7 @@ func (m *Manager) Start(ctx context.Context) error {
 	m.vfs = vfsManager
 
 	// Create backend
-	bck, err := backend.New(m.defaultBackendType, vfsManager, m.config)
+	bck, err := backend.New(m.defaultBackendType, vfsManager, m.manager, m.config)
 	if err != nil {
 		return fmt.Errorf("failed to create backend: %w", err)
 	}
@@ -67,6 +75,8 @@ func (m *Manager) Start(ctx context.Context) error {
 		return fmt.Errorf("backend mount failed: %w", err)
 	}
 
+	m.mountViews(ctx)
+
 	m.ready.Store(true)
 	m.logger.Info().
 		Str("mount_path", m.config.MountPath).
@@ -75,6 +85,31 @@ func (m *Manager) Start(ctx context.Context) error {
 	return nil
 }
 
+// mountViews mounts every configured view next to the main mount. A view
+// that fails to mount is logged and skipped; the main mount keeps running.
+func (m *Manager) mountViews(ctx context.Context) {
+	for _, view := range m.manager.Views() {
+		cfg := *m.config
+		cfg.MountPath = view.MountPath()
+		bck, err := backend.New(m.defaultBackendType, m.vfs, view, &cfg)
+		if err == nil {
+			err = bck.Mount(ctx)
+		}
+		if err != nil {
+			m.logger.Error().Err(err).
+				Str("view", view.Name()).
+				Str("mount_path", view.MountPath()).
+				Msg("Failed to mount view")
+			continue
+		}
+		m.views = append(m.views, &viewMount{view: view, backend: bck})
+		m.logger.Info().
+			Str("view", view.Name()).
+			Str("mount_path", view.MountPath()).
+			Msg("Mounted view")
+	}
+}
+
 // Stop stops the  FUSE filesystem manager
 func (m *Manager) Stop() error {
 	if m.backend == nil {
@@ -88,6 +123,15 @@ func (m *Manager) Stop() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Views go first: every backend closes the shared VFS manager on unmount,
+	// so the main mount is the last one left serving reads.
+	for _, vm := range m.views {
+		if err := vm.backend.Unmount(ctx); err != nil {
+			m.logger.Warn().Err(err).Str("view", vm.view.Name()).Msg("View unmount error")
+		}
+	}
+	m.views = nil
+
 	// Unmount using backend, this also ensures the VFS manager is properly closed
 	if err := m.backend.Unmount(ctx); err != nil {
 		m.logger.Warn().Err(err).Msg("Backend unmount error")
@@ -100,12 +144,33 @@ func (m *Manager) IsReady() bool {
 	return m.ready.Load()
 }
 
+// CheckHealth reports whether the FUSE backend is still serving the mount
+func (m *Manager) CheckHealth(_ context.Context) error {
+	if m.backend == nil || !m.ready.Load() {
+		return fmt.Errorf("not mounted")
+	}
+	if !m.backend.IsReady() {
+		return fmt.Errorf("%s backend is not ready", m.backend.Type())
+	}
+	for _, vm := range m.views {
+		if !vm.backend.IsReady() {
+			return fmt.Errorf("view %s is not ready", vm.view.Name())
+		}
+	}
+	return nil
+}
+
 func (m *Manager) Refresh(dirs []string) error {
 	if m.backend == nil {
 		return fmt.Errorf("backend not initialized")
 	}
 	for _, dir := range dirs {
 		m.backend.Refresh(dir)
+		for _, vm := range m.views {
+			if name, ok := vm.view.ShownName(dir); ok {
+				vm.backend.Refresh(name)
+			}
+		}
 	}
 	return nil
 }
@@ -122,6 +187,17 @@ func (m *Manager) Stats() map[string]any {
 	if m.vfs != nil {
 		maps.Copy(stats, m.vfs.GetStats())
 	}
+	if len(m.views) > 0 {
+		views := make([]map[string]any, 0, len(m.views))
+		for _, vm := range m.views {
+			views = append(views, map[string]any{
+				"name":       vm.view.Name(),
+				"mount_path": vm.view.MountPath(),
+				"ready":      vm.backend.IsReady(),
+			})
+		}
+		stats["views"] = views
+	}
 	return stats
 }
 
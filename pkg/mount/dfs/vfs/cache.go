@@ -61,9 +61,14 @@ type Cache struct {
 	cancel context.CancelFunc
 
 	createGroup singleflight.Group
-	threshold   int64
 	cleanupMu   sync.Mutex
 
+	// Pinned entry folders, refreshed by the pin loop. Their files are
+	// skipped by eviction and purge and accounted apart from totalSize.
+	pinnedFolders atomic.Pointer[map[string]struct{}]
+	pinnedSize    atomic.Int64
+	pinnedItems   atomic.Int64
+
 	// Stats counters
 	cacheHits       atomic.Int64
 	cacheMisses     atomic.Int64
@@ -85,11 +90,14 @@ type candidateEntry struct {
 	cachedSize int64 // Actual bytes on disk (from ranges)
 	opens      int32
 	inMap      bool // Whether this item is loaded in the cache map
+	pinned     bool // Pinned items are never evicted or purged
 }
 
 type diskScanResult struct {
 	candidates            []candidateEntry
-	totalSize             int64
+	totalSize             int64 // excludes pinned items
+	pinnedSize            int64
+	pinnedItems           int
 	emptyDirsRemoved      int
 	orphanMetadataRemoved int
 	errors                int
@@ -114,6 +122,7 @@ type purgeRunSummary struct {
 	forcedClosed     int
 	removedDiskItems int
 	skippedBusyItems int
+	skippedPinned    int
 	sizeBefore       int64
 	sizeAfter        int64
 	freedBytes       int64
@@ -130,13 +139,6 @@ func NewCache(ctx context.Context, mgr *manager.Manager, config *config.FuseConf
 	ctx, cancel := context.WithCancel(ctx)
 
 	maxSize := config.CacheDiskSize
-	threshold := int64(0)
-	if maxSize > 0 {
-		threshold = int64(float64(maxSize) * cacheEvictThreshold)
-		if threshold <= 0 {
-			threshold = maxSize
-		}
-	}
 	// The DFS streaming-buffer pool: its own RAM budget plus a disk limit equal
 	// to the cache size, so a single huge open stream stays bounded by punching
 	// holes behind the read head once over the limit. Keep a back-window of
@@ -151,20 +153,24 @@ func NewCache(ctx context.Context, mgr *manager.Manager, config *config.FuseConf
 		MemoryBudget: config.BufferMemory,
 		DiskLimit:    maxSize,
 		BackWindow:   backWindow,
+		Governor:     buffer.DefaultGovernor(),
 	})
 
 	c := &Cache{
-		config:    config,
-		logger:    logger.New("dfs"),
-		items:     xsync.NewMap[string, *CacheItem](),
-		manager:   mgr,
-		ctx:       ctx,
-		cancel:    cancel,
-		threshold: threshold,
-		pool:      pool,
-	}
+		config:  config,
+		logger:  logger.New("dfs"),
+		items:   xsync.NewMap[string, *CacheItem](),
+		manager: mgr,
+		ctx:     ctx,
+		cancel:  cancel,
+		pool:    pool,
+	}
+	// Resolve pins before the first eviction pass so pinned files that are
+	// already cached survive startup
+	pinned := c.refreshPins()
 	go c.evictLoop()
 	go c.speedSampleLoop()
+	go c.pinLoop(pinned)
 	return c, nil
 }
 
@@ -226,6 +232,7 @@ func (c *Cache) scanDiskCandidates() diskScanResult {
 
 		entryName := topEntry.Name()
 		entryDir := filepath.Join(c.config.CacheDir, entryName)
+		pinned := c.isPinned(entryName)
 
 		subEntries, err := os.ReadDir(entryDir)
 		if err != nil {
@@ -321,16 +328,39 @@ func (c *Cache) scanDiskCandidates() diskScanResult {
 				cachedSize: cachedSize,
 				opens:      opens,
 				inMap:      inMap,
+				pinned:     pinned,
 			})
-			result.totalSize += cachedSize
+			if pinned {
+				result.pinnedSize += cachedSize
+				result.pinnedItems++
+			} else {
+				result.totalSize += cachedSize
+			}
 		}
 	}
 
 	return result
 }
 
+// evictThreshold is the size the janitor evicts down to. It follows the
+// pool's disk limit rather than CacheDiskSize so a governed disk budget
+// shrinks the cache too.
+func (c *Cache) evictThreshold() int64 {
+	if c.pool == nil {
+		return 0
+	}
+	limit := c.pool.DiskLimit()
+	if limit <= 0 {
+		return 0
+	}
+	if threshold := int64(float64(limit) * cacheEvictThreshold); threshold > 0 {
+		return threshold
+	}
+	return limit
+}
+
 func (c *Cache) evictCandidates(now time.Time, candidates []candidateEntry, totalSize int64, thresholdOverride int64) (int64, int, int, map[string]struct{}) {
-	threshold := c.threshold
+	threshold := c.evictThreshold()
 	if thresholdOverride > 0 {
 		threshold = thresholdOverride
 	}
@@ -345,8 +375,9 @@ func (c *Cache) evictCandidates(now time.Time, candidates []candidateEntry, tota
 		if _, skip := removed[candidate.key]; skip {
 			return false
 		}
-		// Never remove items that are in the map or have open handles
-		if candidate.inMap || candidate.opens > 0 {
+		// Never remove pinned items, or items that are in the map or have
+		// open handles
+		if candidate.pinned || candidate.inMap || candidate.opens > 0 {
 			return false
 		}
 		hadError := false
@@ -412,6 +443,9 @@ func (c *Cache) purgeCandidates(candidates []candidateEntry, totalSize int64) (i
 	skippedBusy := 0
 
 	for _, candidate := range candidates {
+		if candidate.pinned {
+			continue
+		}
 		if candidate.inMap || candidate.opens > 0 {
 			skippedBusy++
 			continue
@@ -449,6 +483,11 @@ func (c *Cache) purgeCandidates(candidates []candidateEntry, totalSize int64) (i
 	return totalSize, len(removed), removalErrors, skippedBusy, removed
 }
 
+func (c *Cache) storePinnedStats(scan diskScanResult) {
+	c.pinnedSize.Store(scan.pinnedSize)
+	c.pinnedItems.Store(int64(scan.pinnedItems))
+}
+
 func (c *Cache) storeDiskStats(candidates []candidateEntry, removed map[string]struct{}) {
 	count := int64(0)
 
@@ -526,6 +565,9 @@ func (c *Cache) newItem(key, entryName, filename string, fileSize int64) (*Cache
 				item.onBufferEvict(off, length)
 			}
 		},
+		// Pinned files stay whole: the pool's disk backstop skips them and
+		// their bytes count toward the pinned total instead of the limit.
+		Pinned: c.isPinned(entryName),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create buffer: %w", err)
@@ -543,14 +585,15 @@ func (c *Cache) newItem(key, entryName, filename string, fileSize int64) (*Cache
 	}
 
 	item = &CacheItem{
-		cache:    c,
-		key:      key,
-		entry:    entry,
-		filename: filename,
-		buf:      buf,
-		metaPath: metaPath,
-		info:     info,
-		logger:   log.Rate(buildCacheKey(entryName, filename)),
+		cache:     c,
+		key:       key,
+		entry:     entry,
+		entryName: entryName,
+		filename:  filename,
+		buf:       buf,
+		metaPath:  metaPath,
+		info:      info,
+		logger:    log.Rate(buildCacheKey(entryName, filename)),
 	}
 
 	item.downloaders = NewDownloaders(c.ctx, c.manager, item, c.config)
@@ -682,10 +725,11 @@ func (c *Cache) logPurgeSummary(summary purgeRunSummary) {
 		return
 	}
 	c.logger.Info().Msgf(
-		"DFS cache purge: %s. Scanned %d cache item(s), skipped %d busy, force-closed %d idle item(s), removed %d disk item(s), freed %s. Result: %s.",
+		"DFS cache purge: %s. Scanned %d cache item(s), skipped %d busy and %d pinned, force-closed %d idle item(s), removed %d disk item(s), freed %s. Result: %s.",
 		cacheUsageText(summary.sizeAfter, c.config.CacheDiskSize),
 		len(summary.scan.candidates),
 		summary.skippedBusyItems,
+		summary.skippedPinned,
 		summary.forcedClosed,
 		summary.removedDiskItems,
 		utils.FormatSize(summary.freedBytes),
@@ -736,8 +780,9 @@ func (c *Cache) evict() cleanupRunSummary {
 	// WinFsp clients tend to speculatively open files. If we're already over
 	// budget, close zero-open cache items immediately so they become evictable
 	// on the same pass instead of waiting for the idle timeout.
+	threshold := c.evictThreshold()
 	forcedClosedItems := 0
-	if runtime.GOOS == "windows" && c.threshold > 0 && totalSize > c.threshold {
+	if runtime.GOOS == "windows" && threshold > 0 && totalSize > threshold {
 		forcedClosedItems = c.cleanupItems(now, true)
 		if forcedClosedItems > 0 {
 			rescan := c.scanDiskCandidates()
@@ -754,7 +799,7 @@ func (c *Cache) evict() cleanupRunSummary {
 	removedKeys := map[string]struct{}{}
 
 	// If cache expiry is disabled and we're under threshold, skip disk scan.
-	if c.config.CacheExpiry <= 0 && (c.threshold <= 0 || totalSize <= c.threshold) {
+	if c.config.CacheExpiry <= 0 && (threshold <= 0 || totalSize <= threshold) {
 		evictionSkipped = true
 	} else {
 		var removalErrors int
@@ -763,6 +808,7 @@ func (c *Cache) evict() cleanupRunSummary {
 	}
 
 	c.totalSize.Store(totalSize)
+	c.storePinnedStats(scan)
 	c.storeDiskStats(scan.candidates, removedKeys)
 
 	summary := c.finalizeCleanupSummary(cleanupRunSummary{
@@ -799,6 +845,7 @@ func (c *Cache) PurgeCache() map[string]any {
 	scan.errors += removalErrors
 
 	c.totalSize.Store(totalSize)
+	c.storePinnedStats(scan)
 	c.storeDiskStats(scan.candidates, removedKeys)
 
 	freedBytes := max(sizeBefore-totalSize, 0)
@@ -814,6 +861,7 @@ func (c *Cache) PurgeCache() map[string]any {
 		forcedClosed:     forcedClosed,
 		removedDiskItems: removedCount,
 		skippedBusyItems: skippedBusy,
+		skippedPinned:    scan.pinnedItems,
 		sizeBefore:       sizeBefore,
 		sizeAfter:        totalSize,
 		freedBytes:       freedBytes,
@@ -823,17 +871,18 @@ func (c *Cache) PurgeCache() map[string]any {
 	c.logPurgeSummary(summary)
 
 	return map[string]any{
-		"purge_status":              summary.status,
-		"purge_result":              summary.result,
-		"purge_warning_count":       int64(summary.scan.errors),
-		"purge_freed_bytes":         summary.freedBytes,
-		"purge_removed_items":       int64(summary.removedDiskItems),
-		"purge_skipped_busy_items":  int64(summary.skippedBusyItems),
-		"purge_force_closed_items":  int64(summary.forcedClosed),
-		"purge_cache_size_before":   summary.sizeBefore,
-		"purge_cache_size_after":    summary.sizeAfter,
-		"purge_empty_dirs_removed":  int64(summary.scan.emptyDirsRemoved),
-		"purge_orphan_meta_removed": int64(summary.scan.orphanMetadataRemoved),
+		"purge_status":               summary.status,
+		"purge_result":               summary.result,
+		"purge_warning_count":        int64(summary.scan.errors),
+		"purge_freed_bytes":          summary.freedBytes,
+		"purge_removed_items":        int64(summary.removedDiskItems),
+		"purge_skipped_busy_items":   int64(summary.skippedBusyItems),
+		"purge_skipped_pinned_items": int64(summary.skippedPinned),
+		"purge_force_closed_items":   int64(summary.forcedClosed),
+		"purge_cache_size_before":    summary.sizeBefore,
+		"purge_cache_size_after":     summary.sizeAfter,
+		"purge_empty_dirs_removed":   int64(summary.scan.emptyDirsRemoved),
+		"purge_orphan_meta_removed":  int64(summary.scan.orphanMetadataRemoved),
 	}
 }
 
@@ -940,6 +989,9 @@ func (c *Cache) GetStats() map[string]any {
 		"total_downloaded":  c.totalDownloaded.Load(),
 		"download_speed":    c.downloadSpeed.Load(),
 		"circuit_breakers":  c.circuitBreakers.Load(),
+		"pinned_size":       c.pinnedSize.Load(),
+		"pinned_max_size":   c.config.CachePinnedSize,
+		"pinned_item_count": c.pinnedItems.Load(),
 	}
 
 	return stats
@@ -950,10 +1002,11 @@ func (c *Cache) GetStats() map[string]any {
 // cache — so this struct only carries the per-item *policy* state
 // (downloaders coordinator, pin/refcounts, metadata persistence).
 type CacheItem struct {
-	cache    *Cache
-	key      string
-	entry    *storage.Entry
-	filename string
+	cache     *Cache
+	key       string
+	entry     *storage.Entry
+	entryName string
+	filename  string
 
 	buf      *buffer.Buffer
 	metaPath string
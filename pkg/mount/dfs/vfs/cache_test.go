@@ -353,3 +353,70 @@ func TestCleanupItems_ForceZeroOpenClosesRecentItems(t *testing.T) {
 		t.Fatalf("expected cache buffer to be closed after forced cleanup, got %v", err)
 	}
 }
+
+func TestScanDiskCandidates_AccountsPinnedSeparately(t *testing.T) {
+	cacheDir := t.TempDir()
+	for _, name := range []string{"pinned", "plain"} {
+		entryDir := filepath.Join(cacheDir, name)
+		if err := os.MkdirAll(entryDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(entryDir, "video.mkv"), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(entryDir, "video.mkv.json"), []byte(`{"size":1024,"ranges":[{"Pos":0,"Size":40}]}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := newTestCache(cacheDir)
+	c.pinnedFolders.Store(&map[string]struct{}{"pinned": {}})
+	scan := c.scanDiskCandidates()
+
+	if scan.totalSize != 40 {
+		t.Fatalf("expected unpinned total size 40, got %d", scan.totalSize)
+	}
+	if scan.pinnedSize != 40 || scan.pinnedItems != 1 {
+		t.Fatalf("expected 1 pinned item of 40 bytes, got %d items of %d bytes", scan.pinnedItems, scan.pinnedSize)
+	}
+}
+
+func TestEvictCandidates_SkipsPinned(t *testing.T) {
+	cacheDir := t.TempDir()
+	entryDir := filepath.Join(cacheDir, "entry")
+	if err := os.MkdirAll(entryDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	dataPath := filepath.Join(entryDir, "a.mkv")
+	metaPath := dataPath + ".json"
+	for _, path := range []string{dataPath, metaPath} {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := newTestCache(cacheDir)
+	now := time.Now()
+	candidates := []candidateEntry{{
+		key:        "entry/a.mkv",
+		path:       entryDir,
+		dataPath:   dataPath,
+		metaPath:   metaPath,
+		atime:      now.Add(-48 * time.Hour),
+		mtime:      now.Add(-48 * time.Hour),
+		cachedSize: 1,
+		pinned:     true,
+	}}
+
+	_, removed, _, _ := c.evictCandidates(now, candidates, 1, 1)
+	if removed != 0 {
+		t.Fatalf("expected pinned candidate to be kept, %d removed", removed)
+	}
+	totalSize, removed, _, _, _ := c.purgeCandidates(candidates, 0)
+	if removed != 0 || totalSize != 0 {
+		t.Fatalf("expected purge to keep pinned candidate, removed=%d totalSize=%d", removed, totalSize)
+	}
+	if _, err := os.Stat(dataPath); err != nil {
+		t.Fatalf("pinned data should remain, stat err=%v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package vfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/mount/dfs/vfs/ranges"
+)
+
+const (
+	// pinSyncInterval is how often pinned entries are re-resolved and any
+	// missing bytes fetched. Pin changes made through the API trigger a pass
+	// straight away.
+	pinSyncInterval = 30 * time.Minute
+
+	// pinFillStep is how much of a pinned file is requested from the
+	// downloaders at a time
+	pinFillStep = 32 << 20
+)
+
+// isPinned reports whether an entry folder was pinned at the last refresh
+func (c *Cache) isPinned(entryName string) bool {
+	folders := c.pinnedFolders.Load()
+	if folders == nil {
+		return false
+	}
+	_, ok := (*folders)[entryName]
+	return ok
+}
+
+// refreshPins resolves the pins to entry folders and pins or unpins the
+// buffers of loaded items to match. It returns the pinned folders.
+func (c *Cache) refreshPins() []string {
+	set, err := c.manager.CachePinSet()
+	if err != nil {
+		c.logger.Warn().Err(err).Msg("Failed to load cache pins")
+		return nil
+	}
+	folders := set.Folders()
+	pinned := make(map[string]struct{}, len(folders))
+	for _, folder := range folders {
+		pinned[folder] = struct{}{}
+	}
+	c.pinnedFolders.Store(&pinned)
+
+	c.items.Range(func(_ string, item *CacheItem) bool {
+		if item.buf != nil {
+			item.buf.SetPinned(c.isPinned(item.entryName))
+		}
+		return true
+	})
+	return folders
+}
+
+// pinLoop keeps pinned files fully cached
+func (c *Cache) pinLoop(folders []string) {
+	ticker := time.NewTicker(pinSyncInterval)
+	defer ticker.Stop()
+
+	c.fillPins(folders)
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.manager.CachePinsChanged():
+		case <-c.ctx.Done():
+			return
+		}
+		c.fillPins(c.refreshPins())
+	}
+}
+
+// fillPins downloads whatever is missing of the pinned folders' files, one
+// file at a time. The pass stops at the first file that would take the
+// pinned total past CachePinnedSize.
+func (c *Cache) fillPins(folders []string) {
+	budget := c.config.CachePinnedSize
+	for _, folder := range folders {
+		entryItem, err := c.manager.GetEntryItem(folder)
+		if err != nil || entryItem == nil {
+			continue
+		}
+		names := make([]string, 0, len(entryItem.Files))
+		for name, f := range entryItem.Files {
+			if f.Active() {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if c.ctx.Err() != nil {
+				return
+			}
+			size := entryItem.Files[name].Size
+			if size <= 0 {
+				continue
+			}
+			if err := c.fillPinned(folder, name, size, budget); err != nil {
+				if c.ctx.Err() != nil {
+					return
+				}
+				if errors.Is(err, errPinnedBudget) {
+					c.logger.Warn().Err(err).Str("entry", folder).Str("filename", name).Msg("Stopped caching pinned files")
+					return
+				}
+				c.logger.Warn().Err(err).Str("entry", folder).Str("filename", name).Msg("Failed to cache pinned file")
+			}
+		}
+	}
+}
+
+var errPinnedBudget = errors.New("pinned cache size reached")
+
+func (c *Cache) fillPinned(entryName, filename string, size, budget int64) error {
+	if !c.isPinned(entryName) {
+		return nil // Unpinned while the pass was running
+	}
+	item, err := c.GetItem(entryName, filename, size)
+	if err != nil {
+		return err
+	}
+	if !item.Open() {
+		return nil // Claimed by the janitor; the next pass gets a fresh item
+	}
+	defer item.Release()
+
+	missing := size - item.cachedSize()
+	if missing <= 0 {
+		return nil
+	}
+	if budget > 0 && c.pinnedSize.Load()+missing > budget {
+		return fmt.Errorf("%w: %s of %s used, %s more needed", errPinnedBudget,
+			utils.FormatSize(c.pinnedSize.Load()), utils.FormatSize(budget), utils.FormatSize(missing))
+	}
+	if item.buf != nil {
+		item.buf.SetPinned(true)
+	}
+
+	before := item.cachedSize()
+	err = item.fill(c.ctx)
+	c.pinnedSize.Add(item.cachedSize() - before)
+	if err == nil {
+		c.logger.Debug().Str("entry", entryName).Str("filename", filename).Msg("Pinned file fully cached")
+	}
+	return err
+}
+
+// cachedSize returns how many of the item's bytes are on disk
+func (item *CacheItem) cachedSize() int64 {
+	item.metaMu.RLock()
+	defer item.metaMu.RUnlock()
+	return item.info.Rs.Size()
+}
+
+// fill downloads every missing range of the item through its downloaders
+func (item *CacheItem) fill(ctx context.Context) error {
+	size := item.info.Size
+	for off := int64(0); off < size; off += pinFillStep {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r := ranges.Range{Pos: off, Size: min(pinFillStep, size-off)}
+		if item.HasRange(r) {
+			continue
+		}
+		item.dlMu.Lock()
+		dls := item.downloaders
+		item.dlMu.Unlock()
+		if dls == nil {
+			return errors.New("downloaders closed")
+		}
+		if err := dls.DownloadWithRetry(ctx, r, false); err != nil {
+			return fmt.Errorf("download failed at %d: %w", off, err)
+		}
+	}
+	return nil
+}
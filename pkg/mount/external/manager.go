@@ -57,6 +57,11 @@ func (m *Manager) IsReady() bool {
 	return true
 }
 
+// CheckHealth checks that the external rclone RC server answers
+func (m *Manager) CheckHealth(ctx context.Context) error {
+	return m.client.Ping(ctx)
+}
+
 func (m *Manager) Type() string {
 	return "external"
 }
@@ -15,10 +15,10 @@ import (
 )
 
 // mountWithRetry attempts to mount with retry logic using avast/retry-go
-func (m *Manager) mountWithRetry(ctx context.Context, maxRetries int) error {
+func (m *Manager) mountWithRetry(ctx context.Context, t *mountTarget, maxRetries int) error {
 	return retry.Do(
 		func() error {
-			return m.performMount(ctx)
+			return m.performMount(ctx, t)
 		},
 		retry.Attempts(uint(maxRetries)+1),
 		retry.Delay(config.DefaultRetryDelay),
@@ -31,16 +31,16 @@ func (m *Manager) mountWithRetry(ctx context.Context, maxRetries int) error {
 }
 
 // performMount performs a single mount attempt
-func (m *Manager) performMount(ctx context.Context) error {
+func (m *Manager) performMount(ctx context.Context, t *mountTarget) error {
 	cfg := config.Get().Mount
 
 	// Create mount directory if not on windows
 	if runtime.GOOS != "windows" {
-		_ = os.MkdirAll(cfg.MountPath, 0755)
+		_ = os.MkdirAll(t.path, 0755)
 	}
 
 	// Check if already mounted
-	mountInfo := m.getMountInfo()
+	mountInfo := t.info.Load()
 
 	if mountInfo != nil && mountInfo.Mounted {
 		m.logger.Info().Msg("Already mounted")
@@ -49,28 +49,28 @@ func (m *Manager) performMount(ctx context.Context) error {
 
 	// Clean up any stale mount first
 	if mountInfo != nil && !mountInfo.Mounted {
-		err := m.forceUnmount(ctx)
+		err := m.forceUnmount(ctx, t.path)
 		if err != nil {
 			return err
 		}
 	}
 
 	// Create rclone config for this provider
-	if err := m.createConfig(); err != nil {
+	if err := m.createConfig(t); err != nil {
 		return fmt.Errorf("failed to create rclone config: %w", err)
 	}
 
 	// Prepare mount arguments
 	mountArgs := map[string]any{
-		"fs":         FSName,
-		"mountPoint": cfg.MountPath,
+		"fs":         t.fs(),
+		"mountPoint": t.path,
 	}
 	mountOpt := map[string]any{
 		"AllowNonEmpty": true,
 		"AllowOther":    true,
 		"DebugFUSE":     false,
-		"DeviceName":    "decypharr",
-		"VolumeName":    "decypharr",
+		"DeviceName":    t.configName,
+		"VolumeName":    t.configName,
 	}
 
 	if cfg.Rclone.AsyncRead != nil {
@@ -172,27 +172,30 @@ func (m *Manager) performMount(ctx context.Context) error {
 	mountArgs["mountOpt"] = mountOpt
 
 	if err := m.client.Mount(ctx, mountArgs); err != nil {
-		_ = m.forceUnmount(ctx)
-		return fmt.Errorf("failed to mount %s via RC: %w", cfg.MountPath, err)
+		_ = m.forceUnmount(ctx, t.path)
+		return fmt.Errorf("failed to mount %s via RC: %w", t.path, err)
 	}
 
 	// Store mount info
 	mntInfo := &MountInfo{
-		LocalPath:  cfg.MountPath,
-		WebDAVURL:  m.webdavURL,
+		LocalPath:  t.path,
+		WebDAVURL:  t.url,
 		Mounted:    true,
 		MountedAt:  time.Now().Format(time.RFC3339),
-		ConfigName: ConfigName,
+		ConfigName: t.configName,
+	}
+	if t.view != nil {
+		mntInfo.View = t.view.Name()
 	}
 
-	m.info.Store(mntInfo)
+	t.info.Store(mntInfo)
 
 	return nil
 }
 
 // unmount is the internal unmount function
-func (m *Manager) unmount(ctx context.Context) {
-	mountInfo := m.getMountInfo()
+func (m *Manager) unmount(ctx context.Context, t *mountTarget) {
+	mountInfo := t.info.Load()
 
 	if mountInfo == nil || !mountInfo.Mounted {
 		m.logger.Info().Msg("Mount not found or already unmounted")
@@ -208,7 +211,7 @@ func (m *Manager) unmount(ctx context.Context) {
 	// If RC unmount fails or server is not ready, try force unmount
 	if err != nil {
 		m.logger.Warn().Err(err).Msg("RC unmount failed, trying force unmount")
-		if err := m.forceUnmount(ctx); err != nil {
+		if err := m.forceUnmount(ctx, t.path); err != nil {
 			m.logger.Error().Err(err).Msg("Force unmount failed")
 			// Don't return error here, update the state anyway
 		}
@@ -223,13 +226,13 @@ func (m *Manager) unmount(ctx context.Context) {
 	m.logger.Info().Msg("Unmount completed")
 }
 
-// createConfig creates an rclone config entry for the provider
-func (m *Manager) createConfig() error {
+// createConfig creates an rclone config entry for the mount's remote
+func (m *Manager) createConfig(t *mountTarget) error {
 	args := map[string]any{
-		"name": ConfigName,
+		"name": t.configName,
 		"type": "webdav",
 		"parameters": map[string]any{
-			"url":             m.webdavURL,
+			"url":             t.url,
 			"vendor":          "other",
 			"pacer_min_sleep": "0",
 		},
@@ -241,8 +244,7 @@ func (m *Manager) createConfig() error {
 }
 
 // forceUnmount attempts to force unmount a path using system commands
-func (m *Manager) forceUnmount(ctx context.Context) error {
-	mountPath := config.Get().Mount.MountPath
+func (m *Manager) forceUnmount(ctx context.Context, mountPath string) error {
 	methods := [][]string{
 		{"umount", mountPath},
 		{"umount", "-l", mountPath}, // lazy unmount
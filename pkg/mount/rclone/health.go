@@ -17,7 +17,7 @@ func (m *Manager) RecoverMount(ctx context.Context) error {
 	m.logger.Warn().Msg("Attempting to recover mount")
 
 	// First try to unmount cleanly
-	m.unmount(ctx)
+	m.unmount(ctx, m.main)
 
 	// Wait a moment
 	time.Sleep(1 * time.Second)
@@ -59,7 +59,7 @@ func (m *Manager) performMountHealthCheck() {
 		}
 		mountInfo.Error = "Health check failed"
 		mountInfo.Mounted = false
-		m.info.Store(mountInfo)
+		m.main.info.Store(mountInfo)
 
 		// Attempt recovery
 		go func() {
@@ -35,19 +35,40 @@ type Manager struct {
 	cancel        context.CancelFunc
 	serverReady   chan struct{}
 	serverStarted atomic.Bool
-	info          atomic.Pointer[MountInfo]
+	main          *mountTarget
+	views         []*mountTarget
 	manager       *manager.Manager
 	webdavURL     string
 
 	client *rclone.Client
 }
 
+// mountTarget is one mount served by the RC server: the full library, or a
+// mount view through its own remote on the view's WebDAV tree
+type mountTarget struct {
+	view       *manager.View // nil for the main mount
+	configName string
+	url        string
+	path       string
+	info       atomic.Pointer[MountInfo]
+}
+
+func (t *mountTarget) fs() string {
+	return t.configName + ":"
+}
+
+func (t *mountTarget) isMounted() bool {
+	info := t.info.Load()
+	return info != nil && info.Mounted
+}
+
 type MountInfo struct {
 	LocalPath  string `json:"local_path"`
 	WebDAVURL  string `json:"webdav_url"`
 	Mounted    bool   `json:"mounted"`
 	MountedAt  string `json:"mounted_at,omitempty"`
 	ConfigName string `json:"config_name"`
+	View       string `json:"view,omitempty"`
 	Error      string `json:"error,omitempty"`
 }
 
@@ -107,6 +128,19 @@ func NewManager(manager *manager.Manager) *Manager {
 		serverReady: make(chan struct{}),
 		webdavURL:   webdavUrl,
 		manager:     manager,
+		main: &mountTarget{
+			configName: ConfigName,
+			url:        webdavUrl,
+			path:       cfg.MountPath,
+		},
+	}
+	for _, view := range manager.Views() {
+		m.views = append(m.views, &mountTarget{
+			view:       view,
+			configName: ConfigName + "-" + view.Name(),
+			url:        webdavUrl + "__views__/" + url.PathEscape(view.Name()) + "/",
+			path:       view.MountPath(),
+		})
 	}
 	return m
 }
@@ -251,12 +285,11 @@ func (m *Manager) Stop() error {
 }
 
 func (m *Manager) getMountInfo() *MountInfo {
-	return m.info.Load()
+	return m.main.info.Load()
 }
 
 func (m *Manager) IsMounted() bool {
-	info := m.getMountInfo()
-	return info != nil && info.Mounted
+	return m.main.isMounted()
 }
 
 // Start creates the mount using rclone RC
@@ -272,15 +305,36 @@ func (m *Manager) startMount(ctx context.Context) error {
 		return fmt.Errorf("rclone RC server is not reachable: %w", err)
 	}
 
-	if err := m.mountWithRetry(ctx, 3); err != nil {
+	if err := m.mountWithRetry(ctx, m.main, 3); err != nil {
 		m.logger.Error().Err(err).Msg("Mount operation failed")
 		return err
 	}
+	m.mountViews(ctx)
 	go m.MonitorMounts(ctx)
 	return nil
 }
 
+// mountViews mounts every configured view. A view that fails to mount is
+// logged and skipped; the main mount keeps running.
+func (m *Manager) mountViews(ctx context.Context) {
+	for _, t := range m.views {
+		if t.isMounted() {
+			continue
+		}
+		if err := m.mountWithRetry(ctx, t, 3); err != nil {
+			m.logger.Error().Err(err).Str("view", t.view.Name()).Msgf("Failed to mount view at %s", t.path)
+			continue
+		}
+		m.logger.Info().Str("view", t.view.Name()).Msgf("Mounted view at %s", t.path)
+	}
+}
+
 func (m *Manager) stopMount() {
+	for _, t := range m.views {
+		if t.isMounted() {
+			m.unmount(m.ctx, t)
+		}
+	}
 	if !m.IsMounted() {
 		m.logger.Info().Msgf("Mount is not mounted, skipping unmount")
 		return
@@ -288,7 +342,7 @@ func (m *Manager) stopMount() {
 
 	m.logger.Info().Msg("Unmounting via RC")
 
-	m.unmount(m.ctx)
+	m.unmount(m.ctx, m.main)
 	m.logger.Info().Msgf("Successfully unmounted %s", m.getMountInfo().LocalPath)
 }
 
@@ -302,6 +356,29 @@ func (m *Manager) IsReady() bool {
 	}
 }
 
+// CheckHealth lists the root of the mounted remote through the RC server
+func (m *Manager) CheckHealth(ctx context.Context) error {
+	if !m.IsReady() {
+		return errors.New("rclone RC server is not ready")
+	}
+	mountInfo := m.getMountInfo()
+	if mountInfo == nil || !mountInfo.Mounted {
+		if mountInfo != nil && mountInfo.Error != "" {
+			return fmt.Errorf("not mounted: %s", mountInfo.Error)
+		}
+		return errors.New("not mounted")
+	}
+	if err := m.client.CheckMountHealth(ctx, FSName); err != nil {
+		return err
+	}
+	for _, t := range m.views {
+		if !t.isMounted() {
+			return fmt.Errorf("view %s is not mounted", t.view.Name())
+		}
+	}
+	return nil
+}
+
 // Refresh refreshes directories in the VFS cache
 func (m *Manager) Refresh(dirs []string) error {
 	mountInfo := m.getMountInfo()
@@ -314,6 +391,25 @@ func (m *Manager) Refresh(dirs []string) error {
 			Msg("Failed to refresh directory")
 		return fmt.Errorf("failed to refresh directory %s : %w", dirs, err)
 	}
+
+	// Views show the same folders under their own names, or not at all
+	for _, t := range m.views {
+		if !t.isMounted() {
+			continue
+		}
+		var viewDirs []string
+		for _, dir := range dirs {
+			if name, ok := t.view.ShownName(dir); ok {
+				viewDirs = append(viewDirs, name)
+			}
+		}
+		if len(viewDirs) == 0 {
+			continue
+		}
+		if err := m.client.Refresh(context.Background(), viewDirs, t.fs()); err != nil {
+			m.logger.Warn().Err(err).Str("view", t.view.Name()).Msg("Failed to refresh view directory")
+		}
+	}
 	return nil
 }
 
@@ -16,6 +16,7 @@ type Stats struct {
 	Core      rclone.CoreStatsResponse `json:"core"`
 	Memory    rclone.MemoryStats       `json:"memory"`
 	Mount     *MountInfo               `json:"mounts"`
+	Views     []*MountInfo             `json:"views,omitempty"`
 	Bandwidth rclone.BandwidthStats    `json:"bandwidth"`
 	Version   rclone.VersionResponse   `json:"version"`
 }
@@ -49,6 +50,11 @@ func (m *Manager) Stats() map[string]any {
 	// Add mount infos
 	mountInfo := m.getMountInfo()
 	stats.Mount = mountInfo
+	for _, t := range m.views {
+		if info := t.info.Load(); info != nil {
+			stats.Views = append(stats.Views, info)
+		}
+	}
 
 	// GetReader version info
 	versionResp, err := m.client.GetVersion(ctx)
@@ -119,6 +119,14 @@ func (d *DiscordNotifier) getHeader(event config.NotificationEvent) string {
 		return "[Decypharr] Repair Failed"
 	case config.EventRepairCancelled:
 		return "[Decypharr] Repair Cancelled"
+	case config.EventAccountExpiring:
+		return "[Decypharr] Account Expiring"
+	case config.EventAccountQuota:
+		return "[Decypharr] Account Traffic Budget"
+	case config.EventLibraryPruned:
+		return "[Decypharr] Library Pruned"
+	case config.EventDebridExpiry:
+		return "[Decypharr] Debrid Expiry"
 	default:
 		// Split the event string and capitalize the first letter of each word
 		evs := strings.Split(string(event), "_")
@@ -34,6 +34,10 @@ func (s *Server) handleGetArrs(w http.ResponseWriter, r *http.Request) {
 	utils.JSONResponse(w, s.manager.Arr().GetAll(), http.StatusOK)
 }
 
+func (s *Server) handleGetAccounts(w http.ResponseWriter, r *http.Request) {
+	utils.JSONResponse(w, s.manager.AccountStats(), http.StatusOK)
+}
+
 func (s *Server) handleAddContent(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	if err := r.ParseMultipartForm(32 << 20); err != nil {
@@ -58,6 +62,12 @@ func (s *Server) handleAddContent(w http.ResponseWriter, r *http.Request) {
 	}
 	rmTrackerUrls := r.FormValue("rmTrackerUrls") == "true"
 
+	selection, err := fileSelectionFromForm(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Check config setting - if always remove tracker URLs is enabled, force it to true
 	cfg := config.Get()
 	if cfg.AlwaysRmTrackerUrls {
@@ -69,6 +79,10 @@ func (s *Server) handleAddContent(w http.ResponseWriter, r *http.Request) {
 		// These are not found in the config. They are throwaway arrs.
 		_arr = arr.New(arrName, "", "", false, downloadUncached, "", "")
 	}
+	// The request's own action and season handling replace the profile's
+	policy := cfg.PolicyFor(_arr.Name)
+	policy.DownloadAction = config.DownloadAction(action)
+	policy.SkipMultiSeason = skipMultiSeason
 
 	// Unified task type for all content types
 	type addTask struct {
@@ -169,7 +183,10 @@ func (s *Server) handleAddContent(w http.ResponseWriter, r *http.Request) {
 			}
 
 		case "torrent":
-			importReq := manager.NewTorrentRequest(debridName, downloadFolder, task.magnet, _arr, config.DownloadAction(action), downloadUncached, callbackUrl, manager.ImportTypeAPI, skipMultiSeason)
+			importReq := manager.NewTorrentRequest(debridName, downloadFolder, task.magnet, _arr, policy, downloadUncached, callbackUrl, manager.ImportTypeAPI)
+			if selection != nil {
+				importReq.FileSelection = selection.Clone()
+			}
 			if err := s.manager.AddNewTorrent(ctx, importReq); err != nil {
 				s.logger.Error().Err(err).Str("source", task.source).Msg("Failed to add torrent")
 				importReq.Error = err.Error()
@@ -178,7 +195,10 @@ func (s *Server) handleAddContent(w http.ResponseWriter, r *http.Request) {
 			return importReq
 
 		case "nzb":
-			importReq := manager.NewNZBRequest(task.name, downloadFolder, task.nzbContent, _arr, config.DownloadAction(action), callbackUrl, manager.ImportTypeAPI, skipMultiSeason)
+			importReq := manager.NewNZBRequest(task.name, downloadFolder, task.nzbContent, _arr, policy, callbackUrl, manager.ImportTypeAPI)
+			if selection != nil {
+				importReq.FileSelection = selection.Clone()
+			}
 			nzoID, err := s.manager.AddNewNZB(ctx, importReq)
 			if err != nil {
 				s.logger.Error().Err(err).Str("source", task.source).Msg("Failed to add NZB")
@@ -204,6 +224,35 @@ func (s *Server) handleAddContent(w http.ResponseWriter, r *http.Request) {
 	utils.JSONResponse(w, filtered, http.StatusOK)
 }
 
+// fileSelectionFromForm reads per-request file-selection rules. It returns nil
+// when none are set, so the category rules apply.
+func fileSelectionFromForm(r *http.Request) (*config.FileSelection, error) {
+	splitLines := func(value string) []string {
+		var out []string
+		for line := range strings.SplitSeq(value, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				out = append(out, line)
+			}
+		}
+		return out
+	}
+	selection := &config.FileSelection{
+		Include:     splitLines(r.FormValue("include")),
+		Exclude:     splitLines(r.FormValue("exclude")),
+		SkipExtras:  r.FormValue("skipExtras") == "true",
+		Seasons:     strings.TrimSpace(r.FormValue("seasons")),
+		Episodes:    strings.TrimSpace(r.FormValue("episodes")),
+		LargestOnly: r.FormValue("largestOnly") == "true",
+	}
+	if selection.IsZero() {
+		return nil, nil
+	}
+	if err := selection.Validate(); err != nil {
+		return nil, err
+	}
+	return selection, nil
+}
+
 func getNZBContentFromFile(fileHeader *multipart.FileHeader) ([]byte, error) {
 	file, err := fileHeader.Open()
 	if err != nil {
@@ -284,6 +333,50 @@ func (s *Server) handlePurgeMountCache(w http.ResponseWriter, r *http.Request) {
 	}, http.StatusOK)
 }
 
+func (s *Server) handleGetCachePins(w http.ResponseWriter, r *http.Request) {
+	pins, err := s.manager.CachePins()
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to read cache pins")
+		http.Error(w, "Failed to read cache pins", http.StatusInternalServerError)
+		return
+	}
+	utils.JSONResponse(w, pins, http.StatusOK)
+}
+
+// handleAddCachePin pins an entry (by infohash) or a category. DFS picks the
+// change up straight away and starts caching the pinned files.
+func (s *Server) handleAddCachePin(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Type  storage.CachePinType `json:"type"`
+		Value string               `json:"value"`
+	}
+	if err := json.ConfigDefault.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	pin, err := s.manager.PinCache(req.Type, req.Value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	utils.JSONResponse(w, pin, http.StatusOK)
+}
+
+func (s *Server) handleDeleteCachePin(w http.ResponseWriter, r *http.Request) {
+	typ := storage.CachePinType(chi.URLParam(r, "type"))
+	value := chi.URLParam(r, "value")
+	if typ != storage.CachePinEntry && typ != storage.CachePinCategory {
+		http.Error(w, "Pin type must be entry or category", http.StatusBadRequest)
+		return
+	}
+	if err := s.manager.UnpinCache(typ, value); err != nil {
+		s.logger.Error().Err(err).Str("type", string(typ)).Str("value", value).Msg("Failed to remove cache pin")
+		http.Error(w, "Failed to remove cache pin", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
 func (s *Server) handleGetTorrents(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters for server-side filtering, sorting, and pagination
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
@@ -429,7 +522,7 @@ func (s *Server) handleDeleteTorrent(w http.ResponseWriter, r *http.Request) {
 			exists, _ := s.manager.EntryExists(t.InfoHash)
 			if exists {
 				// Remove the entry from manager fully, which will handle removing from debrid and deleting the entry
-				return s.manager.DeleteEntry(t.InfoHash, true)
+				return s.manager.DeleteEntry(t.InfoHash, true, true)
 			}
 			go s.manager.RemoveTorrentPlacements(t)
 			return nil
@@ -445,6 +538,23 @@ func (s *Server) handleDeleteTorrent(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// handleGetEntryEvents returns the timeline of an entry, oldest first. An
+// entry without events gets an empty list.
+func (s *Server) handleGetEntryEvents(w http.ResponseWriter, r *http.Request) {
+	hash := chi.URLParam(r, "hash")
+	if hash == "" {
+		http.Error(w, "No hash provided", http.StatusBadRequest)
+		return
+	}
+	events, err := s.manager.EntryEvents(hash)
+	if err != nil {
+		s.logger.Error().Err(err).Str("hash", hash).Msg("Failed to read entry events")
+		http.Error(w, "Failed to read entry events", http.StatusInternalServerError)
+		return
+	}
+	utils.JSONResponse(w, events, http.StatusOK)
+}
+
 func (s *Server) handleDeleteTorrents(w http.ResponseWriter, r *http.Request) {
 	hashesStr := r.URL.Query().Get("hashes")
 	removeFromDebrid := r.URL.Query().Get("removeFromDebrid") == "true"
@@ -459,7 +569,7 @@ func (s *Server) handleDeleteTorrents(w http.ResponseWriter, r *http.Request) {
 			exists, _ := s.manager.EntryExists(t.InfoHash)
 			if exists {
 				// Remove the entry from manager fully, which will handle removing from debrid and deleting the entry
-				return s.manager.DeleteEntry(t.InfoHash, true)
+				return s.manager.DeleteEntry(t.InfoHash, true, true)
 			}
 			go s.manager.RemoveTorrentPlacements(t)
 			return nil
@@ -549,6 +659,7 @@ func (s *Server) handleUpdateConfig(w http.ResponseWriter, r *http.Request) {
 		go s.Restart()
 	} else {
 		config.Get().ApplyRuntime(&newConfig)
+		s.manager.LoadTagRules()
 		// Reschedule/reapply the repair sweep if its settings changed.
 		if svc := s.manager.Repair(); svc != nil {
 			if err := svc.ApplyConfig(); err != nil {
@@ -630,6 +741,7 @@ func (s *Server) handleRunRepair(w http.ResponseWriter, r *http.Request) {
 		Force             bool   `json:"force,omitempty"`
 		AutoRepair        *bool  `json:"auto_repair,omitempty"`
 		UnrestrictLink    bool   `json:"unrestrict_link,omitempty"`
+		PieceVerify       bool   `json:"piece_verify,omitempty"`
 		Protocol          string `json:"protocol,omitempty"`
 	}
 	if r.Body != nil && r.ContentLength != 0 {
@@ -663,6 +775,13 @@ func (s *Server) handleRunRepair(w http.ResponseWriter, r *http.Request) {
 	case "0", "false", "no", "off":
 		unrestrictLink = false
 	}
+	pieceVerify := req.PieceVerify
+	switch strings.ToLower(strings.TrimSpace(r.URL.Query().Get("piece_verify"))) {
+	case "1", "true", "yes", "on":
+		pieceVerify = true
+	case "0", "false", "no", "off":
+		pieceVerify = false
+	}
 	protocolScope := strings.ToLower(strings.TrimSpace(req.Protocol))
 	if queryProtocol := strings.TrimSpace(r.URL.Query().Get("protocol")); queryProtocol != "" {
 		protocolScope = strings.ToLower(queryProtocol)
@@ -686,6 +805,7 @@ func (s *Server) handleRunRepair(w http.ResponseWriter, r *http.Request) {
 		IgnoreLastChecked: ignoreLastChecked,
 		AutoRepair:        autoRepair,
 		UnrestrictLink:    unrestrictLink,
+		PieceVerify:       pieceVerify,
 		ProtocolScope:     protocolScope,
 	})
 	if err != nil {
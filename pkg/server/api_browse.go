@@ -28,6 +28,8 @@ type BrowseEntry struct {
 	InfoHash     string `json:"info_hash,omitempty"`  // For torrent folders
 	CanDelete    bool   `json:"can_delete,omitempty"` // Whether this can be deleted
 	ActiveDebrid string `json:"active_debrid"`
+
+	Media *storage.MediaInfo `json:"media,omitempty"` // Container probe result for files
 }
 
 // BrowseResponse is the response for browse requests
@@ -271,6 +273,7 @@ func (s *Server) handleBrowseTorrentFiles(w http.ResponseWriter, r *http.Request
 			IsDir:        child.IsDir(),
 			InfoHash:     child.InfoHash(),
 			ActiveDebrid: child.ActiveDebrid(),
+			Media:        child.Media(),
 		})
 	}
 	sortBrowseEntries(entries, sortBy, sortOrder)
@@ -313,7 +316,7 @@ func (s *Server) handleDeleteBrowseTorrent(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if err := s.manager.DeleteEntry(id, true); err != nil {
+	if err := s.manager.DeleteEntry(id, true, true); err != nil {
 		s.logger.Error().Err(err).Str("id", id).Msg("Failed to delete entry")
 		http.Error(w, "Failed to delete entry", http.StatusInternalServerError)
 		return
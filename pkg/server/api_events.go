@@ -0,0 +1,113 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	json "github.com/bytedance/sonic"
+
+	"github.com/sirrobot01/decypharr/internal/eventbus"
+)
+
+const sseHeartbeatInterval = 15 * time.Second
+
+var liveTopics = []string{eventbus.TopicQueue, eventbus.TopicStreams, eventbus.TopicRepair, eventbus.TopicLogs}
+
+// handleEvents streams live updates as Server-Sent Events. ?topics= limits the
+// stream to a comma separated list of topics. A reconnecting client resumes
+// after the Last-Event-ID header, or ?last_event_id= for clients that can't
+// set headers.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	var topics []string
+	for t := range strings.SplitSeq(r.URL.Query().Get("topics"), ",") {
+		if t = strings.TrimSpace(t); t == "" {
+			continue
+		}
+		if !slices.Contains(liveTopics, t) {
+			http.Error(w, fmt.Sprintf("Unknown topic %q, expected one of %s", t, strings.Join(liveTopics, ", ")), http.StatusBadRequest)
+			return
+		}
+		topics = append(topics, t)
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+	var after uint64
+	if lastID != "" {
+		id, err := strconv.ParseUint(lastID, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid last event ID", http.StatusBadRequest)
+			return
+		}
+		after = id
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	bus := s.manager.Bus()
+	sub, replay, complete := bus.Subscribe(topics, after)
+	defer bus.Unsubscribe(sub)
+
+	if _, err := fmt.Fprint(w, "retry: 3000\n\n"); err != nil {
+		return
+	}
+	if !complete {
+		// Some missed events are gone; the client reloads its state
+		if _, err := fmt.Fprint(w, "event: reset\ndata: {}\n\n"); err != nil {
+			return
+		}
+	}
+	for _, ev := range replay {
+		if err := writeSSE(w, ev); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		s.logger.Debug().Err(err).Msg("Event stream does not support flushing")
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-sub.Events():
+			if !ok {
+				// Dropped for falling behind; the client reconnects and replays
+				return
+			}
+			if err := writeSSE(w, ev); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, ev eventbus.Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return nil // Skip events that can't be encoded
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Topic, data)
+	return err
+}
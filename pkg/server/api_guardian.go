@@ -0,0 +1,77 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	json "github.com/bytedance/sonic"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+func (s *Server) handleRunGuardian(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Providers []string `json:"providers"`
+		DryRun    *bool    `json:"dry_run,omitempty"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.ConfigDefault.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	// Manual runs only report unless dry_run is explicitly false
+	dryRun := req.DryRun == nil || *req.DryRun
+	run, err := s.manager.StartGuardian(storage.RepairTriggerManual, manager.GuardianOptions{
+		Providers: req.Providers,
+		DryRun:    dryRun,
+	})
+	if err != nil {
+		if errors.Is(err, manager.ErrGuardianRunning) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	utils.JSONResponse(w, run, http.StatusAccepted)
+}
+
+// handleListGuardianRuns returns run summaries without their items.
+func (s *Server) handleListGuardianRuns(w http.ResponseWriter, r *http.Request) {
+	runs, err := s.manager.Storage().ListGuardianRuns()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, run := range runs {
+		run.Items = nil
+	}
+	utils.JSONResponse(w, runs, http.StatusOK)
+}
+
+func (s *Server) handleGetGuardianRun(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "No run ID provided", http.StatusBadRequest)
+		return
+	}
+	run, err := s.manager.Storage().GetGuardianRun(id)
+	if err != nil {
+		http.Error(w, "Run not found", http.StatusNotFound)
+		return
+	}
+	if action := storage.GuardianAction(r.URL.Query().Get("action")); action != "" {
+		items := make([]*storage.GuardianItem, 0, len(run.Items))
+		for _, item := range run.Items {
+			if item.Action == action {
+				items = append(items, item)
+			}
+		}
+		run.Items = items
+	}
+	utils.JSONResponse(w, run, http.StatusOK)
+}
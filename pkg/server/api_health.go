@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+)
+
+// handleHealthz is the liveness probe: it answers as long as the server is
+// serving requests
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	utils.JSONResponse(w, map[string]any{
+		"status": "ok",
+		"uptime": s.manager.Uptime().Round(time.Second).String(),
+	}, http.StatusOK)
+}
+
+// handleReadyz is the readiness probe. It reports every component and
+// answers 503 while any of them is failing. It is served without auth, so the
+// reasons, which may carry provider errors, are left out; /debug/health has
+// them.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	report := s.manager.Health(r.Context())
+	for i := range report.Components {
+		report.Components[i].Reason = ""
+	}
+	writeHealthReport(w, report)
+}
+
+// handleHealthReport is /readyz with the reason of each component
+func (s *Server) handleHealthReport(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, s.manager.Health(r.Context()))
+}
+
+func writeHealthReport(w http.ResponseWriter, report manager.HealthReport) {
+	status := http.StatusOK
+	if !report.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	utils.JSONResponse(w, report, status)
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	json "github.com/bytedance/sonic"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+type startMigrationRequest struct {
+	Provider    string `json:"provider"`
+	Category    string `json:"category"`
+	Tag         string `json:"tag"`
+	Target      string `json:"target"`
+	KeepOld     bool   `json:"keep_old"`
+	DryRun      bool   `json:"dry_run"`
+	Concurrency int    `json:"concurrency"`
+}
+
+func (s *Server) handleStartMigration(w http.ResponseWriter, r *http.Request) {
+	var req startMigrationRequest
+	if err := json.ConfigDefault.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	batch, err := s.manager.StartSwitchBatch(manager.SwitchBatchRequest{
+		Filter: storage.SwitchBatchFilter{
+			Provider: strings.TrimSpace(req.Provider),
+			Category: strings.TrimSpace(req.Category),
+			Tag:      strings.TrimSpace(req.Tag),
+		},
+		Target:      strings.TrimSpace(req.Target),
+		KeepOld:     req.KeepOld,
+		DryRun:      req.DryRun,
+		Concurrency: req.Concurrency,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	utils.JSONResponse(w, batch, http.StatusAccepted)
+}
+
+// handleListMigrations returns batch summaries; per-item results are only
+// included when fetching a single batch.
+func (s *Server) handleListMigrations(w http.ResponseWriter, r *http.Request) {
+	batches, err := s.manager.ListSwitchBatches()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, batch := range batches {
+		batch.Items = nil
+	}
+	utils.JSONResponse(w, batches, http.StatusOK)
+}
+
+func (s *Server) handleGetMigration(w http.ResponseWriter, r *http.Request) {
+	batch, err := s.manager.GetSwitchBatch(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Migration not found", http.StatusNotFound)
+		return
+	}
+	utils.JSONResponse(w, batch, http.StatusOK)
+}
+
+func (s *Server) handlePauseMigration(w http.ResponseWriter, r *http.Request) {
+	s.migrationAction(w, r, s.manager.PauseSwitchBatch)
+}
+
+func (s *Server) handleResumeMigration(w http.ResponseWriter, r *http.Request) {
+	s.migrationAction(w, r, s.manager.ResumeSwitchBatch)
+}
+
+func (s *Server) handleCancelMigration(w http.ResponseWriter, r *http.Request) {
+	s.migrationAction(w, r, s.manager.CancelSwitchBatch)
+}
+
+func (s *Server) handleDeleteMigration(w http.ResponseWriter, r *http.Request) {
+	s.migrationAction(w, r, s.manager.DeleteSwitchBatch)
+}
+
+func (s *Server) migrationAction(w http.ResponseWriter, r *http.Request, action func(id string) error) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "No migration ID provided", http.StatusBadRequest)
+		return
+	}
+	if err := action(id); err != nil {
+		switch {
+		case errors.Is(err, manager.ErrSwitchBatchNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, manager.ErrSwitchBatchRunning),
+			errors.Is(err, manager.ErrSwitchBatchNotRunning),
+			errors.Is(err, manager.ErrSwitchBatchFinished):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	batch, err := s.manager.GetSwitchBatch(id)
+	if err != nil {
+		// Deleted
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	utils.JSONResponse(w, batch, http.StatusOK)
+}
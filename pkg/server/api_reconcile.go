@@ -0,0 +1,84 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	json "github.com/bytedance/sonic"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+func (s *Server) handleRunReconcile(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Providers  []string `json:"providers"`
+		DryRun     *bool    `json:"dry_run,omitempty"`
+		Orphans    string   `json:"orphans"`
+		Duplicates string   `json:"duplicates"`
+		Missing    string   `json:"missing"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.ConfigDefault.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	// Manual runs only report unless dry_run is explicitly false
+	dryRun := req.DryRun == nil || *req.DryRun
+	run, err := s.manager.StartReconcile(storage.RepairTriggerManual, manager.ReconcileOptions{
+		Providers:  req.Providers,
+		DryRun:     dryRun,
+		Orphans:    storage.ReconcileAction(strings.ToLower(strings.TrimSpace(req.Orphans))),
+		Duplicates: storage.ReconcileAction(strings.ToLower(strings.TrimSpace(req.Duplicates))),
+		Missing:    storage.ReconcileAction(strings.ToLower(strings.TrimSpace(req.Missing))),
+	})
+	if err != nil {
+		if errors.Is(err, manager.ErrReconcileRunning) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	utils.JSONResponse(w, run, http.StatusAccepted)
+}
+
+// handleListReconcileRuns returns run summaries without their findings.
+func (s *Server) handleListReconcileRuns(w http.ResponseWriter, r *http.Request) {
+	runs, err := s.manager.Storage().ListReconcileRuns()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, run := range runs {
+		run.Items = nil
+	}
+	utils.JSONResponse(w, runs, http.StatusOK)
+}
+
+func (s *Server) handleGetReconcileRun(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "No run ID provided", http.StatusBadRequest)
+		return
+	}
+	run, err := s.manager.Storage().GetReconcileRun(id)
+	if err != nil {
+		http.Error(w, "Run not found", http.StatusNotFound)
+		return
+	}
+	if class := storage.ReconcileClass(r.URL.Query().Get("class")); class != "" {
+		items := make([]*storage.ReconcileItem, 0, len(run.Items))
+		for _, item := range run.Items {
+			if item.Class == class {
+				items = append(items, item)
+			}
+		}
+		run.Items = items
+	}
+	utils.JSONResponse(w, run, http.StatusOK)
+}
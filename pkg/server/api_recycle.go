@@ -0,0 +1,88 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	json "github.com/bytedance/sonic"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+type recycledEntryResponse struct {
+	*storage.RecycledEntry
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (s *Server) handleListRecycleBin(w http.ResponseWriter, r *http.Request) {
+	recs, err := s.manager.RecycledEntries()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	out := make([]recycledEntryResponse, 0, len(recs))
+	for _, rec := range recs {
+		out = append(out, recycledEntryResponse{RecycledEntry: rec, ExpiresAt: s.manager.RecycleExpiry(rec)})
+	}
+	utils.JSONResponse(w, out, http.StatusOK)
+}
+
+func (s *Server) handleRestoreRecycled(w http.ResponseWriter, r *http.Request) {
+	hash := chi.URLParam(r, "hash")
+	if hash == "" {
+		http.Error(w, "No hash provided", http.StatusBadRequest)
+		return
+	}
+	var req struct {
+		Provider string `json:"provider"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.ConfigDefault.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	entry, err := s.manager.RestoreEntry(hash, req.Provider)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	utils.JSONResponse(w, map[string]any{
+		"infohash": entry.InfoHash,
+		"name":     entry.Name,
+		"provider": entry.ActiveProvider,
+	}, http.StatusOK)
+}
+
+func (s *Server) handlePurgeRecycled(w http.ResponseWriter, r *http.Request) {
+	hash := chi.URLParam(r, "hash")
+	if hash == "" {
+		http.Error(w, "No hash provided", http.StatusBadRequest)
+		return
+	}
+	if err := s.manager.PurgeRecycledEntry(hash); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEmptyRecycleBin purges every recycled entry
+func (s *Server) handleEmptyRecycleBin(w http.ResponseWriter, r *http.Request) {
+	recs, err := s.manager.RecycledEntries()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	purged := 0
+	for _, rec := range recs {
+		if err := s.manager.PurgeRecycledEntry(rec.InfoHash); err != nil {
+			s.logger.Warn().Err(err).Str("name", rec.Name).Msg("Failed to purge recycled entry")
+			continue
+		}
+		purged++
+	}
+	utils.JSONResponse(w, map[string]int{"purged": purged}, http.StatusOK)
+}
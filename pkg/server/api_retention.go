@@ -0,0 +1,77 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	json "github.com/bytedance/sonic"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+func (s *Server) handleRunRetention(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Policies []string `json:"policies"`
+		DryRun   *bool    `json:"dry_run,omitempty"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.ConfigDefault.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	// Manual runs only report unless dry_run is explicitly false
+	dryRun := req.DryRun == nil || *req.DryRun
+	run, err := s.manager.StartRetention(storage.RepairTriggerManual, manager.RetentionOptions{
+		Policies: req.Policies,
+		DryRun:   dryRun,
+	})
+	if err != nil {
+		if errors.Is(err, manager.ErrRetentionRunning) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	utils.JSONResponse(w, run, http.StatusAccepted)
+}
+
+// handleListRetentionRuns returns run summaries without their items.
+func (s *Server) handleListRetentionRuns(w http.ResponseWriter, r *http.Request) {
+	runs, err := s.manager.Storage().ListRetentionRuns()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, run := range runs {
+		run.Items = nil
+	}
+	utils.JSONResponse(w, runs, http.StatusOK)
+}
+
+func (s *Server) handleGetRetentionRun(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "No run ID provided", http.StatusBadRequest)
+		return
+	}
+	run, err := s.manager.Storage().GetRetentionRun(id)
+	if err != nil {
+		http.Error(w, "Run not found", http.StatusNotFound)
+		return
+	}
+	if policy := r.URL.Query().Get("policy"); policy != "" {
+		items := make([]*storage.RetentionItem, 0, len(run.Items))
+		for _, item := range run.Items {
+			if item.Policy == policy {
+				items = append(items, item)
+			}
+		}
+		run.Items = items
+	}
+	utils.JSONResponse(w, run, http.StatusOK)
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"net/http"
+
+	json "github.com/bytedance/sonic"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+)
+
+// handlePreviewTagRules shows what tag rules would do to the stored entries.
+// The body may carry rules to try before saving them; without any, the
+// configured rules are used. Nothing is changed.
+func (s *Server) handlePreviewTagRules(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Rules []config.TagRule    `json:"rules,omitempty"`
+		Event config.TagRuleEvent `json:"event,omitempty"`
+		Limit int                 `json:"limit,omitempty"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.ConfigDefault.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	switch req.Event {
+	case "", config.TagRuleOnAdd, config.TagRuleOnComplete:
+	default:
+		http.Error(w, "Invalid event (must be 'add' or 'complete')", http.StatusBadRequest)
+		return
+	}
+	if req.Limit < 0 {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	outcomes, err := s.manager.PreviewTagRules(manager.TagRulePreviewOptions{
+		Rules: req.Rules,
+		Event: req.Event,
+		Limit: req.Limit,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	utils.JSONResponse(w, map[string]any{
+		"matched":  len(outcomes),
+		"outcomes": outcomes,
+	}, http.StatusOK)
+}
@@ -1 +1 @@
-class ConfigManager{constructor(){this.debridCount=0,this.arrCount=0,this.usenetProviderCount=0,this.debridDirectoryCounts={},this.directoryFilterCounts={},this.virtualFolderCount=0,this.refs={configForm:document.getElementById("configForm"),loadingOverlay:document.getElementById("loadingOverlay"),debridConfigs:document.getElementById("debridConfigs"),arrConfigs:document.getElementById("arrConfigs"),virtualFoldersContainer:document.getElementById("virtualFoldersContainer"),usenetProviders:document.getElementById("usenetProviders"),addDebridBtn:document.getElementById("addDebridBtn"),addArrBtn:document.getElementById("addArrBtn"),addVirtualFolderBtn:document.getElementById("addVirtualFolderBtn"),addUsenetProviderBtn:document.getElementById("addUsenetProviderBtn")},this.init()}init(){this.bindEvents(),this.loadConfiguration(),this.setupMagnetHandler(),this.checkIncompleteConfig()}checkIncompleteConfig(){const e=new URLSearchParams(window.location.search);if(e.has("inco")){const t=e.get("inco");window.decypharrUtils.createToast(`Incomplete configuration: ${t}`,"warning")}}bindEvents(){this.refs.configForm.addEventListener("submit",e=>this.saveConfiguration(e)),this.refs.addDebridBtn.addEventListener("click",()=>this.addDebridConfig()),this.refs.addArrBtn.addEventListener("click",()=>this.addArrConfig()),this.refs.addVirtualFolderBtn.addEventListener("click",()=>this.addVirtualFolder()),this.refs.addUsenetProviderBtn.addEventListener("click",()=>this.addUsenetProvider());const e=document.getElementById("addQueueCleanupRuleBtn");e&&e.addEventListener("click",()=>this.addQueueCleanupCustomRow())}get queueCleanupCatalog(){return[{id:"failed_download",label:"Failed download"},{id:"title_mismatch",label:"Title mismatch (automatic import not possible)"},{id:"matched_by_id",label:"Release matched to series/movie by ID"},{id:"unable_to_parse",label:"Unable to parse download"},{id:"no_eligible_files",label:"No files eligible for import"},{id:"episodes_missing",label:"Episodes missing / not imported from release"},{id:"file_empty",label:"Downloaded file is empty"},{id:"invalid_local_path",label:"Invalid local path (remote path mapping)"},{id:"not_grabbed",label:"Not grabbed by the arr / no category"}]}queueCleanupActionOptions(e){return[{value:"",label:"Ignore (leave in queue)"},{value:"import",label:"Force import"},{value:"blacklist",label:"Blacklist only"},{value:"blacklist_research",label:"Blacklist + research"}].map(t=>`<option value="${t.value}" ${t.value===(e||"")?"selected":""}>${t.label}</option>`).join("")}async loadConfiguration(){try{const e=await window.decypharrUtils.fetcher("/api/config");if(!e.ok)throw new Error("Failed to load configuration");const t=await e.json();this.populateForm(t)}catch(e){console.error("Error loading configuration:",e),window.decypharrUtils.createToast("Error loading configuration","error")}}populateForm(e){this.populateGeneralSettings(e),this.populateDownloadSettings(e),e.debrids&&Array.isArray(e.debrids)&&e.debrids.forEach(e=>this.addDebridConfig(e)),e.usenet&&this.populateUsenetSettings(e.usenet),e.custom_folders&&this.populateVirtualFolders(e.custom_folders),e.arrs&&Array.isArray(e.arrs)&&e.arrs.forEach(e=>this.addArrConfig(e)),this.populateQueueCleanup(e.queue_cleanup),this.populateMountSettings(e.mount),this.populateAPIToken(e),this.populateNotificationSettings(e.notifications),this.populateRepairSettings(e.repair,e.arrs)}populateRepairSettings(e,t){const n=document.getElementById("repair.arrs");if(n){const a=new Set(e&&Array.isArray(e.arrs)?e.arrs:[]);n.innerHTML="";for(const e of t||[]){if(!e||!e.name)continue;const t=document.createElement("option");t.value=e.name,t.textContent=e.name,a.has(e.name)&&(t.selected=!0),n.appendChild(t)}}if(!e)return;const $=e=>document.getElementById(e);$("repair.enabled")&&($("repair.enabled").checked=!!e.enabled),$("repair.source")&&($("repair.source").value=e.source||"arr"),$("repair.schedule")&&($("repair.schedule").value=e.schedule||""),$("repair.recheck_interval")&&($("repair.recheck_interval").value=e.recheck_interval||""),$("repair.workers")&&($("repair.workers").value=e.workers||5),$("repair.nntp_connection_percent")&&($("repair.nntp_connection_percent").value=e.nntp_connection_percent||20),$("repair.strategy")&&($("repair.strategy").value=e.strategy||"per_entry"),$("repair.auto_repair")&&($("repair.auto_repair").checked=!!e.auto_repair),$("repair.skip_nzb_repair")&&($("repair.skip_nzb_repair").checked=!!e.skip_nzb_repair)}collectRepairConfig(){const $=e=>document.getElementById(e),e=$("repair.arrs"),t=e?Array.from(e.selectedOptions).map(e=>e.value).filter(Boolean):[];return{enabled:$("repair.enabled")?.checked||!1,source:$("repair.source")?.value||"arr",schedule:$("repair.schedule")?.value.trim()||"",recheck_interval:$("repair.recheck_interval")?.value.trim()||"",workers:parseInt($("repair.workers")?.value,10)||0,nntp_connection_percent:parseInt($("repair.nntp_connection_percent")?.value,10)||0,strategy:$("repair.strategy")?.value||"per_entry",auto_repair:$("repair.auto_repair")?.checked||!1,skip_nzb_repair:$("repair.skip_nzb_repair")?.checked||!1,arrs:t}}populateGeneralSettings(e){["log_level","url_base","bind_address","port","min_file_size","max_file_size","folder_naming","refresh_dirs","disable_webdav","app_url"].forEach(t=>{const n=document.querySelector(`[name="${t}"]`);n&&void 0!==e[t]&&("checkbox"===n.type?n.checked=e[t]:n.value=e[t])}),e.allowed_file_types&&Array.isArray(e.allowed_file_types)&&(document.querySelector('[name="allowed_file_types"]').value=e.allowed_file_types.join(", "))}populateDownloadSettings(e){["remove_stalled_after","nzb_user_agent","download_folder","refresh_interval","max_active_downloads","skip_pre_cache","always_rm_tracker_urls","default_download_action"].forEach(t=>{const n=document.querySelector(`[name="${t}"]`);n&&void 0!==e[t]&&("checkbox"===n.type?n.checked=e[t]:n.value=e[t])})}populateNotificationSettings(e){if(!e)return;const t=document.getElementById("notifications.enabled");t&&(t.checked=e.enabled||!1);const n=document.getElementById("notifications.webhook_url");n&&e.webhook_url&&(n.value=e.webhook_url);const a=document.getElementById("notifications.callback_url");a&&e.callback_url&&(a.value=e.callback_url),e.events&&Array.isArray(e.events)&&e.events.forEach(e=>{const t=document.querySelector(`input[name="notifications.events[]"][value="${e}"]`);t&&(t.checked=!0)})}populateMountSettings(e){if(!e)return;if(e.type){const t=document.querySelector(`input[name="mount.type"][value="${e.type}"]`);t&&(t.checked=!0,t.dispatchEvent(new Event("change")))}const t=document.querySelector('[name="mount.mount_path"]');t&&void 0!==e.mount_path&&(t.value=e.mount_path),this.populateRcloneSettings(e.rclone),this.populateDFSSettings(e.dfs),this.populateExternalRcloneSettings(e.external_rclone)}populateRcloneSettings(e){if(!e)return;["port","cache_dir","transfers","vfs_cache_mode","vfs_cache_max_size","vfs_cache_max_age","vfs_cache_poll_interval","vfs_read_chunk_size","vfs_read_chunk_size_limit","buffer_size","bw_limit","uid","gid","vfs_read_ahead","attr_timeout","dir_cache_time","poll_interval","umask","no_modtime","no_checksum","log_level","vfs_cache_min_free_space","vfs_fast_fingerprint","vfs_read_chunk_streams","async_read","use_mmap"].forEach(t=>{const n=document.querySelector(`[name="mount.rclone.${t}"]`);n&&void 0!==e[t]&&("checkbox"===n.type?n.checked=e[t]:n.value=e[t])})}populateDFSSettings(e){if(!e)return;["cache_dir","disk_cache_size","buffer_memory","cache_expiry","cache_cleanup_interval","chunk_size","read_ahead_size","daemon_timeout","uid","gid","umask"].forEach(t=>{const n=document.querySelector(`[name="mount.dfs.${t}"]`);n&&void 0!==e[t]&&("checkbox"===n.type?n.checked=e[t]:n.value=e[t])})}populateExternalRcloneSettings(e){if(!e)return;["rc_url","rc_username","rc_password"].forEach(t=>{const n=document.querySelector(`[name="mount.external_rclone.${t}"]`);n&&void 0!==e[t]&&(n.value=e[t])})}addDebridConfig(e={}){const t=this.getDebridTemplate(this.debridCount,e);this.refs.debridConfigs.insertAdjacentHTML("beforeend",t);const n=this.refs.debridConfigs.lastElementChild,a=n.querySelector(`[name="debrid[${this.debridCount}].name"]`);a&&a.addEventListener("blur",()=>{this.updateArrDebridDropdowns()});const r=n.querySelector(".btn-error");r&&r.addEventListener("click",()=>{setTimeout(()=>{this.updateArrDebridDropdowns()},100)}),Object.keys(e).length>0&&this.populateDebridData(this.debridCount,e),this.debridDirectoryCounts[this.debridCount]=0,e.directories&&Object.entries(e.directories).forEach(([e,t])=>{const n=this.addDirectory(this.debridCount,{name:e,...t});t.filters&&Object.entries(t.filters).forEach(([e,t])=>{this.addFilter(this.debridCount,n,e,t)})}),this.debridCount++,this.updateArrDebridDropdowns()}populateDebridData(e,t){Object.entries(t).forEach(([t,n])=>{const a=document.querySelector(`[name="debrid[${e}].${t}"]`);a&&("checkbox"===a.type?a.checked=n:"download_api_keys"===t&&Array.isArray(n)?(a.value=n.join("\n"),"textarea"===a.tagName.toLowerCase()&&(a.style.webkitTextSecurity="disc",a.style.textSecurity="disc",a.setAttribute("data-password-visible","false"))):a.value=n)})}getDebridTemplate(e,t={}){return`\n        <div class="card bg-base-100 border border-base-300 shadow-sm debrid-config" data-index="${e}">\n            <div class="card-body">\n                <div class="flex justify-between items-start mb-4">\n                    <h3 class="card-title text-lg">\n                        <i class="bi bi-cloud mr-2 text-secondary"></i>\n                        Debrid #${e+1}\n                    </h3>\n                    <button type="button" class="btn btn-error btn-sm" onclick="this.closest('.debrid-config').remove();">\n                        <i class="bi bi-trash"></i>\n                    </button>\n                </div>\n                <div class="grid grid-cols-1 lg:grid-cols-3 gap-6">\n                        <div>\n                            <label class="label" for="debrid[${e}].name">\n                                <span class=" font-medium">Service Type</span>\n                            </label>\n                            <select class="select w-full" name="debrid[${e}].provider" id="debrid[${e}].provider" required>\n                                <option value="realdebrid">Real Debrid</option>\n                                <option value="alldebrid">AllDebrid</option>\n                                <option value="debridlink">Debrid Link</option>\n                                <option value="torbox">Torbox</option>\n                                <option value="premiumize">Premiumize</option>\n                            </select>\n                        </div>\n                        \n                        <div>\n                            <label class="label" for="debrid[${e}].name">\n                                <span class=" font-medium">Name</span>\n                            </label>\n                            <input type="text" class="input w-full" \n                                   name="debrid[${e}].name" id="debrid[${e}].name" \n                                   placeholder="realdebrid">\n                            <span class="text-sm opacity-70">A unique name for this debrid account</span>\n                        </div>\n\n                        <div>\n                            <label class="label" for="debrid[${e}].api_key">\n                                <span class=" font-medium">API Key</span>\n                            </label>\n                            <div class="password-toggle-container">\n                                <input type="password" class="input input-has-toggle" \n                                       name="debrid[${e}].api_key" id="debrid[${e}].api_key" required>\n                                <button type="button" class="password-toggle-btn">\n                                    <i class="bi bi-eye" id="debrid[${e}].api_key_icon"></i>\n                                </button>\n                            </div>\n                           <span class="text-sm opacity-70">API key for the debrid service</span>\n                        </div>\n                </div>\n\n                <div class="grid grid-cols-1 lg:grid-cols-2 gap-6">\n                    <div class="flex flex-col">\n                        <div class="fieldset flex-1">\n                            <label class="label" for="debrid[${e}].download_api_keys">\n                                <span class=" font-medium">Download API Keys</span>\n                            </label>\n                            <div class="password-toggle-container">\n                                <textarea class="textarea has-toggle font-mono h-full min-h-[200px]" \n                                          name="debrid[${e}].download_api_keys" \n                                          id="debrid[${e}].download_api_keys" \n                                          placeholder="Multiple API keys for download (one per line). If empty, main API key will be used."></textarea>\n                                <button type="button" class="password-toggle-btn textarea-toggle">\n                                    <i class="bi bi-eye" id="debrid[${e}].download_api_keys_icon"></i>\n                                </button>\n                            </div>\n                            <span class="text-sm opacity-70">Multiple API keys for downloads - leave empty to use main API key</span>\n                        </div>\n                    </div>\n                    <div class="space-y-4">\n                        <div class="grid grid-cols-2 lg:grid-cols-3 gap-3">\n                            <div>\n                                <label class="label" for="debrid[${e}].rate_limit">\n                                    <span class=" font-medium">Rate Limit</span>\n                                </label>\n                                <input type="text" class="input w-full" \n                                       name="debrid[${e}].rate_limit" id="debrid[${e}].rate_limit" \n                                       placeholder="250/minute" value="250/minute">\n                                <span class="text-sm opacity-70">API rate limit for this service</span>\n                            </div>\n                            <div>\n                                <label class="label" for="debrid[${e}].repair_rate_limit">\n                                    <span class=" font-medium">Repair Rate Limit</span>\n                                </label>\n                                <input type="text" class="input w-full" \n                                       name="debrid[${e}].repair_rate_limit" id="debrid[${e}].repair_rate_limit" \n                                       placeholder="100/minute">\n                                <span class="text-sm opacity-70">API rate limit for repair operations</span>\n                            </div>\n                            <div>\n                                <label class="label" for="debrid[${e}].download_rate_limit">\n                                    <span class=" font-medium">Download Rate Limit</span>\n                                </label>\n                                <input type="text" class="input w-full" \n                                       name="debrid[${e}].download_rate_limit" id="debrid[${e}].download_rate_limit" \n                                       placeholder="150/minute">\n                                <span class="text-sm opacity-70">API rate limit for download operations</span>\n                            </div>\n                            <div>\n                                <label class="label" for="debrid[${e}].proxy">\n                                    <span class=" font-medium">Proxy</span>\n                                </label>\n                                <input type="text" class="input w-full" \n                                       name="debrid[${e}].proxy" id="debrid[${e}].proxy" \n                                       placeholder="socks4, socks5, https proxy">\n                                <span class="text-sm opacity-70">This proxy is used for this debrid account</span>\n                            </div>\n                            <div>\n                                <label class="label" for="debrid[${e}].user_agent">\n                                    <span class=" font-medium">Custom User Agent</span>\n                                </label>\n                                <input type="text" class="input w-full" \n                                       name="debrid[${e}].user_agent" id="debrid[${e}].user_agent" \n                                       placeholder="Decypharr/1.0">\n                                <span class="text-sm opacity-70">Custom User Agent for this debrid</span>\n                            </div>\n                            <div>\n                                <label class="label" for="debrid[${e}].minimum_free_slot">\n                                    <span class=" font-medium">Minimum Free Slot</span>\n                                </label>\n                                <input type="number" class="input w-full" \n                                       name="debrid[${e}].minimum_free_slot" id="debrid[${e}].minimum_free_slot" \n                                       placeholder="1" value="1">\n                                <span class="text-sm opacity-70">Minimum free slot for this debrid</span>\n                            </div>\n                        </div>\n                    </div>\n                </div>\n                \n                <div class="grid grid-cols-1 lg:grid-cols-3 gap-6">\n                    <div>\n                        <label class="label" for="debrid[${e}].torrents_refresh_interval">\n                            <span class=" font-medium">Torrents Refresh Interval</span>\n                        </label>\n                        <input type="text" class="input webdav-field" \n                               name="debrid[${e}].torrents_refresh_interval" \n                               id="debrid[${e}].torrents_refresh_interval" \n                               placeholder="10m" value="10m">\n                        <span class="text-sm opacity-70">How often to refresh torrents list</span>\n                    </div>\n                    <div>\n                        <label class="label" for="debrid[${e}].download_links_refresh_interval">\n                            <span class=" font-medium">Links Refresh Interval</span>\n                        </label>\n                        <input type="text" class="input webdav-field" \n                               name="debrid[${e}].download_links_refresh_interval" \n                               id="debrid[${e}].download_links_refresh_interval" \n                               placeholder="40m" value="40m">\n                        <span class="text-sm opacity-70">How often to refresh download links</span>\n                    </div>\n                    <div>\n                        <label class="label" for="debrid[${e}].auto_expire_links_after">\n                            <span class=" font-medium">Links Expiry</span>\n                        </label>\n                        <input type="text" class="input webdav-field" \n                               name="debrid[${e}].auto_expire_links_after" \n                               id="debrid[${e}].auto_expire_links_after" \n                               placeholder="3d" value="3d">\n                        <span class="text-sm opacity-70">Automatically expire links after this duration</span>\n                    </div>\n                </div>\n                <div class="grid grid-cols-2 lg:grid-cols-3 gap-4 mt-6">\n                    <div>\n                        <label class="label cursor-pointer justify-start gap-2">\n                            <input type="checkbox" class="checkbox checkbox-primary" \n                                   name="debrid[${e}].download_uncached" id="debrid[${e}].download_uncached">\n                            <div>\n                                <span class="font-medium">Download Uncached</span>\n                                <div class="label-text-alt">Download uncached files</div>\n                            </div>\n                        </label>\n                    </div>\n\n                    <div>\n                        <label class="label cursor-pointer justify-start gap-2">\n                            <input type="checkbox" class="checkbox checkbox-primary" \n                                   name="debrid[${e}].add_samples" id="debrid[${e}].add_samples">\n                             <div>\n                                <span class=" font-medium">Add Samples</span>\n                                <div class="label-text-alt">Include sample files</div>\n                            </div>\n                        </label>\n                    </div>\n\n                    <div>\n                        <label class="label cursor-pointer justify-start gap-2">\n                            <input type="checkbox" class="checkbox checkbox-primary" \n                                   name="debrid[${e}].unpack_rar" id="debrid[${e}].unpack_rar">\n                             <div>\n                                <span class="font-medium">Unpack RAR</span>\n                                <div class="label-text-alt">Preprocess RAR files</div>\n                            </div>\n                        </label>\n                    </div>\n                </div>\n        </div>\n    `}addDirectory(e,t={}){this.debridDirectoryCounts[e]||(this.debridDirectoryCounts[e]=0);const n=this.debridDirectoryCounts[e],a=document.getElementById(`debrid[${e}].directories`),r=this.getDirectoryTemplate(e,n);a.insertAdjacentHTML("beforeend",r);const i=`${e}-${n}`;if(this.directoryFilterCounts[i]=0,t.name){const a=document.querySelector(`[name="debrid[${e}].directory[${n}].name"]`);a&&(a.value=t.name)}return this.debridDirectoryCounts[e]++,n}getDirectoryTemplate(e,t){return`\n            <div class="card bg-base-200 border border-base-300 directory-item">\n                <div class="card-body">\n                    <div class="flex justify-between items-start mb-4">\n                        <h5 class="text-lg font-medium">Virtual Directory</h5>\n                        <button type="button" class="btn btn-error btn-xs" onclick="this.closest('.directory-item').remove();">\n                            <i class="bi bi-trash"></i>\n                        </button>\n                    </div>\n\n                    <div class="fieldset mb-4">\n                        <label class="label">\n                            <span class=" font-medium">Directory Name</span>\n                        </label>\n                        <input type="text" class="input webdav-field"\n                               name="debrid[${e}].directory[${t}].name"\n                               placeholder="Movies, TV Shows, Collections, etc.">\n                    </div>\n\n                    <div class="space-y-4">\n                        <div class="flex justify-between items-center">\n                            <h6 class="font-medium flex items-center">\n                                Filters\n                                <button type="button" class="btn btn-ghost btn-xs ml-2" onclick="configManager.showFilterHelp();">\n                                    <i class="bi bi-question-circle"></i>\n                                </button>\n                            </h6>\n                        </div>\n\n                        <div class="filters-container space-y-2" id="debrid[${e}].directory[${t}].filters">\n                        </div>\n\n                        <div class="flex flex-wrap gap-2">\n                            <div class="dropdown">\n                                <div tabindex="0" role="button" class="btn btn-outline btn-sm">\n                                    <i class="bi bi-plus mr-1"></i>Text Filter\n                                    <i class="bi bi-chevron-down ml-1"></i>\n                                </div>\n                                <ul tabindex="0" class="dropdown-content menu bg-base-100 rounded-box z-[1] w-48 p-2 shadow">\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'include');">Include</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'exclude');">Exclude</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'starts_with');">Starts With</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'not_starts_with');">Not Starts With</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'ends_with');">Ends With</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'not_ends_with');">Not Ends With</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'exact_match');">Exact Match</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'not_exact_match');">Not Exact Match</a></li>\n                                </ul>\n                            </div>\n\n                            <div class="dropdown">\n                                <div tabindex="0" role="button" class="btn btn-outline btn-sm">\n                                    <i class="bi bi-code mr-1"></i>Regex Filter\n                                    <i class="bi bi-chevron-down ml-1"></i>\n                                </div>\n                                <ul tabindex="0" class="dropdown-content menu bg-base-100 rounded-box z-[1] w-48 p-2 shadow">\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'regex');">Regex Match</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'not_regex');">Regex Doesn't Match</a></li>\n                                </ul>\n                            </div>\n\n                            <div class="dropdown">\n                                <div tabindex="0" role="button" class="btn btn-outline btn-sm">\n                                    <i class="bi bi-hdd mr-1"></i>Size Filter\n                                    <i class="bi bi-chevron-down ml-1"></i>\n                                </div>\n                                <ul tabindex="0" class="dropdown-content menu bg-base-100 rounded-box z-[1] w-48 p-2 shadow">\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'size_gt');">Size Greater Than</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'size_lt');">Size Less Than</a></li>\n                                </ul>\n                            </div>\n\n                            <button type="button" class="btn btn-outline btn-sm" onclick="configManager.addFilter(${e}, ${t}, 'last_added');">\n                                <i class="bi bi-clock mr-1"></i>Last Added Filter\n                            </button>\n                        </div>\n                    </div>\n                </div>\n            </div>\n        `}addFilter(e,t,n,a=""){const r=`${e}-${t}`;this.directoryFilterCounts[r]||(this.directoryFilterCounts[r]=0);const i=this.directoryFilterCounts[r],s=document.getElementById(`debrid[${e}].directory[${t}].filters`);if(s){const l=this.getFilterTemplate(e,t,i,n);if(s.insertAdjacentHTML("beforeend",l),a){const n=s.querySelector(`[name="debrid[${e}].directory[${t}].filter[${i}].value"]`);n&&(n.value=a)}this.directoryFilterCounts[r]++}}getFilterTemplate(e,t,n,a){const r=this.getFilterConfig(a);return`\n            <div class="filter-item flex items-center gap-3 p-3 bg-base-100 rounded-lg border border-base-300">\n                <div class="badge ${r.badgeClass} badge-sm">\n                    ${r.label}\n                </div>\n                <input type="hidden"\n                       name="debrid[${e}].directory[${t}].filter[${n}].type"\n                       value="${a}">\n                <div class="flex-1">\n                    <input type="text" \n                           class="input input-sm w-full webdav-field"\n                           name="debrid[${e}].directory[${t}].filter[${n}].value"\n                           placeholder="${r.placeholder}">\n                </div>\n                <button type="button" class="btn btn-error btn-xs" onclick="this.closest('.filter-item').remove();">\n                    <i class="bi bi-x"></i>\n                </button>\n            </div>\n        `}getFilterConfig(e){return{include:{label:"Include",placeholder:"Text that should be included in filename",badgeClass:"badge-primary"},exclude:{label:"Exclude",placeholder:"Text that should not be in filename",badgeClass:"badge-error"},regex:{label:"Regex Match",placeholder:"Regular expression pattern",badgeClass:"badge-warning"},not_regex:{label:"Regex Not Match",placeholder:"Regular expression pattern that should not match",badgeClass:"badge-error"},exact_match:{label:"Exact Match",placeholder:"Exact text to match",badgeClass:"badge-primary"},not_exact_match:{label:"Not Exact Match",placeholder:"Exact text that should not match",badgeClass:"badge-error"},starts_with:{label:"Starts With",placeholder:"Text that filename starts with",badgeClass:"badge-primary"},not_starts_with:{label:"Not Starts With",placeholder:"Text that filename should not start with",badgeClass:"badge-error"},ends_with:{label:"Ends With",placeholder:"Text that filename ends with",badgeClass:"badge-primary"},not_ends_with:{label:"Not Ends With",placeholder:"Text that filename should not end with",badgeClass:"badge-error"},size_gt:{label:"Size Greater Than",placeholder:"Size in bytes, KB, MB, GB (e.g. 700MB)",badgeClass:"badge-success"},size_lt:{label:"Size Less Than",placeholder:"Size in bytes, KB, MB, GB (e.g. 700MB)",badgeClass:"badge-warning"},last_added:{label:"Added in the last",placeholder:"Time duration (e.g. 24h, 7d, 30d)",badgeClass:"badge-info"}}[e]||{label:e.replace(/_/g," ").replace(/\b\w/g,e=>e.toUpperCase()),placeholder:"Filter value",badgeClass:"badge-ghost"}}showFilterHelp(){const e=document.createElement("dialog");e.className="modal",e.innerHTML='\n            <div class="modal-box max-w-2xl">\n                <form method="dialog">\n                    <button class="btn btn-sm btn-circle btn-ghost absolute right-2 top-2">✕</button>\n                </form>\n                <h3 class="font-bold text-lg mb-4">Directory Filter Types</h3>\n                <div class="space-y-4">\n                    <div>\n                        <h4 class="font-semibold text-primary">Text Filters</h4>\n                        <ul class="list-disc list-inside text-sm space-y-1 ml-4">\n                            <li><strong>Include/Exclude:</strong> Simple text inclusion/exclusion</li>\n                            <li><strong>Starts/Ends With:</strong> Matches beginning or end of filename</li>\n                            <li><strong>Exact Match:</strong> Match the entire filename</li>\n                        </ul>\n                    </div>\n                    <div>\n                        <h4 class="font-semibold text-warning">Regex Filters</h4>\n                        <ul class="list-disc list-inside text-sm space-y-1 ml-4">\n                            <li><strong>Regex:</strong> Use regular expressions for complex patterns</li>\n                            <li>Example: <code>.*\\.mkv$</code> matches files ending with .mkv</li>\n                        </ul>\n                    </div>\n                    <div>\n                        <h4 class="font-semibold text-success">Size Filters</h4>\n                        <ul class="list-disc list-inside text-sm space-y-1 ml-4">\n                            <li><strong>Size Greater/Less Than:</strong> Filter by file size</li>\n                            <li>Examples: 1GB, 500MB, 2.5GB</li>\n                        </ul>\n                    </div>\n                    <div>\n                        <h4 class="font-semibold text-info">Time Filters</h4>\n                        <ul class="list-disc list-inside text-sm space-y-1 ml-4">\n                            <li><strong>Last Added:</strong> Show only recently added content</li>\n                            <li>Examples: 24h, 7d, 30d</li>\n                        </ul>\n                    </div>\n                    <div class="alert alert-info">\n                        <i class="bi bi-info-circle"></i>\n                        <span>Negative filters (Not...) will exclude matches instead of including them.</span>\n                    </div>\n                </div>\n            </div>\n        ',document.body.appendChild(e),e.showModal(),e.addEventListener("close",()=>{document.body.removeChild(e)})}getDebridOptions(){const e=[];return document.querySelectorAll(".debrid-config").forEach(t=>{const n=t.getAttribute("data-index"),a=document.querySelector(`[name="debrid[${n}].name"]`);a&&a.value.trim()&&e.push(a.value.trim())}),e.map(e=>`<option value="${window.decypharrUtils.escapeHtml(e)}">${window.decypharrUtils.escapeHtml(e)}</option>`).join("")}updateArrDebridDropdowns(){const e=document.querySelectorAll(".arr-config"),t=this.getDebridOptions();e.forEach(e=>{const n=e.getAttribute("data-index"),a=document.querySelector(`[name="arr[${n}].selected_debrid"]`);if(a){const e=a.value;a.innerHTML=`<option value="">Auto Select</option>${t}`,e&&(a.value=e)}})}addArrConfig(e={}){const t=this.getArrTemplate(this.arrCount,e);this.refs.arrConfigs.insertAdjacentHTML("beforeend",t),Object.keys(e).length>0&&this.populateArrData(this.arrCount,e),this.arrCount++}populateArrData(e,t){Object.entries(t).forEach(([t,n])=>{const a=document.querySelector(`[name="arr[${e}].${t}"]`);a&&("checkbox"===a.type?a.checked=n:a.value=n)})}getArrTemplate(e,t={}){const n="auto"===t.source,a=this.getDebridOptions();return`\n            <div class="card bg-base-100 border border-base-300 shadow-sm arr-config ${n?"border-info":""}" data-index="${e}">\n                <div class="card-body p-4 gap-4">\n                    <div class="flex items-start justify-between gap-3">\n                        <h3 class="card-title text-base leading-tight min-w-0">\n                            <i class="bi bi-collection text-warning shrink-0"></i>\n                            <span class="min-w-0 break-words">Arr Service #${e+1}</span>\n                            ${n?'<span class="badge badge-info badge-sm shrink-0">Auto-detected</span>':""}\n                        </h3>\n                        ${n?"":'\n                            <button type="button" class="btn btn-error btn-sm btn-square shrink-0" onclick="this.closest(\'.arr-config\').remove();">\n                                <i class="bi bi-trash"></i>\n                            </button>\n                        '}\n                    </div>\n\n                    <input type="hidden" name="arr[${e}].source" value="${t.source||""}">\n\n                    <div class="grid grid-cols-1 gap-3">\n                        <div>\n                            <label class="label" for="arr[${e}].name">\n                                <span class="font-medium">Service Name</span>\n                            </label>\n                            <input type="text" class="input ${n?"input-disabled":""}"\n                                   name="arr[${e}].name" id="arr[${e}].name"\n                                   ${n?"readonly":"required"}\n                                   placeholder="sonarr, radarr, etc.">\n                        </div>\n\n                        <div>\n                            <label class="label" for="arr[${e}].host">\n                                <span class="font-medium">Host URL</span>\n                            </label>\n                            <input type="url" class="input ${n?"input-disabled":""}"\n                                   name="arr[${e}].host" id="arr[${e}].host"\n                                   ${n?"readonly":"required"}\n                                   placeholder="http://localhost:8989">\n                        </div>\n\n                        <div>\n                            <label class="label" for="arr[${e}].token">\n                                <span class="font-medium">API Token</span>\n                            </label>\n                            <div class="password-toggle-container">\n                                <input type="password" class="input input-has-toggle ${n?"input-disabled":""}"\n                                       name="arr[${e}].token" id="arr[${e}].token"\n                                       ${n?"readonly":"required"}>\n                                <button type="button" class="password-toggle-btn ${n?"opacity-50 cursor-not-allowed":""}"\n                                        ${n?"disabled":""}>\n                                    <i class="bi bi-eye" id="arr[${e}].token_icon"></i>\n                                </button>\n                            </div>\n                        </div>\n\n                        <div>\n                            <label class="label" for="arr[${e}].selected_debrid">\n                                <span class="font-medium">Preferred Provider</span>\n                            </label>\n                            <select class="select w-full" name="arr[${e}].selected_debrid" id="arr[${e}].selected_debrid">\n                                <option value="">Auto Select</option>\n                                ${a}\n                            </select>\n                            <span class="text-sm opacity-70">Which debrid service this Arr should prefer</span>\n                        </div>\n                    </div>\n\n                    <div class="grid grid-cols-1 md:grid-cols-2 gap-2">\n                        <div class="rounded-box bg-base-200/50 px-3 py-2">\n                            <label class="label cursor-pointer justify-start gap-2 p-0">\n                                <input type="checkbox" class="checkbox checkbox-sm checkbox-primary"\n                                       name="arr[${e}].skip_repair" id="arr[${e}].skip_repair">\n                                <span class="text-sm leading-tight">Skip Repair</span>\n                            </label>\n                        </div>\n\n                        <div class="rounded-box bg-base-200/50 px-3 py-2">\n                            <label class="label cursor-pointer justify-start gap-2 p-0">\n                                <input type="checkbox" class="checkbox checkbox-sm checkbox-primary"\n                                       name="arr[${e}].download_uncached" id="arr[${e}].download_uncached">\n                                <span class="text-sm leading-tight">Download Uncached</span>\n                            </label>\n                        </div>\n                    </div>\n                </div>\n            </div>\n        `}async saveConfiguration(e){e.preventDefault(),this.refs.loadingOverlay.classList.remove("hidden");try{const e=this.collectFormData(),t=this.validateConfiguration(e);if(!t.valid)throw new Error(t.errors.join("\n"));const n=await window.decypharrUtils.fetcher("/api/config",{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify(e)});if(!n.ok){const e=await n.text();throw new Error(e||"Failed to save configuration")}let a=!0;try{a=!1!==(await n.json()).restarted}catch(e){}a?(window.decypharrUtils.createToast("Configuration saved successfully! Services are restarting...","success"),setTimeout(()=>{window.location.reload()},2e3)):(window.decypharrUtils.createToast("Configuration saved and applied.","success"),this.refs.loadingOverlay.classList.add("hidden"))}catch(e){console.error("Error saving configuration:",e),window.decypharrUtils.createToast(`Error saving configuration: ${e.message}`,"error"),this.refs.loadingOverlay.classList.add("hidden")}}validateConfiguration(e){const t=[];return e.debrids.forEach((e,n)=>{e.name&&e.api_key&&e.provider||t.push(`Debrid service #${n+1}: Name, API key are required`)}),e.arrs.forEach((e,n)=>{e.name&&e.host||t.push(`Arr service #${n+1}: Name and host are required`),e.host&&!this.isValidUrl(e.host)&&t.push(`Arr service #${n+1}: Invalid host URL format`)}),""===e.mount.type&&t.push("Mount type is required when "),""===e.mount.mount_path&&t.push("Mount path is required when Rclone is enabled"),e.repair?.enabled&&!e.repair.schedule&&t.push("Repair: schedule is required when Repair is enabled"),{valid:0===t.length,errors:t}}isValidUrl(e){try{return new URL(e),!0}catch(e){return!1}}collectFormData(){return{log_level:document.querySelector('[name="log_level"]').value,url_base:document.querySelector('[name="url_base"]').value,bind_address:document.querySelector('[name="bind_address"]').value,app_url:document.querySelector('[name="app_url"]').value,port:document.querySelector('[name="port"]').value,allowed_file_types:document.querySelector('[name="allowed_file_types"]').value.split(",").map(e=>e.trim()).filter(Boolean),min_file_size:document.querySelector('[name="min_file_size"]').value,max_file_size:document.querySelector('[name="max_file_size"]').value,remove_stalled_after:document.querySelector('[name="remove_stalled_after"]').value||"10m",nzb_user_agent:document.querySelector('[name="nzb_user_agent"]').value,download_folder:document.querySelector('[name="download_folder"]').value,refresh_interval:document.querySelector('[name="refresh_interval"]').value||"30s",default_download_action:document.querySelector('[name="default_download_action"]')?.value||"symlink",max_active_downloads:parseInt(document.querySelector('[name="max_active_downloads"]').value)||5,skip_pre_cache:document.querySelector('[name="skip_pre_cache"]').checked,always_rm_tracker_urls:document.querySelector('[name="always_rm_tracker_urls"]').checked,folder_naming:document.querySelector('[name="folder_naming"]')?.value||"",disable_webdav:document.querySelector('[name="disable_webdav"]').checked,refresh_dirs:document.querySelector('[name="refresh_dirs"]')?.value||"",custom_folders:this.collectVirtualFolders(),debrids:this.collectDebridConfigs(),arrs:this.collectArrConfigs(),queue_cleanup:this.collectQueueCleanup(),mount:this.collectMountConfig(),usenet:this.collectUsenetConfig(),notifications:this.collectNotificationsConfig(),repair:this.collectRepairConfig()}}collectNotificationsConfig(){const e=document.getElementById("notifications.enabled"),t=document.getElementById("notifications.webhook_url"),n=document.getElementById("notifications.callback_url"),a=[];return document.querySelectorAll('input[name="notifications.events[]"]').forEach(e=>{e.checked&&a.push(e.value)}),{enabled:!!e&&e.checked,webhook_url:t?t.value:"",callback_url:n?n.value:"",events:a}}collectUsenetConfig(){const e=[];return this.refs.usenetProviders.querySelectorAll(".usenet-provider").forEach(t=>{const n=t.getAttribute("data-index"),a=e=>t.querySelector(`[name="usenet.providers[${n}].${e}"]`),r=a("host"),i=a("port"),s=a("username"),l=a("password"),o=a("backbone"),d=a("ssl"),c=a("max_connections"),u=a("priority"),p=a("backup");if(!(r&&i&&s&&l&&o&&d&&c&&u))return;const m={host:r.value,port:parseInt(i.value)||119,username:s.value,password:l.value,backbone:o.value.trim(),ssl:d.checked,max_connections:parseInt(c.value)||100,priority:parseInt(u.value)||0,backup:!!p&&p.checked};m.host&&m.username&&m.password&&e.push(m)}),{providers:e,max_connections:parseInt(document.querySelector('[name="usenet.max_connections"]')?.value)||15,processing_max_connections:parseInt(document.querySelector('[name="usenet.processing_max_connections"]')?.value)||parseInt(document.querySelector('[name="usenet.max_connections"]')?.value)||15,read_ahead:document.querySelector('[name="usenet.read_ahead"]').value||"16MB",processing_timeout:document.querySelector('[name="usenet.processing_timeout"]')?.value||"5m",availability_sample_percent:parseInt(document.querySelector('[name="usenet.availability_sample_percent"]')?.value)||10,import_availability_sample_percent:parseInt(document.querySelector('[name="usenet.import_availability_sample_percent"]')?.value)||1,disk_buffer_path:document.querySelector('[name="usenet.disk_buffer_path"]')?.value||"",buffer_memory:document.querySelector('[name="usenet.buffer_memory"]')?.value||""}}collectDebridConfigs(){const e=[];return this.refs.debridConfigs.querySelectorAll(".debrid-config").forEach(t=>{const n=t.getAttribute("data-index"),a=e=>t.querySelector(`[name="debrid[${n}].${e}"]`),r=a("name"),i=a("provider"),s=a("api_key"),l=a("rate_limit"),o=a("repair_rate_limit"),d=a("download_rate_limit"),c=a("minimum_free_slot"),u=a("proxy"),p=a("download_uncached"),m=a("unpack_rar"),b=a("add_samples"),_=a("user_agent"),h=a("download_api_keys"),v=a("torrents_refresh_interval"),f=a("download_links_refresh_interval"),g=a("auto_expire_links_after");if(!(r&&i&&s&&l&&o&&d&&c&&u&&p&&m&&b&&_&&v&&f&&g))return;const y={name:r.value,provider:i.value,api_key:s.value,rate_limit:l.value,repair_rate_limit:o.value,download_rate_limit:d.value,minimum_free_slot:parseInt(c.value)||0,proxy:u.value,download_uncached:p.checked,unpack_rar:m.checked,add_samples:b.checked,user_agent:_.value};h&&h.value.trim()&&(y.download_api_keys=h.value.split("\n").map(e=>e.trim()).filter(e=>e.length>0)),y.torrents_refresh_interval=v.value,y.download_links_refresh_interval=f.value,y.auto_expire_links_after=g.value,y.name&&y.api_key&&y.provider&&e.push(y)}),e}collectArrConfigs(){const e=[];return this.refs.arrConfigs.querySelectorAll(".arr-config").forEach(t=>{const n=t.getAttribute("data-index"),a=e=>t.querySelector(`[name="arr[${n}].${e}"]`),r=a("name"),i=a("host"),s=a("token"),l=a("skip_repair"),o=a("download_uncached"),d=a("selected_debrid"),c=a("source");if(!(r&&i&&s&&l&&o&&d&&c))return;const u={name:r.value,host:i.value,token:s.value,skip_repair:l.checked,download_uncached:o.checked,selected_debrid:d.value,source:c.value};u.name&&u.host&&e.push(u)}),e}populateQueueCleanup(e){const t=document.getElementById("queueCleanupCatalog"),n=document.getElementById("queueCleanupCustom");if(!t||!n)return;const a=e&&Array.isArray(e.rules)?e.rules:[],r={},i=[];a.forEach(e=>{e&&e.id?r[e.id]=e.action||"":e&&(e.match||"").trim()&&i.push(e)});const s=window.decypharrUtils.escapeHtml;t.innerHTML=this.queueCleanupCatalog.map(e=>{const t=e.id in r?r[e.id]:"";return`\n                <div class="grid grid-cols-1 md:grid-cols-5 gap-2 md:items-center px-3 py-3 even:bg-base-200/40" data-rule-id="${e.id}">\n                    <span class="md:col-span-3 text-sm leading-snug min-w-0">${s(e.label)}</span>\n                    <select class="md:col-span-2 select select-sm w-full queue-cleanup-action">\n                        ${this.queueCleanupActionOptions(t)}\n                    </select>\n                </div>`}).join(""),n.innerHTML="",i.forEach(e=>this.addQueueCleanupCustomRow(e.match,e.action))}addQueueCleanupCustomRow(e="",t=""){const n=document.getElementById("queueCleanupCustom");if(!n)return;const a=window.decypharrUtils.escapeHtml,r=document.createElement("div");r.className="grid grid-cols-1 md:grid-cols-12 gap-2 queue-cleanup-custom-row",r.innerHTML=`\n            <input type="text" class="md:col-span-7 input input-sm w-full min-w-0 queue-cleanup-match"\n                   placeholder="text in status message, e.g. stalled"\n                   value="${a(e)}">\n            <select class="md:col-span-4 select select-sm w-full queue-cleanup-action">\n                ${this.queueCleanupActionOptions(t)}\n            </select>\n            <button type="button" class="md:col-span-1 btn btn-sm btn-square btn-ghost text-error queue-cleanup-remove" aria-label="Remove custom queue cleanup rule" title="Remove rule">\n                <i class="bi bi-trash"></i>\n            </button>`,r.querySelector(".queue-cleanup-remove").addEventListener("click",()=>r.remove()),n.appendChild(r)}collectQueueCleanup(){const e=[];return document.querySelectorAll("#queueCleanupCatalog [data-rule-id]").forEach(t=>{const n=t.getAttribute("data-rule-id"),a=t.querySelector(".queue-cleanup-action")?.value||"";e.push({id:n,action:a})}),document.querySelectorAll("#queueCleanupCustom .queue-cleanup-custom-row").forEach(t=>{const n=(t.querySelector(".queue-cleanup-match")?.value||"").trim();if(!n)return;const a=t.querySelector(".queue-cleanup-action")?.value||"";e.push({match:n,action:a})}),{rules:e}}collectMountConfig(){const e=document.querySelector('input[name="mount.type"]:checked');return{type:e?e.value:"none",mount_path:document.querySelector('[name="mount.mount_path"]').value,dfs:this.collectDFSConfig(),rclone:this.collectRcloneConfig(),external_rclone:this.collectExternalRclone()}}collectExternalRclone(){return{rc_url:document.querySelector('[name="mount.external_rclone.rc_url"]')?.value||"",rc_username:document.querySelector('[name="mount.external_rclone.rc_username"]')?.value||"",rc_password:document.querySelector('[name="mount.external_rclone.rc_password"]')?.value||""}}collectRcloneConfig(){const e=(e,t="")=>{const n=document.querySelector(`[name="mount.rclone.${e}"]`);if(!n)return t;if("checkbox"===n.type)return n.checked;if("number"===n.type){const e=parseInt(n.value);return isNaN(e)?0:e}return n.value||t};return{port:e("port","5572"),buffer_size:e("buffer_size"),bw_limit:e("bw_limit"),cache_dir:e("cache_dir"),transfers:e("transfers",8),vfs_cache_mode:e("vfs_cache_mode","off"),vfs_cache_max_age:e("vfs_cache_max_age","1h"),vfs_cache_max_size:e("vfs_cache_max_size"),vfs_cache_poll_interval:e("vfs_cache_poll_interval","1m"),vfs_read_chunk_size:e("vfs_read_chunk_size",""),vfs_read_chunk_size_limit:e("vfs_read_chunk_size_limit","off"),vfs_cache_min_free_space:e("vfs_cache_min_free_space",""),vfs_fast_fingerprint:e("vfs_fast_fingerprint",!1),vfs_read_chunk_streams:e("vfs_read_chunk_streams",0),use_mmap:e("use_mmap",!1),async_read:e("async_read",!0),uid:e("uid",0),gid:e("gid",0),umask:e("umask",""),vfs_read_ahead:e("vfs_read_ahead",""),attr_timeout:e("attr_timeout","1s"),dir_cache_time:e("dir_cache_time","5m"),no_modtime:e("no_modtime",!1),no_checksum:e("no_checksum",!1),log_level:e("log_level","INFO")}}collectDFSConfig(){const e=(e,t="")=>{const n=document.querySelector(`[name="mount.dfs.${e}"]`);if(!n)return t;if("checkbox"===n.type)return n.checked;if("number"===n.type){const e=parseInt(n.value);return isNaN(e)?0:e}return n.value||t};return{cache_dir:e("cache_dir"),disk_cache_size:e("disk_cache_size"),buffer_memory:e("buffer_memory"),cache_expiry:e("cache_expiry"),cache_cleanup_interval:e("cache_cleanup_interval"),chunk_size:e("chunk_size"),read_ahead_size:e("read_ahead_size"),daemon_timeout:e("daemon_timeout"),uid:e("uid",0),gid:e("gid",0),umask:e("umask")}}setupMagnetHandler(){if(window.registerMagnetLinkHandler=()=>{if("registerProtocolHandler"in navigator)try{navigator.registerProtocolHandler("magnet",`${window.location.origin}${window.urlBase}download?magnet=%s`,"Decypharr"),localStorage.setItem("magnetHandler","true");const e=document.getElementById("registerMagnetLink");e.innerHTML='<i class="bi bi-check-circle mr-2"></i>Magnet Handler Registered',e.classList.remove("btn-primary"),e.classList.add("btn-success"),e.disabled=!0,window.decypharrUtils.createToast("Magnet link handler registered successfully")}catch(e){console.error("Failed to register magnet link handler:",e),window.decypharrUtils.createToast("Failed to register magnet link handler","error")}else window.decypharrUtils.createToast("Magnet link registration not supported in this browser","warning")},"true"===localStorage.getItem("magnetHandler")){const e=document.getElementById("registerMagnetLink");e&&(e.innerHTML='<i class="bi bi-check-circle mr-2"></i>Magnet Handler Registered',e.classList.remove("btn-primary"),e.classList.add("btn-success"),e.disabled=!0)}}populateAPIToken(e){const t=document.getElementById("api-token-display");t&&(t.value=e.api_token||"****");const n=document.getElementById("auth-username");n&&e.auth_username&&(n.value=e.auth_username)}populateVirtualFolders(e){e&&Object.entries(e).forEach(([e,t])=>{this.addVirtualFolder(e,t.filters)})}addVirtualFolder(e="",t={}){const n=this.virtualFolderCount++,a=Object.entries(t),r=`\n            <div class="card bg-base-200 shadow-sm" data-virtual-folder="${n}">\n                <div class="card-body p-4">\n                    <div class="flex justify-between items-start mb-4">\n                        <h4 class="font-semibold text-lg">Virtual Folder</h4>\n                        <button type="button" class="btn btn-ghost btn-sm btn-circle" onclick="configManager.removeVirtualFolder(${n});">\n                            <i class="bi bi-x-lg"></i>\n                        </button>\n                    </div>\n\n                    <div class="space-y-4">\n                        <div>\n                            <label class="label">\n                                <span class="font-medium">Folder Name</span>\n                            </label>\n                            <input type="text"\n                                   class="input input-bordered w-full"\n                                   name="virtual_folder_${n}_name"\n                                   value="${window.decypharrUtils.escapeHtml(e)}"\n                                   placeholder="e.g., Movies, TV Shows, 4K"\n                                   required>\n                            <span class="text-sm opacity-70">This folder will appear in your mount</span>\n                        </div>\n\n                        <div>\n                            <label class="label">\n                                <span class="font-medium">Filters</span>\n                                <button type="button" class="btn btn-xs btn-primary" onclick="configManager.addVirtualFolderFilter(${n});">\n                                    <i class="bi bi-plus"></i> Add Filter\n                                </button>\n                            </label>\n                            <div class="space-y-2" id="virtual_folder_${n}_filters">\n                                ${a.length>0?a.map(([e,t],a)=>`\n                                    <div class="flex gap-2" data-filter-index="${a}">\n                                        <input type="text"\n                                               class="input input-bordered input-sm flex-1"\n                                               name="virtual_folder_${n}_filter_key_${a}"\n                                               value="${window.decypharrUtils.escapeHtml(e)}"\n                                               placeholder="Filter key (e.g., name, category)">\n                                        <input type="text"\n                                               class="input input-bordered input-sm flex-1"\n                                               name="virtual_folder_${n}_filter_value_${a}"\n                                               value="${window.decypharrUtils.escapeHtml(t)}"\n                                               placeholder="Filter value (e.g., *movie*, tv)">\n                                        <button type="button" class="btn btn-sm btn-ghost btn-circle" onclick="configManager.removeVirtualFolderFilter(${n}, ${a});">\n                                            <i class="bi bi-trash"></i>\n                                        </button>\n                                    </div>\n                                `).join(""):'\n                                    <div class="text-sm opacity-70">No filters. Click "Add Filter" to add one.</div>\n                                '}\n                            </div>\n                            <span class="text-sm opacity-70">Filters use wildcards: * for any characters, ? for single character</span>\n                        </div>\n                    </div>\n                </div>\n            </div>\n        `;this.refs.virtualFoldersContainer.insertAdjacentHTML("beforeend",r)}addVirtualFolderFilter(e){const t=document.getElementById(`virtual_folder_${e}_filters`),n=t.querySelectorAll("[data-filter-index]").length,a=t.querySelector(".text-sm.opacity-70");a&&a.remove();const r=`\n            <div class="flex gap-2" data-filter-index="${n}">\n                <input type="text"\n                       class="input input-bordered input-sm flex-1"\n                       name="virtual_folder_${e}_filter_key_${n}"\n                       placeholder="Filter key (e.g., name, category)">\n                <input type="text"\n                       class="input input-bordered input-sm flex-1"\n                       name="virtual_folder_${e}_filter_value_${n}"\n                       placeholder="Filter value (e.g., *movie*, tv)">\n                <button type="button" class="btn btn-sm btn-ghost btn-circle" onclick="configManager.removeVirtualFolderFilter(${e}, ${n});">\n                    <i class="bi bi-trash"></i>\n                </button>\n            </div>\n        `;t.insertAdjacentHTML("beforeend",r)}removeVirtualFolderFilter(e,t){const n=document.getElementById(`virtual_folder_${e}_filters`),a=n.querySelector(`[data-filter-index="${t}"]`);a&&a.remove();0===n.querySelectorAll("[data-filter-index]").length&&(n.innerHTML='<div class="text-sm opacity-70">No filters. Click "Add Filter" to add one.</div>')}removeVirtualFolder(e){const t=document.querySelector(`[data-virtual-folder="${e}"]`);t&&confirm("Are you sure you want to remove this virtual folder?")&&t.remove()}collectVirtualFolders(){const e={};return this.refs.virtualFoldersContainer.querySelectorAll("[data-virtual-folder]").forEach(t=>{const n=t.getAttribute("data-virtual-folder"),a=t.querySelector(`[name="virtual_folder_${n}_name"]`),r=a?a.value.trim():"";if(r){const t={};document.getElementById(`virtual_folder_${n}_filters`).querySelectorAll("[data-filter-index]").forEach(e=>{const a=e.getAttribute("data-filter-index"),r=e.querySelector(`[name="virtual_folder_${n}_filter_key_${a}"]`),i=e.querySelector(`[name="virtual_folder_${n}_filter_value_${a}"]`),s=r?r.value.trim():"",l=i?i.value.trim():"";s&&l&&(t[s]=l)}),e[r]={filters:t}}}),e}populateUsenetSettings(e){e.providers&&Array.isArray(e.providers)&&e.providers.forEach(e=>this.addUsenetProvider(e));const t={max_connections:e.max_connections,processing_max_connections:e.processing_max_connections,read_ahead:e.read_ahead,processing_timeout:e.processing_timeout,availability_sample_percent:e.availability_sample_percent,import_availability_sample_percent:e.import_availability_sample_percent,disk_buffer_path:e.disk_buffer_path,buffer_memory:e.buffer_memory};Object.entries(t).forEach(([e,t])=>{const n=document.getElementsByName(`usenet.${e}`)[0];n&&void 0!==t&&(n.value=t)})}addUsenetProvider(e={}){const t=this.getUsenetProviderTemplate(this.usenetProviderCount,e);this.refs.usenetProviders.insertAdjacentHTML("beforeend",t),Object.keys(e).length>0&&this.populateUsenetProviderData(this.usenetProviderCount,e),this.usenetProviderCount++}populateUsenetProviderData(e,t){Object.entries(t).forEach(([t,n])=>{const a=document.querySelector(`[name="usenet.providers[${e}].${t}"]`);a&&("checkbox"===a.type?a.checked=n:a.value=n)})}getUsenetProviderTemplate(e,t={}){return`\n        <div class="card bg-base-200 border border-base-300 usenet-provider" data-index="${e}">\n            <div class="card-body">\n                <div class="flex justify-between items-start mb-4">\n                    <h4 class="font-bold text-lg">\n                        <i class="bi bi-server mr-2"></i>\n                        Provider #${e+1}\n                    </h4>\n                    <button type="button" class="btn btn-error btn-sm" onclick="this.closest('.usenet-provider').remove();">\n                        <i class="bi bi-trash"></i>\n                    </button>\n                </div>\n\n                <div class="grid grid-cols-1 lg:grid-cols-2 gap-4">\n                    <div>\n                        <label class="label" for="usenet_provider_${e}_host">\n                            <span class="font-medium">Server Host</span>\n                        </label>\n                        <input type="text" class="input w-full"\n                               name="usenet.providers[${e}].host"\n                               id="usenet_provider_${e}_host"\n                               placeholder="news.usenetexpress.com"\n                               required>\n                        <span class="text-sm opacity-70">NNTP server hostname</span>\n                    </div>\n                    <div>\n                        <label class="label" for="usenet_provider_${e}_username">\n                            <span class="font-medium">Username</span>\n                        </label>\n                        <input type="text" class="input w-full"\n                               name="usenet.providers[${e}].username"\n                               id="usenet_provider_${e}_username"\n                               autocomplete="off">\n                        <span class="text-sm opacity-70">NNTP username</span>\n                    </div>\n                    <div>\n                        <label class="label" for="usenet_provider_${e}_password">\n                            <span class="font-medium">Password</span>\n                        </label>\n                        <div class="password-toggle-container">\n                            <input type="password" class="input input-has-toggle"\n                                   name="usenet.providers[${e}].password"\n                                   id="usenet_provider_${e}_password"\n                                   autocomplete="new-password">\n                            <button type="button" class="password-toggle-btn">\n                                <i class="bi bi-eye" id="usenet_provider_${e}_password_icon"></i>\n                            </button>\n                        </div>\n                        <span class="text-sm opacity-70">NNTP password</span>\n                    </div>\n\n                    <div>\n                        <label class="label" for="usenet_provider_${e}_port">\n                            <span class="font-medium">Port</span>\n                        </label>\n                        <input type="number" class="input w-full"\n                               name="usenet.providers[${e}].port"\n                               id="usenet_provider_${e}_port"\n                               placeholder="119"\n                               min="1" max="65535"\n                               value="119">\n                        <span class="text-sm opacity-70">NNTP port (563 for SSL, 119 for plain)</span>\n                    </div>\n                    <div>\n                        <label class="label" for="usenet_provider_${e}_backbone">\n                            <span class="font-medium">Backbone</span>\n                        </label>\n                        <input type="text" class="input w-full"\n                               name="usenet.providers[${e}].backbone"\n                               id="usenet_provider_${e}_backbone"\n                               placeholder="Omicron">\n                        <span class="text-sm opacity-70">Optional shared article backbone for smarter 430 failover</span>\n                    </div>\n\n                    <div>\n                        <label class="label" for="usenet_provider_${e}_max_connections">\n                            <span class="font-medium">Max Connections</span>\n                        </label>\n                        <input type="number" class="input w-full"\n                               name="usenet.providers[${e}].max_connections"\n                               id="usenet_provider_${e}_max_connections">\n                        <span class="text-sm opacity-70">Max connections for this provider</span>\n                    </div>\n                    <div>\n                        <label class="label" for="usenet_provider_${e}_priority">\n                            <span class="font-medium">Priority</span>\n                        </label>\n                        <input type="number" class="input w-full"\n                               name="usenet.providers[${e}].priority"\n                               id="usenet_provider_${e}_priority">\n                        <span class="text-sm opacity-70">Priority for this provider (lower number = higher priority)</span>\n                    </div>\n                </div>\n\n                <div class="flex flex-wrap gap-4 mt-4">\n                    <label class="flex items-center gap-2 cursor-pointer">\n                        <input type="checkbox" class="checkbox checkbox-primary checkbox-sm"\n                               name="usenet.providers[${e}].ssl"\n                               id="usenet_provider_${e}_ssl">\n                        <span class="text-sm">Use SSL</span>\n                    </label>\n                    <label class="flex items-center gap-2 cursor-pointer"\n                           title="Only used when every non-backup provider is excluded (article not found, connection errors). Not used just because primary pools are busy — requests wait for a primary slot instead. Use this for block providers you only want to bill for completion.">\n                        <input type="checkbox" class="checkbox checkbox-warning checkbox-sm"\n                               name="usenet.providers[${e}].backup"\n                               id="usenet_provider_${e}_backup">\n                        <span class="text-sm">Backup provider (fallback only)</span>\n                    </label>\n                </div>\n            </div>\n        </div>\n        `}}
\ No newline at end of file
+class ConfigManager{constructor(){this.debridCount=0,this.arrCount=0,this.usenetProviderCount=0,this.debridDirectoryCounts={},this.directoryFilterCounts={},this.virtualFolderCount=0,this.refs={configForm:document.getElementById("configForm"),loadingOverlay:document.getElementById("loadingOverlay"),debridConfigs:document.getElementById("debridConfigs"),arrConfigs:document.getElementById("arrConfigs"),virtualFoldersContainer:document.getElementById("virtualFoldersContainer"),usenetProviders:document.getElementById("usenetProviders"),addDebridBtn:document.getElementById("addDebridBtn"),addArrBtn:document.getElementById("addArrBtn"),addVirtualFolderBtn:document.getElementById("addVirtualFolderBtn"),addUsenetProviderBtn:document.getElementById("addUsenetProviderBtn")},this.init()}init(){this.bindEvents(),this.loadConfiguration(),this.setupMagnetHandler(),this.checkIncompleteConfig()}checkIncompleteConfig(){const e=new URLSearchParams(window.location.search);if(e.has("inco")){const t=e.get("inco");window.decypharrUtils.createToast(`Incomplete configuration: ${t}`,"warning")}}bindEvents(){this.refs.configForm.addEventListener("submit",e=>this.saveConfiguration(e)),this.refs.addDebridBtn.addEventListener("click",()=>this.addDebridConfig()),this.refs.addArrBtn.addEventListener("click",()=>this.addArrConfig()),this.refs.addVirtualFolderBtn.addEventListener("click",()=>this.addVirtualFolder()),this.refs.addUsenetProviderBtn.addEventListener("click",()=>this.addUsenetProvider());const e=document.getElementById("addQueueCleanupRuleBtn");e&&e.addEventListener("click",()=>this.addQueueCleanupCustomRow())}get queueCleanupCatalog(){return[{id:"failed_download",label:"Failed download"},{id:"title_mismatch",label:"Title mismatch (automatic import not possible)"},{id:"matched_by_id",label:"Release matched to series/movie by ID"},{id:"unable_to_parse",label:"Unable to parse download"},{id:"no_eligible_files",label:"No files eligible for import"},{id:"episodes_missing",label:"Episodes missing / not imported from release"},{id:"file_empty",label:"Downloaded file is empty"},{id:"invalid_local_path",label:"Invalid local path (remote path mapping)"},{id:"not_grabbed",label:"Not grabbed by the arr / no category"}]}queueCleanupActionOptions(e){return[{value:"",label:"Ignore (leave in queue)"},{value:"import",label:"Force import"},{value:"blacklist",label:"Blacklist only"},{value:"blacklist_research",label:"Blacklist + research"}].map(t=>`<option value="${t.value}" ${t.value===(e||"")?"selected":""}>${t.label}</option>`).join("")}async loadConfiguration(){try{const e=await window.decypharrUtils.fetcher("/api/config");if(!e.ok)throw new Error("Failed to load configuration");const t=await e.json();this.loadedConfig=t,this.populateForm(t)}catch(e){console.error("Error loading configuration:",e),window.decypharrUtils.createToast("Error loading configuration","error")}}populateForm(e){this.populateGeneralSettings(e),this.populateDownloadSettings(e),e.debrids&&Array.isArray(e.debrids)&&e.debrids.forEach(e=>this.addDebridConfig(e)),e.usenet&&this.populateUsenetSettings(e.usenet),e.custom_folders&&this.populateVirtualFolders(e.custom_folders),e.arrs&&Array.isArray(e.arrs)&&e.arrs.forEach(e=>this.addArrConfig(e)),this.populateQueueCleanup(e.queue_cleanup),this.populateMountSettings(e.mount),this.populateAPIToken(e),this.populateNotificationSettings(e.notifications),this.populateRepairSettings(e.repair,e.arrs)}populateRepairSettings(e,t){const n=document.getElementById("repair.arrs");if(n){const a=new Set(e&&Array.isArray(e.arrs)?e.arrs:[]);n.innerHTML="";for(const e of t||[]){if(!e||!e.name)continue;const t=document.createElement("option");t.value=e.name,t.textContent=e.name,a.has(e.name)&&(t.selected=!0),n.appendChild(t)}}if(!e)return;const $=e=>document.getElementById(e);$("repair.enabled")&&($("repair.enabled").checked=!!e.enabled),$("repair.source")&&($("repair.source").value=e.source||"arr"),$("repair.schedule")&&($("repair.schedule").value=e.schedule||""),$("repair.recheck_interval")&&($("repair.recheck_interval").value=e.recheck_interval||""),$("repair.workers")&&($("repair.workers").value=e.workers||5),$("repair.nntp_connection_percent")&&($("repair.nntp_connection_percent").value=e.nntp_connection_percent||20),$("repair.strategy")&&($("repair.strategy").value=e.strategy||"per_entry"),$("repair.piece_verify")&&($("repair.piece_verify").checked=!!e.piece_verify),$("repair.piece_verify_samples")&&($("repair.piece_verify_samples").value=e.piece_verify_samples||4),$("repair.auto_repair")&&($("repair.auto_repair").checked=!!e.auto_repair),$("repair.skip_nzb_repair")&&($("repair.skip_nzb_repair").checked=!!e.skip_nzb_repair)}collectRepairConfig(){const $=e=>document.getElementById(e),e=$("repair.arrs"),t=e?Array.from(e.selectedOptions).map(e=>e.value).filter(Boolean):[];return{enabled:$("repair.enabled")?.checked||!1,source:$("repair.source")?.value||"arr",schedule:$("repair.schedule")?.value.trim()||"",recheck_interval:$("repair.recheck_interval")?.value.trim()||"",workers:parseInt($("repair.workers")?.value,10)||0,nntp_connection_percent:parseInt($("repair.nntp_connection_percent")?.value,10)||0,strategy:$("repair.strategy")?.value||"per_entry",piece_verify:$("repair.piece_verify")?.checked||!1,piece_verify_samples:parseInt($("repair.piece_verify_samples")?.value,10)||0,auto_repair:$("repair.auto_repair")?.checked||!1,skip_nzb_repair:$("repair.skip_nzb_repair")?.checked||!1,arrs:t}}populateGeneralSettings(e){["log_level","url_base","bind_address","port","min_file_size","max_file_size","folder_naming","refresh_dirs","disable_webdav","app_url"].forEach(t=>{const n=document.querySelector(`[name="${t}"]`);n&&void 0!==e[t]&&("checkbox"===n.type?n.checked=e[t]:n.value=e[t])}),e.allowed_file_types&&Array.isArray(e.allowed_file_types)&&(document.querySelector('[name="allowed_file_types"]').value=e.allowed_file_types.join(", "))}populateDownloadSettings(e){["remove_stalled_after","nzb_user_agent","download_folder","refresh_interval","max_active_downloads","skip_pre_cache","probe_media","always_rm_tracker_urls","default_download_action"].forEach(t=>{const n=document.querySelector(`[name="${t}"]`);n&&void 0!==e[t]&&("checkbox"===n.type?n.checked=e[t]:n.value=e[t])})}populateNotificationSettings(e){if(!e)return;const t=document.getElementById("notifications.enabled");t&&(t.checked=e.enabled||!1);const n=document.getElementById("notifications.webhook_url");n&&e.webhook_url&&(n.value=e.webhook_url);const a=document.getElementById("notifications.callback_url");a&&e.callback_url&&(a.value=e.callback_url),e.events&&Array.isArray(e.events)&&e.events.forEach(e=>{const t=document.querySelector(`input[name="notifications.events[]"][value="${e}"]`);t&&(t.checked=!0)})}populateMountSettings(e){if(!e)return;if(e.type){const t=document.querySelector(`input[name="mount.type"][value="${e.type}"]`);t&&(t.checked=!0,t.dispatchEvent(new Event("change")))}const t=document.querySelector('[name="mount.mount_path"]');t&&void 0!==e.mount_path&&(t.value=e.mount_path),this.populateRcloneSettings(e.rclone),this.populateDFSSettings(e.dfs),this.populateExternalRcloneSettings(e.external_rclone)}populateRcloneSettings(e){if(!e)return;["port","cache_dir","transfers","vfs_cache_mode","vfs_cache_max_size","vfs_cache_max_age","vfs_cache_poll_interval","vfs_read_chunk_size","vfs_read_chunk_size_limit","buffer_size","bw_limit","uid","gid","vfs_read_ahead","attr_timeout","dir_cache_time","poll_interval","umask","no_modtime","no_checksum","log_level","vfs_cache_min_free_space","vfs_fast_fingerprint","vfs_read_chunk_streams","async_read","use_mmap"].forEach(t=>{const n=document.querySelector(`[name="mount.rclone.${t}"]`);n&&void 0!==e[t]&&("checkbox"===n.type?n.checked=e[t]:n.value=e[t])})}populateDFSSettings(e){if(!e)return;["cache_dir","disk_cache_size","pinned_cache_size","buffer_memory","cache_expiry","cache_cleanup_interval","chunk_size","read_ahead_size","daemon_timeout","uid","gid","umask"].forEach(t=>{const n=document.querySelector(`[name="mount.dfs.${t}"]`);n&&void 0!==e[t]&&("checkbox"===n.type?n.checked=e[t]:n.value=e[t])})}populateExternalRcloneSettings(e){if(!e)return;["rc_url","rc_username","rc_password"].forEach(t=>{const n=document.querySelector(`[name="mount.external_rclone.${t}"]`);n&&void 0!==e[t]&&(n.value=e[t])})}addDebridConfig(e={}){const t=this.getDebridTemplate(this.debridCount,e);this.refs.debridConfigs.insertAdjacentHTML("beforeend",t);const n=this.refs.debridConfigs.lastElementChild,a=n.querySelector(`[name="debrid[${this.debridCount}].name"]`);a&&a.addEventListener("blur",()=>{this.updateArrDebridDropdowns()});const r=n.querySelector(".btn-error");r&&r.addEventListener("click",()=>{setTimeout(()=>{this.updateArrDebridDropdowns()},100)}),Object.keys(e).length>0&&this.populateDebridData(this.debridCount,e),this.debridDirectoryCounts[this.debridCount]=0,e.directories&&Object.entries(e.directories).forEach(([e,t])=>{const n=this.addDirectory(this.debridCount,{name:e,...t});t.filters&&Object.entries(t.filters).forEach(([e,t])=>{this.addFilter(this.debridCount,n,e,t)})}),this.debridCount++,this.updateArrDebridDropdowns()}populateDebridData(e,t){Object.entries(t).forEach(([t,n])=>{const a=document.querySelector(`[name="debrid[${e}].${t}"]`);a&&("checkbox"===a.type?a.checked=n:"download_api_keys"===t&&Array.isArray(n)?(a.value=n.join("\n"),"textarea"===a.tagName.toLowerCase()&&(a.style.webkitTextSecurity="disc",a.style.textSecurity="disc",a.setAttribute("data-password-visible","false"))):a.value=n)})}getDebridTemplate(e,t={}){return`\n        <div class="card bg-base-100 border border-base-300 shadow-sm debrid-config" data-index="${e}">\n            <div class="card-body">\n                <div class="flex justify-between items-start mb-4">\n                    <h3 class="card-title text-lg">\n                        <i class="bi bi-cloud mr-2 text-secondary"></i>\n                        Debrid #${e+1}\n                    </h3>\n                    <button type="button" class="btn btn-error btn-sm" onclick="this.closest('.debrid-config').remove();">\n                        <i class="bi bi-trash"></i>\n                    </button>\n                </div>\n                <div class="grid grid-cols-1 lg:grid-cols-3 gap-6">\n                        <div>\n                            <label class="label" for="debrid[${e}].name">\n                                <span class=" font-medium">Service Type</span>\n                            </label>\n                            <select class="select w-full" name="debrid[${e}].provider" id="debrid[${e}].provider" required>\n                                <option value="realdebrid">Real Debrid</option>\n                                <option value="alldebrid">AllDebrid</option>\n                                <option value="debridlink">Debrid Link</option>\n                                <option value="torbox">Torbox</option>\n                                <option value="premiumize">Premiumize</option>\n                            </select>\n                        </div>\n                        \n                        <div>\n                            <label class="label" for="debrid[${e}].name">\n                                <span class=" font-medium">Name</span>\n                            </label>\n                            <input type="text" class="input w-full" \n                                   name="debrid[${e}].name" id="debrid[${e}].name" \n                                   placeholder="realdebrid">\n                            <span class="text-sm opacity-70">A unique name for this debrid account</span>\n                        </div>\n\n                        <div>\n                            <label class="label" for="debrid[${e}].api_key">\n                                <span class=" font-medium">API Key</span>\n                            </label>\n                            <div class="password-toggle-container">\n                                <input type="password" class="input input-has-toggle" \n                                       name="debrid[${e}].api_key" id="debrid[${e}].api_key" required>\n                                <button type="button" class="password-toggle-btn">\n                                    <i class="bi bi-eye" id="debrid[${e}].api_key_icon"></i>\n                                </button>\n                            </div>\n                           <span class="text-sm opacity-70">API key for the debrid service</span>\n                        </div>\n                </div>\n\n                <div class="grid grid-cols-1 lg:grid-cols-2 gap-6">\n                    <div class="flex flex-col">\n                        <div class="fieldset flex-1">\n                            <label class="label" for="debrid[${e}].download_api_keys">\n                                <span class=" font-medium">Download API Keys</span>\n                            </label>\n                            <div class="password-toggle-container">\n                                <textarea class="textarea has-toggle font-mono h-full min-h-[200px]" \n                                          name="debrid[${e}].download_api_keys" \n                                          id="debrid[${e}].download_api_keys" \n                                          placeholder="Multiple API keys for download (one per line). If empty, main API key will be used."></textarea>\n                                <button type="button" class="password-toggle-btn textarea-toggle">\n                                    <i class="bi bi-eye" id="debrid[${e}].download_api_keys_icon"></i>\n                                </button>\n                            </div>\n                            <span class="text-sm opacity-70">Multiple API keys for downloads - leave empty to use main API key</span>\n                        </div>\n                    </div>\n                    <div class="space-y-4">\n                        <div class="grid grid-cols-2 lg:grid-cols-3 gap-3">\n                            <div>\n                                <label class="label" for="debrid[${e}].rate_limit">\n                                    <span class=" font-medium">Rate Limit</span>\n                                </label>\n                                <input type="text" class="input w-full" \n                                       name="debrid[${e}].rate_limit" id="debrid[${e}].rate_limit" \n                                       placeholder="250/minute" value="250/minute">\n                                <span class="text-sm opacity-70">API rate limit for this service</span>\n                            </div>\n                            <div>\n                                <label class="label" for="debrid[${e}].repair_rate_limit">\n                                    <span class=" font-medium">Repair Rate Limit</span>\n                                </label>\n                                <input type="text" class="input w-full" \n                                       name="debrid[${e}].repair_rate_limit" id="debrid[${e}].repair_rate_limit" \n                                       placeholder="100/minute">\n                                <span class="text-sm opacity-70">API rate limit for repair operations</span>\n                            </div>\n                            <div>\n                                <label class="label" for="debrid[${e}].download_rate_limit">\n                                    <span class=" font-medium">Download Rate Limit</span>\n                                </label>\n                                <input type="text" class="input w-full" \n                                       name="debrid[${e}].download_rate_limit" id="debrid[${e}].download_rate_limit" \n                                       placeholder="150/minute">\n                                <span class="text-sm opacity-70">API rate limit for download operations</span>\n                            </div>\n                            <div>\n                                <label class="label" for="debrid[${e}].proxy">\n                                    <span class=" font-medium">Proxy</span>\n                                </label>\n                                <input type="text" class="input w-full" \n                                       name="debrid[${e}].proxy" id="debrid[${e}].proxy" \n                                       placeholder="socks4, socks5, https proxy">\n                                <span class="text-sm opacity-70">This proxy is used for this debrid account</span>\n                            </div>\n                            <div>\n                                <label class="label" for="debrid[${e}].user_agent">\n                                    <span class=" font-medium">Custom User Agent</span>\n                                </label>\n                                <input type="text" class="input w-full" \n                                       name="debrid[${e}].user_agent" id="debrid[${e}].user_agent" \n                                       placeholder="Decypharr/1.0">\n                                <span class="text-sm opacity-70">Custom User Agent for this debrid</span>\n                            </div>\n                            <div>\n                                <label class="label" for="debrid[${e}].minimum_free_slot">\n                                    <span class=" font-medium">Minimum Free Slot</span>\n                                </label>\n                                <input type="number" class="input w-full" \n                                       name="debrid[${e}].minimum_free_slot" id="debrid[${e}].minimum_free_slot" \n                                       placeholder="1" value="1">\n                                <span class="text-sm opacity-70">Minimum free slot for this debrid</span>\n                            </div>\n                        </div>\n                    </div>\n                </div>\n                \n                <div class="grid grid-cols-1 lg:grid-cols-3 gap-6">\n                    <div>\n                        <label class="label" for="debrid[${e}].torrents_refresh_interval">\n                            <span class=" font-medium">Torrents Refresh Interval</span>\n                        </label>\n                        <input type="text" class="input webdav-field" \n                               name="debrid[${e}].torrents_refresh_interval" \n                               id="debrid[${e}].torrents_refresh_interval" \n                               placeholder="10m" value="10m">\n                        <span class="text-sm opacity-70">How often to refresh torrents list</span>\n                    </div>\n                    <div>\n                        <label class="label" for="debrid[${e}].download_links_refresh_interval">\n                            <span class=" font-medium">Links Refresh Interval</span>\n                        </label>\n                        <input type="text" class="input webdav-field" \n                               name="debrid[${e}].download_links_refresh_interval" \n                               id="debrid[${e}].download_links_refresh_interval" \n                               placeholder="40m" value="40m">\n                        <span class="text-sm opacity-70">How often to refresh download links</span>\n                    </div>\n                    <div>\n                        <label class="label" for="debrid[${e}].auto_expire_links_after">\n                            <span class=" font-medium">Links Expiry</span>\n                        </label>\n                        <input type="text" class="input webdav-field" \n                               name="debrid[${e}].auto_expire_links_after" \n                               id="debrid[${e}].auto_expire_links_after" \n                               placeholder="3d" value="3d">\n                        <span class="text-sm opacity-70">Automatically expire links after this duration</span>\n                    </div>\n                </div>\n                <div class="grid grid-cols-1 lg:grid-cols-3 gap-6 mt-6">\n                    <div>\n                        <label class="label" for="debrid[${e}].daily_traffic_limit">\n                            <span class=" font-medium">Daily Traffic Budget</span>\n                        </label>\n                        <input type="text" class="input w-full" \n                               name="debrid[${e}].daily_traffic_limit" \n                               id="debrid[${e}].daily_traffic_limit" \n                               placeholder="e.g. 500GB">\n                        <span class="text-sm opacity-70">Per download account. Leave empty for no limit</span>\n                    </div>\n                    <div>\n                        <label class="label" for="debrid[${e}].monthly_traffic_limit">\n                            <span class=" font-medium">Monthly Traffic Budget</span>\n                        </label>\n                        <input type="text" class="input w-full" \n                               name="debrid[${e}].monthly_traffic_limit" \n                               id="debrid[${e}].monthly_traffic_limit" \n                               placeholder="e.g. 5TB">\n                        <span class="text-sm opacity-70">Per download account. Leave empty for no limit</span>\n                    </div>\n                    <div>\n                        <label class="label" for="debrid[${e}].expiry_warning_days">\n                            <span class=" font-medium">Expiry Warning (days)</span>\n                        </label>\n                        <input type="number" class="input w-full" min="0"\n                               name="debrid[${e}].expiry_warning_days" \n                               id="debrid[${e}].expiry_warning_days" \n                               placeholder="0">\n                        <span class="text-sm opacity-70">Notify this many days before premium expires. 0 disables</span>\n                    </div>\n                </div>\n                <div class="grid grid-cols-1 lg:grid-cols-3 gap-6 mt-6">\n                    <div>\n                        <label class="label" for="debrid[${e}].torrent_max_age">\n                            <span class=" font-medium">Torrent Max Age</span>\n                        </label>\n                        <input type="text" class="input w-full" \n                               name="debrid[${e}].torrent_max_age" \n                               id="debrid[${e}].torrent_max_age" \n                               placeholder="e.g. 30d">\n                        <span class="text-sm opacity-70">The debrid drops torrents this long after they're added. Leave empty if it doesn't</span>\n                    </div>\n                    <div>\n                        <label class="label" for="debrid[${e}].torrent_idle_expiry">\n                            <span class=" font-medium">Torrent Idle Expiry</span>\n                        </label>\n                        <input type="text" class="input w-full" \n                               name="debrid[${e}].torrent_idle_expiry" \n                               id="debrid[${e}].torrent_idle_expiry" \n                               placeholder="e.g. 14d">\n                        <span class="text-sm opacity-70">The debrid drops torrents not streamed for this long. Leave empty if it doesn't</span>\n                    </div>\n                </div>\n                <div class="grid grid-cols-2 lg:grid-cols-3 gap-4 mt-6">\n                    <div>\n                        <label class="label cursor-pointer justify-start gap-2">\n                            <input type="checkbox" class="checkbox checkbox-primary" \n                                   name="debrid[${e}].download_uncached" id="debrid[${e}].download_uncached">\n                            <div>\n                                <span class="font-medium">Download Uncached</span>\n                                <div class="label-text-alt">Download uncached files</div>\n                            </div>\n                        </label>\n                    </div>\n\n                    <div>\n                        <label class="label cursor-pointer justify-start gap-2">\n                            <input type="checkbox" class="checkbox checkbox-primary" \n                                   name="debrid[${e}].add_samples" id="debrid[${e}].add_samples">\n                             <div>\n                                <span class=" font-medium">Add Samples</span>\n                                <div class="label-text-alt">Include sample files</div>\n                            </div>\n                        </label>\n                    </div>\n\n                    <div>\n                        <label class="label cursor-pointer justify-start gap-2">\n                            <input type="checkbox" class="checkbox checkbox-primary" \n                                   name="debrid[${e}].unpack_rar" id="debrid[${e}].unpack_rar">\n                             <div>\n                                <span class="font-medium">Unpack RAR</span>\n                                <div class="label-text-alt">Preprocess RAR files</div>\n                            </div>\n                        </label>\n                    </div>\n\n                    <div>\n                        <label class="label cursor-pointer justify-start gap-2">\n                            <input type="checkbox" class="checkbox checkbox-primary" \n                                   name="debrid[${e}].usenet" id="debrid[${e}].usenet">\n                             <div>\n                                <span class="font-medium">Usenet</span>\n                                <div class="label-text-alt">Send NZBs to this debrid (TorBox, Premiumize)</div>\n                            </div>\n                        </label>\n                    </div>\n                </div>\n        </div>\n    `}addDirectory(e,t={}){this.debridDirectoryCounts[e]||(this.debridDirectoryCounts[e]=0);const n=this.debridDirectoryCounts[e],a=document.getElementById(`debrid[${e}].directories`),r=this.getDirectoryTemplate(e,n);a.insertAdjacentHTML("beforeend",r);const i=`${e}-${n}`;if(this.directoryFilterCounts[i]=0,t.name){const a=document.querySelector(`[name="debrid[${e}].directory[${n}].name"]`);a&&(a.value=t.name)}return this.debridDirectoryCounts[e]++,n}getDirectoryTemplate(e,t){return`\n            <div class="card bg-base-200 border border-base-300 directory-item">\n                <div class="card-body">\n                    <div class="flex justify-between items-start mb-4">\n                        <h5 class="text-lg font-medium">Virtual Directory</h5>\n                        <button type="button" class="btn btn-error btn-xs" onclick="this.closest('.directory-item').remove();">\n                            <i class="bi bi-trash"></i>\n                        </button>\n                    </div>\n\n                    <div class="fieldset mb-4">\n                        <label class="label">\n                            <span class=" font-medium">Directory Name</span>\n                        </label>\n                        <input type="text" class="input webdav-field"\n                               name="debrid[${e}].directory[${t}].name"\n                               placeholder="Movies, TV Shows, Collections, etc.">\n                    </div>\n\n                    <div class="space-y-4">\n                        <div class="flex justify-between items-center">\n                            <h6 class="font-medium flex items-center">\n                                Filters\n                                <button type="button" class="btn btn-ghost btn-xs ml-2" onclick="configManager.showFilterHelp();">\n                                    <i class="bi bi-question-circle"></i>\n                                </button>\n                            </h6>\n                        </div>\n\n                        <div class="filters-container space-y-2" id="debrid[${e}].directory[${t}].filters">\n                        </div>\n\n                        <div class="flex flex-wrap gap-2">\n                            <div class="dropdown">\n                                <div tabindex="0" role="button" class="btn btn-outline btn-sm">\n                                    <i class="bi bi-plus mr-1"></i>Text Filter\n                                    <i class="bi bi-chevron-down ml-1"></i>\n                                </div>\n                                <ul tabindex="0" class="dropdown-content menu bg-base-100 rounded-box z-[1] w-48 p-2 shadow">\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'include');">Include</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'exclude');">Exclude</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'starts_with');">Starts With</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'not_starts_with');">Not Starts With</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'ends_with');">Ends With</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'not_ends_with');">Not Ends With</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'exact_match');">Exact Match</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'not_exact_match');">Not Exact Match</a></li>\n                                </ul>\n                            </div>\n\n                            <div class="dropdown">\n                                <div tabindex="0" role="button" class="btn btn-outline btn-sm">\n                                    <i class="bi bi-code mr-1"></i>Regex Filter\n                                    <i class="bi bi-chevron-down ml-1"></i>\n                                </div>\n                                <ul tabindex="0" class="dropdown-content menu bg-base-100 rounded-box z-[1] w-48 p-2 shadow">\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'regex');">Regex Match</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'not_regex');">Regex Doesn't Match</a></li>\n                                </ul>\n                            </div>\n\n                            <div class="dropdown">\n                                <div tabindex="0" role="button" class="btn btn-outline btn-sm">\n                                    <i class="bi bi-hdd mr-1"></i>Size Filter\n                                    <i class="bi bi-chevron-down ml-1"></i>\n                                </div>\n                                <ul tabindex="0" class="dropdown-content menu bg-base-100 rounded-box z-[1] w-48 p-2 shadow">\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'size_gt');">Size Greater Than</a></li>\n                                    <li><a onclick="configManager.addFilter(${e}, ${t}, 'size_lt');">Size Less Than</a></li>\n                                </ul>\n                            </div>\n\n                            <button type="button" class="btn btn-outline btn-sm" onclick="configManager.addFilter(${e}, ${t}, 'last_added');">\n                                <i class="bi bi-clock mr-1"></i>Last Added Filter\n                            </button>\n                        </div>\n                    </div>\n                </div>\n            </div>\n        `}addFilter(e,t,n,a=""){const r=`${e}-${t}`;this.directoryFilterCounts[r]||(this.directoryFilterCounts[r]=0);const i=this.directoryFilterCounts[r],s=document.getElementById(`debrid[${e}].directory[${t}].filters`);if(s){const l=this.getFilterTemplate(e,t,i,n);if(s.insertAdjacentHTML("beforeend",l),a){const n=s.querySelector(`[name="debrid[${e}].directory[${t}].filter[${i}].value"]`);n&&(n.value=a)}this.directoryFilterCounts[r]++}}getFilterTemplate(e,t,n,a){const r=this.getFilterConfig(a);return`\n            <div class="filter-item flex items-center gap-3 p-3 bg-base-100 rounded-lg border border-base-300">\n                <div class="badge ${r.badgeClass} badge-sm">\n                    ${r.label}\n                </div>\n                <input type="hidden"\n                       name="debrid[${e}].directory[${t}].filter[${n}].type"\n                       value="${a}">\n                <div class="flex-1">\n                    <input type="text" \n                           class="input input-sm w-full webdav-field"\n                           name="debrid[${e}].directory[${t}].filter[${n}].value"\n                           placeholder="${r.placeholder}">\n                </div>\n                <button type="button" class="btn btn-error btn-xs" onclick="this.closest('.filter-item').remove();">\n                    <i class="bi bi-x"></i>\n                </button>\n            </div>\n        `}getFilterConfig(e){return{include:{label:"Include",placeholder:"Text that should be included in filename",badgeClass:"badge-primary"},exclude:{label:"Exclude",placeholder:"Text that should not be in filename",badgeClass:"badge-error"},regex:{label:"Regex Match",placeholder:"Regular expression pattern",badgeClass:"badge-warning"},not_regex:{label:"Regex Not Match",placeholder:"Regular expression pattern that should not match",badgeClass:"badge-error"},exact_match:{label:"Exact Match",placeholder:"Exact text to match",badgeClass:"badge-primary"},not_exact_match:{label:"Not Exact Match",placeholder:"Exact text that should not match",badgeClass:"badge-error"},starts_with:{label:"Starts With",placeholder:"Text that filename starts with",badgeClass:"badge-primary"},not_starts_with:{label:"Not Starts With",placeholder:"Text that filename should not start with",badgeClass:"badge-error"},ends_with:{label:"Ends With",placeholder:"Text that filename ends with",badgeClass:"badge-primary"},not_ends_with:{label:"Not Ends With",placeholder:"Text that filename should not end with",badgeClass:"badge-error"},size_gt:{label:"Size Greater Than",placeholder:"Size in bytes, KB, MB, GB (e.g. 700MB)",badgeClass:"badge-success"},size_lt:{label:"Size Less Than",placeholder:"Size in bytes, KB, MB, GB (e.g. 700MB)",badgeClass:"badge-warning"},last_added:{label:"Added in the last",placeholder:"Time duration (e.g. 24h, 7d, 30d)",badgeClass:"badge-info"}}[e]||{label:e.replace(/_/g," ").replace(/\b\w/g,e=>e.toUpperCase()),placeholder:"Filter value",badgeClass:"badge-ghost"}}showFilterHelp(){const e=document.createElement("dialog");e.className="modal",e.innerHTML='\n            <div class="modal-box max-w-2xl">\n                <form method="dialog">\n                    <button class="btn btn-sm btn-circle btn-ghost absolute right-2 top-2">✕</button>\n                </form>\n                <h3 class="font-bold text-lg mb-4">Directory Filter Types</h3>\n                <div class="space-y-4">\n                    <div>\n                        <h4 class="font-semibold text-primary">Text Filters</h4>\n                        <ul class="list-disc list-inside text-sm space-y-1 ml-4">\n                            <li><strong>Include/Exclude:</strong> Simple text inclusion/exclusion</li>\n                            <li><strong>Starts/Ends With:</strong> Matches beginning or end of filename</li>\n                            <li><strong>Exact Match:</strong> Match the entire filename</li>\n                        </ul>\n                    </div>\n                    <div>\n                        <h4 class="font-semibold text-warning">Regex Filters</h4>\n                        <ul class="list-disc list-inside text-sm space-y-1 ml-4">\n                            <li><strong>Regex:</strong> Use regular expressions for complex patterns</li>\n                            <li>Example: <code>.*\\.mkv$</code> matches files ending with .mkv</li>\n                        </ul>\n                    </div>\n                    <div>\n                        <h4 class="font-semibold text-success">Size Filters</h4>\n                        <ul class="list-disc list-inside text-sm space-y-1 ml-4">\n                            <li><strong>Size Greater/Less Than:</strong> Filter by file size</li>\n                            <li>Examples: 1GB, 500MB, 2.5GB</li>\n                        </ul>\n                    </div>\n                    <div>\n                        <h4 class="font-semibold text-info">Time Filters</h4>\n                        <ul class="list-disc list-inside text-sm space-y-1 ml-4">\n                            <li><strong>Last Added:</strong> Show only recently added content</li>\n                            <li>Examples: 24h, 7d, 30d</li>\n                        </ul>\n                    </div>\n                    <div class="alert alert-info">\n                        <i class="bi bi-info-circle"></i>\n                        <span>Negative filters (Not...) will exclude matches instead of including them.</span>\n                    </div>\n                </div>\n            </div>\n        ',document.body.appendChild(e),e.showModal(),e.addEventListener("close",()=>{document.body.removeChild(e)})}getDebridOptions(){const e=[];return document.querySelectorAll(".debrid-config").forEach(t=>{const n=t.getAttribute("data-index"),a=document.querySelector(`[name="debrid[${n}].name"]`);a&&a.value.trim()&&e.push(a.value.trim())}),e.map(e=>`<option value="${window.decypharrUtils.escapeHtml(e)}">${window.decypharrUtils.escapeHtml(e)}</option>`).join("")}updateArrDebridDropdowns(){const e=document.querySelectorAll(".arr-config"),t=this.getDebridOptions();e.forEach(e=>{const n=e.getAttribute("data-index"),a=document.querySelector(`[name="arr[${n}].selected_debrid"]`);if(a){const e=a.value;a.innerHTML=`<option value="">Auto Select</option>${t}`,e&&(a.value=e)}})}addArrConfig(e={}){const t=this.getArrTemplate(this.arrCount,e);this.refs.arrConfigs.insertAdjacentHTML("beforeend",t),Object.keys(e).length>0&&this.populateArrData(this.arrCount,e),this.arrCount++}populateArrData(e,t){Object.entries(t).forEach(([t,n])=>{const a=document.querySelector(`[name="arr[${e}].${t}"]`);a&&("checkbox"===a.type?a.checked=n:a.value=n)})}getArrTemplate(e,t={}){const n="auto"===t.source,a=this.getDebridOptions();return`\n            <div class="card bg-base-100 border border-base-300 shadow-sm arr-config ${n?"border-info":""}" data-index="${e}">\n                <div class="card-body p-4 gap-4">\n                    <div class="flex items-start justify-between gap-3">\n                        <h3 class="card-title text-base leading-tight min-w-0">\n                            <i class="bi bi-collection text-warning shrink-0"></i>\n                            <span class="min-w-0 break-words">Arr Service #${e+1}</span>\n                            ${n?'<span class="badge badge-info badge-sm shrink-0">Auto-detected</span>':""}\n                        </h3>\n                        ${n?"":'\n                            <button type="button" class="btn btn-error btn-sm btn-square shrink-0" onclick="this.closest(\'.arr-config\').remove();">\n                                <i class="bi bi-trash"></i>\n                            </button>\n                        '}\n                    </div>\n\n                    <input type="hidden" name="arr[${e}].source" value="${t.source||""}">\n\n                    <div class="grid grid-cols-1 gap-3">\n                        <div>\n                            <label class="label" for="arr[${e}].name">\n                                <span class="font-medium">Service Name</span>\n                            </label>\n                            <input type="text" class="input ${n?"input-disabled":""}"\n                                   name="arr[${e}].name" id="arr[${e}].name"\n                                   ${n?"readonly":"required"}\n                                   placeholder="sonarr, radarr, etc.">\n                        </div>\n\n                        <div>\n                            <label class="label" for="arr[${e}].host">\n                                <span class="font-medium">Host URL</span>\n                            </label>\n                            <input type="url" class="input ${n?"input-disabled":""}"\n                                   name="arr[${e}].host" id="arr[${e}].host"\n                                   ${n?"readonly":"required"}\n                                   placeholder="http://localhost:8989">\n                        </div>\n\n                        <div>\n                            <label class="label" for="arr[${e}].token">\n                                <span class="font-medium">API Token</span>\n                            </label>\n                            <div class="password-toggle-container">\n                                <input type="password" class="input input-has-toggle ${n?"input-disabled":""}"\n                                       name="arr[${e}].token" id="arr[${e}].token"\n                                       ${n?"readonly":"required"}>\n                                <button type="button" class="password-toggle-btn ${n?"opacity-50 cursor-not-allowed":""}"\n                                        ${n?"disabled":""}>\n                                    <i class="bi bi-eye" id="arr[${e}].token_icon"></i>\n                                </button>\n                            </div>\n                        </div>\n\n                        <div>\n                            <label class="label" for="arr[${e}].selected_debrid">\n                                <span class="font-medium">Preferred Provider</span>\n                            </label>\n                            <select class="select w-full" name="arr[${e}].selected_debrid" id="arr[${e}].selected_debrid">\n                                <option value="">Auto Select</option>\n                                ${a}\n                            </select>\n                            <span class="text-sm opacity-70">Which debrid service this Arr should prefer</span>\n                        </div>\n                    </div>\n\n                    <div class="grid grid-cols-1 md:grid-cols-2 gap-2">\n                        <div class="rounded-box bg-base-200/50 px-3 py-2">\n                            <label class="label cursor-pointer justify-start gap-2 p-0">\n                                <input type="checkbox" class="checkbox checkbox-sm checkbox-primary"\n                                       name="arr[${e}].skip_repair" id="arr[${e}].skip_repair">\n                                <span class="text-sm leading-tight">Skip Repair</span>\n                            </label>\n                        </div>\n\n                        <div class="rounded-box bg-base-200/50 px-3 py-2">\n                            <label class="label cursor-pointer justify-start gap-2 p-0">\n                                <input type="checkbox" class="checkbox checkbox-sm checkbox-primary"\n                                       name="arr[${e}].download_uncached" id="arr[${e}].download_uncached">\n                                <span class="text-sm leading-tight">Download Uncached</span>\n                            </label>\n                        </div>\n                    </div>\n                </div>\n            </div>\n        `}async saveConfiguration(e){e.preventDefault(),this.refs.loadingOverlay.classList.remove("hidden");try{const e=this.collectFormData(),t=this.validateConfiguration(e);if(!t.valid)throw new Error(t.errors.join("\n"));const n=await window.decypharrUtils.fetcher("/api/config",{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify(e)});if(!n.ok){const e=await n.text();throw new Error(e||"Failed to save configuration")}let a=!0;try{a=!1!==(await n.json()).restarted}catch(e){}a?(window.decypharrUtils.createToast("Configuration saved successfully! Services are restarting...","success"),setTimeout(()=>{window.location.reload()},2e3)):(window.decypharrUtils.createToast("Configuration saved and applied.","success"),this.refs.loadingOverlay.classList.add("hidden"))}catch(e){console.error("Error saving configuration:",e),window.decypharrUtils.createToast(`Error saving configuration: ${e.message}`,"error"),this.refs.loadingOverlay.classList.add("hidden")}}validateConfiguration(e){const t=[];return e.debrids.forEach((e,n)=>{e.name&&e.api_key&&e.provider||t.push(`Debrid service #${n+1}: Name, API key are required`)}),e.arrs.forEach((e,n)=>{e.name&&e.host||t.push(`Arr service #${n+1}: Name and host are required`),e.host&&!this.isValidUrl(e.host)&&t.push(`Arr service #${n+1}: Invalid host URL format`)}),""===e.mount.type&&t.push("Mount type is required when "),""===e.mount.mount_path&&t.push("Mount path is required when Rclone is enabled"),e.repair?.enabled&&!e.repair.schedule&&t.push("Repair: schedule is required when Repair is enabled"),{valid:0===t.length,errors:t}}isValidUrl(e){try{return new URL(e),!0}catch(e){return!1}}collectFormData(){return{log_level:document.querySelector('[name="log_level"]').value,url_base:document.querySelector('[name="url_base"]').value,bind_address:document.querySelector('[name="bind_address"]').value,app_url:document.querySelector('[name="app_url"]').value,port:document.querySelector('[name="port"]').value,allowed_file_types:document.querySelector('[name="allowed_file_types"]').value.split(",").map(e=>e.trim()).filter(Boolean),min_file_size:document.querySelector('[name="min_file_size"]').value,max_file_size:document.querySelector('[name="max_file_size"]').value,remove_stalled_after:document.querySelector('[name="remove_stalled_after"]').value||"10m",nzb_user_agent:document.querySelector('[name="nzb_user_agent"]').value,download_folder:document.querySelector('[name="download_folder"]').value,refresh_interval:document.querySelector('[name="refresh_interval"]').value||"30s",default_download_action:document.querySelector('[name="default_download_action"]')?.value||"symlink",max_active_downloads:parseInt(document.querySelector('[name="max_active_downloads"]').value)||5,skip_pre_cache:document.querySelector('[name="skip_pre_cache"]').checked,probe_media:document.querySelector('[name="probe_media"]').checked,always_rm_tracker_urls:document.querySelector('[name="always_rm_tracker_urls"]').checked,folder_naming:document.querySelector('[name="folder_naming"]')?.value||"",disable_webdav:document.querySelector('[name="disable_webdav"]').checked,refresh_dirs:document.querySelector('[name="refresh_dirs"]')?.value||"",custom_folders:this.collectVirtualFolders(),debrids:this.collectDebridConfigs(),arrs:this.collectArrConfigs(),queue_cleanup:this.collectQueueCleanup(),mount:this.collectMountConfig(),usenet:this.collectUsenetConfig(),notifications:this.collectNotificationsConfig(),repair:this.collectRepairConfig(),file_selection:this.loadedConfig?.file_selection,reconcile:this.loadedConfig?.reconcile,retention:this.loadedConfig?.retention,recycle_bin:this.loadedConfig?.recycle_bin,guardian:this.loadedConfig?.guardian,events:this.loadedConfig?.events,buffers:this.loadedConfig?.buffers,sftp:this.loadedConfig?.sftp,category_profiles:this.loadedConfig?.category_profiles,tag_rules:this.loadedConfig?.tag_rules}}collectNotificationsConfig(){const e=document.getElementById("notifications.enabled"),t=document.getElementById("notifications.webhook_url"),n=document.getElementById("notifications.callback_url"),a=[];return document.querySelectorAll('input[name="notifications.events[]"]').forEach(e=>{e.checked&&a.push(e.value)}),{enabled:!!e&&e.checked,webhook_url:t?t.value:"",callback_url:n?n.value:"",events:a}}collectUsenetConfig(){const e=[];return this.refs.usenetProviders.querySelectorAll(".usenet-provider").forEach(t=>{const n=t.getAttribute("data-index"),a=e=>t.querySelector(`[name="usenet.providers[${n}].${e}"]`),r=a("host"),i=a("port"),s=a("username"),l=a("password"),o=a("backbone"),d=a("ssl"),c=a("max_connections"),u=a("priority"),p=a("backup");if(!(r&&i&&s&&l&&o&&d&&c&&u))return;const m={host:r.value,port:parseInt(i.value)||119,username:s.value,password:l.value,backbone:o.value.trim(),ssl:d.checked,max_connections:parseInt(c.value)||100,priority:parseInt(u.value)||0,backup:!!p&&p.checked};m.host&&m.username&&m.password&&e.push(m)}),{providers:e,max_connections:parseInt(document.querySelector('[name="usenet.max_connections"]')?.value)||15,processing_max_connections:parseInt(document.querySelector('[name="usenet.processing_max_connections"]')?.value)||parseInt(document.querySelector('[name="usenet.max_connections"]')?.value)||15,read_ahead:document.querySelector('[name="usenet.read_ahead"]').value||"16MB",processing_timeout:document.querySelector('[name="usenet.processing_timeout"]')?.value||"5m",availability_sample_percent:parseInt(document.querySelector('[name="usenet.availability_sample_percent"]')?.value)||10,import_availability_sample_percent:parseInt(document.querySelector('[name="usenet.import_availability_sample_percent"]')?.value)||1,prefer_debrid:document.querySelector('[name="usenet.prefer_debrid"]')?.checked||!1,disk_buffer_path:document.querySelector('[name="usenet.disk_buffer_path"]')?.value||"",buffer_memory:document.querySelector('[name="usenet.buffer_memory"]')?.value||""}}collectDebridConfigs(){const e=[];return this.refs.debridConfigs.querySelectorAll(".debrid-config").forEach(t=>{const n=t.getAttribute("data-index"),a=e=>t.querySelector(`[name="debrid[${n}].${e}"]`),r=a("name"),i=a("provider"),s=a("api_key"),l=a("rate_limit"),o=a("repair_rate_limit"),d=a("download_rate_limit"),c=a("minimum_free_slot"),u=a("proxy"),p=a("download_uncached"),m=a("unpack_rar"),b=a("add_samples"),_=a("user_agent"),h=a("download_api_keys"),v=a("torrents_refresh_interval"),f=a("download_links_refresh_interval"),g=a("auto_expire_links_after");if(!(r&&i&&s&&l&&o&&d&&c&&u&&p&&m&&b&&_&&v&&f&&g))return;const y={name:r.value,provider:i.value,api_key:s.value,rate_limit:l.value,repair_rate_limit:o.value,download_rate_limit:d.value,minimum_free_slot:parseInt(c.value)||0,proxy:u.value,download_uncached:p.checked,unpack_rar:m.checked,add_samples:b.checked,usenet:a("usenet")?.checked||!1,user_agent:_.value};h&&h.value.trim()&&(y.download_api_keys=h.value.split("\n").map(e=>e.trim()).filter(e=>e.length>0)),y.torrents_refresh_interval=v.value,y.download_links_refresh_interval=f.value,y.auto_expire_links_after=g.value,y.daily_traffic_limit=a("daily_traffic_limit")?.value||"",y.monthly_traffic_limit=a("monthly_traffic_limit")?.value||"",y.expiry_warning_days=parseInt(a("expiry_warning_days")?.value)||0,y.torrent_max_age=a("torrent_max_age")?.value||"",y.torrent_idle_expiry=a("torrent_idle_expiry")?.value||"",y.name&&y.api_key&&y.provider&&e.push(y)}),e}collectArrConfigs(){const e=[];return this.refs.arrConfigs.querySelectorAll(".arr-config").forEach(t=>{const n=t.getAttribute("data-index"),a=e=>t.querySelector(`[name="arr[${n}].${e}"]`),r=a("name"),i=a("host"),s=a("token"),l=a("skip_repair"),o=a("download_uncached"),d=a("selected_debrid"),c=a("source");if(!(r&&i&&s&&l&&o&&d&&c))return;const u={name:r.value,host:i.value,token:s.value,skip_repair:l.checked,download_uncached:o.checked,selected_debrid:d.value,source:c.value};u.name&&u.host&&e.push(u)}),e}populateQueueCleanup(e){const t=document.getElementById("queueCleanupCatalog"),n=document.getElementById("queueCleanupCustom");if(!t||!n)return;const a=e&&Array.isArray(e.rules)?e.rules:[],r={},i=[];a.forEach(e=>{e&&e.id?r[e.id]=e.action||"":e&&(e.match||"").trim()&&i.push(e)});const s=window.decypharrUtils.escapeHtml;t.innerHTML=this.queueCleanupCatalog.map(e=>{const t=e.id in r?r[e.id]:"";return`\n                <div class="grid grid-cols-1 md:grid-cols-5 gap-2 md:items-center px-3 py-3 even:bg-base-200/40" data-rule-id="${e.id}">\n                    <span class="md:col-span-3 text-sm leading-snug min-w-0">${s(e.label)}</span>\n                    <select class="md:col-span-2 select select-sm w-full queue-cleanup-action">\n                        ${this.queueCleanupActionOptions(t)}\n                    </select>\n                </div>`}).join(""),n.innerHTML="",i.forEach(e=>this.addQueueCleanupCustomRow(e.match,e.action))}addQueueCleanupCustomRow(e="",t=""){const n=document.getElementById("queueCleanupCustom");if(!n)return;const a=window.decypharrUtils.escapeHtml,r=document.createElement("div");r.className="grid grid-cols-1 md:grid-cols-12 gap-2 queue-cleanup-custom-row",r.innerHTML=`\n            <input type="text" class="md:col-span-7 input input-sm w-full min-w-0 queue-cleanup-match"\n                   placeholder="text in status message, e.g. stalled"\n                   value="${a(e)}">\n            <select class="md:col-span-4 select select-sm w-full queue-cleanup-action">\n                ${this.queueCleanupActionOptions(t)}\n            </select>\n            <button type="button" class="md:col-span-1 btn btn-sm btn-square btn-ghost text-error queue-cleanup-remove" aria-label="Remove custom queue cleanup rule" title="Remove rule">\n                <i class="bi bi-trash"></i>\n            </button>`,r.querySelector(".queue-cleanup-remove").addEventListener("click",()=>r.remove()),n.appendChild(r)}collectQueueCleanup(){const e=[];return document.querySelectorAll("#queueCleanupCatalog [data-rule-id]").forEach(t=>{const n=t.getAttribute("data-rule-id"),a=t.querySelector(".queue-cleanup-action")?.value||"";e.push({id:n,action:a})}),document.querySelectorAll("#queueCleanupCustom .queue-cleanup-custom-row").forEach(t=>{const n=(t.querySelector(".queue-cleanup-match")?.value||"").trim();if(!n)return;const a=t.querySelector(".queue-cleanup-action")?.value||"";e.push({match:n,action:a})}),{rules:e}}collectMountConfig(){const e=document.querySelector('input[name="mount.type"]:checked');return{type:e?e.value:"none",mount_path:document.querySelector('[name="mount.mount_path"]').value,dfs:this.collectDFSConfig(),rclone:this.collectRcloneConfig(),external_rclone:this.collectExternalRclone(),views:this.loadedConfig?.mount?.views}}collectExternalRclone(){return{rc_url:document.querySelector('[name="mount.external_rclone.rc_url"]')?.value||"",rc_username:document.querySelector('[name="mount.external_rclone.rc_username"]')?.value||"",rc_password:document.querySelector('[name="mount.external_rclone.rc_password"]')?.value||""}}collectRcloneConfig(){const e=(e,t="")=>{const n=document.querySelector(`[name="mount.rclone.${e}"]`);if(!n)return t;if("checkbox"===n.type)return n.checked;if("number"===n.type){const e=parseInt(n.value);return isNaN(e)?0:e}return n.value||t};return{port:e("port","5572"),buffer_size:e("buffer_size"),bw_limit:e("bw_limit"),cache_dir:e("cache_dir"),transfers:e("transfers",8),vfs_cache_mode:e("vfs_cache_mode","off"),vfs_cache_max_age:e("vfs_cache_max_age","1h"),vfs_cache_max_size:e("vfs_cache_max_size"),vfs_cache_poll_interval:e("vfs_cache_poll_interval","1m"),vfs_read_chunk_size:e("vfs_read_chunk_size",""),vfs_read_chunk_size_limit:e("vfs_read_chunk_size_limit","off"),vfs_cache_min_free_space:e("vfs_cache_min_free_space",""),vfs_fast_fingerprint:e("vfs_fast_fingerprint",!1),vfs_read_chunk_streams:e("vfs_read_chunk_streams",0),use_mmap:e("use_mmap",!1),async_read:e("async_read",!0),uid:e("uid",0),gid:e("gid",0),umask:e("umask",""),vfs_read_ahead:e("vfs_read_ahead",""),attr_timeout:e("attr_timeout","1s"),dir_cache_time:e("dir_cache_time","5m"),no_modtime:e("no_modtime",!1),no_checksum:e("no_checksum",!1),log_level:e("log_level","INFO")}}collectDFSConfig(){const e=(e,t="")=>{const n=document.querySelector(`[name="mount.dfs.${e}"]`);if(!n)return t;if("checkbox"===n.type)return n.checked;if("number"===n.type){const e=parseInt(n.value);return isNaN(e)?0:e}return n.value||t};return{cache_dir:e("cache_dir"),disk_cache_size:e("disk_cache_size"),pinned_cache_size:e("pinned_cache_size"),buffer_memory:e("buffer_memory"),cache_expiry:e("cache_expiry"),cache_cleanup_interval:e("cache_cleanup_interval"),chunk_size:e("chunk_size"),read_ahead_size:e("read_ahead_size"),daemon_timeout:e("daemon_timeout"),uid:e("uid",0),gid:e("gid",0),umask:e("umask")}}setupMagnetHandler(){if(window.registerMagnetLinkHandler=()=>{if("registerProtocolHandler"in navigator)try{navigator.registerProtocolHandler("magnet",`${window.location.origin}${window.urlBase}download?magnet=%s`,"Decypharr"),localStorage.setItem("magnetHandler","true");const e=document.getElementById("registerMagnetLink");e.innerHTML='<i class="bi bi-check-circle mr-2"></i>Magnet Handler Registered',e.classList.remove("btn-primary"),e.classList.add("btn-success"),e.disabled=!0,window.decypharrUtils.createToast("Magnet link handler registered successfully")}catch(e){console.error("Failed to register magnet link handler:",e),window.decypharrUtils.createToast("Failed to register magnet link handler","error")}else window.decypharrUtils.createToast("Magnet link registration not supported in this browser","warning")},"true"===localStorage.getItem("magnetHandler")){const e=document.getElementById("registerMagnetLink");e&&(e.innerHTML='<i class="bi bi-check-circle mr-2"></i>Magnet Handler Registered',e.classList.remove("btn-primary"),e.classList.add("btn-success"),e.disabled=!0)}}populateAPIToken(e){const t=document.getElementById("api-token-display");t&&(t.value=e.api_token||"****");const n=document.getElementById("auth-username");n&&e.auth_username&&(n.value=e.auth_username)}populateVirtualFolders(e){e&&Object.entries(e).forEach(([e,t])=>{this.addVirtualFolder(e,t.filters,t.expr)})}addVirtualFolder(e="",t={},o=""){const n=this.virtualFolderCount++,a=Object.entries(t),r=`\n            <div class="card bg-base-200 shadow-sm" data-virtual-folder="${n}">\n                <div class="card-body p-4">\n                    <div class="flex justify-between items-start mb-4">\n                        <h4 class="font-semibold text-lg">Virtual Folder</h4>\n                        <button type="button" class="btn btn-ghost btn-sm btn-circle" onclick="configManager.removeVirtualFolder(${n});">\n                            <i class="bi bi-x-lg"></i>\n                        </button>\n                    </div>\n\n                    <div class="space-y-4">\n                        <div>\n                            <label class="label">\n                                <span class="font-medium">Folder Name</span>\n                            </label>\n                            <input type="text"\n                                   class="input input-bordered w-full"\n                                   name="virtual_folder_${n}_name"\n                                   value="${window.decypharrUtils.escapeHtml(e)}"\n                                   placeholder="e.g., Movies, TV Shows, 4K"\n                                   required>\n                            <span class="text-sm opacity-70">This folder will appear in your mount</span>\n                        </div>\n\n                        <div>\n                            <label class="label">\n                                <span class="font-medium">Expression</span>\n                            </label>\n                            <input type="text"\n                                   class="input input-bordered w-full font-mono"\n                                   name="virtual_folder_${n}_expr"\n                                   value="${window.decypharrUtils.escapeHtml(o)}"\n                                   placeholder='e.g., (name ~ "(?i)remux" or file = "*.iso") and size > 2GB and not tag = kids'>\n                            <span class="text-sm opacity-70">Optional. When set, it replaces the filters below</span>\n                        </div>\n\n                        <div>\n                            <label class="label">\n                                <span class="font-medium">Filters</span>\n                                <button type="button" class="btn btn-xs btn-primary" onclick="configManager.addVirtualFolderFilter(${n});">\n                                    <i class="bi bi-plus"></i> Add Filter\n                                </button>\n                            </label>\n                            <div class="space-y-2" id="virtual_folder_${n}_filters">\n                                ${a.length>0?a.map(([e,t],a)=>`\n                                    <div class="flex gap-2" data-filter-index="${a}">\n                                        <input type="text"\n                                               class="input input-bordered input-sm flex-1"\n                                               name="virtual_folder_${n}_filter_key_${a}"\n                                               value="${window.decypharrUtils.escapeHtml(e)}"\n                                               placeholder="Filter key (e.g., name, category)">\n                                        <input type="text"\n                                               class="input input-bordered input-sm flex-1"\n                                               name="virtual_folder_${n}_filter_value_${a}"\n                                               value="${window.decypharrUtils.escapeHtml(t)}"\n                                               placeholder="Filter value (e.g., *movie*, tv)">\n                                        <button type="button" class="btn btn-sm btn-ghost btn-circle" onclick="configManager.removeVirtualFolderFilter(${n}, ${a});">\n                                            <i class="bi bi-trash"></i>\n                                        </button>\n                                    </div>\n                                `).join(""):'\n                                    <div class="text-sm opacity-70">No filters. Click "Add Filter" to add one.</div>\n                                '}\n                            </div>\n                            <span class="text-sm opacity-70">Filters use wildcards: * for any characters, ? for single character</span>\n                        </div>\n                    </div>\n                </div>\n            </div>\n        `;this.refs.virtualFoldersContainer.insertAdjacentHTML("beforeend",r)}addVirtualFolderFilter(e){const t=document.getElementById(`virtual_folder_${e}_filters`),n=t.querySelectorAll("[data-filter-index]").length,a=t.querySelector(".text-sm.opacity-70");a&&a.remove();const r=`\n            <div class="flex gap-2" data-filter-index="${n}">\n                <input type="text"\n                       class="input input-bordered input-sm flex-1"\n                       name="virtual_folder_${e}_filter_key_${n}"\n                       placeholder="Filter key (e.g., name, category)">\n                <input type="text"\n                       class="input input-bordered input-sm flex-1"\n                       name="virtual_folder_${e}_filter_value_${n}"\n                       placeholder="Filter value (e.g., *movie*, tv)">\n                <button type="button" class="btn btn-sm btn-ghost btn-circle" onclick="configManager.removeVirtualFolderFilter(${e}, ${n});">\n                    <i class="bi bi-trash"></i>\n                </button>\n            </div>\n        `;t.insertAdjacentHTML("beforeend",r)}removeVirtualFolderFilter(e,t){const n=document.getElementById(`virtual_folder_${e}_filters`),a=n.querySelector(`[data-filter-index="${t}"]`);a&&a.remove();0===n.querySelectorAll("[data-filter-index]").length&&(n.innerHTML='<div class="text-sm opacity-70">No filters. Click "Add Filter" to add one.</div>')}removeVirtualFolder(e){const t=document.querySelector(`[data-virtual-folder="${e}"]`);t&&confirm("Are you sure you want to remove this virtual folder?")&&t.remove()}collectVirtualFolders(){const e={};return this.refs.virtualFoldersContainer.querySelectorAll("[data-virtual-folder]").forEach(t=>{const n=t.getAttribute("data-virtual-folder"),a=t.querySelector(`[name="virtual_folder_${n}_name"]`),r=a?a.value.trim():"";if(r){const t={};document.getElementById(`virtual_folder_${n}_filters`).querySelectorAll("[data-filter-index]").forEach(e=>{const a=e.getAttribute("data-filter-index"),r=e.querySelector(`[name="virtual_folder_${n}_filter_key_${a}"]`),i=e.querySelector(`[name="virtual_folder_${n}_filter_value_${a}"]`),s=r?r.value.trim():"",l=i?i.value.trim():"";s&&l&&(t[s]=l)}),e[r]=(e=>e?{expr:e}:{filters:t})(document.querySelector(`[name="virtual_folder_${n}_expr"]`)?.value.trim()||"")}}),e}populateUsenetSettings(e){e.providers&&Array.isArray(e.providers)&&e.providers.forEach(e=>this.addUsenetProvider(e));const t={max_connections:e.max_connections,processing_max_connections:e.processing_max_connections,read_ahead:e.read_ahead,processing_timeout:e.processing_timeout,availability_sample_percent:e.availability_sample_percent,import_availability_sample_percent:e.import_availability_sample_percent,disk_buffer_path:e.disk_buffer_path,buffer_memory:e.buffer_memory};Object.entries(t).forEach(([e,t])=>{const n=document.getElementsByName(`usenet.${e}`)[0];n&&void 0!==t&&(n.value=t)});const n=document.getElementsByName("usenet.prefer_debrid")[0];n&&(n.checked=!!e.prefer_debrid)}addUsenetProvider(e={}){const t=this.getUsenetProviderTemplate(this.usenetProviderCount,e);this.refs.usenetProviders.insertAdjacentHTML("beforeend",t),Object.keys(e).length>0&&this.populateUsenetProviderData(this.usenetProviderCount,e),this.usenetProviderCount++}populateUsenetProviderData(e,t){Object.entries(t).forEach(([t,n])=>{const a=document.querySelector(`[name="usenet.providers[${e}].${t}"]`);a&&("checkbox"===a.type?a.checked=n:a.value=n)})}getUsenetProviderTemplate(e,t={}){return`\n        <div class="card bg-base-200 border border-base-300 usenet-provider" data-index="${e}">\n            <div class="card-body">\n                <div class="flex justify-between items-start mb-4">\n                    <h4 class="font-bold text-lg">\n                        <i class="bi bi-server mr-2"></i>\n                        Provider #${e+1}\n                    </h4>\n                    <button type="button" class="btn btn-error btn-sm" onclick="this.closest('.usenet-provider').remove();">\n                        <i class="bi bi-trash"></i>\n                    </button>\n                </div>\n\n                <div class="grid grid-cols-1 lg:grid-cols-2 gap-4">\n                    <div>\n                        <label class="label" for="usenet_provider_${e}_host">\n                            <span class="font-medium">Server Host</span>\n                        </label>\n                        <input type="text" class="input w-full"\n                               name="usenet.providers[${e}].host"\n                               id="usenet_provider_${e}_host"\n                               placeholder="news.usenetexpress.com"\n                               required>\n                        <span class="text-sm opacity-70">NNTP server hostname</span>\n                    </div>\n                    <div>\n                        <label class="label" for="usenet_provider_${e}_username">\n                            <span class="font-medium">Username</span>\n                        </label>\n                        <input type="text" class="input w-full"\n                               name="usenet.providers[${e}].username"\n                               id="usenet_provider_${e}_username"\n                               autocomplete="off">\n                        <span class="text-sm opacity-70">NNTP username</span>\n                    </div>\n                    <div>\n                        <label class="label" for="usenet_provider_${e}_password">\n                            <span class="font-medium">Password</span>\n                        </label>\n                        <div class="password-toggle-container">\n                            <input type="password" class="input input-has-toggle"\n                                   name="usenet.providers[${e}].password"\n                                   id="usenet_provider_${e}_password"\n                                   autocomplete="new-password">\n                            <button type="button" class="password-toggle-btn">\n                                <i class="bi bi-eye" id="usenet_provider_${e}_password_icon"></i>\n                            </button>\n                        </div>\n                        <span class="text-sm opacity-70">NNTP password</span>\n                    </div>\n\n                    <div>\n                        <label class="label" for="usenet_provider_${e}_port">\n                            <span class="font-medium">Port</span>\n                        </label>\n                        <input type="number" class="input w-full"\n                               name="usenet.providers[${e}].port"\n                               id="usenet_provider_${e}_port"\n                               placeholder="119"\n                               min="1" max="65535"\n                               value="119">\n                        <span class="text-sm opacity-70">NNTP port (563 for SSL, 119 for plain)</span>\n                    </div>\n                    <div>\n                        <label class="label" for="usenet_provider_${e}_backbone">\n                            <span class="font-medium">Backbone</span>\n                        </label>\n                        <input type="text" class="input w-full"\n                               name="usenet.providers[${e}].backbone"\n                               id="usenet_provider_${e}_backbone"\n                               placeholder="Omicron">\n                        <span class="text-sm opacity-70">Optional shared article backbone for smarter 430 failover</span>\n                    </div>\n\n                    <div>\n                        <label class="label" for="usenet_provider_${e}_max_connections">\n                            <span class="font-medium">Max Connections</span>\n                        </label>\n                        <input type="number" class="input w-full"\n                               name="usenet.providers[${e}].max_connections"\n                               id="usenet_provider_${e}_max_connections">\n                        <span class="text-sm opacity-70">Max connections for this provider</span>\n                    </div>\n                    <div>\n                        <label class="label" for="usenet_provider_${e}_priority">\n                            <span class="font-medium">Priority</span>\n                        </label>\n                        <input type="number" class="input w-full"\n                               name="usenet.providers[${e}].priority"\n                               id="usenet_provider_${e}_priority">\n                        <span class="text-sm opacity-70">Priority for this provider (lower number = higher priority)</span>\n                    </div>\n                </div>\n\n                <div class="flex flex-wrap gap-4 mt-4">\n                    <label class="flex items-center gap-2 cursor-pointer">\n                        <input type="checkbox" class="checkbox checkbox-primary checkbox-sm"\n                               name="usenet.providers[${e}].ssl"\n                               id="usenet_provider_${e}_ssl">\n                        <span class="text-sm">Use SSL</span>\n                    </label>\n                    <label class="flex items-center gap-2 cursor-pointer"\n                           title="Only used when every non-backup provider is excluded (article not found, connection errors). Not used just because primary pools are busy — requests wait for a primary slot instead. Use this for block providers you only want to bill for completion.">\n                        <input type="checkbox" class="checkbox checkbox-warning checkbox-sm"\n                               name="usenet.providers[${e}].backup"\n                               id="usenet_provider_${e}_backup">\n                        <span class="text-sm">Backup provider (fallback only)</span>\n                    </label>\n                </div>\n            </div>\n        </div>\n        `}}
\ No newline at end of file
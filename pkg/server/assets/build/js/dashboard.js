@@ -1 +1 @@
-class TorrentDashboard{constructor(){this.state={torrents:[],selectedEntries:new Set,categories:[],total:0,currentPage:1,itemsPerPage:20,totalPages:0,searchQuery:"",selectedCategory:"",selectedState:"",sortBy:"added_on",sortOrder:"desc",selectedTorrentContextMenu:null},this.refs={torrentsList:document.getElementById("torrentsList"),searchInput:document.getElementById("searchInput"),categoryFilter:document.getElementById("categoryFilter"),stateFilter:document.getElementById("stateFilter"),sortSelector:document.getElementById("sortSelector"),selectAll:document.getElementById("selectAll"),batchDeleteBtn:document.getElementById("batchDeleteBtn"),batchDeleteDebridBtn:document.getElementById("batchDeleteDebridBtn"),refreshBtn:document.getElementById("refreshBtn"),torrentContextMenu:document.getElementById("torrentContextMenu"),paginationControls:document.getElementById("paginationControls"),paginationInfo:document.getElementById("paginationInfo"),emptyState:document.getElementById("emptyState")},this.searchTimeout=null,this.init()}init(){this.bindEvents(),this.loadTorrents(),this.startAutoRefresh()}bindEvents(){this.refs.refreshBtn.addEventListener("click",()=>this.loadTorrents()),this.refs.batchDeleteBtn.addEventListener("click",()=>this.deleteSelectedTorrents()),this.refs.batchDeleteDebridBtn.addEventListener("click",()=>this.deleteSelectedTorrents(!0)),this.refs.selectAll.addEventListener("change",t=>this.toggleSelectAll(t.target.checked)),this.refs.searchInput.addEventListener("input",t=>{clearTimeout(this.searchTimeout),this.searchTimeout=setTimeout(()=>{this.state.searchQuery=t.target.value,this.state.currentPage=1,this.loadTorrents()},300)}),this.refs.categoryFilter.addEventListener("change",t=>{this.state.selectedCategory=t.target.value,this.state.currentPage=1,this.loadTorrents()}),this.refs.stateFilter.addEventListener("change",t=>{this.state.selectedState=t.target.value,this.state.currentPage=1,this.loadTorrents()}),this.refs.sortSelector.addEventListener("change",t=>{const e=t.target.value;e.endsWith("_asc")?(this.state.sortBy=e.replace("_asc",""),this.state.sortOrder="asc"):e.endsWith("_desc")?(this.state.sortBy=e.replace("_desc",""),this.state.sortOrder="desc"):(this.state.sortBy=e,this.state.sortOrder="desc"),this.state.currentPage=1,this.loadTorrents()}),this.bindContextMenu(),this.refs.torrentsList.addEventListener("change",t=>{t.target.classList.contains("torrent-select")&&this.toggleTorrentSelection(t.target.dataset.hash,t.target.checked)})}bindContextMenu(){this.refs.torrentsList.addEventListener("contextmenu",t=>{const e=t.target.closest("tr[data-hash]");e&&(t.preventDefault(),this.showContextMenu(t,e))}),document.addEventListener("click",t=>{this.refs.torrentContextMenu.contains(t.target)||this.hideContextMenu()}),this.refs.torrentContextMenu.addEventListener("click",t=>{const e=t.target.closest("[data-action]")?.dataset.action;e&&(this.handleContextAction(e),this.hideContextMenu())})}showContextMenu(t,e){this.state.selectedTorrentContextMenu={hash:e.dataset.hash,name:e.dataset.name,category:e.dataset.category||""},this.refs.torrentContextMenu.querySelector(".torrent-name").textContent=this.state.selectedTorrentContextMenu.name;const{pageX:s,pageY:r}=t,{clientWidth:n,clientHeight:a}=document.documentElement,o=this.refs.torrentContextMenu;o.style.left=`${Math.min(s,n-200)}px`,o.style.top=`${Math.min(r,a-150)}px`,o.classList.remove("hidden")}hideContextMenu(){this.refs.torrentContextMenu.classList.add("hidden"),this.state.selectedTorrentContextMenu=null}async handleContextAction(t){const e=this.state.selectedTorrentContextMenu;if(!e)return;const s={"copy-magnet":async()=>{try{await navigator.clipboard.writeText(`magnet:?xt=urn:btih:${e.hash}`),window.decypharrUtils.createToast("Magnet link copied to clipboard")}catch(t){window.decypharrUtils.createToast("Failed to copy magnet link","error")}},"copy-name":async()=>{try{await navigator.clipboard.writeText(e.name),window.decypharrUtils.createToast("Torrent name copied to clipboard")}catch(t){window.decypharrUtils.createToast("Failed to copy torrent name","error")}},delete:async()=>{await this.deleteTorrent(e.hash,e.category,!1)},"delete-debrid":async()=>{await this.deleteTorrent(e.hash,e.category,!0)}};s[t]&&await s[t]()}async loadTorrents(){try{this.refs.refreshBtn.disabled=!0,this.refs.paginationInfo.textContent="Loading torrents...";const t=new URLSearchParams({page:this.state.currentPage,limit:this.state.itemsPerPage,sort_by:this.state.sortBy,sort_order:this.state.sortOrder});this.state.searchQuery&&t.set("search",this.state.searchQuery),this.state.selectedCategory&&t.set("category",this.state.selectedCategory),this.state.selectedState&&t.set("state",this.state.selectedState);const e=await window.decypharrUtils.fetcher(`/api/torrents?${t}`);if(!e.ok)throw new Error("Failed to fetch items");const s=await e.json();this.state.torrents=s.torrents||[],this.state.total=s.total||0,this.state.totalPages=s.total_pages||0,this.state.categories=s.categories||[],this.updateUI()}catch(t){console.error("Error loading items:",t),window.decypharrUtils.createToast(`Error loading items: ${t.message}`,"error")}finally{this.refs.refreshBtn.disabled=!1}}updateUI(){this.updateCategoryFilter(),this.renderTorrents(),this.renderPagination(),this.updateSelectionUI(),this.toggleEmptyState()}updateCategoryFilter(){const t=this.refs.categoryFilter.value;this.refs.categoryFilter.innerHTML='<option value="">All Categories</option>',this.state.categories.forEach(e=>{const s=document.createElement("option");s.value=e,s.textContent=e,e===t&&(s.selected=!0),this.refs.categoryFilter.appendChild(s)})}renderTorrents(){0!==this.state.torrents.length?this.refs.torrentsList.innerHTML=this.state.torrents.map(t=>{const e=this.state.selectedEntries.has(t.info_hash);return`\n                <tr class="hover" data-hash="${t.info_hash}" data-name="${this.escapeHtml(t.name)}" data-category="${this.escapeHtml(t.category||"")}">\n                    <td>\n                        <label class="cursor-pointer">\n                            <input type="checkbox" class="checkbox checkbox-sm checkbox-primary torrent-select"\n                                   data-hash="${t.info_hash}" ${e?"checked":""}>\n                        </label>\n                    </td>\n                    <td>\n                        <div class="flex flex-col">\n                            <span class="font-medium">${this.escapeHtml(t.name)}</span>\n                            <span class="text-xs text-base-content/60 font-mono">${t.info_hash.substring(0,8)}...</span>\n                        </div>\n                    </td>\n                    <td>\n                        <span class="badge badge-ghost">${this.formatSize(t.size)}</span>\n                    </td>\n                    <td>\n                        ${this.renderProgressBar(t.progress)}\n                    </td>\n                    <td>\n                        <span class="text-sm">${this.formatSpeed(t.speed??t.dlspeed)}</span>\n                    </td>\n                    <td>\n                        ${t.category?`<span class="badge badge-sm badge-outline">${this.escapeHtml(t.category)}</span>`:"-"}\n                    </td>\n                    <td>\n                        ${this.renderProtocolBadge(t.protocol)}\n                    </td>\n                    <td>\n                        ${t.debrid?`<span class="badge badge-sm badge-primary">${this.escapeHtml(t.debrid)}</span>`:"-"}\n                    </td>\n                    <td>\n                        <span class="text-sm">${t.num_seeds||0}</span>\n                    </td>\n                    <td>\n                        ${this.renderStateBadge(t.state)}\n                    </td>\n                    <td>\n                        <button class="btn btn-ghost btn-xs text-error"\n                                title="Delete Torrent"\n                                onclick="window.dashboard.deleteTorrent('${t.info_hash}', '${this.escapeAttr(t.category||"")}', false);">\n                            <i class="bi bi-trash"></i>\n                        </button>\n                        <button class="btn btn-ghost btn-xs text-error"\n                                title="Delete from Provider"\n                                onclick="window.dashboard.deleteTorrent('${t.info_hash}', '${this.escapeAttr(t.category||"")}', true);">\n                            <i class="bi bi-cloud-slash"></i>\n                        </button>\n                    </td>\n                </tr>\n            `}).join(""):this.refs.torrentsList.innerHTML=""}renderProgressBar(t){const e=Math.round(100*t);let s="progress-info";return 100===e?s="progress-success":e<25?s="progress-error":e<75&&(s="progress-warning"),`\n            <div class="flex items-center gap-2">\n                <progress class="progress ${s} w-20" value="${e}" max="100"></progress>\n                <span class="text-xs font-medium">${e}%</span>\n            </div>\n        `}renderStateBadge(t){const e={pausedUP:{class:"badge-success",text:"Completed"},downloading:{class:"badge-info",text:"Downloading"},error:{class:"badge-error",text:"Error"},queued:{class:"badge-ghost",text:"Queued"},paused:{class:"badge-warning",text:"Paused"}}[t]||{class:"badge-ghost",text:t};return`<span class="badge ${e.class} badge-sm">${e.text}</span>`}renderProtocolBadge(t){const e={torrent:{class:"badge-accent",icon:"bi-magnet",text:"Torrent"},nzb:{class:"badge-secondary",icon:"bi-newspaper",text:"Usenet"}}[t]||{class:"badge-ghost",icon:"bi-question-circle",text:t||"Unknown"};return`<span class="badge ${e.class} badge-sm"><i class="${e.icon} mr-1"></i>${e.text}</span>`}renderPagination(){const t=(this.state.currentPage-1)*this.state.itemsPerPage+1,e=Math.min(t+this.state.itemsPerPage-1,this.state.total);if(this.refs.paginationInfo.textContent=this.state.total>0?`Showing ${t}-${e} of ${this.state.total} items`:"No items found",this.state.totalPages<=1)return void(this.refs.paginationControls.innerHTML="");let s=`\n            <button class="join-item btn btn-sm ${1===this.state.currentPage?"btn-disabled":""}"\n                    onclick="window.dashboard.goToPage(${this.state.currentPage-1});">«</button>\n        `;for(let t=1;t<=this.state.totalPages;t++)1===t||t===this.state.totalPages||t>=this.state.currentPage-2&&t<=this.state.currentPage+2?s+=`\n                    <button class="join-item btn btn-sm ${t===this.state.currentPage?"btn-active":""}"\n                            onclick="window.dashboard.goToPage(${t});">${t}</button>\n                `:t!==this.state.currentPage-3&&t!==this.state.currentPage+3||(s+='<button class="join-item btn btn-sm btn-disabled">...</button>');s+=`\n            <button class="join-item btn btn-sm ${this.state.currentPage===this.state.totalPages?"btn-disabled":""}"\n                    onclick="window.dashboard.goToPage(${this.state.currentPage+1})">»</button>\n        `,this.refs.paginationControls.innerHTML=s}goToPage(t){t<1||t>this.state.totalPages||(this.state.currentPage=t,this.loadTorrents())}toggleEmptyState(){const t=this.state.total>0;this.refs.emptyState.classList.toggle("hidden",t),this.refs.torrentsList.closest(".card").classList.toggle("hidden",!t)}toggleSelectAll(t){t?this.state.torrents.forEach(t=>this.state.selectedEntries.add(t.info_hash)):this.state.selectedEntries.clear(),this.renderTorrents(),this.updateSelectionUI()}toggleTorrentSelection(t,e){e?this.state.selectedEntries.add(t):this.state.selectedEntries.delete(t),this.updateSelectionUI()}updateSelectionUI(){const t=this.state.selectedEntries.size>0;this.refs.batchDeleteBtn.classList.toggle("hidden",!t),this.refs.batchDeleteDebridBtn.classList.toggle("hidden",!t);const e=this.state.torrents.length>0&&this.state.torrents.every(t=>this.state.selectedEntries.has(t.info_hash));this.refs.selectAll.checked=e}async deleteTorrent(t,e,s=!1){if(confirm("Are you sure you want to delete this torrent?"))try{const r=`${window.urlBase}api/torrents/${e}/${t}?removeFromDebrid=${s}`;if(!(await window.decypharrUtils.fetcher(r,{method:"DELETE"})).ok)throw new Error("Failed to delete entry");window.decypharrUtils.createToast("Item deleted successfully"),this.state.selectedEntries.delete(t),this.loadTorrents()}catch(t){console.error("Error deleting torrent:",t),window.decypharrUtils.createToast("Failed to delete entry","error")}}async deleteSelectedTorrents(t=!1){if(0!==this.state.selectedEntries.size&&confirm(`Delete ${this.state.selectedEntries.size} selected items?`))try{const e=Array.from(this.state.selectedEntries).join(","),s=`${window.urlBase}api/torrents?hashes=${e}&removeFromDebrid=${t}`;if(!(await window.decypharrUtils.fetcher(s,{method:"DELETE"})).ok)throw new Error("Failed to delete items");window.decypharrUtils.createToast(`Deleted ${this.state.selectedEntries.size} items successfully`),this.state.selectedEntries.clear(),this.loadTorrents()}catch(t){console.error("Error deleting items:",t),window.decypharrUtils.createToast("Failed to delete items","error")}}startAutoRefresh(){setInterval(()=>{this.loadTorrents()},1e4)}formatSize(t){if(!t||0===t)return"0 B";const e=Math.floor(Math.log(t)/Math.log(1024));return parseFloat((t/Math.pow(1024,e)).toFixed(2))+" "+["B","KB","MB","GB","TB"][e]}formatSpeed(t){return t&&0!==t?this.formatSize(t)+"/s":"-"}escapeHtml(t){if(!t)return"";const e=document.createElement("div");return e.textContent=t,e.innerHTML}escapeAttr(t){return t?t.replace(/'/g,"&#39;").replace(/"/g,"&quot;"):""}}
\ No newline at end of file
+class TorrentDashboard{constructor(){this.state={torrents:[],selectedEntries:new Set,categories:[],total:0,currentPage:1,itemsPerPage:20,totalPages:0,searchQuery:"",selectedCategory:"",selectedState:"",sortBy:"added_on",sortOrder:"desc",selectedTorrentContextMenu:null},this.refs={torrentsList:document.getElementById("torrentsList"),searchInput:document.getElementById("searchInput"),categoryFilter:document.getElementById("categoryFilter"),stateFilter:document.getElementById("stateFilter"),sortSelector:document.getElementById("sortSelector"),selectAll:document.getElementById("selectAll"),batchDeleteBtn:document.getElementById("batchDeleteBtn"),batchDeleteDebridBtn:document.getElementById("batchDeleteDebridBtn"),refreshBtn:document.getElementById("refreshBtn"),torrentContextMenu:document.getElementById("torrentContextMenu"),paginationControls:document.getElementById("paginationControls"),paginationInfo:document.getElementById("paginationInfo"),emptyState:document.getElementById("emptyState")},this.searchTimeout=null,this.init()}init(){this.bindEvents(),this.loadTorrents(),this.startAutoRefresh()}bindEvents(){this.refs.refreshBtn.addEventListener("click",()=>this.loadTorrents()),this.refs.batchDeleteBtn.addEventListener("click",()=>this.deleteSelectedTorrents()),this.refs.batchDeleteDebridBtn.addEventListener("click",()=>this.deleteSelectedTorrents(!0)),this.refs.selectAll.addEventListener("change",t=>this.toggleSelectAll(t.target.checked)),this.refs.searchInput.addEventListener("input",t=>{clearTimeout(this.searchTimeout),this.searchTimeout=setTimeout(()=>{this.state.searchQuery=t.target.value,this.state.currentPage=1,this.loadTorrents()},300)}),this.refs.categoryFilter.addEventListener("change",t=>{this.state.selectedCategory=t.target.value,this.state.currentPage=1,this.loadTorrents()}),this.refs.stateFilter.addEventListener("change",t=>{this.state.selectedState=t.target.value,this.state.currentPage=1,this.loadTorrents()}),this.refs.sortSelector.addEventListener("change",t=>{const e=t.target.value;e.endsWith("_asc")?(this.state.sortBy=e.replace("_asc",""),this.state.sortOrder="asc"):e.endsWith("_desc")?(this.state.sortBy=e.replace("_desc",""),this.state.sortOrder="desc"):(this.state.sortBy=e,this.state.sortOrder="desc"),this.state.currentPage=1,this.loadTorrents()}),this.bindContextMenu(),this.refs.torrentsList.addEventListener("change",t=>{t.target.classList.contains("torrent-select")&&this.toggleTorrentSelection(t.target.dataset.hash,t.target.checked)})}bindContextMenu(){this.refs.torrentsList.addEventListener("contextmenu",t=>{const e=t.target.closest("tr[data-hash]");e&&(t.preventDefault(),this.showContextMenu(t,e))}),document.addEventListener("click",t=>{this.refs.torrentContextMenu.contains(t.target)||this.hideContextMenu()}),this.refs.torrentContextMenu.addEventListener("click",t=>{const e=t.target.closest("[data-action]")?.dataset.action;e&&(this.handleContextAction(e),this.hideContextMenu())})}showContextMenu(t,e){this.state.selectedTorrentContextMenu={hash:e.dataset.hash,name:e.dataset.name,category:e.dataset.category||""},this.refs.torrentContextMenu.querySelector(".torrent-name").textContent=this.state.selectedTorrentContextMenu.name;const{pageX:s,pageY:r}=t,{clientWidth:n,clientHeight:a}=document.documentElement,o=this.refs.torrentContextMenu;o.style.left=`${Math.min(s,n-200)}px`,o.style.top=`${Math.min(r,a-150)}px`,o.classList.remove("hidden")}hideContextMenu(){this.refs.torrentContextMenu.classList.add("hidden"),this.state.selectedTorrentContextMenu=null}async handleContextAction(t){const e=this.state.selectedTorrentContextMenu;if(!e)return;const s={"copy-magnet":async()=>{try{await navigator.clipboard.writeText(`magnet:?xt=urn:btih:${e.hash}`),window.decypharrUtils.createToast("Magnet link copied to clipboard")}catch(t){window.decypharrUtils.createToast("Failed to copy magnet link","error")}},"copy-name":async()=>{try{await navigator.clipboard.writeText(e.name),window.decypharrUtils.createToast("Torrent name copied to clipboard")}catch(t){window.decypharrUtils.createToast("Failed to copy torrent name","error")}},delete:async()=>{await this.deleteTorrent(e.hash,e.category,!1)},"delete-debrid":async()=>{await this.deleteTorrent(e.hash,e.category,!0)}};s[t]&&await s[t]()}async loadTorrents(){try{this.refs.refreshBtn.disabled=!0,this.refs.paginationInfo.textContent="Loading torrents...";const t=new URLSearchParams({page:this.state.currentPage,limit:this.state.itemsPerPage,sort_by:this.state.sortBy,sort_order:this.state.sortOrder});this.state.searchQuery&&t.set("search",this.state.searchQuery),this.state.selectedCategory&&t.set("category",this.state.selectedCategory),this.state.selectedState&&t.set("state",this.state.selectedState);const e=await window.decypharrUtils.fetcher(`/api/torrents?${t}`);if(!e.ok)throw new Error("Failed to fetch items");const s=await e.json();this.state.torrents=s.torrents||[],this.state.total=s.total||0,this.state.totalPages=s.total_pages||0,this.state.categories=s.categories||[],this.updateUI()}catch(t){console.error("Error loading items:",t),window.decypharrUtils.createToast(`Error loading items: ${t.message}`,"error")}finally{this.refs.refreshBtn.disabled=!1}}updateUI(){this.updateCategoryFilter(),this.renderTorrents(),this.renderPagination(),this.updateSelectionUI(),this.toggleEmptyState()}updateCategoryFilter(){const t=this.refs.categoryFilter.value;this.refs.categoryFilter.innerHTML='<option value="">All Categories</option>',this.state.categories.forEach(e=>{const s=document.createElement("option");s.value=e,s.textContent=e,e===t&&(s.selected=!0),this.refs.categoryFilter.appendChild(s)})}renderTorrents(){0!==this.state.torrents.length?this.refs.torrentsList.innerHTML=this.state.torrents.map(t=>{const e=this.state.selectedEntries.has(t.info_hash);return`\n                <tr class="hover" data-hash="${t.info_hash}" data-name="${this.escapeHtml(t.name)}" data-category="${this.escapeHtml(t.category||"")}">\n                    <td>\n                        <label class="cursor-pointer">\n                            <input type="checkbox" class="checkbox checkbox-sm checkbox-primary torrent-select"\n                                   data-hash="${t.info_hash}" ${e?"checked":""}>\n                        </label>\n                    </td>\n                    <td>\n                        <div class="flex flex-col">\n                            <span class="font-medium">${this.escapeHtml(t.name)}</span>\n                            <span class="text-xs text-base-content/60 font-mono">${t.info_hash.substring(0,8)}...</span>\n                        </div>\n                    </td>\n                    <td>\n                        <span class="badge badge-ghost">${this.formatSize(t.size)}</span>\n                    </td>\n                    <td>\n                        ${this.renderProgressBar(t.progress)}\n                    </td>\n                    <td>\n                        <span class="text-sm">${this.formatSpeed(t.speed??t.dlspeed)}</span>\n                    </td>\n                    <td>\n                        ${t.category?`<span class="badge badge-sm badge-outline">${this.escapeHtml(t.category)}</span>`:"-"}\n                    </td>\n                    <td>\n                        ${this.renderProtocolBadge(t.protocol)}\n                    </td>\n                    <td>\n                        ${t.debrid?`<span class="badge badge-sm badge-primary">${this.escapeHtml(t.debrid)}</span>`:"-"}\n                    </td>\n                    <td>\n                        <span class="text-sm">${t.num_seeds||0}</span>\n                    </td>\n                    <td>\n                        ${this.renderStateBadge(t.state)}\n                    </td>\n                    <td>\n                        <button class="btn btn-ghost btn-xs text-error"\n                                title="Delete Torrent"\n                                onclick="window.dashboard.deleteTorrent('${t.info_hash}', '${this.escapeAttr(t.category||"")}', false);">\n                            <i class="bi bi-trash"></i>\n                        </button>\n                        <button class="btn btn-ghost btn-xs text-error"\n                                title="Delete from Provider"\n                                onclick="window.dashboard.deleteTorrent('${t.info_hash}', '${this.escapeAttr(t.category||"")}', true);">\n                            <i class="bi bi-cloud-slash"></i>\n                        </button>\n                    </td>\n                </tr>\n            `}).join(""):this.refs.torrentsList.innerHTML=""}renderProgressBar(t){const e=Math.round(100*t);let s="progress-info";return 100===e?s="progress-success":e<25?s="progress-error":e<75&&(s="progress-warning"),`\n            <div class="flex items-center gap-2">\n                <progress class="progress ${s} w-20" value="${e}" max="100"></progress>\n                <span class="text-xs font-medium">${e}%</span>\n            </div>\n        `}renderStateBadge(t){const e={pausedUP:{class:"badge-success",text:"Completed"},downloading:{class:"badge-info",text:"Downloading"},error:{class:"badge-error",text:"Error"},queued:{class:"badge-ghost",text:"Queued"},paused:{class:"badge-warning",text:"Paused"}}[t]||{class:"badge-ghost",text:t};return`<span class="badge ${e.class} badge-sm">${e.text}</span>`}renderProtocolBadge(t){const e={torrent:{class:"badge-accent",icon:"bi-magnet",text:"Torrent"},nzb:{class:"badge-secondary",icon:"bi-newspaper",text:"Usenet"}}[t]||{class:"badge-ghost",icon:"bi-question-circle",text:t||"Unknown"};return`<span class="badge ${e.class} badge-sm"><i class="${e.icon} mr-1"></i>${e.text}</span>`}renderPagination(){const t=(this.state.currentPage-1)*this.state.itemsPerPage+1,e=Math.min(t+this.state.itemsPerPage-1,this.state.total);if(this.refs.paginationInfo.textContent=this.state.total>0?`Showing ${t}-${e} of ${this.state.total} items`:"No items found",this.state.totalPages<=1)return void(this.refs.paginationControls.innerHTML="");let s=`\n            <button class="join-item btn btn-sm ${1===this.state.currentPage?"btn-disabled":""}"\n                    onclick="window.dashboard.goToPage(${this.state.currentPage-1});">«</button>\n        `;for(let t=1;t<=this.state.totalPages;t++)1===t||t===this.state.totalPages||t>=this.state.currentPage-2&&t<=this.state.currentPage+2?s+=`\n                    <button class="join-item btn btn-sm ${t===this.state.currentPage?"btn-active":""}"\n                            onclick="window.dashboard.goToPage(${t});">${t}</button>\n                `:t!==this.state.currentPage-3&&t!==this.state.currentPage+3||(s+='<button class="join-item btn btn-sm btn-disabled">...</button>');s+=`\n            <button class="join-item btn btn-sm ${this.state.currentPage===this.state.totalPages?"btn-disabled":""}"\n                    onclick="window.dashboard.goToPage(${this.state.currentPage+1})">»</button>\n        `,this.refs.paginationControls.innerHTML=s}goToPage(t){t<1||t>this.state.totalPages||(this.state.currentPage=t,this.loadTorrents())}toggleEmptyState(){const t=this.state.total>0;this.refs.emptyState.classList.toggle("hidden",t),this.refs.torrentsList.closest(".card").classList.toggle("hidden",!t)}toggleSelectAll(t){t?this.state.torrents.forEach(t=>this.state.selectedEntries.add(t.info_hash)):this.state.selectedEntries.clear(),this.renderTorrents(),this.updateSelectionUI()}toggleTorrentSelection(t,e){e?this.state.selectedEntries.add(t):this.state.selectedEntries.delete(t),this.updateSelectionUI()}updateSelectionUI(){const t=this.state.selectedEntries.size>0;this.refs.batchDeleteBtn.classList.toggle("hidden",!t),this.refs.batchDeleteDebridBtn.classList.toggle("hidden",!t);const e=this.state.torrents.length>0&&this.state.torrents.every(t=>this.state.selectedEntries.has(t.info_hash));this.refs.selectAll.checked=e}async deleteTorrent(t,e,s=!1){if(confirm("Are you sure you want to delete this torrent?"))try{const r=`${window.urlBase}api/torrents/${e}/${t}?removeFromDebrid=${s}`;if(!(await window.decypharrUtils.fetcher(r,{method:"DELETE"})).ok)throw new Error("Failed to delete entry");window.decypharrUtils.createToast("Item deleted successfully"),this.state.selectedEntries.delete(t),this.loadTorrents()}catch(t){console.error("Error deleting torrent:",t),window.decypharrUtils.createToast("Failed to delete entry","error")}}async deleteSelectedTorrents(t=!1){if(0!==this.state.selectedEntries.size&&confirm(`Delete ${this.state.selectedEntries.size} selected items?`))try{const e=Array.from(this.state.selectedEntries).join(","),s=`${window.urlBase}api/torrents?hashes=${e}&removeFromDebrid=${t}`;if(!(await window.decypharrUtils.fetcher(s,{method:"DELETE"})).ok)throw new Error("Failed to delete items");window.decypharrUtils.createToast(`Deleted ${this.state.selectedEntries.size} items successfully`),this.state.selectedEntries.clear(),this.loadTorrents()}catch(t){console.error("Error deleting items:",t),window.decypharrUtils.createToast("Failed to delete items","error")}}startAutoRefresh(){const t=!!window.EventSource;if(t){const t=window.decypharrUtils,e=new EventSource(t.joinURL(t.urlBase,"/api/events?topics=queue"));let s=null;const i=()=>{s||(s=setTimeout(()=>{s=null,this.loadTorrents()},1e3))};e.addEventListener("queue",i),e.addEventListener("reset",i)}setInterval(()=>{this.loadTorrents()},t?3e4:1e4)}formatSize(t){if(!t||0===t)return"0 B";const e=Math.floor(Math.log(t)/Math.log(1024));return parseFloat((t/Math.pow(1024,e)).toFixed(2))+" "+["B","KB","MB","GB","TB"][e]}formatSpeed(t){return t&&0!==t?this.formatSize(t)+"/s":"-"}escapeHtml(t){if(!t)return"";const e=document.createElement("div");return e.textContent=t,e.innerHTML}escapeAttr(t){return t?t.replace(/'/g,"&#39;").replace(/"/g,"&quot;"):""}}
\ No newline at end of file
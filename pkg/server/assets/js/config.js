@@ -89,6 +89,7 @@ class ConfigManager {
             }
 
             const config = await response.json();
+            this.loadedConfig = config;
             this.populateForm(config);
 
         } catch (error) {
@@ -163,6 +164,8 @@ class ConfigManager {
         if ($('repair.workers')) $('repair.workers').value = repair.workers || 5;
         if ($('repair.nntp_connection_percent')) $('repair.nntp_connection_percent').value = repair.nntp_connection_percent || 20;
         if ($('repair.strategy')) $('repair.strategy').value = repair.strategy || 'per_entry';
+        if ($('repair.piece_verify')) $('repair.piece_verify').checked = !!repair.piece_verify;
+        if ($('repair.piece_verify_samples')) $('repair.piece_verify_samples').value = repair.piece_verify_samples || 4;
         if ($('repair.auto_repair')) $('repair.auto_repair').checked = !!repair.auto_repair;
         if ($('repair.skip_nzb_repair')) $('repair.skip_nzb_repair').checked = !!repair.skip_nzb_repair;
     }
@@ -181,6 +184,8 @@ class ConfigManager {
             workers: parseInt($('repair.workers')?.value, 10) || 0,
             nntp_connection_percent: parseInt($('repair.nntp_connection_percent')?.value, 10) || 0,
             strategy: $('repair.strategy')?.value || 'per_entry',
+            piece_verify: $('repair.piece_verify')?.checked || false,
+            piece_verify_samples: parseInt($('repair.piece_verify_samples')?.value, 10) || 0,
             auto_repair: $('repair.auto_repair')?.checked || false,
             skip_nzb_repair: $('repair.skip_nzb_repair')?.checked || false,
             arrs,
@@ -215,7 +220,7 @@ class ConfigManager {
     populateDownloadSettings(config) {
         const fields = [
             'remove_stalled_after', 'nzb_user_agent', 'download_folder',
-            'refresh_interval', 'max_active_downloads', 'skip_pre_cache',
+            'refresh_interval', 'max_active_downloads', 'skip_pre_cache', 'probe_media',
             'always_rm_tracker_urls', 'default_download_action'
         ];
 
@@ -317,7 +322,7 @@ class ConfigManager {
         if (!dfsConfig) return;
 
         const fields = [
-            'cache_dir', 'disk_cache_size', 'buffer_memory', 'cache_expiry', 'cache_cleanup_interval',
+            'cache_dir', 'disk_cache_size', 'pinned_cache_size', 'buffer_memory', 'cache_expiry', 'cache_cleanup_interval',
             'chunk_size', 'read_ahead_size', 'daemon_timeout',
             'uid', 'gid', 'umask'
         ];
@@ -582,6 +587,60 @@ class ConfigManager {
                         <span class="text-sm opacity-70">Automatically expire links after this duration</span>
                     </div>
                 </div>
+                <div class="grid grid-cols-1 lg:grid-cols-3 gap-6 mt-6">
+                    <div>
+                        <label class="label" for="debrid[${index}].daily_traffic_limit">
+                            <span class=" font-medium">Daily Traffic Budget</span>
+                        </label>
+                        <input type="text" class="input w-full" 
+                               name="debrid[${index}].daily_traffic_limit" 
+                               id="debrid[${index}].daily_traffic_limit" 
+                               placeholder="e.g. 500GB">
+                        <span class="text-sm opacity-70">Per download account. Leave empty for no limit</span>
+                    </div>
+                    <div>
+                        <label class="label" for="debrid[${index}].monthly_traffic_limit">
+                            <span class=" font-medium">Monthly Traffic Budget</span>
+                        </label>
+                        <input type="text" class="input w-full" 
+                               name="debrid[${index}].monthly_traffic_limit" 
+                               id="debrid[${index}].monthly_traffic_limit" 
+                               placeholder="e.g. 5TB">
+                        <span class="text-sm opacity-70">Per download account. Leave empty for no limit</span>
+                    </div>
+                    <div>
+                        <label class="label" for="debrid[${index}].expiry_warning_days">
+                            <span class=" font-medium">Expiry Warning (days)</span>
+                        </label>
+                        <input type="number" class="input w-full" min="0"
+                               name="debrid[${index}].expiry_warning_days" 
+                               id="debrid[${index}].expiry_warning_days" 
+                               placeholder="0">
+                        <span class="text-sm opacity-70">Notify this many days before premium expires. 0 disables</span>
+                    </div>
+                </div>
+                <div class="grid grid-cols-1 lg:grid-cols-3 gap-6 mt-6">
+                    <div>
+                        <label class="label" for="debrid[${index}].torrent_max_age">
+                            <span class=" font-medium">Torrent Max Age</span>
+                        </label>
+                        <input type="text" class="input w-full" 
+                               name="debrid[${index}].torrent_max_age" 
+                               id="debrid[${index}].torrent_max_age" 
+                               placeholder="e.g. 30d">
+                        <span class="text-sm opacity-70">The debrid drops torrents this long after they're added. Leave empty if it doesn't</span>
+                    </div>
+                    <div>
+                        <label class="label" for="debrid[${index}].torrent_idle_expiry">
+                            <span class=" font-medium">Torrent Idle Expiry</span>
+                        </label>
+                        <input type="text" class="input w-full" 
+                               name="debrid[${index}].torrent_idle_expiry" 
+                               id="debrid[${index}].torrent_idle_expiry" 
+                               placeholder="e.g. 14d">
+                        <span class="text-sm opacity-70">The debrid drops torrents not streamed for this long. Leave empty if it doesn't</span>
+                    </div>
+                </div>
                 <div class="grid grid-cols-2 lg:grid-cols-3 gap-4 mt-6">
                     <div>
                         <label class="label cursor-pointer justify-start gap-2">
@@ -615,6 +674,17 @@ class ConfigManager {
                             </div>
                         </label>
                     </div>
+
+                    <div>
+                        <label class="label cursor-pointer justify-start gap-2">
+                            <input type="checkbox" class="checkbox checkbox-primary" 
+                                   name="debrid[${index}].usenet" id="debrid[${index}].usenet">
+                             <div>
+                                <span class="font-medium">Usenet</span>
+                                <div class="label-text-alt">Send NZBs to this debrid (TorBox, Premiumize)</div>
+                            </div>
+                        </label>
+                    </div>
                 </div>
         </div>
     `;
@@ -1187,6 +1257,7 @@ class ConfigManager {
             default_download_action: document.querySelector('[name="default_download_action"]')?.value || "symlink",
             max_active_downloads: parseInt(document.querySelector('[name="max_active_downloads"]').value) || 5,
             skip_pre_cache: document.querySelector('[name="skip_pre_cache"]').checked,
+            probe_media: document.querySelector('[name="probe_media"]').checked,
             always_rm_tracker_urls: document.querySelector('[name="always_rm_tracker_urls"]').checked,
             folder_naming: document.querySelector('[name="folder_naming"]')?.value || "",
             disable_webdav: document.querySelector('[name="disable_webdav"]').checked,
@@ -1212,7 +1283,19 @@ class ConfigManager {
             notifications: this.collectNotificationsConfig(),
 
             // Collect repair config
-            repair: this.collectRepairConfig()
+            repair: this.collectRepairConfig(),
+
+            // Settings without a form yet; keep them as loaded
+            file_selection: this.loadedConfig?.file_selection,
+            reconcile: this.loadedConfig?.reconcile,
+            retention: this.loadedConfig?.retention,
+            recycle_bin: this.loadedConfig?.recycle_bin,
+            guardian: this.loadedConfig?.guardian,
+            events: this.loadedConfig?.events,
+            buffers: this.loadedConfig?.buffers,
+            sftp: this.loadedConfig?.sftp,
+            category_profiles: this.loadedConfig?.category_profiles,
+            tag_rules: this.loadedConfig?.tag_rules
         };
     }
 
@@ -1288,6 +1371,7 @@ class ConfigManager {
             processing_timeout: document.querySelector('[name="usenet.processing_timeout"]')?.value || "5m",
             availability_sample_percent: parseInt(document.querySelector('[name="usenet.availability_sample_percent"]')?.value) || 10,
             import_availability_sample_percent: parseInt(document.querySelector('[name="usenet.import_availability_sample_percent"]')?.value) || 1,
+            prefer_debrid: document.querySelector('[name="usenet.prefer_debrid"]')?.checked || false,
             disk_buffer_path: document.querySelector('[name="usenet.disk_buffer_path"]')?.value || "",
             buffer_memory: document.querySelector('[name="usenet.buffer_memory"]')?.value || ""
         };
@@ -1335,6 +1419,7 @@ class ConfigManager {
                 download_uncached: downloadUncachedInput.checked,
                 unpack_rar: unpackRarInput.checked,
                 add_samples: addSamplesInput.checked,
+                usenet: getField('usenet')?.checked || false,
                 user_agent: userAgentInput.value
             };
 
@@ -1349,6 +1434,11 @@ class ConfigManager {
             debrid.torrents_refresh_interval = torrentsRefreshIntervalInput.value;
             debrid.download_links_refresh_interval = downloadLinksRefreshIntervalInput.value;
             debrid.auto_expire_links_after = autoExpireLinksAfterInput.value;
+            debrid.daily_traffic_limit = getField('daily_traffic_limit')?.value || '';
+            debrid.monthly_traffic_limit = getField('monthly_traffic_limit')?.value || '';
+            debrid.expiry_warning_days = parseInt(getField('expiry_warning_days')?.value) || 0;
+            debrid.torrent_max_age = getField('torrent_max_age')?.value || '';
+            debrid.torrent_idle_expiry = getField('torrent_idle_expiry')?.value || '';
 
             if (debrid.name && debrid.api_key && debrid.provider) {
                 debrids.push(debrid);
@@ -1477,7 +1567,8 @@ class ConfigManager {
             mount_path: document.querySelector('[name="mount.mount_path"]').value,
             dfs: this.collectDFSConfig(),
             rclone: this.collectRcloneConfig(),
-            external_rclone: this.collectExternalRclone()
+            external_rclone: this.collectExternalRclone(),
+            views: this.loadedConfig?.mount?.views
         };
     }
 
@@ -1551,6 +1642,7 @@ class ConfigManager {
         return {
             cache_dir: getElementValue('cache_dir'),
             disk_cache_size: getElementValue('disk_cache_size'),
+            pinned_cache_size: getElementValue('pinned_cache_size'),
             buffer_memory: getElementValue('buffer_memory'),
             cache_expiry: getElementValue('cache_expiry'),
             cache_cleanup_interval: getElementValue('cache_cleanup_interval'),
@@ -1618,11 +1710,11 @@ class ConfigManager {
         if (!customFolders) return;
 
         Object.entries(customFolders).forEach(([folderName, folderData]) => {
-            this.addVirtualFolder(folderName, folderData.filters);
+            this.addVirtualFolder(folderName, folderData.filters, folderData.expr);
         });
     }
 
-    addVirtualFolder(folderName = '', filters = {}) {
+    addVirtualFolder(folderName = '', filters = {}, expr = '') {
         const id = this.virtualFolderCount++;
         const filterEntries = Object.entries(filters);
 
@@ -1650,6 +1742,18 @@ class ConfigManager {
                             <span class="text-sm opacity-70">This folder will appear in your mount</span>
                         </div>
 
+                        <div>
+                            <label class="label">
+                                <span class="font-medium">Expression</span>
+                            </label>
+                            <input type="text"
+                                   class="input input-bordered w-full font-mono"
+                                   name="virtual_folder_${id}_expr"
+                                   value="${window.decypharrUtils.escapeHtml(expr)}"
+                                   placeholder='e.g., (name ~ "(?i)remux" or file = "*.iso") and size > 2GB and not tag = kids'>
+                            <span class="text-sm opacity-70">Optional. When set, it replaces the filters below</span>
+                        </div>
+
                         <div>
                             <label class="label">
                                 <span class="font-medium">Filters</span>
@@ -1766,7 +1870,9 @@ class ConfigManager {
                     }
                 });
 
-                customFolders[folderName] = {filters};
+                const exprInput = folderEl.querySelector(`[name="virtual_folder_${id}_expr"]`);
+                const expr = exprInput ? exprInput.value.trim() : '';
+                customFolders[folderName] = expr ? {expr} : {filters};
             }
         });
 
@@ -1798,6 +1904,11 @@ class ConfigManager {
                 input.value = value;
             }
         });
+
+        const preferDebrid = document.getElementsByName('usenet.prefer_debrid')[0];
+        if (preferDebrid) {
+            preferDebrid.checked = !!usenet.prefer_debrid;
+        }
     }
 
     addUsenetProvider(data = {}) {
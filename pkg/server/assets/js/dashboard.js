@@ -494,9 +494,25 @@ class TorrentDashboard {
     }
 
     startAutoRefresh() {
+        // Queue changes arrive over /api/events; polling stays as a slower fallback
+        const live = !!window.EventSource;
+        if (live) {
+            const utils = window.decypharrUtils;
+            const source = new EventSource(utils.joinURL(utils.urlBase, '/api/events?topics=queue'));
+            let pending = null;
+            const reload = () => {
+                if (pending) return;
+                pending = setTimeout(() => {
+                    pending = null;
+                    this.loadTorrents();
+                }, 1000);
+            };
+            source.addEventListener('queue', reload);
+            source.addEventListener('reset', reload);
+        }
         setInterval(() => {
             this.loadTorrents();
-        }, 10000); // Refresh every 10 seconds
+        }, live ? 30000 : 10000);
     }
 
     // Utility methods
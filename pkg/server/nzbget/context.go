@@ -0,0 +1,22 @@
+package nzbget
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// getCredentials reads the NZBGet control username and password, from basic
+// auth or from a user:pass path segment. As with the SABnzbd API, they are
+// the Arr's host and token.
+func getCredentials(r *http.Request) (string, string) {
+	if username, password, ok := r.BasicAuth(); ok {
+		return username, password
+	}
+	if credentials := chi.URLParam(r, "credentials"); credentials != "" {
+		username, password, _ := strings.Cut(credentials, ":")
+		return username, password
+	}
+	return "", ""
+}
@@ -0,0 +1,386 @@
+package nzbget
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/arr"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// errUnauthorized wraps authentication failures, which NZBGet answers with
+// HTTP 401 rather than an RPC error.
+var errUnauthorized = errors.New("unauthorized")
+
+// handleJSONRPC serves NZBGet's JSON-RPC endpoint
+func (n *NZBGet) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
+	call, err := decodeJSONRPC(r.Body)
+	if err != nil {
+		n.writeJSONError(w, nil, err)
+		return
+	}
+	result, err := n.dispatch(r, call)
+	if errors.Is(err, errUnauthorized) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		n.writeJSONError(w, call.ID, err)
+		return
+	}
+	response := map[string]any{
+		"version": "1.1",
+		"id":      call.ID,
+		"result":  result,
+	}
+	utils.JSONResponse(w, response, http.StatusOK)
+}
+
+// handleXMLRPC serves NZBGet's XML-RPC endpoint
+func (n *NZBGet) handleXMLRPC(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/xml")
+	call, err := decodeXMLRPC(r.Body)
+	if err != nil {
+		_ = encodeXMLRPCFault(w, 1, err.Error())
+		return
+	}
+	result, err := n.dispatch(r, call)
+	if errors.Is(err, errUnauthorized) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		_ = encodeXMLRPCFault(w, 1, err.Error())
+		return
+	}
+	if err := encodeXMLRPC(w, result); err != nil {
+		n.logger.Error().Err(err).Str("method", call.Method).Msg("Failed to encode XML-RPC response")
+	}
+}
+
+func (n *NZBGet) writeJSONError(w http.ResponseWriter, id any, err error) {
+	response := map[string]any{
+		"version": "1.1",
+		"id":      id,
+		"result":  nil,
+		"error": map[string]any{
+			"name":    "JSONRPCError",
+			"code":    1,
+			"message": err.Error(),
+		},
+	}
+	utils.JSONResponse(w, response, http.StatusOK)
+}
+
+func (n *NZBGet) dispatch(r *http.Request, call *rpcCall) (any, error) {
+	username, password := getCredentials(r)
+	category := ""
+	if call.Method == "append" {
+		category = paramString(call.Params, 2)
+	}
+	a, err := n.manager.Arr().Authenticate(category, username, password)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errUnauthorized, err)
+	}
+
+	switch call.Method {
+	case "version":
+		return Version, nil
+	case "append":
+		return n.append(r.Context(), a, call.Params)
+	case "listgroups":
+		return n.listGroups(), nil
+	case "history":
+		return n.history(), nil
+	case "status":
+		return n.status(), nil
+	case "config", "loadconfig":
+		return n.config(), nil
+	case "editqueue":
+		return n.editQueue(call.Params)
+	case "rate":
+		// NZBGet takes the limit in KB/s; 0 removes it
+		n.manager.SetDownloadSpeedLimit(int64(paramInt(call.Params, 0)) * 1024)
+		return true, nil
+	default:
+		n.logger.Warn().Str("method", call.Method).Msg("Unknown NZBGet method")
+		return nil, fmt.Errorf("invalid procedure %q", call.Method)
+	}
+}
+
+// append adds an NZB. Params follow NZBGet 13+: NZBFilename, Content (base64
+// or URL), Category, Priority, AddToTop, AddPaused, DupeKey, DupeScore,
+// DupeMode, PPParameters. Returns the new NZBID.
+func (n *NZBGet) append(ctx context.Context, a *arr.Arr, params []any) (int, error) {
+	filename := paramString(params, 0)
+	content := strings.TrimSpace(paramString(params, 1))
+	if content == "" {
+		return 0, fmt.Errorf("NZB content is empty")
+	}
+
+	var data []byte
+	var err error
+	if strings.HasPrefix(content, "http://") || strings.HasPrefix(content, "https://") {
+		var name string
+		name, data, err = utils.DownloadFile(content)
+		if err != nil {
+			return 0, fmt.Errorf("failed to download NZB from URL: %w", err)
+		}
+		filename = cmp.Or(filename, name)
+	} else if data, err = base64.StdEncoding.DecodeString(content); err != nil {
+		return 0, fmt.Errorf("invalid NZB content: %w", err)
+	}
+
+	priority := toQueuePriority(paramInt(params, 3))
+	if paramBool(params, 5) {
+		// AddPaused; the job stays queued until GroupResume
+		priority = manager.PriorityPaused
+	}
+
+	cfg := config.Get()
+	policy := cfg.PolicyFor(a.Name)
+	req := manager.NewNZBRequest(filename, cmp.Or(policy.DownloadFolder, n.downloadFolder), data, a, policy, cfg.Notifications.CallbackURL, manager.ImportTypeNZBGet)
+	req.Priority = priority
+	req.PPParameters = paramPPParameters(params, 9)
+	id, err := n.manager.AddNewNZB(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	if paramBool(params, 4) {
+		// AddToTop; an NZB that already holds a slot has nothing to pass
+		_, _, _ = n.manager.MoveEntry(id, 0)
+	}
+	return nzbID(id), nil
+}
+
+// listGroups returns the queue in processing order
+func (n *NZBGet) listGroups() []QueueItem {
+	entries := n.manager.Queue().ListFilter("", config.ProtocolNZB, storage.EntryStateDownloading, nil, "added_on", false)
+	n.manager.SortByQueuePosition(entries)
+	positions := n.manager.QueuePositions()
+
+	items := make([]QueueItem, 0, len(entries))
+	for _, e := range entries {
+		_, waiting := positions[e.InfoHash]
+		items = append(items, newQueueItem(e, !waiting))
+	}
+	return items
+}
+
+// history returns completed and failed NZBs, newest first
+func (n *NZBGet) history() []HistoryItem {
+	entries := n.manager.Queue().ListFilter("", config.ProtocolNZB, storage.EntryStatePausedUP, nil, "", false)
+	entries = append(entries, n.manager.Queue().ListFilter("", config.ProtocolNZB, storage.EntryStateError, nil, "", false)...)
+
+	items := make([]HistoryItem, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, newHistoryItem(e))
+	}
+	slices.SortStableFunc(items, func(a, b HistoryItem) int {
+		return cmp.Compare(b.HistoryTime, a.HistoryTime)
+	})
+	return items
+}
+
+func (n *NZBGet) status() Status {
+	const MB = 1024 * 1024
+	var remaining, downloaded, rate int64
+	entries := n.manager.Queue().ListFilter("", config.ProtocolNZB, storage.EntryStateDownloading, nil, "", false)
+	for _, e := range entries {
+		left := int64(float64(e.Size) * (1 - e.Progress))
+		remaining += left
+		downloaded += e.Size - left
+		rate += e.Speed
+	}
+
+	uptime := int64(time.Since(n.startTime).Seconds())
+	status := Status{
+		RemainingSizeMB:     remaining / MB,
+		DownloadedSizeMB:    downloaded / MB,
+		DownloadRate:        rate,
+		AverageDownloadRate: rate,
+		DownloadLimit:       n.manager.DownloadSpeedLimit(),
+		ThreadCount:         n.manager.JobQueue().ActiveCount(),
+		UpTimeSec:           uptime,
+		DownloadTimeSec:     uptime,
+		ServerTime:          time.Now().Unix(),
+		NewsServers:         []any{},
+	}
+	status.RemainingSizeLo, status.RemainingSizeHi = splitSize(remaining)
+	status.DownloadedSizeLo, status.DownloadedSizeHi = splitSize(downloaded)
+	return status
+}
+
+// config returns the options NZBGet clients read: the destination folders
+// and one CategoryN entry per category.
+func (n *NZBGet) config() []ConfigItem {
+	cfg := config.Get()
+	items := []ConfigItem{
+		{Name: "MainDir", Value: n.downloadFolder},
+		{Name: "DestDir", Value: n.downloadFolder},
+		{Name: "InterDir", Value: n.downloadFolder},
+		{Name: "AppendCategoryDir", Value: "yes"},
+		{Name: "KeepHistory", Value: "30"},
+		{Name: "ControlPort", Value: cfg.Port},
+		{Name: "Version", Value: Version},
+	}
+	for i, category := range n.categories() {
+		prefix := fmt.Sprintf("Category%d.", i+1)
+		items = append(items,
+			ConfigItem{Name: prefix + "Name", Value: category},
+			ConfigItem{Name: prefix + "DestDir", Value: filepath.Join(cmp.Or(cfg.PolicyFor(category).DownloadFolder, n.downloadFolder), category)},
+		)
+	}
+	return items
+}
+
+func (n *NZBGet) categories() []string {
+	var categories []string
+	for _, a := range n.manager.Arr().GetAll() {
+		if a.Name != "" && !slices.Contains(categories, a.Name) {
+			categories = append(categories, a.Name)
+		}
+	}
+	for _, category := range config.Get().Categories {
+		if category != "" && !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+// editQueue runs an editqueue command on a list of NZBIDs. NZBGet 18+ sends
+// Command, Param, IDs; older versions send Command, Offset, Text, IDs.
+// Returns false if any ID is unknown or the command fails on it.
+func (n *NZBGet) editQueue(params []any) (bool, error) {
+	command := paramString(params, 0)
+	var param string
+	var ids []int
+	if len(params) >= 4 {
+		param = cmp.Or(paramString(params, 2), paramString(params, 1))
+		ids = paramIDs(params, 3)
+	} else {
+		param = paramString(params, 1)
+		ids = paramIDs(params, 2)
+	}
+
+	entries := n.entriesByID()
+	positions := n.manager.QueuePositions()
+	ok := true
+	for _, id := range ids {
+		entry, found := entries[id]
+		if !found {
+			ok = false
+			continue
+		}
+		if entry == nil {
+			n.logger.Warn().Int("nzb_id", id).Str("command", command).Msg("NZBID is shared by several NZBs, refusing to edit them")
+			ok = false
+			continue
+		}
+		if err := n.editEntry(command, param, entry, positions); err != nil {
+			if errors.Is(err, errUnsupportedCommand) {
+				return false, err
+			}
+			n.logger.Warn().Err(err).Str("command", command).Str("nzo_id", entry.InfoHash).Msg("editqueue failed")
+			ok = false
+		}
+	}
+	return ok, nil
+}
+
+var errUnsupportedCommand = errors.New("unsupported editqueue command")
+
+func (n *NZBGet) editEntry(command, param string, entry *storage.Entry, positions map[string]int) error {
+	id := entry.InfoHash
+	switch command {
+	case "GroupMoveTop":
+		_, _, err := n.manager.MoveEntry(id, 0)
+		return err
+	case "GroupMoveBottom":
+		_, _, err := n.manager.MoveEntry(id, math.MaxInt)
+		return err
+	case "GroupMoveOffset":
+		offset, err := strconv.Atoi(strings.TrimSpace(param))
+		if err != nil {
+			return fmt.Errorf("invalid offset %q", param)
+		}
+		position, waiting := positions[id]
+		if !waiting {
+			return fmt.Errorf("%s is not waiting for a slot", entry.Name)
+		}
+		_, _, err = n.manager.MoveEntry(id, max(position+offset, 0))
+		return err
+	case "GroupSetPriority":
+		priority, err := strconv.Atoi(strings.TrimSpace(param))
+		if err != nil {
+			return fmt.Errorf("invalid priority %q", param)
+		}
+		_, err = n.manager.SetEntryPriority(id, toQueuePriority(priority))
+		return err
+	case "GroupSetCategory", "GroupApplyCategory":
+		return n.manager.SetEntryCategory(id, param)
+	case "GroupSetName":
+		return n.manager.RenameEntry(id, param)
+	case "GroupPause":
+		if _, waiting := positions[id]; !waiting {
+			return fmt.Errorf("%s already holds a download slot and cannot be paused", entry.Name)
+		}
+		_, err := n.manager.SetEntryPriority(id, manager.PriorityPaused)
+		return err
+	case "GroupResume":
+		if entry.Priority > manager.PriorityPaused {
+			return nil
+		}
+		_, err := n.manager.SetEntryPriority(id, 0)
+		return err
+	case "GroupPauseAllPars", "GroupPauseExtraPars":
+		// Repair files are not downloaded separately
+		return nil
+	case "GroupDelete", "GroupDupeDelete", "GroupParkDelete", "HistoryDelete":
+		return n.manager.Queue().Delete(id, nil)
+	case "GroupFinalDelete", "HistoryFinalDelete":
+		if err := n.manager.Queue().Delete(id, nil); err != nil {
+			return err
+		}
+		if entry.State == storage.EntryStateError || !entry.IsComplete {
+			return n.manager.PurgeFailedNZB(id)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedCommand, command)
+	}
+}
+
+// entriesByID indexes queued and finished NZBs by NZBID
+func (n *NZBGet) entriesByID() map[int]*storage.Entry {
+	return indexByNZBID(n.manager.Queue().ListFilter("", config.ProtocolNZB, "", nil, "", false))
+}
+
+// indexByNZBID maps each NZBID to its entry. NZBIDs are hashes, so two
+// entries can share one; such an ID maps to nil, as it can't name either.
+func indexByNZBID(entries []*storage.Entry) map[int]*storage.Entry {
+	byID := make(map[int]*storage.Entry, len(entries))
+	for _, e := range entries {
+		id := nzbID(e.InfoHash)
+		if _, taken := byID[id]; taken {
+			byID[id] = nil
+			continue
+		}
+		byID[id] = e
+	}
+	return byID
+}
@@ -0,0 +1,29 @@
+package nzbget
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/logger"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+)
+
+// NZBGet serves an NZBGet-compatible JSON-RPC and XML-RPC API on top of the
+// same queue and history the SABnzbd API uses.
+type NZBGet struct {
+	downloadFolder string
+	logger         zerolog.Logger
+	manager        *manager.Manager
+	startTime      time.Time
+}
+
+func New(manager *manager.Manager) *NZBGet {
+	cfg := config.Get()
+	return &NZBGet{
+		downloadFolder: cfg.DownloadFolder,
+		logger:         logger.New("nzbget"),
+		manager:        manager,
+		startTime:      time.Now(),
+	}
+}
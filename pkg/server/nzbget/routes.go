@@ -0,0 +1,20 @@
+package nzbget
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (n *NZBGet) Routes() http.Handler {
+	r := chi.NewRouter()
+
+	r.Post("/jsonrpc", n.handleJSONRPC)
+	r.Post("/xmlrpc", n.handleXMLRPC)
+
+	// NZBGet also accepts credentials as a path segment: /user:pass/jsonrpc
+	r.Post("/{credentials}/jsonrpc", n.handleJSONRPC)
+	r.Post("/{credentials}/xmlrpc", n.handleXMLRPC)
+
+	return r
+}
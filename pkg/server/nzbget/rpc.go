@@ -0,0 +1,291 @@
+package nzbget
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// rpcCall is one decoded JSON-RPC or XML-RPC call. Numbers arrive as
+// json.Number or int64, base64 content as its encoded string.
+type rpcCall struct {
+	ID     any
+	Method string
+	Params []any
+}
+
+func decodeJSONRPC(r io.Reader) (*rpcCall, error) {
+	var req struct {
+		ID     any    `json:"id"`
+		Method string `json:"method"`
+		Params []any  `json:"params"`
+	}
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	if err := d.Decode(&req); err != nil {
+		return nil, fmt.Errorf("invalid JSON-RPC request: %w", err)
+	}
+	return &rpcCall{ID: req.ID, Method: req.Method, Params: req.Params}, nil
+}
+
+type xmlrpcMember struct {
+	Name  string      `xml:"name"`
+	Value xmlrpcValue `xml:"value"`
+}
+
+type xmlrpcValue struct {
+	String  *string  `xml:"string"`
+	Int     *int64   `xml:"int"`
+	I4      *int64   `xml:"i4"`
+	I8      *int64   `xml:"i8"`
+	Boolean *string  `xml:"boolean"`
+	Double  *float64 `xml:"double"`
+	Base64  *string  `xml:"base64"`
+	Array   *struct {
+		Values []xmlrpcValue `xml:"data>value"`
+	} `xml:"array"`
+	Struct *struct {
+		Members []xmlrpcMember `xml:"member"`
+	} `xml:"struct"`
+	Text string `xml:",chardata"`
+}
+
+func (v xmlrpcValue) toAny() any {
+	switch {
+	case v.String != nil:
+		return *v.String
+	case v.Int != nil:
+		return *v.Int
+	case v.I4 != nil:
+		return *v.I4
+	case v.I8 != nil:
+		return *v.I8
+	case v.Boolean != nil:
+		return strings.TrimSpace(*v.Boolean) == "1"
+	case v.Double != nil:
+		return *v.Double
+	case v.Base64 != nil:
+		return strings.TrimSpace(*v.Base64)
+	case v.Array != nil:
+		out := make([]any, 0, len(v.Array.Values))
+		for _, item := range v.Array.Values {
+			out = append(out, item.toAny())
+		}
+		return out
+	case v.Struct != nil:
+		out := make(map[string]any, len(v.Struct.Members))
+		for _, m := range v.Struct.Members {
+			out[m.Name] = m.Value.toAny()
+		}
+		return out
+	default:
+		// An untyped value is a string
+		return v.Text
+	}
+}
+
+func decodeXMLRPC(r io.Reader) (*rpcCall, error) {
+	var req struct {
+		MethodName string        `xml:"methodName"`
+		Params     []xmlrpcValue `xml:"params>param>value"`
+	}
+	if err := xml.NewDecoder(r).Decode(&req); err != nil {
+		return nil, fmt.Errorf("invalid XML-RPC request: %w", err)
+	}
+	call := &rpcCall{Method: strings.TrimSpace(req.MethodName)}
+	for _, p := range req.Params {
+		call.Params = append(call.Params, p.toAny())
+	}
+	return call, nil
+}
+
+// encodeXMLRPC writes result as an XML-RPC response. The result goes through
+// its JSON form first, so the same response types serve both protocols.
+func encodeXMLRPC(w io.Writer, result any) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var generic any
+	if err := d.Decode(&generic); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString("<methodResponse><params><param>")
+	writeXMLRPCValue(&buf, generic)
+	buf.WriteString("</param></params></methodResponse>")
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// encodeXMLRPCFault writes an XML-RPC fault response.
+func encodeXMLRPCFault(w io.Writer, code int, message string) error {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString("<methodResponse><fault>")
+	writeXMLRPCValue(&buf, map[string]any{
+		"faultCode":   json.Number(strconv.Itoa(code)),
+		"faultString": message,
+	})
+	buf.WriteString("</fault></methodResponse>")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeXMLRPCValue(buf *bytes.Buffer, v any) {
+	buf.WriteString("<value>")
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("<string></string>")
+	case bool:
+		if v {
+			buf.WriteString("<boolean>1</boolean>")
+		} else {
+			buf.WriteString("<boolean>0</boolean>")
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil && n >= math.MinInt32 && n <= math.MaxInt32 {
+			fmt.Fprintf(buf, "<i4>%d</i4>", n)
+		} else {
+			fmt.Fprintf(buf, "<double>%s</double>", v.String())
+		}
+	case string:
+		buf.WriteString("<string>")
+		_ = xml.EscapeText(buf, []byte(v))
+		buf.WriteString("</string>")
+	case []any:
+		buf.WriteString("<array><data>")
+		for _, item := range v {
+			writeXMLRPCValue(buf, item)
+		}
+		buf.WriteString("</data></array>")
+	case map[string]any:
+		buf.WriteString("<struct>")
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			buf.WriteString("<member><name>")
+			_ = xml.EscapeText(buf, []byte(key))
+			buf.WriteString("</name>")
+			writeXMLRPCValue(buf, v[key])
+			buf.WriteString("</member>")
+		}
+		buf.WriteString("</struct>")
+	default:
+		buf.WriteString("<string>")
+		_ = xml.EscapeText(buf, []byte(fmt.Sprint(v)))
+		buf.WriteString("</string>")
+	}
+	buf.WriteString("</value>")
+}
+
+// Parameter accessors. NZBGet clients are loose with types, sending numbers
+// as strings and booleans as numbers, so each accessor converts what it can.
+
+func paramString(params []any, i int) string {
+	if i >= len(params) || params[i] == nil {
+		return ""
+	}
+	switch v := params[i].(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func paramInt(params []any, i int) int {
+	if i >= len(params) {
+		return 0
+	}
+	switch v := params[i].(type) {
+	case json.Number:
+		n, _ := v.Int64()
+		return int(n)
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	case string:
+		n, _ := strconv.Atoi(strings.TrimSpace(v))
+		return n
+	default:
+		return 0
+	}
+}
+
+func paramBool(params []any, i int) bool {
+	if i >= len(params) {
+		return false
+	}
+	switch v := params[i].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(strings.TrimSpace(v))
+		return b
+	default:
+		return paramInt(params, i) != 0
+	}
+}
+
+func paramList(params []any, i int) []any {
+	if i >= len(params) {
+		return nil
+	}
+	if list, ok := params[i].([]any); ok {
+		return list
+	}
+	return nil
+}
+
+// paramIDs reads a list of NZBIDs.
+func paramIDs(params []any, i int) []int {
+	list := paramList(params, i)
+	ids := make([]int, 0, len(list))
+	for j := range list {
+		if id := paramInt(list, j); id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// paramPPParameters reads post-processing parameters. NZBGet takes them as
+// Name/Value structs; older clients send a flat list of names and values.
+func paramPPParameters(params []any, i int) map[string]string {
+	list := paramList(params, i)
+	out := make(map[string]string)
+	for j := 0; j < len(list); j++ {
+		if m, ok := list[j].(map[string]any); ok {
+			name := paramString([]any{m["Name"]}, 0)
+			if name != "" {
+				out[name] = paramString([]any{m["Value"]}, 0)
+			}
+			continue
+		}
+		if j+1 < len(list) {
+			if name := paramString(list, j); name != "" {
+				out[name] = paramString(list, j+1)
+			}
+			j++
+		}
+	}
+	return out
+}
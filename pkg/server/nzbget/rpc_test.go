@@ -0,0 +1,127 @@
+package nzbget
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+func TestDecodeXMLRPCAppend(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<methodCall>
+  <methodName>append</methodName>
+  <params>
+    <param><value><string>show.nzb</string></value></param>
+    <param><value><string>PG56Yj4=</string></value></param>
+    <param><value>sonarr</value></param>
+    <param><value><i4>50</i4></value></param>
+    <param><value><boolean>1</boolean></value></param>
+    <param><value><boolean>0</boolean></value></param>
+    <param><value><string></string></value></param>
+    <param><value><int>0</int></value></param>
+    <param><value><string>SCORE</string></value></param>
+    <param><value><array><data>
+      <value><struct>
+        <member><name>Name</name><value><string>*unpack:</string></value></member>
+        <member><name>Value</name><value><string>yes</string></value></member>
+      </struct></value>
+    </data></array></value></param>
+  </params>
+</methodCall>`
+	call, err := decodeXMLRPC(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if call.Method != "append" || len(call.Params) != 10 {
+		t.Fatalf("unexpected call %q with %d params", call.Method, len(call.Params))
+	}
+	if got := paramString(call.Params, 2); got != "sonarr" {
+		t.Errorf("untyped value should decode as a string, got %q", got)
+	}
+	if got := paramInt(call.Params, 3); got != 50 {
+		t.Errorf("expected priority 50, got %d", got)
+	}
+	if !paramBool(call.Params, 4) || paramBool(call.Params, 5) {
+		t.Errorf("booleans decoded wrong: %v %v", call.Params[4], call.Params[5])
+	}
+	if got := paramPPParameters(call.Params, 9); got["*unpack:"] != "yes" {
+		t.Errorf("expected post-processing parameter, got %v", got)
+	}
+}
+
+func TestDecodeJSONRPCEditQueue(t *testing.T) {
+	body := `{"method":"editqueue","id":7,"params":["GroupMoveOffset","-2",[12,"34",0]]}`
+	call, err := decodeJSONRPC(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if got := paramString(call.Params, 1); got != "-2" {
+		t.Errorf("expected offset -2, got %q", got)
+	}
+	ids := paramIDs(call.Params, 2)
+	if len(ids) != 2 || ids[0] != 12 || ids[1] != 34 {
+		t.Errorf("expected ids [12 34], got %v", ids)
+	}
+}
+
+func TestEncodeXMLRPC(t *testing.T) {
+	var buf bytes.Buffer
+	result := []ConfigItem{{Name: "DestDir", Value: "/downloads & more"}}
+	if err := encodeXMLRPC(&buf, result); err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	want := "<array><data><value><struct>" +
+		"<member><name>Name</name><value><string>DestDir</string></value></member>" +
+		"<member><name>Value</name><value><string>/downloads &amp; more</string></value></member>" +
+		"</struct></value></data></array>"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("unexpected response: %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := encodeXMLRPC(&buf, Status{DownloadedSizeMB: 1 << 40}); err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "<double>1099511627776</double>") {
+		t.Errorf("values outside int32 should be encoded as double: %s", buf.String())
+	}
+
+	// editqueue and the other commands answer with a bare boolean
+	buf.Reset()
+	if err := encodeXMLRPC(&buf, true); err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "<boolean>1</boolean>") {
+		t.Errorf("unexpected boolean encoding: %s", buf.String())
+	}
+}
+
+func TestNZBID(t *testing.T) {
+	id := nzbID("ABCDEF")
+	if id <= 0 {
+		t.Fatalf("nzbID must be positive, got %d", id)
+	}
+	if id != nzbID("abcdef") {
+		t.Errorf("nzbID should not depend on case")
+	}
+}
+
+func TestIndexByNZBIDCollision(t *testing.T) {
+	// These two IDs hash to the same NZBID
+	a := &storage.Entry{InfoHash: "00000000000000000000000000000000000299bf"}
+	b := &storage.Entry{InfoHash: "0000000000000000000000000000000000044dd8"}
+	c := &storage.Entry{InfoHash: "abcdef"}
+	if nzbID(a.InfoHash) != nzbID(b.InfoHash) {
+		t.Fatalf("test IDs no longer collide")
+	}
+
+	byID := indexByNZBID([]*storage.Entry{a, b, c})
+	if entry, found := byID[nzbID(a.InfoHash)]; !found || entry != nil {
+		t.Errorf("a shared NZBID should map to nil, got %v", entry)
+	}
+	if byID[nzbID(c.InfoHash)] != c {
+		t.Errorf("a unique NZBID should map to its entry")
+	}
+}
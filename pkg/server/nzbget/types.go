@@ -0,0 +1,299 @@
+package nzbget
+
+import (
+	"hash/fnv"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirrobot01/decypharr/pkg/manager"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// NZBGet API response types, following the NZBGet 21 JSON-RPC documentation
+
+var (
+	Version = "21.1"
+)
+
+// NZBGet priorities
+const (
+	PriorityVeryLow  = -100
+	PriorityLow      = -50
+	PriorityNormal   = 0
+	PriorityHigh     = 50
+	PriorityVeryHigh = 100
+	PriorityForce    = 900
+)
+
+// Parameter is a post-processing parameter
+type Parameter struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+// QueueItem is a group returned by listgroups
+type QueueItem struct {
+	NZBID              int         `json:"NZBID"`
+	FirstID            int         `json:"FirstID"`
+	LastID             int         `json:"LastID"`
+	NZBName            string      `json:"NZBName"`
+	NZBNicename        string      `json:"NZBNicename"`
+	NZBFilename        string      `json:"NZBFilename"`
+	Kind               string      `json:"Kind"`
+	URL                string      `json:"URL"`
+	DestDir            string      `json:"DestDir"`
+	FinalDir           string      `json:"FinalDir"`
+	Category           string      `json:"Category"`
+	FileSizeLo         uint32      `json:"FileSizeLo"`
+	FileSizeHi         uint32      `json:"FileSizeHi"`
+	FileSizeMB         int64       `json:"FileSizeMB"`
+	RemainingSizeLo    uint32      `json:"RemainingSizeLo"`
+	RemainingSizeHi    uint32      `json:"RemainingSizeHi"`
+	RemainingSizeMB    int64       `json:"RemainingSizeMB"`
+	PausedSizeLo       uint32      `json:"PausedSizeLo"`
+	PausedSizeHi       uint32      `json:"PausedSizeHi"`
+	PausedSizeMB       int64       `json:"PausedSizeMB"`
+	DownloadedSizeLo   uint32      `json:"DownloadedSizeLo"`
+	DownloadedSizeHi   uint32      `json:"DownloadedSizeHi"`
+	DownloadedSizeMB   int64       `json:"DownloadedSizeMB"`
+	FileCount          int         `json:"FileCount"`
+	RemainingFileCount int         `json:"RemainingFileCount"`
+	MaxPriority        int         `json:"MaxPriority"`
+	ActiveDownloads    int         `json:"ActiveDownloads"`
+	Status             string      `json:"Status"`
+	Health             int         `json:"Health"`
+	CriticalHealth     int         `json:"CriticalHealth"`
+	DupeKey            string      `json:"DupeKey"`
+	DupeScore          int         `json:"DupeScore"`
+	DupeMode           string      `json:"DupeMode"`
+	DownloadTimeSec    int64       `json:"DownloadTimeSec"`
+	Parameters         []Parameter `json:"Parameters"`
+}
+
+// HistoryItem is an entry returned by history
+type HistoryItem struct {
+	ID              int         `json:"ID"`
+	NZBID           int         `json:"NZBID"`
+	Kind            string      `json:"Kind"`
+	Name            string      `json:"Name"`
+	NZBName         string      `json:"NZBName"`
+	NZBFilename     string      `json:"NZBFilename"`
+	Category        string      `json:"Category"`
+	DestDir         string      `json:"DestDir"`
+	FinalDir        string      `json:"FinalDir"`
+	FileSizeLo      uint32      `json:"FileSizeLo"`
+	FileSizeHi      uint32      `json:"FileSizeHi"`
+	FileSizeMB      int64       `json:"FileSizeMB"`
+	FileCount       int         `json:"FileCount"`
+	Status          string      `json:"Status"`
+	ParStatus       string      `json:"ParStatus"`
+	UnpackStatus    string      `json:"UnpackStatus"`
+	MoveStatus      string      `json:"MoveStatus"`
+	ScriptStatus    string      `json:"ScriptStatus"`
+	DeleteStatus    string      `json:"DeleteStatus"`
+	MarkStatus      string      `json:"MarkStatus"`
+	URLStatus       string      `json:"UrlStatus"`
+	Health          int         `json:"Health"`
+	CriticalHealth  int         `json:"CriticalHealth"`
+	HistoryTime     int64       `json:"HistoryTime"`
+	DownloadTimeSec int64       `json:"DownloadTimeSec"`
+	DupeKey         string      `json:"DupeKey"`
+	DupeScore       int         `json:"DupeScore"`
+	DupeMode        string      `json:"DupeMode"`
+	Parameters      []Parameter `json:"Parameters"`
+}
+
+// Status is the response of status
+type Status struct {
+	RemainingSizeLo     uint32 `json:"RemainingSizeLo"`
+	RemainingSizeHi     uint32 `json:"RemainingSizeHi"`
+	RemainingSizeMB     int64  `json:"RemainingSizeMB"`
+	DownloadedSizeLo    uint32 `json:"DownloadedSizeLo"`
+	DownloadedSizeHi    uint32 `json:"DownloadedSizeHi"`
+	DownloadedSizeMB    int64  `json:"DownloadedSizeMB"`
+	DownloadRate        int64  `json:"DownloadRate"`
+	AverageDownloadRate int64  `json:"AverageDownloadRate"`
+	DownloadLimit       int64  `json:"DownloadLimit"`
+	ThreadCount         int    `json:"ThreadCount"`
+	PostJobCount        int    `json:"PostJobCount"`
+	UpTimeSec           int64  `json:"UpTimeSec"`
+	DownloadTimeSec     int64  `json:"DownloadTimeSec"`
+	ServerPaused        bool   `json:"ServerPaused"`
+	DownloadPaused      bool   `json:"DownloadPaused"`
+	Download2Paused     bool   `json:"Download2Paused"`
+	ServerStandBy       bool   `json:"ServerStandBy"`
+	PostPaused          bool   `json:"PostPaused"`
+	ScanPaused          bool   `json:"ScanPaused"`
+	QuotaReached        bool   `json:"QuotaReached"`
+	FreeDiskSpaceLo     uint32 `json:"FreeDiskSpaceLo"`
+	FreeDiskSpaceHi     uint32 `json:"FreeDiskSpaceHi"`
+	FreeDiskSpaceMB     int64  `json:"FreeDiskSpaceMB"`
+	ServerTime          int64  `json:"ServerTime"`
+	ResumeTime          int64  `json:"ResumeTime"`
+	FeedActive          bool   `json:"FeedActive"`
+	NewsServers         []any  `json:"NewsServers"`
+}
+
+// ConfigItem is an option returned by config
+type ConfigItem struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+// nzbID maps an entry's ID onto the positive integer NZBGet clients expect.
+// It is derived from the ID, so it stays the same across restarts. Distinct
+// IDs can collide; see indexByNZBID.
+func nzbID(infohash string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.ToLower(infohash)))
+	id := int(h.Sum32() & 0x7fffffff)
+	if id == 0 {
+		id = 1
+	}
+	return id
+}
+
+// toQueuePriority maps an NZBGet priority onto the queue's scale
+func toQueuePriority(priority int) int {
+	switch {
+	case priority >= PriorityForce:
+		return 2
+	case priority >= PriorityHigh:
+		return 1
+	case priority <= PriorityLow:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// fromQueuePriority maps a queue priority onto NZBGet's scale
+func fromQueuePriority(priority int) int {
+	switch {
+	case priority >= 2:
+		return PriorityForce
+	case priority == 1:
+		return PriorityHigh
+	case priority == -1:
+		return PriorityLow
+	case priority <= -2:
+		return PriorityVeryLow
+	default:
+		return PriorityNormal
+	}
+}
+
+// splitSize splits a size into the 32-bit halves NZBGet reports
+func splitSize(size int64) (lo, hi uint32) {
+	return uint32(size & 0xffffffff), uint32(size >> 32)
+}
+
+func parameters(e *storage.Entry) []Parameter {
+	params := make([]Parameter, 0, len(e.PPParameters))
+	for _, name := range slices.Sorted(maps.Keys(e.PPParameters)) {
+		params = append(params, Parameter{Name: name, Value: e.PPParameters[name]})
+	}
+	return params
+}
+
+func activeFileCount(e *storage.Entry) int {
+	count := 0
+	for _, f := range e.Files {
+		if f.Active() {
+			count++
+		}
+	}
+	return count
+}
+
+func newQueueItem(e *storage.Entry, active bool) QueueItem {
+	const MB = 1024 * 1024
+	remaining := int64(float64(e.Size) * (1 - e.Progress))
+	downloaded := e.Size - remaining
+	id := nzbID(e.InfoHash)
+
+	status := "QUEUED"
+	activeDownloads := 0
+	switch {
+	case active:
+		status = "DOWNLOADING"
+		activeDownloads = 1
+	case e.Priority <= manager.PriorityPaused:
+		status = "PAUSED"
+	}
+
+	item := QueueItem{
+		NZBID:              id,
+		FirstID:            id,
+		LastID:             id,
+		NZBName:            e.Name,
+		NZBNicename:        e.Name,
+		NZBFilename:        e.OriginalFilename,
+		Kind:               "NZB",
+		DestDir:            e.DownloadPath(),
+		Category:           e.Category,
+		FileSizeMB:         e.Size / MB,
+		RemainingSizeMB:    remaining / MB,
+		DownloadedSizeMB:   downloaded / MB,
+		FileCount:          activeFileCount(e),
+		RemainingFileCount: activeFileCount(e),
+		MaxPriority:        fromQueuePriority(e.Priority),
+		ActiveDownloads:    activeDownloads,
+		Status:             status,
+		Health:             1000,
+		CriticalHealth:     1000,
+		DupeMode:           "SCORE",
+		DownloadTimeSec:    int64(time.Since(e.CreatedAt).Seconds()),
+		Parameters:         parameters(e),
+	}
+	item.FileSizeLo, item.FileSizeHi = splitSize(e.Size)
+	item.RemainingSizeLo, item.RemainingSizeHi = splitSize(remaining)
+	item.DownloadedSizeLo, item.DownloadedSizeHi = splitSize(downloaded)
+	return item
+}
+
+func newHistoryItem(e *storage.Entry) HistoryItem {
+	const MB = 1024 * 1024
+	id := nzbID(e.InfoHash)
+	item := HistoryItem{
+		ID:             id,
+		NZBID:          id,
+		Kind:           "NZB",
+		Name:           e.Name,
+		NZBName:        e.Name,
+		NZBFilename:    e.OriginalFilename,
+		Category:       e.Category,
+		DestDir:        e.DownloadPath(),
+		FinalDir:       e.DownloadPath(),
+		FileSizeMB:     e.Size / MB,
+		FileCount:      activeFileCount(e),
+		Status:         "SUCCESS/ALL",
+		ParStatus:      "SUCCESS",
+		UnpackStatus:   "SUCCESS",
+		MoveStatus:     "SUCCESS",
+		ScriptStatus:   "NONE",
+		DeleteStatus:   "NONE",
+		MarkStatus:     "NONE",
+		URLStatus:      "NONE",
+		Health:         1000,
+		CriticalHealth: 1000,
+		HistoryTime:    e.UpdatedAt.Unix(),
+		DupeMode:       "SCORE",
+		Parameters:     parameters(e),
+	}
+	if e.CompletedAt != nil {
+		item.HistoryTime = e.CompletedAt.Unix()
+		item.DownloadTimeSec = int64(e.CompletedAt.Sub(e.CreatedAt).Seconds())
+	}
+	if e.State == storage.EntryStateError {
+		item.Status = "FAILURE/PAR"
+		item.ParStatus = "FAILURE"
+		item.UnpackStatus = "NONE"
+		item.MoveStatus = "NONE"
+		item.Health = 0
+	}
+	item.FileSizeLo, item.FileSizeHi = splitSize(e.Size)
+	return item
+}
@@ -111,7 +111,7 @@ func (q *QBit) authContext(next http.Handler) http.Handler {
 			return
 		}
 		category := getCategory(r.Context())
-		a, err := q.authenticate(category, username, password)
+		a, err := q.manager.Arr().Authenticate(category, username, password)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusUnauthorized)
 			return
@@ -142,48 +142,6 @@ func getUsernameAndPassword(r *http.Request) (string, string, error) {
 	return username, password, nil
 }
 
-func (q *QBit) authenticate(category, username, password string) (*arr.Arr, error) {
-	cfg := config.Get()
-	a := q.manager.Arr().Get(category)
-	if a == nil {
-		// Arr is not yet in runtime storage — look for a matching config entry
-		// so we inherit its download_uncached setting. If no config match,
-		// leave nil so SendToDebrid falls back to the debrid provider's setting.
-		var downloadUncached *bool
-		for _, cfgArr := range config.Get().Arrs {
-			if cfgArr.Name == category {
-				downloadUncached = cfgArr.DownloadUncached
-				break
-			}
-		}
-		a = arr.New(category, username, password, false, downloadUncached, "", "auto")
-	}
-	arrValidated := false // This is a flag to indicate if arr validation was successful
-	if (username == "" || password == "") && cfg.UseAuth {
-		return nil, fmt.Errorf("unauthorized: Host and token are required for authentication(you've enabled authentication)")
-	}
-	if a.Source == "auto" {
-		a.Host = username
-		a.Token = password
-	}
-	if err := a.Validate(); err == nil {
-		arrValidated = true
-	}
-
-	if !arrValidated && cfg.UseAuth {
-		// If arr validation failed, try to use user auth validation
-		if !config.VerifyAuth(username, password) {
-			return nil, fmt.Errorf("unauthorized: invalid credentials")
-		}
-	}
-
-	if username != "" && password != "" {
-		// Then add or update arr in manager
-		q.manager.Arr().AddOrUpdate(a)
-	}
-	return a, nil
-}
-
 func createSID(username, password string) string {
 	// Create a verification hash
 	cfg := config.Get()
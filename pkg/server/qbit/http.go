@@ -1,8 +1,15 @@
 package qbit
 
 import (
+	"encoding/json"
+	"maps"
+	"net"
 	"net/http"
+	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/sirrobot01/decypharr/internal/config"
@@ -16,7 +23,7 @@ func (q *QBit) handleLogin(w http.ResponseWriter, r *http.Request) {
 	cfg := config.Get()
 	username := r.FormValue("username")
 	password := r.FormValue("password")
-	a, err := q.authenticate(getCategory(ctx), username, password)
+	a, err := q.manager.Arr().Authenticate(getCategory(ctx), username, password)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusUnauthorized)
 		return
@@ -42,12 +49,52 @@ func (q *QBit) handleWebAPIVersion(w http.ResponseWriter, r *http.Request) {
 }
 
 func (q *QBit) handlePreferences(w http.ResponseWriter, r *http.Request) {
+	a := getArrFromContext(r.Context())
 	preferences := getAppPreferences()
 
-	preferences.SavePath = q.downloadFolder
-	preferences.TempPath = filepath.Join(q.downloadFolder, "temp")
+	downloadFolder := q.getDownloadFolder(a)
+	preferences.SavePath = downloadFolder
+	preferences.TempPath = filepath.Join(downloadFolder, "temp")
 
-	utils.JSONResponse(w, preferences, http.StatusOK)
+	fields := toFields(preferences)
+	maps.Copy(fields, q.getPreferences(a))
+	utils.JSONResponse(w, fields, http.StatusOK)
+}
+
+// handleSetPreferences keeps what an arr sets until restart, so it reads its
+// own values back; another arr doesn't see them. Only save_path changes
+// behaviour: the arr's new torrents land there. It must be an existing
+// absolute directory.
+func (q *QBit) handleSetPreferences(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form data", http.StatusBadRequest)
+		return
+	}
+	var preferences map[string]any
+	if err := json.Unmarshal([]byte(r.FormValue("json")), &preferences); err != nil {
+		http.Error(w, "Invalid preferences", http.StatusBadRequest)
+		return
+	}
+	if value, ok := preferences["save_path"]; ok {
+		savePath, _ := value.(string)
+		if !filepath.IsAbs(savePath) {
+			http.Error(w, "save_path must be an absolute path", http.StatusBadRequest)
+			return
+		}
+		if info, err := os.Stat(savePath); err != nil || !info.IsDir() {
+			http.Error(w, "save_path must be an existing directory", http.StatusBadRequest)
+			return
+		}
+		preferences["save_path"] = filepath.Clean(savePath)
+	}
+	key := arrKeyOf(getArrFromContext(r.Context()))
+	q.preferencesMu.Lock()
+	if q.preferences[key] == nil {
+		q.preferences[key] = make(map[string]any)
+	}
+	maps.Copy(q.preferences[key], preferences)
+	q.preferencesMu.Unlock()
+	w.WriteHeader(http.StatusOK)
 }
 
 func (q *QBit) handleBuildInfo(w http.ResponseWriter, r *http.Request) {
@@ -105,10 +152,6 @@ func (q *QBit) handleTorrentsAdd(w http.ResponseWriter, r *http.Request) {
 	}
 
 	cfg := config.Get()
-	action := cfg.DefaultDownloadAction
-	if strings.ToLower(r.FormValue("sequentialDownload")) == "true" {
-		action = config.DownloadActionDownload
-	}
 
 	rmTrackerUrls := strings.ToLower(r.FormValue("firstLastPiecePrio")) == "true"
 
@@ -124,6 +167,12 @@ func (q *QBit) handleTorrentsAdd(w http.ResponseWriter, r *http.Request) {
 		// Arr is not in context
 		_arr = arr.New(category, "", "", false, nil, "", "")
 	}
+	policy := cfg.PolicyFor(_arr.Name)
+	if strings.ToLower(r.FormValue("sequentialDownload")) == "true" {
+		// Older setups ask for a local download this way; category profiles
+		// are the way to set it now
+		policy.DownloadAction = config.DownloadActionDownload
+	}
 	atleastOne := false
 
 	// Handle magnet URLs
@@ -133,7 +182,7 @@ func (q *QBit) handleTorrentsAdd(w http.ResponseWriter, r *http.Request) {
 			urlList = append(urlList, strings.TrimSpace(u))
 		}
 		for _, url := range urlList {
-			if err := q.addMagnet(ctx, url, _arr, debridName, action, cfg.Notifications.CallbackURL, rmTrackerUrls, cfg.SkipMultiSeason); err != nil {
+			if err := q.addMagnet(ctx, url, _arr, debridName, policy, cfg.Notifications.CallbackURL, rmTrackerUrls); err != nil {
 				q.logger.Debug().Msgf("Error adding magnet: %s", err.Error())
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
@@ -146,7 +195,7 @@ func (q *QBit) handleTorrentsAdd(w http.ResponseWriter, r *http.Request) {
 	if r.MultipartForm != nil && r.MultipartForm.File != nil {
 		if files := r.MultipartForm.File["torrents"]; len(files) > 0 {
 			for _, fileHeader := range files {
-				if err := q.addTorrent(ctx, fileHeader, _arr, debridName, action, cfg.Notifications.CallbackURL, rmTrackerUrls, cfg.SkipMultiSeason); err != nil {
+				if err := q.addTorrent(ctx, fileHeader, _arr, debridName, policy, cfg.Notifications.CallbackURL, rmTrackerUrls); err != nil {
 					q.logger.Debug().Err(err).Str("torrent", fileHeader.Filename).Msgf("Error adding torrent")
 					http.Error(w, err.Error(), http.StatusBadRequest)
 					return
@@ -226,15 +275,20 @@ func (q *QBit) handleTorrentRecheck(w http.ResponseWriter, r *http.Request) {
 }
 
 func (q *QBit) handleCategories(w http.ResponseWriter, r *http.Request) {
+	utils.JSONResponse(w, q.categoryMap(getArrFromContext(r.Context())), http.StatusOK)
+}
+
+// categoryMap lists the categories with where the arr's torrents of each are
+// saved.
+func (q *QBit) categoryMap(a *arr.Arr) map[string]TorrentCategory {
 	var categories = map[string]TorrentCategory{}
 	for _, cat := range q.categories {
-		path := filepath.Join(q.downloadFolder, cat)
 		categories[cat] = TorrentCategory{
 			Name:     cat,
-			SavePath: path,
+			SavePath: filepath.Join(q.saveFolder(a, config.Get().PolicyFor(cat)), cat),
 		}
 	}
-	utils.JSONResponse(w, categories, http.StatusOK)
+	return categories
 }
 
 func (q *QBit) handleCreateCategory(w http.ResponseWriter, r *http.Request) {
@@ -277,6 +331,192 @@ func (q *QBit) handleTorrentFiles(w http.ResponseWriter, r *http.Request) {
 	utils.JSONResponse(w, getTorrentFiles(torrent), http.StatusOK)
 }
 
+// handleFilePrio maps qBit file priorities onto file selection: priority 0
+// hides a file, any other priority shows it again.
+func (q *QBit) handleFilePrio(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form data", http.StatusBadRequest)
+		return
+	}
+	hash := r.FormValue("hash")
+	priority, err := strconv.Atoi(r.FormValue("priority"))
+	if err != nil || !slices.Contains([]int{0, 1, 6, 7}, priority) {
+		http.Error(w, "Invalid priority", http.StatusBadRequest)
+		return
+	}
+	torrent, err := q.manager.Queue().GetTorrent(hash)
+	if err != nil {
+		http.Error(w, "Entry not found", http.StatusNotFound)
+		return
+	}
+
+	files := getTorrentFiles(torrent)
+	names := make([]string, 0)
+	for id := range strings.SplitSeq(r.FormValue("id"), "|") {
+		index, err := strconv.Atoi(strings.TrimSpace(id))
+		if err != nil || index < 0 || index >= len(files) {
+			http.Error(w, "Invalid file id "+id, http.StatusConflict)
+			return
+		}
+		names = append(names, files[index].Name)
+	}
+
+	if err := q.manager.SetFilesSelected(torrent.InfoHash, names, priority > 0); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (q *QBit) handleTorrentRename(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form data", http.StatusBadRequest)
+		return
+	}
+	hash := r.FormValue("hash")
+	if _, err := q.manager.Queue().GetTorrent(hash); err != nil {
+		http.Error(w, "Entry not found", http.StatusNotFound)
+		return
+	}
+	if err := q.manager.RenameEntry(hash, r.FormValue("name")); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleTorrentRenameFile takes the oldPath/newPath form of qBit 4.4+ as well
+// as the older id/name form. Files sit flat in the entry folder, so directory
+// parts are dropped.
+func (q *QBit) handleTorrentRenameFile(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form data", http.StatusBadRequest)
+		return
+	}
+	torrent, err := q.manager.Queue().GetTorrent(r.FormValue("hash"))
+	if err != nil {
+		http.Error(w, "Entry not found", http.StatusNotFound)
+		return
+	}
+
+	oldPath, newPath := r.FormValue("oldPath"), r.FormValue("newPath")
+	if id := r.FormValue("id"); oldPath == "" && id != "" {
+		files := getTorrentFiles(torrent)
+		index, err := strconv.Atoi(id)
+		if err != nil || index < 0 || index >= len(files) {
+			http.Error(w, "Invalid file id "+id, http.StatusConflict)
+			return
+		}
+		oldPath, newPath = files[index].Name, r.FormValue("name")
+	}
+	if oldPath == "" || newPath == "" {
+		http.Error(w, "Missing file path", http.StatusBadRequest)
+		return
+	}
+
+	if err := q.manager.RenameEntryFile(torrent.InfoHash, path.Base(oldPath), path.Base(newPath)); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (q *QBit) handleSetLocation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	location := strings.TrimSpace(r.FormValue("location"))
+	if location == "" {
+		http.Error(w, "Save path is empty", http.StatusBadRequest)
+		return
+	}
+	for _, hash := range getHashes(ctx) {
+		if err := q.manager.SetEntryLocation(hash, location); err != nil {
+			q.logger.Warn().Err(err).Str("hash", hash).Msg("Failed to set location")
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleQueuePrio returns a handler moving torrents among those waiting for
+// an active-download slot; target maps a torrent's position to its new one.
+// Torrents that already hold a slot are left alone.
+func (q *QBit) handleQueuePrio(target func(position int) int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, hash := range getHashes(r.Context()) {
+			hash = strings.ToLower(hash)
+			position, ok := q.manager.QueuePositions()[hash]
+			if !ok {
+				continue
+			}
+			if _, _, err := q.manager.MoveEntry(hash, target(position)); err != nil {
+				q.logger.Warn().Err(err).Str("hash", hash).Msg("Failed to move torrent in queue")
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// handleSyncMainData serves sync/maindata. A client passing back the rid of
+// its last response gets only the torrents, categories, tags and server state
+// that changed since; rid 0 or an expired rid gets everything.
+func (q *QBit) handleSyncMainData(w http.ResponseWriter, r *http.Request) {
+	rid, _ := strconv.ParseInt(r.FormValue("rid"), 10, 64)
+	torrents := q.manager.Queue().ListFilter("", config.ProtocolTorrent, "", nil, "added_on", false)
+
+	snapshot := &syncSnapshot{
+		torrents:    make(map[string]map[string]any, len(torrents)),
+		categories:  q.categoryMap(getArrFromContext(r.Context())),
+		tags:        slices.Clone(q.Tags),
+		serverState: q.serverState(torrents),
+	}
+	for _, t := range torrents {
+		fields := toFields(convertToQBitTorrentTorrent(t))
+		delete(fields, "files")
+		snapshot.torrents[t.InfoHash] = fields
+	}
+	utils.JSONResponse(w, q.sync.next(syncClientKey(r), rid, snapshot), http.StatusOK)
+}
+
+// syncClientKey tells apart the clients polling sync/maindata by the arr they
+// authenticated as and the address they poll from.
+func syncClientKey(r *http.Request) string {
+	key := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		key = host
+	}
+	if a := getArrFromContext(r.Context()); a != nil {
+		key = a.Host + "@" + key
+	}
+	return key
+}
+
+func (q *QBit) handleTransferInfo(w http.ResponseWriter, r *http.Request) {
+	torrents := q.manager.Queue().ListFilter("", config.ProtocolTorrent, "", nil, "", false)
+	utils.JSONResponse(w, transferInfo(torrents), http.StatusOK)
+}
+
+func (q *QBit) serverState(torrents []*storage.Entry) map[string]any {
+	state := toFields(transferInfo(torrents))
+	state["queueing"] = false
+	state["use_alt_speed_limits"] = false
+	state["refresh_interval"] = 1500
+	return state
+}
+
+// transferInfo sums the debrid-side progress of queued torrents. Nothing is
+// ever uploaded.
+func transferInfo(torrents []*storage.Entry) TransferInfo {
+	info := TransferInfo{ConnectionStatus: "connected"}
+	for _, t := range torrents {
+		if !t.IsComplete {
+			info.DlInfoSpeed += t.Speed
+		}
+		info.DlInfoData += int64(float64(t.Size) * t.Progress)
+	}
+	return info
+}
+
 func (q *QBit) handleSetCategory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	category := getCategory(ctx)
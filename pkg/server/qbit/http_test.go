@@ -0,0 +1,63 @@
+package qbit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/pkg/arr"
+)
+
+func TestSetPreferencesSavePath(t *testing.T) {
+	q := &QBit{downloadFolder: "/downloads", preferences: make(map[string]map[string]any)}
+	sonarr := &arr.Arr{Name: "sonarr", Host: "http://sonarr:8989"}
+	radarr := &arr.Arr{Name: "radarr", Host: "http://radarr:7878"}
+
+	set := func(a *arr.Arr, savePath string) int {
+		form := url.Values{"json": {`{"save_path":"` + savePath + `"}`}}
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/app/setPreferences", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(context.WithValue(req.Context(), arrKey, a))
+		rec := httptest.NewRecorder()
+		q.handleSetPreferences(rec, req)
+		return rec.Code
+	}
+
+	dir := t.TempDir()
+	// Requests read the folder while a client changes it
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			if code := set(sonarr, dir+"/"); code != http.StatusOK {
+				t.Errorf("setPreferences returned %d", code)
+			}
+			_ = q.saveFolder(sonarr, config.Policy{})
+		})
+	}
+	wg.Wait()
+
+	if got := q.saveFolder(sonarr, config.Policy{}); got != filepath.Clean(dir) {
+		t.Errorf("saveFolder = %q, want %s", got, dir)
+	}
+	if got := q.saveFolder(sonarr, config.Policy{DownloadFolder: "/profile"}); got != "/profile" {
+		t.Errorf("profile folder should win, got %q", got)
+	}
+	if got := q.saveFolder(radarr, config.Policy{}); got != "/downloads" {
+		t.Errorf("another arr's folder = %q, want the configured /downloads", got)
+	}
+
+	for _, bad := range []string{"relative/path", filepath.Join(dir, "missing"), ""} {
+		if code := set(radarr, bad); code != http.StatusBadRequest {
+			t.Errorf("save_path %q returned %d, want %d", bad, code, http.StatusBadRequest)
+		}
+	}
+	if got := q.saveFolder(radarr, config.Policy{}); got != "/downloads" {
+		t.Errorf("rejected save_path changed the folder to %q", got)
+	}
+}
@@ -1,9 +1,14 @@
 package qbit
 
 import (
+	"cmp"
+	"maps"
+	"sync"
+
 	"github.com/rs/zerolog"
 	"github.com/sirrobot01/decypharr/internal/config"
 	"github.com/sirrobot01/decypharr/internal/logger"
+	"github.com/sirrobot01/decypharr/pkg/arr"
 	"github.com/sirrobot01/decypharr/pkg/manager"
 )
 
@@ -14,6 +19,35 @@ type QBit struct {
 	logger                  zerolog.Logger
 	Tags                    []string
 	manager                 *manager.Manager
+	sync                    *syncState
+
+	// preferencesMu guards preferences, which app/setPreferences changes
+	// while requests read it.
+	preferencesMu sync.Mutex
+	preferences   map[string]map[string]any // Overrides set through app/setPreferences by arr host; not persisted
+}
+
+// getPreferences returns a copy of the overrides the arr has set.
+func (q *QBit) getPreferences(a *arr.Arr) map[string]any {
+	q.preferencesMu.Lock()
+	defer q.preferencesMu.Unlock()
+	return maps.Clone(q.preferences[arrKeyOf(a)])
+}
+
+// getDownloadFolder is the download folder the arr set through preferences,
+// or the configured one.
+func (q *QBit) getDownloadFolder(a *arr.Arr) string {
+	q.preferencesMu.Lock()
+	defer q.preferencesMu.Unlock()
+	savePath, _ := q.preferences[arrKeyOf(a)]["save_path"].(string)
+	return cmp.Or(savePath, q.downloadFolder)
+}
+
+func arrKeyOf(a *arr.Arr) string {
+	if a == nil {
+		return ""
+	}
+	return a.Host
 }
 
 func New(manager *manager.Manager) *QBit {
@@ -23,6 +57,8 @@ func New(manager *manager.Manager) *QBit {
 		categories:              cfg.Categories,
 		alwaysRemoveTrackerURLS: cfg.AlwaysRmTrackerUrls,
 		manager:                 manager,
+		sync:                    newSyncState(),
+		preferences:             make(map[string]map[string]any),
 		logger:                  logger.New("qbit"),
 	}
 }
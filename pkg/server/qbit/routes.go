@@ -1,6 +1,7 @@
 package qbit
 
 import (
+	"math"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -44,13 +45,35 @@ func (q *QBit) Routes() http.Handler {
 			r.Post("/recheck", q.handleTorrentRecheck)
 			r.Post("/properties", q.handleTorrentProperties)
 			r.Post("/files", q.handleTorrentFiles)
+			r.Post("/filePrio", q.handleFilePrio)
+			r.Post("/rename", q.handleTorrentRename)
+			r.Post("/renameFile", q.handleTorrentRenameFile)
+			r.Post("/setLocation", q.handleSetLocation)
 
+			// Reorder torrents waiting for an active-download slot
+			r.Post("/topPrio", q.handleQueuePrio(func(int) int { return 0 }))
+			r.Post("/bottomPrio", q.handleQueuePrio(func(int) int { return math.MaxInt }))
+			r.Post("/increasePrio", q.handleQueuePrio(func(p int) int { return p - 1 }))
+			r.Post("/decreasePrio", q.handleQueuePrio(func(p int) int { return p + 1 }))
+		})
+
+		r.Route("/sync", func(r chi.Router) {
+			r.Use(q.authContext)
+			r.Get("/maindata", q.handleSyncMainData)
+			r.Post("/maindata", q.handleSyncMainData)
+		})
+
+		r.Route("/transfer", func(r chi.Router) {
+			r.Use(q.authContext)
+			r.Get("/info", q.handleTransferInfo)
+			r.Post("/info", q.handleTransferInfo)
 		})
 
 		r.Route("/app", func(r chi.Router) {
 			r.Get("/version", q.handleVersion)
 			r.Get("/webapiVersion", q.handleWebAPIVersion)
 			r.Get("/preferences", q.handlePreferences)
+			r.Post("/setPreferences", q.handleSetPreferences)
 			r.Get("/buildInfo", q.handleBuildInfo)
 			r.Get("/shutdown", q.handleShutdown)
 		})
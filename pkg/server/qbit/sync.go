@@ -0,0 +1,180 @@
+package qbit
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxSyncSnapshots bounds how many past maindata responses are kept for each
+// client, and maxSyncClients how many clients are remembered. A client whose
+// rid has aged out gets a full update, as with a restarted qBittorrent.
+const (
+	maxSyncSnapshots = 4
+	maxSyncClients   = 32
+)
+
+type MainData struct {
+	Rid               int64                      `json:"rid"`
+	FullUpdate        bool                       `json:"full_update,omitempty"`
+	Torrents          map[string]map[string]any  `json:"torrents,omitempty"`
+	TorrentsRemoved   []string                   `json:"torrents_removed,omitempty"`
+	Categories        map[string]TorrentCategory `json:"categories,omitempty"`
+	CategoriesRemoved []string                   `json:"categories_removed,omitempty"`
+	Tags              []string                   `json:"tags,omitempty"`
+	TagsRemoved       []string                   `json:"tags_removed,omitempty"`
+	ServerState       map[string]any             `json:"server_state,omitempty"`
+}
+
+// syncSnapshot is what one maindata response described.
+type syncSnapshot struct {
+	torrents    map[string]map[string]any
+	categories  map[string]TorrentCategory
+	tags        []string
+	serverState map[string]any
+}
+
+// syncClient holds the snapshots sent to one client.
+type syncClient struct {
+	snapshots map[int64]*syncSnapshot
+	lastSeen  time.Time
+}
+
+// syncState remembers recent maindata snapshots of each client by rid, so a
+// client passing its last rid back gets only what changed since. Rids are
+// shared, so one client never mistakes another's rid for its own.
+type syncState struct {
+	mu      sync.Mutex
+	rid     int64
+	clients map[string]*syncClient
+}
+
+func newSyncState() *syncState {
+	return &syncState{clients: make(map[string]*syncClient)}
+}
+
+// next records current under a new rid and returns it as a diff against the
+// snapshot the client last saw, or in full if that one is unknown.
+func (s *syncState) next(client string, rid int64, current *syncSnapshot) MainData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.clients[client]
+	if c == nil {
+		if len(s.clients) >= maxSyncClients {
+			s.forgetIdleClient()
+		}
+		c = &syncClient{snapshots: make(map[int64]*syncSnapshot)}
+		s.clients[client] = c
+	}
+	c.lastSeen = time.Now()
+	prev := c.snapshots[rid]
+	s.rid++
+	c.snapshots[s.rid] = current
+	if len(c.snapshots) > maxSyncSnapshots {
+		delete(c.snapshots, slices.Min(slices.Collect(maps.Keys(c.snapshots))))
+	}
+	return diffMainData(s.rid, prev, current)
+}
+
+// forgetIdleClient drops the client that polled least recently.
+// Callers must hold mu.
+func (s *syncState) forgetIdleClient() {
+	var idle string
+	var seen time.Time
+	for key, c := range s.clients {
+		if idle == "" || c.lastSeen.Before(seen) {
+			idle, seen = key, c.lastSeen
+		}
+	}
+	delete(s.clients, idle)
+}
+
+func diffMainData(rid int64, prev, cur *syncSnapshot) MainData {
+	if prev == nil {
+		return MainData{
+			Rid:         rid,
+			FullUpdate:  true,
+			Torrents:    cur.torrents,
+			Categories:  cur.categories,
+			Tags:        cur.tags,
+			ServerState: cur.serverState,
+		}
+	}
+
+	data := MainData{Rid: rid}
+	for hash, fields := range cur.torrents {
+		changed := changedFields(prev.torrents[hash], fields)
+		if len(changed) == 0 {
+			continue
+		}
+		if data.Torrents == nil {
+			data.Torrents = make(map[string]map[string]any)
+		}
+		data.Torrents[hash] = changed
+	}
+	data.TorrentsRemoved = removedKeys(prev.torrents, cur.torrents)
+
+	for name, category := range cur.categories {
+		if old, ok := prev.categories[name]; ok && old == category {
+			continue
+		}
+		if data.Categories == nil {
+			data.Categories = make(map[string]TorrentCategory)
+		}
+		data.Categories[name] = category
+	}
+	data.CategoriesRemoved = removedKeys(prev.categories, cur.categories)
+
+	for _, tag := range cur.tags {
+		if !slices.Contains(prev.tags, tag) {
+			data.Tags = append(data.Tags, tag)
+		}
+	}
+	for _, tag := range prev.tags {
+		if !slices.Contains(cur.tags, tag) {
+			data.TagsRemoved = append(data.TagsRemoved, tag)
+		}
+	}
+
+	if changed := changedFields(prev.serverState, cur.serverState); len(changed) > 0 {
+		data.ServerState = changed
+	}
+	return data
+}
+
+// changedFields returns the fields of cur that differ from prev. A nil prev
+// yields every field.
+func changedFields(prev, cur map[string]any) map[string]any {
+	changed := make(map[string]any)
+	for key, value := range cur {
+		if old, ok := prev[key]; !ok || !reflect.DeepEqual(old, value) {
+			changed[key] = value
+		}
+	}
+	return changed
+}
+
+func removedKeys[V any](prev, cur map[string]V) []string {
+	var removed []string
+	for _, key := range slices.Sorted(maps.Keys(prev)) {
+		if _, ok := cur[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	return removed
+}
+
+// toFields flattens a response struct into its JSON fields, the shape maindata
+// diffs work on.
+func toFields(v any) map[string]any {
+	fields := make(map[string]any)
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
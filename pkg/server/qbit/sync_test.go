@@ -0,0 +1,94 @@
+package qbit
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSyncStateDiffs(t *testing.T) {
+	s := newSyncState()
+	first := &syncSnapshot{
+		torrents: map[string]map[string]any{
+			"aaa": {"name": "one", "progress": 0.5},
+			"bbb": {"name": "two", "progress": 1.0},
+		},
+		categories:  map[string]TorrentCategory{"sonarr": {Name: "sonarr", SavePath: "/dl/sonarr"}},
+		tags:        []string{"keep"},
+		serverState: map[string]any{"dl_info_speed": 10.0},
+	}
+	full := s.next("sonarr", 0, first)
+	if !full.FullUpdate || full.Rid != 1 || len(full.Torrents) != 2 {
+		t.Fatalf("first call should be a full update with rid 1, got %+v", full)
+	}
+
+	second := &syncSnapshot{
+		torrents: map[string]map[string]any{
+			"aaa": {"name": "one", "progress": 1.0},
+			"ccc": {"name": "three", "progress": 0.0},
+		},
+		categories:  map[string]TorrentCategory{"radarr": {Name: "radarr", SavePath: "/dl/radarr"}},
+		tags:        []string{"new"},
+		serverState: map[string]any{"dl_info_speed": 10.0},
+	}
+	diff := s.next("sonarr", full.Rid, second)
+	if diff.FullUpdate || diff.Rid != 2 {
+		t.Fatalf("expected incremental update with rid 2, got %+v", diff)
+	}
+	if got := diff.Torrents["aaa"]; len(got) != 1 || got["progress"] != 1.0 {
+		t.Errorf("expected only progress to change for aaa, got %v", got)
+	}
+	if got := diff.Torrents["ccc"]; len(got) != 2 {
+		t.Errorf("expected every field for new torrent ccc, got %v", got)
+	}
+	if !slices.Equal(diff.TorrentsRemoved, []string{"bbb"}) {
+		t.Errorf("expected bbb removed, got %v", diff.TorrentsRemoved)
+	}
+	if _, ok := diff.Categories["radarr"]; !ok || !slices.Equal(diff.CategoriesRemoved, []string{"sonarr"}) {
+		t.Errorf("unexpected category diff: %v removed %v", diff.Categories, diff.CategoriesRemoved)
+	}
+	if !slices.Equal(diff.Tags, []string{"new"}) || !slices.Equal(diff.TagsRemoved, []string{"keep"}) {
+		t.Errorf("unexpected tag diff: %v removed %v", diff.Tags, diff.TagsRemoved)
+	}
+	if diff.ServerState != nil {
+		t.Errorf("unchanged server state should be omitted, got %v", diff.ServerState)
+	}
+
+	unchanged := s.next("sonarr", diff.Rid, second)
+	if len(unchanged.Torrents) != 0 || len(unchanged.TorrentsRemoved) != 0 {
+		t.Errorf("expected an empty diff, got %+v", unchanged)
+	}
+
+	if stale := s.next("sonarr", 999, second); !stale.FullUpdate {
+		t.Errorf("unknown rid should get a full update")
+	}
+}
+
+func TestSyncStatePerClient(t *testing.T) {
+	s := newSyncState()
+	snapshot := &syncSnapshot{torrents: map[string]map[string]any{"aaa": {"name": "one"}}}
+
+	sonarr := s.next("sonarr", 0, snapshot)
+	// Another client polling often doesn't age out sonarr's snapshot
+	radarr := s.next("radarr", 0, snapshot)
+	for range maxSyncSnapshots * 4 {
+		radarr = s.next("radarr", radarr.Rid, snapshot)
+		if radarr.FullUpdate {
+			t.Fatalf("radarr got a full update for its own last rid")
+		}
+	}
+	if diff := s.next("sonarr", sonarr.Rid, snapshot); diff.FullUpdate {
+		t.Errorf("sonarr got a full update after radarr polled")
+	}
+	// One client's rid means nothing to another
+	if diff := s.next("sonarr", radarr.Rid, snapshot); !diff.FullUpdate {
+		t.Errorf("sonarr passing radarr's rid should get a full update")
+	}
+
+	// Past the client limit the one idle longest is forgotten
+	for i := range maxSyncClients {
+		s.next(string(rune('a'+i)), 0, snapshot)
+	}
+	if _, ok := s.clients["sonarr"]; ok {
+		t.Errorf("idle client sonarr should have been forgotten")
+	}
+}
@@ -1,6 +1,7 @@
 package qbit
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"io"
@@ -15,13 +16,13 @@ import (
 )
 
 // All torrent-related helpers goes here
-func (q *QBit) addMagnet(ctx context.Context, url string, arr *arr.Arr, debrid string, action config.DownloadAction, callbackURL string, rmTrackerUrls, skipMultiSeason bool) error {
+func (q *QBit) addMagnet(ctx context.Context, url string, arr *arr.Arr, debrid string, policy config.Policy, callbackURL string, rmTrackerUrls bool) error {
 	magnet, err := utils.GetMagnetFromUrl(url, rmTrackerUrls)
 	if err != nil {
 		return fmt.Errorf("error parsing magnet link: %w", err)
 	}
 
-	importReq := manager.NewTorrentRequest(debrid, q.downloadFolder, magnet, arr, action, arr.DownloadUncached, callbackURL, manager.ImportTypeQBit, skipMultiSeason)
+	importReq := manager.NewTorrentRequest(debrid, q.saveFolder(arr, policy), magnet, arr, policy, cmp.Or(policy.DownloadUncached, arr.DownloadUncached), callbackURL, manager.ImportTypeQBit)
 
 	err = q.manager.AddNewTorrent(ctx, importReq)
 	if err != nil {
@@ -30,7 +31,7 @@ func (q *QBit) addMagnet(ctx context.Context, url string, arr *arr.Arr, debrid s
 	return nil
 }
 
-func (q *QBit) addTorrent(ctx context.Context, fileHeader *multipart.FileHeader, arr *arr.Arr, debrid string, action config.DownloadAction, callbackURL string, rmTrackerUrls, skipMultiSeason bool) error {
+func (q *QBit) addTorrent(ctx context.Context, fileHeader *multipart.FileHeader, arr *arr.Arr, debrid string, policy config.Policy, callbackURL string, rmTrackerUrls bool) error {
 	file, _ := fileHeader.Open()
 	defer file.Close()
 	var reader io.Reader = file
@@ -38,7 +39,7 @@ func (q *QBit) addTorrent(ctx context.Context, fileHeader *multipart.FileHeader,
 	if err != nil {
 		return fmt.Errorf("error reading file: %s \n %w", fileHeader.Filename, err)
 	}
-	importReq := manager.NewTorrentRequest(debrid, q.downloadFolder, magnet, arr, action, arr.DownloadUncached, callbackURL, manager.ImportTypeQBit, skipMultiSeason)
+	importReq := manager.NewTorrentRequest(debrid, q.saveFolder(arr, policy), magnet, arr, policy, cmp.Or(policy.DownloadUncached, arr.DownloadUncached), callbackURL, manager.ImportTypeQBit)
 	err = q.manager.AddNewTorrent(ctx, importReq)
 	if err != nil {
 		return fmt.Errorf("failed to process torrent: %w", err)
@@ -46,6 +47,12 @@ func (q *QBit) addTorrent(ctx context.Context, fileHeader *multipart.FileHeader,
 	return nil
 }
 
+// saveFolder is where an arr's torrents are saved: the category profile's
+// download folder, or the one the arr set through preferences.
+func (q *QBit) saveFolder(a *arr.Arr, policy config.Policy) string {
+	return cmp.Or(policy.DownloadFolder, q.getDownloadFolder(a))
+}
+
 func (q *QBit) ResumeTorrent(t *storage.Entry) bool {
 	return true
 }
@@ -1,6 +1,10 @@
 package qbit
 
 import (
+	"maps"
+	"slices"
+	"strings"
+
 	"github.com/sirrobot01/decypharr/internal/config"
 	debridTypes "github.com/sirrobot01/decypharr/pkg/debrid/types"
 	"github.com/sirrobot01/decypharr/pkg/storage"
@@ -167,6 +171,17 @@ type AppPreferences struct {
 
 type ScanDirs struct{}
 
+type TransferInfo struct {
+	DlInfoSpeed      int64  `json:"dl_info_speed"`
+	DlInfoData       int64  `json:"dl_info_data"`
+	UpInfoSpeed      int64  `json:"up_info_speed"`
+	UpInfoData       int64  `json:"up_info_data"`
+	DlRateLimit      int64  `json:"dl_rate_limit"`
+	UpRateLimit      int64  `json:"up_rate_limit"`
+	DhtNodes         int    `json:"dht_nodes"`
+	ConnectionStatus string `json:"connection_status"`
+}
+
 type TorrentCategory struct {
 	Name     string `json:"name"`
 	SavePath string `json:"savePath"`
@@ -380,6 +395,7 @@ type Torrent struct {
 	AmountLeft   int64                `json:"amount_left"`
 	Downloaded   int64                `json:"downloaded"`
 	MagnetURI    string               `json:"magnet_uri"`
+	Tags         string               `json:"tags"`
 	Files        []TorrentFile        `json:"files"`
 
 	Ratio      int    `json:"ratio,omitempty"`
@@ -395,7 +411,7 @@ type TorrentFile struct {
 	Name         string  `json:"name,omitempty"`
 	Size         int64   `json:"size,omitempty"`
 	Progress     int     `json:"progress,omitempty"`
-	Priority     int     `json:"priority,omitempty"`
+	Priority     int     `json:"priority"`
 	IsSeed       bool    `json:"is_seed,omitempty"`
 	PieceRange   []int   `json:"piece_range,omitempty"`
 	Availability float64 `json:"availability,omitempty"`
@@ -422,6 +438,7 @@ func convertToQBitTorrentTorrent(t *storage.Entry) Torrent {
 		AmountLeft:   int64(float64(t.Size) * (1 - t.Progress)),
 		Downloaded:   int64(float64(t.Size) * t.Progress),
 		MagnetURI:    t.Magnet,
+		Tags:         strings.Join(t.Tags, ","),
 		Files:        getTorrentFiles(t),
 
 		UpLimit:    -1,
@@ -438,16 +455,23 @@ func convertToQBitTorrentTorrent(t *storage.Entry) Torrent {
 	return qbitTorrent
 }
 
+// getTorrentFiles lists files sorted by name, so the indexes filePrio takes
+// stay stable between calls. Hidden files report priority 0.
 func getTorrentFiles(t *storage.Entry) []TorrentFile {
-	index := 0
-	files := make([]TorrentFile, 0)
-	for _, f := range t.Files {
+	names := slices.Sorted(maps.Keys(t.Files))
+	files := make([]TorrentFile, 0, len(names))
+	for index, name := range names {
+		f := t.Files[name]
+		priority := 1
+		if !f.Active() {
+			priority = 0
+		}
 		files = append(files, TorrentFile{
-			Name:  f.Name,
-			Size:  f.Size,
-			Index: index,
+			Name:     f.Name,
+			Size:     f.Size,
+			Index:    index,
+			Priority: priority,
 		})
-		index++
 	}
 	return files
 }
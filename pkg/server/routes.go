@@ -48,6 +48,9 @@ func (s *Server) WebRoutes() http.Handler {
 			r.Get("/arrs", s.handleGetArrs)
 			r.Post("/add", s.handleAddContent)
 
+			// Debrid download accounts and their traffic budgets
+			r.Get("/accounts", s.handleGetAccounts)
+
 			// Repair / health-checker operations
 			r.Get("/repair/config", s.handleGetRepairConfig)
 			r.Put("/repair/config", s.handleUpdateRepairConfig)
@@ -69,6 +72,41 @@ func (s *Server) WebRoutes() http.Handler {
 			r.Get("/torrents", s.handleGetTorrents)
 			r.Delete("/torrents/{category}/{hash}", s.handleDeleteTorrent)
 			r.Delete("/torrents", s.handleDeleteTorrents) // Fixed trailing slash
+			r.Get("/entries/{hash}/events", s.handleGetEntryEvents)
+			r.Post("/tag-rules/preview", s.handlePreviewTagRules)
+
+			// Live updates
+			r.Get("/events", s.handleEvents)
+
+			// Bulk provider migrations
+			r.Get("/migrations", s.handleListMigrations)
+			r.Post("/migrations", s.handleStartMigration)
+			r.Get("/migrations/{id}", s.handleGetMigration)
+			r.Delete("/migrations/{id}", s.handleDeleteMigration)
+			r.Post("/migrations/{id}/pause", s.handlePauseMigration)
+			r.Post("/migrations/{id}/resume", s.handleResumeMigration)
+			r.Post("/migrations/{id}/cancel", s.handleCancelMigration)
+
+			// Debrid library reconciliation
+			r.Post("/reconcile", s.handleRunReconcile)
+			r.Get("/reconcile/runs", s.handleListReconcileRuns)
+			r.Get("/reconcile/runs/{id}", s.handleGetReconcileRun)
+
+			// Retention policies
+			r.Post("/retention", s.handleRunRetention)
+			r.Get("/retention/runs", s.handleListRetentionRuns)
+			r.Get("/retention/runs/{id}", s.handleGetRetentionRun)
+
+			// Recycle bin
+			r.Get("/recycle-bin", s.handleListRecycleBin)
+			r.Delete("/recycle-bin", s.handleEmptyRecycleBin)
+			r.Post("/recycle-bin/{hash}/restore", s.handleRestoreRecycled)
+			r.Delete("/recycle-bin/{hash}", s.handlePurgeRecycled)
+
+			// Debrid expiry guardian
+			r.Post("/guardian", s.handleRunGuardian)
+			r.Get("/guardian/runs", s.handleListGuardianRuns)
+			r.Get("/guardian/runs/{id}", s.handleGetGuardianRun)
 
 			// Browse - WebDAV-style hierarchical file browser
 			r.Route("/browse", func(r chi.Router) {
@@ -91,6 +129,9 @@ func (s *Server) WebRoutes() http.Handler {
 			r.Post("/config", s.handleUpdateConfig)
 			r.Post("/mount/cache/cleanup", s.handleRunMountCacheCleanup)
 			r.Post("/mount/cache/purge", s.handlePurgeMountCache)
+			r.Get("/mount/pins", s.handleGetCachePins)
+			r.Post("/mount/pins", s.handleAddCachePin)
+			r.Delete("/mount/pins/{type}/{value}", s.handleDeleteCachePin)
 			r.Post("/refresh-token", s.handleRefreshAPIToken)
 			r.Post("/update-auth", s.handleUpdateAuth)
 		})
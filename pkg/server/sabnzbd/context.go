@@ -2,12 +2,9 @@ package sabnzbd
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"strings"
 
-	"github.com/sirrobot01/decypharr/internal/config"
-
 	"github.com/sirrobot01/decypharr/pkg/arr"
 )
 
@@ -93,7 +90,7 @@ func (s *SABnzbd) authContext(next http.Handler) http.Handler {
 		host := r.URL.Query().Get("ma_username")
 		token := r.URL.Query().Get("ma_password")
 		category := getCategory(r.Context())
-		a, err := s.authenticate(category, host, token)
+		a, err := s.manager.Arr().Authenticate(category, host, token)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusUnauthorized)
 			return
@@ -102,43 +99,3 @@ func (s *SABnzbd) authContext(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
-
-func (s *SABnzbd) authenticate(category, username, password string) (*arr.Arr, error) {
-	cfg := config.Get()
-	a := s.manager.Arr().Get(category)
-	if a == nil {
-		// Arr is not yet in runtime storage — look for a matching config entry
-		// so we inherit its download_uncached setting. If no config match,
-		// leave nil so SendToDebrid falls back to the debrid provider's setting.
-		var downloadUncached *bool
-		for _, cfgArr := range config.Get().Arrs {
-			if cfgArr.Name == category {
-				downloadUncached = cfgArr.DownloadUncached
-				break
-			}
-		}
-		a = arr.New(category, username, password, false, downloadUncached, "", "auto")
-	}
-	arrValidated := false // This is a flag to indicate if arr validation was successful
-	if (username == "" || password == "") && cfg.UseAuth {
-		return nil, fmt.Errorf("unauthorized: Host and token are required for authentication(you've enabled authentication)")
-	}
-	if a.Source == "auto" {
-		a.Host = username
-		a.Token = password
-	}
-	if err := a.Validate(); err == nil {
-		arrValidated = true
-	}
-
-	if !arrValidated && cfg.UseAuth {
-		// If arr validation failed, try to use user auth validation
-		if !config.VerifyAuth(username, password) {
-			return nil, fmt.Errorf("unauthorized: invalid credentials")
-		}
-	}
-	if username != "" && password != "" {
-		s.manager.Arr().AddOrUpdate(a)
-	}
-	return a, nil
-}
@@ -1,14 +1,19 @@
 package sabnzbd
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/nntp"
 	"github.com/sirrobot01/decypharr/internal/utils"
 	"github.com/sirrobot01/decypharr/pkg/arr"
 	"github.com/sirrobot01/decypharr/pkg/manager"
@@ -26,7 +31,7 @@ func (s *SABnzbd) handleAPI(w http.ResponseWriter, r *http.Request) {
 	case ModeHistory:
 		s.handleHistory(w, r)
 	case ModeConfig:
-		s.handleConfig(w, r)
+		s.handleSetConfig(w, r)
 	case ModeStatus, ModeFullStatus:
 		s.handleStatus(w, r)
 	case ModeGetConfig:
@@ -43,6 +48,16 @@ func (s *SABnzbd) handleAPI(w http.ResponseWriter, r *http.Request) {
 		s.handleGetScripts(w, r)
 	case ModeGetFiles:
 		s.handleGetFiles(w, r)
+	case ModeSwitch:
+		s.handleSwitch(w, r)
+	case ModeChangeCat:
+		s.handleChangeCategory(w, r)
+	case ModeChangeOpts:
+		s.handleChangeOpts(w, r)
+	case ModeServerStats:
+		s.handleServerStats(w, r)
+	case ModeWarnings:
+		s.handleWarnings(w, r)
 	default:
 		// Default to queue if no mode specified
 		s.logger.Warn().Str("mode", mode).Msg("Unknown API mode, returning 404")
@@ -64,6 +79,8 @@ func (s *SABnzbd) handleQueue(w http.ResponseWriter, r *http.Request) {
 		s.handleQueuePause(w, r)
 	case "resume":
 		s.handleQueueResume(w, r)
+	case "priority":
+		s.handleQueuePriority(w, r)
 	}
 }
 
@@ -153,11 +170,16 @@ func (s *SABnzbd) handleListQueue(w http.ResponseWriter, r *http.Request) {
 		nzoIDs = strings.Split(nzoIDsVal, ",")
 	}
 
-	entries := s.manager.Queue().ListFilter(category, config.ProtocolNZB, storage.EntryStateDownloading, nzoIDs, "added_on", false)
+	entries := s.queueOrder(category, nzoIDs)
 
 	queue := Queue{
-		Version: Version,
-		Slots:   []QueueSlot{},
+		Version:    Version,
+		SpeedLimit: "100",
+		Slots:      []QueueSlot{},
+	}
+	if limit := s.manager.DownloadSpeedLimit(); limit > 0 {
+		queue.SpeedLimit = ""
+		queue.SpeedLimitAbs = strconv.FormatInt(limit, 10)
 	}
 
 	const MB = 1024 * 1024
@@ -222,7 +244,7 @@ func (s *SABnzbd) handleHistory(w http.ResponseWriter, r *http.Request) {
 	name = strings.ToLower(strings.TrimSpace(name))
 	switch name {
 	case "delete":
-		s.handleDelete(w, r)
+		s.handleHistoryDelete(w, r)
 	default:
 		s.writeError(w, "Unknown history action", http.StatusBadRequest)
 	}
@@ -287,11 +309,20 @@ func (s *SABnzbd) handleAddURL(w http.ResponseWriter, r *http.Request) {
 	}
 
 	urls := r.URL.Query().Get("name")
+	priority, err := parsePriority(r.URL.Query().Get("priority"))
+	if err != nil {
+		s.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	cfg := config.Get()
-	action := cfg.DefaultDownloadAction
-	if r.URL.Query().Get("action") != "" {
-		action = config.DownloadAction(r.URL.Query().Get("action"))
+	// The category profile decides the action unless the request names one
+	policy := config.Get().PolicyFor(_arr.Name)
+	if param := r.URL.Query().Get("action"); param != "" {
+		policy.DownloadAction = config.DownloadAction(param)
+		if !policy.DownloadAction.Valid() {
+			s.writeError(w, fmt.Sprintf("Invalid action %q", param), http.StatusBadRequest)
+			return
+		}
 	}
 
 	if urls == "" {
@@ -310,7 +341,7 @@ func (s *SABnzbd) handleAddURL(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		nzoID, err := s.addNZBURL(ctx, url, _arr, action)
+		nzoID, err := s.addNZBURL(ctx, url, _arr, policy, priority)
 		if err != nil {
 			s.logger.Error().Err(err).Str("url", url).Msg("Failed to add NZB from URL")
 			errors = append(errors, fmt.Sprintf("Failed to add %s: %v", url, err))
@@ -366,10 +397,20 @@ func (s *SABnzbd) handleAddFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cfg := config.Get()
-	action := cfg.DefaultDownloadAction
-	if r.URL.Query().Get("action") != "" {
-		action = config.DownloadAction(r.URL.Query().Get("action"))
+	priority, err := parsePriority(r.FormValue("priority"))
+	if err != nil {
+		s.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The category profile decides the action unless the request names one
+	policy := config.Get().PolicyFor(_arr.Name)
+	if param := r.URL.Query().Get("action"); param != "" {
+		policy.DownloadAction = config.DownloadAction(param)
+		if !policy.DownloadAction.Valid() {
+			s.writeError(w, fmt.Sprintf("Invalid action %q", param), http.StatusBadRequest)
+			return
+		}
 	}
 
 	var nzoIDs []string
@@ -400,7 +441,7 @@ func (s *SABnzbd) handleAddFile(w http.ResponseWriter, r *http.Request) {
 			}
 
 			// Parse NZB file
-			nzbID, err := s.addNZBFile(ctx, content, fileHeader.Filename, _arr, action)
+			nzbID, err := s.addNZBFile(ctx, content, fileHeader.Filename, _arr, policy, priority)
 			if err != nil {
 				s.logger.Error().Err(err).Str("filename", fileHeader.Filename).Msg("Failed to add NZB file")
 				errors = append(errors, fmt.Sprintf("Failed to add %s: %v", fileHeader.Filename, err))
@@ -427,7 +468,7 @@ func (s *SABnzbd) handleAddFile(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Parse NZB file
-		nzbID, err := s.addNZBFile(ctx, content, header.Filename, _arr, action)
+		nzbID, err := s.addNZBFile(ctx, content, header.Filename, _arr, policy, priority)
 		if err != nil {
 			s.writeError(w, fmt.Sprintf("Failed to add NZB file: %s", err.Error()), http.StatusInternalServerError)
 			return
@@ -516,6 +557,224 @@ func (s *SABnzbd) handleStatus(w http.ResponseWriter, r *http.Request) {
 	utils.JSONResponse(w, response, http.StatusOK)
 }
 
+// handleQueuePriority changes the priority of queued NZBs and returns the
+// new queue position of the first one
+func (s *SABnzbd) handleQueuePriority(w http.ResponseWriter, r *http.Request) {
+	nzoIDs := splitIDs(r.URL.Query().Get("value"))
+	if len(nzoIDs) == 0 {
+		s.writeError(w, "No NZB IDs provided", http.StatusBadRequest)
+		return
+	}
+	priority, err := parsePriority(r.URL.Query().Get("value2"))
+	if err != nil {
+		s.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, nzoID := range nzoIDs {
+		if _, err := s.manager.SetEntryPriority(nzoID, priority); err != nil {
+			s.writeError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+	}
+	response := map[string]int{
+		"position": s.queueIndex(nzoIDs[0]),
+	}
+	utils.JSONResponse(w, response, http.StatusOK)
+}
+
+// handleSwitch moves a queued NZB to where another one sits. value2 is
+// either that NZB's ID or a queue index.
+func (s *SABnzbd) handleSwitch(w http.ResponseWriter, r *http.Request) {
+	nzoID := strings.TrimSpace(r.URL.Query().Get("value"))
+	target := strings.TrimSpace(r.URL.Query().Get("value2"))
+	if nzoID == "" || target == "" {
+		s.writeError(w, "value and value2 are required", http.StatusBadRequest)
+		return
+	}
+
+	positions := s.manager.QueuePositions()
+	position := 0
+	if index, err := strconv.Atoi(target); err == nil {
+		order := s.queueOrder("", nil)
+		switch {
+		case index >= len(order):
+			position = math.MaxInt
+		case index >= 0:
+			// Running jobs cannot be passed; landing on one means the front
+			// of the pending jobs.
+			position = max(positions[order[index].InfoHash], 0)
+		}
+	} else if p, ok := positions[target]; ok {
+		position = p
+	}
+
+	_, priority, err := s.manager.MoveEntry(nzoID, position)
+	if err != nil {
+		s.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	response := map[string]any{
+		"result": map[string]int{
+			"priority": priority,
+			"position": s.queueIndex(nzoID),
+		},
+	}
+	utils.JSONResponse(w, response, http.StatusOK)
+}
+
+// handleChangeCategory moves NZBs to another category
+func (s *SABnzbd) handleChangeCategory(w http.ResponseWriter, r *http.Request) {
+	nzoIDs := splitIDs(r.URL.Query().Get("value"))
+	category := strings.TrimSpace(r.URL.Query().Get("value2"))
+	if len(nzoIDs) == 0 {
+		s.writeError(w, "No NZB IDs provided", http.StatusBadRequest)
+		return
+	}
+	if category == "" || category == "*" {
+		s.writeError(w, "Category is required", http.StatusBadRequest)
+		return
+	}
+	for _, nzoID := range nzoIDs {
+		if err := s.manager.SetEntryCategory(nzoID, category); err != nil {
+			s.writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	utils.JSONResponse(w, StatusResponse{Status: true}, http.StatusOK)
+}
+
+// handleChangeOpts accepts post-processing changes. Repair and unpack always
+// run as part of processing, so there is nothing to change.
+func (s *SABnzbd) handleChangeOpts(w http.ResponseWriter, r *http.Request) {
+	nzoIDs := splitIDs(r.URL.Query().Get("value"))
+	if len(nzoIDs) == 0 {
+		s.writeError(w, "No NZB IDs provided", http.StatusBadRequest)
+		return
+	}
+	for _, nzoID := range nzoIDs {
+		if _, err := s.manager.Queue().GetTorrent(nzoID); err != nil {
+			s.writeError(w, fmt.Sprintf("NZB %s not found", nzoID), http.StatusNotFound)
+			return
+		}
+	}
+	utils.JSONResponse(w, StatusResponse{Status: true}, http.StatusOK)
+}
+
+// handleHistoryDelete removes history slots. With del_files=1 a failed NZB
+// also loses its parsed NZB and stored entry; completed ones keep their
+// files, since the library may link into them.
+func (s *SABnzbd) handleHistoryDelete(w http.ResponseWriter, r *http.Request) {
+	value := strings.TrimSpace(r.URL.Query().Get("value"))
+	delFiles := r.URL.Query().Get("del_files") == "1"
+	cat := getCategory(r.Context())
+	if value == "" {
+		s.writeError(w, "No NZB IDs provided", http.StatusBadRequest)
+		return
+	}
+
+	var entries []*storage.Entry
+	switch value {
+	case "all":
+		entries = s.manager.Queue().ListFilter(cat, config.ProtocolNZB, storage.EntryStatePausedUP, nil, "", false)
+		entries = append(entries, s.manager.Queue().ListFilter(cat, config.ProtocolNZB, storage.EntryStateError, nil, "", false)...)
+	case "failed":
+		entries = s.manager.Queue().ListFilter(cat, config.ProtocolNZB, storage.EntryStateError, nil, "", false)
+	default:
+		for _, nzoID := range splitIDs(value) {
+			entry, err := s.manager.Queue().GetTorrent(nzoID)
+			if err != nil {
+				s.logger.Warn().Str("nzo_id", nzoID).Msg("History entry not found for deletion")
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	for _, entry := range entries {
+		if err := s.manager.Queue().Delete(entry.InfoHash, nil); err != nil {
+			s.logger.Error().Err(err).Str("nzo_id", entry.InfoHash).Msg("Failed to delete history entry")
+			continue
+		}
+		if delFiles && entry.State == storage.EntryStateError {
+			if err := s.manager.PurgeFailedNZB(entry.InfoHash); err != nil {
+				s.logger.Warn().Err(err).Str("nzo_id", entry.InfoHash).Msg("Failed to remove files of failed NZB")
+			}
+		}
+	}
+	utils.JSONResponse(w, StatusResponse{Status: true}, http.StatusOK)
+}
+
+// handleServerStats returns the traffic per usenet server
+func (s *SABnzbd) handleServerStats(w http.ResponseWriter, r *http.Request) {
+	utils.JSONResponse(w, serverStats(s.manager.UsenetTraffic()), http.StatusOK)
+}
+
+// serverStats totals the traffic of the usenet servers
+func serverStats(providers []nntp.ProviderTraffic) ServerStatsResponse {
+	response := ServerStatsResponse{
+		Servers: make(map[string]ServerStats),
+	}
+	for _, traffic := range providers {
+		response.Total += traffic.Total
+		response.Month += traffic.Month
+		response.Week += traffic.Week
+		response.Day += traffic.Day
+		response.Servers[traffic.Host] = ServerStats{
+			Total:           traffic.Total,
+			Month:           traffic.Month,
+			Week:            traffic.Week,
+			Day:             traffic.Day,
+			Daily:           traffic.Daily,
+			ArticlesTried:   traffic.ArticlesTried,
+			ArticlesSuccess: traffic.ArticlesSuccess,
+		}
+	}
+	return response
+}
+
+// handleWarnings lists the errors of failed NZBs, oldest first. name=clear
+// hides the ones raised so far.
+func (s *SABnzbd) handleWarnings(w http.ResponseWriter, r *http.Request) {
+	if strings.EqualFold(r.URL.Query().Get("name"), "clear") {
+		s.warningsClearedAt.Store(time.Now().Unix())
+		utils.JSONResponse(w, StatusResponse{Status: true}, http.StatusOK)
+		return
+	}
+
+	clearedAt := s.warningsClearedAt.Load()
+	warnings := []Warning{}
+	for _, entry := range s.manager.Queue().ListFilter("", config.ProtocolNZB, storage.EntryStateError, nil, "", false) {
+		if entry.LastError == "" || entry.LastErrorTime == nil || entry.LastErrorTime.Unix() <= clearedAt {
+			continue
+		}
+		warnings = append(warnings, Warning{
+			Text: fmt.Sprintf("%s: %s", entry.Name, entry.LastError),
+			Type: "WARNING",
+			Time: entry.LastErrorTime.Unix(),
+		})
+	}
+	slices.SortFunc(warnings, func(a, b Warning) int {
+		return cmp.Compare(a.Time, b.Time)
+	})
+	utils.JSONResponse(w, WarningsResponse{Warnings: warnings}, http.StatusOK)
+}
+
+// handleSetConfig handles the config mode. name=speedlimit sets the download
+// speed cap; anything else returns the configuration.
+func (s *SABnzbd) handleSetConfig(w http.ResponseWriter, r *http.Request) {
+	if !strings.EqualFold(r.URL.Query().Get("name"), "speedlimit") {
+		s.handleConfig(w, r)
+		return
+	}
+	limit, err := parseSpeedLimit(r.URL.Query().Get("value"))
+	if err != nil {
+		s.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.manager.SetDownloadSpeedLimit(limit)
+	utils.JSONResponse(w, StatusResponse{Status: true}, http.StatusOK)
+}
+
 // Helper methods
 
 func (s *SABnzbd) getHistory(ctx context.Context, limit int, nzoIDs []string) History {
@@ -566,7 +825,7 @@ func (s *SABnzbd) writeError(w http.ResponseWriter, message string, status int)
 	utils.JSONResponse(w, response, status)
 }
 
-func (s *SABnzbd) addNZBURL(ctx context.Context, url string, arr *arr.Arr, action config.DownloadAction) (string, error) {
+func (s *SABnzbd) addNZBURL(ctx context.Context, url string, arr *arr.Arr, policy config.Policy, priority int) (string, error) {
 	if url == "" {
 		return "", fmt.Errorf("URL is required")
 	}
@@ -581,17 +840,18 @@ func (s *SABnzbd) addNZBURL(ctx context.Context, url string, arr *arr.Arr, actio
 		s.logger.Warn().Str("url", url).Msg("Downloaded content is empty")
 		return "", fmt.Errorf("downloaded content is empty")
 	}
-	return s.addNZBFile(ctx, content, filename, arr, action)
+	return s.addNZBFile(ctx, content, filename, arr, policy, priority)
 }
 
-func (s *SABnzbd) addNZBFile(ctx context.Context, content []byte, filename string, arr *arr.Arr, action config.DownloadAction) (string, error) {
+func (s *SABnzbd) addNZBFile(ctx context.Context, content []byte, filename string, arr *arr.Arr, policy config.Policy, priority int) (string, error) {
 	if len(content) == 0 {
 		return "", fmt.Errorf("NZB content is empty")
 	}
 
-	cfg := config.Get()
+	downloadFolder := cmp.Or(policy.DownloadFolder, s.downloadFolder)
 
-	importReq := manager.NewNZBRequest(filename, s.downloadFolder, content, arr, action, cfg.Notifications.CallbackURL, manager.ImportTypeSABnzbd, cfg.SkipMultiSeason)
+	importReq := manager.NewNZBRequest(filename, downloadFolder, content, arr, policy, config.Get().Notifications.CallbackURL, manager.ImportTypeSABnzbd)
+	importReq.Priority = priority
 	id, err := s.manager.AddNewNZB(ctx, importReq)
 	if err != nil {
 		return "", err
@@ -599,6 +859,62 @@ func (s *SABnzbd) addNZBFile(ctx context.Context, content []byte, filename strin
 	return id, nil
 }
 
+// queueOrder lists queued NZBs in processing order: running jobs first, then
+// the jobs waiting for a slot in the order they will be picked up.
+func (s *SABnzbd) queueOrder(category string, nzoIDs []string) []*storage.Entry {
+	entries := s.manager.Queue().ListFilter(category, config.ProtocolNZB, storage.EntryStateDownloading, nzoIDs, "added_on", false)
+	s.manager.SortByQueuePosition(entries)
+	return entries
+}
+
+// queueIndex returns an NZB's index in the queue, or -1 if it is not queued.
+func (s *SABnzbd) queueIndex(nzoID string) int {
+	return slices.IndexFunc(s.queueOrder("", nil), func(e *storage.Entry) bool {
+		return e.InfoHash == nzoID
+	})
+}
+
+// splitIDs splits a comma-separated list of NZB IDs
+func splitIDs(value string) []string {
+	var ids []string
+	for id := range strings.SplitSeq(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// parseSpeedLimit parses a SABnzbd speed limit into bytes per second.
+// SABnzbd takes either an absolute value with a K, M or G suffix or a
+// percentage of the configured line speed. There is no line speed here, so
+// only 0 and 100 percent, both meaning no limit, are accepted.
+func parseSpeedLimit(value string) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return 0, fmt.Errorf("speed limit is required")
+	}
+	switch value[len(value)-1] {
+	case 'K', 'M', 'G':
+		value += "B"
+	}
+	if strings.HasSuffix(value, "B") {
+		limit, err := config.ParseSize(value)
+		if err != nil || limit < 0 {
+			return 0, fmt.Errorf("invalid speed limit %q", value)
+		}
+		return limit, nil
+	}
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid speed limit %q", value)
+	}
+	if percent != 0 && percent != 100 {
+		return 0, fmt.Errorf("percentage speed limits need a line speed; use an absolute value such as 10M")
+	}
+	return 0, nil
+}
+
 // formatSize formats bytes to human-readable string (SABnzbd format)
 func formatSize(bytes int64) string {
 	const (
@@ -0,0 +1,287 @@
+package sabnzbd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/nntp"
+	debridTypes "github.com/sirrobot01/decypharr/pkg/debrid/types"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// queuedNZB is an NZB waiting for a download slot
+type queuedNZB struct {
+	id       string
+	category string
+	priority int
+}
+
+// newTestSABnzbd returns a SABnzbd over a fresh manager whose queue holds
+// nzbs, in order, at their priorities. The queue's workers are stopped so
+// the jobs stay pending whatever their priority.
+func newTestSABnzbd(t *testing.T, nzbs ...queuedNZB) *SABnzbd {
+	t.Helper()
+	dir := t.TempDir()
+	config.SetConfigPath(dir)
+
+	// Stored before the manager starts, which queues them as it restores
+	// the active downloads. Paused jobs are never picked up.
+	strg, err := storage.NewStorage(filepath.Join(dir, "db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	added := time.Now().Add(-time.Hour)
+	for i, nzb := range nzbs {
+		err := strg.AddQueue(&storage.Entry{
+			InfoHash: nzb.id,
+			Name:     nzb.id,
+			Category: nzb.category,
+			SavePath: filepath.Join(dir, "downloads", nzb.category),
+			Protocol: config.ProtocolNZB,
+			State:    storage.EntryStateDownloading,
+			Status:   debridTypes.TorrentStatusDownloading,
+			AddedOn:  added.Add(time.Duration(i) * time.Second),
+			Priority: manager.PriorityPaused,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := strg.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := manager.New()
+	t.Cleanup(func() { _ = m.Stop() })
+	deadline := time.Now().Add(5 * time.Second)
+	for len(m.QueuePositions()) < len(nzbs) {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d of %d jobs restored", len(m.QueuePositions()), len(nzbs))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	m.JobQueue().Close()
+	for _, nzb := range nzbs {
+		if nzb.priority == manager.PriorityPaused {
+			continue
+		}
+		if _, err := m.SetEntryPriority(nzb.id, nzb.priority); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return New(m)
+}
+
+// call runs an API request and decodes its JSON response into v
+func (s *SABnzbd) call(t *testing.T, query string, v any) int {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api?"+query, nil)
+	rec := httptest.NewRecorder()
+	s.modeContext(http.HandlerFunc(s.handleAPI)).ServeHTTP(rec, req)
+	if rec.Code == http.StatusOK && v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s: %v: %s", query, err, rec.Body.String())
+		}
+	}
+	return rec.Code
+}
+
+// order lists the queued NZB IDs in processing order
+func (s *SABnzbd) order() []string {
+	var ids []string
+	for _, e := range s.queueOrder("", nil) {
+		ids = append(ids, e.InfoHash)
+	}
+	return ids
+}
+
+func TestQueuePriority(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		wantCode     int
+		wantPriority int // Of a afterwards
+		wantPosition int
+		wantOrder    []string
+	}{
+		{"raised ahead of normal", "value=a&value2=1", http.StatusOK, 1, 0, []string{"a", "b", "c"}},
+		{"default is normal", "value=a&value2=-100", http.StatusOK, 0, 2, []string{"b", "c", "a"}},
+		{"lowered behind normal", "value=a&value2=-1", http.StatusOK, -1, 2, []string{"b", "c", "a"}},
+		{"paused sorts last", "value=a&value2=-2", http.StatusOK, manager.PriorityPaused, 2, []string{"b", "c", "a"}},
+		{"several at once", "value=c,a&value2=2", http.StatusOK, 2, 0, []string{"c", "a", "b"}},
+		{"out of range", "value=a&value2=3", http.StatusBadRequest, 0, 0, []string{"a", "b", "c"}},
+		{"no ids", "value=&value2=1", http.StatusBadRequest, 0, 0, []string{"a", "b", "c"}},
+		{"unknown id", "value=missing&value2=1", http.StatusNotFound, 0, 0, []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSABnzbd(t, queuedNZB{id: "a"}, queuedNZB{id: "b"}, queuedNZB{id: "c"})
+			var resp map[string]int
+			if code := s.call(t, "mode=queue&name=priority&"+tt.query, &resp); code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusOK && resp["position"] != tt.wantPosition {
+				t.Errorf("position = %d, want %d", resp["position"], tt.wantPosition)
+			}
+			if entry, _ := s.manager.Queue().GetTorrent("a"); entry.Priority != tt.wantPriority {
+				t.Errorf("priority = %d, want %d", entry.Priority, tt.wantPriority)
+			}
+			if got := fmt.Sprint(s.order()); got != fmt.Sprint(tt.wantOrder) {
+				t.Errorf("order = %s, want %v", got, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestSwitch(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		wantCode     int
+		wantPriority int
+		wantPosition int
+		wantOrder    []string
+	}{
+		{"to another nzb takes its priority", "value=c&value2=high", http.StatusOK, 1, 0, []string{"c", "high", "a", "b"}},
+		{"to an index", "value=high&value2=2", http.StatusOK, 0, 2, []string{"a", "b", "high", "c"}},
+		{"past the end", "value=a&value2=99", http.StatusOK, 0, 3, []string{"high", "b", "c", "a"}},
+		{"missing target", "value=a", http.StatusBadRequest, 0, 0, []string{"high", "a", "b", "c"}},
+		{"unknown nzb", "value=missing&value2=0", http.StatusBadRequest, 0, 0, []string{"high", "a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSABnzbd(t, queuedNZB{id: "a"}, queuedNZB{id: "b"}, queuedNZB{id: "c"}, queuedNZB{id: "high", priority: 1})
+			var resp struct {
+				Result struct {
+					Priority int `json:"priority"`
+					Position int `json:"position"`
+				} `json:"result"`
+			}
+			if code := s.call(t, "mode=switch&"+tt.query, &resp); code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusOK && (resp.Result.Priority != tt.wantPriority || resp.Result.Position != tt.wantPosition) {
+				t.Errorf("result = %+v, want priority %d at %d", resp.Result, tt.wantPriority, tt.wantPosition)
+			}
+			if got := fmt.Sprint(s.order()); got != fmt.Sprint(tt.wantOrder) {
+				t.Errorf("order = %s, want %v", got, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestChangeCategory(t *testing.T) {
+	s := newTestSABnzbd(t, queuedNZB{id: "a", category: "sonarr"}, queuedNZB{id: "b", category: "sonarr"})
+	for _, query := range []string{"value=a&value2=*", "value=a&value2=", "value=&value2=radarr", "value=a&value2=../radarr"} {
+		if code := s.call(t, "mode=change_cat&"+query, nil); code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", query, code, http.StatusBadRequest)
+		}
+	}
+
+	var resp StatusResponse
+	if code := s.call(t, "mode=change_cat&value=a&value2=radarr", &resp); code != http.StatusOK || !resp.Status {
+		t.Fatalf("status = %d, %+v", code, resp)
+	}
+	entry, err := s.manager.Queue().GetTorrent("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Category != "radarr" || filepath.Base(entry.SavePath) != "radarr" {
+		t.Errorf("category %q, save path %q; want both radarr", entry.Category, entry.SavePath)
+	}
+	if _, err := os.Stat(entry.SavePath); err != nil {
+		t.Errorf("category folder not created: %v", err)
+	}
+	if other, _ := s.manager.Queue().GetTorrent("b"); other.Category != "sonarr" {
+		t.Errorf("other nzb moved to %q", other.Category)
+	}
+}
+
+func TestSetSpeedLimit(t *testing.T) {
+	s := newTestSABnzbd(t)
+	tests := []struct {
+		value    string
+		wantCode int
+		want     int64
+	}{
+		{"10M", http.StatusOK, 10 << 20},
+		{"512k", http.StatusOK, 512 << 10},
+		{"100", http.StatusOK, 0},
+		{"1G", http.StatusOK, 1 << 30},
+		{"0", http.StatusOK, 0},
+		{"50", http.StatusBadRequest, 0},
+		{"fast", http.StatusBadRequest, 0},
+		{"", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		s.manager.SetDownloadSpeedLimit(123)
+		code := s.call(t, "mode=config&name=speedlimit&value="+tt.value, nil)
+		if code != tt.wantCode {
+			t.Errorf("%q: status = %d, want %d", tt.value, code, tt.wantCode)
+			continue
+		}
+		want := tt.want
+		if code != http.StatusOK {
+			want = 123 // Left alone
+		}
+		if got := s.manager.DownloadSpeedLimit(); got != want {
+			t.Errorf("%q: limit = %d, want %d", tt.value, got, want)
+		}
+	}
+}
+
+func TestServerStats(t *testing.T) {
+	got := serverStats([]nntp.ProviderTraffic{
+		{Host: "news.one", Total: 100, Month: 50, Week: 20, Day: 5, Daily: map[string]int64{"2026-10-18": 5}},
+		{Host: "news.two", Total: 10, Month: 10, Week: 10, Day: 1, ArticlesTried: map[string]int64{"2026-10-18": 4}},
+	})
+	if got.Total != 110 || got.Month != 60 || got.Week != 30 || got.Day != 6 {
+		t.Errorf("totals = %d/%d/%d/%d, want 110/60/30/6", got.Total, got.Month, got.Week, got.Day)
+	}
+	if len(got.Servers) != 2 || got.Servers["news.one"].Daily["2026-10-18"] != 5 || got.Servers["news.two"].ArticlesTried["2026-10-18"] != 4 {
+		t.Errorf("unexpected servers %+v", got.Servers)
+	}
+
+	// Without usenet the servers are an empty object, not null
+	s := newTestSABnzbd(t)
+	var resp map[string]json.RawMessage
+	if code := s.call(t, "mode=server_stats", &resp); code != http.StatusOK {
+		t.Fatalf("status = %d", code)
+	}
+	if string(resp["servers"]) != "{}" || string(resp["total"]) != "0" {
+		t.Errorf("unexpected response %v", resp)
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{PriorityDefault, 0, false},
+		{PriorityForced, 2, false},
+		{PriorityHigh, 1, false},
+		{" 0 ", 0, false},
+		{PriorityLow, -1, false},
+		{PriorityStop, manager.PriorityPaused, false},
+		{"3", 0, true},
+		{"-3", 0, true},
+		{"high", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parsePriority(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parsePriority(%q) = %d, %v; want %d, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
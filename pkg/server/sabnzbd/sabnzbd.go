@@ -1,7 +1,9 @@
 package sabnzbd
 
 import (
+	"cmp"
 	"path/filepath"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -18,6 +20,9 @@ type SABnzbd struct {
 	manager           *manager.Manager
 	defaultCategories []string
 	config            *Config
+
+	// Warnings raised before this time (unix seconds) were cleared
+	warningsClearedAt atomic.Int64
 }
 
 func New(manager *manager.Manager) *SABnzbd {
@@ -92,7 +97,7 @@ func (s *SABnzbd) getCategories() []Category {
 			Order:    i + 1,
 			Pp:       "3",
 			Script:   "None",
-			Dir:      filepath.Join(s.downloadFolder, a.Name),
+			Dir:      s.categoryDir(a.Name),
 			Priority: PriorityNormal,
 		})
 	}
@@ -107,7 +112,7 @@ func (s *SABnzbd) getCategories() []Category {
 			Order:    len(categories) + 1,
 			Pp:       "3",
 			Script:   "None",
-			Dir:      filepath.Join(s.downloadFolder, defaultCat),
+			Dir:      s.categoryDir(defaultCat),
 			Priority: PriorityNormal,
 		})
 		added[defaultCat] = struct{}{}
@@ -116,5 +121,12 @@ func (s *SABnzbd) getCategories() []Category {
 	return categories
 }
 
+// categoryDir is where downloads of a category are saved, following its
+// category profile's download folder when it has one.
+func (s *SABnzbd) categoryDir(category string) string {
+	folder := cmp.Or(config.Get().PolicyFor(category).DownloadFolder, s.downloadFolder)
+	return filepath.Join(folder, category)
+}
+
 func (s *SABnzbd) Reset() {
 }
@@ -2,8 +2,11 @@ package sabnzbd
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	debridTypes "github.com/sirrobot01/decypharr/pkg/debrid/types"
+	"github.com/sirrobot01/decypharr/pkg/manager"
 	"github.com/sirrobot01/decypharr/pkg/storage"
 )
 
@@ -22,8 +25,10 @@ type QueueResponse struct {
 
 // Queue represents the download queue
 type Queue struct {
-	Version string      `json:"version"`
-	Slots   []QueueSlot `json:"slots"`
+	Version       string      `json:"version"`
+	SpeedLimit    string      `json:"speedlimit"`     // Percentage of line speed, "100" when unlimited
+	SpeedLimitAbs string      `json:"speedlimit_abs"` // Bytes per second, empty when unlimited
+	Slots         []QueueSlot `json:"slots"`
 }
 
 // QueueSlot represents a download in the queue
@@ -80,6 +85,39 @@ type StageLog struct {
 	Actions []string `json:"actions"`
 }
 
+// ServerStatsResponse represents the traffic per usenet server
+type ServerStatsResponse struct {
+	Total   int64                  `json:"total"`
+	Month   int64                  `json:"month"`
+	Week    int64                  `json:"week"`
+	Day     int64                  `json:"day"`
+	Servers map[string]ServerStats `json:"servers"`
+}
+
+// ServerStats represents the traffic of one usenet server. Daily maps are
+// keyed by date (YYYY-MM-DD).
+type ServerStats struct {
+	Total           int64            `json:"total"`
+	Month           int64            `json:"month"`
+	Week            int64            `json:"week"`
+	Day             int64            `json:"day"`
+	Daily           map[string]int64 `json:"daily"`
+	ArticlesTried   map[string]int64 `json:"articles_tried"`
+	ArticlesSuccess map[string]int64 `json:"articles_success"`
+}
+
+// WarningsResponse represents the warnings list
+type WarningsResponse struct {
+	Warnings []Warning `json:"warnings"`
+}
+
+// Warning represents a single warning
+type Warning struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+	Time int64  `json:"time"`
+}
+
 // VersionResponse represents version information
 type VersionResponse struct {
 	Version string `json:"version"`
@@ -120,24 +158,29 @@ type AddNZBResponse struct {
 
 // API Mode constants
 const (
-	ModeQueue      = "queue"
-	ModeHistory    = "history"
-	ModeConfig     = "config"
-	ModeGetConfig  = "get_config"
-	ModeAddURL     = "addurl"
-	ModeAddFile    = "addfile"
-	ModeVersion    = "version"
-	ModePause      = "pause"
-	ModeResume     = "resume"
-	ModeDelete     = "delete"
-	ModeShutdown   = "shutdown"
-	ModeRestart    = "restart"
-	ModeGetCats    = "get_cats"
-	ModeGetScripts = "get_scripts"
-	ModeGetFiles   = "get_files"
-	ModeRetry      = "retry"
-	ModeStatus     = "status"
-	ModeFullStatus = "fullstatus"
+	ModeQueue       = "queue"
+	ModeHistory     = "history"
+	ModeConfig      = "config"
+	ModeGetConfig   = "get_config"
+	ModeAddURL      = "addurl"
+	ModeAddFile     = "addfile"
+	ModeVersion     = "version"
+	ModePause       = "pause"
+	ModeResume      = "resume"
+	ModeDelete      = "delete"
+	ModeShutdown    = "shutdown"
+	ModeRestart     = "restart"
+	ModeGetCats     = "get_cats"
+	ModeGetScripts  = "get_scripts"
+	ModeGetFiles    = "get_files"
+	ModeRetry       = "retry"
+	ModeStatus      = "status"
+	ModeFullStatus  = "fullstatus"
+	ModeSwitch      = "switch"
+	ModeChangeCat   = "change_cat"
+	ModeChangeOpts  = "change_opts"
+	ModeServerStats = "server_stats"
+	ModeWarnings    = "warnings"
 )
 
 // Status constants
@@ -164,8 +207,26 @@ const (
 	PriorityNormal = "0"
 	PriorityLow    = "-1"
 	PriorityStop   = "-2"
+
+	// PriorityDefault asks for the category's priority
+	PriorityDefault = "-100"
 )
 
+// parsePriority maps a SABnzbd priority onto the queue's scale. The default
+// priority becomes normal, as every category here uses normal. Paused (-2)
+// holds the job in the queue until its priority is changed.
+func parsePriority(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == PriorityDefault {
+		return 0, nil
+	}
+	priority, err := strconv.Atoi(value)
+	if err != nil || priority < -2 || priority > 2 {
+		return 0, fmt.Errorf("invalid priority %q", value)
+	}
+	return priority, nil
+}
+
 // NZB represents an NZB download in SABnzbd format (similar to qbit's Torrent)
 type NZB struct {
 	NzoId        string   `json:"nzo_id"`        // Unique NZB identifier
@@ -231,6 +292,9 @@ func convertToSABnzbdNZB(e *storage.Entry) NZB {
 	status := mapStorageStateToSABStatus(e.State)
 	if e.Status == debridTypes.TorrentStatusQueued {
 		status = StatusQueued
+		if e.Priority <= manager.PriorityPaused {
+			status = StatusPaused
+		}
 	}
 
 	var completedOn int64
@@ -249,7 +313,7 @@ func convertToSABnzbdNZB(e *storage.Entry) NZB {
 		TimeLeft:     timeLeft,
 		Status:       status,
 		Category:     e.Category,
-		Priority:     PriorityNormal,
+		Priority:     strconv.Itoa(e.Priority),
 		SavePath:     e.SavePath,
 		ContentPath:  e.DownloadPath(),
 		Script:       "None",
@@ -284,7 +348,7 @@ func getNZBFiles(e *storage.Entry) []File {
 	}
 	idx := 0
 	for _, f := range e.Files {
-		if f.Deleted {
+		if !f.Active() {
 			continue
 		}
 
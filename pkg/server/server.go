@@ -20,8 +20,10 @@ import (
 	"github.com/sirrobot01/decypharr/internal/config"
 	"github.com/sirrobot01/decypharr/internal/logger"
 	"github.com/sirrobot01/decypharr/pkg/manager"
+	"github.com/sirrobot01/decypharr/pkg/server/nzbget"
 	"github.com/sirrobot01/decypharr/pkg/server/qbit"
 	"github.com/sirrobot01/decypharr/pkg/server/sabnzbd"
+	"github.com/sirrobot01/decypharr/pkg/server/transmission"
 	"github.com/sirrobot01/decypharr/pkg/server/webdav"
 	"github.com/sirrobot01/decypharr/pkg/stats"
 )
@@ -112,6 +114,8 @@ func New(mgr *manager.Manager) *Server {
 
 	qb := qbit.New(mgr)
 	sb := sabnzbd.New(mgr)
+	ng := nzbget.New(mgr)
+	tr := transmission.New(mgr)
 	wd := webdav.NewHandler(mgr)
 
 	routes := make(map[string]http.Handler)
@@ -121,6 +125,8 @@ func New(mgr *manager.Manager) *Server {
 		routes["/webdav"] = wd.Routes()
 	}
 	routes["/sabnzbd"] = sb.Routes()
+	routes["/nzbget"] = ng.Routes()
+	routes["/transmission"] = tr.Routes()
 
 	// Trim trailing slash so chi registers the URLBase root path itself
 	routePath := cfg.URLBase
@@ -143,6 +149,7 @@ func New(mgr *manager.Manager) *Server {
 
 			r.Route("/debug", func(r chi.Router) {
 				r.Get("/stats", s.stats.Handler())
+				r.Get("/health", s.handleHealthReport)
 				r.Post("/speedtest", s.handleSpeedTest)
 				r.Get("/logs", s.getLogs)
 				r.Get("/logs/rclone", s.getRcloneLogs)
@@ -153,6 +160,10 @@ func New(mgr *manager.Manager) *Server {
 
 		//webhooks
 		r.Post("/webhooks/tautulli", s.handleTautulli)
+
+		// Probes, left outside auth for orchestrators
+		r.Get("/healthz", s.handleHealthz)
+		r.Get("/readyz", s.handleReadyz)
 	})
 	s.router = r
 	return s
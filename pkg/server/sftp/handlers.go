@@ -0,0 +1,196 @@
+package sftp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	gosftp "github.com/pkg/sftp"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+)
+
+// handlers maps SFTP requests onto the same tree WebDAV serves:
+// /{group}/{entry}/{file}, plus the root files such as version.txt.
+// Anything that would change the tree is refused.
+type handlers struct {
+	ctx    context.Context
+	server *Server
+}
+
+func newHandlers(ctx context.Context, s *Server) gosftp.Handlers {
+	h := &handlers{ctx: ctx, server: s}
+	return gosftp.Handlers{
+		FileGet:  h,
+		FilePut:  h,
+		FileCmd:  h,
+		FileList: h,
+	}
+}
+
+func (h *handlers) Fileread(r *gosftp.Request) (io.ReaderAt, error) {
+	info := h.lookup(r.Filepath)
+	if info == nil {
+		return nil, os.ErrNotExist
+	}
+	if info.IsDir() {
+		return nil, gosftp.ErrSSHFxFailure
+	}
+	if !info.IsRemote() {
+		return bytes.NewReader(info.Content()), nil
+	}
+	entry, err := h.server.manager.GetEntryByName(info.Parent(), info.Name())
+	if err != nil || entry == nil {
+		return nil, os.ErrNotExist
+	}
+	return newStreamReader(h.ctx, h.server.manager, entry, info.Name(), info.Size()), nil
+}
+
+func (h *handlers) Filewrite(*gosftp.Request) (io.WriterAt, error) {
+	return nil, gosftp.ErrSSHFxPermissionDenied
+}
+
+func (h *handlers) Filecmd(*gosftp.Request) error {
+	return gosftp.ErrSSHFxPermissionDenied
+}
+
+func (h *handlers) Filelist(r *gosftp.Request) (gosftp.ListerAt, error) {
+	switch r.Method {
+	case "List":
+		children, ok := h.list(r.Filepath)
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		infos := make(listerAt, 0, len(children))
+		for i := range children {
+			infos = append(infos, fileInfo{&children[i]})
+		}
+		return infos, nil
+	case "Stat":
+		info := h.lookup(r.Filepath)
+		if info == nil {
+			return nil, os.ErrNotExist
+		}
+		return listerAt{fileInfo{info}}, nil
+	default:
+		return nil, gosftp.ErrSSHFxOpUnsupported
+	}
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// lookup resolves a path to its FileInfo, or nil when it doesn't exist
+func (h *handlers) lookup(p string) *manager.FileInfo {
+	mgr := h.server.manager
+	parts := splitPath(p)
+	switch len(parts) {
+	case 0:
+		return mgr.RootInfo()
+	case 1:
+		for _, child := range mgr.GetEntries() {
+			if child.Name() != parts[0] {
+				continue
+			}
+			if !child.IsDir() {
+				return &child
+			}
+			current, _ := mgr.GetEntryChildren(parts[0])
+			return current
+		}
+		return nil
+	case 2:
+		if !h.inGroup(parts[0], parts[1]) {
+			return nil
+		}
+		current, _ := mgr.GetTorrentChildren(parts[1])
+		return current
+	case 3:
+		if !h.inGroup(parts[0], parts[1]) {
+			return nil
+		}
+		info, err := mgr.GetTorrentFile(parts[1], parts[2])
+		if err != nil {
+			return nil
+		}
+		return info
+	default:
+		return nil
+	}
+}
+
+// list returns a directory's children, and false when it isn't a directory
+func (h *handlers) list(p string) ([]manager.FileInfo, bool) {
+	mgr := h.server.manager
+	parts := splitPath(p)
+	switch len(parts) {
+	case 0:
+		return mgr.GetEntries(), true
+	case 1:
+		if info := h.lookup(p); info == nil || !info.IsDir() {
+			return nil, false
+		}
+		_, children := mgr.GetEntryChildren(parts[0])
+		return children, true
+	case 2:
+		if !h.inGroup(parts[0], parts[1]) {
+			return nil, false
+		}
+		current, children := mgr.GetTorrentChildren(parts[1])
+		return children, current != nil
+	default:
+		return nil, false
+	}
+}
+
+// inGroup reports whether the torrent folder is listed under the group, so a
+// torrent can't be reached through a group it isn't in
+func (h *handlers) inGroup(group, torrent string) bool {
+	_, children := h.server.manager.GetEntryChildren(group)
+	for _, child := range children {
+		if child.Name() == torrent {
+			return true
+		}
+	}
+	return false
+}
+
+// fileInfo gives directories the mode bits SFTP clients look for; the
+// library's FileInfo only sets IsDir.
+type fileInfo struct {
+	*manager.FileInfo
+}
+
+func (f fileInfo) Name() string {
+	if f.FileInfo.Name() == "" {
+		return "/"
+	}
+	return f.FileInfo.Name()
+}
+
+func (f fileInfo) Mode() os.FileMode {
+	if f.IsDir() {
+		return os.ModeDir | 0555
+	}
+	return 0444
+}
+
+type listerAt []os.FileInfo
+
+func (l listerAt) ListAt(dst []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+	n := copy(dst, l[offset:])
+	if n < len(dst) {
+		return n, io.EOF
+	}
+	return n, nil
+}
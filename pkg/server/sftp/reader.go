@@ -0,0 +1,156 @@
+package sftp
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/sirrobot01/decypharr/pkg/manager"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+const (
+	// maxSkip is how far ahead of the stream a read may land before the
+	// stream is restarted at the read's offset instead of read through
+	maxSkip = 4 << 20
+
+	// keepBehind is how many already-streamed bytes are kept to answer reads
+	// that arrive after the stream has moved past them
+	keepBehind = 4 << 20
+
+	streamClient = "SFTP"
+)
+
+// streamSource is the part of the manager a streamReader reads through
+type streamSource interface {
+	Stream(ctx context.Context, entry *storage.Entry, filename string, start, end int64, writer io.Writer, onReady manager.StreamReadyFunc, client string) error
+	TrackStream(entry *storage.Entry, filename, client string) string
+	UntrackStream(streamID string)
+}
+
+// streamReader serves an open file's reads from a single Manager.Stream that
+// runs forward from the last offset. Clients pipeline their reads and the
+// request server answers them from several workers, so reads arrive slightly
+// out of order; small gaps are read through and recent bytes are kept, and
+// only a real seek restarts the stream.
+type streamReader struct {
+	ctx      context.Context
+	manager  streamSource
+	entry    *storage.Entry
+	filename string
+	size     int64
+
+	mu       sync.Mutex
+	pr       *io.PipeReader
+	cancel   context.CancelFunc
+	pos      int64  // offset of the next byte from pr
+	behind   []byte // the bytes just before pos
+	streamID string
+}
+
+func newStreamReader(ctx context.Context, mgr streamSource, entry *storage.Entry, filename string, size int64) *streamReader {
+	return &streamReader{
+		ctx:      ctx,
+		manager:  mgr,
+		entry:    entry,
+		filename: filename,
+		size:     size,
+	}
+}
+
+func (r *streamReader) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	want := p
+	if rest := r.size - off; int64(len(want)) > rest {
+		want = want[:rest]
+	}
+
+	n := 0
+	if start := r.pos - int64(len(r.behind)); off >= start && off < r.pos {
+		n = copy(want, r.behind[off-start:])
+	}
+	for n < len(want) {
+		cur := off + int64(n)
+		if r.pr == nil || cur < r.pos || cur > r.pos+maxSkip {
+			r.open(cur)
+		}
+		if gap := cur - r.pos; gap > 0 {
+			if _, err := r.read(make([]byte, gap)); err != nil {
+				return n, err
+			}
+		}
+		m, err := r.read(want[n:])
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// read fills buf from the stream and remembers what it read
+func (r *streamReader) read(buf []byte) (int, error) {
+	n, err := io.ReadFull(r.pr, buf)
+	r.pos += int64(n)
+	r.behind = append(r.behind, buf[:n]...)
+	if extra := len(r.behind) - keepBehind; extra > 0 {
+		r.behind = r.behind[extra:]
+	}
+	if err != nil {
+		r.stop()
+		// The stream ending at the end of the file is a plain EOF; ending
+		// before it means the file was cut short
+		switch {
+		case err == io.ErrUnexpectedEOF && r.pos >= r.size:
+			err = io.EOF
+		case err == io.EOF && r.pos < r.size:
+			err = io.ErrUnexpectedEOF
+		}
+	}
+	return n, err
+}
+
+// open (re)starts the stream at off
+func (r *streamReader) open(off int64) {
+	r.stop()
+	if r.streamID == "" {
+		r.streamID = r.manager.TrackStream(r.entry, r.filename, streamClient)
+	}
+	ctx, cancel := context.WithCancel(r.ctx)
+	pr, pw := io.Pipe()
+	r.pr, r.cancel, r.pos, r.behind = pr, cancel, off, nil
+	go func() {
+		err := r.manager.Stream(ctx, r.entry, r.filename, off, r.size-1, pw, nil, streamClient)
+		_ = pw.CloseWithError(err)
+	}()
+}
+
+func (r *streamReader) stop() {
+	if r.pr == nil {
+		return
+	}
+	r.cancel()
+	_ = r.pr.Close()
+	r.pr, r.cancel = nil, nil
+}
+
+// Close is called by the request server when the client closes the handle
+func (r *streamReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stop()
+	r.behind = nil
+	if r.streamID != "" {
+		r.manager.UntrackStream(r.streamID)
+		r.streamID = ""
+	}
+	return nil
+}
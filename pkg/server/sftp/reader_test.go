@@ -0,0 +1,133 @@
+package sftp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/sirrobot01/decypharr/pkg/manager"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// fakeSource streams content in small chunks and records where each stream
+// started
+type fakeSource struct {
+	content []byte
+
+	mu     sync.Mutex
+	starts []int64
+}
+
+func (f *fakeSource) Stream(ctx context.Context, _ *storage.Entry, _ string, start, end int64, w io.Writer, _ manager.StreamReadyFunc, _ string) error {
+	f.mu.Lock()
+	f.starts = append(f.starts, start)
+	f.mu.Unlock()
+	for off := start; off <= end; off += 32 << 10 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := w.Write(f.content[off:min(off+32<<10, end+1)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeSource) TrackStream(*storage.Entry, string, string) string { return "stream" }
+func (f *fakeSource) UntrackStream(string)                              {}
+
+func (f *fakeSource) opened() []int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.starts)
+}
+
+func TestStreamReaderReadAt(t *testing.T) {
+	const size = 16 << 20
+	content := make([]byte, size)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+
+	// A read as offset and length
+	type read struct{ off, n int64 }
+	tests := []struct {
+		name       string
+		reads      []read
+		wantStarts []int64
+	}{
+		{
+			name:       "sequential",
+			reads:      []read{{0, 32 << 10}, {32 << 10, 32 << 10}, {64 << 10, 32 << 10}},
+			wantStarts: []int64{0},
+		},
+		{
+			name:       "reordered reads are read through and kept",
+			reads:      []read{{0, 32 << 10}, {64 << 10, 32 << 10}, {32 << 10, 32 << 10}, {96 << 10, 32 << 10}},
+			wantStarts: []int64{0},
+		},
+		{
+			name:       "backward read inside keepBehind",
+			reads:      []read{{0, 1 << 20}, {1 << 20, 1 << 20}, {512 << 10, 64 << 10}, {2 << 20, 32 << 10}},
+			wantStarts: []int64{0},
+		},
+		{
+			name:       "read straddling kept and new bytes",
+			reads:      []read{{0, 64 << 10}, {32 << 10, 64 << 10}},
+			wantStarts: []int64{0},
+		},
+		{
+			name:       "backward read past keepBehind restarts",
+			reads:      []read{{0, 6 << 20}, {0, 32 << 10}},
+			wantStarts: []int64{0, 0},
+		},
+		{
+			name:       "seek beyond maxSkip restarts",
+			reads:      []read{{0, 32 << 10}, {10 << 20, 32 << 10}, {(10 << 20) + (32 << 10), 32 << 10}},
+			wantStarts: []int64{0, 10 << 20},
+		},
+		{
+			name:       "skip within maxSkip is read through",
+			reads:      []read{{0, 32 << 10}, {3 << 20, 32 << 10}},
+			wantStarts: []int64{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &fakeSource{content: content}
+			r := newStreamReader(context.Background(), src, &storage.Entry{}, "movie.mkv", size)
+			defer r.Close()
+			for _, rd := range tt.reads {
+				buf := make([]byte, rd.n)
+				n, err := r.ReadAt(buf, rd.off)
+				if err != nil || int64(n) != rd.n {
+					t.Fatalf("ReadAt(%d, %d) = %d, %v", rd.off, rd.n, n, err)
+				}
+				if !bytes.Equal(buf, content[rd.off:rd.off+rd.n]) {
+					t.Fatalf("ReadAt(%d, %d) returned the wrong bytes", rd.off, rd.n)
+				}
+			}
+			if got := src.opened(); !slices.Equal(got, tt.wantStarts) {
+				t.Errorf("streams started at %v, want %v", got, tt.wantStarts)
+			}
+		})
+	}
+}
+
+func TestStreamReaderEOF(t *testing.T) {
+	content := []byte("0123456789")
+	r := newStreamReader(context.Background(), &fakeSource{content: content}, &storage.Entry{}, "a.txt", int64(len(content)))
+	defer r.Close()
+
+	buf := make([]byte, 8)
+	n, err := r.ReadAt(buf, 6)
+	if n != 4 || err != io.EOF || string(buf[:n]) != "6789" {
+		t.Errorf("read over the end = %d %q, %v; want 4 \"6789\", EOF", n, buf[:n], err)
+	}
+	if n, err := r.ReadAt(buf, 10); n != 0 || err != io.EOF {
+		t.Errorf("read at the end = %d, %v; want 0, EOF", n, err)
+	}
+}
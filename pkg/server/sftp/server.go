@@ -0,0 +1,231 @@
+package sftp
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	gosftp "github.com/pkg/sftp"
+	"github.com/rs/zerolog"
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/logger"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+	"golang.org/x/crypto/ssh"
+)
+
+const defaultPort = "2222"
+
+var errAuthFailed = errors.New("authentication failed")
+
+// Server serves the virtual library read-only over SFTP
+type Server struct {
+	logger  zerolog.Logger
+	manager *manager.Manager
+}
+
+func New(mgr *manager.Manager) *Server {
+	return &Server{
+		logger:  logger.New("sftp"),
+		manager: mgr,
+	}
+}
+
+// Start listens until ctx is cancelled. A listener or host key failure is
+// logged rather than returned so it doesn't take the other services down.
+func (s *Server) Start(ctx context.Context) error {
+	cfg := config.Get()
+	hostKeyPath := cmp.Or(cfg.SFTP.HostKey, filepath.Join(config.GetMainPath(), "sftp_host_key"))
+	signer, err := loadHostKey(hostKeyPath)
+	if err != nil {
+		s.logger.Error().Err(err).Str("path", hostKeyPath).Msg("Failed to load SFTP host key")
+		return nil
+	}
+
+	sshConfig := &ssh.ServerConfig{
+		PasswordCallback:     s.passwordAuth,
+		PublicKeyCallback:    s.publicKeyAuth,
+		NoClientAuth:         true,
+		NoClientAuthCallback: s.noAuth,
+	}
+	sshConfig.AddHostKey(signer)
+
+	addr := net.JoinHostPort(cfg.BindAddress, cmp.Or(cfg.SFTP.Port, defaultPort))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		s.logger.Error().Err(err).Msgf("Error starting SFTP server on %s", addr)
+		return nil
+	}
+	s.logger.Info().Msgf("Starting SFTP server on %s", addr)
+
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			s.logger.Warn().Err(err).Msg("Failed to accept SFTP connection")
+			continue
+		}
+		go s.serveConn(ctx, conn, sshConfig)
+	}
+}
+
+func (s *Server) serveConn(ctx context.Context, conn net.Conn, sshConfig *ssh.ServerConfig) {
+	sconn, chans, reqs, err := ssh.NewServerConn(conn, sshConfig)
+	if err != nil {
+		s.logger.Debug().Err(err).Str("remote", conn.RemoteAddr().String()).Msg("SSH handshake failed")
+		_ = conn.Close()
+		return
+	}
+	defer sconn.Close()
+	go ssh.DiscardRequests(reqs)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = sconn.Close()
+		case <-done:
+		}
+	}()
+
+	s.logger.Debug().Str("user", sconn.User()).Str("remote", sconn.RemoteAddr().String()).Msg("SFTP client connected")
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			s.logger.Debug().Err(err).Msg("Failed to accept SSH channel")
+			continue
+		}
+		go s.serveSession(ctx, channel, requests)
+	}
+}
+
+// serveSession waits for the sftp subsystem request and runs the request
+// server on the channel. Shells, exec and other subsystems are refused.
+func (s *Server) serveSession(ctx context.Context, channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer channel.Close()
+	for req := range requests {
+		if req.Type != "subsystem" || !isSFTPSubsystem(req.Payload) {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		_ = req.Reply(true, nil)
+		go ssh.DiscardRequests(requests)
+
+		select {
+		case <-s.manager.IsReady():
+		case <-ctx.Done():
+			return
+		}
+
+		server := gosftp.NewRequestServer(channel, newHandlers(ctx, s))
+		if err := server.Serve(); err != nil && !errors.Is(err, os.ErrClosed) {
+			s.logger.Debug().Err(err).Msg("SFTP session ended")
+		}
+		_ = server.Close()
+		return
+	}
+}
+
+func isSFTPSubsystem(payload []byte) bool {
+	if len(payload) < 4 {
+		return false
+	}
+	n := binary.BigEndian.Uint32(payload)
+	return int(n) == len(payload)-4 && string(payload[4:]) == "sftp"
+}
+
+// Authentication follows the app: with auth off anyone may connect, with it on
+// a login needs the app credentials, the API token as the password, or a key
+// from the authorized keys file. All read live so changes apply without a
+// restart.
+
+func (s *Server) noAuth(ssh.ConnMetadata) (*ssh.Permissions, error) {
+	if !config.Get().UseAuth {
+		return nil, nil
+	}
+	return nil, errAuthFailed
+}
+
+func (s *Server) passwordAuth(meta ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+	cfg := config.Get()
+	if !cfg.UseAuth || config.VerifyAuth(meta.User(), string(password)) {
+		return nil, nil
+	}
+	if auth := cfg.GetAuth(); auth != nil && auth.APIToken != "" &&
+		subtle.ConstantTimeCompare(password, []byte(auth.APIToken)) == 1 {
+		return nil, nil
+	}
+	s.logger.Warn().Str("user", meta.User()).Str("remote", meta.RemoteAddr().String()).Msg("SFTP password login failed")
+	return nil, errAuthFailed
+}
+
+func (s *Server) publicKeyAuth(meta ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+	cfg := config.Get()
+	if !cfg.UseAuth {
+		return nil, nil
+	}
+	path := cmp.Or(cfg.SFTP.AuthorizedKeys, filepath.Join(config.GetMainPath(), "sftp_authorized_keys"))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errAuthFailed
+	}
+	want := key.Marshal()
+	for len(data) > 0 {
+		authorized, _, _, rest, err := ssh.ParseAuthorizedKey(data)
+		if err != nil {
+			break
+		}
+		if bytes.Equal(authorized.Marshal(), want) {
+			return nil, nil
+		}
+		data = rest
+	}
+	return nil, errAuthFailed
+}
+
+// loadHostKey reads the server's private key, generating an ed25519 key on
+// first start.
+func loadHostKey(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		_, key, genErr := ed25519.GenerateKey(rand.Reader)
+		if genErr != nil {
+			return nil, genErr
+		}
+		block, genErr := ssh.MarshalPrivateKey(key, "decypharr")
+		if genErr != nil {
+			return nil, genErr
+		}
+		data = pem.EncodeToMemory(block)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to save host key: %w", err)
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	return ssh.ParsePrivateKey(data)
+}
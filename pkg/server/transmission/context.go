@@ -0,0 +1,19 @@
+package transmission
+
+import "net/http"
+
+const sessionIDHeader = "X-Transmission-Session-Id"
+
+// sessionContext does Transmission's CSRF handshake: a request without the
+// current session id is answered with 409 and the id in the response header,
+// and the client retries with it.
+func (t *Transmission) sessionContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(sessionIDHeader) != t.sessionID {
+			w.Header().Set(sessionIDHeader, t.sessionID)
+			http.Error(w, "Your request had an invalid session-id header.", http.StatusConflict)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
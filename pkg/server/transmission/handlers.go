@@ -0,0 +1,517 @@
+package transmission
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/utils"
+	"github.com/sirrobot01/decypharr/pkg/arr"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// recentlyActive is how far back the "recently-active" id selector looks
+const recentlyActive = time.Minute
+
+// handleRPC serves Transmission's RPC endpoint. Errors are reported in the
+// response's result, as Transmission does; only authentication fails with an
+// HTTP status.
+func (t *Transmission) handleRPC(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	var add AddArguments
+	if req.Method == "torrent-add" {
+		if err := decodeArguments(req.Arguments, &add); err != nil {
+			utils.JSONResponse(w, Response{Result: err.Error(), Tag: req.Tag}, http.StatusOK)
+			return
+		}
+	}
+	username, password, _ := r.BasicAuth()
+	a, err := t.manager.Arr().Authenticate(t.categoryFromAdd(add.DownloadDir, add.Labels), username, password)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Basic realm="Transmission"`)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var arguments any
+	switch req.Method {
+	case "torrent-add":
+		arguments, err = t.addTorrent(r.Context(), a, add)
+	case "torrent-get":
+		arguments, err = t.getTorrents(req.Arguments)
+	case "torrent-remove":
+		arguments, err = t.removeTorrents(req.Arguments)
+	case "torrent-set":
+		arguments, err = t.setTorrents(req.Arguments)
+	case "torrent-set-location":
+		arguments, err = t.setLocation(req.Arguments)
+	case "torrent-start", "torrent-start-now":
+		arguments, err = t.startTorrents(req.Arguments, req.Method == "torrent-start-now")
+	case "torrent-stop":
+		arguments, err = t.stopTorrents(req.Arguments)
+	case "torrent-verify", "torrent-reannounce":
+		// Debrid torrents cannot be rechecked
+		arguments = struct{}{}
+	case "queue-move-top":
+		arguments, err = t.moveQueue(req.Arguments, func(int) int { return 0 })
+	case "queue-move-up":
+		arguments, err = t.moveQueue(req.Arguments, func(p int) int { return p - 1 })
+	case "queue-move-down":
+		arguments, err = t.moveQueue(req.Arguments, func(p int) int { return p + 1 })
+	case "queue-move-bottom":
+		arguments, err = t.moveQueue(req.Arguments, func(int) int { return math.MaxInt })
+	case "session-get":
+		arguments, err = t.sessionGet(req.Arguments)
+	case "session-stats":
+		arguments = t.sessionStats()
+	default:
+		err = fmt.Errorf("method name not recognized")
+	}
+
+	response := Response{Arguments: arguments, Result: "success", Tag: req.Tag}
+	if err != nil {
+		t.logger.Debug().Err(err).Str("method", req.Method).Msg("RPC call failed")
+		response = Response{Arguments: struct{}{}, Result: err.Error(), Tag: req.Tag}
+	}
+	utils.JSONResponse(w, response, http.StatusOK)
+}
+
+func decodeArguments(raw json.RawMessage, v any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// categoryFromAdd works out the category of a new torrent. Labels win; the
+// Arrs add their category as a label from Transmission 4 on. Otherwise the
+// category is the folder under the download folder the torrent is sent to,
+// which is how the Arrs pass it to older versions.
+func (t *Transmission) categoryFromAdd(downloadDir string, labels []string) string {
+	for _, label := range labels {
+		if label = strings.TrimSpace(label); label != "" {
+			return label
+		}
+	}
+	downloadDir = strings.TrimSpace(downloadDir)
+	if downloadDir == "" {
+		return ""
+	}
+	downloadDir = filepath.Clean(downloadDir)
+	if rel, err := filepath.Rel(t.downloadFolder, downloadDir); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		category, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+		return category
+	}
+	if downloadDir == filepath.Clean(t.downloadFolder) {
+		return ""
+	}
+	return filepath.Base(downloadDir)
+}
+
+func (t *Transmission) addTorrent(ctx context.Context, a *arr.Arr, args AddArguments) (any, error) {
+	rmTrackerUrls := t.alwaysRemoveTrackerURLS
+
+	var magnet *utils.Magnet
+	var err error
+	switch {
+	case args.Metainfo != "":
+		data, decodeErr := base64.StdEncoding.DecodeString(strings.TrimSpace(args.Metainfo))
+		if decodeErr != nil {
+			return nil, fmt.Errorf("invalid or corrupt torrent file")
+		}
+		magnet, err = utils.GetMagnetFromBytes(data, rmTrackerUrls)
+	case args.Filename != "":
+		magnet, err = utils.GetMagnetFromUrl(strings.TrimSpace(args.Filename), rmTrackerUrls)
+	default:
+		return nil, fmt.Errorf("no filename or metainfo specified")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid or corrupt torrent file: %w", err)
+	}
+
+	if existing, err := t.manager.Queue().GetTorrent(magnet.InfoHash); err == nil && existing != nil {
+		return map[string]any{"torrent-duplicate": newAddedTorrent(existing.InfoHash, existing.Name)}, nil
+	}
+
+	priority := max(min(args.BandwidthPriority, 1), -1)
+	if args.Paused {
+		// The torrent stays queued until torrent-start
+		priority = manager.PriorityPaused
+	}
+
+	cfg := config.Get()
+	policy := cfg.PolicyFor(a.Name)
+	req := manager.NewTorrentRequest("", cmp.Or(policy.DownloadFolder, t.downloadFolder), magnet, a, policy, cmp.Or(policy.DownloadUncached, a.DownloadUncached), cfg.Notifications.CallbackURL, manager.ImportTypeTransmission)
+	req.Priority = priority
+	if err := t.manager.AddNewTorrent(ctx, req); err != nil {
+		return nil, fmt.Errorf("failed to process torrent: %w", err)
+	}
+	return map[string]any{"torrent-added": newAddedTorrent(magnet.InfoHash, magnet.Name)}, nil
+}
+
+func newAddedTorrent(infohash, name string) AddedTorrent {
+	return AddedTorrent{ID: torrentID(infohash), Name: name, HashString: strings.ToLower(infohash)}
+}
+
+// resolveIDs returns the torrents an ids argument selects: nothing for all
+// torrents, a single id or hash, a list of ids and hashes, or
+// "recently-active". An id shared by several torrents selects none of them.
+func (t *Transmission) resolveIDs(raw json.RawMessage) ([]*storage.Entry, error) {
+	entries := t.manager.Queue().ListFilter("", config.ProtocolTorrent, "", nil, "added_on", false)
+	if len(raw) == 0 || string(raw) == "null" {
+		return entries, nil
+	}
+
+	var selector string
+	if err := json.Unmarshal(raw, &selector); err == nil && selector == "recently-active" {
+		cutoff := time.Now().Add(-recentlyActive)
+		return slices.DeleteFunc(entries, func(e *storage.Entry) bool {
+			return e.UpdatedAt.Before(cutoff)
+		}), nil
+	}
+
+	var ids []any
+	if err := json.Unmarshal(raw, &ids); err != nil {
+		var single any
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return nil, fmt.Errorf("invalid ids")
+		}
+		ids = []any{single}
+	}
+
+	byID := indexByTorrentID(entries)
+	byHash := make(map[string]*storage.Entry, len(entries))
+	for _, e := range entries {
+		byHash[strings.ToLower(e.InfoHash)] = e
+	}
+	var selected []*storage.Entry
+	for _, id := range ids {
+		var e *storage.Entry
+		switch id := id.(type) {
+		case float64:
+			if e = byID[int(id)]; e == nil {
+				t.logger.Warn().Int("id", int(id)).Msg("Torrent id is unknown or shared by several torrents, ignoring it")
+			}
+		case string:
+			e = byHash[strings.ToLower(strings.TrimSpace(id))]
+		}
+		if e != nil && !slices.Contains(selected, e) {
+			selected = append(selected, e)
+		}
+	}
+	return selected, nil
+}
+
+// indexByTorrentID maps each torrent id to its entry. Ids are hashes, so two
+// entries can share one; such an id maps to nil, as it can't name either.
+func indexByTorrentID(entries []*storage.Entry) map[int]*storage.Entry {
+	byID := make(map[int]*storage.Entry, len(entries))
+	for _, e := range entries {
+		id := torrentID(e.InfoHash)
+		if _, taken := byID[id]; taken {
+			byID[id] = nil
+			continue
+		}
+		byID[id] = e
+	}
+	return byID
+}
+
+func (t *Transmission) getTorrents(raw json.RawMessage) (any, error) {
+	var args GetArguments
+	if err := decodeArguments(raw, &args); err != nil {
+		return nil, err
+	}
+	entries, err := t.resolveIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	positions := t.manager.QueuePositions()
+	torrents := make([]map[string]any, 0, len(entries))
+	for _, e := range entries {
+		position, waiting := positions[e.InfoHash]
+		if !waiting {
+			position = -1
+		}
+		torrents = append(torrents, selectFields(torrentFields(e, position), args.Fields))
+	}
+	result := map[string]any{"torrents": torrents}
+	if string(args.IDs) == `"recently-active"` {
+		// Removals are not tracked; clients fall back to a full refresh
+		result["removed"] = []int{}
+	}
+	return result, nil
+}
+
+// removeTorrents removes torrents from the queue. Files are cleaned up the
+// same way whether or not delete-local-data is set, as with the qBittorrent
+// API.
+func (t *Transmission) removeTorrents(raw json.RawMessage) (any, error) {
+	var args RemoveArguments
+	if err := decodeArguments(raw, &args); err != nil {
+		return nil, err
+	}
+	if len(args.IDs) == 0 {
+		return nil, fmt.Errorf("no torrents specified")
+	}
+	entries, err := t.resolveIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if err := t.manager.Queue().Delete(e.InfoHash, nil); err != nil && !strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+	}
+	return struct{}{}, nil
+}
+
+// setTorrents applies torrent-set. The first label becomes the category and
+// the rest the tags; bandwidthPriority and queuePosition reorder the queue.
+func (t *Transmission) setTorrents(raw json.RawMessage) (any, error) {
+	var args SetArguments
+	if err := decodeArguments(raw, &args); err != nil {
+		return nil, err
+	}
+	entries, err := t.resolveIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, e := range entries {
+		if args.Labels != nil {
+			errs = append(errs, t.setLabels(e, *args.Labels))
+		}
+		if args.BandwidthPriority != nil {
+			_, err := t.manager.SetEntryPriority(e.InfoHash, max(min(*args.BandwidthPriority, 1), -1))
+			errs = append(errs, err)
+		}
+		if args.QueuePosition != nil {
+			if _, waiting := t.manager.QueuePositions()[e.InfoHash]; waiting {
+				_, _, err := t.manager.MoveEntry(e.InfoHash, max(*args.QueuePosition, 0))
+				errs = append(errs, err)
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return struct{}{}, nil
+}
+
+func (t *Transmission) setLabels(e *storage.Entry, labels []string) error {
+	var tags []string
+	category := ""
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		switch {
+		case label == "":
+		case category == "":
+			category = label
+		case !slices.Contains(tags, label):
+			tags = append(tags, label)
+		}
+	}
+	if category != "" && category != e.Category {
+		if err := t.manager.SetEntryCategory(e.InfoHash, category); err != nil {
+			return err
+		}
+	}
+	entry, err := t.manager.Queue().GetTorrent(e.InfoHash)
+	if err != nil {
+		return err
+	}
+	entry.Tags = tags
+	return t.manager.Queue().Update(entry)
+}
+
+func (t *Transmission) setLocation(raw json.RawMessage) (any, error) {
+	var args SetLocationArguments
+	if err := decodeArguments(raw, &args); err != nil {
+		return nil, err
+	}
+	location := strings.TrimSpace(args.Location)
+	if location == "" {
+		return nil, fmt.Errorf("no location specified")
+	}
+	entries, err := t.resolveIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if err := t.manager.SetEntryLocation(e.InfoHash, location); err != nil {
+			return nil, err
+		}
+	}
+	return struct{}{}, nil
+}
+
+// moveQueue moves torrents among those waiting for an active-download slot;
+// target maps a torrent's position to its new one. Torrents that already hold
+// a slot are left alone.
+func (t *Transmission) moveQueue(raw json.RawMessage, target func(position int) int) (any, error) {
+	var args GetArguments
+	if err := decodeArguments(raw, &args); err != nil {
+		return nil, err
+	}
+	entries, err := t.resolveIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		position, ok := t.manager.QueuePositions()[e.InfoHash]
+		if !ok {
+			continue
+		}
+		if _, _, err := t.manager.MoveEntry(e.InfoHash, max(target(position), 0)); err != nil {
+			t.logger.Warn().Err(err).Str("hash", e.InfoHash).Msg("Failed to move torrent in queue")
+		}
+	}
+	return struct{}{}, nil
+}
+
+// startTorrents releases paused torrents at normal priority. With now, they
+// also move to the front of the torrents waiting for a slot.
+func (t *Transmission) startTorrents(raw json.RawMessage, now bool) (any, error) {
+	var args GetArguments
+	if err := decodeArguments(raw, &args); err != nil {
+		return nil, err
+	}
+	entries, err := t.resolveIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, e := range entries {
+		if e.Priority <= manager.PriorityPaused {
+			_, err := t.manager.SetEntryPriority(e.InfoHash, 0)
+			errs = append(errs, err)
+		}
+		if now {
+			if _, waiting := t.manager.QueuePositions()[e.InfoHash]; waiting {
+				_, _, err := t.manager.MoveEntry(e.InfoHash, 0)
+				errs = append(errs, err)
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return struct{}{}, nil
+}
+
+// stopTorrents holds back torrents waiting for a slot. Torrents that already
+// hold one are left alone, as debrid downloads cannot be paused.
+func (t *Transmission) stopTorrents(raw json.RawMessage) (any, error) {
+	var args GetArguments
+	if err := decodeArguments(raw, &args); err != nil {
+		return nil, err
+	}
+	entries, err := t.resolveIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	positions := t.manager.QueuePositions()
+	var errs []error
+	for _, e := range entries {
+		if _, waiting := positions[e.InfoHash]; !waiting {
+			continue
+		}
+		_, err := t.manager.SetEntryPriority(e.InfoHash, manager.PriorityPaused)
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return struct{}{}, nil
+}
+
+func (t *Transmission) sessionGet(raw json.RawMessage) (any, error) {
+	var args GetArguments
+	if err := decodeArguments(raw, &args); err != nil {
+		return nil, err
+	}
+	session := map[string]any{
+		"version":                    fmt.Sprintf("%s (decypharr)", Version),
+		"rpc-version":                RPCVersion,
+		"rpc-version-minimum":        RPCVersionMinimum,
+		"rpc-version-semver":         "5.3.0",
+		"session-id":                 t.sessionID,
+		"download-dir":               t.downloadFolder,
+		"incomplete-dir":             t.downloadFolder,
+		"incomplete-dir-enabled":     false,
+		"config-dir":                 t.downloadFolder,
+		"start-added-torrents":       true,
+		"download-queue-enabled":     true,
+		"seedRatioLimit":             1,
+		"seedRatioLimited":           false,
+		"idle-seeding-limit":         0,
+		"idle-seeding-limit-enabled": false,
+		"speed-limit-down":           0,
+		"speed-limit-down-enabled":   false,
+		"speed-limit-up":             0,
+		"speed-limit-up-enabled":     false,
+		"rename-partial-files":       false,
+		"peer-port":                  51413,
+		"units": map[string]any{
+			"speed-units":  []string{"kB/s", "MB/s", "GB/s", "TB/s"},
+			"speed-bytes":  1000,
+			"size-units":   []string{"kB", "MB", "GB", "TB"},
+			"size-bytes":   1000,
+			"memory-units": []string{"KiB", "MiB", "GiB", "TiB"},
+			"memory-bytes": 1024,
+		},
+	}
+	return selectFields(session, args.Fields), nil
+}
+
+func (t *Transmission) sessionStats() any {
+	entries := t.manager.Queue().ListFilter("", config.ProtocolTorrent, "", nil, "", false)
+	var active, paused int
+	var downloadSpeed, downloaded int64
+	for _, e := range entries {
+		if e.State == storage.EntryStateDownloading {
+			active++
+			downloadSpeed += e.Speed
+		} else {
+			paused++
+		}
+		downloaded += int64(float64(e.Size) * e.Progress)
+	}
+	uptime := int64(time.Since(t.startTime).Seconds())
+	stats := map[string]any{
+		"uploadedBytes":   0,
+		"downloadedBytes": downloaded,
+		"filesAdded":      len(entries),
+		"sessionCount":    1,
+		"secondsActive":   uptime,
+	}
+	return map[string]any{
+		"activeTorrentCount": active,
+		"pausedTorrentCount": paused,
+		"torrentCount":       len(entries),
+		"downloadSpeed":      downloadSpeed,
+		"uploadSpeed":        0,
+		"cumulative-stats":   stats,
+		"current-stats":      stats,
+	}
+}
@@ -0,0 +1,14 @@
+package transmission
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (t *Transmission) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Use(t.sessionContext)
+	r.Post("/rpc", t.handleRPC)
+	return r
+}
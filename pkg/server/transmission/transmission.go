@@ -0,0 +1,34 @@
+package transmission
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sirrobot01/decypharr/internal/config"
+	"github.com/sirrobot01/decypharr/internal/logger"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+)
+
+// Transmission serves a Transmission-compatible RPC API on top of the same
+// queue the qBittorrent API uses.
+type Transmission struct {
+	downloadFolder          string
+	alwaysRemoveTrackerURLS bool
+	logger                  zerolog.Logger
+	manager                 *manager.Manager
+	sessionID               string
+	startTime               time.Time
+}
+
+func New(manager *manager.Manager) *Transmission {
+	cfg := config.Get()
+	return &Transmission{
+		downloadFolder:          cfg.DownloadFolder,
+		alwaysRemoveTrackerURLS: cfg.AlwaysRmTrackerUrls,
+		logger:                  logger.New("transmission"),
+		manager:                 manager,
+		sessionID:               uuid.NewString(),
+		startTime:               time.Now(),
+	}
+}
@@ -0,0 +1,258 @@
+package transmission
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	debridTypes "github.com/sirrobot01/decypharr/pkg/debrid/types"
+	"github.com/sirrobot01/decypharr/pkg/manager"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+// Transmission RPC types, following the Transmission 4.0 RPC specification
+
+var (
+	Version           = "4.0.5"
+	RPCVersion        = 17
+	RPCVersionMinimum = 14
+)
+
+// Torrent status
+const (
+	StatusStopped = iota
+	StatusCheckWait
+	StatusCheck
+	StatusDownloadWait
+	StatusDownload
+	StatusSeedWait
+	StatusSeed
+)
+
+// errorLocal is Transmission's error code for a local error
+const errorLocal = 3
+
+type Request struct {
+	Method    string          `json:"method"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+	Tag       any             `json:"tag,omitempty"`
+}
+
+type Response struct {
+	Arguments any    `json:"arguments"`
+	Result    string `json:"result"`
+	Tag       any    `json:"tag,omitempty"`
+}
+
+type AddArguments struct {
+	Filename          string   `json:"filename"`
+	Metainfo          string   `json:"metainfo"`
+	DownloadDir       string   `json:"download-dir"`
+	Paused            bool     `json:"paused"`
+	Labels            []string `json:"labels"`
+	BandwidthPriority int      `json:"bandwidthPriority"`
+}
+
+type GetArguments struct {
+	IDs    json.RawMessage `json:"ids"`
+	Fields []string        `json:"fields"`
+}
+
+type RemoveArguments struct {
+	IDs             json.RawMessage `json:"ids"`
+	DeleteLocalData bool            `json:"delete-local-data"`
+}
+
+type SetArguments struct {
+	IDs               json.RawMessage `json:"ids"`
+	Labels            *[]string       `json:"labels"`
+	BandwidthPriority *int            `json:"bandwidthPriority"`
+	QueuePosition     *int            `json:"queuePosition"`
+}
+
+type SetLocationArguments struct {
+	IDs      json.RawMessage `json:"ids"`
+	Location string          `json:"location"`
+	Move     bool            `json:"move"`
+}
+
+type AddedTorrent struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	HashString string `json:"hashString"`
+}
+
+type File struct {
+	Name           string `json:"name"`
+	Length         int64  `json:"length"`
+	BytesCompleted int64  `json:"bytesCompleted"`
+}
+
+type FileStat struct {
+	BytesCompleted int64 `json:"bytesCompleted"`
+	Wanted         bool  `json:"wanted"`
+	Priority       int   `json:"priority"`
+}
+
+// torrentID maps an infohash onto the positive integer id Transmission
+// clients expect. It is derived from the hash, so it stays the same across
+// restarts. Distinct hashes can collide; see indexByTorrentID.
+func torrentID(infohash string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.ToLower(infohash)))
+	id := int(h.Sum32() & 0x7fffffff)
+	if id == 0 {
+		id = 1
+	}
+	return id
+}
+
+// torrentStatus maps an entry onto a Transmission status. Completed and failed
+// entries are stopped, as the Arrs only remove stopped torrents, and so are
+// paused ones still waiting for a slot.
+func torrentStatus(e *storage.Entry, waiting bool) int {
+	switch {
+	case e.State == storage.EntryStateError, e.State == storage.EntryStatePausedUP, e.State == storage.EntryStatePausedDL:
+		return StatusStopped
+	case waiting && e.Priority <= manager.PriorityPaused:
+		return StatusStopped
+	case waiting || e.Status == debridTypes.TorrentStatusQueued:
+		return StatusDownloadWait
+	default:
+		return StatusDownload
+	}
+}
+
+// labels reports the category first, then the entry's tags
+func labels(e *storage.Entry) []string {
+	out := make([]string, 0, len(e.Tags)+1)
+	if e.Category != "" {
+		out = append(out, e.Category)
+	}
+	for _, tag := range e.Tags {
+		if tag != "" && !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// torrentFiles lists files sorted by name, under the torrent's folder as
+// Transmission does for multi-file torrents.
+func torrentFiles(e *storage.Entry) ([]File, []FileStat) {
+	names := slices.Sorted(maps.Keys(e.Files))
+	files := make([]File, 0, len(names))
+	stats := make([]FileStat, 0, len(names))
+	for _, name := range names {
+		f := e.Files[name]
+		fileName := f.Name
+		if len(names) > 1 || fileName != e.Name {
+			fileName = path.Join(e.Name, f.Name)
+		}
+		completed := int64(float64(f.Size) * e.Progress)
+		files = append(files, File{Name: fileName, Length: f.Size, BytesCompleted: completed})
+		stats = append(stats, FileStat{BytesCompleted: completed, Wanted: f.Active()})
+	}
+	return files, stats
+}
+
+// torrentFields returns every field torrent-get can report for an entry.
+// position is the entry's place among jobs waiting for a slot, or -1.
+func torrentFields(e *storage.Entry, position int) map[string]any {
+	remaining := int64(float64(e.Size) * (1 - e.Progress))
+	downloaded := e.Size - remaining
+	files, stats := torrentFiles(e)
+
+	wanted := make([]int, len(stats))
+	priorities := make([]int, len(stats))
+	for i, s := range stats {
+		if s.Wanted {
+			wanted[i] = 1
+		}
+	}
+
+	var doneDate, secondsSeeding int64
+	secondsDownloading := int64(time.Since(e.CreatedAt).Seconds())
+	if e.CompletedAt != nil {
+		doneDate = e.CompletedAt.Unix()
+		secondsDownloading = int64(e.CompletedAt.Sub(e.CreatedAt).Seconds())
+		secondsSeeding = int64(time.Since(*e.CompletedAt).Seconds())
+	}
+
+	errorCode, errorString := 0, ""
+	if e.State == storage.EntryStateError {
+		errorCode, errorString = errorLocal, e.LastError
+		if errorString == "" {
+			errorString = "Download failed"
+		}
+	}
+
+	return map[string]any{
+		"id":                      torrentID(e.InfoHash),
+		"hashString":              strings.ToLower(e.InfoHash),
+		"name":                    e.Name,
+		"downloadDir":             e.SavePath,
+		"totalSize":               e.Size,
+		"sizeWhenDone":            e.Size,
+		"leftUntilDone":           remaining,
+		"haveValid":               downloaded,
+		"haveUnchecked":           0,
+		"downloadedEver":          downloaded,
+		"uploadedEver":            downloaded,
+		"uploadRatio":             1,
+		"seedRatioLimit":          1,
+		"seedRatioMode":           0,
+		"seedIdleLimit":           0,
+		"seedIdleMode":            0,
+		"percentDone":             e.Progress,
+		"metadataPercentComplete": 1,
+		"rateDownload":            e.Speed,
+		"rateUpload":              0,
+		"eta":                     -1,
+		"status":                  torrentStatus(e, position >= 0),
+		"error":                   errorCode,
+		"errorString":             errorString,
+		"isFinished":              e.IsComplete,
+		"isStalled":               false,
+		"isPrivate":               false,
+		"labels":                  labels(e),
+		"addedDate":               e.CreatedAt.Unix(),
+		"doneDate":                doneDate,
+		"activityDate":            e.UpdatedAt.Unix(),
+		"secondsDownloading":      secondsDownloading,
+		"secondsSeeding":          secondsSeeding,
+		"queuePosition":           max(position, 0),
+		"bandwidthPriority":       max(min(e.Priority, 1), -1),
+		"magnetLink":              e.Magnet,
+		"comment":                 "",
+		"creator":                 "Decypharr",
+		"peersConnected":          0,
+		"peersSendingToUs":        e.Seeders,
+		"peersGettingFromUs":      0,
+		"trackerStats":            []any{},
+		"fileCount":               len(files),
+		"files":                   files,
+		"fileStats":               stats,
+		"wanted":                  wanted,
+		"priorities":              priorities,
+	}
+}
+
+// selectFields keeps the requested fields. Without a field list every field
+// is returned.
+func selectFields(all map[string]any, fields []string) map[string]any {
+	if len(fields) == 0 {
+		return all
+	}
+	out := make(map[string]any, len(fields))
+	for _, field := range fields {
+		if v, ok := all[field]; ok {
+			out[field] = v
+		}
+	}
+	return out
+}
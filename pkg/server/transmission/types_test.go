@@ -0,0 +1,104 @@
+package transmission
+
+import (
+	"testing"
+
+	"github.com/sirrobot01/decypharr/pkg/manager"
+	"github.com/sirrobot01/decypharr/pkg/storage"
+)
+
+func TestCategoryFromAdd(t *testing.T) {
+	tr := &Transmission{downloadFolder: "/mnt/downloads"}
+	tests := []struct {
+		name        string
+		downloadDir string
+		labels      []string
+		want        string
+	}{
+		{"label wins", "/mnt/downloads/radarr", []string{"", "sonarr"}, "sonarr"},
+		{"folder under download folder", "/mnt/downloads/sonarr", nil, "sonarr"},
+		{"nested folder", "/mnt/downloads/sonarr/extra/", nil, "sonarr"},
+		{"download folder itself", "/mnt/downloads", nil, ""},
+		{"outside download folder", "/data/tv/lidarr", nil, "lidarr"},
+		{"nothing", "", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := tr.categoryFromAdd(tt.downloadDir, tt.labels); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTorrentFields(t *testing.T) {
+	e := &storage.Entry{
+		InfoHash: "ABCDEF0123",
+		Name:     "Show.S01",
+		Category: "sonarr",
+		Tags:     []string{"sonarr", "hd"},
+		Size:     1000,
+		Progress: 1,
+		State:    storage.EntryStatePausedUP,
+		Files: map[string]*storage.File{
+			"b.mkv": {Name: "b.mkv", Size: 600},
+			"a.nfo": {Name: "a.nfo", Size: 400, Deleted: true},
+		},
+		IsComplete: true,
+	}
+	fields := selectFields(torrentFields(e, -1), []string{"hashString", "status", "leftUntilDone", "labels", "files", "wanted", "unknown"})
+	if len(fields) != 6 {
+		t.Fatalf("expected 6 fields, got %v", fields)
+	}
+	if fields["hashString"] != "abcdef0123" {
+		t.Errorf("hashString should be lower case, got %v", fields["hashString"])
+	}
+	if fields["status"] != StatusStopped || fields["leftUntilDone"] != int64(0) {
+		t.Errorf("completed torrents should be stopped with nothing left, got %v %v", fields["status"], fields["leftUntilDone"])
+	}
+	if got := fields["labels"].([]string); len(got) != 2 || got[0] != "sonarr" || got[1] != "hd" {
+		t.Errorf("expected category then tags as labels, got %v", got)
+	}
+	files := fields["files"].([]File)
+	if files[0].Name != "Show.S01/a.nfo" || files[1].Name != "Show.S01/b.mkv" {
+		t.Errorf("files should be sorted under the torrent folder, got %v", files)
+	}
+	if wanted := fields["wanted"].([]int); wanted[0] != 0 || wanted[1] != 1 {
+		t.Errorf("hidden files should not be wanted, got %v", wanted)
+	}
+}
+
+func TestTorrentStatus(t *testing.T) {
+	e := &storage.Entry{State: storage.EntryStateDownloading}
+	if got := torrentStatus(e, true); got != StatusDownloadWait {
+		t.Errorf("waiting torrent should be download-wait, got %d", got)
+	}
+	if got := torrentStatus(e, false); got != StatusDownload {
+		t.Errorf("running torrent should be downloading, got %d", got)
+	}
+	e.Priority = manager.PriorityPaused
+	if got := torrentStatus(e, true); got != StatusStopped {
+		t.Errorf("paused torrent should be stopped, got %d", got)
+	}
+	e.Priority = 0
+	e.State = storage.EntryStateError
+	if got := torrentStatus(e, false); got != StatusStopped {
+		t.Errorf("failed torrent should be stopped, got %d", got)
+	}
+}
+
+func TestIndexByTorrentIDCollision(t *testing.T) {
+	// These two hashes map to the same id
+	a := &storage.Entry{InfoHash: "00000000000000000000000000000000000299bf"}
+	b := &storage.Entry{InfoHash: "0000000000000000000000000000000000044dd8"}
+	c := &storage.Entry{InfoHash: "abcdef"}
+	if torrentID(a.InfoHash) != torrentID(b.InfoHash) {
+		t.Fatalf("test hashes no longer collide")
+	}
+
+	byID := indexByTorrentID([]*storage.Entry{a, b, c})
+	if entry, found := byID[torrentID(a.InfoHash)]; !found || entry != nil {
+		t.Errorf("a shared id should map to nil, got %v", entry)
+	}
+	if byID[torrentID(c.InfoHash)] != c {
+		t.Errorf("a unique id should map to its entry")
+	}
+}
@@ -68,9 +68,34 @@ func (h *Handler) Routes() chi.Router {
 	r.HandleFunc("/{group}/{torrent}", h.handleTorrentFolder)
 	r.HandleFunc("/{group}/{torrent}/{file}", h.handleTorrentFile)
 	r.HandleFunc("/stream/{group}/{torrent}/{file}", h.handleTorrentFile)
+
+	// Mount views: the same tree filtered and renamed per view. The managed
+	// rclone mount uses these as the remotes of its view mounts.
+	r.Route("/__views__/{view}", func(r chi.Router) {
+		r.HandleFunc("/", h.handleRoot)
+		r.HandleFunc("/{group}", h.handleGroup)
+		r.HandleFunc("/{group}/{torrent}", h.handleTorrentFolder)
+		r.HandleFunc("/{group}/{torrent}/{file}", h.handleTorrentFile)
+	})
 	return r
 }
 
+// tree returns the tree a request browses: a mount view under /__views__,
+// the full library otherwise. It writes a 404 and returns nil for an unknown
+// view.
+func (h *Handler) tree(w http.ResponseWriter, r *http.Request) manager.Tree {
+	name := chi.URLParam(r, "view")
+	if name == "" {
+		return h.manager
+	}
+	view := h.manager.View(utils.PathUnescape(name))
+	if view == nil {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return nil
+	}
+	return view
+}
+
 func (h *Handler) IsDisabled() bool {
 	cfg := config.Get()
 	return cfg.DisableWebDav
@@ -103,29 +128,45 @@ func (h *Handler) handler(current *manager.FileInfo, children []manager.FileInfo
 }
 
 func (h *Handler) handleRoot(w http.ResponseWriter, r *http.Request) {
-	current := h.manager.RootInfo()
-	children := h.manager.GetEntries()
+	tree := h.tree(w, r)
+	if tree == nil {
+		return
+	}
+	current := tree.RootInfo()
+	children := tree.GetEntries()
 	h.handler(current, children, w, r)
 }
 
 func (h *Handler) handleGroup(w http.ResponseWriter, r *http.Request) {
+	tree := h.tree(w, r)
+	if tree == nil {
+		return
+	}
 	group := utils.PathUnescape(chi.URLParam(r, "group"))
-	currentInfo, rawEntries := h.manager.GetEntryChildren(group)
+	currentInfo, rawEntries := tree.GetEntryChildren(group)
 	h.handler(currentInfo, rawEntries, w, r)
 
 }
 
 func (h *Handler) handleTorrentFolder(w http.ResponseWriter, r *http.Request) {
+	tree := h.tree(w, r)
+	if tree == nil {
+		return
+	}
 	torrent := utils.PathUnescape(chi.URLParam(r, "torrent"))
 
-	currentInfo, children := h.manager.GetTorrentChildren(torrent)
+	currentInfo, children := tree.GetTorrentChildren(torrent)
 	h.handler(currentInfo, children, w, r)
 }
 
 func (h *Handler) handleTorrentFile(w http.ResponseWriter, r *http.Request) {
+	tree := h.tree(w, r)
+	if tree == nil {
+		return
+	}
 	torrent := utils.PathUnescape(chi.URLParam(r, "torrent"))
 	file := utils.PathUnescape(chi.URLParam(r, "file"))
-	currentInfo, err := h.manager.GetTorrentFile(torrent, file)
+	currentInfo, err := tree.GetTorrentFile(torrent, file)
 	if err != nil || currentInfo == nil {
 		http.Error(w, "File not found", http.StatusNotFound)
 		return
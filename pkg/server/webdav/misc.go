@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/sirrobot01/decypharr/pkg/manager"
+	"github.com/sirrobot01/decypharr/pkg/storage"
 	"github.com/stanNthe5/stringbuf"
 )
 
@@ -45,6 +46,7 @@ type entryItem struct {
 	size    int64
 	isDir   bool
 	modTime string
+	media   *storage.MediaInfo
 }
 
 type httpRange struct{ start, end int64 }
@@ -140,6 +142,7 @@ func convertToXML(cleanPath string, currentInfo *manager.FileInfo, children []ma
 			isDir:   currentInfo.IsDir(),
 			size:    currentInfo.Size(),
 			modTime: currentInfo.ModTime().Format(time.RFC3339),
+			media:   currentInfo.Media(),
 		})
 	}
 
@@ -158,6 +161,7 @@ func convertToXML(cleanPath string, currentInfo *manager.FileInfo, children []ma
 			isDir:   info.IsDir(),
 			size:    info.Size(),
 			modTime: info.ModTime().Format(time.RFC3339),
+			media:   info.Media(),
 		})
 	}
 
@@ -165,7 +169,7 @@ func convertToXML(cleanPath string, currentInfo *manager.FileInfo, children []ma
 
 	// XML header and main element
 	_, _ = sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
-	_, _ = sb.WriteString(`<d:multistatus xmlns:d="DAV:">`)
+	_, _ = sb.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:m="` + mediaPropNamespace + `">`)
 
 	// AddOrUpdate responses for each entryItem
 	for _, e := range entries {
@@ -193,6 +197,10 @@ func convertToXML(cleanPath string, currentInfo *manager.FileInfo, children []ma
 		_, _ = sb.WriteString(e.escName)
 		_, _ = sb.WriteString(`</d:displayname>`)
 
+		if e.media != nil {
+			writeMediaProps(&sb, e.media)
+		}
+
 		_, _ = sb.WriteString(`</d:prop>`)
 		_, _ = sb.WriteString(`<d:status>HTTP/1.1 200 OK</d:status>`)
 		_, _ = sb.WriteString(`</d:propstat>`)
@@ -203,3 +211,31 @@ func convertToXML(cleanPath string, currentInfo *manager.FileInfo, children []ma
 	_, _ = sb.WriteString(`</d:multistatus>`)
 	return sb
 }
+
+// mediaPropNamespace is the XML namespace of the container probe properties
+// reported on files in PROPFIND responses.
+const mediaPropNamespace = "https://github.com/sirrobot01/decypharr/ns/media"
+
+func writeMediaProps(sb *stringbuf.StringBuf, media *storage.MediaInfo) {
+	writeProp := func(name, value string) {
+		if value == "" {
+			return
+		}
+		_, _ = sb.WriteString(`<m:` + name + `>`)
+		_, _ = sb.WriteString(xmlEscape(value))
+		_, _ = sb.WriteString(`</m:` + name + `>`)
+	}
+	writeProp("container", media.Container)
+	writeProp("status", media.Status)
+	writeProp("reason", media.Reason)
+	if media.DurationSeconds > 0 {
+		writeProp("duration", strconv.FormatFloat(media.DurationSeconds, 'f', 3, 64))
+	}
+	writeProp("videocodec", media.VideoCodec)
+	if media.Width > 0 && media.Height > 0 {
+		writeProp("resolution", strconv.Itoa(media.Width)+"x"+strconv.Itoa(media.Height))
+	}
+	writeProp("audiocodecs", strings.Join(media.AudioCodecs, ","))
+	writeProp("audiolanguages", strings.Join(media.AudioLanguages, ","))
+	writeProp("subtitlelanguages", strings.Join(media.SubtitleLanguages, ","))
+}
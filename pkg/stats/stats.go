@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/sirrobot01/decypharr/internal/buffer"
 	"github.com/sirrobot01/decypharr/internal/config"
 	"github.com/sirrobot01/decypharr/internal/logger"
 	"github.com/sirrobot01/decypharr/internal/utils"
@@ -194,6 +195,9 @@ func (c *Collector) collect() *Snapshot {
 		}
 	}
 
+	// --- Buffers ---
+	snap.Buffers = buffer.DefaultGovernor().Stats()
+
 	return snap
 }
 
@@ -1,6 +1,7 @@
 package stats
 
 import (
+	"github.com/sirrobot01/decypharr/internal/buffer"
 	"github.com/sirrobot01/decypharr/pkg/debrid/types"
 	"github.com/sirrobot01/decypharr/pkg/manager"
 )
@@ -17,6 +18,9 @@ type Snapshot struct {
 	Queue         QueueStats        `json:"queue"`
 	Arrs          ArrStats          `json:"arrs"`
 	Repair        RepairStats       `json:"repair"`
+	// Buffers is the streaming-buffer governor's budgets and per-consumer
+	// (DFS, usenet) usage.
+	Buffers buffer.GovernorStats `json:"buffers"`
 }
 
 type SystemStats struct {
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"fmt"
+
+	json "github.com/bytedance/sonic"
+	"github.com/sirrobot01/decypharr/internal/nntp"
+	debridTypes "github.com/sirrobot01/decypharr/pkg/debrid/types"
+)
+
+// GetAccountTraffic returns the stored traffic state of a debrid account.
+func (s *Storage) GetAccountTraffic(key string) (*debridTypes.AccountTraffic, error) {
+	if key == "" {
+		return nil, fmt.Errorf("account key is empty")
+	}
+	data, err := s.traffic.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	var traffic debridTypes.AccountTraffic
+	if err := json.Unmarshal(data, &traffic); err != nil {
+		return nil, err
+	}
+	return &traffic, nil
+}
+
+// usenetTrafficKey keeps usenet providers apart from debrid accounts, which
+// share the bucket.
+func usenetTrafficKey(host string) string {
+	return "usenet:" + host
+}
+
+// GetProviderTraffic returns the stored traffic counters of a usenet provider.
+func (s *Storage) GetProviderTraffic(host string) (*nntp.TrafficState, error) {
+	if host == "" {
+		return nil, fmt.Errorf("provider host is empty")
+	}
+	data, err := s.traffic.Get(usenetTrafficKey(host))
+	if err != nil {
+		return nil, err
+	}
+	var traffic nntp.TrafficState
+	if err := json.Unmarshal(data, &traffic); err != nil {
+		return nil, err
+	}
+	return &traffic, nil
+}
+
+func (s *Storage) SaveProviderTraffic(host string, traffic *nntp.TrafficState) error {
+	if host == "" || traffic == nil {
+		return fmt.Errorf("provider traffic is missing a host")
+	}
+	data, err := json.Marshal(traffic)
+	if err != nil {
+		return err
+	}
+	return s.traffic.Put(usenetTrafficKey(host), data, nil)
+}
+
+func (s *Storage) SaveAccountTraffic(key string, traffic *debridTypes.AccountTraffic) error {
+	if key == "" || traffic == nil {
+		return fmt.Errorf("account traffic is missing a key")
+	}
+	data, err := json.Marshal(traffic)
+	if err != nil {
+		return err
+	}
+	return s.traffic.Put(key, data, nil)
+}
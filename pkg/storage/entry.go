@@ -34,7 +34,11 @@ func (s *Storage) AddOrUpdate(entry *Entry) error {
 		AddedOn:   entry.AddedOn.Unix(),
 	}
 
-	return s.entries.Put(entry.InfoHash, data, meta)
+	if err := s.entries.Put(entry.InfoHash, data, meta); err != nil {
+		return err
+	}
+	s.notifyEntryChanged(entry.InfoHash)
+	return nil
 }
 
 // BatchAddOrUpdate adds or updates multiple entries
@@ -206,7 +210,11 @@ func (s *Storage) Delete(infohash string) error {
 	if err == nil && entry != nil {
 		s.removeFromEntryItem(entry)
 	}
-	return s.entries.Delete(infohash)
+	if err := s.entries.Delete(infohash); err != nil {
+		return err
+	}
+	s.notifyEntryChanged(infohash)
+	return nil
 }
 
 // Count returns the number of entries
@@ -236,7 +244,7 @@ func (s *Storage) updateEntryItem(entry *Entry) {
 
 	for fileName, file := range entry.Files {
 		if existing, ok := item.Files[fileName]; ok {
-			if file.AddedOn.After(existing.AddedOn) || (file.AddedOn.Equal(existing.AddedOn) && file.Size != existing.Size) {
+			if file.AddedOn.After(existing.AddedOn) || (file.AddedOn.Equal(existing.AddedOn) && file.Size != existing.Size) || mediaProbedAfter(file.Media, existing.Media) {
 				item.Files[fileName] = file
 			}
 		} else {
@@ -255,6 +263,13 @@ func (s *Storage) updateEntryItem(entry *Entry) {
 	}
 }
 
+// Reindex saves an entry whose folder or file names changed. old is the entry
+// as stored before the change; its files leave the name index first.
+func (s *Storage) Reindex(old, entry *Entry) error {
+	s.removeFromEntryItem(old)
+	return s.AddOrUpdate(entry)
+}
+
 // removeFromEntryItem removes an entry from the name index
 func (s *Storage) removeFromEntryItem(entry *Entry) {
 	name := entry.GetFolder()
@@ -0,0 +1,260 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/bytedance/sonic"
+)
+
+// EntryEventType is what happened to an entry.
+type EntryEventType string
+
+const (
+	EntryEventAdded        EntryEventType = "added"
+	EntryEventSubmitted    EntryEventType = "submitted"
+	EntryEventSubmitFailed EntryEventType = "submit_failed"
+	EntryEventStatus       EntryEventType = "status"
+	EntryEventSwitched     EntryEventType = "switched"
+	EntryEventSwitchFailed EntryEventType = "switch_failed"
+	EntryEventLinkFailed   EntryEventType = "link_failed"
+	EntryEventRepair       EntryEventType = "repair"
+	EntryEventSymlinks     EntryEventType = "symlinks"
+	EntryEventImported     EntryEventType = "imported"
+	EntryEventTagged       EntryEventType = "tagged"
+	EntryEventRecycled     EntryEventType = "recycled"
+	EntryEventRestored     EntryEventType = "restored"
+	EntryEventRefreshed    EntryEventType = "refreshed"
+	EntryEventEvicted      EntryEventType = "evicted"
+)
+
+// EntryEvent is one item of an entry's timeline.
+type EntryEvent struct {
+	Time     time.Time      `json:"time"`
+	Type     EntryEventType `json:"type"`
+	Provider string         `json:"provider,omitempty"`
+	Message  string         `json:"message,omitempty"`
+}
+
+func eventsKey(infohash string) string {
+	return strings.ToLower(strings.TrimSpace(infohash))
+}
+
+// Every event is stored as its own record keyed by infohash and a sequence
+// number, so appending to a timeline is a single write. eventLog indexes the
+// sequence numbers of each timeline in memory.
+type eventLog struct {
+	mu      sync.Mutex
+	nextSeq uint64
+	seqs    map[string][]uint64 // infohash -> sequence numbers, oldest first
+}
+
+func eventKey(infohash string, seq uint64) string {
+	return fmt.Sprintf("%s/%016x", infohash, seq)
+}
+
+func parseEventKey(key string) (string, uint64, bool) {
+	infohash, hex, ok := strings.Cut(key, "/")
+	if !ok {
+		return "", 0, false
+	}
+	seq, err := strconv.ParseUint(hex, 16, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return infohash, seq, true
+}
+
+// loadEntryEvents builds the timeline index, splitting timelines stored by
+// older versions as one array per entry into per-event records.
+func (s *Storage) loadEntryEvents() error {
+	s.events.mu.Lock()
+	defer s.events.mu.Unlock()
+
+	s.events.seqs = make(map[string][]uint64)
+	s.events.nextSeq = 0
+	var legacy []string
+	for _, key := range s.entryEvents.Keys() {
+		infohash, seq, ok := parseEventKey(key)
+		if !ok {
+			legacy = append(legacy, key)
+			continue
+		}
+		s.events.seqs[infohash] = append(s.events.seqs[infohash], seq)
+		s.events.nextSeq = max(s.events.nextSeq, seq+1)
+	}
+	for _, seqs := range s.events.seqs {
+		slices.Sort(seqs)
+	}
+
+	for _, key := range legacy {
+		data, err := s.entryEvents.Get(key)
+		if err != nil {
+			return err
+		}
+		var events []EntryEvent
+		if err := json.Unmarshal(data, &events); err != nil {
+			s.logger.Warn().Err(err).Str("infohash", key).Msg("Dropping unreadable entry timeline")
+		}
+		for _, ev := range events {
+			if err := s.putEntryEvent(key, s.events.nextSeq, ev); err != nil {
+				return err
+			}
+			s.events.seqs[key] = append(s.events.seqs[key], s.events.nextSeq)
+			s.events.nextSeq++
+		}
+		if err := s.entryEvents.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Storage) putEntryEvent(infohash string, seq uint64, ev EntryEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return s.entryEvents.Put(eventKey(infohash, seq), data, nil)
+}
+
+// AppendEntryEvent adds an event to the end of an entry's timeline. When the
+// timeline grows past maxEvents the oldest events are dropped; 0 keeps all.
+func (s *Storage) AppendEntryEvent(infohash string, ev EntryEvent, maxEvents int) error {
+	key := eventsKey(infohash)
+	if key == "" {
+		return fmt.Errorf("event is missing infohash")
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	s.events.mu.Lock()
+	seq := s.events.nextSeq
+	s.events.nextSeq++
+	seqs := append(s.events.seqs[key], seq)
+	var dropped []uint64
+	if maxEvents > 0 && len(seqs) > maxEvents {
+		dropped = slices.Clone(seqs[:len(seqs)-maxEvents])
+		seqs = slices.Clone(seqs[len(seqs)-maxEvents:])
+	}
+	s.events.seqs[key] = seqs
+	s.events.mu.Unlock()
+
+	if err := s.putEntryEvent(key, seq, ev); err != nil {
+		s.forgetEntryEvents(key, []uint64{seq})
+		return err
+	}
+	for _, old := range dropped {
+		if err := s.entryEvents.Delete(eventKey(key, old)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetEntryEvents returns an entry's timeline, oldest first.
+func (s *Storage) GetEntryEvents(infohash string) ([]EntryEvent, error) {
+	key := eventsKey(infohash)
+	if key == "" {
+		return nil, fmt.Errorf("infohash is empty")
+	}
+	s.events.mu.Lock()
+	seqs := slices.Clone(s.events.seqs[key])
+	s.events.mu.Unlock()
+
+	events := make([]EntryEvent, 0, len(seqs))
+	for _, seq := range seqs {
+		data, err := s.entryEvents.Get(eventKey(key, seq))
+		if err != nil {
+			// Trimmed or pruned since the snapshot was taken
+			continue
+		}
+		var ev EntryEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// HasEntryEvents reports whether an entry has a timeline at all.
+func (s *Storage) HasEntryEvents(infohash string) bool {
+	key := eventsKey(infohash)
+	if key == "" {
+		return false
+	}
+	s.events.mu.Lock()
+	defer s.events.mu.Unlock()
+	return len(s.events.seqs[key]) > 0
+}
+
+func (s *Storage) DeleteEntryEvents(infohash string) error {
+	key := eventsKey(infohash)
+	if key == "" {
+		return nil
+	}
+	s.events.mu.Lock()
+	seqs := s.events.seqs[key]
+	delete(s.events.seqs, key)
+	s.events.mu.Unlock()
+
+	for _, seq := range seqs {
+		if err := s.entryEvents.Delete(eventKey(key, seq)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PruneEntryEvents drops events older than before and deletes timelines left
+// empty. It returns the number of events removed.
+func (s *Storage) PruneEntryEvents(before time.Time) (int, error) {
+	expired := make(map[string][]uint64)
+	err := s.entryEvents.ForEach(func(key string, value []byte) error {
+		infohash, seq, ok := parseEventKey(key)
+		if !ok {
+			return nil
+		}
+		var ev EntryEvent
+		if err := json.Unmarshal(value, &ev); err != nil || ev.Time.After(before) {
+			return nil
+		}
+		expired[infohash] = append(expired[infohash], seq)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for infohash, seqs := range expired {
+		s.forgetEntryEvents(infohash, seqs)
+		for _, seq := range seqs {
+			if err := s.entryEvents.Delete(eventKey(infohash, seq)); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// forgetEntryEvents removes sequence numbers from a timeline's index.
+func (s *Storage) forgetEntryEvents(infohash string, seqs []uint64) {
+	s.events.mu.Lock()
+	defer s.events.mu.Unlock()
+	kept := slices.DeleteFunc(slices.Clone(s.events.seqs[infohash]), func(seq uint64) bool {
+		return slices.Contains(seqs, seq)
+	})
+	if len(kept) == 0 {
+		delete(s.events.seqs, infohash)
+		return
+	}
+	s.events.seqs[infohash] = kept
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	json "github.com/bytedance/sonic"
+)
+
+// GuardianAction is what the expiry guardian does about a placement.
+type GuardianAction string
+
+const (
+	GuardianReAdd   GuardianAction = "readd"   // Active placement gone from the debrid; the magnet is re-added
+	GuardianDrop    GuardianAction = "drop"    // Secondary placement gone from the debrid; it is removed from the entry
+	GuardianRefresh GuardianAction = "refresh" // Placement about to expire; the magnet is re-added to restart its clock
+	GuardianEvict   GuardianAction = "evict"   // Debrid at its limit; the least recently streamed placement is removed
+)
+
+// GuardianItem is one placement a guardian run acted on, or would act on.
+type GuardianItem struct {
+	Action     GuardianAction `json:"action"`
+	Provider   string         `json:"provider"`
+	TorrentID  string         `json:"torrent_id,omitempty"`
+	InfoHash   string         `json:"infohash"`
+	Name       string         `json:"name"`
+	Size       int64          `json:"size,omitempty"`
+	AddedAt    time.Time      `json:"added_at"`             // When the placement was added to the debrid
+	ExpiresAt  *time.Time     `json:"expires_at,omitempty"` // When the debrid's expiry rules drop it
+	LastPlayed *time.Time     `json:"last_played,omitempty"`
+	Applied    bool           `json:"applied,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+type GuardianRunStats struct {
+	Placements int `json:"placements"` // Placements checked
+	Missing    int `json:"missing"`
+	Expiring   int `json:"expiring"`
+	Evicted    int `json:"evicted"` // Placements selected for eviction
+	Applied    int `json:"applied"`
+	Failed     int `json:"failed"`
+}
+
+// GuardianRun is the report of one expiry guardian pass.
+type GuardianRun struct {
+	ID          string            `json:"id"`
+	Trigger     RepairRunTrigger  `json:"trigger"`
+	Status      RepairRunStatus   `json:"status"`
+	DryRun      bool              `json:"dry_run"`
+	Providers   []string          `json:"providers,omitempty"`
+	StartedAt   time.Time         `json:"started_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	CompletedAt time.Time         `json:"completed_at"`
+	Stats       GuardianRunStats  `json:"stats"`
+	Items       []*GuardianItem   `json:"items,omitempty"`
+	Errors      map[string]string `json:"errors,omitempty"` // Provider -> listing error
+	Error       string            `json:"error,omitempty"`
+}
+
+func (s *Storage) SaveGuardianRun(run *GuardianRun) error {
+	if run == nil || run.ID == "" {
+		return fmt.Errorf("guardian run is missing id")
+	}
+	run.UpdatedAt = time.Now()
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	return s.guardian.Put(run.ID, data, nil)
+}
+
+func (s *Storage) GetGuardianRun(id string) (*GuardianRun, error) {
+	if id == "" {
+		return nil, fmt.Errorf("guardian run id is empty")
+	}
+	data, err := s.guardian.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	var run GuardianRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, err
+	}
+	if run.ID == "" {
+		run.ID = id
+	}
+	return &run, nil
+}
+
+// ListGuardianRuns returns runs sorted newest-first.
+func (s *Storage) ListGuardianRuns() ([]*GuardianRun, error) {
+	runs := make([]*GuardianRun, 0)
+	err := s.guardian.ForEach(func(key string, value []byte) error {
+		var run GuardianRun
+		if err := json.Unmarshal(value, &run); err != nil {
+			return nil
+		}
+		if run.ID == "" {
+			run.ID = key
+		}
+		runs = append(runs, &run)
+		return nil
+	})
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartedAt.After(runs[j].StartedAt)
+	})
+	return runs, err
+}
+
+// PruneGuardianRuns keeps the newest `keep` runs and deletes the rest.
+func (s *Storage) PruneGuardianRuns(keep int) error {
+	runs, err := s.ListGuardianRuns()
+	if err != nil {
+		return err
+	}
+	if keep <= 0 || len(runs) <= keep {
+		return nil
+	}
+	for _, run := range runs[keep:] {
+		if run.Status == RepairRunRunning {
+			continue
+		}
+		_ = s.guardian.Delete(run.ID)
+	}
+	return nil
+}
@@ -164,6 +164,11 @@ func New(config Config) (*Store, error) {
 	return s, nil
 }
 
+// IsClosed reports whether the store has been closed
+func (s *Store) IsClosed() bool {
+	return s.closed.Load()
+}
+
 // Close shuts down the store gracefully
 func (s *Store) Close() error {
 	if s.closed.Swap(true) {
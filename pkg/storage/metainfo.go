@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SaveTorrentMetainfo persists the raw .torrent bytes for an infohash. The
+// metainfo carries the piece hashes the piece_verify repair probe checks
+// debrid content against, so it is kept for as long as the entry exists.
+func (s *Storage) SaveTorrentMetainfo(infohash string, data []byte) error {
+	infohash = strings.ToLower(strings.TrimSpace(infohash))
+	if infohash == "" {
+		return fmt.Errorf("metainfo is missing infohash")
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("metainfo is empty")
+	}
+	return s.metainfo.Put(infohash, data, nil)
+}
+
+// GetTorrentMetainfo returns the raw .torrent bytes stored for an infohash.
+func (s *Storage) GetTorrentMetainfo(infohash string) ([]byte, error) {
+	infohash = strings.ToLower(strings.TrimSpace(infohash))
+	if infohash == "" {
+		return nil, fmt.Errorf("infohash is empty")
+	}
+	return s.metainfo.Get(infohash)
+}
+
+// HasTorrentMetainfo reports whether metainfo is stored for an infohash.
+func (s *Storage) HasTorrentMetainfo(infohash string) bool {
+	return s.metainfo.Exists(strings.ToLower(strings.TrimSpace(infohash)))
+}
+
+func (s *Storage) DeleteTorrentMetainfo(infohash string) error {
+	infohash = strings.ToLower(strings.TrimSpace(infohash))
+	if infohash == "" || !s.metainfo.Exists(infohash) {
+		return nil
+	}
+	return s.metainfo.Delete(infohash)
+}
@@ -16,6 +16,10 @@ func HandleExistingEntryMerge(existing, incoming *Entry) *Entry {
 	incoming.ActiveProvider = selectActivePlacement(existing, incoming)
 	incoming.Providers = mergeProviders(existing.Providers, incoming.Providers)
 	incoming.Tags = mergeTags(existing.Tags, incoming.Tags)
+	incoming.Folders = mergeTags(existing.Folders, incoming.Folders)
+	if incoming.Profile == "" {
+		incoming.Profile = existing.Profile
+	}
 
 	return incoming
 }
@@ -68,6 +72,12 @@ func mergeFiles(existing, incoming map[string]*File) map[string]*File {
 		if existingFile, exists := merged[k]; exists {
 			// Prefer file with newer AddedOn timestamp
 			if v.AddedOn.After(existingFile.AddedOn) {
+				if v.Media == nil && existingFile.Media != nil && existingFile.Size == v.Size {
+					// Copy, so the caller's incoming file is left as it was
+					file := *v
+					file.Media = existingFile.Media
+					v = &file
+				}
 				merged[k] = v
 			}
 		} else {
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SaveNZBSource persists the NZB an entry was imported from when it went to a
+// debrid usenet service. NNTP imports keep their own copy; this one lets a
+// debrid NZB be re-submitted on repair or moved to another usenet provider.
+func (s *Storage) SaveNZBSource(id string, data []byte) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return fmt.Errorf("nzb source is missing id")
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("nzb source is empty")
+	}
+	return s.nzbSources.Put(id, data, nil)
+}
+
+// GetNZBSource returns the NZB stored for an entry.
+func (s *Storage) GetNZBSource(id string) ([]byte, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, fmt.Errorf("nzb id is empty")
+	}
+	return s.nzbSources.Get(id)
+}
+
+func (s *Storage) DeleteNZBSource(id string) error {
+	id = strings.TrimSpace(id)
+	if id == "" || !s.nzbSources.Exists(id) {
+		return nil
+	}
+	return s.nzbSources.Delete(id)
+}
@@ -0,0 +1,35 @@
+package storage
+
+// EntryObserver is told about entry and entry health writes, so indexes
+// derived from entries can follow changes instead of rescanning the store.
+// Calls are made after the write, on the writer's goroutine, and must not
+// block.
+type EntryObserver interface {
+	EntryChanged(infohash string)
+	EntryHealthChanged(entryName string)
+}
+
+type entryObserverRef struct {
+	EntryObserver
+}
+
+// SetEntryObserver replaces the entry observer; nil removes it
+func (s *Storage) SetEntryObserver(o EntryObserver) {
+	if o == nil {
+		s.observer.Store(nil)
+		return
+	}
+	s.observer.Store(&entryObserverRef{o})
+}
+
+func (s *Storage) notifyEntryChanged(infohash string) {
+	if ref := s.observer.Load(); ref != nil {
+		ref.EntryChanged(infohash)
+	}
+}
+
+func (s *Storage) notifyEntryHealthChanged(entryName string) {
+	if ref := s.observer.Load(); ref != nil {
+		ref.EntryHealthChanged(entryName)
+	}
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	json "github.com/bytedance/sonic"
+)
+
+// CachePinType is what a cache pin selects.
+type CachePinType string
+
+const (
+	CachePinEntry    CachePinType = "entry"
+	CachePinCategory CachePinType = "category"
+)
+
+// CachePin keeps an entry, or every entry of a category, fully on the DFS
+// cache disk.
+type CachePin struct {
+	Type      CachePinType `json:"type"`
+	Value     string       `json:"value"` // infohash or category name
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+func cachePinKey(typ CachePinType, value string) string {
+	value = strings.TrimSpace(value)
+	if typ == CachePinEntry {
+		value = strings.ToLower(value)
+	}
+	return string(typ) + ":" + value
+}
+
+// PutCachePin saves a pin, replacing an existing one for the same target.
+func (s *Storage) PutCachePin(pin CachePin) error {
+	if pin.Value == "" {
+		return fmt.Errorf("pin is missing a value")
+	}
+	data, err := json.Marshal(pin)
+	if err != nil {
+		return err
+	}
+	return s.cachePins.Put(cachePinKey(pin.Type, pin.Value), data, nil)
+}
+
+// DeleteCachePin removes a pin. Removing a pin that doesn't exist is not an
+// error.
+func (s *Storage) DeleteCachePin(typ CachePinType, value string) error {
+	key := cachePinKey(typ, value)
+	if !s.cachePins.Exists(key) {
+		return nil
+	}
+	return s.cachePins.Delete(key)
+}
+
+// GetCachePins returns every pin, oldest first.
+func (s *Storage) GetCachePins() ([]CachePin, error) {
+	pins := make([]CachePin, 0)
+	err := s.cachePins.ForEach(func(key string, value []byte) error {
+		var pin CachePin
+		if err := json.Unmarshal(value, &pin); err != nil {
+			s.logger.Warn().Err(err).Str("key", key).Msg("Skipping unreadable cache pin")
+			return nil
+		}
+		pins = append(pins, pin)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pins, func(i, j int) bool {
+		return pins[i].CreatedAt.Before(pins[j].CreatedAt)
+	})
+	return pins, nil
+}
@@ -13,11 +13,12 @@ import (
 
 func fileToProto(f *File) *FileProto {
 	pb := &FileProto{
-		Name:     f.Name,
-		Path:     f.Path,
-		Size:     f.Size,
-		Deleted:  f.Deleted,
-		InfoHash: f.InfoHash,
+		Name:       f.Name,
+		Path:       f.Path,
+		Size:       f.Size,
+		Deleted:    f.Deleted,
+		InfoHash:   f.InfoHash,
+		Unselected: f.Unselected,
 	}
 	if !f.AddedOn.IsZero() {
 		pb.AddedOnUnix = f.AddedOn.Unix()
@@ -27,16 +28,20 @@ func fileToProto(f *File) *FileProto {
 		pb.ByteRangeStart = f.ByteRange[0]
 		pb.ByteRangeEnd = f.ByteRange[1]
 	}
+	if f.Media != nil {
+		pb.Media = mediaInfoToProto(f.Media)
+	}
 	return pb
 }
 
 func protoToFile(pb *FileProto) *File {
 	f := &File{
-		Name:     pb.Name,
-		Path:     pb.Path,
-		Size:     pb.Size,
-		Deleted:  pb.Deleted,
-		InfoHash: pb.InfoHash,
+		Name:       pb.Name,
+		Path:       pb.Path,
+		Size:       pb.Size,
+		Deleted:    pb.Deleted,
+		InfoHash:   pb.InfoHash,
+		Unselected: pb.Unselected,
 	}
 	if pb.AddedOnUnix != 0 {
 		f.AddedOn = time.Unix(pb.AddedOnUnix, 0)
@@ -44,9 +49,50 @@ func protoToFile(pb *FileProto) *File {
 	if pb.HasByteRange {
 		f.ByteRange = &[2]int64{pb.ByteRangeStart, pb.ByteRangeEnd}
 	}
+	if pb.Media != nil {
+		f.Media = protoToMediaInfo(pb.Media)
+	}
 	return f
 }
 
+func mediaInfoToProto(mi *MediaInfo) *MediaInfoProto {
+	pb := &MediaInfoProto{
+		Container:         mi.Container,
+		DurationSeconds:   mi.DurationSeconds,
+		VideoCodec:        mi.VideoCodec,
+		Width:             int32(mi.Width),
+		Height:            int32(mi.Height),
+		AudioCodecs:       mi.AudioCodecs,
+		AudioLanguages:    mi.AudioLanguages,
+		SubtitleLanguages: mi.SubtitleLanguages,
+		Status:            mi.Status,
+		Reason:            mi.Reason,
+	}
+	if !mi.ProbedAt.IsZero() {
+		pb.ProbedAtUnix = mi.ProbedAt.Unix()
+	}
+	return pb
+}
+
+func protoToMediaInfo(pb *MediaInfoProto) *MediaInfo {
+	mi := &MediaInfo{
+		Container:         pb.Container,
+		DurationSeconds:   pb.DurationSeconds,
+		VideoCodec:        pb.VideoCodec,
+		Width:             int(pb.Width),
+		Height:            int(pb.Height),
+		AudioCodecs:       pb.AudioCodecs,
+		AudioLanguages:    pb.AudioLanguages,
+		SubtitleLanguages: pb.SubtitleLanguages,
+		Status:            pb.Status,
+		Reason:            pb.Reason,
+	}
+	if pb.ProbedAtUnix != 0 {
+		mi.ProbedAt = time.Unix(pb.ProbedAtUnix, 0)
+	}
+	return mi
+}
+
 // ============================================================================
 // ProviderFile Conversions
 // ============================================================================
@@ -157,6 +203,12 @@ func EntryToProto(e *Entry) *EntryProto {
 		SkipMultiSeason:  e.SkipMultiSeason,
 		LastError:        e.LastError,
 		ErrorCount:       int32(e.ErrorCount),
+		NameOverride:     e.NameOverride,
+		FileRenames:      e.FileRenames,
+		Priority:         int32(e.Priority),
+		PpParameters:     e.PPParameters,
+		Profile:          e.Profile,
+		Folders:          e.Folders,
 	}
 
 	// Timestamps
@@ -189,6 +241,18 @@ func EntryToProto(e *Entry) *EntryProto {
 	for name, f := range e.Files {
 		pb.Files[name] = fileToProto(f)
 	}
+	if sel := e.FileSelection; sel != nil {
+		pb.FileSelection = &FileSelectionProto{
+			Include:     sel.Include,
+			Exclude:     sel.Exclude,
+			SkipExtras:  sel.SkipExtras,
+			Seasons:     sel.Seasons,
+			Episodes:    sel.Episodes,
+			LargestOnly: sel.LargestOnly,
+			Keep:        sel.Keep,
+			Skip:        sel.Skip,
+		}
+	}
 
 	return pb
 }
@@ -225,6 +289,12 @@ func ProtoToEntry(pb *EntryProto) *Entry {
 		SkipMultiSeason:  pb.SkipMultiSeason,
 		LastError:        pb.LastError,
 		ErrorCount:       int(pb.ErrorCount),
+		NameOverride:     pb.NameOverride,
+		FileRenames:      pb.FileRenames,
+		Priority:         int(pb.Priority),
+		PPParameters:     pb.PpParameters,
+		Profile:          pb.Profile,
+		Folders:          pb.Folders,
 	}
 
 	// Timestamps
@@ -257,6 +327,18 @@ func ProtoToEntry(pb *EntryProto) *Entry {
 	for name, f := range pb.Files {
 		e.Files[name] = protoToFile(f)
 	}
+	if sel := pb.FileSelection; sel != nil {
+		e.FileSelection = &config.FileSelection{
+			Include:     sel.Include,
+			Exclude:     sel.Exclude,
+			SkipExtras:  sel.SkipExtras,
+			Seasons:     sel.Seasons,
+			Episodes:    sel.Episodes,
+			LargestOnly: sel.LargestOnly,
+			Keep:        sel.Keep,
+			Skip:        sel.Skip,
+		}
+	}
 
 	// Ensure non-nil slices
 	if e.Tags == nil {
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	json "github.com/bytedance/sonic"
+)
+
+// ReconcileClass is how a remote torrent (or a local placement) compares
+// against local storage.
+type ReconcileClass string
+
+const (
+	ReconcileTracked   ReconcileClass = "tracked"   // On the debrid and tracked locally under the same ID
+	ReconcileOrphaned  ReconcileClass = "orphaned"  // On the debrid, unknown locally
+	ReconcileDuplicate ReconcileClass = "duplicate" // Same infohash already tracked under another ID or provider
+	ReconcileMissing   ReconcileClass = "missing"   // Tracked locally, gone from the debrid
+)
+
+type ReconcileAction string
+
+const (
+	ReconcileActionNone   ReconcileAction = ""
+	ReconcileActionAdopt  ReconcileAction = "adopt"
+	ReconcileActionDelete ReconcileAction = "delete"
+	ReconcileActionReAdd  ReconcileAction = "readd"
+	ReconcileActionRemove ReconcileAction = "remove"
+)
+
+// ReconcileItem is one finding of a reconciliation run. Tracked torrents are
+// only counted, not listed.
+type ReconcileItem struct {
+	Class     ReconcileClass  `json:"class"`
+	Provider  string          `json:"provider"`
+	TorrentID string          `json:"torrent_id,omitempty"`
+	InfoHash  string          `json:"infohash"`
+	Name      string          `json:"name"`
+	Size      int64           `json:"size,omitempty"`
+	Status    string          `json:"status,omitempty"`      // Remote torrent status
+	StandInID string          `json:"stand_in_id,omitempty"` // Remote copy of a missing placement
+	Action    ReconcileAction `json:"action,omitempty"`
+	Applied   bool            `json:"applied,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+type ReconcileRunStats struct {
+	Remote    int `json:"remote"`
+	Tracked   int `json:"tracked"`
+	Orphaned  int `json:"orphaned"`
+	Duplicate int `json:"duplicate"`
+	Missing   int `json:"missing"`
+	Applied   int `json:"applied"`
+	Failed    int `json:"failed"`
+}
+
+// ReconcileRun is the report of one reconciliation pass.
+type ReconcileRun struct {
+	ID          string            `json:"id"`
+	Trigger     RepairRunTrigger  `json:"trigger"`
+	Status      RepairRunStatus   `json:"status"`
+	DryRun      bool              `json:"dry_run"`
+	Providers   []string          `json:"providers,omitempty"`
+	StartedAt   time.Time         `json:"started_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	CompletedAt time.Time         `json:"completed_at"`
+	Stats       ReconcileRunStats `json:"stats"`
+	Items       []*ReconcileItem  `json:"items,omitempty"`
+	Errors      map[string]string `json:"errors,omitempty"` // Provider -> listing error
+	Error       string            `json:"error,omitempty"`
+}
+
+func (s *Storage) SaveReconcileRun(run *ReconcileRun) error {
+	if run == nil || run.ID == "" {
+		return fmt.Errorf("reconcile run is missing id")
+	}
+	run.UpdatedAt = time.Now()
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	return s.reconcile.Put(run.ID, data, nil)
+}
+
+func (s *Storage) GetReconcileRun(id string) (*ReconcileRun, error) {
+	if id == "" {
+		return nil, fmt.Errorf("reconcile run id is empty")
+	}
+	data, err := s.reconcile.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	var run ReconcileRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, err
+	}
+	if run.ID == "" {
+		run.ID = id
+	}
+	return &run, nil
+}
+
+// ListReconcileRuns returns runs sorted newest-first.
+func (s *Storage) ListReconcileRuns() ([]*ReconcileRun, error) {
+	runs := make([]*ReconcileRun, 0)
+	err := s.reconcile.ForEach(func(key string, value []byte) error {
+		var run ReconcileRun
+		if err := json.Unmarshal(value, &run); err != nil {
+			return nil
+		}
+		if run.ID == "" {
+			run.ID = key
+		}
+		runs = append(runs, &run)
+		return nil
+	})
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartedAt.After(runs[j].StartedAt)
+	})
+	return runs, err
+}
+
+// PruneReconcileRuns keeps the newest `keep` runs and deletes the rest.
+func (s *Storage) PruneReconcileRuns(keep int) error {
+	runs, err := s.ListReconcileRuns()
+	if err != nil {
+		return err
+	}
+	if keep <= 0 || len(runs) <= keep {
+		return nil
+	}
+	for _, run := range runs[keep:] {
+		if run.Status == RepairRunRunning {
+			continue
+		}
+		_ = s.reconcile.Delete(run.ID)
+	}
+	return nil
+}
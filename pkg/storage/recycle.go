@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	json "github.com/bytedance/sonic"
+	"google.golang.org/protobuf/proto"
+)
+
+// RecycledEntry is a deleted entry kept in the recycle bin until it is
+// restored or purged.
+type RecycledEntry struct {
+	InfoHash  string    `json:"infohash"`
+	Name      string    `json:"name"`
+	Category  string    `json:"category,omitempty"`
+	Protocol  string    `json:"protocol,omitempty"`
+	Size      int64     `json:"size,omitempty"`
+	Provider  string    `json:"provider,omitempty"` // Provider the entry was served from
+	DeletedAt time.Time `json:"deleted_at"`
+	Data      []byte    `json:"data,omitempty"` // The entry, protobuf-encoded
+}
+
+// Entry decodes the stored entry.
+func (r *RecycledEntry) Entry() (*Entry, error) {
+	var pb EntryProto
+	if err := proto.Unmarshal(r.Data, &pb); err != nil {
+		return nil, err
+	}
+	return ProtoToEntry(&pb), nil
+}
+
+// RecycleEntry moves an entry from the library into the recycle bin. Its
+// metainfo and NZB source are left in place for a restore.
+func (s *Storage) RecycleEntry(entry *Entry, deletedAt time.Time) error {
+	if entry == nil || entry.InfoHash == "" {
+		return fmt.Errorf("recycled entry is missing infohash")
+	}
+	data, err := proto.Marshal(EntryToProto(entry))
+	if err != nil {
+		return fmt.Errorf("failed to marshal entry: %w", err)
+	}
+	rec := RecycledEntry{
+		InfoHash:  entry.InfoHash,
+		Name:      entry.Name,
+		Category:  entry.Category,
+		Protocol:  string(entry.Protocol),
+		Size:      entry.Size,
+		Provider:  entry.ActiveProvider,
+		DeletedAt: deletedAt,
+		Data:      data,
+	}
+	value, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := s.recycleBin.Put(entry.InfoHash, value, nil); err != nil {
+		return err
+	}
+	return s.Delete(entry.InfoHash)
+}
+
+func (s *Storage) GetRecycledEntry(infohash string) (*RecycledEntry, error) {
+	infohash = strings.TrimSpace(infohash)
+	if infohash == "" {
+		return nil, fmt.Errorf("infohash is empty")
+	}
+	data, err := s.recycleBin.Get(infohash)
+	if err != nil {
+		return nil, err
+	}
+	var rec RecycledEntry
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// ListRecycledEntries returns the recycle bin, most recently deleted first,
+// without the encoded entries.
+func (s *Storage) ListRecycledEntries() ([]*RecycledEntry, error) {
+	recs := make([]*RecycledEntry, 0)
+	err := s.recycleBin.ForEach(func(key string, value []byte) error {
+		var rec RecycledEntry
+		if err := json.Unmarshal(value, &rec); err != nil {
+			s.logger.Warn().Err(err).Str("key", key).Msg("Skipping unreadable recycled entry")
+			return nil
+		}
+		rec.Data = nil
+		recs = append(recs, &rec)
+		return nil
+	})
+	sort.Slice(recs, func(i, j int) bool {
+		return recs[i].DeletedAt.After(recs[j].DeletedAt)
+	})
+	return recs, err
+}
+
+// DeleteRecycledEntry drops an entry from the recycle bin. Dropping one that
+// isn't there is not an error.
+func (s *Storage) DeleteRecycledEntry(infohash string) error {
+	infohash = strings.TrimSpace(infohash)
+	if infohash == "" || !s.recycleBin.Exists(infohash) {
+		return nil
+	}
+	return s.recycleBin.Delete(infohash)
+}
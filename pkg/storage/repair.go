@@ -290,7 +290,11 @@ func (s *Storage) SaveEntryHealth(state *EntryHealth) error {
 	}
 	// Index the status so CountEntryHealthByStatus can build its histogram
 	// straight from the in-memory index without decoding every record.
-	return s.repairState.Put(state.EntryName, data, &hybrid.EntryMeta{Status: string(state.Status)})
+	if err := s.repairState.Put(state.EntryName, data, &hybrid.EntryMeta{Status: string(state.Status)}); err != nil {
+		return err
+	}
+	s.notifyEntryHealthChanged(state.EntryName)
+	return nil
 }
 
 func (s *Storage) GetEntryHealth(entryName string) (*EntryHealth, error) {
@@ -328,7 +332,11 @@ func (s *Storage) DeleteEntryHealth(entryName string) error {
 	if entryName == "" || !s.repairState.Exists(entryName) {
 		return nil
 	}
-	return s.repairState.Delete(entryName)
+	if err := s.repairState.Delete(entryName); err != nil {
+		return err
+	}
+	s.notifyEntryHealthChanged(entryName)
+	return nil
 }
 
 // ClearEntryHealthByStatuses deletes persisted repair health records whose
@@ -476,6 +484,9 @@ func EntryItemRepairFingerprint(item *EntryItem) string {
 		if file.Deleted {
 			h.Write([]byte("deleted"))
 		}
+		if file.Unselected {
+			h.Write([]byte("unselected"))
+		}
 		if file.ByteRange != nil {
 			h.Write([]byte(strconv.FormatInt(file.ByteRange[0], 10)))
 			h.Write([]byte{':'})
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	json "github.com/bytedance/sonic"
+)
+
+// PlayRecord is the stream history of an entry.
+type PlayRecord struct {
+	LastPlayed time.Time `json:"last_played"`
+	LastFile   string    `json:"last_file,omitempty"`
+}
+
+func playKey(infohash string) string {
+	return strings.ToLower(strings.TrimSpace(infohash))
+}
+
+// RecordPlay notes that a file of an entry was streamed at t.
+func (s *Storage) RecordPlay(infohash, file string, t time.Time) error {
+	key := playKey(infohash)
+	if key == "" {
+		return fmt.Errorf("play is missing infohash")
+	}
+	data, err := json.Marshal(PlayRecord{LastPlayed: t, LastFile: file})
+	if err != nil {
+		return err
+	}
+	return s.plays.Put(key, data, nil)
+}
+
+// GetPlayRecords returns the stream history of every entry streamed at
+// least once, keyed by lowercase infohash.
+func (s *Storage) GetPlayRecords() (map[string]PlayRecord, error) {
+	records := make(map[string]PlayRecord)
+	err := s.plays.ForEach(func(key string, value []byte) error {
+		var rec PlayRecord
+		if err := json.Unmarshal(value, &rec); err != nil {
+			return nil
+		}
+		records[key] = rec
+		return nil
+	})
+	return records, err
+}
+
+func (s *Storage) DeletePlayRecord(infohash string) error {
+	key := playKey(infohash)
+	if key == "" || !s.plays.Exists(key) {
+		return nil
+	}
+	return s.plays.Delete(key)
+}
+
+// RetentionReason is the retention limit that selected an entry for deletion.
+type RetentionReason string
+
+const (
+	RetentionMaxAge       RetentionReason = "max_age"
+	RetentionUnreferenced RetentionReason = "unreferenced"
+	RetentionUnplayed     RetentionReason = "unplayed"
+	RetentionKeepLatest   RetentionReason = "keep_latest"
+)
+
+// RetentionItem is an entry a retention run deleted, or would delete.
+type RetentionItem struct {
+	Policy     string            `json:"policy"`
+	InfoHash   string            `json:"infohash"`
+	Name       string            `json:"name"`
+	Category   string            `json:"category,omitempty"`
+	Size       int64             `json:"size,omitempty"`
+	AddedOn    time.Time         `json:"added_on"`
+	LastPlayed *time.Time        `json:"last_played,omitempty"`
+	Reasons    []RetentionReason `json:"reasons"`
+	Deleted    bool              `json:"deleted,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+type RetentionRunStats struct {
+	Scanned int   `json:"scanned"` // Entries selected by at least one policy
+	Matched int   `json:"matched"` // Entries past a limit
+	Deleted int   `json:"deleted"`
+	Failed  int   `json:"failed"`
+	Freed   int64 `json:"freed"` // Bytes of the matched entries
+}
+
+// RetentionRun is the report of one retention pass.
+type RetentionRun struct {
+	ID          string            `json:"id"`
+	Trigger     RepairRunTrigger  `json:"trigger"`
+	Status      RepairRunStatus   `json:"status"`
+	DryRun      bool              `json:"dry_run"`
+	Policies    []string          `json:"policies,omitempty"`
+	StartedAt   time.Time         `json:"started_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	CompletedAt time.Time         `json:"completed_at"`
+	Stats       RetentionRunStats `json:"stats"`
+	Items       []*RetentionItem  `json:"items,omitempty"`
+	Errors      map[string]string `json:"errors,omitempty"` // Arr -> media listing error
+	Error       string            `json:"error,omitempty"`
+}
+
+func (s *Storage) SaveRetentionRun(run *RetentionRun) error {
+	if run == nil || run.ID == "" {
+		return fmt.Errorf("retention run is missing id")
+	}
+	run.UpdatedAt = time.Now()
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	return s.retention.Put(run.ID, data, nil)
+}
+
+func (s *Storage) GetRetentionRun(id string) (*RetentionRun, error) {
+	if id == "" {
+		return nil, fmt.Errorf("retention run id is empty")
+	}
+	data, err := s.retention.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	var run RetentionRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, err
+	}
+	if run.ID == "" {
+		run.ID = id
+	}
+	return &run, nil
+}
+
+// ListRetentionRuns returns runs sorted newest-first.
+func (s *Storage) ListRetentionRuns() ([]*RetentionRun, error) {
+	runs := make([]*RetentionRun, 0)
+	err := s.retention.ForEach(func(key string, value []byte) error {
+		var run RetentionRun
+		if err := json.Unmarshal(value, &run); err != nil {
+			return nil
+		}
+		if run.ID == "" {
+			run.ID = key
+		}
+		runs = append(runs, &run)
+		return nil
+	})
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartedAt.After(runs[j].StartedAt)
+	})
+	return runs, err
+}
+
+// PruneRetentionRuns keeps the newest `keep` runs and deletes the rest.
+func (s *Storage) PruneRetentionRuns(keep int) error {
+	runs, err := s.ListRetentionRuns()
+	if err != nil {
+		return err
+	}
+	if keep <= 0 || len(runs) <= keep {
+		return nil
+	}
+	for _, run := range runs[keep:] {
+		if run.Status == RepairRunRunning {
+			continue
+		}
+		_ = s.retention.Delete(run.ID)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -13,7 +14,7 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-var storeNames = []string{"entries", "queue", "items", "repair_state", "repair_runs"}
+var storeNames = []string{"entries", "queue", "items", "repair_state", "repair_runs", "metainfo", "switch_batches", "reconcile_runs", "nzb_sources", "entry_events", "cache_pins", "play_history", "retention_runs", "recycle_bin", "guardian_runs", "account_traffic"}
 
 // legacyStoreNames are buckets from the v1 repair system. They are removed
 // on startup so they don't accumulate dead data.
@@ -26,12 +27,27 @@ type Storage struct {
 	entryItems  *hybrid.Store
 	repairState *hybrid.Store
 	repairRuns  *hybrid.Store
+	metainfo    *hybrid.Store
+	switchBatch *hybrid.Store
+	reconcile   *hybrid.Store
+	nzbSources  *hybrid.Store
+	entryEvents *hybrid.Store
+	cachePins   *hybrid.Store
+	plays       *hybrid.Store
+	retention   *hybrid.Store
+	recycleBin  *hybrid.Store
+	guardian    *hybrid.Store
+	traffic     *hybrid.Store
 	dir         string
 	logger      zerolog.Logger
 
+	events eventLog
+
 	healthCountsMu      sync.Mutex
 	healthCounts        map[HealthStatus]int
 	healthCountsBuiltAt time.Time
+
+	observer atomic.Pointer[entryObserverRef]
 }
 
 
@@ -95,10 +111,25 @@ func NewStorage(dbPath string) (*Storage, error) {
 		entryItems:  itemStores["items"],
 		repairState: itemStores["repair_state"],
 		repairRuns:  itemStores["repair_runs"],
+		metainfo:    itemStores["metainfo"],
+		switchBatch: itemStores["switch_batches"],
+		reconcile:   itemStores["reconcile_runs"],
+		nzbSources:  itemStores["nzb_sources"],
+		entryEvents: itemStores["entry_events"],
+		cachePins:   itemStores["cache_pins"],
+		plays:       itemStores["play_history"],
+		retention:   itemStores["retention_runs"],
+		recycleBin:  itemStores["recycle_bin"],
+		guardian:    itemStores["guardian_runs"],
+		traffic:     itemStores["account_traffic"],
 		dir:         dbPath,
 		logger:      log,
 	}
 
+	if err := s.loadEntryEvents(); err != nil {
+		log.Warn().Err(err).Msg("Failed to load entry timelines")
+	}
+
 	if count, err := s.MigrateMetadata(); err != nil {
 		log.Warn().Err(err).Msg("Metadata migration failed")
 	} else if count > 0 {
@@ -110,7 +141,7 @@ func NewStorage(dbPath string) (*Storage, error) {
 
 func (s *Storage) Close() error {
 	var errs []error
-	stores := []*hybrid.Store{s.entries, s.queue, s.entryItems, s.repairState, s.repairRuns}
+	stores := []*hybrid.Store{s.entries, s.queue, s.entryItems, s.repairState, s.repairRuns, s.metainfo, s.switchBatch, s.reconcile, s.nzbSources, s.entryEvents, s.cachePins, s.plays, s.retention, s.recycleBin, s.guardian, s.traffic}
 	for _, store := range stores {
 		if store == nil {
 			continue
@@ -128,7 +159,7 @@ func (s *Storage) Close() error {
 // DiskSize returns the total on-disk size of all stores (O(1), no filesystem walk).
 func (s *Storage) DiskSize() int64 {
 	var size int64
-	for _, store := range []*hybrid.Store{s.entries, s.queue, s.entryItems, s.repairState, s.repairRuns} {
+	for _, store := range []*hybrid.Store{s.entries, s.queue, s.entryItems, s.repairState, s.repairRuns, s.metainfo, s.switchBatch, s.reconcile, s.nzbSources, s.entryEvents, s.cachePins, s.plays, s.retention, s.recycleBin, s.guardian, s.traffic} {
 		if store != nil {
 			size += store.DiskSize()
 		}
@@ -136,6 +167,16 @@ func (s *Storage) DiskSize() int64 {
 	return size
 }
 
+// Ping reports an error when any store is closed
+func (s *Storage) Ping() error {
+	for _, store := range []*hybrid.Store{s.entries, s.queue, s.entryItems, s.repairState, s.repairRuns, s.metainfo, s.switchBatch, s.reconcile, s.nzbSources, s.entryEvents, s.cachePins, s.plays, s.retention, s.recycleBin, s.guardian, s.traffic} {
+		if store == nil || store.IsClosed() {
+			return hybrid.ErrStoreClosed
+		}
+	}
+	return nil
+}
+
 // SaveMigrationStatus saves the system migration status
 func (s *Storage) SaveMigrationStatus(status *SystemMigrationStatus) error {
 	pb := SystemMigrationStatusToProto(status)
@@ -170,6 +211,17 @@ func (s *Storage) copyFrom(other *Storage) error {
 		{"items", other.entryItems, s.entryItems},
 		{"repair_state", other.repairState, s.repairState},
 		{"repair_runs", other.repairRuns, s.repairRuns},
+		{"metainfo", other.metainfo, s.metainfo},
+		{"switch_batches", other.switchBatch, s.switchBatch},
+		{"reconcile_runs", other.reconcile, s.reconcile},
+		{"nzb_sources", other.nzbSources, s.nzbSources},
+		{"entry_events", other.entryEvents, s.entryEvents},
+		{"cache_pins", other.cachePins, s.cachePins},
+		{"play_history", other.plays, s.plays},
+		{"retention_runs", other.retention, s.retention},
+		{"recycle_bin", other.recycleBin, s.recycleBin},
+		{"guardian_runs", other.guardian, s.guardian},
+		{"account_traffic", other.traffic, s.traffic},
 	}
 
 	for _, p := range pairs {
@@ -182,5 +234,5 @@ func (s *Storage) copyFrom(other *Storage) error {
 			return fmt.Errorf("failed to copy %s: %w", p.name, err)
 		}
 	}
-	return nil
+	return s.loadEntryEvents()
 }
@@ -32,6 +32,8 @@ type FileProto struct {
 	HasByteRange   bool                   `protobuf:"varint,7,opt,name=has_byte_range,json=hasByteRange,proto3" json:"has_byte_range,omitempty"`
 	Deleted        bool                   `protobuf:"varint,8,opt,name=deleted,proto3" json:"deleted,omitempty"`
 	InfoHash       string                 `protobuf:"bytes,9,opt,name=info_hash,json=infoHash,proto3" json:"info_hash,omitempty"`
+	Media          *MediaInfoProto        `protobuf:"bytes,10,opt,name=media,proto3" json:"media,omitempty"`
+	Unselected     bool                   `protobuf:"varint,11,opt,name=unselected,proto3" json:"unselected,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -129,6 +131,144 @@ func (x *FileProto) GetInfoHash() string {
 	return ""
 }
 
+func (x *FileProto) GetMedia() *MediaInfoProto {
+	if x != nil {
+		return x.Media
+	}
+	return nil
+}
+
+func (x *FileProto) GetUnselected() bool {
+	if x != nil {
+		return x.Unselected
+	}
+	return false
+}
+
+type MediaInfoProto struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Container         string                 `protobuf:"bytes,1,opt,name=container,proto3" json:"container,omitempty"`
+	DurationSeconds   float64                `protobuf:"fixed64,2,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	VideoCodec        string                 `protobuf:"bytes,3,opt,name=video_codec,json=videoCodec,proto3" json:"video_codec,omitempty"`
+	Width             int32                  `protobuf:"varint,4,opt,name=width,proto3" json:"width,omitempty"`
+	Height            int32                  `protobuf:"varint,5,opt,name=height,proto3" json:"height,omitempty"`
+	AudioCodecs       []string               `protobuf:"bytes,6,rep,name=audio_codecs,json=audioCodecs,proto3" json:"audio_codecs,omitempty"`
+	AudioLanguages    []string               `protobuf:"bytes,7,rep,name=audio_languages,json=audioLanguages,proto3" json:"audio_languages,omitempty"`
+	SubtitleLanguages []string               `protobuf:"bytes,8,rep,name=subtitle_languages,json=subtitleLanguages,proto3" json:"subtitle_languages,omitempty"`
+	Status            string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	Reason            string                 `protobuf:"bytes,10,opt,name=reason,proto3" json:"reason,omitempty"`
+	ProbedAtUnix      int64                  `protobuf:"varint,11,opt,name=probed_at_unix,json=probedAtUnix,proto3" json:"probed_at_unix,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *MediaInfoProto) Reset() {
+	*x = MediaInfoProto{}
+	mi := &file_pkg_storage_storage_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MediaInfoProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MediaInfoProto) ProtoMessage() {}
+
+func (x *MediaInfoProto) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_storage_storage_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MediaInfoProto.ProtoReflect.Descriptor instead.
+func (*MediaInfoProto) Descriptor() ([]byte, []int) {
+	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MediaInfoProto) GetContainer() string {
+	if x != nil {
+		return x.Container
+	}
+	return ""
+}
+
+func (x *MediaInfoProto) GetDurationSeconds() float64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+func (x *MediaInfoProto) GetVideoCodec() string {
+	if x != nil {
+		return x.VideoCodec
+	}
+	return ""
+}
+
+func (x *MediaInfoProto) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *MediaInfoProto) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *MediaInfoProto) GetAudioCodecs() []string {
+	if x != nil {
+		return x.AudioCodecs
+	}
+	return nil
+}
+
+func (x *MediaInfoProto) GetAudioLanguages() []string {
+	if x != nil {
+		return x.AudioLanguages
+	}
+	return nil
+}
+
+func (x *MediaInfoProto) GetSubtitleLanguages() []string {
+	if x != nil {
+		return x.SubtitleLanguages
+	}
+	return nil
+}
+
+func (x *MediaInfoProto) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *MediaInfoProto) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *MediaInfoProto) GetProbedAtUnix() int64 {
+	if x != nil {
+		return x.ProbedAtUnix
+	}
+	return 0
+}
+
 type ProviderFileProto struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -140,7 +280,7 @@ type ProviderFileProto struct {
 
 func (x *ProviderFileProto) Reset() {
 	*x = ProviderFileProto{}
-	mi := &file_pkg_storage_storage_proto_msgTypes[1]
+	mi := &file_pkg_storage_storage_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -152,7 +292,7 @@ func (x *ProviderFileProto) String() string {
 func (*ProviderFileProto) ProtoMessage() {}
 
 func (x *ProviderFileProto) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_storage_storage_proto_msgTypes[1]
+	mi := &file_pkg_storage_storage_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -165,7 +305,7 @@ func (x *ProviderFileProto) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProviderFileProto.ProtoReflect.Descriptor instead.
 func (*ProviderFileProto) Descriptor() ([]byte, []int) {
-	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{1}
+	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ProviderFileProto) GetId() string {
@@ -207,7 +347,7 @@ type ProviderEntryProto struct {
 
 func (x *ProviderEntryProto) Reset() {
 	*x = ProviderEntryProto{}
-	mi := &file_pkg_storage_storage_proto_msgTypes[2]
+	mi := &file_pkg_storage_storage_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -219,7 +359,7 @@ func (x *ProviderEntryProto) String() string {
 func (*ProviderEntryProto) ProtoMessage() {}
 
 func (x *ProviderEntryProto) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_storage_storage_proto_msgTypes[2]
+	mi := &file_pkg_storage_storage_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -232,7 +372,7 @@ func (x *ProviderEntryProto) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProviderEntryProto.ProtoReflect.Descriptor instead.
 func (*ProviderEntryProto) Descriptor() ([]byte, []int) {
-	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{2}
+	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ProviderEntryProto) GetProvider() string {
@@ -346,13 +486,20 @@ type EntryProto struct {
 	ErrorCount        int32                          `protobuf:"varint,37,opt,name=error_count,json=errorCount,proto3" json:"error_count,omitempty"`
 	LastErrorTimeUnix int64                          `protobuf:"varint,38,opt,name=last_error_time_unix,json=lastErrorTimeUnix,proto3" json:"last_error_time_unix,omitempty"`
 	HasLastErrorTime  bool                           `protobuf:"varint,39,opt,name=has_last_error_time,json=hasLastErrorTime,proto3" json:"has_last_error_time,omitempty"`
+	FileSelection     *FileSelectionProto            `protobuf:"bytes,40,opt,name=file_selection,json=fileSelection,proto3" json:"file_selection,omitempty"`
+	NameOverride      string                         `protobuf:"bytes,41,opt,name=name_override,json=nameOverride,proto3" json:"name_override,omitempty"`
+	FileRenames       map[string]string              `protobuf:"bytes,42,rep,name=file_renames,json=fileRenames,proto3" json:"file_renames,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Priority          int32                          `protobuf:"varint,43,opt,name=priority,proto3" json:"priority,omitempty"`
+	PpParameters      map[string]string              `protobuf:"bytes,44,rep,name=pp_parameters,json=ppParameters,proto3" json:"pp_parameters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Profile           string                         `protobuf:"bytes,45,opt,name=profile,proto3" json:"profile,omitempty"`
+	Folders           []string                       `protobuf:"bytes,46,rep,name=folders,proto3" json:"folders,omitempty"`
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
 
 func (x *EntryProto) Reset() {
 	*x = EntryProto{}
-	mi := &file_pkg_storage_storage_proto_msgTypes[3]
+	mi := &file_pkg_storage_storage_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -364,7 +511,7 @@ func (x *EntryProto) String() string {
 func (*EntryProto) ProtoMessage() {}
 
 func (x *EntryProto) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_storage_storage_proto_msgTypes[3]
+	mi := &file_pkg_storage_storage_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -377,7 +524,7 @@ func (x *EntryProto) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EntryProto.ProtoReflect.Descriptor instead.
 func (*EntryProto) Descriptor() ([]byte, []int) {
-	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{3}
+	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *EntryProto) GetProtocol() string {
@@ -653,6 +800,155 @@ func (x *EntryProto) GetHasLastErrorTime() bool {
 	return false
 }
 
+func (x *EntryProto) GetFileSelection() *FileSelectionProto {
+	if x != nil {
+		return x.FileSelection
+	}
+	return nil
+}
+
+func (x *EntryProto) GetNameOverride() string {
+	if x != nil {
+		return x.NameOverride
+	}
+	return ""
+}
+
+func (x *EntryProto) GetFileRenames() map[string]string {
+	if x != nil {
+		return x.FileRenames
+	}
+	return nil
+}
+
+func (x *EntryProto) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *EntryProto) GetPpParameters() map[string]string {
+	if x != nil {
+		return x.PpParameters
+	}
+	return nil
+}
+
+func (x *EntryProto) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *EntryProto) GetFolders() []string {
+	if x != nil {
+		return x.Folders
+	}
+	return nil
+}
+
+type FileSelectionProto struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Include       []string               `protobuf:"bytes,1,rep,name=include,proto3" json:"include,omitempty"`
+	Exclude       []string               `protobuf:"bytes,2,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	SkipExtras    bool                   `protobuf:"varint,3,opt,name=skip_extras,json=skipExtras,proto3" json:"skip_extras,omitempty"`
+	Seasons       string                 `protobuf:"bytes,4,opt,name=seasons,proto3" json:"seasons,omitempty"`
+	Episodes      string                 `protobuf:"bytes,5,opt,name=episodes,proto3" json:"episodes,omitempty"`
+	LargestOnly   bool                   `protobuf:"varint,6,opt,name=largest_only,json=largestOnly,proto3" json:"largest_only,omitempty"`
+	Keep          []string               `protobuf:"bytes,7,rep,name=keep,proto3" json:"keep,omitempty"`
+	Skip          []string               `protobuf:"bytes,8,rep,name=skip,proto3" json:"skip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileSelectionProto) Reset() {
+	*x = FileSelectionProto{}
+	mi := &file_pkg_storage_storage_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileSelectionProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileSelectionProto) ProtoMessage() {}
+
+func (x *FileSelectionProto) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_storage_storage_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileSelectionProto.ProtoReflect.Descriptor instead.
+func (*FileSelectionProto) Descriptor() ([]byte, []int) {
+	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FileSelectionProto) GetInclude() []string {
+	if x != nil {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *FileSelectionProto) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+func (x *FileSelectionProto) GetSkipExtras() bool {
+	if x != nil {
+		return x.SkipExtras
+	}
+	return false
+}
+
+func (x *FileSelectionProto) GetSeasons() string {
+	if x != nil {
+		return x.Seasons
+	}
+	return ""
+}
+
+func (x *FileSelectionProto) GetEpisodes() string {
+	if x != nil {
+		return x.Episodes
+	}
+	return ""
+}
+
+func (x *FileSelectionProto) GetLargestOnly() bool {
+	if x != nil {
+		return x.LargestOnly
+	}
+	return false
+}
+
+func (x *FileSelectionProto) GetKeep() []string {
+	if x != nil {
+		return x.Keep
+	}
+	return nil
+}
+
+func (x *FileSelectionProto) GetSkip() []string {
+	if x != nil {
+		return x.Skip
+	}
+	return nil
+}
+
 type EntryItemProto struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -664,7 +960,7 @@ type EntryItemProto struct {
 
 func (x *EntryItemProto) Reset() {
 	*x = EntryItemProto{}
-	mi := &file_pkg_storage_storage_proto_msgTypes[4]
+	mi := &file_pkg_storage_storage_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -676,7 +972,7 @@ func (x *EntryItemProto) String() string {
 func (*EntryItemProto) ProtoMessage() {}
 
 func (x *EntryItemProto) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_storage_storage_proto_msgTypes[4]
+	mi := &file_pkg_storage_storage_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -689,7 +985,7 @@ func (x *EntryItemProto) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EntryItemProto.ProtoReflect.Descriptor instead.
 func (*EntryItemProto) Descriptor() ([]byte, []int) {
-	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{4}
+	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *EntryItemProto) GetName() string {
@@ -732,7 +1028,7 @@ type ContentFileProto struct {
 
 func (x *ContentFileProto) Reset() {
 	*x = ContentFileProto{}
-	mi := &file_pkg_storage_storage_proto_msgTypes[5]
+	mi := &file_pkg_storage_storage_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -744,7 +1040,7 @@ func (x *ContentFileProto) String() string {
 func (*ContentFileProto) ProtoMessage() {}
 
 func (x *ContentFileProto) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_storage_storage_proto_msgTypes[5]
+	mi := &file_pkg_storage_storage_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -757,7 +1053,7 @@ func (x *ContentFileProto) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ContentFileProto.ProtoReflect.Descriptor instead.
 func (*ContentFileProto) Descriptor() ([]byte, []int) {
-	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{5}
+	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ContentFileProto) GetName() string {
@@ -856,7 +1152,7 @@ type JobProto struct {
 
 func (x *JobProto) Reset() {
 	*x = JobProto{}
-	mi := &file_pkg_storage_storage_proto_msgTypes[6]
+	mi := &file_pkg_storage_storage_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -868,7 +1164,7 @@ func (x *JobProto) String() string {
 func (*JobProto) ProtoMessage() {}
 
 func (x *JobProto) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_storage_storage_proto_msgTypes[6]
+	mi := &file_pkg_storage_storage_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -881,7 +1177,7 @@ func (x *JobProto) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JobProto.ProtoReflect.Descriptor instead.
 func (*JobProto) Descriptor() ([]byte, []int) {
-	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{6}
+	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *JobProto) GetId() string {
@@ -970,7 +1266,7 @@ type BrokenItemsProto struct {
 
 func (x *BrokenItemsProto) Reset() {
 	*x = BrokenItemsProto{}
-	mi := &file_pkg_storage_storage_proto_msgTypes[7]
+	mi := &file_pkg_storage_storage_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -982,7 +1278,7 @@ func (x *BrokenItemsProto) String() string {
 func (*BrokenItemsProto) ProtoMessage() {}
 
 func (x *BrokenItemsProto) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_storage_storage_proto_msgTypes[7]
+	mi := &file_pkg_storage_storage_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -995,7 +1291,7 @@ func (x *BrokenItemsProto) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BrokenItemsProto.ProtoReflect.Descriptor instead.
 func (*BrokenItemsProto) Descriptor() ([]byte, []int) {
-	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{7}
+	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *BrokenItemsProto) GetFiles() []*ContentFileProto {
@@ -1025,7 +1321,7 @@ type SwitcherJobProto struct {
 
 func (x *SwitcherJobProto) Reset() {
 	*x = SwitcherJobProto{}
-	mi := &file_pkg_storage_storage_proto_msgTypes[8]
+	mi := &file_pkg_storage_storage_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1037,7 +1333,7 @@ func (x *SwitcherJobProto) String() string {
 func (*SwitcherJobProto) ProtoMessage() {}
 
 func (x *SwitcherJobProto) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_storage_storage_proto_msgTypes[8]
+	mi := &file_pkg_storage_storage_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1050,7 +1346,7 @@ func (x *SwitcherJobProto) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SwitcherJobProto.ProtoReflect.Descriptor instead.
 func (*SwitcherJobProto) Descriptor() ([]byte, []int) {
-	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{8}
+	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *SwitcherJobProto) GetId() string {
@@ -1152,7 +1448,7 @@ type SystemMigrationStatusProto struct {
 
 func (x *SystemMigrationStatusProto) Reset() {
 	*x = SystemMigrationStatusProto{}
-	mi := &file_pkg_storage_storage_proto_msgTypes[9]
+	mi := &file_pkg_storage_storage_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1164,7 +1460,7 @@ func (x *SystemMigrationStatusProto) String() string {
 func (*SystemMigrationStatusProto) ProtoMessage() {}
 
 func (x *SystemMigrationStatusProto) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_storage_storage_proto_msgTypes[9]
+	mi := &file_pkg_storage_storage_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1177,7 +1473,7 @@ func (x *SystemMigrationStatusProto) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SystemMigrationStatusProto.ProtoReflect.Descriptor instead.
 func (*SystemMigrationStatusProto) Descriptor() ([]byte, []int) {
-	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{9}
+	return file_pkg_storage_storage_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *SystemMigrationStatusProto) GetRunning() bool {
@@ -1233,7 +1529,7 @@ var File_pkg_storage_storage_proto protoreflect.FileDescriptor
 
 const file_pkg_storage_storage_proto_rawDesc = "" +
 	"\n" +
-	"\x19pkg/storage/storage.proto\x12\astorage\"\x98\x02\n" +
+	"\x19pkg/storage/storage.proto\x12\astorage\"\xe7\x02\n" +
 	"\tFileProto\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
 	"\x04path\x18\x02 \x01(\tR\x04path\x12\"\n" +
@@ -1243,7 +1539,26 @@ const file_pkg_storage_storage_proto_rawDesc = "" +
 	"\x0ebyte_range_end\x18\x06 \x01(\x03R\fbyteRangeEnd\x12$\n" +
 	"\x0ehas_byte_range\x18\a \x01(\bR\fhasByteRange\x12\x18\n" +
 	"\adeleted\x18\b \x01(\bR\adeleted\x12\x1b\n" +
-	"\tinfo_hash\x18\t \x01(\tR\binfoHash\"K\n" +
+	"\tinfo_hash\x18\t \x01(\tR\binfoHash\x12-\n" +
+	"\x05media\x18\n" +
+	" \x01(\v2\x17.storage.MediaInfoProtoR\x05media\x12\x1e\n" +
+	"\n" +
+	"unselected\x18\v \x01(\bR\n" +
+	"unselected\"\xf9\x02\n" +
+	"\x0eMediaInfoProto\x12\x1c\n" +
+	"\tcontainer\x18\x01 \x01(\tR\tcontainer\x12)\n" +
+	"\x10duration_seconds\x18\x02 \x01(\x01R\x0fdurationSeconds\x12\x1f\n" +
+	"\vvideo_codec\x18\x03 \x01(\tR\n" +
+	"videoCodec\x12\x14\n" +
+	"\x05width\x18\x04 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x05 \x01(\x05R\x06height\x12!\n" +
+	"\faudio_codecs\x18\x06 \x03(\tR\vaudioCodecs\x12'\n" +
+	"\x0faudio_languages\x18\a \x03(\tR\x0eaudioLanguages\x12-\n" +
+	"\x12subtitle_languages\x18\b \x03(\tR\x11subtitleLanguages\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\n" +
+	" \x01(\tR\x06reason\x12$\n" +
+	"\x0eprobed_at_unix\x18\v \x01(\x03R\fprobedAtUnix\"K\n" +
 	"\x11ProviderFileProto\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04link\x18\x02 \x01(\tR\x04link\x12\x12\n" +
@@ -1263,7 +1578,7 @@ const file_pkg_storage_storage_proto_rawDesc = "" +
 	"\n" +
 	"FilesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
-	"\x05value\x18\x02 \x01(\v2\x1a.storage.ProviderFileProtoR\x05value:\x028\x01\"\xee\v\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.storage.ProviderFileProtoR\x05value:\x028\x01\"\xbd\x0f\n" +
 	"\n" +
 	"EntryProto\x12\x1a\n" +
 	"\bprotocol\x18\x01 \x01(\tR\bprotocol\x12\x1b\n" +
@@ -1309,14 +1624,37 @@ const file_pkg_storage_storage_proto_rawDesc = "" +
 	"\verror_count\x18% \x01(\x05R\n" +
 	"errorCount\x12/\n" +
 	"\x14last_error_time_unix\x18& \x01(\x03R\x11lastErrorTimeUnix\x12-\n" +
-	"\x13has_last_error_time\x18' \x01(\bR\x10hasLastErrorTime\x1aY\n" +
+	"\x13has_last_error_time\x18' \x01(\bR\x10hasLastErrorTime\x12B\n" +
+	"\x0efile_selection\x18( \x01(\v2\x1b.storage.FileSelectionProtoR\rfileSelection\x12#\n" +
+	"\rname_override\x18) \x01(\tR\fnameOverride\x12G\n" +
+	"\ffile_renames\x18* \x03(\v2$.storage.EntryProto.FileRenamesEntryR\vfileRenames\x12\x1a\n" +
+	"\bpriority\x18+ \x01(\x05R\bpriority\x12J\n" +
+	"\rpp_parameters\x18, \x03(\v2%.storage.EntryProto.PpParametersEntryR\fppParameters\x12\x18\n" +
+	"\aprofile\x18- \x01(\tR\aprofile\x12\x18\n" +
+	"\afolders\x18. \x03(\tR\afolders\x1aY\n" +
 	"\x0eProvidersEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x121\n" +
 	"\x05value\x18\x02 \x01(\v2\x1b.storage.ProviderEntryProtoR\x05value:\x028\x01\x1aL\n" +
 	"\n" +
 	"FilesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12(\n" +
-	"\x05value\x18\x02 \x01(\v2\x12.storage.FileProtoR\x05value:\x028\x01\"\xc0\x01\n" +
+	"\x05value\x18\x02 \x01(\v2\x12.storage.FileProtoR\x05value:\x028\x01\x1a>\n" +
+	"\x10FileRenamesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a?\n" +
+	"\x11PpParametersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xea\x01\n" +
+	"\x12FileSelectionProto\x12\x18\n" +
+	"\ainclude\x18\x01 \x03(\tR\ainclude\x12\x18\n" +
+	"\aexclude\x18\x02 \x03(\tR\aexclude\x12\x1f\n" +
+	"\vskip_extras\x18\x03 \x01(\bR\n" +
+	"skipExtras\x12\x18\n" +
+	"\aseasons\x18\x04 \x01(\tR\aseasons\x12\x1a\n" +
+	"\bepisodes\x18\x05 \x01(\tR\bepisodes\x12!\n" +
+	"\flargest_only\x18\x06 \x01(\bR\vlargestOnly\x12\x12\n" +
+	"\x04keep\x18\a \x03(\tR\x04keep\x12\x12\n" +
+	"\x04skip\x18\b \x03(\tR\x04skip\"\xc0\x01\n" +
 	"\x0eEntryItemProto\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x128\n" +
 	"\x05files\x18\x02 \x03(\v2\".storage.EntryItemProto.FilesEntryR\x05files\x12\x12\n" +
@@ -1395,41 +1733,49 @@ func file_pkg_storage_storage_proto_rawDescGZIP() []byte {
 	return file_pkg_storage_storage_proto_rawDescData
 }
 
-var file_pkg_storage_storage_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_pkg_storage_storage_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_pkg_storage_storage_proto_goTypes = []any{
 	(*FileProto)(nil),                  // 0: storage.FileProto
-	(*ProviderFileProto)(nil),          // 1: storage.ProviderFileProto
-	(*ProviderEntryProto)(nil),         // 2: storage.ProviderEntryProto
-	(*EntryProto)(nil),                 // 3: storage.EntryProto
-	(*EntryItemProto)(nil),             // 4: storage.EntryItemProto
-	(*ContentFileProto)(nil),           // 5: storage.ContentFileProto
-	(*JobProto)(nil),                   // 6: storage.JobProto
-	(*BrokenItemsProto)(nil),           // 7: storage.BrokenItemsProto
-	(*SwitcherJobProto)(nil),           // 8: storage.SwitcherJobProto
-	(*SystemMigrationStatusProto)(nil), // 9: storage.SystemMigrationStatusProto
-	nil,                                // 10: storage.ProviderEntryProto.FilesEntry
-	nil,                                // 11: storage.EntryProto.ProvidersEntry
-	nil,                                // 12: storage.EntryProto.FilesEntry
-	nil,                                // 13: storage.EntryItemProto.FilesEntry
-	nil,                                // 14: storage.JobProto.BrokenItemsEntry
+	(*MediaInfoProto)(nil),             // 1: storage.MediaInfoProto
+	(*ProviderFileProto)(nil),          // 2: storage.ProviderFileProto
+	(*ProviderEntryProto)(nil),         // 3: storage.ProviderEntryProto
+	(*EntryProto)(nil),                 // 4: storage.EntryProto
+	(*FileSelectionProto)(nil),         // 5: storage.FileSelectionProto
+	(*EntryItemProto)(nil),             // 6: storage.EntryItemProto
+	(*ContentFileProto)(nil),           // 7: storage.ContentFileProto
+	(*JobProto)(nil),                   // 8: storage.JobProto
+	(*BrokenItemsProto)(nil),           // 9: storage.BrokenItemsProto
+	(*SwitcherJobProto)(nil),           // 10: storage.SwitcherJobProto
+	(*SystemMigrationStatusProto)(nil), // 11: storage.SystemMigrationStatusProto
+	nil,                                // 12: storage.ProviderEntryProto.FilesEntry
+	nil,                                // 13: storage.EntryProto.ProvidersEntry
+	nil,                                // 14: storage.EntryProto.FilesEntry
+	nil,                                // 15: storage.EntryProto.FileRenamesEntry
+	nil,                                // 16: storage.EntryProto.PpParametersEntry
+	nil,                                // 17: storage.EntryItemProto.FilesEntry
+	nil,                                // 18: storage.JobProto.BrokenItemsEntry
 }
 var file_pkg_storage_storage_proto_depIdxs = []int32{
-	10, // 0: storage.ProviderEntryProto.files:type_name -> storage.ProviderEntryProto.FilesEntry
-	11, // 1: storage.EntryProto.providers:type_name -> storage.EntryProto.ProvidersEntry
-	12, // 2: storage.EntryProto.files:type_name -> storage.EntryProto.FilesEntry
-	13, // 3: storage.EntryItemProto.files:type_name -> storage.EntryItemProto.FilesEntry
-	14, // 4: storage.JobProto.broken_items:type_name -> storage.JobProto.BrokenItemsEntry
-	5,  // 5: storage.BrokenItemsProto.files:type_name -> storage.ContentFileProto
-	1,  // 6: storage.ProviderEntryProto.FilesEntry.value:type_name -> storage.ProviderFileProto
-	2,  // 7: storage.EntryProto.ProvidersEntry.value:type_name -> storage.ProviderEntryProto
-	0,  // 8: storage.EntryProto.FilesEntry.value:type_name -> storage.FileProto
-	0,  // 9: storage.EntryItemProto.FilesEntry.value:type_name -> storage.FileProto
-	7,  // 10: storage.JobProto.BrokenItemsEntry.value:type_name -> storage.BrokenItemsProto
-	11, // [11:11] is the sub-list for method output_type
-	11, // [11:11] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	1,  // 0: storage.FileProto.media:type_name -> storage.MediaInfoProto
+	12, // 1: storage.ProviderEntryProto.files:type_name -> storage.ProviderEntryProto.FilesEntry
+	13, // 2: storage.EntryProto.providers:type_name -> storage.EntryProto.ProvidersEntry
+	14, // 3: storage.EntryProto.files:type_name -> storage.EntryProto.FilesEntry
+	5,  // 4: storage.EntryProto.file_selection:type_name -> storage.FileSelectionProto
+	15, // 5: storage.EntryProto.file_renames:type_name -> storage.EntryProto.FileRenamesEntry
+	16, // 6: storage.EntryProto.pp_parameters:type_name -> storage.EntryProto.PpParametersEntry
+	17, // 7: storage.EntryItemProto.files:type_name -> storage.EntryItemProto.FilesEntry
+	18, // 8: storage.JobProto.broken_items:type_name -> storage.JobProto.BrokenItemsEntry
+	7,  // 9: storage.BrokenItemsProto.files:type_name -> storage.ContentFileProto
+	2,  // 10: storage.ProviderEntryProto.FilesEntry.value:type_name -> storage.ProviderFileProto
+	3,  // 11: storage.EntryProto.ProvidersEntry.value:type_name -> storage.ProviderEntryProto
+	0,  // 12: storage.EntryProto.FilesEntry.value:type_name -> storage.FileProto
+	0,  // 13: storage.EntryItemProto.FilesEntry.value:type_name -> storage.FileProto
+	9,  // 14: storage.JobProto.BrokenItemsEntry.value:type_name -> storage.BrokenItemsProto
+	15, // [15:15] is the sub-list for method output_type
+	15, // [15:15] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
 }
 
 func init() { file_pkg_storage_storage_proto_init() }
@@ -1443,7 +1789,7 @@ func file_pkg_storage_storage_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_storage_storage_proto_rawDesc), len(file_pkg_storage_storage_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   15,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
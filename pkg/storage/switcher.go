@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	json "github.com/bytedance/sonic"
+)
+
+// SwitchBatchFilter selects the entries a bulk migration moves. Empty fields
+// match everything.
+type SwitchBatchFilter struct {
+	Provider string `json:"provider,omitempty"` // Active provider the entry is served from
+	Category string `json:"category,omitempty"`
+	Tag      string `json:"tag,omitempty"`
+}
+
+// Matches reports whether an entry falls inside the filter.
+func (f SwitchBatchFilter) Matches(e *Entry) bool {
+	if f.Provider != "" && e.ActiveProvider != f.Provider {
+		return false
+	}
+	if f.Category != "" && e.Category != f.Category {
+		return false
+	}
+	if f.Tag != "" {
+		for _, tag := range e.Tags {
+			if tag == f.Tag {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+type SwitchBatchStats struct {
+	Total     int `json:"total"`
+	Pending   int `json:"pending"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+	Cancelled int `json:"cancelled"`
+}
+
+// SwitchBatch is a persisted bulk migration. Each item is a SwitcherJob for
+// one entry; items still pending when the process stops are picked up again
+// on the next start, so a batch over thousands of entries survives restarts.
+type SwitchBatch struct {
+	ID             string            `json:"id"`
+	Filter         SwitchBatchFilter `json:"filter"`
+	TargetProvider string            `json:"target_provider"`
+	KeepOld        bool              `json:"keep_old"`
+	DryRun         bool              `json:"dry_run"`
+	Concurrency    int               `json:"concurrency"`
+	Status         SwitcherStatus    `json:"status"`
+	Stats          SwitchBatchStats  `json:"stats"`
+	Items          []*SwitcherJob    `json:"items,omitempty"`
+	Error          string            `json:"error,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	CompletedAt    *time.Time        `json:"completed_at,omitempty"`
+}
+
+// Recount rebuilds Stats from the item statuses.
+func (b *SwitchBatch) Recount() {
+	stats := SwitchBatchStats{Total: len(b.Items)}
+	for _, item := range b.Items {
+		switch item.Status {
+		case SwitcherStatusCompleted:
+			stats.Completed++
+		case SwitcherStatusFailed:
+			stats.Failed++
+		case SwitcherStatusSkipped:
+			stats.Skipped++
+		case SwitcherStatusCancelled:
+			stats.Cancelled++
+		default:
+			stats.Pending++
+		}
+	}
+	b.Stats = stats
+}
+
+// Finished reports whether the batch can no longer make progress.
+func (b *SwitchBatch) Finished() bool {
+	switch b.Status {
+	case SwitcherStatusCompleted, SwitcherStatusFailed, SwitcherStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (s *Storage) SaveSwitchBatch(batch *SwitchBatch) error {
+	if batch == nil || batch.ID == "" {
+		return fmt.Errorf("switch batch is missing id")
+	}
+	batch.UpdatedAt = time.Now()
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	return s.switchBatch.Put(batch.ID, data, nil)
+}
+
+func (s *Storage) GetSwitchBatch(id string) (*SwitchBatch, error) {
+	if id == "" {
+		return nil, fmt.Errorf("switch batch id is empty")
+	}
+	data, err := s.switchBatch.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	var batch SwitchBatch
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, err
+	}
+	if batch.ID == "" {
+		batch.ID = id
+	}
+	return &batch, nil
+}
+
+// ListSwitchBatches returns batches sorted newest-first.
+func (s *Storage) ListSwitchBatches() ([]*SwitchBatch, error) {
+	batches := make([]*SwitchBatch, 0)
+	err := s.switchBatch.ForEach(func(key string, value []byte) error {
+		var batch SwitchBatch
+		if err := json.Unmarshal(value, &batch); err != nil {
+			return nil
+		}
+		if batch.ID == "" {
+			batch.ID = key
+		}
+		batches = append(batches, &batch)
+		return nil
+	})
+	sort.Slice(batches, func(i, j int) bool {
+		return batches[i].CreatedAt.After(batches[j].CreatedAt)
+	})
+	return batches, err
+}
+
+func (s *Storage) DeleteSwitchBatch(id string) error {
+	if id == "" {
+		return nil
+	}
+	return s.switchBatch.Delete(id)
+}
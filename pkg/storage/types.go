@@ -1,10 +1,12 @@
 package storage
 
 import (
+	"cmp"
 	"fmt"
 	"math"
 	"path"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/sirrobot01/decypharr/internal/config"
@@ -24,6 +26,8 @@ const (
 	SwitcherStatusCompleted  SwitcherStatus = "completed"
 	SwitcherStatusFailed     SwitcherStatus = "failed"
 	SwitcherStatusCancelled  SwitcherStatus = "cancelled"
+	SwitcherStatusPaused     SwitcherStatus = "paused"
+	SwitcherStatusSkipped    SwitcherStatus = "skipped"
 
 	EntryStateDownloading TorrentState = "downloading"
 	EntryStatePausedDL    TorrentState = "pausedDL"
@@ -72,6 +76,7 @@ type Entry struct {
 	// Metadata
 	Category    string   `msgpack:"category,omitempty" json:"category,omitempty"`         // Category (e.g., sonarr, radarr)
 	Tags        []string `msgpack:"tags,omitempty" json:"tags,omitempty"`                 // User-defined tags
+	Folders     []string `msgpack:"folders,omitempty" json:"folders,omitempty"`           // Custom folders tag rules routed the entry to
 	MountPath   string   `msgpack:"mount_path" json:"mount_path"`                         // Mount path for this torrent
 	SavePath    string   `msgpack:"save_path,omitempty" json:"save_path,omitempty"`       // Download/symlink folder
 	ContentPath string   `msgpack:"content_path,omitempty" json:"content_path,omitempty"` // Final content path
@@ -88,6 +93,12 @@ type Entry struct {
 	DownloadUncached bool                  `msgpack:"download_uncached,omitempty" json:"download_uncached,omitempty"` // Force uncached download
 	CallbackURL      string                `msgpack:"callback_url,omitempty" json:"callback_url,omitempty"`           // Callback URL for completion
 	SkipMultiSeason  bool                  `msgpack:"skip_multi_season,omitempty" json:"skip_multi_season,omitempty"` // Skip multi-season detection
+	FileSelection    *config.FileSelection `msgpack:"file_selection,omitempty" json:"file_selection,omitempty"`       // Which files to fetch and show
+	NameOverride     string                `msgpack:"name_override,omitempty" json:"name_override,omitempty"`         // Name set through qBit rename
+	FileRenames      map[string]string     `msgpack:"file_renames,omitempty" json:"file_renames,omitempty"`           // Provider file name -> name set through qBit renameFile
+	Priority         int                   `msgpack:"priority,omitempty" json:"priority,omitempty"`                   // Queue priority, SABnzbd scale (-1 low to 2 force)
+	PPParameters     map[string]string     `msgpack:"pp_parameters,omitempty" json:"pp_parameters,omitempty"`         // Post-processing parameters set through NZBGet append
+	Profile          string                `msgpack:"profile,omitempty" json:"profile,omitempty"`                     // Category profile set by a tag rule
 
 	// Error tracking
 	LastError     string     `msgpack:"last_error,omitempty" json:"last_error,omitempty"`           // Last error message
@@ -103,6 +114,12 @@ func (e *Entry) IsNZB() bool {
 	return e.Protocol == config.ProtocolNZB
 }
 
+// OnUsenet reports whether an NZB entry streams over the NNTP providers. NZBs
+// placed on a debrid usenet service are served over HTTP like torrents.
+func (e *Entry) OnUsenet() bool {
+	return e.IsNZB() && (e.ActiveProvider == "" || e.ActiveProvider == "usenet")
+}
+
 func (e *Entry) Validate() error {
 	activeProvider := e.GetActiveProvider()
 	if activeProvider == nil {
@@ -129,14 +146,14 @@ func (e *Entry) Sanitize() {
 	}
 }
 
-// CanBeFixed checks if the entry can be repaired
-// TODO: Add more checks later. This will be done when we add other NZB source like TB(nzb)
+// CanBeFixed checks if the entry can be repaired. NZBs are re-submitted to
+// another usenet provider, NNTP or a debrid usenet service.
 func (e *Entry) CanBeFixed() bool {
-	return e.IsTorrent()
+	return e.IsTorrent() || e.IsNZB()
 }
 
 func (e *Entry) CanBeMoved() bool {
-	return e.IsTorrent()
+	return e.IsTorrent() || e.IsNZB()
 }
 
 // EntryItem These are torrents by names.
@@ -156,7 +173,7 @@ func (e *EntryItem) GetFile(filename string) (*File, error) {
 	if !exists {
 		return nil, fmt.Errorf("failed to get entry item, file does not exist")
 	}
-	if f.Deleted {
+	if !f.Active() {
 		return nil, fmt.Errorf("failed to get entry item, file is deleted")
 	}
 	return f, nil
@@ -165,7 +182,7 @@ func (e *EntryItem) GetFile(filename string) (*File, error) {
 func (e *EntryItem) GetSize() int64 {
 	size := int64(0)
 	for _, f := range e.Files {
-		if !f.Deleted {
+		if f.Active() {
 			size += f.Size
 		}
 	}
@@ -182,7 +199,7 @@ func (e *EntryItem) GetFirstFile() (*File, error) {
 func (e *EntryItem) GetActiveFiles() []*File {
 	files := make([]*File, 0, len(e.Files))
 	for _, f := range e.Files {
-		if !f.Deleted {
+		if f.Active() {
 			files = append(files, f)
 		}
 	}
@@ -190,13 +207,48 @@ func (e *EntryItem) GetActiveFiles() []*File {
 }
 
 type File struct {
-	Name      string    `msgpack:"name" json:"name"`
-	Path      string    `msgpack:"path,omitempty" json:"path,omitempty"`
-	AddedOn   time.Time `msgpack:"added_on" json:"added_on"`
-	Size      int64     `msgpack:"size" json:"size"`
-	ByteRange *[2]int64 `msgpack:"byte_range,omitempty" json:"byte_range,omitempty"`
-	Deleted   bool      `msgpack:"deleted" json:"deleted"`
-	InfoHash  string    `msgpack:"infohash,omitempty" json:"infohash,omitempty"` // Parent infohash(might be an nzb or torrent)
+	Name      string     `msgpack:"name" json:"name"`
+	Path      string     `msgpack:"path,omitempty" json:"path,omitempty"`
+	AddedOn   time.Time  `msgpack:"added_on" json:"added_on"`
+	Size      int64      `msgpack:"size" json:"size"`
+	ByteRange *[2]int64  `msgpack:"byte_range,omitempty" json:"byte_range,omitempty"`
+	Deleted   bool       `msgpack:"deleted" json:"deleted"`
+	InfoHash  string     `msgpack:"infohash,omitempty" json:"infohash,omitempty"` // Parent infohash(might be an nzb or torrent)
+	Media     *MediaInfo `msgpack:"media,omitempty" json:"media,omitempty"`
+	// Unselected files are left out by the entry's file selection. Unlike
+	// Deleted, selecting the file again brings it back.
+	Unselected bool `msgpack:"unselected,omitempty" json:"unselected,omitempty"`
+}
+
+// Active reports whether the file is shown: neither deleted nor left out by
+// the file selection.
+func (f *File) Active() bool {
+	return !f.Deleted && !f.Unselected
+}
+
+// MediaInfo is the container-level metadata read from a file's headers.
+// Status is "ok", "truncated" or "unplayable"; Reason explains the latter two.
+type MediaInfo struct {
+	Container         string    `msgpack:"container" json:"container"`
+	DurationSeconds   float64   `msgpack:"duration_seconds,omitempty" json:"duration_seconds,omitempty"`
+	VideoCodec        string    `msgpack:"video_codec,omitempty" json:"video_codec,omitempty"`
+	Width             int       `msgpack:"width,omitempty" json:"width,omitempty"`
+	Height            int       `msgpack:"height,omitempty" json:"height,omitempty"`
+	AudioCodecs       []string  `msgpack:"audio_codecs,omitempty" json:"audio_codecs,omitempty"`
+	AudioLanguages    []string  `msgpack:"audio_languages,omitempty" json:"audio_languages,omitempty"`
+	SubtitleLanguages []string  `msgpack:"subtitle_languages,omitempty" json:"subtitle_languages,omitempty"`
+	Status            string    `msgpack:"status" json:"status"`
+	Reason            string    `msgpack:"reason,omitempty" json:"reason,omitempty"`
+	ProbedAt          time.Time `msgpack:"probed_at" json:"probed_at"`
+}
+
+// Playable reports whether the last probe found an intact container.
+func (m *MediaInfo) Playable() bool {
+	return m == nil || m.Status == "" || m.Status == "ok"
+}
+
+func mediaProbedAfter(a, b *MediaInfo) bool {
+	return a != nil && (b == nil || a.ProbedAt.After(b.ProbedAt))
 }
 
 // ProviderFile represents debrid-specific file information
@@ -301,7 +353,7 @@ func (e *Entry) AddTorrentProvider(debridTorrent *debridTypes.Torrent) *Provider
 	}
 
 	for _, f := range debridTorrent.GetFiles() {
-		providerEntry.Files[f.Name] = &ProviderFile{
+		providerEntry.Files[e.FileName(f.Name)] = &ProviderFile{
 			Id:   f.Id,
 			Link: f.Link,
 			Path: f.Path,
@@ -429,7 +481,7 @@ func (e *Entry) GetFile(filename string) (*File, error) {
 	if !exists {
 		return nil, fmt.Errorf("failed to get entry file, file not found")
 	}
-	if f.Deleted {
+	if !f.Active() {
 		return nil, fmt.Errorf("file deleted")
 	}
 	return f, nil
@@ -455,7 +507,7 @@ func (e *Entry) RunChecks() (bool, error) {
 
 	// Then check all the files exists and files not deleted have links
 	for _, f := range e.Files {
-		if f.Deleted {
+		if !f.Active() {
 			continue
 		}
 		pf, exists := activeProvider.Files[f.Name]
@@ -469,10 +521,102 @@ func (e *Entry) RunChecks() (bool, error) {
 	return false, nil
 }
 
+// ApplyFileSelection marks the files the entry's selection leaves out as
+// unselected, so they drop out of the mount, and brings back the ones it keeps.
+// A selection that would hide every file is ignored. It returns true if any
+// file is left out.
+func (e *Entry) ApplyFileSelection() bool {
+	if e.FileSelection.IsZero() || len(e.Files) == 0 {
+		return false
+	}
+	names := make([]string, 0, len(e.Files))
+	files := make([]config.SelectionFile, 0, len(e.Files))
+	for name, f := range e.Files {
+		if f.Deleted {
+			continue
+		}
+		names = append(names, name)
+		files = append(files, config.SelectionFile{Name: name, Path: cmp.Or(f.Path, f.Name), Size: f.Size})
+	}
+	keep := e.FileSelection.Select(files)
+	if !slices.Contains(keep, true) {
+		return false
+	}
+	hidden := false
+	for i, name := range names {
+		e.Files[name].Unselected = !keep[i]
+		hidden = hidden || !keep[i]
+	}
+	return hidden
+}
+
+// FileName returns the name a provider file is shown under, which differs
+// from the provider's name once the file was renamed through qBit renameFile.
+func (e *Entry) FileName(providerName string) string {
+	if name, ok := e.FileRenames[providerName]; ok {
+		return name
+	}
+	return providerName
+}
+
+// Rename changes the entry name, and with it the virtual folder unless folders
+// are named after the original filename or the hash. The name sticks across
+// provider refreshes.
+func (e *Entry) Rename(name string) {
+	e.Name = name
+	e.NameOverride = name
+}
+
+// RenameFile renames a file in the entry and in every placement. The rename is
+// recorded against the provider's file name, so refreshes and repairs keep it.
+func (e *Entry) RenameFile(oldName, newName string) error {
+	file, ok := e.Files[oldName]
+	if !ok {
+		return fmt.Errorf("file %s not found", oldName)
+	}
+	if _, exists := e.Files[newName]; exists {
+		return fmt.Errorf("file %s already exists", newName)
+	}
+	delete(e.Files, oldName)
+	file.Name = newName
+	e.Files[newName] = file
+	for _, pe := range e.Providers {
+		if pf, ok := pe.Files[oldName]; ok {
+			delete(pe.Files, oldName)
+			pe.Files[newName] = pf
+		}
+	}
+
+	providerName := oldName
+	for original, name := range e.FileRenames {
+		if name == oldName {
+			providerName = original
+			break
+		}
+	}
+	if providerName == newName {
+		delete(e.FileRenames, providerName)
+	} else {
+		if e.FileRenames == nil {
+			e.FileRenames = make(map[string]string)
+		}
+		e.FileRenames[providerName] = newName
+	}
+
+	if sel := e.FileSelection; sel != nil {
+		for _, names := range [][]string{sel.Keep, sel.Skip} {
+			if i := slices.Index(names, oldName); i >= 0 {
+				names[i] = newName
+			}
+		}
+	}
+	return nil
+}
+
 func (e *Entry) GetActiveFiles() []*File {
 	files := make([]*File, 0, len(e.Files))
 	for _, f := range e.Files {
-		if !f.Deleted {
+		if f.Active() {
 			files = append(files, f)
 		}
 	}
@@ -11,7 +11,8 @@ import (
 // owning a "usenet" singleton — the buffer package stays generic. The pool is
 // created once with the configured usenet RAM budget and shared across every
 // SegmentCache. Disk is bounded per-stream by the sliding-window sweep
-// (see SegmentCache.sweepWindow), so the pool runs no disk backstop.
+// (see SegmentCache.sweepWindow); the pool only gets a disk backstop when the
+// process-wide buffer governor has a disk budget.
 var (
 	bufPoolOnce sync.Once
 	bufPool     *buffer.Pool
@@ -22,6 +23,7 @@ func usenetBufferPool() *buffer.Pool {
 		bufPool = buffer.NewPool(buffer.PoolConfig{
 			Name:         "usenet",
 			MemoryBudget: config.Get().Usenet.BufferMemoryBytes(),
+			Governor:     buffer.DefaultGovernor(),
 		})
 	})
 	return bufPool
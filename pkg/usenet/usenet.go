@@ -948,6 +948,27 @@ func (u *Usenet) Stats() map[string]any {
 	return stats
 }
 
+// Ping checks that at least one NNTP provider can be reached and logs in
+func (u *Usenet) Ping(ctx context.Context) error {
+	return u.nntp.Ping(ctx)
+}
+
+// SetTrafficStore restores the provider traffic counters from store and
+// persists them there on SaveTraffic.
+func (u *Usenet) SetTrafficStore(store nntp.TrafficStore) {
+	u.nntp.SetTrafficStore(store)
+}
+
+// SaveTraffic persists the provider traffic counters.
+func (u *Usenet) SaveTraffic() {
+	u.nntp.SaveTraffic()
+}
+
+// Traffic returns the bytes and articles each NNTP provider served.
+func (u *Usenet) Traffic() []nntp.ProviderTraffic {
+	return u.nntp.Traffic()
+}
+
 // GetNZB returns NZB metadata by ID
 func (u *Usenet) GetNZB(id string) (*storage.NZB, error) {
 	return u.nzbStorage.GetNZB(id)